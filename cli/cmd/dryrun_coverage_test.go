@@ -23,6 +23,7 @@ var dryRunExpectation = map[string]bool{
 	"kb create": true, "kb update": true, "kb delete": true, "kb pin": true, "kb unpin": true,
 	"kb config set": true, // binds models to a KB (state change)
 	"model create": true, "model update": true, "model delete": true,
+	"eval run": true, "eval dataset upload": true, "eval dataset delete": true,
 	"doc create": true, "doc upload": true, "doc fetch": true, "doc delete": true,
 	"doc reparse": true, // re-triggers server-side parsing (a state change)
 	"doc update":  true, // edits title/description server-side
@@ -46,6 +47,7 @@ var dryRunExpectation = map[string]bool{
 	"session list": false, "session view": false,
 	"agent list": false, "agent view": false, "agent status": false, "agent check": false,
 	"model list": false, "model view": false,
+	"eval list": false, "eval view": false, "eval results": false, "eval compare": false, "eval dataset list": false,
 	"search chunks": false, "search docs": false, "search kb": false, "search sessions": false,
 	"auth list": false, "auth status": false, "auth token": false,
 	"profile list": false,
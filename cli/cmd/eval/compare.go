@@ -0,0 +1,181 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	"github.com/Tencent/WeKnora/cli/internal/text"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+var evalCompareFields = []string{
+	"base_task_id", "target_task_id", "dataset_id", "gate", "aggregate",
+	"questions", "unmatched_questions", "regressed_questions", "passed",
+}
+
+// evalMetricValues mirrors the server's metric names; a typo is rejected
+// locally instead of round-tripping for a 400.
+var evalMetricValues = []string{
+	"precision", "recall", "ndcg3", "ndcg10", "mrr", "map",
+	"bleu1", "bleu2", "bleu4", "rouge1", "rouge2", "rougel",
+	"faithfulness", "answer_relevance", "context_precision", "context_recall",
+}
+
+// CompareOptions captures `eval compare` flag state.
+type CompareOptions struct {
+	BaseTaskID   string
+	TargetTaskID string
+	// Metrics are the gated metrics; empty means report-only (always passes).
+	Metrics []string
+	MaxDrop float64
+	// Show caps the regressed questions listed in text mode.
+	Show int
+}
+
+// CompareService is the narrow SDK surface this command depends on.
+type CompareService interface {
+	CompareEvaluations(ctx context.Context, req *sdk.EvaluationCompareRequest) (*sdk.EvaluationComparison, error)
+}
+
+// NewCmdCompare builds `weknora eval compare <base-task-id> <target-task-id>`.
+func NewCmdCompare(f *cmdutil.Factory) *cobra.Command {
+	opts := &CompareOptions{}
+	cmd := &cobra.Command{
+		Use:   "compare <base-task-id> <target-task-id>",
+		Short: "Diff two evaluation runs and gate on regressions",
+		Long: `Compare a target run against a baseline run of the same dataset, per question
+and in aggregate. With --metric the comparison becomes a regression gate: the
+command exits 1 (operation.failed) when any gated aggregate metric drops by
+more than --max-drop, so it can block a KB config change in CI.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			opts.BaseTaskID, opts.TargetTaskID = args[0], args[1]
+			if err := validateCompareOpts(opts); err != nil {
+				return err
+			}
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runCompare(c.Context(), opts, fopts, cli)
+		},
+	}
+	cmd.Flags().StringSliceVar(&opts.Metrics, "metric", nil, "Gate on this metric (repeatable): "+strings.Join(evalMetricValues, ", "))
+	cmd.Flags().Float64Var(&opts.MaxDrop, "max-drop", 0, "Largest tolerated absolute drop of a gated metric, e.g. 0.02")
+	cmd.Flags().IntVar(&opts.Show, "show", 10, "Regressed questions to list in text output")
+	cmdutil.AddFormatFlag(cmd, evalCompareFields...)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:       "decide whether a new evaluation run regressed versus a baseline before rolling out a KB config change",
+		RequiredFlags: []string{"<base-task-id> <target-task-id> (positional)"},
+		Examples: []string{
+			"weknora eval compare base_task new_task",
+			"weknora eval compare base_task new_task --metric mrr --metric ndcg10 --max-drop 0.02",
+		},
+		Output: "envelope.data is {aggregate[], questions[], regressed_questions, passed}; exit 1 (operation.failed) when passed=false",
+	})
+	return cmd
+}
+
+func validateCompareOpts(opts *CompareOptions) error {
+	for i, m := range opts.Metrics {
+		canon, err := cmdutil.ValidateEnum("metric", m, evalMetricValues)
+		if err != nil {
+			return err
+		}
+		opts.Metrics[i] = canon
+	}
+	if opts.MaxDrop < 0 {
+		return cmdutil.NewError(cmdutil.CodeInputInvalidArgument, "--max-drop must not be negative")
+	}
+	return nil
+}
+
+func runCompare(ctx context.Context, opts *CompareOptions, fopts *cmdutil.FormatOptions, svc CompareService) error {
+	if err := validateCompareOpts(opts); err != nil {
+		return err
+	}
+	cmp, err := svc.CompareEvaluations(ctx, &sdk.EvaluationCompareRequest{
+		BaseTaskID:   opts.BaseTaskID,
+		TargetTaskID: opts.TargetTaskID,
+		Metrics:      opts.Metrics,
+		MaxDrop:      opts.MaxDrop,
+	})
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "compare evaluations %q and %q", opts.BaseTaskID, opts.TargetTaskID)
+	}
+	if fopts.WantsJSON() {
+		if err := fopts.Emit(iostreams.IO.Out, cmp, nil); err != nil {
+			return err
+		}
+	} else if err := renderComparison(cmp, opts.Show); err != nil {
+		return err
+	}
+	if !cmp.Passed {
+		return cmdutil.NewError(cmdutil.CodeOperationFailed,
+			fmt.Sprintf("evaluation %s regressed versus %s", cmp.TargetTaskID, cmp.BaseTaskID)).WithSilent()
+	}
+	return nil
+}
+
+func renderComparison(cmp *sdk.EvaluationComparison, show int) error {
+	w := iostreams.IO.Out
+	var gated []string
+	if cmp.Gate != nil {
+		gated = cmp.Gate.Metrics
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tBASE\tTARGET\tDELTA\tGATE")
+	for _, d := range cmp.Aggregate {
+		gate := ""
+		switch {
+		case d.Regressed:
+			gate = "✗ regressed"
+		case slices.Contains(gated, d.Metric):
+			gate = "✓"
+		}
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%+.4f\t%s\n", d.Metric, d.Base, d.Target, d.Delta, gate)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if cmp.RegressedQuestions > 0 && show > 0 {
+		fmt.Fprintf(w, "\nRegressed questions (%d):\n", cmp.RegressedQuestions)
+		listed := 0
+		for _, q := range cmp.Questions {
+			if !q.Regressed {
+				continue
+			}
+			if listed == show {
+				fmt.Fprintf(w, "  … %d more (use --format json)\n", cmp.RegressedQuestions-listed)
+				break
+			}
+			fmt.Fprintf(w, "  [%d] %s\n", q.QID, text.Truncate(80, q.Question))
+			listed++
+		}
+	}
+	if cmp.UnmatchedQuestions > 0 {
+		fmt.Fprintf(w, "\n%d question(s) present in only one run were skipped\n", cmp.UnmatchedQuestions)
+	}
+	switch {
+	case len(gated) == 0:
+		fmt.Fprintln(w, "\nNo gate (pass --metric to gate on a metric)")
+	case cmp.Passed:
+		fmt.Fprintln(w, "\nPASS")
+	default:
+		fmt.Fprintln(w, "\nFAIL")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package evalcmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeCompareSvc struct {
+	got *sdk.EvaluationCompareRequest
+	cmp *sdk.EvaluationComparison
+}
+
+func (f *fakeCompareSvc) CompareEvaluations(
+	_ context.Context, req *sdk.EvaluationCompareRequest,
+) (*sdk.EvaluationComparison, error) {
+	f.got = req
+	return f.cmp, nil
+}
+
+func regressedComparison() *sdk.EvaluationComparison {
+	return &sdk.EvaluationComparison{
+		BaseTaskID: "base", TargetTaskID: "target",
+		Gate: &sdk.EvaluationGate{Metrics: []string{"mrr"}, MaxDrop: 0.01},
+		Aggregate: []sdk.EvaluationMetricDelta{
+			{Metric: "mrr", Base: 0.8, Target: 0.7, Delta: -0.1, Regressed: true},
+		},
+		Questions: []sdk.EvaluationQuestionComparison{
+			{QID: 4, Question: "slipped question", Regressed: true},
+		},
+		RegressedQuestions: 1,
+	}
+}
+
+func TestEvalCompare_RegressionExitsNonZero(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeCompareSvc{cmp: regressedComparison()}
+	opts := &CompareOptions{BaseTaskID: "base", TargetTaskID: "target", Metrics: []string{"MRR"}, MaxDrop: 0.01, Show: 10}
+	err := runCompare(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc)
+	if typed := cmdutil.AsError(err); typed == nil || typed.Code != cmdutil.CodeOperationFailed {
+		t.Fatalf("expected operation.failed, got %v", err)
+	}
+	if svc.got.Metrics[0] != "mrr" || svc.got.MaxDrop != 0.01 {
+		t.Errorf("gate not forwarded: %+v", svc.got)
+	}
+	for _, want := range []string{"mrr", "-0.1000", "regressed", "slipped question", "FAIL"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestEvalCompare_PassReturnsNil(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeCompareSvc{cmp: &sdk.EvaluationComparison{Passed: true}}
+	opts := &CompareOptions{BaseTaskID: "base", TargetTaskID: "target"}
+	if err := runCompare(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc); err != nil {
+		t.Fatalf("runCompare: %v", err)
+	}
+	if !strings.Contains(out.String(), "No gate") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestEvalCompare_RejectsUnknownMetric(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	opts := &CompareOptions{BaseTaskID: "base", TargetTaskID: "target", Metrics: []string{"f1"}}
+	err := runCompare(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, &fakeCompareSvc{})
+	if typed := cmdutil.AsError(err); typed == nil || typed.Code != cmdutil.CodeInputInvalidArgument {
+		t.Fatalf("expected input.invalid_argument, got %v", err)
+	}
+}
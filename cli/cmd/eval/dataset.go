@@ -0,0 +1,21 @@
+package evalcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+)
+
+// NewCmdDataset builds the `weknora eval dataset` parent.
+func NewCmdDataset(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dataset",
+		Short: "Manage evaluation datasets (upload / list / delete)",
+		Long: `Upload your own QA / qrels datasets so evaluation runs measure the questions
+your users actually ask. Datasets are private to the current tenant.`,
+	}
+	cmd.AddCommand(NewCmdDatasetUpload(f))
+	cmd.AddCommand(NewCmdDatasetList(f))
+	cmd.AddCommand(NewCmdDatasetDelete(f))
+	return cmd
+}
@@ -0,0 +1,96 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+// DatasetDeleteService is the narrow SDK surface this command depends on.
+type DatasetDeleteService interface {
+	DeleteEvaluationDataset(ctx context.Context, id string) error
+}
+
+type datasetDeleteOptions struct {
+	Yes    bool
+	DryRun bool
+}
+
+// NewCmdDatasetDelete builds `weknora eval dataset delete <dataset-id>`.
+func NewCmdDatasetDelete(f *cmdutil.Factory) *cobra.Command {
+	opts := &datasetDeleteOptions{}
+	cmd := &cobra.Command{
+		Use:   "delete <dataset-id>",
+		Short: "Delete an uploaded evaluation dataset",
+		Long: `Delete an uploaded dataset and its questions. Past runs on the dataset keep
+their stored results. High-risk write: without -y/--yes in a non-TTY / JSON
+context it exits 10 (input.confirmation_required) without deleting.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			opts.Yes, _ = c.Flags().GetBool("yes")
+			id := args[0]
+			if handled, err := cmdutil.HandleDryRun(c, opts.DryRun, cmdutil.DryRunPlan{
+				Action: "eval.dataset.delete",
+				Args:   map[string]any{"dataset": id},
+			}); handled {
+				return err
+			}
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			if err := cmdutil.ConfirmDestructive(f.Prompter(), opts.Yes, fopts.WantsJSON(),
+				"delete", "dataset", id, "eval.dataset.delete",
+				[]string{"weknora", "eval", "dataset", "delete", id, "-y"}); err != nil {
+				return err
+			}
+			return runDatasetDelete(c.Context(), fopts, cli, id)
+		},
+	}
+	cmdutil.AddFormatFlag(cmd, "id", "deleted")
+	cmdutil.AddDryRunFlag(cmd, &opts.DryRun)
+	cmdutil.SetRisk(cmd, "eval.dataset.delete")
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:       "delete an uploaded evaluation dataset by id",
+		RequiredFlags: []string{"<dataset-id> (positional)"},
+		Examples:      []string{"weknora eval dataset delete ds_123 -y"},
+		Output:        "envelope.data is {id, deleted:true}",
+		Warnings: []string{
+			"Requires explicit user approval (exit 10 / input.confirmation_required); never auto-add -y.",
+		},
+	})
+	return cmd
+}
+
+func runDatasetDelete(ctx context.Context, fopts *cmdutil.FormatOptions, svc DatasetDeleteService, id string) error {
+	if err := svc.DeleteEvaluationDataset(ctx, id); err != nil {
+		return cmdutil.WrapHTTP(err, "delete dataset %q", id)
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, map[string]any{"id": id, "deleted": true}, nil)
+	}
+	fmt.Fprintf(iostreams.IO.Out, "✓ Deleted dataset %s\n", id)
+	return nil
+}
+
+// compile-time check: the production SDK client implements every service.
+var (
+	_ RunService           = (*sdk.Client)(nil)
+	_ ViewService          = (*sdk.Client)(nil)
+	_ ListService          = (*sdk.Client)(nil)
+	_ ResultsService       = (*sdk.Client)(nil)
+	_ CompareService       = (*sdk.Client)(nil)
+	_ DatasetUploadService = (*sdk.Client)(nil)
+	_ DatasetListService   = (*sdk.Client)(nil)
+	_ DatasetDeleteService = (*sdk.Client)(nil)
+)
@@ -0,0 +1,47 @@
+package evalcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+)
+
+type fakeDatasetDeleteSvc struct {
+	gotID string
+	err   error
+}
+
+func (f *fakeDatasetDeleteSvc) DeleteEvaluationDataset(_ context.Context, id string) error {
+	f.gotID = id
+	return f.err
+}
+
+func TestDatasetDelete_JSON(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeDatasetDeleteSvc{}
+	if err := runDatasetDelete(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatJSON}, svc, "ds1"); err != nil {
+		t.Fatalf("runDatasetDelete: %v", err)
+	}
+	var env struct {
+		Data struct {
+			ID      string `json:"id"`
+			Deleted bool   `json:"deleted"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &env); err != nil || env.Data.ID != "ds1" || !env.Data.Deleted {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestDatasetDelete_NotFound(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	svc := &fakeDatasetDeleteSvc{err: errors.New("HTTP error 404: not found")}
+	err := runDatasetDelete(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc, "missing")
+	if !cmdutil.IsNotFound(err) {
+		t.Errorf("expected resource.not_found, got %v", err)
+	}
+}
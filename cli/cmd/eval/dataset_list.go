@@ -0,0 +1,72 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	"github.com/Tencent/WeKnora/cli/internal/output"
+	"github.com/Tencent/WeKnora/cli/internal/text"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+// DatasetListService is the narrow SDK surface this command depends on.
+type DatasetListService interface {
+	ListEvaluationDatasets(ctx context.Context) ([]*sdk.EvaluationDataset, error)
+}
+
+// NewCmdDatasetList builds `weknora eval dataset list`.
+func NewCmdDatasetList(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List uploaded evaluation datasets",
+		Long:  `List the evaluation datasets uploaded by the current tenant. The bundled sample dataset is not listed; omit --dataset on 'eval run' to use it.`,
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runDatasetList(c.Context(), fopts, cli)
+		},
+	}
+	cmdutil.AddFormatFlag(cmd, evalDatasetFields...)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:  "discover dataset ids for `eval run --dataset`",
+		Examples: []string{"weknora eval dataset list --format json"},
+		Output:   "envelope.data is an array of EvaluationDataset objects (id, name, format, qa_count, passage_count), newest first",
+	})
+	return cmd
+}
+
+func runDatasetList(ctx context.Context, fopts *cmdutil.FormatOptions, svc DatasetListService) error {
+	items, err := svc.ListEvaluationDatasets(ctx)
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "list datasets")
+	}
+	if items == nil {
+		items = []*sdk.EvaluationDataset{}
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, items, &output.Meta{Count: output.IntPtr(len(items))})
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(iostreams.IO.Out, "(no datasets)")
+		return nil
+	}
+	tw := tabwriter.NewWriter(iostreams.IO.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tFORMAT\tQUESTIONS\tPASSAGES")
+	for _, d := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", d.ID, text.Truncate(40, d.Name), d.Format, d.QACount, d.PassageCount)
+	}
+	return tw.Flush()
+}
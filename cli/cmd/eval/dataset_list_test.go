@@ -0,0 +1,44 @@
+package evalcmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeDatasetListSvc struct {
+	items []*sdk.EvaluationDataset
+}
+
+func (f *fakeDatasetListSvc) ListEvaluationDatasets(_ context.Context) ([]*sdk.EvaluationDataset, error) {
+	return f.items, nil
+}
+
+func TestDatasetList_Text(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeDatasetListSvc{items: []*sdk.EvaluationDataset{
+		{ID: "ds1", Name: "faq", Format: "jsonl", QACount: 12, PassageCount: 30},
+	}}
+	if err := runDatasetList(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc); err != nil {
+		t.Fatalf("runDatasetList: %v", err)
+	}
+	for _, want := range []string{"ds1", "faq", "jsonl", "12", "30"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDatasetList_Empty(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	if err := runDatasetList(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, &fakeDatasetListSvc{}); err != nil {
+		t.Fatalf("runDatasetList: %v", err)
+	}
+	if !strings.Contains(out.String(), "no datasets") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
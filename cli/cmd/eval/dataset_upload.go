@@ -0,0 +1,154 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+var evalDatasetFields = []string{
+	"id", "name", "description", "format", "qa_count", "passage_count", "created_at",
+}
+
+// DatasetUploadOptions captures `eval dataset upload` flag state.
+type DatasetUploadOptions struct {
+	Name        string
+	Description string
+	JSONL       string
+	// Parquet parts, laid out like the bundled sample dataset.
+	Queries string
+	Corpus  string
+	Qrels   string
+	Answers string
+	Qas     string
+	DryRun  bool
+}
+
+// DatasetUploadService is the narrow SDK surface this command depends on.
+type DatasetUploadService interface {
+	UploadEvaluationDataset(ctx context.Context, req *sdk.EvaluationDatasetUploadRequest) (*sdk.EvaluationDataset, error)
+}
+
+// NewCmdDatasetUpload builds `weknora eval dataset upload <name>`.
+func NewCmdDatasetUpload(f *cmdutil.Factory) *cobra.Command {
+	opts := &DatasetUploadOptions{}
+	cmd := &cobra.Command{
+		Use:   "upload <name>",
+		Short: "Upload a JSONL or parquet evaluation dataset",
+		Long: `Upload an evaluation dataset in one of two formats:
+
+  JSONL:    --jsonl qa.jsonl
+            One question per line:
+            {"question": "...", "answer": "...", "passages": ["relevant text", ...]}
+            Passages may also be objects {"pid": 7, "text": "..."}.
+
+  Parquet:  --queries q.parquet --corpus c.parquet --qrels r.parquet
+            [--answers a.parquet --qas qa.parquet]
+            Same layout as the bundled sample dataset.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			opts.Name = args[0]
+			req, err := opts.request()
+			if err != nil {
+				return err
+			}
+			if handled, err := cmdutil.HandleDryRun(c, opts.DryRun, cmdutil.DryRunPlan{
+				Action: "eval.dataset.upload",
+				Args: map[string]any{
+					"name": req.Name, "jsonl": req.JSONLPath, "parquet": req.ParquetPaths,
+				},
+			}); handled {
+				return err
+			}
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runDatasetUpload(c.Context(), fopts, cli, req)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Description, "description", "", "Dataset description")
+	cmd.Flags().StringVar(&opts.JSONL, "jsonl", "", "Path to a JSONL dataset")
+	cmd.Flags().StringVar(&opts.Queries, "queries", "", "Path to queries.parquet")
+	cmd.Flags().StringVar(&opts.Corpus, "corpus", "", "Path to corpus.parquet")
+	cmd.Flags().StringVar(&opts.Qrels, "qrels", "", "Path to qrels.parquet")
+	cmd.Flags().StringVar(&opts.Answers, "answers", "", "Path to answers.parquet (optional)")
+	cmd.Flags().StringVar(&opts.Qas, "qas", "", "Path to qas.parquet (optional)")
+	cmdutil.AddFormatFlag(cmd, evalDatasetFields...)
+	cmdutil.AddDryRunFlag(cmd, &opts.DryRun)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:       "upload a custom QA dataset for `eval run --dataset`",
+		RequiredFlags: []string{"<name> (positional)", "--jsonl, or --queries + --corpus + --qrels"},
+		Examples: []string{
+			"weknora eval dataset upload support-faq --jsonl faq.jsonl",
+			"weknora eval dataset upload msmarco --queries q.parquet --corpus c.parquet --qrels r.parquet",
+		},
+		Output: "envelope.data is the EvaluationDataset (id, name, format, qa_count, passage_count); pass id to `eval run --dataset`",
+	})
+	return cmd
+}
+
+// request validates the flag combination and builds the SDK request.
+func (o *DatasetUploadOptions) request() (*sdk.EvaluationDatasetUploadRequest, error) {
+	req := &sdk.EvaluationDatasetUploadRequest{
+		Name:        strings.TrimSpace(o.Name),
+		Description: o.Description,
+	}
+	if req.Name == "" {
+		return nil, cmdutil.NewError(cmdutil.CodeInputInvalidArgument, "dataset name must not be empty")
+	}
+	parquet := map[string]string{}
+	for field, path := range map[string]string{
+		"queries": o.Queries, "corpus": o.Corpus, "qrels": o.Qrels, "answers": o.Answers, "qas": o.Qas,
+	} {
+		if path != "" {
+			parquet[field] = path
+		}
+	}
+	switch {
+	case o.JSONL != "" && len(parquet) > 0:
+		return nil, cmdutil.NewError(cmdutil.CodeInputInvalidArgument,
+			"--jsonl cannot be combined with parquet flags")
+	case o.JSONL != "":
+		req.JSONLPath = o.JSONL
+	case len(parquet) > 0:
+		for _, required := range []string{"queries", "corpus", "qrels"} {
+			if parquet[required] == "" {
+				return nil, cmdutil.NewError(cmdutil.CodeInputMissingFlag,
+					fmt.Sprintf("parquet upload requires --%s", required)).
+					WithHint("parquet datasets need --queries, --corpus and --qrels")
+			}
+		}
+		req.ParquetPaths = parquet
+	default:
+		return nil, cmdutil.NewError(cmdutil.CodeInputMissingFlag,
+			"one of --jsonl or --queries/--corpus/--qrels is required")
+	}
+	return req, nil
+}
+
+func runDatasetUpload(
+	ctx context.Context, fopts *cmdutil.FormatOptions, svc DatasetUploadService, req *sdk.EvaluationDatasetUploadRequest,
+) error {
+	ds, err := svc.UploadEvaluationDataset(ctx, req)
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "upload dataset %q", req.Name)
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, ds, nil)
+	}
+	fmt.Fprintf(iostreams.IO.Out, "✓ Uploaded dataset %s (%s): %d questions, %d passages\n",
+		ds.ID, ds.Name, ds.QACount, ds.PassageCount)
+	return nil
+}
@@ -0,0 +1,69 @@
+package evalcmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeUploadSvc struct {
+	got *sdk.EvaluationDatasetUploadRequest
+}
+
+func (f *fakeUploadSvc) UploadEvaluationDataset(
+	_ context.Context, req *sdk.EvaluationDatasetUploadRequest,
+) (*sdk.EvaluationDataset, error) {
+	f.got = req
+	return &sdk.EvaluationDataset{ID: "ds1", Name: req.Name, QACount: 3, PassageCount: 5}, nil
+}
+
+func TestDatasetUpload_RequestValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		opts DatasetUploadOptions
+		code cmdutil.ErrorCode
+	}{
+		{"no files", DatasetUploadOptions{Name: "x"}, cmdutil.CodeInputMissingFlag},
+		{"mixed", DatasetUploadOptions{Name: "x", JSONL: "a.jsonl", Queries: "q.parquet"}, cmdutil.CodeInputInvalidArgument},
+		{"partial parquet", DatasetUploadOptions{Name: "x", Queries: "q.parquet"}, cmdutil.CodeInputMissingFlag},
+		{"blank name", DatasetUploadOptions{Name: " ", JSONL: "a.jsonl"}, cmdutil.CodeInputInvalidArgument},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.opts.request()
+			if typed := cmdutil.AsError(err); typed == nil || typed.Code != tc.code {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestDatasetUpload_Parquet(t *testing.T) {
+	opts := DatasetUploadOptions{Name: "m", Queries: "q", Corpus: "c", Qrels: "r", Answers: "a"}
+	req, err := opts.request()
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if len(req.ParquetPaths) != 4 || req.ParquetPaths["answers"] != "a" || req.JSONLPath != "" {
+		t.Errorf("unexpected parquet request: %+v", req)
+	}
+}
+
+func TestDatasetUpload_Text(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeUploadSvc{}
+	req := &sdk.EvaluationDatasetUploadRequest{Name: "faq", JSONLPath: "faq.jsonl"}
+	if err := runDatasetUpload(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc, req); err != nil {
+		t.Fatalf("runDatasetUpload: %v", err)
+	}
+	if svc.got.JSONLPath != "faq.jsonl" {
+		t.Errorf("request not forwarded: %+v", svc.got)
+	}
+	if !strings.Contains(out.String(), "ds1") || !strings.Contains(out.String(), "3 questions") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
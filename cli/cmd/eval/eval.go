@@ -0,0 +1,48 @@
+// Package evalcmd holds the `weknora eval` command tree: run / view / list /
+// results / compare plus `eval dataset` upload / list / delete.
+//
+// Evaluation runs are persisted server-side, so these commands work against
+// any replica and keep working after a server restart. `eval run --wait` is
+// the scripting entry point: it blocks until the run reaches a terminal state
+// and exits non-zero when the run failed; `eval compare --metric` is the
+// regression gate that exits non-zero when a run scores worse than a baseline.
+//
+// The directory is named `eval/` to match the cobra subcommand; the Go package
+// is `evalcmd` to keep it distinct from the SDK's Evaluation types.
+package evalcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+// NewCmd builds the `weknora eval` parent and registers leaves. Called from
+// cli/cmd/root.go.
+func NewCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Run and inspect retrieval / generation evaluations",
+		Long: `Start evaluation runs against a knowledge base's models, inspect their
+aggregated and per-question metrics, and manage the QA datasets they run on.
+Omit --dataset to use the bundled sample dataset.`,
+	}
+	cmd.AddCommand(NewCmdRun(f))
+	cmd.AddCommand(NewCmdView(f))
+	cmd.AddCommand(NewCmdList(f))
+	cmd.AddCommand(NewCmdResults(f))
+	cmd.AddCommand(NewCmdCompare(f))
+	cmd.AddCommand(NewCmdDataset(f))
+	return cmd
+}
+
+// progressLabel renders "finished/total" once the server knows the total.
+func progressLabel(t *sdk.EvaluationTask) string {
+	if t.Total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", t.Finished, t.Total)
+}
@@ -0,0 +1,103 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	"github.com/Tencent/WeKnora/cli/internal/output"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+var evalListFields = []string{
+	"id", "dataset_id", "knowledge_base_id", "chat_model_id", "rerank_model_id",
+	"status", "err_msg", "total", "finished", "start_time", "end_time",
+}
+
+// ListOptions captures `eval list` flag state.
+type ListOptions struct {
+	// Limit is passed to the server, which returns the newest runs first.
+	Limit int
+}
+
+// ListService is the narrow SDK surface this command depends on.
+type ListService interface {
+	ListEvaluations(ctx context.Context, limit int) ([]*sdk.EvaluationTask, error)
+}
+
+// NewCmdList builds `weknora eval list`.
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	opts := &ListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List evaluation runs",
+		Long:  `List the evaluation runs of the current tenant, newest first.`,
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			if err := validateLimit(opts.Limit); err != nil {
+				return err
+			}
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runList(c.Context(), opts, fopts, cli)
+		},
+	}
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", 30, "Maximum runs to return (1..1000)")
+	cmdutil.AddFormatFlag(cmd, evalListFields...)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor: "discover evaluation run ids and their status",
+		Examples: []string{
+			"weknora eval list",
+			"weknora eval list -L 5 --format json",
+		},
+		Output: "envelope.data is an array of EvaluationTask objects (id, dataset_id, status, total, finished, start_time), newest first; meta.count is the returned count",
+	})
+	return cmd
+}
+
+func validateLimit(limit int) error {
+	if limit < 1 || limit > 1000 {
+		return &cmdutil.Error{
+			Code:    cmdutil.CodeInputInvalidArgument,
+			Message: fmt.Sprintf("--limit must be in 1..1000, got %d", limit),
+		}
+	}
+	return nil
+}
+
+func runList(ctx context.Context, opts *ListOptions, fopts *cmdutil.FormatOptions, svc ListService) error {
+	if err := validateLimit(opts.Limit); err != nil {
+		return err
+	}
+	items, err := svc.ListEvaluations(ctx, opts.Limit)
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "list evaluations")
+	}
+	if items == nil {
+		items = []*sdk.EvaluationTask{} // ensure JSON [] not null
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, items, &output.Meta{Count: output.IntPtr(len(items))})
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(iostreams.IO.Out, "(no evaluations)")
+		return nil
+	}
+	tw := tabwriter.NewWriter(iostreams.IO.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tDATASET\tPROGRESS\tSTARTED")
+	for _, t := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.Status, t.DatasetID, progressLabel(t), t.StartTime)
+	}
+	return tw.Flush()
+}
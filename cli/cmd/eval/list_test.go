@@ -0,0 +1,61 @@
+package evalcmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeListSvc struct {
+	items    []*sdk.EvaluationTask
+	gotLimit int
+}
+
+func (f *fakeListSvc) ListEvaluations(_ context.Context, limit int) ([]*sdk.EvaluationTask, error) {
+	f.gotLimit = limit
+	return f.items, nil
+}
+
+func TestEvalList_Text(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeListSvc{items: []*sdk.EvaluationTask{
+		{ID: "t1", Status: sdk.EvaluationStatusRunning, DatasetID: "default", Total: 4, Finished: 1},
+	}}
+	if err := runList(context.Background(), &ListOptions{Limit: 5}, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc); err != nil {
+		t.Fatalf("runList: %v", err)
+	}
+	if svc.gotLimit != 5 {
+		t.Errorf("limit not forwarded: %d", svc.gotLimit)
+	}
+	for _, want := range []string{"ID", "t1", "running", "1/4"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestEvalList_EmptyJSON(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	if err := runList(context.Background(), &ListOptions{Limit: 30}, &cmdutil.FormatOptions{Mode: cmdutil.FormatJSON}, &fakeListSvc{}); err != nil {
+		t.Fatalf("runList: %v", err)
+	}
+	var env struct {
+		Data []any `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &env); err != nil || env.Data == nil {
+		t.Errorf("expected data: [], got %s", out.String())
+	}
+}
+
+func TestEvalList_InvalidLimit(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	err := runList(context.Background(), &ListOptions{Limit: 0}, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, &fakeListSvc{})
+	if typed := cmdutil.AsError(err); typed == nil || typed.Code != cmdutil.CodeInputInvalidArgument {
+		t.Fatalf("expected input.invalid_argument, got %v", err)
+	}
+}
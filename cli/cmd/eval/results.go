@@ -0,0 +1,87 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	"github.com/Tencent/WeKnora/cli/internal/output"
+	"github.com/Tencent/WeKnora/cli/internal/text"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+var evalResultsFields = []string{
+	"question_index", "qid", "question", "expected", "generated",
+	"retrieval_gt", "retrieval_ids", "metric",
+}
+
+// ResultsService is the narrow SDK surface this command depends on.
+type ResultsService interface {
+	ListEvaluationResults(ctx context.Context, taskID string) ([]*sdk.EvaluationQuestionResult, error)
+}
+
+// NewCmdResults builds `weknora eval results <task-id>`.
+func NewCmdResults(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "results <task-id>",
+		Short: "Show per-question results of an evaluation run",
+		Long: `List every evaluated question of a run: the expected and generated answer,
+the relevant vs. retrieved passage ids, and that question's metrics. Useful for
+finding the questions that drag an aggregate score down.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runResults(c.Context(), fopts, cli, args[0])
+		},
+	}
+	cmdutil.AddFormatFlag(cmd, evalResultsFields...)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:       "inspect per-question retrieval and answers of an evaluation run",
+		RequiredFlags: []string{"<task-id> (positional)"},
+		Examples:      []string{"weknora eval results 8f0c... --format json --jq '.data[] | select(.metric.retrieval_metrics.recall < 0.5)'"},
+		Output:        "envelope.data is an array of per-question results (question, expected, generated, retrieval_gt, retrieval_ids, metric) ordered by question_index",
+	})
+	return cmd
+}
+
+func runResults(ctx context.Context, fopts *cmdutil.FormatOptions, svc ResultsService, id string) error {
+	items, err := svc.ListEvaluationResults(ctx, id)
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "list evaluation results %q", id)
+	}
+	if items == nil {
+		items = []*sdk.EvaluationQuestionResult{}
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, items, &output.Meta{Count: output.IntPtr(len(items))})
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(iostreams.IO.Out, "(no results yet)")
+		return nil
+	}
+	tw := tabwriter.NewWriter(iostreams.IO.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tQUESTION\tRECALL\tMRR\tROUGE-L")
+	for _, r := range items {
+		var recall, mrr, rougeL float64
+		if r.Metric != nil {
+			recall = r.Metric.RetrievalMetrics.Recall
+			mrr = r.Metric.RetrievalMetrics.MRR
+			rougeL = r.Metric.GenerationMetrics.ROUGEL
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.3f\t%.3f\t%.3f\n",
+			r.QuestionIndex, text.Truncate(60, r.Question), recall, mrr, rougeL)
+	}
+	return tw.Flush()
+}
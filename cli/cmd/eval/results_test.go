@@ -0,0 +1,45 @@
+package evalcmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeResultsSvc struct {
+	items []*sdk.EvaluationQuestionResult
+}
+
+func (f *fakeResultsSvc) ListEvaluationResults(_ context.Context, _ string) ([]*sdk.EvaluationQuestionResult, error) {
+	return f.items, nil
+}
+
+func TestEvalResults_Text(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeResultsSvc{items: []*sdk.EvaluationQuestionResult{{
+		QuestionIndex: 3, Question: "what is weknora",
+		Metric: &sdk.EvaluationMetric{RetrievalMetrics: sdk.EvaluationRetrievalMetrics{Recall: 1, MRR: 0.5}},
+	}}}
+	if err := runResults(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc, "t1"); err != nil {
+		t.Fatalf("runResults: %v", err)
+	}
+	for _, want := range []string{"QUESTION", "what is weknora", "1.000", "0.500"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestEvalResults_Empty(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	if err := runResults(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, &fakeResultsSvc{}, "t1"); err != nil {
+		t.Fatalf("runResults: %v", err)
+	}
+	if !strings.Contains(out.String(), "no results") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
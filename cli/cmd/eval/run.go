@@ -0,0 +1,164 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+var evalRunFields = []string{"task", "params", "metric"}
+
+// RunOptions captures `eval run` flag state.
+type RunOptions struct {
+	Dataset     string
+	KB          string
+	ChatModel   string
+	RerankModel string
+	// JudgeModel is a chat model that scores faithfulness, answer relevance
+	// and context precision/recall; empty skips the judge metrics.
+	JudgeModel string
+	// Wait blocks until the run succeeds or fails, polling every Interval
+	// for at most Timeout.
+	Wait     bool
+	Timeout  time.Duration
+	Interval time.Duration
+	DryRun   bool
+}
+
+// RunService is the narrow SDK surface this command depends on.
+type RunService interface {
+	StartEvaluation(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.EvaluationResult, error)
+	GetEvaluationResult(ctx context.Context, taskID string) (*sdk.EvaluationResult, error)
+}
+
+// NewCmdRun builds `weknora eval run`.
+func NewCmdRun(f *cmdutil.Factory) *cobra.Command {
+	opts := &RunOptions{}
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Start an evaluation run",
+		Long: `Start an evaluation run. The run builds a throwaway knowledge base from the
+dataset's passages using --kb's embedding / parser config, then answers every
+question with --chat-model and scores retrieval and generation. With
+--judge-model a second chat model also grades faithfulness, answer relevance
+and context precision/recall.
+
+--dataset is an uploaded dataset id (see 'weknora eval dataset list'); omit it
+for the bundled sample. With --wait the command polls until the run finishes
+and exits 1 (operation.failed) when it failed, 124 when --timeout elapses.`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			req := opts.request()
+			if handled, err := cmdutil.HandleDryRun(c, opts.DryRun, cmdutil.DryRunPlan{
+				Action: "eval.run",
+				Method: "POST",
+				Path:   "/api/v1/evaluation",
+				Body:   req,
+			}); handled {
+				return err
+			}
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runRun(c.Context(), opts, fopts, cli)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Dataset, "dataset", "", "Dataset id (default: bundled sample dataset)")
+	cmd.Flags().StringVar(&opts.KB, "kb", "", "Knowledge base whose embedding / parser config the run uses")
+	cmd.Flags().StringVar(&opts.ChatModel, "chat-model", "", "Chat model id used to generate answers")
+	cmd.Flags().StringVar(&opts.RerankModel, "rerank-model", "", "Rerank model id")
+	cmd.Flags().StringVar(&opts.JudgeModel, "judge-model", "", "Chat model id grading answers as LLM judge (default: no judge metrics)")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", false, "Block until the run finishes; exit non-zero if it failed")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Max wait time with --wait before exiting 124")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 5*time.Second, "Poll interval with --wait")
+	cmdutil.AddFormatFlag(cmd, evalRunFields...)
+	cmdutil.AddDryRunFlag(cmd, &opts.DryRun)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor: "start an evaluation run (optionally waiting for it) to score retrieval and answer quality",
+		Examples: []string{
+			"weknora eval run --kb kb_abc --chat-model model_chat",
+			"weknora eval run --dataset ds_123 --kb kb_abc --chat-model model_chat --wait --format json",
+			"weknora eval run --kb kb_abc --chat-model model_chat --judge-model model_judge --wait",
+		},
+		Output: "envelope.data is {task, params, metric}; task.id feeds `eval view` / `eval results`; metric is set once the run finished (with --wait)",
+	})
+	return cmd
+}
+
+func (o *RunOptions) request() *sdk.EvaluationRequest {
+	return &sdk.EvaluationRequest{
+		DatasetID:       o.Dataset,
+		KnowledgeBaseID: o.KB,
+		ChatModelID:     o.ChatModel,
+		RerankModelID:   o.RerankModel,
+		JudgeModelID:    o.JudgeModel,
+	}
+}
+
+func runRun(ctx context.Context, opts *RunOptions, fopts *cmdutil.FormatOptions, svc RunService) error {
+	res, err := svc.StartEvaluation(ctx, opts.request())
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "start evaluation")
+	}
+	if opts.Wait && res.Task != nil {
+		res, err = waitForRun(ctx, svc, res.Task.ID, opts.Timeout, opts.Interval)
+		if err != nil {
+			return err
+		}
+	}
+	if fopts.WantsJSON() {
+		if err := fopts.Emit(iostreams.IO.Out, res, nil); err != nil {
+			return err
+		}
+	} else {
+		renderResult(res)
+	}
+	if opts.Wait && res.Task != nil && res.Task.Status == sdk.EvaluationStatusFailed {
+		return cmdutil.NewError(cmdutil.CodeOperationFailed,
+			fmt.Sprintf("evaluation %s failed: %s", res.Task.ID, res.Task.ErrMsg)).WithSilent()
+	}
+	return nil
+}
+
+// waitForRun polls the task until it succeeds or fails.
+func waitForRun(
+	ctx context.Context, svc RunService, taskID string, timeout, interval time.Duration,
+) (*sdk.EvaluationResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		res, err := svc.GetEvaluationResult(ctx, taskID)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, cmdutil.NewError(cmdutil.CodeOperationTimeout,
+					fmt.Sprintf("evaluation %s still running after %s", taskID, timeout))
+			}
+			return nil, cmdutil.WrapHTTP(err, "get evaluation %q", taskID)
+		}
+		if res.Task != nil &&
+			(res.Task.Status == sdk.EvaluationStatusSuccess || res.Task.Status == sdk.EvaluationStatusFailed) {
+			return res, nil
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, cmdutil.NewError(cmdutil.CodeOperationTimeout,
+					fmt.Sprintf("evaluation %s still running after %s", taskID, timeout))
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
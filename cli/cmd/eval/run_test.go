@@ -0,0 +1,106 @@
+package evalcmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeRunSvc struct {
+	gotReq *sdk.EvaluationRequest
+	start  *sdk.EvaluationResult
+	polls  []*sdk.EvaluationResult
+	calls  int
+}
+
+func (f *fakeRunSvc) StartEvaluation(_ context.Context, req *sdk.EvaluationRequest) (*sdk.EvaluationResult, error) {
+	f.gotReq = req
+	return f.start, nil
+}
+
+func (f *fakeRunSvc) GetEvaluationResult(_ context.Context, _ string) (*sdk.EvaluationResult, error) {
+	res := f.polls[min(f.calls, len(f.polls)-1)]
+	f.calls++
+	return res, nil
+}
+
+func runningResult(status sdk.EvaluationStatus) *sdk.EvaluationResult {
+	return &sdk.EvaluationResult{Task: &sdk.EvaluationTask{ID: "t1", Status: status, DatasetID: "default"}}
+}
+
+func TestEvalRun_SendsRequest(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeRunSvc{start: runningResult(sdk.EvaluationStatusPending)}
+	opts := &RunOptions{Dataset: "ds1", KB: "kb1", ChatModel: "m1", JudgeModel: "j1"}
+	if err := runRun(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc); err != nil {
+		t.Fatalf("runRun: %v", err)
+	}
+	if svc.gotReq.DatasetID != "ds1" || svc.gotReq.KnowledgeBaseID != "kb1" || svc.gotReq.ChatModelID != "m1" ||
+		svc.gotReq.JudgeModelID != "j1" {
+		t.Errorf("unexpected request: %+v", svc.gotReq)
+	}
+	if svc.calls != 0 {
+		t.Errorf("expected no polling without --wait, got %d calls", svc.calls)
+	}
+	if !strings.Contains(out.String(), "pending") {
+		t.Errorf("missing status in:\n%s", out.String())
+	}
+}
+
+func TestEvalRun_WaitSuccess(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	done := runningResult(sdk.EvaluationStatusSuccess)
+	done.Metric = &sdk.EvaluationMetric{RetrievalMetrics: sdk.EvaluationRetrievalMetrics{MRR: 0.5}}
+	svc := &fakeRunSvc{
+		start: runningResult(sdk.EvaluationStatusPending),
+		polls: []*sdk.EvaluationResult{runningResult(sdk.EvaluationStatusRunning), done},
+	}
+	opts := &RunOptions{Wait: true, Timeout: time.Second, Interval: time.Millisecond}
+	if err := runRun(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatJSON}, svc); err != nil {
+		t.Fatalf("runRun: %v", err)
+	}
+	var env struct {
+		OK   bool                 `json:"ok"`
+		Data sdk.EvaluationResult `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &env); err != nil {
+		t.Fatalf("parse: %v\n%s", err, out.String())
+	}
+	if env.Data.Metric == nil || env.Data.Metric.RetrievalMetrics.MRR != 0.5 {
+		t.Errorf("expected final metric in output, got %s", out.String())
+	}
+	if svc.calls != 2 {
+		t.Errorf("expected 2 polls, got %d", svc.calls)
+	}
+}
+
+func TestEvalRun_WaitFailedExitsNonZero(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	failed := runningResult(sdk.EvaluationStatusFailed)
+	failed.Task.ErrMsg = "boom"
+	svc := &fakeRunSvc{start: runningResult(sdk.EvaluationStatusPending), polls: []*sdk.EvaluationResult{failed}}
+	opts := &RunOptions{Wait: true, Timeout: time.Second, Interval: time.Millisecond}
+	err := runRun(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc)
+	if typed := cmdutil.AsError(err); typed == nil || typed.Code != cmdutil.CodeOperationFailed {
+		t.Fatalf("expected operation.failed, got %v", err)
+	}
+}
+
+func TestEvalRun_WaitTimeout(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	svc := &fakeRunSvc{
+		start: runningResult(sdk.EvaluationStatusPending),
+		polls: []*sdk.EvaluationResult{runningResult(sdk.EvaluationStatusRunning)},
+	}
+	opts := &RunOptions{Wait: true, Timeout: 20 * time.Millisecond, Interval: 5 * time.Millisecond}
+	err := runRun(context.Background(), opts, &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc)
+	if typed := cmdutil.AsError(err); typed == nil || typed.Code != cmdutil.CodeOperationTimeout {
+		t.Fatalf("expected operation.timeout, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package evalcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+// ViewService is the narrow SDK surface this command depends on.
+type ViewService interface {
+	GetEvaluationResult(ctx context.Context, taskID string) (*sdk.EvaluationResult, error)
+}
+
+// NewCmdView builds `weknora eval view <task-id>`.
+func NewCmdView(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view <task-id>",
+		Short: "Show an evaluation run and its aggregated metrics",
+		Long:  `Fetch one evaluation run: status, progress, and the aggregated retrieval and generation metrics.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			fopts, err := cmdutil.CheckFormatFlag(c)
+			if err != nil {
+				return err
+			}
+			fopts.ResolveDefault(iostreams.IO.IsStdoutTTY())
+			cli, err := f.Client()
+			if err != nil {
+				return err
+			}
+			return runView(c.Context(), fopts, cli, args[0])
+		},
+	}
+	cmdutil.AddFormatFlag(cmd, evalRunFields...)
+	cmdutil.SetAgentHelp(cmd, cmdutil.AgentHelp{
+		UsedFor:       "fetch one evaluation run's status and aggregated metrics by task id",
+		RequiredFlags: []string{"<task-id> (positional)"},
+		Examples:      []string{"weknora eval view 8f0c... --jq .data.metric.retrieval_metrics.mrr"},
+		Output:        "envelope.data is {task, params, metric}; task.status is 0 pending, 1 running, 2 success, 3 failed",
+	})
+	return cmd
+}
+
+func runView(ctx context.Context, fopts *cmdutil.FormatOptions, svc ViewService, id string) error {
+	res, err := svc.GetEvaluationResult(ctx, id)
+	if err != nil {
+		return cmdutil.WrapHTTP(err, "get evaluation %q", id)
+	}
+	if fopts.WantsJSON() {
+		return fopts.Emit(iostreams.IO.Out, res, nil)
+	}
+	renderResult(res)
+	return nil
+}
+
+// renderResult prints a run in the human format shared by `run` and `view`.
+func renderResult(res *sdk.EvaluationResult) {
+	w := iostreams.IO.Out
+	if t := res.Task; t != nil {
+		fmt.Fprintf(w, "ID:        %s\n", t.ID)
+		fmt.Fprintf(w, "STATUS:    %s\n", t.Status)
+		fmt.Fprintf(w, "DATASET:   %s\n", t.DatasetID)
+		if t.KnowledgeBaseID != "" {
+			fmt.Fprintf(w, "KB:        %s\n", t.KnowledgeBaseID)
+		}
+		fmt.Fprintf(w, "PROGRESS:  %s\n", progressLabel(t))
+		fmt.Fprintf(w, "STARTED:   %s\n", t.StartTime)
+		if t.EndTime != "" {
+			fmt.Fprintf(w, "ENDED:     %s\n", t.EndTime)
+		}
+		if t.ErrMsg != "" {
+			fmt.Fprintf(w, "ERROR:     %s\n", t.ErrMsg)
+		}
+	}
+	if m := res.Metric; m != nil {
+		r, g := m.RetrievalMetrics, m.GenerationMetrics
+		fmt.Fprintf(w, "RETRIEVAL: precision=%.4f recall=%.4f ndcg@3=%.4f ndcg@10=%.4f mrr=%.4f map=%.4f\n",
+			r.Precision, r.Recall, r.NDCG3, r.NDCG10, r.MRR, r.MAP)
+		fmt.Fprintf(w, "GENERATION: bleu1=%.4f bleu2=%.4f bleu4=%.4f rouge1=%.4f rouge2=%.4f rougeL=%.4f\n",
+			g.BLEU1, g.BLEU2, g.BLEU4, g.ROUGE1, g.ROUGE2, g.ROUGEL)
+		if j := m.JudgeMetrics; j != nil {
+			fmt.Fprintf(w, "JUDGE:     faithfulness=%.4f answer_relevance=%.4f context_precision=%.4f context_recall=%.4f\n",
+				j.Faithfulness, j.AnswerRelevance, j.ContextPrecision, j.ContextRecall)
+		}
+	}
+}
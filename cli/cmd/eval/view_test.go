@@ -0,0 +1,50 @@
+package evalcmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/cli/internal/cmdutil"
+	"github.com/Tencent/WeKnora/cli/internal/iostreams"
+	sdk "github.com/Tencent/WeKnora/client"
+)
+
+type fakeViewSvc struct {
+	res *sdk.EvaluationResult
+	err error
+}
+
+func (f *fakeViewSvc) GetEvaluationResult(_ context.Context, _ string) (*sdk.EvaluationResult, error) {
+	return f.res, f.err
+}
+
+func TestEvalView_Text(t *testing.T) {
+	out, _ := iostreams.SetForTest(t)
+	svc := &fakeViewSvc{res: &sdk.EvaluationResult{
+		Task: &sdk.EvaluationTask{ID: "t1", Status: sdk.EvaluationStatusSuccess, DatasetID: "ds1", Total: 10, Finished: 10},
+		Metric: &sdk.EvaluationMetric{
+			RetrievalMetrics: sdk.EvaluationRetrievalMetrics{Recall: 0.75},
+			JudgeMetrics:     &sdk.EvaluationJudgeMetrics{Faithfulness: 0.5},
+		},
+	}}
+	if err := runView(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc, "t1"); err != nil {
+		t.Fatalf("runView: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"t1", "success", "ds1", "10/10", "recall=0.7500", "faithfulness=0.5000"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestEvalView_NotFound(t *testing.T) {
+	_, _ = iostreams.SetForTest(t)
+	svc := &fakeViewSvc{err: errors.New("HTTP error 404: not found")}
+	err := runView(context.Background(), &cmdutil.FormatOptions{Mode: cmdutil.FormatText}, svc, "missing")
+	if !cmdutil.IsNotFound(err) {
+		t.Errorf("expected resource.not_found, got %v", err)
+	}
+}
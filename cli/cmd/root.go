@@ -18,6 +18,7 @@ import (
 	configcmd "github.com/Tencent/WeKnora/cli/cmd/config"
 	"github.com/Tencent/WeKnora/cli/cmd/doc"
 	"github.com/Tencent/WeKnora/cli/cmd/doctor"
+	evalcmd "github.com/Tencent/WeKnora/cli/cmd/eval"
 	"github.com/Tencent/WeKnora/cli/cmd/kb"
 	linkcmd "github.com/Tencent/WeKnora/cli/cmd/link"
 	messagecmd "github.com/Tencent/WeKnora/cli/cmd/message"
@@ -211,6 +212,7 @@ a curated read-only MCP tool surface for AI agents.`,
 	cmd.AddCommand(messagecmd.NewCmd(f))
 	cmd.AddCommand(agentcmd.NewCmd(f))
 	cmd.AddCommand(modelcmd.NewCmd(f))
+	cmd.AddCommand(evalcmd.NewCmd(f))
 	cmd.AddCommand(chunkcmd.NewCmdChunk(f))
 	cmd.AddCommand(mcpcmd.NewCmd(f))
 	cmd.AddCommand(skillscmd.NewCmd(f))
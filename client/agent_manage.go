@@ -104,6 +104,9 @@ type AgentConfig struct {
 	FAQPriorityEnabled          bool                      `json:"faq_priority_enabled"`
 	FAQDirectAnswerThreshold    float64                   `json:"faq_direct_answer_threshold"`
 	FAQScoreBoost               float64                   `json:"faq_score_boost"`
+	AnswerCacheEnabled          bool                      `json:"answer_cache_enabled"`
+	AnswerCacheThreshold        float64                   `json:"answer_cache_threshold,omitempty"`
+	AnswerCacheTTLHours         int                       `json:"answer_cache_ttl_hours,omitempty"`
 	WebSearchEnabled            bool                      `json:"web_search_enabled"`
 	WebSearchMaxResults         int                       `json:"web_search_max_results"`
 	WebSearchProviderID         string                    `json:"web_search_provider_id,omitempty"`
@@ -118,6 +121,8 @@ type AgentConfig struct {
 	RerankThreshold             float64                   `json:"rerank_threshold"`
 	EnableQueryExpansion        bool                      `json:"enable_query_expansion"`
 	EnableRewrite               bool                      `json:"enable_rewrite"`
+	EnableHyDE                  bool                      `json:"enable_hyde"`
+	EnableQueryDecomposition    bool                      `json:"enable_query_decomposition"`
 	RewritePromptSystem         string                    `json:"rewrite_prompt_system"`
 	RewritePromptUser           string                    `json:"rewrite_prompt_user"`
 	QueryUnderstandModelID      string                    `json:"query_understand_model_id,omitempty"`
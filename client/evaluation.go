@@ -5,55 +5,159 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// EvaluationStatus is the lifecycle state of an evaluation task
+type EvaluationStatus int
+
+// Evaluation task states, matching the server's numeric encoding
+const (
+	EvaluationStatusPending EvaluationStatus = iota // Task is waiting to start
+	EvaluationStatusRunning                         // Task is in progress
+	EvaluationStatusSuccess                         // Task completed successfully
+	EvaluationStatusFailed                          // Task failed
+)
+
+// String returns the lowercase name of the status
+func (s EvaluationStatus) String() string {
+	switch s {
+	case EvaluationStatusPending:
+		return "pending"
+	case EvaluationStatusRunning:
+		return "running"
+	case EvaluationStatusSuccess:
+		return "success"
+	case EvaluationStatusFailed:
+		return "failed"
+	default:
+		return strconv.Itoa(int(s))
+	}
+}
+
 // EvaluationTask represents an evaluation task
 // Contains basic information about a model evaluation task
 type EvaluationTask struct {
-	ID          string `json:"id"`           // Task unique identifier
-	Status      string `json:"status"`       // Task status: pending, running, completed, failed
-	Progress    int    `json:"progress"`     // Task progress, integer value 0-100
-	DatasetID   string `json:"dataset_id"`   // Evaluation dataset ID
-	EmbeddingID string `json:"embedding_id"` // Embedding model ID
-	ChatID      string `json:"chat_id"`      // Chat model ID
-	RerankID    string `json:"rerank_id"`    // Reranking model ID
-	CreatedAt   string `json:"created_at"`   // Task creation time
-	CompleteAt  string `json:"complete_at"`  // Task completion time
-	ErrorMsg    string `json:"error_msg"`    // Error message, has value when task fails
+	ID              string           `json:"id"`                          // Task unique identifier
+	TenantID        uint64           `json:"tenant_id"`                   // Owning tenant
+	DatasetID       string           `json:"dataset_id"`                  // Evaluation dataset ID
+	KnowledgeBaseID string           `json:"knowledge_base_id,omitempty"` // Knowledge base the run was built from
+	ChatModelID     string           `json:"chat_model_id,omitempty"`     // Chat model ID
+	RerankModelID   string           `json:"rerank_model_id,omitempty"`   // Reranking model ID
+	JudgeModelID    string           `json:"judge_model_id,omitempty"`    // Chat model scoring judge metrics
+	FusionConfig    *FusionConfig    `json:"fusion_config,omitempty"`     // Fusion override the run searched with
+	Status          EvaluationStatus `json:"status"`                      // Task status
+	ErrMsg          string           `json:"err_msg,omitempty"`           // Error message, has value when task fails
+	Total           int              `json:"total,omitempty"`             // Number of questions to evaluate
+	Finished        int              `json:"finished,omitempty"`          // Number of questions evaluated so far
+	StartTime       string           `json:"start_time"`                  // Task start time
+	EndTime         string           `json:"end_time,omitempty"`          // Task end time, empty while running
+	CreatedAt       string           `json:"created_at"`                  // Creation time
+	UpdatedAt       string           `json:"updated_at"`                  // Last progress update
+}
+
+// EvaluationRetrievalMetrics contains retrieval quality metrics
+type EvaluationRetrievalMetrics struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	NDCG3     float64 `json:"ndcg3"`
+	NDCG10    float64 `json:"ndcg10"`
+	MRR       float64 `json:"mrr"`
+	MAP       float64 `json:"map"`
+}
+
+// EvaluationGenerationMetrics contains answer generation quality metrics
+type EvaluationGenerationMetrics struct {
+	BLEU1  float64 `json:"bleu1"`
+	BLEU2  float64 `json:"bleu2"`
+	BLEU4  float64 `json:"bleu4"`
+	ROUGE1 float64 `json:"rouge1"`
+	ROUGE2 float64 `json:"rouge2"`
+	ROUGEL float64 `json:"rougel"`
+}
+
+// EvaluationJudgeMetrics contains LLM-as-judge answer quality scores in [0, 1]
+type EvaluationJudgeMetrics struct {
+	Faithfulness     float64 `json:"faithfulness"`
+	AnswerRelevance  float64 `json:"answer_relevance"`
+	ContextPrecision float64 `json:"context_precision"`
+	ContextRecall    float64 `json:"context_recall"`
+}
+
+// EvaluationMetric groups the retrieval, generation and judge metrics of a run or question
+type EvaluationMetric struct {
+	RetrievalMetrics  EvaluationRetrievalMetrics  `json:"retrieval_metrics"`
+	GenerationMetrics EvaluationGenerationMetrics `json:"generation_metrics"`
+	// JudgeMetrics is nil when the run had no judge model
+	JudgeMetrics *EvaluationJudgeMetrics `json:"judge_metrics,omitempty"`
 }
 
 // EvaluationResult represents the evaluation results
-// Contains detailed evaluation result information
+// Contains the task, the effective pipeline parameters and the aggregated metric
 type EvaluationResult struct {
-	TaskID       string                   `json:"task_id"`       // Associated task ID
-	Status       string                   `json:"status"`        // Task status
-	Progress     int                      `json:"progress"`      // Task progress
-	TotalQueries int                      `json:"total_queries"` // Total number of queries
-	TotalSamples int                      `json:"total_samples"` // Total number of samples
-	Metrics      map[string]float64       `json:"metrics"`       // Evaluation metrics collection
-	QueriesStat  []map[string]interface{} `json:"queries_stat"`  // Statistics for each query
-	CreatedAt    string                   `json:"created_at"`    // Creation time
-	CompleteAt   string                   `json:"complete_at"`   // Completion time
-	ErrorMsg     string                   `json:"error_msg"`     // Error message
+	Task   *EvaluationTask        `json:"task"`             // Evaluation task info
+	Params map[string]interface{} `json:"params,omitempty"` // Pipeline parameters used for the run
+	Metric *EvaluationMetric      `json:"metric,omitempty"` // Aggregated metric, set once questions finished
+}
+
+// EvaluationQuestionResult is the outcome of one question of an evaluation run
+type EvaluationQuestionResult struct {
+	ID            uint64            `json:"id"`
+	TaskID        string            `json:"task_id"`
+	QuestionIndex int               `json:"question_index"` // Position in the dataset iteration
+	QID           int               `json:"qid"`            // Question ID from the dataset
+	Question      string            `json:"question"`
+	Expected      string            `json:"expected"`      // Ground-truth answer
+	Generated     string            `json:"generated"`     // Answer produced by the pipeline
+	RetrievalGT   []int             `json:"retrieval_gt"`  // Relevant passage IDs
+	RetrievalIDs  []int             `json:"retrieval_ids"` // Retrieved passage IDs, ranked
+	Metric        *EvaluationMetric `json:"metric"`
+	CreatedAt     string            `json:"created_at"`
+}
+
+// EvaluationDataset is an uploaded evaluation dataset
+type EvaluationDataset struct {
+	ID           string `json:"id"`
+	TenantID     uint64 `json:"tenant_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Format       string `json:"format"` // jsonl or parquet
+	QACount      int    `json:"qa_count"`
+	PassageCount int    `json:"passage_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
 }
 
 // EvaluationRequest represents an evaluation request
 // Parameters used to start a new evaluation task
 type EvaluationRequest struct {
-	DatasetID        string `json:"dataset_id"`   // Dataset ID to evaluate
-	EmbeddingModelID string `json:"embedding_id"` // Embedding model ID
-	ChatModelID      string `json:"chat_id"`      // Chat model ID
-	RerankModelID    string `json:"rerank_id"`    // Reranking model ID
+	DatasetID       string `json:"dataset_id"`                  // Dataset ID to evaluate, empty for the bundled default
+	KnowledgeBaseID string `json:"knowledge_base_id,omitempty"` // Knowledge base whose models/config are used
+	ChatModelID     string `json:"chat_id,omitempty"`           // Chat model ID
+	RerankModelID   string `json:"rerank_id,omitempty"`         // Reranking model ID
+	JudgeModelID    string `json:"judge_model_id,omitempty"`    // Chat model scoring judge metrics, empty to skip them
+	// FusionConfig overrides the hybrid search fusion of this run
+	FusionConfig *FusionConfig `json:"fusion_config,omitempty"`
 }
 
-// EvaluationTaskResponse represents an evaluation task response
-// API response structure for evaluation tasks
-type EvaluationTaskResponse struct {
-	Success bool           `json:"success"` // Whether operation was successful
-	Data    EvaluationTask `json:"data"`    // Evaluation task data
+// EvaluationDatasetUploadRequest describes a dataset upload. Set JSONLPath for
+// a JSONL dataset, or ParquetPaths (keyed by queries, corpus, qrels, answers,
+// qas) for a parquet dataset laid out like the bundled sample.
+type EvaluationDatasetUploadRequest struct {
+	Name         string
+	Description  string
+	JSONLPath    string
+	ParquetPaths map[string]string
 }
 
 // EvaluationResultResponse represents an evaluation result response
@@ -63,6 +167,30 @@ type EvaluationResultResponse struct {
 	Data    EvaluationResult `json:"data"`    // Evaluation result data
 }
 
+// EvaluationTaskListResponse is the API response for listing evaluation tasks
+type EvaluationTaskListResponse struct {
+	Success bool              `json:"success"`
+	Data    []*EvaluationTask `json:"data"`
+}
+
+// EvaluationQuestionResultListResponse is the API response for per-question results
+type EvaluationQuestionResultListResponse struct {
+	Success bool                        `json:"success"`
+	Data    []*EvaluationQuestionResult `json:"data"`
+}
+
+// EvaluationDatasetResponse is the API response for a single dataset
+type EvaluationDatasetResponse struct {
+	Success bool              `json:"success"`
+	Data    EvaluationDataset `json:"data"`
+}
+
+// EvaluationDatasetListResponse is the API response for listing datasets
+type EvaluationDatasetListResponse struct {
+	Success bool                 `json:"success"`
+	Data    []*EvaluationDataset `json:"data"`
+}
+
 // StartEvaluation starts an evaluation task
 // Creates and starts a new evaluation task based on provided parameters
 // Parameters:
@@ -70,15 +198,15 @@ type EvaluationResultResponse struct {
 //   - request: Evaluation request parameters, including dataset ID and model IDs
 //
 // Returns:
-//   - *EvaluationTask: Created evaluation task information
+//   - *EvaluationResult: Created evaluation task and its parameters
 //   - error: Error information if the request fails
-func (c *Client) StartEvaluation(ctx context.Context, request *EvaluationRequest) (*EvaluationTask, error) {
+func (c *Client) StartEvaluation(ctx context.Context, request *EvaluationRequest) (*EvaluationResult, error) {
 	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/evaluation", request, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var response EvaluationTaskResponse
+	var response EvaluationResultResponse
 	if err := parseResponse(resp, &response); err != nil {
 		return nil, err
 	}
@@ -111,3 +239,242 @@ func (c *Client) GetEvaluationResult(ctx context.Context, taskID string) (*Evalu
 
 	return &response.Data, nil
 }
+
+// ListEvaluations lists the evaluation tasks of the current tenant, newest
+// first. limit <= 0 uses the server default.
+func (c *Client) ListEvaluations(ctx context.Context, limit int) ([]*EvaluationTask, error) {
+	var queryParams url.Values
+	if limit > 0 {
+		queryParams = url.Values{}
+		queryParams.Add("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/evaluation/tasks", nil, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EvaluationTaskListResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ListEvaluationResults returns the per-question results of an evaluation task
+func (c *Client) ListEvaluationResults(ctx context.Context, taskID string) ([]*EvaluationQuestionResult, error) {
+	path := fmt.Sprintf("/api/v1/evaluation/tasks/%s/results", url.PathEscape(taskID))
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EvaluationQuestionResultListResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ListEvaluationDatasets lists the uploaded evaluation datasets of the current tenant
+func (c *Client) ListEvaluationDatasets(ctx context.Context) ([]*EvaluationDataset, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/evaluation/datasets", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EvaluationDatasetListResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// DeleteEvaluationDataset deletes an uploaded evaluation dataset
+func (c *Client) DeleteEvaluationDataset(ctx context.Context, datasetID string) error {
+	path := fmt.Sprintf("/api/v1/evaluation/datasets/%s", url.PathEscape(datasetID))
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	return parseResponse(resp, nil)
+}
+
+// UploadEvaluationDataset uploads a JSONL or parquet dataset from local files
+func (c *Client) UploadEvaluationDataset(
+	ctx context.Context, request *EvaluationDatasetUploadRequest,
+) (*EvaluationDataset, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("name", request.Name); err != nil {
+		return nil, fmt.Errorf("failed to write name field: %w", err)
+	}
+	if request.Description != "" {
+		if err := writer.WriteField("description", request.Description); err != nil {
+			return nil, fmt.Errorf("failed to write description field: %w", err)
+		}
+	}
+
+	format := "parquet"
+	files := request.ParquetPaths
+	if request.JSONLPath != "" {
+		format = "jsonl"
+		files = map[string]string{"file": request.JSONLPath}
+	}
+	if err := writer.WriteField("format", format); err != nil {
+		return nil, fmt.Errorf("failed to write format field: %w", err)
+	}
+	for field, path := range files {
+		if err := writeMultipartFile(writer, field, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/api/v1/evaluation/datasets", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	c.applyAuthHeaders(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var response EvaluationDatasetResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// writeMultipartFile copies a local file into a multipart form field
+func writeMultipartFile(writer *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
+
+// EvaluationCompareRequest selects the runs to diff and the optional
+// regression gate. The gate applies only when Metrics is non-empty.
+type EvaluationCompareRequest struct {
+	BaseTaskID   string
+	TargetTaskID string
+	Metrics      []string // e.g. mrr, ndcg10, rougel
+	MaxDrop      float64  // Largest tolerated absolute drop of a gated metric
+}
+
+// EvaluationMetricDelta compares one metric between the baseline and target run
+type EvaluationMetricDelta struct {
+	Metric    string  `json:"metric"`
+	Base      float64 `json:"base"`
+	Target    float64 `json:"target"`
+	Delta     float64 `json:"delta"`
+	Regressed bool    `json:"regressed"`
+}
+
+// EvaluationQuestionComparison compares one question answered by both runs
+type EvaluationQuestionComparison struct {
+	QID             int                     `json:"qid"`
+	Question        string                  `json:"question"`
+	BaseGenerated   string                  `json:"base_generated"`
+	TargetGenerated string                  `json:"target_generated"`
+	Deltas          []EvaluationMetricDelta `json:"deltas"`
+	Regressed       bool                    `json:"regressed"`
+}
+
+// EvaluationGate is the regression gate applied by a comparison
+type EvaluationGate struct {
+	Metrics []string `json:"metrics"`
+	MaxDrop float64  `json:"max_drop"`
+}
+
+// EvaluationComparison is the diff of two evaluation runs
+type EvaluationComparison struct {
+	BaseTaskID         string                         `json:"base_task_id"`
+	TargetTaskID       string                         `json:"target_task_id"`
+	DatasetID          string                         `json:"dataset_id"`
+	Gate               *EvaluationGate                `json:"gate,omitempty"`
+	Aggregate          []EvaluationMetricDelta        `json:"aggregate"`
+	Questions          []EvaluationQuestionComparison `json:"questions"`
+	UnmatchedQuestions int                            `json:"unmatched_questions"`
+	RegressedQuestions int                            `json:"regressed_questions"`
+	Passed             bool                           `json:"passed"` // False when a gated aggregate metric regressed
+}
+
+// EvaluationComparisonResponse is the API response for a run comparison
+type EvaluationComparisonResponse struct {
+	Success bool                 `json:"success"`
+	Data    EvaluationComparison `json:"data"`
+}
+
+// CompareEvaluations diffs a target run against a baseline run per question
+// and in aggregate. With a gate, Passed reports whether any gated metric
+// dropped by more than MaxDrop.
+func (c *Client) CompareEvaluations(ctx context.Context, request *EvaluationCompareRequest) (*EvaluationComparison, error) {
+	queryParams := url.Values{}
+	queryParams.Add("base_task_id", request.BaseTaskID)
+	queryParams.Add("target_task_id", request.TargetTaskID)
+	if len(request.Metrics) > 0 {
+		queryParams.Add("metrics", strings.Join(request.Metrics, ","))
+		queryParams.Add("max_drop", strconv.FormatFloat(request.MaxDrop, 'f', -1, 64))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/evaluation/compare", nil, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EvaluationComparisonResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// FusionTuneRequest selects the evaluation runs whose fusion weights are
+// compared and the knowledge base that receives the winner
+type FusionTuneRequest struct {
+	KnowledgeBaseID string   `json:"knowledge_base_id"`
+	TaskIDs         []string `json:"task_ids"`
+	Metric          string   `json:"metric,omitempty"` // Defaults to ndcg10
+}
+
+// FusionTuneResponse is the API response for fusion weight tuning
+type FusionTuneResponse struct {
+	Success bool         `json:"success"`
+	Data    FusionConfig `json:"data"`
+}
+
+// TuneFusionWeights stores the score fusion weights of the best-scoring run
+// on the knowledge base as its learned fusion, and returns the new config
+func (c *Client) TuneFusionWeights(ctx context.Context, request *FusionTuneRequest) (*FusionConfig, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/evaluation/fusion/tune", request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FusionTuneResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
@@ -31,6 +31,7 @@ type KnowledgeBase struct {
 	StorageConfig         StorageConfig          `json:"storage_config"`
 	ExtractConfig         *ExtractConfig         `json:"extract_config"`
 	AutoTagConfig         *AutoTagConfig         `json:"auto_tag_config"`
+	FusionConfig          *FusionConfig          `json:"fusion_config,omitempty"`
 	CreatedAt             time.Time              `json:"created_at"`
 	UpdatedAt             time.Time              `json:"updated_at"`
 	// Computed fields (not stored in database)
@@ -46,6 +47,33 @@ type KnowledgeBaseConfig struct {
 	ImageProcessingConfig ImageProcessingConfig `json:"image_processing_config"`
 	FAQConfig             *FAQConfig            `json:"faq_config"`
 	AutoTagConfig         *AutoTagConfig        `json:"auto_tag_config,omitempty"`
+	// FusionConfig overrides the tenant's hybrid search fusion; nil leaves it
+	// unchanged, an empty value clears the override.
+	FusionConfig *FusionConfig `json:"fusion_config,omitempty"`
+}
+
+// FusionConfig controls how vector and keyword results of a hybrid search are
+// merged: rrf (default), weighted, dbsf or learned
+type FusionConfig struct {
+	FusionStrategy     string                `json:"fusion_strategy,omitempty"`
+	RRFK               int                   `json:"rrf_k,omitempty"`
+	RRFVectorWeight    float64               `json:"rrf_vector_weight,omitempty"`
+	RRFKeywordWeight   float64               `json:"rrf_keyword_weight,omitempty"`
+	ScoreVectorWeight  float64               `json:"score_vector_weight,omitempty"`
+	ScoreKeywordWeight float64               `json:"score_keyword_weight,omitempty"`
+	LearnedWeights     *LearnedFusionWeights `json:"learned_weights,omitempty"`
+}
+
+// LearnedFusionWeights are the weights the evaluation fusion tuner picked
+type LearnedFusionWeights struct {
+	VectorWeight  float64   `json:"vector_weight"`
+	KeywordWeight float64   `json:"keyword_weight"`
+	Normalization string    `json:"normalization"`  // weighted (min-max) or dbsf
+	SourceTaskID  string    `json:"source_task_id"` // Evaluation run the weights came from
+	Metric        string    `json:"metric"`
+	MetricValue   float64   `json:"metric_value"`
+	RunCount      int       `json:"run_count"`
+	TunedAt       time.Time `json:"tuned_at"`
 }
 
 // ChunkingConfig represents document chunking configuration
@@ -11,9 +11,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
 	"go.uber.org/dig"
 
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/dbgraph"
+	neo4jRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/neo4j"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 )
 
@@ -33,9 +37,10 @@ import (
 const bootstrapEnvVar = "WEKNORA_BOOTSTRAP_SYSTEM_ADMIN_EMAIL"
 
 // runStartupBootstrap consults the env and applies any one-shot
-// bootstrap actions. Currently it only handles system-admin promotion;
-// future bootstrap steps (default model seeding, etc.) can be added
-// here as additional dig.Invoke calls.
+// bootstrap actions: API key hash repair, the opt-in Neo4j → database
+// graph migration and system-admin promotion. Future bootstrap steps
+// (default model seeding, etc.) can be added here as additional
+// dig.Invoke calls.
 func runStartupBootstrap(c *dig.Container) {
 	ctx := context.Background()
 
@@ -52,6 +57,15 @@ func runStartupBootstrap(c *dig.Container) {
 		logger.Warnf(ctx, "[bootstrap] failed to resolve TenantAPIKeyService: %v", err)
 	}
 
+	if types.GraphMigrateFromNeo4j() {
+		if err := c.Invoke(func(driver neo4j.Driver, graphRepo interfaces.RetrieveGraphRepository) {
+			// Copying a large graph can take minutes; do not hold up startup.
+			go migrateGraphFromNeo4j(ctx, driver, graphRepo)
+		}); err != nil {
+			logger.Warnf(ctx, "[bootstrap] failed to resolve graph stores: %v", err)
+		}
+	}
+
 	email := strings.TrimSpace(os.Getenv(bootstrapEnvVar))
 	if email == "" {
 		return
@@ -123,3 +137,34 @@ func bootstrapSystemAdmin(ctx context.Context, userSvc interfaces.UserService, e
 		"[bootstrap] promoted user %s (%s) to system admin via %s",
 		user.ID, email, bootstrapEnvVar)
 }
+
+// migrateGraphFromNeo4j copies the Neo4j knowledge graph into the relational
+// graph store (GRAPH_DRIVER=database, GRAPH_MIGRATE_FROM_NEO4J=true). The
+// import is idempotent, so leaving the flag set only repeats a no-op copy on
+// each restart; unset it once the log reports completion.
+func migrateGraphFromNeo4j(ctx context.Context, driver neo4j.Driver, graphRepo interfaces.RetrieveGraphRepository) {
+	if driver == nil {
+		logger.Warnf(ctx, "[bootstrap] GRAPH_MIGRATE_FROM_NEO4J: Neo4j is not connected, check NEO4J_URI")
+		return
+	}
+	dst, ok := graphRepo.(*dbgraph.Repository)
+	if !ok {
+		logger.Warnf(ctx, "[bootstrap] GRAPH_MIGRATE_FROM_NEO4J: graph store is not the database backend")
+		return
+	}
+	src, ok := neo4jRepo.NewNeo4jRepository(driver).(dbgraph.Exporter)
+	if !ok {
+		logger.Warnf(ctx, "[bootstrap] GRAPH_MIGRATE_FROM_NEO4J: Neo4j repository cannot export")
+		return
+	}
+	logger.Infof(ctx, "[bootstrap] copying knowledge graph from Neo4j into the database")
+	stats, err := dst.Import(ctx, src)
+	if err != nil {
+		logger.Errorf(ctx, "[bootstrap] graph migration from Neo4j failed after %d document(s): %v",
+			stats.Documents, err)
+		return
+	}
+	logger.Infof(ctx,
+		"[bootstrap] graph migration from Neo4j finished: %d document(s), %d node(s), %d relation(s), %d skipped",
+		stats.Documents, stats.Nodes, stats.Relations, stats.Skipped)
+}
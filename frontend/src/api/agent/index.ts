@@ -121,6 +121,8 @@ export interface CustomAgentConfig {
   // ===== 高级设置（主要用于普通模式）=====
   enable_query_expansion?: boolean; // 是否启用查询扩展
   enable_rewrite?: boolean;         // 是否启用问题改写
+  enable_hyde?: boolean;            // 是否用假设答案（HyDE）的向量检索
+  enable_query_decomposition?: boolean; // 是否拆解复合问题为子查询
   rewrite_prompt_system?: string;   // 改写系统提示词
   rewrite_prompt_user?: string;     // 改写用户提示词模板
   fallback_strategy?: 'fixed' | 'model'; // 兜底策略
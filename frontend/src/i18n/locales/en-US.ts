@@ -468,6 +468,7 @@ export default {
     channelYuque: 'Yuque',
     channelGitLab: 'GitLab',
     channelIma: 'Tencent IMA',
+    channelConfluence: 'Confluence',
     channelUpload: 'Upload',
     channelManual: 'Manual',
     channelUrl: 'Web',
@@ -994,6 +995,8 @@ export default {
       contextTemplate: 'Context Template',
       contextTemplatePlaceholder: 'Custom context template...',
       enableQueryExpansion: 'Query Expansion',
+      enableHyDE: 'HyDE Retrieval',
+      enableQueryDecomposition: 'Query Decomposition',
       enableRewrite: 'Query Rewrite',
       queryUnderstandModel: 'Query Understand Model',
       queryUnderstandModelPlaceholder: 'Leave empty to reuse the main chat model',
@@ -5065,6 +5068,8 @@ export default {
       attachmentParsing: 'Parsing Attachment',
       imageAnalysis: 'Image Analysis',
       queryUnderstand: 'Understand Query',
+      queryHyDE: 'Draft Hypothetical Answer',
+      queryDecompose: 'Decompose Query',
       queryKnowledgeGraph: 'Knowledge Graph Query',
       readSkill: 'Read Skill',
       executeSkillScript: 'Execute Skill Script',
@@ -5147,6 +5152,10 @@ export default {
       attachmentParsingFailed: 'Attachment parsing failed',
       queryUnderstanding: 'Understanding query...',
       queryUnderstandDone: 'Query understood',
+      hydeRunning: 'Drafting hypothetical answer...',
+      hydeDone: 'Hypothetical answer drafted',
+      decomposing: 'Decomposing query...',
+      decomposeDone: 'Query decomposed',
       called: 'Called {name}',
       calledFailed: 'Failed to call {name}'
     },
@@ -5258,6 +5267,8 @@ export default {
       webFetchTopN: 'Maximum number of web pages to fetch after reranking',
       retrievalSection: 'Configure knowledge base retrieval, ranking, and FAQ priority strategy',
       queryExpansion: 'Automatically expand query terms to improve recall',
+      hyde: 'Draft a hypothetical answer first and search with its embedding instead of the question embedding; keyword search still uses the question',
+      queryDecomposition: 'Split compound questions into independent sub-queries searched in parallel; results are merged and reranked together',
       embeddingTopK: 'Maximum number of results from vector retrieval',
       keywordThreshold: 'Minimum relevance score for keyword retrieval',
       vectorThreshold: 'Minimum similarity score for vector retrieval',
@@ -5686,6 +5697,7 @@ export default {
       yuque: 'Yuque',
       rss: 'RSS / Atom Feed',
       ima: 'Tencent IMA',
+      confluence: 'Confluence',
       gitlab: 'GitLab'
     },
     connectorDesc: {
@@ -5697,6 +5709,7 @@ export default {
       yuque: 'Sync documents from Yuque knowledge bases',
       rss: 'Sync articles from RSS / Atom feeds',
       ima: 'Sync documents, notes and files from Tencent IMA knowledge bases (AI sessions and video parses are not supported)',
+      confluence: 'Sync pages and attachments from Confluence Cloud / Data Center spaces',
       gitlab: 'Sync files from GitLab projects'
     },
     drive: {
@@ -5719,6 +5732,12 @@ export default {
       apiToken: 'API Token',
       imaClientId: 'IMA ClientID',
       imaApiKey: 'IMA APIKey',
+      confluenceEmail: 'Email / Username',
+      confluencePat: 'Personal Access Token',
+      confluenceSiteUrl: 'Site URL',
+      confluenceCredentialHint: 'Cloud: Atlassian account email plus API token. Data Center: a personal access token (or username and password)',
+      confluenceDeployment: 'Deployment (optional)',
+      confluenceDeploymentHint: 'cloud or datacenter. When empty, *.atlassian.net is treated as Cloud and any other host as Data Center',
       baseUrl: 'Base URL (optional)',
       baseUrlHint: 'Leave empty to use the default public cloud address. For private/enterprise deployments or when accessing via reverse proxy, enter your custom address (e.g. https://api-proxy.example.com).',
       feedUrls: 'Feed URLs',
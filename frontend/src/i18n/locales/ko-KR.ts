@@ -698,6 +698,12 @@ export default {
       apiToken: 'API Token',
       imaClientId: 'IMA ClientID',
       imaApiKey: 'IMA APIKey',
+      confluenceEmail: '이메일 / 사용자 이름',
+      confluencePat: 'Personal Access Token',
+      confluenceSiteUrl: '사이트 주소',
+      confluenceCredentialHint: 'Cloud: Atlassian 계정 이메일과 API 토큰. Data Center: 개인 액세스 토큰(또는 사용자 이름과 비밀번호)',
+      confluenceDeployment: '배포 유형 (선택)',
+      confluenceDeploymentHint: 'cloud 또는 datacenter. 비워 두면 *.atlassian.net은 Cloud, 그 외 호스트는 Data Center로 간주합니다',
       baseUrl: 'Base URL',
       baseUrlHint: "비워두면 기본 퍼블릭 클라우드 주소가 사용됩니다. 프라이빗/엔터프라이즈 배포거나 리버스 프록시를 통해 액세스해야 하는 경우 사용자 정의 주소를 입력하세요 (예: https://api-proxy.example.com)",
       feedUrls: '피드 주소',
@@ -713,6 +719,7 @@ export default {
       notion: 'Notion에서 페이지 및 데이터베이스 동기화',
       yuque: '위큐 지식베이스에서 문서 동기화',
       ima: 'Tencent IMA 지식베이스에서 문서, 노트 및 파일 동기화 (AI 세션과 동영상 분석은 지원되지 않음)',
+      confluence: 'Confluence Cloud / Data Center 스페이스의 페이지와 첨부 파일 동기화',
       rss: 'RSS / Atom 피드에서 글 동기화',
       gitlab: 'GitLab 프로젝트의 파일 동기화'
     },
@@ -724,6 +731,7 @@ export default {
       notion: 'Notion',
       yuque: '위큐 (Yuque)',
       ima: 'Tencent IMA',
+      confluence: 'Confluence',
       rss: 'RSS / Atom 피드',
       gitlab: 'GitLab'
     },
@@ -1120,6 +1128,8 @@ export default {
       webFetchTopN: 'Rerank 후 전체 콘텐츠를 가져올 최대 웹 페이지 수',
       retrievalSection: '지식베이스 검색·순위 및 FAQ 우선 전략 설정',
       queryExpansion: '쿼리 용어를 자동으로 확장하여 재현율 향상',
+      hyde: '모델이 먼저 가상 답변을 작성하고 질문 대신 그 임베딩으로 검색합니다. 키워드 검색은 원래 질문을 사용합니다',
+      queryDecomposition: '여러 부분으로 된 질문을 독립적인 하위 쿼리로 나누어 병렬 검색하고, 결과를 합쳐 함께 재순위화합니다',
       embeddingTopK: '벡터 검색에서 반환되는 최대 결과 수',
       keywordThreshold: '키워드 검색의 최소 관련성 점수',
       vectorThreshold: '벡터 검색의 최소 유사도 점수',
@@ -1233,6 +1243,10 @@ export default {
       attachmentParsingFailed: '첨부 파일 파싱 실패',
       queryUnderstanding: '질문 이해 중...',
       queryUnderstandDone: '질문 이해 완료',
+      hydeRunning: '가상 답변 생성 중...',
+      hydeDone: '가상 답변 생성 완료',
+      decomposing: '질문 분해 중...',
+      decomposeDone: '질문 분해 완료',
       called: '{name} 호출 완료',
       calledFailed: '{name} 호출 실패'
     },
@@ -1302,6 +1316,8 @@ export default {
       attachmentParsing: '첨부 파일 파싱',
       imageAnalysis: '이미지 내용 분석',
       queryUnderstand: '질문 이해',
+      queryHyDE: '가상 답변 생성',
+      queryDecompose: '질문 분해',
       queryKnowledgeGraph: '지식 그래프 조회',
       readSkill: '스킬 읽기',
       executeSkillScript: '스킬 스크립트 실행',
@@ -5443,6 +5459,8 @@ export default {
       contextTemplate: '컨텍스트 템플릿',
       contextTemplatePlaceholder: '사용자 정의 컨텍스트 템플릿..',
       enableQueryExpansion: '쿼리 확장',
+      enableHyDE: 'HyDE 가상 문서 검색',
+      enableQueryDecomposition: '질문 분해',
       enableRewrite: '질문 재작성',
       queryUnderstandModel: '질문 이해 모델',
       queryUnderstandModelPlaceholder: '비워 두면 기본 대화 모델을 사용합니다',
@@ -5816,6 +5834,7 @@ export default {
     channelYuque: 'Yuque',
     channelGitLab: 'GitLab',
     channelIma: 'Tencent IMA',
+    channelConfluence: 'Confluence',
     channelUpload: '업로드',
     channelManual: '수동',
     channelUrl: '웹',
@@ -698,6 +698,12 @@ export default {
       apiToken: 'API Token',
       imaClientId: 'IMA ClientID',
       imaApiKey: 'IMA APIKey',
+      confluenceEmail: 'Email / имя пользователя',
+      confluencePat: 'Personal Access Token',
+      confluenceSiteUrl: 'Адрес сайта',
+      confluenceCredentialHint: 'Cloud: email учетной записи Atlassian и API-токен. Data Center: персональный токен доступа (или имя пользователя и пароль)',
+      confluenceDeployment: 'Тип развертывания (необязательно)',
+      confluenceDeploymentHint: 'cloud или datacenter. Если пусто, *.atlassian.net считается Cloud, остальные хосты — Data Center',
       baseUrl: 'Base URL',
       baseUrlHint: 'Оставьте пустым, чтобы использовать адрес общедоступного облака по умолчанию. Для частных/корпоративных развертываний или при доступе через обратный прокси введите ваш собственный адрес (например, https://api-proxy.example.com)',
       feedUrls: 'Адреса лент',
@@ -713,6 +719,7 @@ export default {
       notion: 'Синхронизация страниц и баз данных из Notion',
       yuque: 'Синхронизация документов из баз знаний Yuque',
       ima: 'Синхронизация документов, заметок и файлов из баз знаний Tencent IMA (ИИ-сессии и разбор видео не поддерживаются)',
+      confluence: 'Синхронизация страниц и вложений из пространств Confluence Cloud / Data Center',
       rss: 'Синхронизация статей из лент RSS / Atom',
       gitlab: 'Синхронизация файлов из проектов GitLab'
     },
@@ -724,6 +731,7 @@ export default {
       notion: 'Notion',
       yuque: 'Yuque (Юйцюэ)',
       ima: 'Tencent IMA',
+      confluence: 'Confluence',
       rss: 'RSS / Atom лента',
       gitlab: 'GitLab'
     },
@@ -1120,6 +1128,8 @@ export default {
       webFetchTopN: 'Максимальное количество страниц для загрузки полного содержимого после Rerank',
       retrievalSection: 'Настройка поиска, ранжирования и стратегии приоритета FAQ',
       queryExpansion: 'Автоматическое расширение поисковых запросов для улучшения полноты',
+      hyde: 'Сначала модель пишет гипотетический ответ, и векторный поиск идёт по его эмбеддингу вместо эмбеддинга вопроса; поиск по ключевым словам использует исходный вопрос',
+      queryDecomposition: 'Разбивать составные вопросы на независимые подзапросы, которые ищутся параллельно; результаты объединяются и переранжируются вместе',
       embeddingTopK: 'Максимальное количество результатов векторного поиска',
       keywordThreshold: 'Минимальная оценка релевантности для поиска по ключевым словам',
       vectorThreshold: 'Минимальная оценка сходства для векторного поиска',
@@ -1233,6 +1243,10 @@ export default {
       attachmentParsingFailed: 'Ошибка разбора вложений',
       queryUnderstanding: 'Анализ запроса...',
       queryUnderstandDone: 'Запрос понят',
+      hydeRunning: 'Составление гипотетического ответа...',
+      hydeDone: 'Гипотетический ответ составлен',
+      decomposing: 'Декомпозиция запроса...',
+      decomposeDone: 'Запрос разбит на части',
       called: 'Вызван {name}',
       calledFailed: 'Ошибка вызова {name}'
     },
@@ -1302,6 +1316,8 @@ export default {
       attachmentParsing: 'Разбор вложения',
       imageAnalysis: 'Анализ изображения',
       queryUnderstand: 'Понимание запроса',
+      queryHyDE: 'Гипотетический ответ',
+      queryDecompose: 'Декомпозиция запроса',
       queryKnowledgeGraph: 'Запрос графа знаний',
       readSkill: 'Чтение навыка',
       executeSkillScript: 'Выполнение скрипта навыка',
@@ -5443,6 +5459,8 @@ export default {
       contextTemplate: 'Context Template',
       contextTemplatePlaceholder: 'Custom context template...',
       enableQueryExpansion: 'Query Expansion',
+      enableHyDE: 'HyDE-поиск',
+      enableQueryDecomposition: 'Декомпозиция запроса',
       enableRewrite: 'Query Rewrite',
       queryUnderstandModel: 'Модель понимания запроса',
       queryUnderstandModelPlaceholder: 'Оставьте пустым, чтобы использовать основную модель чата',
@@ -5816,6 +5834,7 @@ export default {
     channelYuque: 'Yuque',
     channelGitLab: 'GitLab',
     channelIma: 'Tencent IMA',
+    channelConfluence: 'Confluence',
     channelUpload: 'Загрузка',
     channelManual: 'Вручную',
     channelUrl: 'Веб',
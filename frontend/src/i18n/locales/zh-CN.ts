@@ -700,6 +700,12 @@ export default {
       apiToken: 'API Token',
       imaClientId: 'IMA ClientID',
       imaApiKey: 'IMA APIKey',
+      confluenceEmail: '邮箱 / 用户名',
+      confluencePat: 'Personal Access Token',
+      confluenceSiteUrl: '站点地址',
+      confluenceCredentialHint: 'Cloud 填写 Atlassian 账号邮箱与 API Token；Data Center 填写 Personal Access Token（或用户名与密码）',
+      confluenceDeployment: '部署类型（可选）',
+      confluenceDeploymentHint: 'cloud 或 datacenter；留空时 *.atlassian.net 视为 Cloud，其余视为 Data Center',
       baseUrl: 'Base URL（可选）',
       baseUrlHint: '留空将使用默认公有云地址；如果是私有部署/企业内网部署，或需要通过反向代理访问，请填写自定义地址（例如 https://api-proxy.example.com）',
       feedUrls: '订阅源地址',
@@ -715,6 +721,7 @@ export default {
       notion: '同步 Notion 中的页面和数据库',
       yuque: '同步语雀知识库中的文档',
       ima: '同步腾讯 IMA 知识库中的文档、笔记与文件（暂不支持 AI 会话与视频解析）',
+      confluence: '同步 Confluence Cloud / Data Center 空间中的页面与附件',
       rss: '同步 RSS / Atom 订阅源中的文章',
       gitlab: '同步 GitLab 项目中的文件'
     },
@@ -726,6 +733,7 @@ export default {
       notion: 'Notion',
       yuque: '语雀',
       ima: '腾讯 IMA',
+      confluence: 'Confluence',
       rss: 'RSS / Atom 订阅',
       gitlab: 'GitLab'
     },
@@ -1122,6 +1130,8 @@ export default {
       webFetchTopN: 'Rerank 后最多抓取几个网页的完整内容',
       retrievalSection: '配置知识库检索召回、排序与 FAQ 优先策略（ReRank 模型见「模型配置」）',
       queryExpansion: '自动扩展查询词以提高召回率',
+      hyde: '先让模型起草一段假设答案，用其向量代替问题向量进行检索，关键词检索仍使用原问题',
+      queryDecomposition: '将包含多个子问题的提问拆成独立的子查询并行检索，结果合并后统一重排',
       embeddingTopK: '向量检索返回的最大结果数量',
       keywordThreshold: '关键词检索的最低相关性分数',
       vectorThreshold: '向量检索的最低相似度分数',
@@ -1235,6 +1245,10 @@ export default {
       attachmentParsingFailed: '附件解析失败',
       queryUnderstanding: '正在理解问题...',
       queryUnderstandDone: '已完成问题理解',
+      hydeRunning: '正在生成假设答案...',
+      hydeDone: '已生成假设答案',
+      decomposing: '正在拆解问题...',
+      decomposeDone: '已完成问题拆解',
       called: '调用 {name}',
       calledFailed: '调用 {name} 失败'
     },
@@ -1304,6 +1318,8 @@ export default {
       attachmentParsing: '解析附件',
       imageAnalysis: '查看图片内容',
       queryUnderstand: '理解问题',
+      queryHyDE: '生成假设答案',
+      queryDecompose: '拆解问题',
       queryKnowledgeGraph: '知识图谱查询',
       readSkill: '读取技能',
       executeSkillScript: '执行技能脚本',
@@ -5445,6 +5461,8 @@ export default {
       contextTemplate: '上下文模板',
       contextTemplatePlaceholder: '自定义上下文模板...',
       enableQueryExpansion: '查询扩展',
+      enableHyDE: 'HyDE 假设文档检索',
+      enableQueryDecomposition: '问题拆解',
       enableRewrite: '问题改写',
       queryUnderstandModel: '问题理解模型',
       queryUnderstandModelPlaceholder: '留空则复用主对话模型',
@@ -5818,6 +5836,7 @@ export default {
     channelYuque: '语雀',
     channelGitLab: 'GitLab',
     channelIma: '腾讯 IMA',
+    channelConfluence: 'Confluence',
     channelUpload: '上传',
     channelManual: '手动',
     channelUrl: '网页',
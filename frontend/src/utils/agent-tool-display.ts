@@ -182,6 +182,18 @@ export function getRagPipelineStepTitle(t: ComposerTranslation, event: RagPipeli
       : t('agentStream.toolStatus.queryUnderstandDone')
   }
 
+  if (toolName === 'query_hyde') {
+    return pending
+      ? t('agentStream.toolStatus.hydeRunning')
+      : t('agentStream.toolStatus.hydeDone')
+  }
+
+  if (toolName === 'query_decompose') {
+    return pending
+      ? t('agentStream.toolStatus.decomposing')
+      : t('agentStream.toolStatus.decomposeDone')
+  }
+
   if (toolName === 'knowledge_search' || toolName === 'search_knowledge') {
     const searchSource = getRetrievalSearchSource(event.arguments, event.tool_data)
     const labels = getRetrievalStatusKeys(searchSource, event.success === false)
@@ -30,7 +30,12 @@ export function getAgentToolIconName(
   if (toolName === 'todo_write') {
     return 'task'
   }
-  if (toolName === 'image_analysis' || toolName === 'query_understand') {
+  if (
+    toolName === 'image_analysis' ||
+    toolName === 'query_understand' ||
+    toolName === 'query_hyde' ||
+    toolName === 'query_decompose'
+  ) {
     return 'ai-search'
   }
   if (toolName === 'attachment_parsing') {
@@ -1,4 +1,9 @@
-export const RAG_PIPELINE_TOOL_NAMES = new Set(['query_understand', 'knowledge_search'])
+export const RAG_PIPELINE_TOOL_NAMES = new Set([
+  'query_understand',
+  'query_hyde',
+  'query_decompose',
+  'knowledge_search',
+])
 
 /** Retrieval tools that can produce citations. `search_knowledge` is the legacy alias. */
 export const RAG_RETRIEVAL_TOOL_NAMES = new Set(['knowledge_search', 'search_knowledge'])
@@ -289,9 +289,10 @@ func searchScopesFromTargets(searchTargets types.SearchTargets) []utils.SearchSc
 			continue
 		}
 		scopes = append(scopes, utils.SearchScope{
-			KnowledgeBaseID: target.KnowledgeBaseID,
-			KnowledgeIDs:    knowledgeIDs,
-			TagIDs:          tagIDs,
+			KnowledgeBaseID:     target.KnowledgeBaseID,
+			KnowledgeIDs:        knowledgeIDs,
+			TagIDs:              tagIDs,
+			ExcludeKnowledgeIDs: target.ExcludeKnowledgeIDs,
 		})
 	}
 	return scopes
@@ -36,6 +36,11 @@ const (
 	// and Local backends do not expose this tool to preserve their
 	// existing stateless security model.
 	ToolShellExec = "shell_exec"
+	// MCP resource tools (registered alongside the MCP tools when an enabled
+	// service advertises resources). Like MCP tool output, their results are
+	// external data and deliberately carry no model-handle policy.
+	ToolListMCPResources = "list_mcp_resources"
+	ToolReadMCPResource  = "read_mcp_resource"
 	// Wiki-related tools (only available when wiki KBs are in scope)
 	ToolWikiReadPage      = "wiki_read_page"
 	ToolWikiWritePage     = "wiki_write_page"
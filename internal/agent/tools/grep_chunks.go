@@ -306,6 +306,19 @@ func (t *GrepChunksTool) resolveGrepScope() (fullKBIDs, knowledgeIDs []string, t
 	return fullKBIDs, knowledgeIDs, tagTargets
 }
 
+// excludedKnowledgeIDs collects the documents that access lists hide from
+// the caller across every search target. Knowledge IDs are unique across
+// knowledge bases, so one NOT IN over the union is exact.
+func (t *GrepChunksTool) excludedKnowledgeIDs() []string {
+	var excluded []string
+	for _, target := range t.searchTargets {
+		if target != nil {
+			excluded = append(excluded, target.ExcludeKnowledgeIDs...)
+		}
+	}
+	return dedupNonEmptyStrings(excluded)
+}
+
 func dedupNonEmptyStrings(values []string) []string {
 	seen := make(map[string]bool, len(values))
 	out := make([]string, 0, len(values))
@@ -402,6 +415,9 @@ func (t *GrepChunksTool) searchChunks(
 	logger.Infof(ctx, "[Tool][GrepChunks] Scope: %d knowledge IDs, %d tag scopes, %d KBs",
 		len(knowledgeIDs), len(tagTargets), len(kbIDs))
 	query = query.Where(scopeSQL, scopeArgs...)
+	if excluded := t.excludedKnowledgeIDs(); len(excluded) > 0 {
+		query = query.Where("chunks.knowledge_id NOT IN ?", excluded)
+	}
 
 	// For MySQL/SQLite REGEXP case-insensitivity we rely on the column's default
 	// collation (utf8mb4_general_ci etc.) OR the driver's REGEXP implementation,
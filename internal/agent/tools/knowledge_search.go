@@ -72,6 +72,9 @@ Avoid:
 - queries (required): 1–5 semantic questions or conceptual statements.
   These should reflect the meaning or topic you want embeddings to capture.
 - knowledge_base_ids (optional): limit the search scope.
+- metadata_filter (optional): restrict results to documents whose custom metadata matches,
+  e.g. {"op":"and","filters":[{"op":"eq","key":"region","value":"EU"},{"op":"gt","key":"effective_date","value":"2025-01-01"}]}.
+  Operators: eq, in (with "values"), gt, gte, lt, lte (numbers or dates), and, or (with "filters").
 
 ## Output
 Returns chunks ranked by semantic similarity, reranked when applicable.  
@@ -96,6 +99,10 @@ Each chunk has a short cN source ID and belongs to a dN document ID. Results rep
       },
       "minItems": 0,
       "maxItems": 10
+    },
+    "metadata_filter": {
+      "type": "object",
+      "description": "Optional: filter over document custom metadata. Leaf: {op: eq|in|gt|gte|lt|lte, key, value|values}; group: {op: and|or, filters: [...]}"
     }
   },
   "required": ["queries"]
@@ -104,8 +111,9 @@ Each chunk has a short cN source ID and belongs to a dN document ID. Results rep
 
 // KnowledgeSearchInput defines the input parameters for knowledge search tool
 type KnowledgeSearchInput struct {
-	Queries          []string `json:"queries"`
-	KnowledgeBaseIDs []string `json:"knowledge_base_ids,omitempty"`
+	Queries          []string              `json:"queries"`
+	KnowledgeBaseIDs []string              `json:"knowledge_base_ids,omitempty"`
+	MetadataFilter   *types.MetadataFilter `json:"metadata_filter,omitempty"`
 }
 
 // searchResultWithMeta wraps search result with metadata about which query matched it
@@ -126,7 +134,8 @@ type KnowledgeSearchTool struct {
 	knowledgeBaseService interfaces.KnowledgeBaseService
 	knowledgeService     interfaces.KnowledgeService
 	chunkService         interfaces.ChunkService
-	searchTargets        types.SearchTargets // Pre-computed unified search targets
+	searchTargets        types.SearchTargets   // Pre-computed unified search targets
+	metadataFilter       *types.MetadataFilter // Request filter every search must satisfy
 	rerankModel          rerank.Reranker
 	chatModel            chat.Chat      // Optional chat model for LLM-based reranking
 	config               *config.Config // Global config for fallback values
@@ -158,6 +167,13 @@ func NewKnowledgeSearchTool(
 	}
 }
 
+// WithMetadataFilter sets the normalised metadata filter of the request.
+// Filters passed by the model narrow it further and can never widen it.
+func (t *KnowledgeSearchTool) WithMetadataFilter(filter *types.MetadataFilter) *KnowledgeSearchTool {
+	t.metadataFilter = filter
+	return t
+}
+
 // Execute executes the knowledge search tool
 func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage) (*types.ToolResult, error) {
 	logger.Infof(ctx, "[Tool][KnowledgeSearch] Execute started")
@@ -186,6 +202,21 @@ func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage)
 		logger.Infof(ctx, "[Tool][KnowledgeSearch] User specified %d knowledge bases: %v", len(userSpecifiedKBs), userSpecifiedKBs)
 	}
 
+	metadataFilter, err := input.MetadataFilter.Normalize()
+	if err != nil {
+		return &types.ToolResult{Success: false, Error: err.Error()}, err
+	}
+	if t.metadataFilter != nil {
+		if metadataFilter == nil {
+			metadataFilter = t.metadataFilter
+		} else {
+			metadataFilter = &types.MetadataFilter{
+				Op:      types.MetadataFilterAnd,
+				Filters: []*types.MetadataFilter{t.metadataFilter, metadataFilter},
+			}
+		}
+	}
+
 	// Use pre-computed search targets, optionally filtered by user-specified KBs
 	searchTargets := t.searchTargets
 	if len(userSpecifiedKBs) > 0 {
@@ -279,7 +310,7 @@ func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage)
 	kbTypeMap := t.getKnowledgeBaseTypes(ctx, kbIDs)
 
 	allResults := t.concurrentSearchByTargets(ctx, queries, searchTargets,
-		topK, vectorThreshold, keywordThreshold, kbTypeMap)
+		topK, vectorThreshold, keywordThreshold, metadataFilter, kbTypeMap)
 	logger.Infof(ctx, "[Tool][KnowledgeSearch] Concurrent search completed: %d raw results", len(allResults))
 
 	// Note: HybridSearch now uses RRF (Reciprocal Rank Fusion) which produces normalized scores
@@ -436,6 +467,7 @@ func (t *KnowledgeSearchTool) concurrentSearchByTargets(
 	searchTargets types.SearchTargets,
 	topK int,
 	vectorThreshold, keywordThreshold float64,
+	metadataFilter *types.MetadataFilter,
 	kbTypeMap map[string]string,
 ) []*searchResultWithMeta {
 	// Batch-fetch KB records for embedding model grouping
@@ -545,6 +577,7 @@ func (t *KnowledgeSearchTool) concurrentSearchByTargets(
 							MatchCount:       topK,
 							VectorThreshold:  vectorThreshold,
 							KeywordThreshold: keywordThreshold,
+							MetadataFilter:   metadataFilter,
 						}
 						kbResults, err := t.knowledgeBaseService.HybridSearch(ctx, fullKBIDs[0], searchParams)
 						if err != nil {
@@ -584,6 +617,7 @@ func (t *KnowledgeSearchTool) concurrentSearchByTargets(
 							KnowledgeIDs:     st.KnowledgeIDs,
 							TagIDs:           st.TagIDs,
 							ScopeTagIDs:      st.ScopeTagIDs,
+							MetadataFilter:   metadataFilter,
 						}
 						kbResults, err := t.knowledgeBaseService.HybridSearch(ctx, st.KnowledgeBaseID, searchParams)
 						if err != nil {
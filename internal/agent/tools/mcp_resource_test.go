@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/mcp"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMCPResourceClient struct {
+	resources []*types.MCPResource
+	contents  map[string][]mcp.ResourceContent
+}
+
+func (f *fakeMCPResourceClient) ListResources(context.Context) ([]*types.MCPResource, error) {
+	return f.resources, nil
+}
+
+func (f *fakeMCPResourceClient) ReadResource(_ context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	contents, ok := f.contents[uri]
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", uri)
+	}
+	return &mcp.ReadResourceResult{Contents: contents}, nil
+}
+
+func newFakeMCPResourceSource(clients map[string]*fakeMCPResourceClient) *mcpResourceSource {
+	source := &mcpResourceSource{}
+	for name := range clients {
+		source.services = append(source.services, &types.MCPService{ID: "id-" + name, Name: name, Enabled: true})
+	}
+	source.connect = func(_ context.Context, service *types.MCPService) (mcpResourceClient, error) {
+		return clients[service.Name], nil
+	}
+	return source
+}
+
+func TestReadMCPResourceReturnsTextWithUntrustedPrefix(t *testing.T) {
+	source := newFakeMCPResourceSource(map[string]*fakeMCPResourceClient{
+		"crm": {contents: map[string][]mcp.ResourceContent{
+			"crm://accounts/acme": {{URI: "crm://accounts/acme", MimeType: "application/json", Text: `{"owner":"alice"}`}},
+		}},
+	})
+	tool := &ReadMCPResourceTool{BaseTool: readMCPResourceTool, source: source}
+
+	result, err := tool.Execute(context.Background(), json.RawMessage(`{"service":"CRM","uri":"crm://accounts/acme"}`))
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Error)
+	assert.True(t, strings.HasPrefix(result.Output, `[MCP resource from "crm"`))
+	assert.Contains(t, result.Output, `{"owner":"alice"}`)
+
+	result, err = tool.Execute(context.Background(), json.RawMessage(`{"service":"erp","uri":"crm://accounts/acme"}`))
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "Available services: crm")
+}
+
+// A resource made of several parts must degrade by trimming its biggest
+// parts, keeping every part's tags intact, rather than by the registry's
+// head+tail cut that drops the parts in the middle.
+func TestRenderMCPResourceContentsFitsBudget(t *testing.T) {
+	contents := []mcp.ResourceContent{
+		{URI: "doc://1", Text: strings.Repeat("a", 5000)},
+		{URI: "doc://2", Text: "short"},
+		{URI: "doc://3", Text: strings.Repeat("c", 5000)},
+	}
+	output, _, truncated := renderMCPResourceContents(contents, 3000)
+
+	assert.True(t, truncated)
+	assert.LessOrEqual(t, utf8.RuneCountInString(output), 3000)
+	assert.Equal(t, 3, strings.Count(output, "</mcp_resource_content>"))
+	assert.Contains(t, output, "short")
+}
+
+func TestRenderMCPResourceContentsDescribesBlobs(t *testing.T) {
+	contents := []mcp.ResourceContent{
+		{URI: "img://logo", MimeType: "image/png", Blob: "aGVsbG8="},
+		{URI: "bin://dump", MimeType: "application/octet-stream", Blob: "aGVsbG8="},
+	}
+	output, images, truncated := renderMCPResourceContents(contents, DefaultMaxToolOutput)
+
+	assert.False(t, truncated)
+	assert.Equal(t, []string{"data:image/png;base64,aGVsbG8="}, images)
+	assert.Contains(t, output, "[Binary content: application/octet-stream, 6 bytes]")
+	assert.NotContains(t, output, "aGVsbG8=")
+}
+
+func TestListMCPResourcesStopsAtWholeEntries(t *testing.T) {
+	var resources []*types.MCPResource
+	for i := 0; i < 200; i++ {
+		resources = append(resources, &types.MCPResource{
+			URI:  fmt.Sprintf("kb://pages/%03d", i),
+			Name: fmt.Sprintf("Page %d", i),
+		})
+	}
+	source := newFakeMCPResourceSource(map[string]*fakeMCPResourceClient{"wiki": {resources: resources}})
+	tool := &ListMCPResourcesTool{BaseTool: listMCPResourcesTool, source: source}
+
+	result, err := tool.Execute(WithOutputBudget(context.Background(), 1500), json.RawMessage(`{}`))
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	assert.LessOrEqual(t, utf8.RuneCountInString(result.Output), 1500)
+	assert.Contains(t, result.Output, "- kb://pages/000 | Page 0\n")
+	assert.Contains(t, result.Output, `more resources omitted to fit the output limit; call again with service="wiki"`)
+	assert.True(t, strings.HasSuffix(result.Output, "</mcp_resources>"))
+	assert.Equal(t, 200, result.Data["resource_count"])
+}
+
+func TestMCPToolNamesByServiceIDIncludesResourceTools(t *testing.T) {
+	registry := NewToolRegistry()
+	source := newFakeMCPResourceSource(map[string]*fakeMCPResourceClient{"wiki": {}})
+	registry.RegisterTool(&ListMCPResourcesTool{BaseTool: listMCPResourcesTool, source: source})
+	registry.RegisterTool(&ReadMCPResourceTool{BaseTool: readMCPResourceTool, source: source})
+
+	byService := MCPToolNamesByServiceID(registry)
+	assert.Equal(t, []string{ToolListMCPResources, ToolReadMCPResource}, byService["id-wiki"])
+}
@@ -405,8 +405,9 @@ func sanitizeName(name string) string {
 	return result.String()
 }
 
-// RegisterMCPTools registers MCP tools from given services. It returns the
-// number of tools registered. oauthSess enables in-conversation OAuth when tool
+// RegisterMCPTools registers MCP tools from given services, plus the resource
+// tools when any of them publishes resources. It returns the number of tools
+// registered. oauthSess enables in-conversation OAuth when tool
 // discovery requires authorization.
 func RegisterMCPTools(
 	ctx context.Context,
@@ -438,6 +439,7 @@ func RegisterMCPTools(
 		authWaitTimeoutSeconds = oauthSess.AuthWaitTimeoutSeconds
 	}
 	regOAuth := oauthSessionForRegistration(ctx, oauthSess, listToolsTimeout)
+	var resourceServices []*types.MCPService
 	for _, service := range services {
 		if !service.Enabled {
 			continue
@@ -462,6 +464,16 @@ func RegisterMCPTools(
 			}()
 		}
 
+		// Resource-only servers have no tools to list; their data is reached
+		// through the resource tools registered below.
+		caps := client.ServerCapabilities()
+		if caps.Resources != nil {
+			resourceServices = append(resourceServices, service)
+			if caps.Tools == nil {
+				continue
+			}
+		}
+
 		// List tools from the service with timeout.
 		// If the cached connection is stale, disconnect and retry once.
 		listCtx, cancel := context.WithTimeout(ctx, listToolsTimeout)
@@ -511,10 +523,18 @@ func RegisterMCPTools(
 		}
 	}
 
+	if len(resourceServices) > 0 {
+		registry.RegisterTool(NewListMCPResourcesTool(resourceServices, mcpManager))
+		registry.RegisterTool(NewReadMCPResourceTool(resourceServices, mcpManager))
+		registered += 2
+		logger.GetLogger(ctx).Infof("Registered MCP resource tools for %d service(s)", len(resourceServices))
+	}
+
 	return registered, nil
 }
 
 // MCPToolNamesByServiceID returns registered MCP tool names grouped by service ID.
+// The resource tools are listed under every service that publishes resources.
 func MCPToolNamesByServiceID(registry *ToolRegistry) map[string][]string {
 	if registry == nil {
 		return nil
@@ -525,12 +545,20 @@ func MCPToolNamesByServiceID(registry *ToolRegistry) map[string][]string {
 		if err != nil {
 			continue
 		}
-		mcpTool, ok := tool.(*MCPTool)
-		if !ok || mcpTool.service == nil {
-			continue
+		var services []*types.MCPService
+		switch t := tool.(type) {
+		case *MCPTool:
+			services = []*types.MCPService{t.service}
+		case *ListMCPResourcesTool:
+			services = t.source.services
+		case *ReadMCPResourceTool:
+			services = t.source.services
+		}
+		for _, service := range services {
+			if service != nil {
+				out[service.ID] = append(out[service.ID], name)
+			}
 		}
-		sid := mcpTool.service.ID
-		out[sid] = append(out[sid], name)
 	}
 	for sid := range out {
 		sort.Strings(out[sid])
@@ -38,7 +38,13 @@ Explores relationships between entities in knowledge bases that have graph extra
 
 ## Parameters
 - **knowledge_base_ids** (required): Array of short bN knowledge base IDs (1-10). Only KBs with graph extraction configured will be effective.
-- **query** (required): Query content - can be entity name, relationship query, or concept search.
+- **query**: Query content - can be entity name, relationship query, or concept search. Required unless a graph traversal below is requested.
+- **entities**: Entity names whose multi-hop neighbourhood to expand.
+- **source_entity** / **target_entity**: Find the shortest path connecting two entities (e.g. "how is supplier X connected to incident Y"). Both must be given.
+- **max_hops**: Traversal depth for entities / path (1-4, default 2).
+- **relation_types**: Only walk these relationship types.
+
+Traversal results list each hop with the chunks it was extracted from (chunk_id + excerpt), so a path can be cited as evidence.
 
 ## Graph Configuration
 Knowledge graph must be pre-configured in knowledge bases:
@@ -62,7 +68,12 @@ If KB is not configured with graph, tool will return regular search results.
 // QueryKnowledgeGraphInput defines the input parameters for query knowledge graph tool
 type QueryKnowledgeGraphInput struct {
 	KnowledgeBaseIDs []string `json:"knowledge_base_ids" jsonschema:"Array of short bN knowledge base IDs to query"`
-	Query            string   `json:"query" jsonschema:"Query content (entity name or query text)"`
+	Query            string   `json:"query,omitempty" jsonschema:"Query content (entity name or query text)"`
+	Entities         []string `json:"entities,omitempty" jsonschema:"Entity names whose multi-hop neighbourhood to expand"`
+	SourceEntity     string   `json:"source_entity,omitempty" jsonschema:"Start entity of a path query (requires target_entity)"`
+	TargetEntity     string   `json:"target_entity,omitempty" jsonschema:"End entity of a path query (requires source_entity)"`
+	MaxHops          int      `json:"max_hops,omitempty" jsonschema:"Maximum traversal depth (1-4, default 2)"`
+	RelationTypes    []string `json:"relation_types,omitempty" jsonschema:"Only traverse these relationship types"`
 }
 
 // QueryKnowledgeGraphTool queries the knowledge graph for entities and relationships
@@ -72,6 +83,8 @@ type QueryKnowledgeGraphTool struct {
 	scopeKnowledgeService interfaces.KnowledgeService
 	searchTargets         types.SearchTargets
 	scopeEnforced         bool
+	graphRepo             interfaces.RetrieveGraphRepository
+	chunkRepo             interfaces.ChunkRepository
 }
 
 // WithKnowledgeScope enables document/tag-level result filtering for Agent
@@ -135,13 +148,35 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 	}
 
 	query := input.Query
-	if query == "" {
+	if query == "" && !input.wantsTraversal() {
 		return &types.ToolResult{
 			Success: false,
 			Error:   "query is required",
 		}, fmt.Errorf("invalid query")
 	}
 
+	var traversalOutput string
+	var traversalData map[string]interface{}
+	if input.wantsTraversal() {
+		var err error
+		traversalOutput, traversalData, err = t.executeGraphTraversal(ctx, input)
+		if err != nil {
+			return &types.ToolResult{Success: false, Error: err.Error()}, err
+		}
+		if query == "" {
+			return &types.ToolResult{
+				Success: true,
+				Output:  "=== Knowledge Graph Traversal ===\n\n" + traversalOutput,
+				Data: map[string]interface{}{
+					"knowledge_base_ids": input.KnowledgeBaseIDs,
+					"graph_traversal":    traversalData,
+					"graph_data":         traversalData["entity_graph"],
+					"display_type":       "graph_query_results",
+				},
+			}, nil
+		}
+	}
+
 	// Concurrently query all knowledge bases
 	type graphQueryResult struct {
 		kbID    string
@@ -174,7 +209,7 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 			}
 
 			// Check if graph extraction is enabled
-			if kb.ExtractConfig == nil || (len(kb.ExtractConfig.Nodes) == 0 && len(kb.ExtractConfig.Relations) == 0) {
+			if !graphExtractionConfigured(kb) {
 				mu.Lock()
 				kbResults[id] = &graphQueryResult{kbID: id, err: fmt.Errorf("graph extraction not configured")}
 				mu.Unlock()
@@ -247,13 +282,14 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 	if len(allResults) == 0 {
 		return &types.ToolResult{
 			Success: true,
-			Output:  "No relevant graph information found.",
+			Output:  traversalOutput + "No relevant graph information found.",
 			Data: map[string]interface{}{
 				"knowledge_base_ids": input.KnowledgeBaseIDs,
 				"query":              query,
 				"results":            []interface{}{},
 				"graph_configs":      graphConfigsToData(graphConfigs),
 				"graph_config":       aggregateGraphConfig(graphConfigs),
+				"graph_traversal":    traversalData,
 				"errors":             errors,
 			},
 		}, nil
@@ -264,6 +300,7 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 	output += fmt.Sprintf("📊 Query: %s\n", query)
 	output += fmt.Sprintf("🎯 Target Knowledge Bases: %v\n", input.KnowledgeBaseIDs)
 	output += fmt.Sprintf("✓ Found %d relevant results (deduplicated)\n\n", len(allResults))
+	output += traversalOutput
 
 	if len(errors) > 0 {
 		output += "=== ⚠️ Partial Failures ===\n"
@@ -359,7 +396,9 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 	if !hasGraphConfig {
 		output += "- ⚠️ Configure graph extraction for more precise entity-relationship results\n"
 	}
-	output += "- ⏳ Full graph query language (Cypher) support is under development\n"
+	if !input.wantsTraversal() {
+		output += "- ✓ Pass entities or source_entity/target_entity to walk multi-hop relationships\n"
+	}
 
 	// Build structured graph data for frontend visualization
 	graphData := buildGraphVisualizationData(allResults)
@@ -376,6 +415,7 @@ func (t *QueryKnowledgeGraphTool) Execute(ctx context.Context, args json.RawMess
 			"graph_configs":      graphConfigsToData(graphConfigs),
 			"graph_config":       aggregateGraphConfig(graphConfigs),
 			"graph_data":         graphData,
+			"graph_traversal":    traversalData,
 			"has_graph_config":   hasGraphConfig,
 			"errors":             errors,
 			"display_type":       "graph_query_results",
@@ -98,6 +98,24 @@ func (s *stubKnowledgeBaseService) ProcessKBDelete(context.Context, *asynq.Task)
 	return nil
 }
 
+func (s *stubKnowledgeBaseService) SetEmbeddingModel(context.Context, string, string) (*types.EmbeddingMigration, error) {
+	return nil, nil
+}
+
+func (s *stubKnowledgeBaseService) ProcessEmbeddingMigration(context.Context, *asynq.Task) error {
+	return nil
+}
+
+func (s *stubKnowledgeBaseService) MigrateVectorStore(
+	context.Context, string, string,
+) (*types.VectorStoreMigration, error) {
+	return nil, nil
+}
+
+func (s *stubKnowledgeBaseService) ProcessVectorStoreMigration(context.Context, *asynq.Task) error {
+	return nil
+}
+
 func TestQueryKnowledgeGraph_ReportsConfiguredEntityAndRelationTypes(t *testing.T) {
 	tool := NewQueryKnowledgeGraphTool(&stubKnowledgeBaseService{
 		kb: &types.KnowledgeBase{
@@ -182,3 +200,109 @@ func TestQueryKnowledgeGraph_ReportsConfiguredEntityAndRelationTypes(t *testing.
 	assert.ElementsMatch(t, []string{"合同", "审批流程", "法务部门"}, graphConfig["nodes"])
 	assert.ElementsMatch(t, []string{"属于", "审批", "管理"}, graphConfig["relations"])
 }
+
+type stubGraphRepository struct {
+	interfaces.RetrieveGraphRepository
+	path *types.GraphPath
+}
+
+func (s *stubGraphRepository) ShortestPath(
+	context.Context, types.NameSpace, string, string, types.GraphTraversalOptions,
+) (*types.GraphPath, error) {
+	return s.path, nil
+}
+
+type stubGraphChunkRepository struct {
+	interfaces.ChunkRepository
+	chunks []*types.Chunk
+}
+
+func (s *stubGraphChunkRepository) ListChunksByID(_ context.Context, _ uint64, ids []string) ([]*types.Chunk, error) {
+	var out []*types.Chunk
+	for _, c := range s.chunks {
+		for _, id := range ids {
+			if c.ID == id {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+func newGraphTraversalTool(searchTargets ...types.SearchTargets) *QueryKnowledgeGraphTool {
+	kbService := &stubKnowledgeBaseService{kb: &types.KnowledgeBase{
+		ID:            "kb-1",
+		ExtractConfig: &types.ExtractConfig{Enabled: true, Relations: []*types.GraphRelation{{Type: "supplies"}}},
+	}}
+	graphRepo := &stubGraphRepository{path: &types.GraphPath{
+		Nodes: []*types.GraphNode{{Name: "Supplier X"}, {Name: "Part P"}, {Name: "Incident Y"}},
+		Relations: []*types.GraphRelation{
+			{Node1: "Supplier X", Type: "supplies", Node2: "Part P", Chunks: []string{"c1"}},
+			{Node1: "Incident Y", Type: "caused_by", Node2: "Part P", Chunks: []string{"c2"}},
+		},
+	}}
+	chunkRepo := &stubGraphChunkRepository{chunks: []*types.Chunk{
+		{ID: "c1", KnowledgeID: "doc-1", KnowledgeBaseID: "kb-1", Content: "Supplier X supplies part P."},
+		{ID: "c2", KnowledgeID: "doc-2", KnowledgeBaseID: "kb-1", Content: "Incident Y was caused by a faulty part P."},
+	}}
+	return NewQueryKnowledgeGraphTool(kbService, searchTargets...).WithGraphTraversal(graphRepo, chunkRepo)
+}
+
+func TestQueryKnowledgeGraph_PathQueryReturnsEdgeEvidence(t *testing.T) {
+	tool := newGraphTraversalTool()
+	args, err := json.Marshal(QueryKnowledgeGraphInput{
+		KnowledgeBaseIDs: []string{"kb-1"},
+		SourceEntity:     "Supplier X",
+		TargetEntity:     "Incident Y",
+	})
+	require.NoError(t, err)
+
+	result, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	assert.Contains(t, result.Output, "Supplier X → Part P → Incident Y (2 hops)")
+	assert.Contains(t, result.Output, "Incident Y -[caused_by]-> Part P")
+	assert.Contains(t, result.Output, "chunk_id: c2")
+	assert.Contains(t, result.Output, "faulty part P")
+
+	traversal, ok := result.Data["graph_traversal"].(map[string]interface{})
+	require.True(t, ok)
+	paths, ok := traversal["graph_paths"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, paths, 1)
+	assert.Equal(t, 2, paths[0]["hops"])
+}
+
+func TestQueryKnowledgeGraph_PathHiddenWhenHopOutsideScope(t *testing.T) {
+	tool := newGraphTraversalTool(types.SearchTargets{{
+		Type:            types.SearchTargetTypeKnowledge,
+		KnowledgeBaseID: "kb-1",
+		KnowledgeIDs:    []string{"doc-1"},
+	}})
+	args, err := json.Marshal(QueryKnowledgeGraphInput{
+		KnowledgeBaseIDs: []string{"kb-1"},
+		SourceEntity:     "Supplier X",
+		TargetEntity:     "Incident Y",
+	})
+	require.NoError(t, err)
+
+	result, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	assert.Contains(t, result.Output, "No connecting path found")
+	assert.NotContains(t, result.Output, "faulty part P")
+}
+
+func TestQueryKnowledgeGraph_PathQueryRequiresBothEndpoints(t *testing.T) {
+	tool := newGraphTraversalTool()
+	args, err := json.Marshal(QueryKnowledgeGraphInput{
+		KnowledgeBaseIDs: []string{"kb-1"},
+		SourceEntity:     "Supplier X",
+	})
+	require.NoError(t, err)
+
+	result, err := tool.Execute(context.Background(), args)
+	require.Error(t, err)
+	assert.False(t, result.Success)
+}
@@ -0,0 +1,419 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const (
+	// graphEvidencePreviewRunes bounds the chunk excerpt shown per edge.
+	graphEvidencePreviewRunes = 200
+	// graphMaxListedRelations bounds the neighbourhood edges printed per KB;
+	// the structured data still carries every edge.
+	graphMaxListedRelations = 50
+)
+
+// WithGraphTraversal enables entity expansion and path queries. chunkRepo
+// resolves the chunks backing each edge so paths can be explained.
+func (t *QueryKnowledgeGraphTool) WithGraphTraversal(
+	graphRepo interfaces.RetrieveGraphRepository,
+	chunkRepo interfaces.ChunkRepository,
+) *QueryKnowledgeGraphTool {
+	t.graphRepo = graphRepo
+	t.chunkRepo = chunkRepo
+	return t
+}
+
+// wantsTraversal reports whether the call asks for entity expansion or a path.
+func (in QueryKnowledgeGraphInput) wantsTraversal() bool {
+	return len(in.Entities) > 0 || in.SourceEntity != "" || in.TargetEntity != ""
+}
+
+// kbGraphTraversal is the traversal outcome for one knowledge base.
+type kbGraphTraversal struct {
+	kbID         string
+	path         *types.GraphPath
+	neighborhood *types.GraphData
+	evidence     map[string]*types.Chunk
+	err          error
+}
+
+// executeGraphTraversal runs the requested path / neighbourhood queries on
+// every KB and renders them. Edges are only kept when at least one backing
+// chunk is visible in the Agent's scope, so a path never leaks through
+// documents the caller cannot read.
+func (t *QueryKnowledgeGraphTool) executeGraphTraversal(
+	ctx context.Context, input QueryKnowledgeGraphInput,
+) (string, map[string]interface{}, error) {
+	if (input.SourceEntity == "") != (input.TargetEntity == "") {
+		return "", nil, fmt.Errorf("source_entity and target_entity must be provided together")
+	}
+	if t.graphRepo == nil {
+		return "", nil, fmt.Errorf("graph traversal is not available in this deployment")
+	}
+	opts := types.GraphTraversalOptions{
+		MaxHops:       input.MaxHops,
+		RelationTypes: dedupNonEmptyStrings(input.RelationTypes),
+	}.Normalize()
+
+	results := make([]*kbGraphTraversal, 0, len(input.KnowledgeBaseIDs))
+	for _, kbID := range input.KnowledgeBaseIDs {
+		results = append(results, t.traverseKnowledgeBase(ctx, kbID, input, opts))
+	}
+
+	var b strings.Builder
+	paths := make([]map[string]interface{}, 0)
+	neighborhoods := make([]map[string]interface{}, 0)
+	var errs []string
+	vis := newGraphEntityVisualization()
+
+	if input.SourceEntity != "" {
+		b.WriteString("=== 🧭 Graph Path ===\n\n")
+		b.WriteString(fmt.Sprintf("From \"%s\" to \"%s\" (max %d hops", input.SourceEntity, input.TargetEntity, opts.MaxHops))
+		if len(opts.RelationTypes) > 0 {
+			b.WriteString(fmt.Sprintf(", relations: %v", opts.RelationTypes))
+		}
+		b.WriteString(")\n\n")
+		found := false
+		for _, r := range results {
+			if r.err != nil || r.path == nil {
+				continue
+			}
+			found = true
+			b.WriteString(fmt.Sprintf("Knowledge Base [%s]: %s (%d hops)\n", r.kbID, formatGraphPathNodes(r.path), r.path.Hops()))
+			for i, rel := range r.path.Relations {
+				b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, formatGraphRelation(rel)))
+				writeGraphEvidence(&b, rel, r.evidence, "     ")
+			}
+			b.WriteString("\n")
+			paths = append(paths, graphPathData(r.kbID, r.path, r.evidence))
+			vis.addPath(r.path)
+		}
+		if !found {
+			b.WriteString("No connecting path found within the hop limit.\n\n")
+		}
+	}
+
+	if len(input.Entities) > 0 {
+		b.WriteString(fmt.Sprintf("=== 🕸️ Entity Neighbourhood (max %d hops) ===\n\n", opts.MaxHops))
+		for _, r := range results {
+			if r.err != nil || r.neighborhood == nil {
+				continue
+			}
+			g := r.neighborhood
+			b.WriteString(fmt.Sprintf("Knowledge Base [%s]: %d entities, %d relations\n", r.kbID, len(g.Node), len(g.Relation)))
+			for i, rel := range g.Relation {
+				if i == graphMaxListedRelations {
+					b.WriteString(fmt.Sprintf("  … %d more relations in data\n", len(g.Relation)-i))
+					break
+				}
+				b.WriteString(fmt.Sprintf("  - %s  [chunks: %s]\n", formatGraphRelation(rel), strings.Join(rel.Chunks, ", ")))
+			}
+			b.WriteString("\n")
+			neighborhoods = append(neighborhoods, graphNeighborhoodData(r.kbID, g, r.evidence))
+			vis.addGraph(g)
+		}
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Sprintf("KB %s: %v", r.kbID, r.err))
+		}
+	}
+	if len(errs) > 0 {
+		b.WriteString("=== ⚠️ Graph Traversal Failures ===\n")
+		for _, msg := range errs {
+			b.WriteString(fmt.Sprintf("  - %s\n", msg))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String(), map[string]interface{}{
+		"graph_paths":         paths,
+		"graph_neighborhoods": neighborhoods,
+		"max_hops":            opts.MaxHops,
+		"relation_types":      opts.RelationTypes,
+		"traversal_errors":    errs,
+		"entity_graph":        vis.data(),
+	}, nil
+}
+
+func (t *QueryKnowledgeGraphTool) traverseKnowledgeBase(
+	ctx context.Context, kbID string, input QueryKnowledgeGraphInput, opts types.GraphTraversalOptions,
+) *kbGraphTraversal {
+	out := &kbGraphTraversal{kbID: kbID}
+	kb, err := t.knowledgeService.GetKnowledgeBaseByIDOnly(ctx, kbID)
+	if err != nil {
+		out.err = fmt.Errorf("failed to get knowledge base: %v", err)
+		return out
+	}
+	if !graphExtractionConfigured(kb) {
+		out.err = fmt.Errorf("graph extraction not configured")
+		return out
+	}
+	namespace := types.NameSpace{KnowledgeBase: kbID}
+	if input.SourceEntity != "" {
+		out.path, err = t.graphRepo.ShortestPath(ctx, namespace, input.SourceEntity, input.TargetEntity, opts)
+		if err != nil {
+			out.err = fmt.Errorf("path query failed: %v", err)
+			return out
+		}
+	}
+	if len(input.Entities) > 0 {
+		out.neighborhood, err = t.graphRepo.ExpandNode(ctx, namespace, input.Entities, opts)
+		if err != nil {
+			out.err = fmt.Errorf("neighbourhood query failed: %v", err)
+			return out
+		}
+	}
+
+	out.evidence, err = t.loadGraphEvidence(ctx, kb, out)
+	if err != nil {
+		out.err = err
+		out.path, out.neighborhood = nil, nil
+		return out
+	}
+	if out.path != nil && !filterGraphRelations(out.path.Relations, out.evidence) {
+		// Some hop is only backed by documents outside the scope.
+		out.path = nil
+	}
+	if out.neighborhood != nil {
+		out.neighborhood = filterGraphNeighborhood(out.neighborhood, out.evidence)
+	}
+	return out
+}
+
+// loadGraphEvidence fetches every chunk backing the traversal's edges and
+// keeps only those inside this KB and the Agent's search scope.
+func (t *QueryKnowledgeGraphTool) loadGraphEvidence(
+	ctx context.Context, kb *types.KnowledgeBase, r *kbGraphTraversal,
+) (map[string]*types.Chunk, error) {
+	var ids []string
+	if r.path != nil {
+		for _, rel := range r.path.Relations {
+			ids = append(ids, rel.Chunks...)
+		}
+	}
+	if r.neighborhood != nil {
+		for _, rel := range r.neighborhood.Relation {
+			ids = append(ids, rel.Chunks...)
+		}
+	}
+	ids = dedupNonEmptyStrings(ids)
+	evidence := make(map[string]*types.Chunk, len(ids))
+	if len(ids) == 0 || t.chunkRepo == nil {
+		return evidence, nil
+	}
+	chunks, err := t.chunkRepo.ListChunksByID(ctx, kb.TenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edge evidence: %v", err)
+	}
+	candidates := make([]*types.SearchResult, 0, len(chunks))
+	byID := make(map[string]*types.Chunk, len(chunks))
+	for _, chunk := range chunks {
+		if chunk == nil || chunk.KnowledgeBaseID != kb.ID {
+			continue
+		}
+		byID[chunk.ID] = chunk
+		candidates = append(candidates, &types.SearchResult{
+			ID:              chunk.ID,
+			KnowledgeID:     chunk.KnowledgeID,
+			KnowledgeBaseID: chunk.KnowledgeBaseID,
+		})
+	}
+	if t.scopeEnforced {
+		candidates, err = filterSearchResultsInSearchTargets(
+			ctx, t.searchTargets, kb.ID, candidates, t.scopeKnowledgeService,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range candidates {
+		evidence[c.ID] = byID[c.ID]
+	}
+	return evidence, nil
+}
+
+// filterGraphRelations narrows each relation's chunks to the visible
+// evidence and reports whether every relation kept at least one chunk.
+func filterGraphRelations(relations []*types.GraphRelation, evidence map[string]*types.Chunk) bool {
+	allBacked := true
+	for _, rel := range relations {
+		visible := make([]string, 0, len(rel.Chunks))
+		for _, id := range rel.Chunks {
+			if evidence[id] != nil {
+				visible = append(visible, id)
+			}
+		}
+		rel.Chunks = visible
+		if len(visible) == 0 {
+			allBacked = false
+		}
+	}
+	return allBacked
+}
+
+// filterGraphNeighborhood drops edges without visible evidence and the
+// entities only reachable through them.
+func filterGraphNeighborhood(g *types.GraphData, evidence map[string]*types.Chunk) *types.GraphData {
+	filterGraphRelations(g.Relation, evidence)
+	out := &types.GraphData{}
+	keep := make(map[string]bool)
+	for _, rel := range g.Relation {
+		if len(rel.Chunks) == 0 {
+			continue
+		}
+		out.Relation = append(out.Relation, rel)
+		keep[rel.Node1] = true
+		keep[rel.Node2] = true
+	}
+	for _, node := range g.Node {
+		if keep[node.Name] {
+			out.Node = append(out.Node, node)
+		}
+	}
+	return out
+}
+
+func graphExtractionConfigured(kb *types.KnowledgeBase) bool {
+	return kb != nil && kb.ExtractConfig != nil &&
+		(len(kb.ExtractConfig.Nodes) > 0 || len(kb.ExtractConfig.Relations) > 0)
+}
+
+func formatGraphRelation(rel *types.GraphRelation) string {
+	return fmt.Sprintf("%s -[%s]-> %s", rel.Node1, rel.Type, rel.Node2)
+}
+
+func formatGraphPathNodes(p *types.GraphPath) string {
+	names := make([]string, 0, len(p.Nodes))
+	for _, node := range p.Nodes {
+		names = append(names, node.Name)
+	}
+	return strings.Join(names, " → ")
+}
+
+func writeGraphEvidence(b *strings.Builder, rel *types.GraphRelation, evidence map[string]*types.Chunk, indent string) {
+	for _, id := range rel.Chunks {
+		chunk := evidence[id]
+		if chunk == nil {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s🆔 chunk_id: %s (knowledge_id: %s)\n", indent, id, chunk.KnowledgeID))
+		b.WriteString(fmt.Sprintf("%s📄 %s\n", indent, truncateRunes(chunk.Content, graphEvidencePreviewRunes)))
+	}
+}
+
+func graphRelationData(rel *types.GraphRelation, evidence map[string]*types.Chunk) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(rel.Chunks))
+	for _, id := range rel.Chunks {
+		chunk := evidence[id]
+		if chunk == nil {
+			continue
+		}
+		items = append(items, map[string]interface{}{
+			"chunk_id":     id,
+			"knowledge_id": chunk.KnowledgeID,
+			"content":      truncateRunes(chunk.Content, graphEvidencePreviewRunes),
+		})
+	}
+	return map[string]interface{}{
+		"source":    rel.Node1,
+		"target":    rel.Node2,
+		"type":      rel.Type,
+		"chunk_ids": rel.Chunks,
+		"evidence":  items,
+	}
+}
+
+func graphPathData(kbID string, p *types.GraphPath, evidence map[string]*types.Chunk) map[string]interface{} {
+	nodes := make([]string, 0, len(p.Nodes))
+	for _, node := range p.Nodes {
+		nodes = append(nodes, node.Name)
+	}
+	relations := make([]map[string]interface{}, 0, len(p.Relations))
+	for _, rel := range p.Relations {
+		relations = append(relations, graphRelationData(rel, evidence))
+	}
+	return map[string]interface{}{
+		"knowledge_base_id": kbID,
+		"nodes":             nodes,
+		"hops":              p.Hops(),
+		"relations":         relations,
+	}
+}
+
+func graphNeighborhoodData(kbID string, g *types.GraphData, evidence map[string]*types.Chunk) map[string]interface{} {
+	nodes := make([]map[string]interface{}, 0, len(g.Node))
+	for _, node := range g.Node {
+		nodes = append(nodes, map[string]interface{}{
+			"name":       node.Name,
+			"attributes": node.Attributes,
+		})
+	}
+	relations := make([]map[string]interface{}, 0, len(g.Relation))
+	for _, rel := range g.Relation {
+		relations = append(relations, graphRelationData(rel, evidence))
+	}
+	return map[string]interface{}{
+		"knowledge_base_id": kbID,
+		"nodes":             nodes,
+		"relations":         relations,
+	}
+}
+
+// graphEntityVisualization collects entity nodes and relation edges in the
+// same shape buildGraphVisualizationData uses for chunk results.
+type graphEntityVisualization struct {
+	nodes []map[string]interface{}
+	edges []map[string]interface{}
+	seen  map[string]bool
+}
+
+func newGraphEntityVisualization() *graphEntityVisualization {
+	return &graphEntityVisualization{seen: make(map[string]bool)}
+}
+
+func (v *graphEntityVisualization) addNode(name string) {
+	if v.seen[name] {
+		return
+	}
+	v.seen[name] = true
+	v.nodes = append(v.nodes, map[string]interface{}{"id": name, "label": name, "type": "entity"})
+}
+
+func (v *graphEntityVisualization) addEdge(rel *types.GraphRelation) {
+	v.addNode(rel.Node1)
+	v.addNode(rel.Node2)
+	v.edges = append(v.edges, map[string]interface{}{
+		"source":    rel.Node1,
+		"target":    rel.Node2,
+		"label":     rel.Type,
+		"chunk_ids": rel.Chunks,
+	})
+}
+
+func (v *graphEntityVisualization) addPath(p *types.GraphPath) {
+	for _, rel := range p.Relations {
+		v.addEdge(rel)
+	}
+}
+
+func (v *graphEntityVisualization) addGraph(g *types.GraphData) {
+	for _, rel := range g.Relation {
+		v.addEdge(rel)
+	}
+}
+
+func (v *graphEntityVisualization) data() map[string]interface{} {
+	return map[string]interface{}{
+		"nodes":       v.nodes,
+		"edges":       v.edges,
+		"total_nodes": len(v.nodes),
+		"total_edges": len(v.edges),
+	}
+}
@@ -184,6 +184,22 @@ func resolveAuthorizedSourceRefs(
 
 type knowledgeTagsFetcher func(context.Context, []string) (map[string][]*types.KnowledgeTag, error)
 
+// searchTargetsExcludedKnowledgeIDs returns the documents of a knowledge base
+// that document access lists hide from the caller. They stay out of reach
+// whatever else the targets grant.
+func searchTargetsExcludedKnowledgeIDs(searchTargets types.SearchTargets, kbID string) map[string]bool {
+	excluded := make(map[string]bool)
+	for _, target := range searchTargets {
+		if target == nil || target.KnowledgeBaseID != kbID {
+			continue
+		}
+		for _, id := range target.ExcludeKnowledgeIDs {
+			excluded[id] = true
+		}
+	}
+	return excluded
+}
+
 func searchTargetsAllowKnowledgeID(
 	ctx context.Context,
 	searchTargets types.SearchTargets,
@@ -194,6 +210,9 @@ func searchTargetsAllowKnowledgeID(
 	if knowledgeID == "" || kbID == "" {
 		return false, nil
 	}
+	if searchTargetsExcludedKnowledgeIDs(searchTargets, kbID)[knowledgeID] {
+		return false, nil
+	}
 
 	var tagIDs []string
 	matchedKB := false
@@ -235,6 +254,16 @@ func filterSearchResultsInSearchTargets(
 	results []*types.SearchResult,
 	knowledgeService interfaces.KnowledgeService,
 ) ([]*types.SearchResult, error) {
+	if excluded := searchTargetsExcludedKnowledgeIDs(searchTargets, kbID); len(excluded) > 0 {
+		visible := make([]*types.SearchResult, 0, len(results))
+		for _, result := range results {
+			if result != nil && !excluded[result.KnowledgeID] {
+				visible = append(visible, result)
+			}
+		}
+		results = visible
+	}
+
 	var explicitIDs []string
 	var tagIDs []string
 	matchedKB := false
@@ -315,3 +315,40 @@ func TestFilterGraphResultsHonorsDocumentScope(t *testing.T) {
 		t.Fatalf("graph result scope leaked: %+v", filtered)
 	}
 }
+
+func TestAuthorizeKnowledgeInSearchTargetsRejectsHiddenDocument(t *testing.T) {
+	service := &scopeKnowledgeService{knowledge: &types.Knowledge{ID: "doc-hidden", KnowledgeBaseID: "kb-1"}}
+	targets := types.SearchTargets{{
+		Type:                types.SearchTargetTypeKnowledgeBase,
+		KnowledgeBaseID:     "kb-1",
+		ExcludeKnowledgeIDs: []string{"doc-hidden"},
+	}}
+	if _, err := authorizeKnowledgeInSearchTargets(context.Background(), targets, "doc-hidden", service); err == nil {
+		t.Fatal("a document hidden by its access list must be rejected even under a whole-KB target")
+	}
+}
+
+func TestWikiScopeDropsPagesBuiltFromHiddenDocuments(t *testing.T) {
+	targets := types.SearchTargets{{
+		Type:                types.SearchTargetTypeKnowledgeBase,
+		KnowledgeBaseID:     "kb-1",
+		ExcludeKnowledgeIDs: []string{"doc-hidden"},
+	}}
+	scopes := NewWikiScopesFromSearchTargets(targets, []string{"kb-1"})
+	if len(scopes) != 1 || len(scopes[0].ExcludeKnowledgeIDs) != 1 {
+		t.Fatalf("hidden documents were not carried into the wiki scope: %+v", scopes)
+	}
+	for _, tc := range []struct {
+		page *types.WikiPage
+		want bool
+	}{
+		{&types.WikiPage{Slug: "entity/open", SourceRefs: []string{"doc-open|Open"}}, true},
+		{&types.WikiPage{Slug: "entity/mixed", SourceRefs: []string{"doc-open", "doc-hidden|Hidden"}}, false},
+		{&types.WikiPage{Slug: "index", PageType: types.WikiPageTypeIndex}, true},
+	} {
+		passes, err := pagePassesWikiScope(context.Background(), tc.page, scopes[0], nil)
+		if err != nil || passes != tc.want {
+			t.Fatalf("%s: passes=%v err=%v, want %v", tc.page.Slug, passes, err, tc.want)
+		}
+	}
+}
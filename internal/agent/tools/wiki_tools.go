@@ -104,10 +104,14 @@ func renderIndexOverviewForAgent(resp *types.WikiIndexResponse) string {
 //     search to specific documents inside a KB.
 //   - TagIDs: OPTIONAL whitelist of document tags. When non-empty, a wiki page
 //     is only surfaced if at least one of its SourceRefs belongs to any tag.
+//   - ExcludeKnowledgeIDs: source documents hidden from the caller by access
+//     lists. A page citing any of them is never surfaced, since its body is
+//     written from their content.
 type WikiScope struct {
-	KnowledgeBaseID string
-	KnowledgeIDs    []string
-	TagIDs          []string
+	KnowledgeBaseID     string
+	KnowledgeIDs        []string
+	TagIDs              []string
+	ExcludeKnowledgeIDs []string
 }
 
 // NewWikiScopesFromKBIDs is a convenience constructor for callers that only
@@ -157,6 +161,7 @@ func NewWikiScopesFromSearchTargets(searchTargets types.SearchTargets, wikiKBIDs
 			scope = &accumulatedScope{WikiScope: WikiScope{KnowledgeBaseID: target.KnowledgeBaseID}}
 			byKB[target.KnowledgeBaseID] = scope
 		}
+		scope.ExcludeKnowledgeIDs = append(scope.ExcludeKnowledgeIDs, target.ExcludeKnowledgeIDs...)
 		if wholeKB {
 			scope.unrestricted = true
 			continue
@@ -171,10 +176,12 @@ func NewWikiScopesFromSearchTargets(searchTargets types.SearchTargets, wikiKBIDs
 		if scope == nil {
 			continue
 		}
+		excluded := dedupNonEmptyStrings(scope.ExcludeKnowledgeIDs)
 		if scope.unrestricted {
-			scopes = append(scopes, WikiScope{KnowledgeBaseID: kbID})
+			scopes = append(scopes, WikiScope{KnowledgeBaseID: kbID, ExcludeKnowledgeIDs: excluded})
 			continue
 		}
+		scope.ExcludeKnowledgeIDs = excluded
 		scope.KnowledgeIDs = dedupNonEmptyStrings(scope.KnowledgeIDs)
 		scope.TagIDs = dedupNonEmptyStrings(scope.TagIDs)
 		scopes = append(scopes, scope.WikiScope)
@@ -278,12 +285,33 @@ func pageIntersectsKnowledgeIDs(page *types.WikiPage, allowed map[string]bool) b
 	return false
 }
 
+// pageCitesExcludedKnowledge reports whether a page is written from any
+// document hidden from the caller
+func pageCitesExcludedKnowledge(page *types.WikiPage, excluded []string) bool {
+	if len(excluded) == 0 {
+		return false
+	}
+	hidden := make(map[string]bool, len(excluded))
+	for _, id := range excluded {
+		hidden[id] = true
+	}
+	for _, kid := range extractSourceKnowledgeIDs(page) {
+		if hidden[kid] {
+			return true
+		}
+	}
+	return false
+}
+
 func pagePassesWikiScope(
 	ctx context.Context,
 	page *types.WikiPage,
 	scope WikiScope,
 	fetchTags knowledgeTagsFetcher,
 ) (bool, error) {
+	if pageCitesExcludedKnowledge(page, scope.ExcludeKnowledgeIDs) {
+		return false, nil
+	}
 	allowed, hasKnowledgeFilter := scopeKnowledgeFilter(scope)
 	tagIDs := dedupNonEmptyStrings(scope.TagIDs)
 	if !hasKnowledgeFilter && len(tagIDs) == 0 {
@@ -488,6 +516,41 @@ Knowledge-base routing is automatic. Known link/search provenance is preferred;
 	}
 }
 
+// excludedKnowledgeIDs returns the documents of kbID hidden from the caller
+func (t *wikiReadPageTool) excludedKnowledgeIDs(kbID string) []string {
+	for _, scope := range t.scopes {
+		if scope.KnowledgeBaseID == kbID {
+			return scope.ExcludeKnowledgeIDs
+		}
+	}
+	return nil
+}
+
+// dropExcludedIndexItems removes from an index overview the pages written
+// from documents hidden from the caller, so their titles and summaries do
+// not reach the model through the index page. A page that cannot be loaded
+// is dropped as well.
+func (t *wikiReadPageTool) dropExcludedIndexItems(
+	ctx context.Context, kbID string, overview *types.WikiIndexResponse,
+) {
+	excluded := t.excludedKnowledgeIDs(kbID)
+	if len(excluded) == 0 {
+		return
+	}
+	for i := range overview.Groups {
+		group := &overview.Groups[i]
+		kept := group.Items[:0]
+		for _, item := range group.Items {
+			page, err := t.wikiService.GetPageBySlug(ctx, kbID, item.Slug)
+			if err != nil || page == nil || pageCitesExcludedKnowledge(page, excluded) {
+				continue
+			}
+			kept = append(kept, item)
+		}
+		group.Items = kept
+	}
+}
+
 // seenLinkKey builds a dedupe key scoped to a knowledge base so that identical
 // slugs from different KBs are not collapsed into a single "already seen" entry.
 func seenLinkKey(kbID, slug string) string {
@@ -546,7 +609,8 @@ func (t *wikiReadPageTool) Execute(ctx context.Context, args json.RawMessage) (*
 			}
 
 			linkPage, err := t.wikiService.GetPageBySlug(ctx, kbID, s)
-			if err != nil || linkPage == nil || linkPage.Summary == "" {
+			if err != nil || linkPage == nil || linkPage.Summary == "" ||
+				pageCitesExcludedKnowledge(linkPage, t.excludedKnowledgeIDs(kbID)) {
 				descs = append(descs, fmt.Sprintf("[[%s]]", s))
 				continue
 			}
@@ -599,6 +663,7 @@ func (t *wikiReadPageTool) Execute(ctx context.Context, args json.RawMessage) (*
 		// steers the model to wiki_search for deeper exploration.
 		if page.PageType == types.WikiPageTypeIndex {
 			if overview, err := t.wikiService.GetIndexView(ctx, kbID, nil, wikiIndexAgentTopK, ""); err == nil && overview != nil {
+				t.dropExcludedIndexItems(ctx, kbID, overview)
 				resolved.body = renderIndexOverviewForAgent(overview)
 				for _, group := range overview.Groups {
 					for _, item := range group.Items {
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAgentTriggerNotFound is returned when an agent trigger does not exist
+	ErrAgentTriggerNotFound = errors.New("agent trigger not found")
+	// ErrAgentTriggerRunNotFound is returned when an agent trigger run does not exist
+	ErrAgentTriggerRunNotFound = errors.New("agent trigger run not found")
+)
+
+type agentTriggerRepository struct {
+	db *gorm.DB
+}
+
+// NewAgentTriggerRepository creates a repository for agent triggers and their runs
+func NewAgentTriggerRepository(db *gorm.DB) interfaces.AgentTriggerRepository {
+	return &agentTriggerRepository{db: db}
+}
+
+func (r *agentTriggerRepository) Create(ctx context.Context, trigger *types.AgentTrigger) error {
+	return r.db.WithContext(ctx).Create(trigger).Error
+}
+
+func (r *agentTriggerRepository) Update(ctx context.Context, trigger *types.AgentTrigger) error {
+	return r.db.WithContext(ctx).Save(trigger).Error
+}
+
+func (r *agentTriggerRepository) Delete(ctx context.Context, tenantID uint64, agentID string, id string) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ? AND agent_id = ?", id, tenantID, agentID).
+		Delete(&types.AgentTrigger{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAgentTriggerNotFound
+	}
+	return nil
+}
+
+func (r *agentTriggerRepository) Get(
+	ctx context.Context, tenantID uint64, agentID string, id string,
+) (*types.AgentTrigger, error) {
+	var trigger types.AgentTrigger
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ? AND agent_id = ?", id, tenantID, agentID).
+		First(&trigger).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAgentTriggerNotFound
+	}
+	return &trigger, err
+}
+
+func (r *agentTriggerRepository) GetByID(ctx context.Context, tenantID uint64, id string) (*types.AgentTrigger, error) {
+	var trigger types.AgentTrigger
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&trigger).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAgentTriggerNotFound
+	}
+	return &trigger, err
+}
+
+func (r *agentTriggerRepository) ListByAgent(
+	ctx context.Context, tenantID uint64, agentID string,
+) ([]*types.AgentTrigger, error) {
+	var triggers []*types.AgentTrigger
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND agent_id = ?", tenantID, agentID).
+		Order("created_at ASC").
+		Find(&triggers).Error
+	return triggers, err
+}
+
+func (r *agentTriggerRepository) ListEnabledSchedules(ctx context.Context) ([]*types.AgentTrigger, error) {
+	var triggers []*types.AgentTrigger
+	err := r.db.WithContext(ctx).
+		Where("enabled = ? AND trigger_type = ?", true, types.AgentTriggerTypeSchedule).
+		Find(&triggers).Error
+	return triggers, err
+}
+
+func (r *agentTriggerRepository) ListEnabledByEvent(
+	ctx context.Context, tenantID uint64, eventType types.AgentTriggerEventType,
+) ([]*types.AgentTrigger, error) {
+	var triggers []*types.AgentTrigger
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND enabled = ? AND trigger_type = ? AND event_type = ?",
+			tenantID, true, types.AgentTriggerTypeEvent, eventType).
+		Find(&triggers).Error
+	return triggers, err
+}
+
+func (r *agentTriggerRepository) RecordRun(ctx context.Context, id string, at time.Time, status string) error {
+	return r.db.WithContext(ctx).
+		Model(&types.AgentTrigger{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"last_run_at":     at,
+			"last_run_status": status,
+		}).Error
+}
+
+func (r *agentTriggerRepository) CreateRun(ctx context.Context, run *types.AgentTriggerRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *agentTriggerRepository) UpdateRun(ctx context.Context, run *types.AgentTriggerRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+func (r *agentTriggerRepository) DeleteRun(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&types.AgentTriggerRun{}).Error
+}
+
+func (r *agentTriggerRepository) GetRun(ctx context.Context, tenantID uint64, id string) (*types.AgentTriggerRun, error) {
+	var run types.AgentTriggerRun
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAgentTriggerRunNotFound
+	}
+	return &run, err
+}
+
+func (r *agentTriggerRepository) ListRuns(
+	ctx context.Context, tenantID uint64, triggerID string, page *types.Pagination,
+) ([]*types.AgentTriggerRun, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&types.AgentTriggerRun{}).
+		Where("tenant_id = ? AND trigger_id = ?", tenantID, triggerID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var runs []*types.AgentTriggerRun
+	err := query.
+		Order("created_at DESC").
+		Offset(page.Offset()).
+		Limit(page.Limit()).
+		Find(&runs).Error
+	return runs, total, err
+}
+
+func (r *agentTriggerRepository) HasActiveRun(ctx context.Context, triggerID string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&types.AgentTriggerRun{}).
+		Where("trigger_id = ? AND status IN ? AND created_at > ?", triggerID,
+			[]string{types.AgentTriggerRunPending, types.AgentTriggerRunRunning}, since).
+		Count(&count).Error
+	return count > 0, err
+}
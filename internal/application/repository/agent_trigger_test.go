@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newAgentTriggerTestRepo(t *testing.T) (*gorm.DB, *agentTriggerRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.AgentTrigger{}, &types.AgentTriggerRun{}))
+	return db, NewAgentTriggerRepository(db).(*agentTriggerRepository)
+}
+
+func TestAgentTriggerRoundTripKeepsDisabledFlagAndDelivery(t *testing.T) {
+	db, repo := newAgentTriggerTestRepo(t)
+	ctx := context.Background()
+
+	trigger := &types.AgentTrigger{
+		ID: uuid.NewString(), TenantID: 1, AgentID: "agent", Name: "digest",
+		Enabled: false, TriggerType: types.AgentTriggerTypeSchedule, Schedule: "0 0 9 * * *",
+		Prompt: "summarize",
+		Delivery: types.AgentTriggerDelivery{
+			Type: types.AgentTriggerDeliveryWebhook, WebhookURL: "https://example.com/hook", WebhookSecret: "s3cret",
+		},
+	}
+	require.NoError(t, repo.Create(ctx, trigger))
+
+	require.True(t, db.Migrator().HasColumn(&types.AgentTrigger{}, "delivery_webhook_secret"))
+	require.True(t, db.Migrator().HasColumn(&types.AgentTrigger{}, "delivery_im_channel_id"))
+
+	got, err := repo.Get(ctx, 1, "agent", trigger.ID)
+	require.NoError(t, err)
+	require.False(t, got.Enabled, "an explicit enabled=false must survive the insert")
+	require.Equal(t, "s3cret", got.Delivery.WebhookSecret)
+
+	schedules, err := repo.ListEnabledSchedules(ctx)
+	require.NoError(t, err)
+	require.Empty(t, schedules)
+
+	_, err = repo.Get(ctx, 1, "other-agent", trigger.ID)
+	require.ErrorIs(t, err, ErrAgentTriggerNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, 2, "agent", trigger.ID), ErrAgentTriggerNotFound)
+}
+
+func TestAgentTriggerHasActiveRunIgnoresFinishedAndOldRuns(t *testing.T) {
+	_, repo := newAgentTriggerTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	newRun := func(status string, created time.Time) {
+		require.NoError(t, repo.CreateRun(ctx, &types.AgentTriggerRun{
+			ID: uuid.NewString(), TenantID: 1, TriggerID: "trigger", AgentID: "agent",
+			Source: types.AgentTriggerSourceSchedule, Status: status, CreatedAt: created,
+			Event: &types.AgentTriggerEvent{Type: types.AgentTriggerEventKnowledgeIngested, SubjectID: "k-1"},
+		}))
+	}
+	newRun(types.AgentTriggerRunSuccess, now.Add(-time.Minute))
+	newRun(types.AgentTriggerRunRunning, now.Add(-3*time.Hour)) // left behind by a crashed worker
+
+	active, err := repo.HasActiveRun(ctx, "trigger", now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.False(t, active)
+
+	newRun(types.AgentTriggerRunPending, now.Add(-time.Minute))
+	active, err = repo.HasActiveRun(ctx, "trigger", now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.True(t, active)
+
+	runs, total, err := repo.ListRuns(ctx, 1, "trigger", &types.Pagination{})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.NotNil(t, runs[0].Event)
+	require.Equal(t, "k-1", runs[0].Event.SubjectID)
+}
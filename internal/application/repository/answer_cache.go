@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+// ErrAnswerCacheEntryNotFound is returned when an answer cache entry does not exist
+var ErrAnswerCacheEntryNotFound = errors.New("answer cache entry not found")
+
+type answerCacheRepository struct {
+	db *gorm.DB
+}
+
+// NewAnswerCacheRepository creates a repository for answer cache entries
+func NewAnswerCacheRepository(db *gorm.DB) interfaces.AnswerCacheRepository {
+	return &answerCacheRepository{db: db}
+}
+
+func (r *answerCacheRepository) Create(ctx context.Context, entry *types.AnswerCacheEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *answerCacheRepository) ListCandidates(
+	ctx context.Context, tenantID uint64, agentID string, scopeHash string, since time.Time, limit int,
+) ([]*types.AnswerCacheEntry, error) {
+	var entries []*types.AnswerCacheEntry
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND agent_id = ? AND scope_hash = ?", tenantID, agentID, scopeHash).
+		Where("created_at > ? AND expires_at > ?", since, time.Now()).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *answerCacheRepository) RecordHit(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&types.AnswerCacheEntry{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"hit_count":   gorm.Expr("hit_count + 1"),
+			"last_hit_at": time.Now(),
+		}).Error
+}
+
+func (r *answerCacheRepository) List(
+	ctx context.Context, tenantID uint64, agentID string, page *types.Pagination,
+) ([]*types.AnswerCacheEntry, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&types.AnswerCacheEntry{}).
+		Where("tenant_id = ? AND agent_id = ?", tenantID, agentID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var entries []*types.AnswerCacheEntry
+	err := query.
+		Omit("query_embedding").
+		Order("created_at DESC").
+		Offset(page.Offset()).
+		Limit(page.Limit()).
+		Find(&entries).Error
+	return entries, total, err
+}
+
+func (r *answerCacheRepository) Delete(ctx context.Context, tenantID uint64, agentID string, id string) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ? AND agent_id = ?", id, tenantID, agentID).
+		Delete(&types.AnswerCacheEntry{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAnswerCacheEntryNotFound
+	}
+	return nil
+}
+
+func (r *answerCacheRepository) DeleteByAgent(ctx context.Context, tenantID uint64, agentID string) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND agent_id = ?", tenantID, agentID).
+		Delete(&types.AnswerCacheEntry{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *answerCacheRepository) DeleteStale(
+	ctx context.Context, tenantID uint64, agentID string, scopeHash string, changedAt time.Time,
+) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND agent_id = ? AND scope_hash = ?", tenantID, agentID, scopeHash).
+		Where("created_at <= ? OR expires_at <= ?", changedAt, time.Now()).
+		Delete(&types.AnswerCacheEntry{})
+	return res.RowsAffected, res.Error
+}
+
+// LastKnowledgeChange probes each knowledge base separately so every lookup
+// is an ordered index scan stopping at the first row. Soft-deleted rows count:
+// removing a document or a chunk changes what an answer may cite.
+func (r *answerCacheRepository) LastKnowledgeChange(
+	ctx context.Context, knowledgeBaseIDs []string,
+) (time.Time, error) {
+	var latest time.Time
+	for _, kbID := range knowledgeBaseIDs {
+		for _, probe := range []struct {
+			model    any
+			kbColumn string
+			column   string
+		}{
+			{&types.KnowledgeBase{}, "id", "updated_at"},
+			{&types.Knowledge{}, "knowledge_base_id", "updated_at"},
+			{&types.Knowledge{}, "knowledge_base_id", "deleted_at"},
+			{&types.Chunk{}, "knowledge_base_id", "updated_at"},
+			{&types.Chunk{}, "knowledge_base_id", "deleted_at"},
+		} {
+			var stamps []time.Time
+			err := r.db.WithContext(ctx).Unscoped().
+				Model(probe.model).
+				Where(probe.kbColumn+" = ? AND "+probe.column+" IS NOT NULL", kbID).
+				Order(probe.column+" DESC").
+				Limit(1).
+				Pluck(probe.column, &stamps).Error
+			if err != nil {
+				return time.Time{}, err
+			}
+			if len(stamps) > 0 && stamps[0].After(latest) {
+				latest = stamps[0]
+			}
+		}
+	}
+	return latest, nil
+}
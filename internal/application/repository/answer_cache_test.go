@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newAnswerCacheTestRepo(t *testing.T) (*gorm.DB, *answerCacheRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(knowledgeBasesTestDDL).Error)
+	require.NoError(t, db.Exec(knowledgesTestDDL).Error)
+	require.NoError(t, db.AutoMigrate(&types.Chunk{}, &types.AnswerCacheEntry{}))
+	return db, NewAnswerCacheRepository(db).(*answerCacheRepository)
+}
+
+func TestAnswerCacheLastKnowledgeChangeCountsChunkDeletes(t *testing.T) {
+	db, repo := newAnswerCacheTestRepo(t)
+	ctx := context.Background()
+
+	edited := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	chunk := makeChunk("kb-1", "k-1", "text")
+	chunk.UpdatedAt = edited
+	require.NoError(t, db.Create(chunk).Error)
+	require.NoError(t, db.Create(makeChunk("kb-2", "k-2", "text")).Error)
+
+	changed, err := repo.LastKnowledgeChange(ctx, []string{"kb-1"})
+	require.NoError(t, err)
+	require.True(t, changed.Equal(edited), "last change = %v, want chunk update %v", changed, edited)
+
+	require.NoError(t, db.Delete(chunk).Error)
+	changed, err = repo.LastKnowledgeChange(ctx, []string{"kb-1"})
+	require.NoError(t, err)
+	require.True(t, changed.After(edited), "soft-deleting a chunk did not count as a change")
+}
+
+func TestAnswerCacheCandidatesSkipStaleAndExpiredEntries(t *testing.T) {
+	_, repo := newAnswerCacheTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	newEntry := func(created, expires time.Time) *types.AnswerCacheEntry {
+		entry := &types.AnswerCacheEntry{
+			ID: uuid.NewString(), TenantID: 1, AgentID: "agent", ScopeHash: "scope",
+			KnowledgeBaseIDs: types.StringArray{"kb-1"}, Query: "q", Answer: "a",
+			References: types.References{{ID: "chunk-1", Content: "cited"}},
+			CreatedAt:  created, ExpiresAt: expires,
+		}
+		require.NoError(t, repo.Create(ctx, entry))
+		return entry
+	}
+	fresh := newEntry(now.Add(-time.Minute), now.Add(time.Hour))
+	newEntry(now.Add(-2*time.Hour), now.Add(time.Hour))      // created before the change
+	newEntry(now.Add(-2*time.Minute), now.Add(-time.Second)) // expired
+
+	candidates, err := repo.ListCandidates(ctx, 1, "agent", "scope", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	require.Equal(t, fresh.ID, candidates[0].ID)
+	require.Equal(t, types.StringArray{"kb-1"}, candidates[0].KnowledgeBaseIDs)
+	require.Len(t, candidates[0].References, 1)
+	require.Equal(t, "cited", candidates[0].References[0].Content)
+
+	require.NoError(t, repo.RecordHit(ctx, fresh.ID))
+	entries, total, err := repo.List(ctx, 1, "agent", &types.Pagination{})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.EqualValues(t, 1, entries[0].HitCount)
+	require.NotNil(t, entries[0].LastHitAt)
+
+	deleted, err := repo.DeleteStale(ctx, 1, "agent", "scope", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, deleted)
+	require.ErrorIs(t, repo.Delete(ctx, 2, "agent", fresh.ID), ErrAnswerCacheEntryNotFound)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEvaluationTaskNotFound    = errors.New("evaluation task not found")
+	ErrEvaluationDatasetNotFound = errors.New("evaluation dataset not found")
+)
+
+// datasetItemBatchSize bounds the number of rows per INSERT when storing an
+// uploaded dataset, keeping large uploads under driver parameter limits.
+const datasetItemBatchSize = 200
+
+type evaluationRepository struct {
+	db *gorm.DB
+}
+
+// NewEvaluationRepository creates a repository for evaluation tasks, results and datasets
+func NewEvaluationRepository(db *gorm.DB) interfaces.EvaluationRepository {
+	return &evaluationRepository{db: db}
+}
+
+func (r *evaluationRepository) CreateTask(ctx context.Context, task *types.EvaluationTask) error {
+	return r.db.WithContext(ctx).Create(task).Error
+}
+
+func (r *evaluationRepository) GetTask(
+	ctx context.Context, tenantID uint64, taskID string,
+) (*types.EvaluationTask, error) {
+	var task types.EvaluationTask
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", taskID, tenantID).
+		First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEvaluationTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *evaluationRepository) ListTasks(
+	ctx context.Context, tenantID uint64, limit int,
+) ([]*types.EvaluationTask, error) {
+	var tasks []*types.EvaluationTask
+	query := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("start_time DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&tasks).Error
+	return tasks, err
+}
+
+// UpdateTaskProgress writes the fields a running task mutates. Columns are
+// listed explicitly so zero values (e.g. Finished=0 on retry) are persisted.
+func (r *evaluationRepository) UpdateTaskProgress(ctx context.Context, task *types.EvaluationTask) error {
+	res := r.db.WithContext(ctx).
+		Model(&types.EvaluationTask{}).
+		Where("id = ? AND tenant_id = ?", task.ID, task.TenantID).
+		Updates(map[string]any{
+			"status":     task.Status,
+			"err_msg":    task.ErrMsg,
+			"total":      task.Total,
+			"finished":   task.Finished,
+			"metric":     task.Metric,
+			"end_time":   task.EndTime,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrEvaluationTaskNotFound
+	}
+	return nil
+}
+
+func (r *evaluationRepository) FailStaleTasks(
+	ctx context.Context, updatedBefore time.Time, errMsg string,
+) (int64, error) {
+	now := time.Now()
+	res := r.db.WithContext(ctx).
+		Model(&types.EvaluationTask{}).
+		Where("status IN ? AND updated_at < ?", []types.EvaluationStatue{
+			types.EvaluationStatuePending, types.EvaluationStatueRunning,
+		}, updatedBefore).
+		Updates(map[string]any{
+			"status":     types.EvaluationStatueFailed,
+			"err_msg":    errMsg,
+			"end_time":   &now,
+			"updated_at": now,
+		})
+	return res.RowsAffected, res.Error
+}
+
+func (r *evaluationRepository) CreateQuestionResult(
+	ctx context.Context, result *types.EvaluationQuestionResult,
+) error {
+	return r.db.WithContext(ctx).Create(result).Error
+}
+
+func (r *evaluationRepository) ListQuestionResults(
+	ctx context.Context, tenantID uint64, taskID string,
+) ([]*types.EvaluationQuestionResult, error) {
+	var results []*types.EvaluationQuestionResult
+	err := r.db.WithContext(ctx).
+		Where("task_id = ? AND tenant_id = ?", taskID, tenantID).
+		Order("question_index ASC").
+		Find(&results).Error
+	return results, err
+}
+
+func (r *evaluationRepository) CreateDataset(
+	ctx context.Context, dataset *types.EvaluationDataset, items []*types.EvaluationDatasetItem,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dataset).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			item.DatasetID = dataset.ID
+			item.TenantID = dataset.TenantID
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(items, datasetItemBatchSize).Error
+	})
+}
+
+func (r *evaluationRepository) GetDataset(
+	ctx context.Context, tenantID uint64, datasetID string,
+) (*types.EvaluationDataset, error) {
+	var dataset types.EvaluationDataset
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", datasetID, tenantID).
+		First(&dataset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEvaluationDatasetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+func (r *evaluationRepository) ListDatasets(
+	ctx context.Context, tenantID uint64,
+) ([]*types.EvaluationDataset, error) {
+	var datasets []*types.EvaluationDataset
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&datasets).Error
+	return datasets, err
+}
+
+func (r *evaluationRepository) ListDatasetItems(
+	ctx context.Context, tenantID uint64, datasetID string,
+) ([]*types.EvaluationDatasetItem, error) {
+	var items []*types.EvaluationDatasetItem
+	err := r.db.WithContext(ctx).
+		Where("dataset_id = ? AND tenant_id = ?", datasetID, tenantID).
+		Order("qid ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *evaluationRepository) DeleteDataset(ctx context.Context, tenantID uint64, datasetID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND tenant_id = ?", datasetID, tenantID).
+			Delete(&types.EvaluationDataset{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrEvaluationDatasetNotFound
+		}
+		return tx.Where("dataset_id = ? AND tenant_id = ?", datasetID, tenantID).
+			Delete(&types.EvaluationDatasetItem{}).Error
+	})
+}
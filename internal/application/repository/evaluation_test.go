@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newEvaluationTestRepo(t *testing.T) (*gorm.DB, *evaluationRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&types.EvaluationTask{}, &types.EvaluationQuestionResult{},
+		&types.EvaluationDataset{}, &types.EvaluationDatasetItem{},
+	))
+	return db, NewEvaluationRepository(db).(*evaluationRepository)
+}
+
+func TestEvaluationTaskLifecycleIsTenantScoped(t *testing.T) {
+	_, repo := newEvaluationTestRepo(t)
+	ctx := context.Background()
+
+	task := &types.EvaluationTask{ID: uuid.NewString(), TenantID: 1, DatasetID: "default", StartTime: time.Now()}
+	require.NoError(t, repo.CreateTask(ctx, task))
+
+	_, err := repo.GetTask(ctx, 2, task.ID)
+	require.ErrorIs(t, err, ErrEvaluationTaskNotFound)
+
+	end := time.Now()
+	task.Status = types.EvaluationStatueSuccess
+	task.Total, task.Finished = 2, 2
+	task.EndTime = &end
+	task.Metric = &types.MetricResult{RetrievalMetrics: types.RetrievalMetrics{MRR: 0.5}}
+	require.NoError(t, repo.UpdateTaskProgress(ctx, task))
+
+	stored, err := repo.GetTask(ctx, 1, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.EvaluationStatueSuccess, stored.Status)
+	require.Equal(t, 2, stored.Finished)
+	require.NotNil(t, stored.EndTime)
+	require.NotNil(t, stored.Metric)
+	require.Equal(t, 0.5, stored.Metric.RetrievalMetrics.MRR)
+
+	other := *task
+	other.TenantID = 2
+	require.ErrorIs(t, repo.UpdateTaskProgress(ctx, &other), ErrEvaluationTaskNotFound)
+
+	tasks, err := repo.ListTasks(ctx, 2, 10)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	for i := range 2 {
+		require.NoError(t, repo.CreateQuestionResult(ctx, &types.EvaluationQuestionResult{
+			TaskID: task.ID, TenantID: 1, QuestionIndex: 1 - i, Question: "q",
+			RetrievalGT: types.IntList{1}, RetrievalIDs: types.IntList{3, 1},
+		}))
+	}
+	results, err := repo.ListQuestionResults(ctx, 1, task.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, 0, results[0].QuestionIndex)
+	require.Equal(t, types.IntList{3, 1}, results[0].RetrievalIDs)
+}
+
+func TestFailStaleEvaluationTasks(t *testing.T) {
+	db, repo := newEvaluationTestRepo(t)
+	ctx := context.Background()
+
+	stale := &types.EvaluationTask{ID: "stale", TenantID: 1, Status: types.EvaluationStatueRunning}
+	fresh := &types.EvaluationTask{ID: "fresh", TenantID: 1, Status: types.EvaluationStatueRunning}
+	done := &types.EvaluationTask{ID: "done", TenantID: 1, Status: types.EvaluationStatueSuccess}
+	for _, task := range []*types.EvaluationTask{stale, fresh, done} {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Model(&types.EvaluationTask{}).
+		Where("id IN ?", []string{"stale", "done"}).UpdateColumn("updated_at", old).Error)
+
+	n, err := repo.FailStaleTasks(ctx, time.Now().Add(-30*time.Minute), "interrupted")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	got, err := repo.GetTask(ctx, 1, "stale")
+	require.NoError(t, err)
+	require.Equal(t, types.EvaluationStatueFailed, got.Status)
+	require.Equal(t, "interrupted", got.ErrMsg)
+	got, err = repo.GetTask(ctx, 1, "fresh")
+	require.NoError(t, err)
+	require.Equal(t, types.EvaluationStatueRunning, got.Status)
+}
+
+func TestEvaluationDatasetRoundTrip(t *testing.T) {
+	db, repo := newEvaluationTestRepo(t)
+	ctx := context.Background()
+
+	dataset := &types.EvaluationDataset{TenantID: 1, Name: "faq", Format: types.EvaluationDatasetFormatJSONL}
+	items := []*types.EvaluationDatasetItem{
+		{QID: 1, Question: "b", PIDs: types.IntList{1}, Passages: types.StringArray{"p1"}},
+		{QID: 0, Question: "a", PIDs: types.IntList{0, 1}, Passages: types.StringArray{"p0", "p1"}},
+	}
+	require.NoError(t, repo.CreateDataset(ctx, dataset, items))
+	require.NotEmpty(t, dataset.ID)
+
+	stored, err := repo.ListDatasetItems(ctx, 1, dataset.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	pair := stored[0].QAPair()
+	require.Equal(t, "a", pair.Question)
+	require.Equal(t, []int{0, 1}, pair.PIDs)
+	require.Equal(t, []string{"p0", "p1"}, pair.Passages)
+
+	require.ErrorIs(t, repo.DeleteDataset(ctx, 2, dataset.ID), ErrEvaluationDatasetNotFound)
+	require.NoError(t, repo.DeleteDataset(ctx, 1, dataset.ID))
+	_, err = repo.GetDataset(ctx, 1, dataset.ID)
+	require.ErrorIs(t, err, ErrEvaluationDatasetNotFound)
+	var count int64
+	require.NoError(t, db.Model(&types.EvaluationDatasetItem{}).Count(&count).Error)
+	require.Zero(t, count)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+// graphCommunityRepository implements the GraphCommunityRepository interface
+type graphCommunityRepository struct {
+	db *gorm.DB
+}
+
+// NewGraphCommunityRepository creates a new graph community repository
+func NewGraphCommunityRepository(db *gorm.DB) interfaces.GraphCommunityRepository {
+	return &graphCommunityRepository{db: db}
+}
+
+// ReplaceByKnowledgeBase deletes the knowledge base's communities and inserts
+// the new set in one transaction, so global retrieval never sees a half
+// rebuilt knowledge base.
+func (r *graphCommunityRepository) ReplaceByKnowledgeBase(
+	ctx context.Context, kbID string, communities []*types.GraphCommunity,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("knowledge_base_id = ?", kbID).Delete(&types.GraphCommunity{}).Error; err != nil {
+			return err
+		}
+		if len(communities) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(communities, 100).Error
+	})
+}
+
+// ListByKnowledgeBases returns the communities of the given knowledge bases,
+// highest rank first
+func (r *graphCommunityRepository) ListByKnowledgeBases(
+	ctx context.Context, kbIDs []string,
+) ([]*types.GraphCommunity, error) {
+	if len(kbIDs) == 0 {
+		return nil, nil
+	}
+	var communities []*types.GraphCommunity
+	if err := r.db.WithContext(ctx).
+		Where("knowledge_base_id IN ?", kbIDs).
+		Order("rank DESC").Order("id").
+		Find(&communities).Error; err != nil {
+		return nil, err
+	}
+	return communities, nil
+}
+
+// DeleteByKnowledgeBase removes every community of a knowledge base
+func (r *graphCommunityRepository) DeleteByKnowledgeBase(ctx context.Context, kbID string) error {
+	return r.db.WithContext(ctx).Where("knowledge_base_id = ?", kbID).Delete(&types.GraphCommunity{}).Error
+}
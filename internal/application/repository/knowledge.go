@@ -147,6 +147,9 @@ func applyKnowledgeListFilter(query *gorm.DB, filter types.KnowledgeListFilter)
 		// dead-letter callback and stays visible so the failure remains actionable.
 		query = query.Where("parse_status <> ?", types.ParseStatusDeleting)
 	}
+	if len(filter.ExcludeKnowledgeIDs) > 0 {
+		query = query.Where("knowledges.id NOT IN ?", filter.ExcludeKnowledgeIDs)
+	}
 	if !filter.UpdatedFrom.IsZero() {
 		query = query.Where("updated_at >= ?", filter.UpdatedFrom)
 	}
@@ -273,9 +276,6 @@ func (r *knowledgeRepository) RenameKnowledgeFolderPath(
 		Find(&rows).Error; err != nil {
 		return 0, err
 	}
-	if len(rows) == 0 {
-		return 0, nil
-	}
 
 	// Group by destination so each distinct rewrite is a single UPDATE.
 	byTarget := map[string][]string{}
@@ -296,7 +296,9 @@ func (r *knowledgeRepository) RenameKnowledgeFolderPath(
 			}
 			affected += result.RowsAffected
 		}
-		return nil
+		// Folder access lists follow the folder, so the moved documents keep
+		// the permissions they inherit.
+		return renameFolderACLEntries(tx, tenantID, kbID, from, to)
 	})
 	if err != nil {
 		return 0, err
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+type knowledgeACLRepository struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeACLRepository creates a repository for document and folder access lists
+func NewKnowledgeACLRepository(db *gorm.DB) interfaces.KnowledgeACLRepository {
+	return &knowledgeACLRepository{db: db}
+}
+
+func (r *knowledgeACLRepository) ListByKnowledgeBases(
+	ctx context.Context, kbIDs []string,
+) ([]*types.KnowledgeACLEntry, error) {
+	if len(kbIDs) == 0 {
+		return nil, nil
+	}
+	var entries []*types.KnowledgeACLEntry
+	err := r.db.WithContext(ctx).
+		Where("knowledge_base_id IN ?", kbIDs).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *knowledgeACLRepository) ListByTarget(
+	ctx context.Context, kbID string, targetType types.KnowledgeACLTargetType, targetID string,
+) ([]*types.KnowledgeACLEntry, error) {
+	var entries []*types.KnowledgeACLEntry
+	err := r.db.WithContext(ctx).
+		Where("knowledge_base_id = ? AND target_type = ? AND target_id = ?", kbID, targetType, targetID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *knowledgeACLRepository) ReplaceTarget(
+	ctx context.Context, kbID string, targetType types.KnowledgeACLTargetType, targetID string,
+	entries []*types.KnowledgeACLEntry,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("knowledge_base_id = ? AND target_type = ? AND target_id = ? AND data_source_id = ?",
+				kbID, targetType, targetID, "").
+			Delete(&types.KnowledgeACLEntry{}).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+func (r *knowledgeACLRepository) ReplaceSynced(
+	ctx context.Context, kbID string, dataSourceID string, knowledgeIDs []string,
+	entries []*types.KnowledgeACLEntry,
+) error {
+	if len(knowledgeIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("knowledge_base_id = ? AND target_type = ? AND target_id IN ? AND data_source_id = ?",
+				kbID, types.KnowledgeACLTargetKnowledge, knowledgeIDs, dataSourceID).
+			Delete(&types.KnowledgeACLEntry{}).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&entries, 200).Error
+	})
+}
+
+func (r *knowledgeACLRepository) MoveKnowledgeTarget(
+	ctx context.Context, kbID string, fromKnowledgeID string, toKnowledgeID string,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&types.KnowledgeACLEntry{}).
+		Where("knowledge_base_id = ? AND target_type = ? AND target_id = ?",
+			kbID, types.KnowledgeACLTargetKnowledge, fromKnowledgeID).
+		Update("target_id", toKnowledgeID).Error
+}
+
+func (r *knowledgeACLRepository) DeleteByDataSource(ctx context.Context, dataSourceID string) error {
+	if dataSourceID == "" {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("data_source_id = ?", dataSourceID).
+		Delete(&types.KnowledgeACLEntry{}).Error
+}
+
+func (r *knowledgeACLRepository) DeleteByKnowledge(ctx context.Context, kbID string, knowledgeIDs []string) error {
+	if len(knowledgeIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("knowledge_base_id = ? AND target_type = ? AND target_id IN ?",
+			kbID, types.KnowledgeACLTargetKnowledge, knowledgeIDs).
+		Delete(&types.KnowledgeACLEntry{}).Error
+}
+
+func (r *knowledgeACLRepository) DeleteByKnowledgeBase(ctx context.Context, kbID string) error {
+	if kbID == "" {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("knowledge_base_id = ?", kbID).
+		Delete(&types.KnowledgeACLEntry{}).Error
+}
+
+func (r *knowledgeACLRepository) ListKnowledgeInFolders(
+	ctx context.Context, kbID string, folderPaths []string,
+) ([]*types.Knowledge, error) {
+	if len(folderPaths) == 0 {
+		return nil, nil
+	}
+	conditions := make([]string, 0, len(folderPaths))
+	args := make([]interface{}, 0, len(folderPaths)*3)
+	for _, path := range folderPaths {
+		conditions = append(conditions, "folder_path = ? OR folder_path LIKE ? ESCAPE ?")
+		args = append(args, path, escapeLikeKeyword(path)+"/%", likeEscapeChar)
+	}
+	var rows []*types.Knowledge
+	err := r.db.WithContext(ctx).
+		Model(&types.Knowledge{}).
+		Select("id", "folder_path").
+		Where("knowledge_base_id = ?", kbID).
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *knowledgeACLRepository) CountByPrincipal(
+	ctx context.Context, tenantID uint64, principalType types.KnowledgeACLPrincipalType, principalID string,
+) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&types.KnowledgeACLEntry{}).
+		Where("tenant_id = ? AND principal_type = ? AND principal_id = ?", tenantID, principalType, principalID).
+		Count(&count).Error
+	return count, err
+}
+
+// renameFolderACLEntries moves the folder access lists of a folder and its
+// subtree along with a folder rename. When the destination folder already has
+// an access list it is kept and the moved one is dropped, so merged documents
+// end up with the destination's permissions like documents moved one by one.
+func renameFolderACLEntries(tx *gorm.DB, tenantID uint64, kbID, from, to string) error {
+	var entries []*types.KnowledgeACLEntry
+	if err := tx.
+		Where("tenant_id = ? AND knowledge_base_id = ? AND target_type = ? AND (target_id = ? OR target_id LIKE ? ESCAPE ?)",
+			tenantID, kbID, types.KnowledgeACLTargetFolder, from, escapeLikeKeyword(from)+"/%", likeEscapeChar).
+		Find(&entries).Error; err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Destinations are checked and entries rewritten by ID against a snapshot
+	// taken before any update, since a folder can be renamed into its own parent
+	// and a moved path may then equal another source path.
+	sources := map[string]bool{}
+	for _, entry := range entries {
+		sources[entry.TargetID] = true
+	}
+	targetOf := func(entry *types.KnowledgeACLEntry) string {
+		return types.NormalizeKnowledgeFolderPath(to + strings.TrimPrefix(entry.TargetID, from))
+	}
+	targets := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		targets = append(targets, targetOf(entry))
+	}
+	var occupied []string
+	if err := tx.Model(&types.KnowledgeACLEntry{}).
+		Where("tenant_id = ? AND knowledge_base_id = ? AND target_type = ? AND target_id IN ?",
+			tenantID, kbID, types.KnowledgeACLTargetFolder, targets).
+		Distinct().Pluck("target_id", &occupied).Error; err != nil {
+		return err
+	}
+	kept := map[string]bool{}
+	for _, path := range occupied {
+		if !sources[path] {
+			kept[path] = true
+		}
+	}
+
+	var dropped []string
+	moved := map[string][]string{}
+	for _, entry := range entries {
+		target := targetOf(entry)
+		if kept[target] {
+			dropped = append(dropped, entry.ID)
+			continue
+		}
+		moved[target] = append(moved[target], entry.ID)
+	}
+	if len(dropped) > 0 {
+		if err := tx.Where("id IN ?", dropped).Delete(&types.KnowledgeACLEntry{}).Error; err != nil {
+			return err
+		}
+	}
+	for target, ids := range moved {
+		if err := tx.Model(&types.KnowledgeACLEntry{}).
+			Where("id IN ?", ids).
+			Update("target_id", target).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
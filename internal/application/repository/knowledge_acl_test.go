@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func folderACLEntry(tenantID uint64, kbID, folderPath, userID string) *types.KnowledgeACLEntry {
+	return &types.KnowledgeACLEntry{
+		ID: uuid.NewString(), TenantID: tenantID, KnowledgeBaseID: kbID,
+		TargetType: types.KnowledgeACLTargetFolder, TargetID: folderPath,
+		PrincipalType: types.KnowledgeACLPrincipalUser, PrincipalID: userID,
+	}
+}
+
+func folderACLPrincipals(t *testing.T, db *gorm.DB, kbID, folderPath string) []string {
+	t.Helper()
+	entries, err := NewKnowledgeACLRepository(db).ListByTarget(
+		context.Background(), kbID, types.KnowledgeACLTargetFolder, folderPath)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.PrincipalID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestKnowledgeACLReplaceTargetAndListInFolders(t *testing.T) {
+	db := setupKnowledgeTestDB(t)
+	repo := NewKnowledgeACLRepository(db)
+	ctx := context.Background()
+
+	const tenantID = uint64(1)
+	kbID := uuid.NewString()
+
+	require.NoError(t, repo.ReplaceTarget(ctx, kbID, types.KnowledgeACLTargetFolder, "hr", []*types.KnowledgeACLEntry{
+		folderACLEntry(tenantID, kbID, "hr", "u1"), folderACLEntry(tenantID, kbID, "hr", "u2"),
+	}))
+	require.NoError(t, repo.ReplaceTarget(ctx, kbID, types.KnowledgeACLTargetFolder, "hr", []*types.KnowledgeACLEntry{
+		folderACLEntry(tenantID, kbID, "hr", "u3"),
+	}))
+	assert.Equal(t, []string{"u3"}, folderACLPrincipals(t, db, kbID, "hr"))
+
+	entries, err := repo.ListByKnowledgeBases(ctx, []string{kbID, uuid.NewString()})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	inside := insertKnowledgeInFolder(t, db, tenantID, kbID, "hr", "policy.md")
+	below := insertKnowledgeInFolder(t, db, tenantID, kbID, "hr/payroll", "2026.xlsx")
+	insertKnowledgeInFolder(t, db, tenantID, kbID, "hr_public", "holidays.md")
+	insertKnowledgeInFolder(t, db, tenantID, uuid.NewString(), "hr", "foreign.md")
+
+	rows, err := repo.ListKnowledgeInFolders(ctx, kbID, []string{"hr"})
+	require.NoError(t, err)
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	assert.ElementsMatch(t, []string{inside, below}, ids)
+
+	count, err := repo.CountByPrincipal(ctx, tenantID, types.KnowledgeACLPrincipalUser, "u3")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = repo.CountByPrincipal(ctx, tenantID+1, types.KnowledgeACLPrincipalUser, "u3")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestRenameKnowledgeFolderPathMovesFolderACLs(t *testing.T) {
+	db := setupKnowledgeTestDB(t)
+	repo := NewKnowledgeRepository(db).(*knowledgeRepository)
+	ctx := context.Background()
+
+	const tenantID = uint64(1)
+	kbID := uuid.NewString()
+
+	insertKnowledgeInFolder(t, db, tenantID, kbID, "docs/spec", "design.md")
+	insertKnowledgeInFolder(t, db, tenantID, kbID, "archive", "old.md")
+	require.NoError(t, db.Create([]*types.KnowledgeACLEntry{
+		folderACLEntry(tenantID, kbID, "docs", "u1"),
+		folderACLEntry(tenantID, kbID, "docs/spec", "u2"),
+		folderACLEntry(tenantID, kbID, "docsets", "u3"),
+		folderACLEntry(tenantID, kbID, "archive/spec", "u4"),
+	}).Error)
+
+	// The access lists of the folder and its subtree follow the rename; a
+	// destination that already has one keeps it.
+	_, err := repo.RenameKnowledgeFolderPath(ctx, tenantID, kbID, "docs", "archive")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, folderACLPrincipals(t, db, kbID, "archive"))
+	assert.Equal(t, []string{"u4"}, folderACLPrincipals(t, db, kbID, "archive/spec"))
+	assert.Equal(t, []string{"u3"}, folderACLPrincipals(t, db, kbID, "docsets"))
+	assert.Empty(t, folderACLPrincipals(t, db, kbID, "docs"))
+	assert.Empty(t, folderACLPrincipals(t, db, kbID, "docs/spec"))
+
+	// Renaming a folder into its parent can map one moved path onto another.
+	require.NoError(t, db.Create(folderACLEntry(tenantID, kbID, "archive/spec/spec", "u5")).Error)
+	_, err = repo.RenameKnowledgeFolderPath(ctx, tenantID, kbID, "archive/spec", "archive")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, folderACLPrincipals(t, db, kbID, "archive"))
+	assert.Equal(t, []string{"u5"}, folderACLPrincipals(t, db, kbID, "archive/spec"))
+}
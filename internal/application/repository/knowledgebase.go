@@ -172,6 +172,74 @@ func (r *knowledgeBaseRepository) UpdateKnowledgeBase(ctx context.Context, kb *t
 	return r.db.WithContext(ctx).Save(kb).Error
 }
 
+// UpdateEmbeddingMigration overwrites only the embedding_migration column.
+// The column is `<-:create` on the model, so the update goes through the bare
+// table; the explicit deleted_at predicate replaces the soft-delete scope
+// that Table() does not apply.
+func (r *knowledgeBaseRepository) UpdateEmbeddingMigration(
+	ctx context.Context, id string, migration *types.EmbeddingMigration,
+) error {
+	return r.db.WithContext(ctx).Table("knowledge_bases").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("embedding_migration", migration).Error
+}
+
+// SwitchEmbeddingIndex moves the knowledge base to a new embedding model and
+// index label in one transaction, re-stamping its documents with the new
+// model without touching their updated_at.
+func (r *knowledgeBaseRepository) SwitchEmbeddingIndex(
+	ctx context.Context, id string, modelID string, indexID string, migration *types.EmbeddingMigration,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("knowledge_bases").
+			Where("id = ? AND deleted_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"embedding_model_id":  modelID,
+				"embedding_index_id":  indexID,
+				"embedding_migration": migration,
+				"updated_at":          time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrKnowledgeBaseNotFound
+		}
+		return tx.Model(&types.Knowledge{}).
+			Where("knowledge_base_id = ?", id).
+			UpdateColumn("embedding_model_id", modelID).Error
+	})
+}
+
+// UpdateVectorStoreMigration overwrites only the vector_store_migration column.
+func (r *knowledgeBaseRepository) UpdateVectorStoreMigration(
+	ctx context.Context, id string, migration *types.VectorStoreMigration,
+) error {
+	return r.db.WithContext(ctx).Table("knowledge_bases").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("vector_store_migration", migration).Error
+}
+
+// SwitchVectorStore rebinds the knowledge base to storeID. vector_store_id is
+// `<-:create` at the GORM layer, so the cutover of a vector store migration
+// goes through raw SQL here and nowhere else.
+func (r *knowledgeBaseRepository) SwitchVectorStore(
+	ctx context.Context, id string, storeID string, migration *types.VectorStoreMigration,
+) error {
+	res := r.db.WithContext(ctx).Exec(
+		"UPDATE knowledge_bases SET vector_store_id = ?, vector_store_migration = ?, updated_at = ? "+
+			"WHERE id = ? AND deleted_at IS NULL",
+		storeID, migration, time.Now(), id,
+	)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrKnowledgeBaseNotFound
+	}
+	return nil
+}
+
 // DeleteKnowledgeBase deletes a knowledge base
 func (r *knowledgeBaseRepository) DeleteKnowledgeBase(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&types.KnowledgeBase{}).Error
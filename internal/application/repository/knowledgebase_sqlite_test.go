@@ -25,6 +25,9 @@ CREATE TABLE IF NOT EXISTS knowledge_bases (
     chunking_config TEXT NOT NULL DEFAULT '{}',
     image_processing_config TEXT NOT NULL DEFAULT '{}',
     embedding_model_id VARCHAR(64) NOT NULL,
+    embedding_index_id VARCHAR(36) NOT NULL DEFAULT '',
+    embedding_migration TEXT,
+    vector_store_migration TEXT,
     summary_model_id VARCHAR(64) NOT NULL,
     cos_config TEXT NOT NULL DEFAULT '{}',
     storage_provider_config TEXT DEFAULT NULL,
@@ -41,6 +44,7 @@ CREATE TABLE IF NOT EXISTS knowledge_bases (
     storage_backend_id VARCHAR(36),
     wiki_config TEXT,
     indexing_strategy TEXT,
+    fusion_config TEXT,
     creator_id VARCHAR(36),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -187,6 +191,38 @@ func TestKnowledgeBase_VectorStoreID_RawSQL_EscapeHatch(t *testing.T) {
 		"raw SQL UPDATE bypasses GORM tags; Phase 4 migration must use this path through a dedicated method")
 }
 
+// TestSwitchVectorStore_RebindsThroughRawSQL verifies the dedicated cutover
+// method of a vector store migration: it moves the binding despite
+// `<-:create`, persists the migration state including the resume cursor, and
+// reports a missing or soft-deleted knowledge base.
+func TestSwitchVectorStore_RebindsThroughRawSQL(t *testing.T) {
+	db := setupKBTestDB(t)
+	repo := &knowledgeBaseRepository{db: db}
+	ctx := t.Context()
+
+	kb := makeKB(nil)
+	require.NoError(t, db.Create(kb).Error)
+
+	require.NoError(t, repo.UpdateVectorStoreMigration(ctx, kb.ID, &types.VectorStoreMigration{
+		TargetStoreID: "store-B", Status: types.VectorStoreMigrationRunning, Cursor: "42",
+	}))
+	reloaded := reloadKB(t, db, kb.ID)
+	require.NotNil(t, reloaded.VectorStoreMigration)
+	assert.Equal(t, "42", reloaded.VectorStoreMigration.Cursor)
+	assert.Nil(t, reloaded.VectorStoreID)
+
+	require.NoError(t, repo.SwitchVectorStore(ctx, kb.ID, "store-B", &types.VectorStoreMigration{
+		TargetStoreID: "store-B", Status: types.VectorStoreMigrationCompleted,
+	}))
+	reloaded = reloadKB(t, db, kb.ID)
+	require.NotNil(t, reloaded.VectorStoreID)
+	assert.Equal(t, "store-B", *reloaded.VectorStoreID)
+	assert.Equal(t, types.VectorStoreMigrationCompleted, reloaded.VectorStoreMigration.Status)
+
+	require.NoError(t, db.Delete(kb).Error)
+	assert.ErrorIs(t, repo.SwitchVectorStore(ctx, kb.ID, "store-C", nil), ErrKnowledgeBaseNotFound)
+}
+
 // TestKnowledgeBase_VectorStoreID_Roundtrip_Nil verifies that a nil
 // VectorStoreID is persisted as SQL NULL and loaded back as nil.
 func TestKnowledgeBase_VectorStoreID_Roundtrip_Nil(t *testing.T) {
@@ -0,0 +1,53 @@
+package dbgraph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// Exporter is a graph store that can stream its contents per document. The
+// Neo4j repository implements it.
+type Exporter interface {
+	ExportGraphs(ctx context.Context, fn func(knowledgeID string, graph *types.GraphData) error) error
+}
+
+// ImportStats summarises an Import run.
+type ImportStats struct {
+	Documents int
+	Skipped   int
+	Nodes     int
+	Relations int
+}
+
+// Import copies every document graph held by src into the relational store.
+// The owning knowledge base is looked up from the knowledges table, and
+// graphs of documents that no longer exist are skipped. Import is safe to
+// re-run: rows already copied are left untouched.
+func (r *Repository) Import(ctx context.Context, src Exporter) (ImportStats, error) {
+	var stats ImportStats
+	err := src.ExportGraphs(ctx, func(knowledgeID string, graph *types.GraphData) error {
+		var kbIDs []string
+		if err := r.db.WithContext(ctx).Table("knowledges").
+			Where("id = ? AND deleted_at IS NULL", knowledgeID).
+			Limit(1).Pluck("knowledge_base_id", &kbIDs).Error; err != nil {
+			return fmt.Errorf("failed to resolve knowledge %s: %w", knowledgeID, err)
+		}
+		if len(kbIDs) == 0 {
+			stats.Skipped++
+			logger.Infof(ctx, "[GraphImport] skip graph of missing knowledge %s", knowledgeID)
+			return nil
+		}
+		namespace := types.NameSpace{KnowledgeBase: kbIDs[0], Knowledge: knowledgeID}
+		if err := r.AddGraph(ctx, namespace, []*types.GraphData{graph}); err != nil {
+			return fmt.Errorf("failed to import graph of knowledge %s: %w", knowledgeID, err)
+		}
+		stats.Documents++
+		stats.Nodes += len(graph.Node)
+		stats.Relations += len(graph.Relation)
+		return nil
+	})
+	return stats, err
+}
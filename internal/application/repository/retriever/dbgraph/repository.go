@@ -0,0 +1,328 @@
+// Package dbgraph stores the knowledge graph in the application's own
+// relational database (Postgres or SQLite). It is selected with
+// GRAPH_DRIVER=database and lets small deployments and the desktop app use
+// entity search without running Neo4j.
+package dbgraph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/graphwalk"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// insertBatchSize bounds rows per INSERT; SQLite caps bound parameters per
+// statement.
+const insertBatchSize = 200
+
+// Repository implements interfaces.RetrieveGraphRepository on top of gorm
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a relational graph repository
+func NewRepository(db *gorm.DB) interfaces.RetrieveGraphRepository {
+	return &Repository{db: db}
+}
+
+// AddGraph adds a graph to the repository. Writes are idempotent: a node or
+// relation already recorded for the same chunk is skipped.
+func (r *Repository) AddGraph(ctx context.Context, namespace types.NameSpace, graphs []*types.GraphData) error {
+	var nodes []graphNode
+	var relations []graphRelation
+	for _, graph := range graphs {
+		if graph == nil {
+			continue
+		}
+		for _, node := range graph.Node {
+			if node == nil || node.Name == "" {
+				continue
+			}
+			for _, chunkID := range chunkKeys(node.Chunks) {
+				nodes = append(nodes, graphNode{
+					KnowledgeBaseID: namespace.KnowledgeBase,
+					KnowledgeID:     namespace.Knowledge,
+					Name:            node.Name,
+					ChunkID:         chunkID,
+					Attributes:      stringArray(node.Attributes),
+				})
+			}
+		}
+		for _, rel := range graph.Relation {
+			if rel == nil || rel.Node1 == "" || rel.Node2 == "" {
+				continue
+			}
+			for _, chunkID := range chunkKeys(rel.Chunks) {
+				relations = append(relations, graphRelation{
+					KnowledgeBaseID: namespace.KnowledgeBase,
+					KnowledgeID:     namespace.Knowledge,
+					Source:          rel.Node1,
+					Target:          rel.Node2,
+					Type:            rel.Type,
+					ChunkID:         chunkID,
+				})
+				// Like apoc.merge.relationship, an edge creates its endpoints
+				// when the extractor did not list them as nodes.
+				for _, name := range []string{rel.Node1, rel.Node2} {
+					nodes = append(nodes, graphNode{
+						KnowledgeBaseID: namespace.KnowledgeBase,
+						KnowledgeID:     namespace.Knowledge,
+						Name:            name,
+						ChunkID:         chunkID,
+						Attributes:      stringArray{},
+					})
+				}
+			}
+		}
+	}
+	if len(nodes) == 0 && len(relations) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(nodes) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				CreateInBatches(&nodes, insertBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to create nodes: %w", err)
+			}
+		}
+		if len(relations) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				CreateInBatches(&relations, insertBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to create relationships: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf(ctx, "failed to add graph: %v", err)
+		return err
+	}
+	return nil
+}
+
+// DelGraph deletes the graphs of the given namespaces
+func (r *Repository) DelGraph(ctx context.Context, namespaces []types.NameSpace) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, namespace := range namespaces {
+			if namespace.KnowledgeBase == "" && namespace.Knowledge == "" {
+				continue
+			}
+			if err := scope(tx, namespace).Delete(&graphRelation{}).Error; err != nil {
+				return fmt.Errorf("failed to delete relationships: %w", err)
+			}
+			if err := scope(tx, namespace).Delete(&graphNode{}).Error; err != nil {
+				return fmt.Errorf("failed to delete nodes: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SearchNode returns the entities whose name contains any of nodes, together
+// with their one-hop relations and neighbours
+func (r *Repository) SearchNode(
+	ctx context.Context,
+	namespace types.NameSpace,
+	nodes []string,
+) (*types.GraphData, error) {
+	db := r.db.WithContext(ctx)
+	var conditions []string
+	var args []interface{}
+	for _, text := range nodes {
+		if text == "" {
+			continue
+		}
+		conditions = append(conditions, `name LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(text)+"%")
+	}
+	if len(conditions) == 0 {
+		return &types.GraphData{}, nil
+	}
+	var names []string
+	if err := scope(db.Model(&graphNode{}), namespace).
+		Where(strings.Join(conditions, " OR "), args...).
+		Distinct("name").Pluck("name", &names).Error; err != nil {
+		logger.Errorf(ctx, "search node failed: %v", err)
+		return nil, err
+	}
+	if len(names) == 0 {
+		return &types.GraphData{}, nil
+	}
+	graph, err := r.neighbors(ctx, namespace, names, nil)
+	if err != nil {
+		logger.Errorf(ctx, "search node failed: %v", err)
+		return nil, err
+	}
+	return graph, nil
+}
+
+// ExpandNode returns the k-hop neighbourhood of the named nodes
+func (r *Repository) ExpandNode(
+	ctx context.Context, namespace types.NameSpace, nodes []string, opts types.GraphTraversalOptions,
+) (*types.GraphData, error) {
+	return graphwalk.Expand(ctx, &namespaceStore{repo: r, namespace: namespace}, nodes, opts)
+}
+
+// ShortestPath returns a shortest path between two named nodes
+func (r *Repository) ShortestPath(
+	ctx context.Context, namespace types.NameSpace, source, target string, opts types.GraphTraversalOptions,
+) (*types.GraphPath, error) {
+	return graphwalk.ShortestPath(ctx, &namespaceStore{repo: r, namespace: namespace}, source, target, opts)
+}
+
+// neighbors returns the relations incident to any of names plus both
+// endpoints, folding the per-chunk rows back into merged nodes and edges.
+// Only nodes that take part in a relation are returned, matching the Neo4j
+// pattern (n)-[r]-(m).
+func (r *Repository) neighbors(
+	ctx context.Context, namespace types.NameSpace, names []string, relationTypes []string,
+) (*types.GraphData, error) {
+	db := r.db.WithContext(ctx)
+	query := scope(db.Model(&graphRelation{}), namespace).
+		Where("(source IN ? OR target IN ?)", names, names)
+	if len(relationTypes) > 0 {
+		query = query.Where("type IN ?", relationTypes)
+	}
+	var relRows []graphRelation
+	if err := query.Order("id").Find(&relRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+	if len(relRows) == 0 {
+		return &types.GraphData{}, nil
+	}
+
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, row := range relRows {
+		for _, name := range []string{row.Source, row.Target} {
+			if !seen[name] {
+				seen[name] = true
+				endpoints = append(endpoints, name)
+			}
+		}
+	}
+	var nodeRows []graphNode
+	if err := scope(db.Model(&graphNode{}), namespace).
+		Where("name IN ?", endpoints).Order("id").Find(&nodeRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	return fold(nodeRows, relRows), nil
+}
+
+// GetGraph returns every relation in the namespace together with its
+// endpoints, merged by entity name. It is meant for whole-graph analysis
+// such as community detection rather than per-query retrieval.
+func (r *Repository) GetGraph(ctx context.Context, namespace types.NameSpace) (*types.GraphData, error) {
+	db := r.db.WithContext(ctx)
+	var relRows []graphRelation
+	if err := scope(db.Model(&graphRelation{}), namespace).Order("id").Find(&relRows).Error; err != nil {
+		logger.Errorf(ctx, "get graph failed: %v", err)
+		return nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+	if len(relRows) == 0 {
+		return &types.GraphData{}, nil
+	}
+	var nodeRows []graphNode
+	if err := scope(db.Model(&graphNode{}), namespace).Order("id").Find(&nodeRows).Error; err != nil {
+		logger.Errorf(ctx, "get graph failed: %v", err)
+		return nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	connected := make(map[string]bool)
+	for _, row := range relRows {
+		connected[row.Source] = true
+		connected[row.Target] = true
+	}
+	nodeRows = slices.DeleteFunc(nodeRows, func(row graphNode) bool { return !connected[row.Name] })
+	return fold(nodeRows, relRows), nil
+}
+
+// fold merges per-chunk node and relation rows into one node per name and
+// one relation per (source, type, target).
+func fold(nodeRows []graphNode, relRows []graphRelation) *types.GraphData {
+	graph := &types.GraphData{}
+	nodeByName := make(map[string]*types.GraphNode)
+	for _, row := range nodeRows {
+		node, ok := nodeByName[row.Name]
+		if !ok {
+			node = &types.GraphNode{Name: row.Name}
+			nodeByName[row.Name] = node
+			graph.Node = append(graph.Node, node)
+		}
+		node.Chunks = appendUnique(node.Chunks, row.ChunkID)
+		for _, attr := range row.Attributes {
+			node.Attributes = appendUnique(node.Attributes, attr)
+		}
+	}
+
+	relByKey := make(map[string]*types.GraphRelation)
+	for _, row := range relRows {
+		key := row.Source + "\x00" + row.Type + "\x00" + row.Target
+		rel, ok := relByKey[key]
+		if !ok {
+			rel = &types.GraphRelation{Node1: row.Source, Node2: row.Target, Type: row.Type}
+			relByKey[key] = rel
+			graph.Relation = append(graph.Relation, rel)
+		}
+		rel.Chunks = appendUnique(rel.Chunks, row.ChunkID)
+	}
+	for _, rel := range graph.Relation {
+		var sourceChunks, targetChunks []string
+		if node := nodeByName[rel.Node1]; node != nil {
+			sourceChunks = node.Chunks
+		}
+		if node := nodeByName[rel.Node2]; node != nil {
+			targetChunks = node.Chunks
+		}
+		rel.Chunks = graphwalk.EvidenceChunks(rel.Chunks, sourceChunks, targetChunks)
+	}
+	return graph
+}
+
+// scope restricts a query to a namespace: the knowledge base, and the single
+// document when namespace.Knowledge is set (the Neo4j label semantics).
+func scope(db *gorm.DB, namespace types.NameSpace) *gorm.DB {
+	if namespace.KnowledgeBase != "" {
+		db = db.Where("knowledge_base_id = ?", namespace.KnowledgeBase)
+	}
+	if namespace.Knowledge != "" {
+		db = db.Where("knowledge_id = ?", namespace.Knowledge)
+	}
+	return db
+}
+
+// chunkKeys returns the chunk ids a row is written for; an observation
+// without chunks is still recorded once under the empty chunk id.
+func chunkKeys(chunks []string) []string {
+	var keys []string
+	for _, id := range chunks {
+		keys = appendUnique(keys, id)
+	}
+	if len(keys) == 0 {
+		return []string{""}
+	}
+	return keys
+}
+
+func appendUnique(list []string, v string) []string {
+	if v == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,200 @@
+package dbgraph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*Repository, *gorm.DB) {
+	t.Helper()
+	dbName := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(gormsqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", dbName)), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sqlDB.Close()) })
+	require.NoError(t, db.AutoMigrate(&graphNode{}, &graphRelation{}))
+	return NewRepository(db).(*Repository), db
+}
+
+func chunkGraph(chunkID string, rels ...[3]string) *types.GraphData {
+	g := &types.GraphData{}
+	for _, r := range rels {
+		g.Node = append(g.Node,
+			&types.GraphNode{Name: r[0], Chunks: []string{chunkID}, Attributes: []string{r[0] + " attr"}},
+			&types.GraphNode{Name: r[2], Chunks: []string{chunkID}})
+		g.Relation = append(g.Relation,
+			&types.GraphRelation{Node1: r[0], Type: r[1], Node2: r[2], Chunks: []string{chunkID}})
+	}
+	return g
+}
+
+func nodeNames(g *types.GraphData) []string {
+	var names []string
+	for _, n := range g.Node {
+		names = append(names, n.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestAddGraphMergesChunksAcrossWrites(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	ns := types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc1"}
+
+	// Two chunks of the same document are extracted independently, and the
+	// first one is retried.
+	for _, g := range []*types.GraphData{
+		chunkGraph("c1", [3]string{"Incident Y", "caused_by", "Pump 7"}),
+		chunkGraph("c2", [3]string{"Incident Y", "caused_by", "Pump 7"}),
+		chunkGraph("c1", [3]string{"Incident Y", "caused_by", "Pump 7"}),
+	} {
+		require.NoError(t, repo.AddGraph(ctx, ns, []*types.GraphData{g}))
+	}
+
+	g, err := repo.SearchNode(ctx, types.NameSpace{KnowledgeBase: "kb1"}, []string{"Incident"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Incident Y", "Pump 7"}, nodeNames(g))
+	require.Len(t, g.Relation, 1)
+	rel := g.Relation[0]
+	require.Equal(t, "Incident Y", rel.Node1)
+	require.Equal(t, []string{"c1", "c2"}, rel.Chunks)
+	for _, n := range g.Node {
+		require.Equal(t, []string{"c1", "c2"}, n.Chunks)
+	}
+}
+
+func TestSearchNodeIsScopedToNamespace(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc1"},
+		[]*types.GraphData{chunkGraph("c1", [3]string{"Supplier X", "supplies", "Part P"})}))
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb2", Knowledge: "doc2"},
+		[]*types.GraphData{chunkGraph("c2", [3]string{"Supplier X", "audited_by", "Auditor A"})}))
+
+	g, err := repo.SearchNode(ctx, types.NameSpace{KnowledgeBase: "kb1"}, []string{"Supplier"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Part P", "Supplier X"}, nodeNames(g))
+
+	g, err = repo.SearchNode(ctx, types.NameSpace{KnowledgeBase: "kb2", Knowledge: "doc1"}, []string{"Supplier"})
+	require.NoError(t, err)
+	require.Empty(t, g.Node)
+
+	// LIKE wildcards in the query are matched literally.
+	g, err = repo.SearchNode(ctx, types.NameSpace{KnowledgeBase: "kb1"}, []string{"%"})
+	require.NoError(t, err)
+	require.Empty(t, g.Node)
+}
+
+func TestShortestPathCrossesDocuments(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc1"},
+		[]*types.GraphData{chunkGraph("c1",
+			[3]string{"Supplier X", "supplies", "Part P"},
+			[3]string{"Part P", "installed_in", "Pump 7"})}))
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc2"},
+		[]*types.GraphData{chunkGraph("c2", [3]string{"Incident Y", "caused_by", "Pump 7"})}))
+
+	p, err := repo.ShortestPath(ctx, types.NameSpace{KnowledgeBase: "kb1"}, "supplier x", "incident",
+		types.GraphTraversalOptions{MaxHops: 4})
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	require.Equal(t, 3, p.Hops())
+	require.Equal(t, "Incident Y", p.Nodes[3].Name)
+	require.Equal(t, []string{"c2"}, p.Relations[2].Chunks)
+
+	g, err := repo.ExpandNode(ctx, types.NameSpace{KnowledgeBase: "kb1"}, []string{"Pump 7"},
+		types.GraphTraversalOptions{MaxHops: 1, RelationTypes: []string{"caused_by"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Incident Y", "Pump 7"}, nodeNames(g))
+}
+
+func TestGetGraphMergesDocumentsOfKnowledgeBase(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc1"},
+		[]*types.GraphData{chunkGraph("c1", [3]string{"Supplier X", "supplies", "Part P"})}))
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc2"},
+		[]*types.GraphData{chunkGraph("c2",
+			[3]string{"Supplier X", "supplies", "Part P"},
+			[3]string{"Part P", "installed_in", "Pump 7"})}))
+	require.NoError(t, repo.AddGraph(ctx, types.NameSpace{KnowledgeBase: "kb2", Knowledge: "doc3"},
+		[]*types.GraphData{chunkGraph("c3", [3]string{"Auditor A", "audits", "Supplier X"})}))
+
+	g, err := repo.GetGraph(ctx, types.NameSpace{KnowledgeBase: "kb1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Part P", "Pump 7", "Supplier X"}, nodeNames(g))
+	require.Len(t, g.Relation, 2)
+	for _, rel := range g.Relation {
+		if rel.Type == "supplies" {
+			require.Equal(t, []string{"c1", "c2"}, rel.Chunks)
+		}
+	}
+}
+
+func TestDelGraphRemovesOnlyThatDocument(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	doc1 := types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc1"}
+	doc2 := types.NameSpace{KnowledgeBase: "kb1", Knowledge: "doc2"}
+	require.NoError(t, repo.AddGraph(ctx, doc1,
+		[]*types.GraphData{chunkGraph("c1", [3]string{"A", "r", "B"})}))
+	require.NoError(t, repo.AddGraph(ctx, doc2,
+		[]*types.GraphData{chunkGraph("c2", [3]string{"C", "r", "D"})}))
+
+	require.NoError(t, repo.DelGraph(ctx, []types.NameSpace{doc1}))
+
+	var remaining []string
+	require.NoError(t, db.Model(&graphNode{}).Distinct("knowledge_id").Pluck("knowledge_id", &remaining).Error)
+	require.Equal(t, []string{"doc2"}, remaining)
+	var rels int64
+	require.NoError(t, db.Model(&graphRelation{}).Count(&rels).Error)
+	require.EqualValues(t, 1, rels)
+}
+
+type fakeExporter map[string]*types.GraphData
+
+func (f fakeExporter) ExportGraphs(_ context.Context, fn func(string, *types.GraphData) error) error {
+	for _, id := range []string{"doc1", "gone"} {
+		if err := fn(id, f[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestImportResolvesKnowledgeBaseAndIsIdempotent(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(
+		`CREATE TABLE knowledges (id TEXT PRIMARY KEY, knowledge_base_id TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO knowledges (id, knowledge_base_id) VALUES ('doc1', 'kb1')`).Error)
+
+	src := fakeExporter{
+		"doc1": chunkGraph("c1", [3]string{"A", "r", "B"}),
+		"gone": chunkGraph("c9", [3]string{"X", "r", "Y"}),
+	}
+	for i := 0; i < 2; i++ {
+		stats, err := repo.Import(ctx, src)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.Documents)
+		require.Equal(t, 1, stats.Skipped)
+	}
+
+	g, err := repo.SearchNode(ctx, types.NameSpace{KnowledgeBase: "kb1"}, []string{"A"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, nodeNames(g))
+	var rels int64
+	require.NoError(t, db.Model(&graphRelation{}).Count(&rels).Error)
+	require.EqualValues(t, 1, rels)
+}
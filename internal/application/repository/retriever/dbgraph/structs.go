@@ -0,0 +1,76 @@
+package dbgraph
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// graphNode is one (entity, chunk) observation. An entity mentioned by three
+// chunks of a document is stored as three rows; readers fold them back into
+// a single types.GraphNode. Keeping the chunk in the key makes writes
+// insert-only, so concurrent chunk extraction for the same document never
+// has to read-modify-write a shared chunk list.
+type graphNode struct {
+	ID              uint        `gorm:"primarykey;autoIncrement"`
+	KnowledgeBaseID string      `gorm:"column:knowledge_base_id;not null;index:idx_graph_nodes_kb_name"`
+	KnowledgeID     string      `gorm:"column:knowledge_id;not null;uniqueIndex:idx_graph_nodes_observation"`
+	Name            string      `gorm:"column:name;not null;uniqueIndex:idx_graph_nodes_observation;index:idx_graph_nodes_kb_name"`
+	ChunkID         string      `gorm:"column:chunk_id;not null;default:'';uniqueIndex:idx_graph_nodes_observation"`
+	Attributes      stringArray `gorm:"column:attributes"`
+	CreatedAt       time.Time   `gorm:"column:created_at"`
+}
+
+// TableName specifies the database table name for graphNode
+func (graphNode) TableName() string { return "graph_nodes" }
+
+// graphRelation is one (edge, chunk) observation, stored in the direction it
+// was extracted.
+type graphRelation struct {
+	ID              uint      `gorm:"primarykey;autoIncrement"`
+	KnowledgeBaseID string    `gorm:"column:knowledge_base_id;not null;index:idx_graph_relations_kb_source;index:idx_graph_relations_kb_target"`
+	KnowledgeID     string    `gorm:"column:knowledge_id;not null;uniqueIndex:idx_graph_relations_observation"`
+	Source          string    `gorm:"column:source;not null;uniqueIndex:idx_graph_relations_observation;index:idx_graph_relations_kb_source"`
+	Type            string    `gorm:"column:type;not null;uniqueIndex:idx_graph_relations_observation"`
+	Target          string    `gorm:"column:target;not null;uniqueIndex:idx_graph_relations_observation;index:idx_graph_relations_kb_target"`
+	ChunkID         string    `gorm:"column:chunk_id;not null;default:'';uniqueIndex:idx_graph_relations_observation"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+}
+
+// TableName specifies the database table name for graphRelation
+func (graphRelation) TableName() string { return "graph_relations" }
+
+// stringArray is a JSON-encoded string list. Postgres (jsonb) hands back
+// []byte while SQLite (TEXT) hands back string, so Scan accepts both.
+type stringArray []string
+
+// Value implements the driver.Valuer interface
+func (a stringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (a *stringArray) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return nil
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(a))
+}
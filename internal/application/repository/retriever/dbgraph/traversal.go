@@ -0,0 +1,67 @@
+package dbgraph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/graphwalk"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// namespaceStore adapts the repository to graphwalk.Store for one namespace.
+type namespaceStore struct {
+	repo      *Repository
+	namespace types.NameSpace
+}
+
+// ResolveNodes prefers exact (case-insensitive) name matches and falls back
+// to substring matches, mirroring the Neo4j backend.
+func (s *namespaceStore) ResolveNodes(ctx context.Context, mentions []string) ([]string, error) {
+	db := s.repo.db.WithContext(ctx)
+	var names []string
+	seen := make(map[string]bool)
+	for _, mention := range mentions {
+		mention = strings.TrimSpace(mention)
+		if mention == "" {
+			continue
+		}
+		var matched []string
+		err := scope(db.Model(&graphNode{}), s.namespace).
+			Where(`LOWER(name) LIKE LOWER(?) ESCAPE '\'`, "%"+escapeLike(mention)+"%").
+			Group("name").
+			Order("LENGTH(name)").Order("name").
+			Limit(graphwalk.MaxResolvedNodes).
+			Pluck("name", &matched).Error
+		if err != nil {
+			logger.Errorf(ctx, "resolve graph nodes failed: %v", err)
+			return nil, err
+		}
+		for _, name := range matched {
+			if strings.EqualFold(name, mention) {
+				// An exact hit is unambiguous; ignore looser matches.
+				matched = []string{name}
+				break
+			}
+		}
+		for _, name := range matched {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// Neighbors returns the one-hop edges of the named nodes
+func (s *namespaceStore) Neighbors(
+	ctx context.Context, names []string, relationTypes []string,
+) (*types.GraphData, error) {
+	graph, err := s.repo.neighbors(ctx, s.namespace, names, relationTypes)
+	if err != nil {
+		logger.Errorf(ctx, "graph neighbour query failed: %v", err)
+		return nil, err
+	}
+	return graph, nil
+}
@@ -245,6 +245,13 @@ func (r *dorisRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return r.deleteByField(ctx, fieldKnowledgeID, knowledgeIDList, dimension)
 }
 
+// DeleteByKnowledgeBaseIDList 用 knowledge_base_id 列删除。
+func (r *dorisRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, _ string,
+) error {
+	return r.deleteByField(ctx, fieldKnowledgeBaseID, knowledgeBaseIDList, dimension)
+}
+
 // DeleteBySourceIDList 用 source_id 列删除。
 func (r *dorisRepository) DeleteBySourceIDList(ctx context.Context,
 	sourceIDList []string, dimension int, _ string,
@@ -284,6 +291,10 @@ func (r *dorisRepository) deleteByField(ctx context.Context,
 func (r *dorisRepository) Retrieve(ctx context.Context,
 	params types.RetrieveParams,
 ) ([]*types.RetrieveResult, error) {
+	// Doris 表未存储文档元数据，拒绝元数据过滤而不是静默忽略。
+	if params.MetadataFilter != nil {
+		return nil, fmt.Errorf("doris retriever does not support metadata filters")
+	}
 	switch params.RetrieverType {
 	case types.VectorRetrieverType:
 		return r.VectorRetrieve(ctx, params)
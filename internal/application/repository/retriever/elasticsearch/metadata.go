@@ -0,0 +1,62 @@
+package elasticsearch
+
+import (
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// MetadataProperty is the document property holding IndexInfo.Metadata.
+const MetadataProperty = "metadata"
+
+// MetadataDynamicTemplates maps the fields under MetadataProperty by their
+// kind prefix (see types.MetadataField): strings as keyword, numbers and
+// dates as double, booleans as boolean. Without them the first value
+// indexed would decide the type, mapping strings as analysed text and whole
+// numbers as long. The same DSL is valid for Elasticsearch and OpenSearch.
+func MetadataDynamicTemplates() []map[string]any {
+	template := func(kind types.MetadataValueKind, fieldType string) map[string]any {
+		return map[string]any{
+			"weknora_metadata_" + string(kind): map[string]any{
+				"path_match": MetadataProperty + "." + string(kind) + "_*",
+				"mapping":    map[string]any{"type": fieldType},
+			},
+		}
+	}
+	return []map[string]any{
+		template(types.MetadataKindString, "keyword"),
+		template(types.MetadataKindNumber, "double"),
+		template(types.MetadataKindDate, "double"),
+		template(types.MetadataKindBool, "boolean"),
+	}
+}
+
+// MetadataFilterQuery translates a normalised metadata filter into query
+// DSL. A node it does not recognise matches nothing, so a filter is never
+// silently dropped.
+func MetadataFilterQuery(f *types.MetadataFilter) map[string]any {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		clauses := make([]map[string]any, 0, len(f.Filters))
+		for _, child := range f.Filters {
+			clauses = append(clauses, MetadataFilterQuery(child))
+		}
+		occur := "filter"
+		boolQuery := map[string]any{}
+		if f.Op == types.MetadataFilterOr {
+			occur = "should"
+			boolQuery["minimum_should_match"] = 1
+		}
+		boolQuery[occur] = clauses
+		return map[string]any{"bool": boolQuery}
+	}
+
+	field := MetadataProperty + "." + f.Field
+	switch f.Op {
+	case types.MetadataFilterEq:
+		return map[string]any{"term": map[string]any{field: f.Value}}
+	case types.MetadataFilterIn:
+		return map[string]any{"terms": map[string]any{field: f.Values}}
+	case types.MetadataFilterGt, types.MetadataFilterGte, types.MetadataFilterLt, types.MetadataFilterLte:
+		return map[string]any{"range": map[string]any{field: map[string]any{string(f.Op): f.Value}}}
+	}
+	return map[string]any{"match_none": map[string]any{}}
+}
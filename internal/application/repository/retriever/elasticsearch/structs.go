@@ -19,6 +19,8 @@ type VectorEmbedding struct {
 	Embedding       []float32 `json:"embedding"         gorm:"column:embedding;not null"`   // Vector embedding of the content
 	IsEnabled       bool      `json:"is_enabled"`                                           // Whether the chunk is enabled
 	IsRecommended   bool      `json:"is_recommended"`                                       // Whether the chunk is recommended
+	// Custom metadata of the document, see IndexInfo.Metadata
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // VectorEmbeddingWithScore extends VectorEmbedding with similarity score
@@ -39,6 +41,7 @@ func ToDBVectorEmbedding(embedding *types.IndexInfo, additionalParams map[string
 		TagID:           embedding.TagID,
 		IsEnabled:       embedding.IsEnabled,
 		IsRecommended:   embedding.IsRecommended,
+		Metadata:        embedding.Metadata,
 	}
 	// Add embedding data if available in additionalParams
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), "embedding") {
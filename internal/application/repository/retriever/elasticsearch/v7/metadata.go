@@ -0,0 +1,79 @@
+package v7
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+// ensureMetadataMapping adds the metadata dynamic templates to the index.
+// Indices created before metadata filtering have no metadata fields yet, so
+// the templates apply to every field written from now on.
+func (e *elasticsearchRepository) ensureMetadataMapping(ctx context.Context) {
+	log := logger.GetLogger(ctx)
+	body, err := json.Marshal(map[string]interface{}{
+		"dynamic_templates": elasticsearchRetriever.MetadataDynamicTemplates(),
+	})
+	if err != nil {
+		return
+	}
+	res, err := esapi.IndicesPutMappingRequest{
+		Index: []string{e.index},
+		Body:  bytes.NewReader(body),
+	}.Do(ctx, e.client)
+	if err != nil {
+		log.Warnf("[ElasticsearchV7] Failed to add metadata mapping to index %s: %v", e.index, err)
+		return
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		log.Warnf("[ElasticsearchV7] Failed to add metadata mapping to index %s: %s", e.index, res.String())
+	}
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata of every document of
+// each knowledge using update_by_query.
+func (e *elasticsearchRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	for knowledgeID, fields := range knowledgeMetadata {
+		query := map[string]interface{}{
+			"query": map[string]interface{}{
+				"term": map[string]interface{}{
+					e.idField("knowledge_id"): knowledgeID,
+				},
+			},
+			"script": map[string]interface{}{
+				"source": "ctx._source.metadata = params.metadata",
+				"lang":   "painless",
+				"params": map[string]interface{}{
+					"metadata": fields,
+				},
+			},
+		}
+		queryJSON, err := json.Marshal(query)
+		if err != nil {
+			return err
+		}
+		res, err := esapi.UpdateByQueryRequest{
+			Index: []string{e.index},
+			Body:  bytes.NewReader(queryJSON),
+		}.Do(ctx, e.client)
+		if err != nil {
+			log.Errorf("[ElasticsearchV7] Failed to update metadata of knowledge %s: %v", knowledgeID, err)
+			return err
+		}
+		res.Body.Close()
+		if res.IsError() {
+			return fmt.Errorf("elasticsearch update_by_query failed with status: %d", res.StatusCode)
+		}
+	}
+	return nil
+}
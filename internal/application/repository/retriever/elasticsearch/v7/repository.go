@@ -52,6 +52,8 @@ func NewElasticsearchEngineRepository(client *elasticsearch.Client,
 	}
 	if err := res.createIndexIfNotExists(context.Background()); err != nil {
 		log.Errorf("[ElasticsearchV7] Failed to create index: %v", err)
+	} else {
+		res.ensureMetadataMapping(context.Background())
 	}
 	res.detectFieldTypes(context.Background())
 	return res
@@ -439,6 +441,13 @@ func (e *elasticsearchRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return e.deleteByFieldList(ctx, e.idField("knowledge_id"), knowledgeIDList)
 }
 
+// DeleteByKnowledgeBaseIDList Delete indices by knowledge base IDs
+func (e *elasticsearchRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	return e.deleteByFieldList(ctx, e.idField("knowledge_base_id"), knowledgeBaseIDList)
+}
+
 // deleteByFieldList Delete documents by field value list
 func (e *elasticsearchRepository) deleteByFieldList(ctx context.Context, field string, valueList []string) error {
 	log := logger.GetLogger(ctx)
@@ -524,6 +533,10 @@ func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams)
 		})
 	}
 
+	if params.MetadataFilter != nil {
+		must = append(must, elasticsearchRetriever.MetadataFilterQuery(params.MetadataFilter))
+	}
+
 	// Build MUST_NOT conditions (negative filters)
 	mustNot := make([]map[string]interface{}, 0)
 	// Exclude disabled chunks (is_enabled = false)
@@ -1255,6 +1268,9 @@ func (e *elasticsearchRepository) processSingleHit(ctx context.Context,
 		IsRecommended:   isRecommended,
 		TagID:           tagID,
 	}
+	if metadata, ok := sourceObj["metadata"].(map[string]interface{}); ok {
+		indexInfo.Metadata = metadata
+	}
 
 	return indexInfo, embedding, nil
 }
@@ -0,0 +1,98 @@
+package v8
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
+	"github.com/Tencent/WeKnora/internal/logger"
+	typesLocal "github.com/Tencent/WeKnora/internal/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// ExportIndices pages through the documents of a knowledge base label with
+// search_after, sorted by source and chunk ID (sorting on _id is disabled in
+// Elasticsearch 8). The cursor is the JSON-encoded sort values of the last
+// exported document.
+func (e *elasticsearchRepository) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*typesLocal.IndexExportPage, error) {
+	req := e.client.Search().Index(e.index).
+		Query(e.knowledgeBaseQuery(knowledgeBaseID)).
+		Sort(e.idField("source_id"), "source_type", e.idField("chunk_id")).
+		Size(limit)
+	if cursor != "" {
+		var after []types.FieldValue
+		if err := json.Unmarshal([]byte(cursor), &after); err != nil {
+			return nil, fmt.Errorf("invalid export cursor: %w", err)
+		}
+		req = req.SearchAfter(after...)
+	}
+	resp, err := req.Do(ctx)
+	if err != nil {
+		logger.GetLogger(ctx).Errorf("[Elasticsearch] Failed to export documents: %v", err)
+		return nil, err
+	}
+
+	hits := resp.Hits.Hits
+	page := &typesLocal.IndexExportPage{
+		Entries:    make([]*typesLocal.IndexInfo, 0, len(hits)),
+		Embeddings: make(map[string][]float32, len(hits)),
+	}
+	for _, hit := range hits {
+		var doc elasticsearchRetriever.VectorEmbedding
+		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
+			return nil, fmt.Errorf("parse document: %w", err)
+		}
+		info := &typesLocal.IndexInfo{
+			Content:         doc.Content,
+			SourceID:        doc.SourceID,
+			SourceType:      typesLocal.SourceType(doc.SourceType),
+			ChunkID:         doc.ChunkID,
+			KnowledgeID:     doc.KnowledgeID,
+			KnowledgeBaseID: doc.KnowledgeBaseID,
+			KnowledgeType:   knowledgeType,
+			TagID:           doc.TagID,
+			IsEnabled:       doc.IsEnabled,
+			IsRecommended:   doc.IsRecommended,
+			Metadata:        doc.Metadata,
+		}
+		if hit.Id_ != nil {
+			info.ID = *hit.Id_
+		}
+		page.Entries = append(page.Entries, info)
+		if len(doc.Embedding) > 0 {
+			page.Embeddings[doc.SourceID] = doc.Embedding
+		}
+	}
+	if len(hits) == limit {
+		next, err := json.Marshal(hits[len(hits)-1].Sort)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = string(next)
+	}
+	return page, nil
+}
+
+// CountIndices counts the documents of a knowledge base label. The index is
+// refreshed first so documents written just before are counted.
+func (e *elasticsearchRepository) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	if _, err := e.client.Indices.Refresh().Index(e.index).Do(ctx); err != nil {
+		return 0, fmt.Errorf("refresh index: %w", err)
+	}
+	resp, err := e.client.Count().Index(e.index).Query(e.knowledgeBaseQuery(knowledgeBaseID)).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+func (e *elasticsearchRepository) knowledgeBaseQuery(knowledgeBaseID string) *types.Query {
+	return &types.Query{Term: map[string]types.TermQuery{
+		e.idField("knowledge_base_id"): {Value: knowledgeBaseID},
+	}}
+}
@@ -0,0 +1,73 @@
+package v8
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
+	"github.com/Tencent/WeKnora/internal/logger"
+	typesLocal "github.com/Tencent/WeKnora/internal/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+)
+
+// ensureMetadataMapping adds the metadata dynamic templates to the index.
+// Indices created before metadata filtering have no metadata fields yet, so
+// the templates apply to every field written from now on.
+func (e *elasticsearchRepository) ensureMetadataMapping(ctx context.Context) {
+	body, err := json.Marshal(map[string]any{
+		"dynamic_templates": elasticsearchRetriever.MetadataDynamicTemplates(),
+	})
+	if err != nil {
+		return
+	}
+	if _, err := e.client.Indices.PutMapping(e.index).Raw(bytes.NewReader(body)).Do(ctx); err != nil {
+		logger.GetLogger(ctx).Warnf("[Elasticsearch] Failed to add metadata mapping to index %s: %v", e.index, err)
+	}
+}
+
+// metadataQuery converts the metadata filter DSL into a typed query. The
+// DSL is built from a normalised filter and always decodes; should it not,
+// the query matches nothing rather than dropping the filter.
+func metadataQuery(f *typesLocal.MetadataFilter) types.Query {
+	var query types.Query
+	raw, err := json.Marshal(elasticsearchRetriever.MetadataFilterQuery(f))
+	if err == nil {
+		err = json.Unmarshal(raw, &query)
+	}
+	if err != nil {
+		return types.Query{MatchNone: types.NewMatchNoneQuery()}
+	}
+	return query
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata of every document of
+// each knowledge using update_by_query.
+func (e *elasticsearchRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	source := "ctx._source.metadata = params.metadata"
+	lang := scriptlanguage.Painless
+	for knowledgeID, fields := range knowledgeMetadata {
+		metadata, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		query := types.Query{Term: map[string]types.TermQuery{
+			e.idField("knowledge_id"): {Value: knowledgeID},
+		}}
+		script := types.Script{
+			Source: &source,
+			Lang:   &lang,
+			Params: map[string]json.RawMessage{"metadata": metadata},
+		}
+		if _, err := e.client.UpdateByQuery(e.index).Query(&query).Script(&script).Do(ctx); err != nil {
+			log.Errorf("[Elasticsearch] Failed to update metadata of knowledge %s: %v", knowledgeID, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -48,6 +48,7 @@ func NewElasticsearchEngineRepository(client *elasticsearch.TypedClient,
 	if err := res.createIndexIfNotExists(context.Background()); err != nil {
 		log.Errorf("[Elasticsearch] Failed to create index: %v", err)
 	} else {
+		res.ensureMetadataMapping(context.Background())
 		log.Info("[Elasticsearch] Successfully initialized repository")
 	}
 	res.detectFieldTypes(context.Background())
@@ -169,8 +170,13 @@ func (e *elasticsearchRepository) Save(ctx context.Context,
 		return err
 	}
 
-	// Index the document
-	resp, err := e.client.Index(e.index).Request(embeddingDB).Do(ctx)
+	// Index the document; a caller-assigned ID makes re-saving the entry
+	// overwrite it instead of adding a duplicate.
+	req := e.client.Index(e.index).Request(embeddingDB)
+	if embedding.ID != "" {
+		req = req.Id(embedding.ID)
+	}
+	resp, err := req.Do(ctx)
 	if err != nil {
 		log.Errorf("[Elasticsearch] Failed to save index: %v", err)
 		return err
@@ -197,7 +203,12 @@ func (e *elasticsearchRepository) BatchSave(ctx context.Context,
 	// Add each document to the bulk request
 	for _, embedding := range embeddingList {
 		embeddingDB := elasticsearchRetriever.ToDBVectorEmbedding(embedding, additionalParams)
-		err := indexRequest.CreateOp(types.CreateOperation{Index_: &e.index}, embeddingDB)
+		op := types.CreateOperation{Index_: &e.index}
+		if embedding.ID != "" {
+			// A caller-assigned ID makes a repeated create a no-op.
+			op.Id_ = &embedding.ID
+		}
+		err := indexRequest.CreateOp(op, embeddingDB)
 		if err != nil {
 			log.Errorf("[Elasticsearch] Failed to create bulk operation: %v", err)
 			return fmt.Errorf("failed to create op: %w", err)
@@ -287,6 +298,31 @@ func (e *elasticsearchRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return nil
 }
 
+// DeleteByKnowledgeBaseIDList deletes indices by knowledge base IDs
+func (e *elasticsearchRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeBaseIDList) == 0 {
+		log.Warn("[Elasticsearch] Empty knowledge base ID list provided for deletion, skipping")
+		return nil
+	}
+
+	log.Infof("[Elasticsearch] Deleting indices by knowledge base IDs, count: %d", len(knowledgeBaseIDList))
+	_, err := e.client.DeleteByQuery(e.index).Query(&types.Query{
+		Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{
+			e.idField("knowledge_base_id"): knowledgeBaseIDList,
+		}},
+	}).Do(ctx)
+	if err != nil {
+		log.Errorf("[Elasticsearch] Failed to delete by knowledge base IDs: %v", err)
+		return fmt.Errorf("failed to delete by query: %w", err)
+	}
+
+	log.Infof("[Elasticsearch] Successfully deleted documents by knowledge base IDs")
+	return nil
+}
+
 // getBaseConds creates the base query conditions for retrieval operations
 // Returns a slice of Query objects with must and must_not conditions
 // KnowledgeBaseIDs and KnowledgeIDs use AND logic (search specific documents within knowledge bases)
@@ -320,6 +356,10 @@ func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams)
 		}})
 	}
 
+	if params.MetadataFilter != nil {
+		must = append(must, metadataQuery(params.MetadataFilter))
+	}
+
 	mustNot := make([]types.Query, 0)
 	// Exclude disabled chunks (is_enabled = false)
 	// Note: Historical data without is_enabled field will be included (not matching must_not)
@@ -651,6 +691,7 @@ func (e *elasticsearchRepository) CopyIndices(ctx context.Context,
 				ChunkID:         targetChunkID,
 				KnowledgeID:     targetKnowledgeID,
 				KnowledgeBaseID: targetKnowledgeBaseID,
+				Metadata:        sourceDoc.Metadata,
 			}
 
 			indexInfoList = append(indexInfoList, indexInfo)
@@ -0,0 +1,291 @@
+// Package graphwalk implements multi-hop traversal (k-hop expansion and
+// shortest path) on top of a graph store's one-hop neighbour lookup.
+//
+// Entities are identified by name rather than by backend node id: the same
+// entity extracted from two documents is stored as two nodes, and walking by
+// name is what lets a path cross from one document into another.
+package graphwalk
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// MaxResolvedNodes bounds how many stored entities one free-text mention may
+// resolve to in Store.ResolveNodes, so a short mention such as "A" cannot
+// seed the whole graph.
+const MaxResolvedNodes = 10
+
+// Store is the per-namespace lookup a graph backend provides.
+type Store interface {
+	// ResolveNodes maps free-text entity mentions to stored node names.
+	ResolveNodes(ctx context.Context, mentions []string) ([]string, error)
+	// Neighbors returns the relations incident to any of the named nodes
+	// (exact match) plus the nodes on both ends, restricted to relationTypes
+	// when non-empty.
+	Neighbors(ctx context.Context, names []string, relationTypes []string) (*types.GraphData, error)
+}
+
+// Expand returns the opts.MaxHops neighbourhood of the entities mentioned in
+// seeds. Same-named nodes are merged and duplicate relations are folded
+// together with their evidence chunks.
+func Expand(
+	ctx context.Context, store Store, seeds []string, opts types.GraphTraversalOptions,
+) (*types.GraphData, error) {
+	opts = opts.Normalize()
+	names, err := store.ResolveNodes(ctx, seeds)
+	if err != nil {
+		return nil, err
+	}
+	g := newAccumulator()
+	frontier := make([]string, 0, len(names))
+	for _, name := range names {
+		if g.visit(name) {
+			frontier = append(frontier, name)
+		}
+	}
+	for hop := 0; hop < opts.MaxHops && len(frontier) > 0; hop++ {
+		data, err := store.Neighbors(ctx, frontier, opts.RelationTypes)
+		if err != nil {
+			return nil, err
+		}
+		g.addNodes(data)
+		var next []string
+		for _, rel := range relations(data) {
+			for _, end := range []string{rel.Node1, rel.Node2} {
+				if g.visited[end] {
+					continue
+				}
+				if len(g.visited) >= opts.MaxNodes {
+					break
+				}
+				g.visit(end)
+				next = append(next, end)
+			}
+			if g.visited[rel.Node1] && g.visited[rel.Node2] {
+				g.addRelation(rel)
+			}
+		}
+		frontier = next
+	}
+	return g.graph(), nil
+}
+
+// ShortestPath returns a shortest path from any entity matching source to any
+// entity matching target within opts.MaxHops, or nil when none exists.
+func ShortestPath(
+	ctx context.Context, store Store, source, target string, opts types.GraphTraversalOptions,
+) (*types.GraphPath, error) {
+	opts = opts.Normalize()
+	sources, err := store.ResolveNodes(ctx, []string{source})
+	if err != nil || len(sources) == 0 {
+		return nil, err
+	}
+	targets, err := store.ResolveNodes(ctx, []string{target})
+	if err != nil || len(targets) == 0 {
+		return nil, err
+	}
+	isTarget := make(map[string]bool, len(targets))
+	for _, name := range targets {
+		isTarget[name] = true
+	}
+
+	g := newAccumulator()
+	// parent[name] is the relation through which name was first reached.
+	parent := make(map[string]*types.GraphRelation)
+	var frontier []string
+	for _, name := range sources {
+		if isTarget[name] {
+			g.visit(name)
+			return g.path(parent, name), nil
+		}
+		if g.visit(name) {
+			frontier = append(frontier, name)
+		}
+	}
+	for hop := 0; hop < opts.MaxHops && len(frontier) > 0; hop++ {
+		data, err := store.Neighbors(ctx, frontier, opts.RelationTypes)
+		if err != nil {
+			return nil, err
+		}
+		g.addNodes(data)
+		var next []string
+		found := ""
+		for _, rel := range relations(data) {
+			// Fold every copy of an edge (the whole level is scanned even
+			// after the target is reached) so a same-named edge from another
+			// document contributes its evidence to the path.
+			rel = g.addRelation(rel)
+			if found != "" {
+				continue
+			}
+			from, to := rel.Node1, rel.Node2
+			if !g.visited[from] {
+				from, to = to, from
+			}
+			if !g.visited[from] || g.visited[to] || len(g.visited) >= opts.MaxNodes {
+				continue
+			}
+			g.visit(to)
+			parent[to] = rel
+			if isTarget[to] {
+				found = to
+			}
+			next = append(next, to)
+		}
+		if found != "" {
+			return g.path(parent, found), nil
+		}
+		frontier = next
+	}
+	return nil, nil
+}
+
+// EvidenceChunks returns the chunks backing a relation. Relations written
+// before edges carried their own chunk list fall back to the chunks both
+// endpoints share (the chunk the relation was extracted from), and to the
+// endpoints' union when they share none.
+func EvidenceChunks(relChunks, sourceChunks, targetChunks []string) []string {
+	if len(relChunks) > 0 {
+		return relChunks
+	}
+	var shared []string
+	for _, id := range sourceChunks {
+		if slices.Contains(targetChunks, id) && !slices.Contains(shared, id) {
+			shared = append(shared, id)
+		}
+	}
+	if len(shared) > 0 {
+		return shared
+	}
+	return mergeUnique(sourceChunks, targetChunks)
+}
+
+func relations(data *types.GraphData) []*types.GraphRelation {
+	if data == nil {
+		return nil
+	}
+	return data.Relation
+}
+
+// accumulator collects the merged nodes and relations seen during a walk.
+type accumulator struct {
+	visited   map[string]bool
+	nodes     map[string]*types.GraphNode
+	nodeOrder []string
+	relations map[string]*types.GraphRelation
+	relOrder  []string
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{
+		visited:   make(map[string]bool),
+		nodes:     make(map[string]*types.GraphNode),
+		relations: make(map[string]*types.GraphRelation),
+	}
+}
+
+// visit marks name visited and reports whether it was new.
+func (a *accumulator) visit(name string) bool {
+	if name == "" || a.visited[name] {
+		return false
+	}
+	a.visited[name] = true
+	if _, ok := a.nodes[name]; !ok {
+		a.nodes[name] = &types.GraphNode{Name: name}
+		a.nodeOrder = append(a.nodeOrder, name)
+	}
+	return true
+}
+
+func (a *accumulator) addNodes(data *types.GraphData) {
+	if data == nil {
+		return
+	}
+	for _, node := range data.Node {
+		if node == nil || node.Name == "" {
+			continue
+		}
+		existing, ok := a.nodes[node.Name]
+		if !ok {
+			existing = &types.GraphNode{Name: node.Name}
+			a.nodes[node.Name] = existing
+			a.nodeOrder = append(a.nodeOrder, node.Name)
+		}
+		existing.Chunks = mergeUnique(existing.Chunks, node.Chunks)
+		existing.Attributes = mergeUnique(existing.Attributes, node.Attributes)
+	}
+}
+
+// addRelation records rel, merging it into an identical edge already seen,
+// and returns the merged relation.
+func (a *accumulator) addRelation(rel *types.GraphRelation) *types.GraphRelation {
+	key := rel.Node1 + "\x00" + rel.Type + "\x00" + rel.Node2
+	if existing, ok := a.relations[key]; ok {
+		existing.Chunks = mergeUnique(existing.Chunks, rel.Chunks)
+		return existing
+	}
+	merged := &types.GraphRelation{
+		Node1:  rel.Node1,
+		Node2:  rel.Node2,
+		Type:   rel.Type,
+		Chunks: mergeUnique(nil, rel.Chunks),
+	}
+	a.relations[key] = merged
+	a.relOrder = append(a.relOrder, key)
+	return merged
+}
+
+// graph returns visited nodes and the relations between them.
+func (a *accumulator) graph() *types.GraphData {
+	out := &types.GraphData{}
+	for _, name := range a.nodeOrder {
+		if a.visited[name] {
+			out.Node = append(out.Node, a.nodes[name])
+		}
+	}
+	for _, key := range a.relOrder {
+		out.Relation = append(out.Relation, a.relations[key])
+	}
+	return out
+}
+
+// path walks parent links back from end and returns the path source→end.
+func (a *accumulator) path(parent map[string]*types.GraphRelation, end string) *types.GraphPath {
+	p := &types.GraphPath{}
+	name := end
+	for {
+		p.Nodes = append(p.Nodes, a.node(name))
+		rel, ok := parent[name]
+		if !ok {
+			break
+		}
+		p.Relations = append(p.Relations, rel)
+		if rel.Node1 == name {
+			name = rel.Node2
+		} else {
+			name = rel.Node1
+		}
+	}
+	slices.Reverse(p.Nodes)
+	slices.Reverse(p.Relations)
+	return p
+}
+
+func (a *accumulator) node(name string) *types.GraphNode {
+	if node, ok := a.nodes[name]; ok {
+		return node
+	}
+	return &types.GraphNode{Name: name}
+}
+
+func mergeUnique(base, extra []string) []string {
+	for _, v := range extra {
+		if v != "" && !slices.Contains(base, v) {
+			base = append(base, v)
+		}
+	}
+	return base
+}
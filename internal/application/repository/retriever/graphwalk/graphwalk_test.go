@@ -0,0 +1,152 @@
+package graphwalk
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// memStore is an in-memory Store. Each edge is its own "document" copy so
+// the tests exercise same-name merging across documents.
+type memStore struct {
+	edges []*types.GraphRelation
+	calls int
+}
+
+func (m *memStore) ResolveNodes(_ context.Context, mentions []string) ([]string, error) {
+	var out []string
+	for _, mention := range mentions {
+		for _, e := range m.edges {
+			for _, name := range []string{e.Node1, e.Node2} {
+				if strings.Contains(strings.ToLower(name), strings.ToLower(mention)) && !slices.Contains(out, name) {
+					out = append(out, name)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) Neighbors(_ context.Context, names []string, relationTypes []string) (*types.GraphData, error) {
+	m.calls++
+	g := &types.GraphData{}
+	for _, e := range m.edges {
+		if len(relationTypes) > 0 && !slices.Contains(relationTypes, e.Type) {
+			continue
+		}
+		if slices.Contains(names, e.Node1) || slices.Contains(names, e.Node2) {
+			g.Relation = append(g.Relation, e)
+			g.Node = append(g.Node,
+				&types.GraphNode{Name: e.Node1, Chunks: e.Chunks},
+				&types.GraphNode{Name: e.Node2, Chunks: e.Chunks})
+		}
+	}
+	return g, nil
+}
+
+func edge(from, typ, to string, chunks ...string) *types.GraphRelation {
+	return &types.GraphRelation{Node1: from, Type: typ, Node2: to, Chunks: chunks}
+}
+
+func newSupplyChain() *memStore {
+	return &memStore{edges: []*types.GraphRelation{
+		edge("Supplier X", "supplies", "Part P", "c1"),
+		edge("Part P", "installed_in", "Pump 7", "c2"),
+		edge("Incident Y", "caused_by", "Pump 7", "c3"),
+		edge("Incident Y", "caused_by", "Pump 7", "c4"), // same edge, another document
+		edge("Supplier X", "audited_by", "Auditor A", "c5"),
+	}}
+}
+
+func TestShortestPathExplainsEachHop(t *testing.T) {
+	store := newSupplyChain()
+	p, err := ShortestPath(context.Background(), store, "supplier x", "Incident Y",
+		types.GraphTraversalOptions{MaxHops: 4})
+	if err != nil {
+		t.Fatalf("ShortestPath: %v", err)
+	}
+	if p == nil {
+		t.Fatal("expected a path")
+	}
+	var names []string
+	for _, n := range p.Nodes {
+		names = append(names, n.Name)
+	}
+	if got := strings.Join(names, " > "); got != "Supplier X > Part P > Pump 7 > Incident Y" {
+		t.Fatalf("path = %s", got)
+	}
+	if p.Hops() != 3 {
+		t.Fatalf("hops = %d", p.Hops())
+	}
+	last := p.Relations[2]
+	if last.Node1 != "Incident Y" || last.Type != "caused_by" {
+		t.Fatalf("last relation should keep its stored direction, got %+v", last)
+	}
+	if !slices.Equal(last.Chunks, []string{"c3", "c4"}) {
+		t.Fatalf("duplicate edges should merge evidence, got %v", last.Chunks)
+	}
+}
+
+func TestShortestPathRespectsHopLimitAndRelationTypes(t *testing.T) {
+	store := newSupplyChain()
+	p, err := ShortestPath(context.Background(), store, "Supplier X", "Incident Y",
+		types.GraphTraversalOptions{MaxHops: 2})
+	if err != nil || p != nil {
+		t.Fatalf("expected no path within 2 hops, got %+v, %v", p, err)
+	}
+	p, err = ShortestPath(context.Background(), store, "Supplier X", "Incident Y",
+		types.GraphTraversalOptions{MaxHops: 4, RelationTypes: []string{"supplies", "caused_by"}})
+	if err != nil || p != nil {
+		t.Fatalf("expected installed_in filter to cut the path, got %+v, %v", p, err)
+	}
+}
+
+func TestExpandReturnsKHopNeighbourhood(t *testing.T) {
+	store := newSupplyChain()
+	g, err := Expand(context.Background(), store, []string{"Supplier X"}, types.GraphTraversalOptions{MaxHops: 2})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	var names []string
+	for _, n := range g.Node {
+		names = append(names, n.Name)
+	}
+	slices.Sort(names)
+	want := []string{"Auditor A", "Part P", "Pump 7", "Supplier X"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("nodes = %v, want %v", names, want)
+	}
+	if len(g.Relation) != 3 {
+		t.Fatalf("relations = %d, want 3", len(g.Relation))
+	}
+	if store.calls != 2 {
+		t.Fatalf("expected one neighbour lookup per hop, got %d", store.calls)
+	}
+}
+
+func TestExpandStopsAtMaxNodes(t *testing.T) {
+	store := newSupplyChain()
+	g, err := Expand(context.Background(), store, []string{"Supplier X"},
+		types.GraphTraversalOptions{MaxHops: 4, MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(g.Node) != 2 || len(g.Relation) != 1 {
+		t.Fatalf("expected 2 nodes / 1 relation, got %d / %d", len(g.Node), len(g.Relation))
+	}
+}
+
+func TestEvidenceChunksFallsBackToSharedEndpointChunks(t *testing.T) {
+	if got := EvidenceChunks([]string{"r"}, []string{"a"}, []string{"a"}); !slices.Equal(got, []string{"r"}) {
+		t.Fatalf("relation chunks should win, got %v", got)
+	}
+	if got := EvidenceChunks(nil, []string{"a", "b"}, []string{"b", "c"}); !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("expected shared chunk, got %v", got)
+	}
+	if got := EvidenceChunks(nil, []string{"a"}, []string{"c"}); !slices.Equal(got, []string{"a", "c"}) {
+		t.Fatalf("expected union, got %v", got)
+	}
+}
@@ -0,0 +1,105 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	client "github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// ExportIndices pages through the rows of a knowledge base label in the
+// dimension's collection in primary-key order, with their vectors. The cursor
+// is the last exported primary key.
+func (m *milvusRepository) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	page := &types.IndexExportPage{Embeddings: make(map[string][]float32)}
+	collectionName := m.getCollectionName(dimension)
+	exists, err := m.client.HasCollection(ctx, client.NewHasCollectionOption(collectionName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	if !exists {
+		return page, nil
+	}
+
+	outputFields := allFields
+	if m.hasMetadataField(ctx, collectionName) {
+		outputFields = append(slices.Clone(allFields), fieldMetadata)
+	}
+	queryOpt := client.NewQueryOption(collectionName).
+		WithFilter(fmt.Sprintf("%s == {kb_id} && %s > {cursor}", fieldKnowledgeBaseID, fieldID)).
+		WithTemplateParam("kb_id", knowledgeBaseID).
+		WithTemplateParam("cursor", cursor).
+		WithOutputFields(outputFields...).
+		WithLimit(limit).
+		WithConsistencyLevel(entity.ClStrong)
+	resultSet, err := m.client.Query(ctx, queryOpt)
+	if err != nil {
+		logger.GetLogger(ctx).Errorf("[Milvus] Failed to export rows: %v", err)
+		return nil, err
+	}
+	rows, _, err := convertResultSet([]client.ResultSet{resultSet})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	page.Entries = make([]*types.IndexInfo, 0, len(rows))
+	for _, row := range rows {
+		page.Entries = append(page.Entries, &types.IndexInfo{
+			ID:              row.ID,
+			Content:         row.Content,
+			SourceID:        row.SourceID,
+			SourceType:      types.SourceType(row.SourceType),
+			ChunkID:         row.ChunkID,
+			KnowledgeID:     row.KnowledgeID,
+			KnowledgeBaseID: row.KnowledgeBaseID,
+			KnowledgeType:   knowledgeType,
+			TagID:           row.TagID,
+			IsEnabled:       row.IsEnabled,
+			Metadata:        row.Metadata,
+		})
+		if len(row.Embedding) > 0 {
+			page.Embeddings[row.SourceID] = row.Embedding
+		}
+	}
+	if len(rows) == limit {
+		page.NextCursor = rows[len(rows)-1].ID
+	}
+	return page, nil
+}
+
+// CountIndices counts the rows of a knowledge base label with a strongly
+// consistent count(*) query, so rows written just before are included.
+func (m *milvusRepository) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	collectionName := m.getCollectionName(dimension)
+	exists, err := m.client.HasCollection(ctx, client.NewHasCollectionOption(collectionName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	resultSet, err := m.client.Query(ctx, client.NewQueryOption(collectionName).
+		WithFilter(fmt.Sprintf("%s == {kb_id}", fieldKnowledgeBaseID)).
+		WithTemplateParam("kb_id", knowledgeBaseID).
+		WithOutputFields("count(*)").
+		WithConsistencyLevel(entity.ClStrong))
+	if err != nil {
+		return 0, err
+	}
+	countColumn := resultSet.GetColumn("count(*)")
+	if countColumn == nil || countColumn.Len() == 0 {
+		return 0, nil
+	}
+	return countColumn.GetAsInt64(0)
+}
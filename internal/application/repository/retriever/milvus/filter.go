@@ -232,10 +232,17 @@ func escapeDoubleQuotes(s string) string {
 }
 
 // convertParamName converts field name to a valid Milvus template parameter name.
-// Milvus template parameters don't support '.' character, so we replace it with '_'.
+// Milvus template parameters only allow identifier characters, so '.' and the
+// brackets and quotes of JSON key access (metadata["key"]) become '_'.
 func (c *filter) convertParamName(field string, counter *int) string {
 	*counter++
-	return fmt.Sprintf("%s_%d", strings.ReplaceAll(field, ".", "_"), *counter)
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, field)
+	return fmt.Sprintf("%s_%d", name, *counter)
 }
 
 type universalFilterCondition struct {
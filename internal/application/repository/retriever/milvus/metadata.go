@@ -0,0 +1,189 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	client "github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// metadataFieldSchema is the nullable JSON field holding IndexInfo.Metadata.
+// It is nullable so it can be added to collections created before it existed.
+func metadataFieldSchema() *entity.Field {
+	return entity.NewField().
+		WithName(fieldMetadata).
+		WithDataType(entity.FieldTypeJSON).
+		WithNullable(true)
+}
+
+// hasMetadataField reports whether the collection stores document metadata.
+// Collections created before the field existed get it added once; servers
+// that cannot add fields (before Milvus 2.6) keep working without metadata,
+// and metadata filters on them fail instead of being ignored.
+func (m *milvusRepository) hasMetadataField(ctx context.Context, collectionName string) bool {
+	if ok, cached := m.metadataCollections.Load(collectionName); cached {
+		return ok.(bool)
+	}
+	log := logger.GetLogger(ctx)
+	collection, err := m.client.DescribeCollection(ctx, client.NewDescribeCollectionOption(collectionName))
+	if err != nil {
+		// Not cached: a transient failure must not disable metadata for good.
+		log.Warnf("[Milvus] Failed to describe collection %s: %v", collectionName, err)
+		return false
+	}
+	has := false
+	for _, field := range collection.Schema.Fields {
+		if field.Name == fieldMetadata {
+			has = true
+			break
+		}
+	}
+	if !has {
+		err := m.client.AddCollectionField(ctx,
+			client.NewAddCollectionFieldOption(collectionName, metadataFieldSchema()))
+		if err != nil {
+			log.Warnf("[Milvus] Collection %s cannot store document metadata, metadata filters are unavailable: %v",
+				collectionName, err)
+		} else {
+			log.Infof("[Milvus] Added metadata field to collection %s", collectionName)
+			has = true
+		}
+	}
+	m.metadataCollections.Store(collectionName, has)
+	return has
+}
+
+// metadataFilterCondition translates a normalised metadata filter into a
+// condition on keys of the metadata JSON field.
+func metadataFilterCondition(f *types.MetadataFilter) (*universalFilterCondition, error) {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		children := make([]*universalFilterCondition, 0, len(f.Filters))
+		for _, child := range f.Filters {
+			cond, err := metadataFilterCondition(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, cond)
+		}
+		operator := operatorAnd
+		if f.Op == types.MetadataFilterOr {
+			operator = operatorOr
+		}
+		return &universalFilterCondition{Operator: operator, Value: children}, nil
+	}
+
+	field := fmt.Sprintf(`%s["%s"]`, fieldMetadata, f.Field)
+	switch f.Op {
+	case types.MetadataFilterEq:
+		return &universalFilterCondition{Field: field, Operator: operatorEqual, Value: f.Value}, nil
+	case types.MetadataFilterIn:
+		// Template parameters need a typed slice; a normalised "in" holds
+		// values of a single kind.
+		values, err := typedValues(f.Values)
+		if err != nil {
+			return nil, err
+		}
+		return &universalFilterCondition{Field: field, Operator: operatorIn, Value: values}, nil
+	case types.MetadataFilterGt, types.MetadataFilterGte, types.MetadataFilterLt, types.MetadataFilterLte:
+		return &universalFilterCondition{Field: field, Operator: string(f.Op), Value: f.Value}, nil
+	}
+	return nil, fmt.Errorf("unsupported metadata filter operator %q", f.Op)
+}
+
+func typedValues(values []any) (any, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("empty metadata filter value list")
+	}
+	switch values[0].(type) {
+	case string:
+		return typedSlice[string](values)
+	case float64:
+		return typedSlice[float64](values)
+	case bool:
+		return typedSlice[bool](values)
+	}
+	return nil, fmt.Errorf("unsupported metadata filter value %v", values[0])
+}
+
+func typedSlice[T any](values []any) ([]T, error) {
+	out := make([]T, 0, len(values))
+	for _, value := range values {
+		v, ok := value.(T)
+		if !ok {
+			return nil, fmt.Errorf("mixed metadata filter values %v", values)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// metadataJSON encodes the metadata column value of a row; rows without
+// metadata store an empty object.
+func metadataJSON(metadata map[string]any) []byte {
+	if len(metadata) == 0 {
+		return []byte("{}")
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata of every row of each
+// knowledge, in all collections that store metadata.
+func (m *milvusRepository) BatchUpdateKnowledgeMetadata(ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadata) == 0 {
+		return nil
+	}
+	log.Infof("[Milvus] Batch updating knowledge metadata, count: %d", len(knowledgeMetadata))
+
+	collections, err := m.client.ListCollections(ctx, client.NewListCollectionOption())
+	if err != nil {
+		log.Errorf("[Milvus] Failed to list collections: %v", err)
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, collectionName := range collections {
+		if len(collectionName) <= len(m.collectionBaseName) ||
+			collectionName[:len(m.collectionBaseName)] != m.collectionBaseName {
+			continue
+		}
+		if !m.hasMetadataField(ctx, collectionName) {
+			continue
+		}
+		for knowledgeID, metadata := range knowledgeMetadata {
+			embeddings, _, err := m.searchByFilter(ctx, collectionName, &universalFilterCondition{
+				Field:    fieldKnowledgeID,
+				Operator: operatorEqual,
+				Value:    knowledgeID,
+			}, nil, nil)
+			if err != nil {
+				log.Errorf("[Milvus] Failed to search knowledge %s in %s: %v", knowledgeID, collectionName, err)
+				return err
+			}
+			upsertEmbeddings := make([]*MilvusVectorEmbedding, 0, len(embeddings))
+			for _, embedding := range embeddings {
+				embedding.Metadata = metadata
+				upsertEmbeddings = append(upsertEmbeddings, &embedding.MilvusVectorEmbedding)
+			}
+			if len(upsertEmbeddings) == 0 {
+				continue
+			}
+			if _, err := m.client.Upsert(ctx, createUpsert(collectionName, upsertEmbeddings, true)); err != nil {
+				log.Errorf("[Milvus] Failed to update metadata of knowledge %s in %s: %v",
+					knowledgeID, collectionName, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
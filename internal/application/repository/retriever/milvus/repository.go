@@ -2,6 +2,7 @@ package milvus
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
@@ -35,6 +36,7 @@ const (
 	fieldIsEnabled        = "is_enabled"
 	fieldID               = "id"
 	fieldContentSparse    = "content_sparse"
+	fieldMetadata         = "metadata"
 )
 
 var (
@@ -154,6 +156,7 @@ func (m *milvusRepository) ensureCollection(ctx context.Context, dimension int)
 				entity.NewField().
 					WithName(fieldIsEnabled).
 					WithDataType(entity.FieldTypeBool),
+				metadataFieldSchema(),
 			},
 		}
 
@@ -187,6 +190,7 @@ func (m *milvusRepository) ensureCollection(ctx context.Context, dimension int)
 		}
 
 		log.Infof("[Milvus] Successfully created collection %s", collectionName)
+		m.metadataCollections.Store(collectionName, true)
 	}
 
 	loadOpt := client.NewLoadCollectionOption(collectionName)
@@ -253,8 +257,8 @@ func (m *milvusRepository) Save(ctx context.Context,
 
 	collectionName := m.getCollectionName(dimension)
 
-	embeddingDB.ID = uuid.New().String()
-	opts := createUpsert(collectionName, []*MilvusVectorEmbedding{embeddingDB})
+	embeddingDB.ID = entryIDFor(embedding)
+	opts := createUpsert(collectionName, []*MilvusVectorEmbedding{embeddingDB}, m.hasMetadataField(ctx, collectionName))
 
 	_, err := m.client.Upsert(ctx, opts)
 	if err != nil {
@@ -311,10 +315,10 @@ func (m *milvusRepository) BatchSave(ctx context.Context,
 
 		for _, embedding := range embeddings {
 			embeddingDB := toMilvusVectorEmbedding(embedding, additionalParams)
-			embeddingDB.ID = uuid.New().String()
+			embeddingDB.ID = entryIDFor(embedding)
 			embeddingDBList = append(embeddingDBList, embeddingDB)
 		}
-		opts := createUpsert(collectionName, embeddingDBList)
+		opts := createUpsert(collectionName, embeddingDBList, m.hasMetadataField(ctx, collectionName))
 		_, err := m.client.Upsert(ctx, opts)
 		if err != nil {
 			log.Errorf("[Milvus] Failed to execute batch operation for dimension %d: %v", dimension, err)
@@ -376,6 +380,31 @@ func (m *milvusRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return nil
 }
 
+// DeleteByKnowledgeBaseIDList removes points from the collection based on knowledge base IDs
+func (m *milvusRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeBaseIDList) == 0 {
+		log.Warn("[Milvus] Empty knowledge base ID list provided for deletion, skipping")
+		return nil
+	}
+
+	collectionName := m.getCollectionName(dimension)
+	log.Infof("[Milvus] Deleting indices by knowledge base IDs from %s, count: %d", collectionName, len(knowledgeBaseIDList))
+
+	deleteOpt := client.NewDeleteOption(collectionName)
+	deleteOpt.WithStringIDs(fieldKnowledgeBaseID, knowledgeBaseIDList)
+	_, err := m.client.Delete(ctx, deleteOpt)
+	if err != nil {
+		log.Errorf("[Milvus] Failed to delete by knowledge base IDs: %v", err)
+		return fmt.Errorf("failed to delete by knowledge base IDs: %w", err)
+	}
+
+	log.Infof("[Milvus] Successfully deleted documents by knowledge base IDs")
+	return nil
+}
+
 // DeleteBySourceIDList removes points from the collection based on source IDs
 func (m *milvusRepository) DeleteBySourceIDList(ctx context.Context,
 	sourceIDList []string, dimension int, knowledgeType string,
@@ -496,7 +525,7 @@ func (m *milvusRepository) updateChunkEnabledStatusInCollection(
 		return nil
 	}
 
-	req := createUpsert(collectionName, upsertEmbeddings)
+	req := createUpsert(collectionName, upsertEmbeddings, m.hasMetadataField(ctx, collectionName))
 	if _, err := m.client.Upsert(ctx, req); err != nil {
 		return err
 	}
@@ -580,7 +609,7 @@ func (m *milvusRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMa
 				upsertEmbeddings = append(upsertEmbeddings, &embedding.MilvusVectorEmbedding)
 			}
 			if len(upsertEmbeddings) > 0 {
-				req := createUpsert(collectionName, upsertEmbeddings)
+				req := createUpsert(collectionName, upsertEmbeddings, m.hasMetadataField(ctx, collectionName))
 				_, err := m.client.Upsert(ctx, req)
 				if err != nil {
 					log.Warnf("[Milvus] Failed to update chunks in %s: %v", collectionName, err)
@@ -597,6 +626,13 @@ func (m *milvusRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMa
 
 func (m *milvusRepository) getBaseFilterForQuery(params types.RetrieveParams) (string, map[string]any, error) {
 	filters := make([]*universalFilterCondition, 0)
+	if params.MetadataFilter != nil {
+		cond, err := metadataFilterCondition(params.MetadataFilter)
+		if err != nil {
+			return "", nil, err
+		}
+		filters = append(filters, cond)
+	}
 	if len(params.KnowledgeBaseIDs) > 0 {
 		filters = append(filters, &universalFilterCondition{
 			Field:    fieldKnowledgeBaseID,
@@ -691,6 +727,9 @@ func (m *milvusRepository) VectorRetrieve(ctx context.Context,
 		log.Warnf("[Milvus] Collection %s does not exist, returning empty results", collectionName)
 		return buildRetrieveResult(nil, types.VectorRetrieverType), nil
 	}
+	if params.MetadataFilter != nil && !m.hasMetadataField(ctx, collectionName) {
+		return nil, fmt.Errorf("collection %s does not store document metadata", collectionName)
+	}
 
 	expr, paramsMap, err := m.getBaseFilterForQuery(params)
 	if err != nil {
@@ -763,6 +802,10 @@ func (m *milvusRepository) KeywordsRetrieve(ctx context.Context,
 			continue
 		}
 
+		if params.MetadataFilter != nil && !m.hasMetadataField(ctx, collectionName) {
+			log.Warnf("[Milvus] Collection %s does not store document metadata, skipping", collectionName)
+			continue
+		}
 		expr, paramsMap, err := m.getBaseFilterForQuery(params)
 		if err != nil {
 			log.Errorf("[Milvus] Failed to build base filter: %v", err)
@@ -885,11 +928,12 @@ func (m *milvusRepository) CopyIndices(ctx context.Context,
 				TagID:           sourceEmbedding.TagID,
 				Embedding:       sourceEmbedding.Embedding,
 				IsEnabled:       sourceEmbedding.IsEnabled,
+				Metadata:        sourceEmbedding.Metadata,
 			}
 			targetEmbeddings = append(targetEmbeddings, targetEmbedding)
 		}
 		if len(targetEmbeddings) > 0 {
-			opts := createUpsert(collectionName, targetEmbeddings)
+			opts := createUpsert(collectionName, targetEmbeddings, m.hasMetadataField(ctx, collectionName))
 			_, err := m.client.Upsert(ctx, opts)
 			if err != nil {
 				log.Errorf("[Milvus] Failed to batch upsert target points: %v", err)
@@ -966,6 +1010,7 @@ func toMilvusVectorEmbedding(embedding *types.IndexInfo, additionalParams map[st
 		KnowledgeBaseID: embedding.KnowledgeBaseID,
 		TagID:           embedding.TagID,
 		IsEnabled:       embedding.IsEnabled,
+		Metadata:        embedding.Metadata,
 	}
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), fieldEmbedding) {
 		if embeddingMap, ok := additionalParams[fieldEmbedding].(map[string][]float32); ok {
@@ -994,7 +1039,19 @@ func fromMilvusVectorEmbedding(id string,
 	}
 }
 
-func createUpsert(collectionName string, embeddings []*MilvusVectorEmbedding) client.UpsertOption {
+// entryIDFor returns the caller-assigned ID, so writing the same entry twice
+// overwrites the row, and a random UUID when there is none.
+func entryIDFor(embedding *types.IndexInfo) string {
+	if embedding.ID != "" {
+		return embedding.ID
+	}
+	return uuid.New().String()
+}
+
+// createUpsert builds a column-based upsert. withMetadata writes the metadata
+// column; it must be set whenever the collection has it, or the upsert would
+// clear the metadata of the rows it rewrites.
+func createUpsert(collectionName string, embeddings []*MilvusVectorEmbedding, withMetadata bool) client.UpsertOption {
 	ids := make([]string, 0, len(embeddings))
 	embeddingsData := make([][]float32, 0, len(embeddings))
 	contents := make([]string, 0, len(embeddings))
@@ -1005,6 +1062,7 @@ func createUpsert(collectionName string, embeddings []*MilvusVectorEmbedding) cl
 	knowledgeBaseIDs := make([]string, 0, len(embeddings))
 	tagIDs := make([]string, 0, len(embeddings))
 	isEnableds := make([]bool, 0, len(embeddings))
+	metadata := make([][]byte, 0, len(embeddings))
 	var dimension int
 	for _, embedding := range embeddings {
 		ids = append(ids, embedding.ID)
@@ -1017,6 +1075,7 @@ func createUpsert(collectionName string, embeddings []*MilvusVectorEmbedding) cl
 		knowledgeBaseIDs = append(knowledgeBaseIDs, embedding.KnowledgeBaseID)
 		tagIDs = append(tagIDs, embedding.TagID)
 		isEnableds = append(isEnableds, embedding.IsEnabled)
+		metadata = append(metadata, metadataJSON(embedding.Metadata))
 		dimension = len(embedding.Embedding)
 	}
 	opt := client.NewColumnBasedInsertOption(collectionName).
@@ -1030,6 +1089,9 @@ func createUpsert(collectionName string, embeddings []*MilvusVectorEmbedding) cl
 		WithVarcharColumn(fieldKnowledgeBaseID, knowledgeBaseIDs).
 		WithVarcharColumn(fieldTagID, tagIDs).
 		WithBoolColumn(fieldIsEnabled, isEnableds)
+	if withMetadata {
+		opt.WithColumns(column.NewColumnJSONBytes(fieldMetadata, metadata))
+	}
 	return opt
 }
 
@@ -1139,6 +1201,18 @@ func convertResultSet(resultSet []client.ResultSet) ([]*MilvusVectorEmbeddingWit
 			}
 		}
 		if field == fieldEmbedding {
+			// Query returns the stored float vectors; keep the double
+			// array form for callers that request it explicitly.
+			if floatColumn, ok := columns.(*column.ColumnFloatVector); ok {
+				for i := 0; i < floatColumn.Len(); i++ {
+					val, err := floatColumn.Value(i)
+					if err != nil {
+						return nil, nil, fmt.Errorf("get vector failed: %w", err)
+					}
+					docs[i].Embedding = []float32(val)
+				}
+				continue
+			}
 			vectorColumn, ok := columns.(*column.ColumnDoubleArray)
 			if !ok {
 				continue
@@ -1156,5 +1230,22 @@ func convertResultSet(resultSet []client.ResultSet) ([]*MilvusVectorEmbeddingWit
 			}
 		}
 	}
+	if columns := set.GetColumn(fieldMetadata); columns != nil {
+		for i := 0; i < columns.Len() && i < len(docs); i++ {
+			if isNull, err := columns.IsNull(i); err != nil || isNull {
+				continue
+			}
+			val, err := columns.Get(i)
+			if err != nil {
+				return nil, nil, err
+			}
+			if raw, ok := val.([]byte); ok && len(raw) > 0 {
+				var metadata map[string]any
+				if err := json.Unmarshal(raw, &metadata); err == nil && len(metadata) > 0 {
+					docs[i].Metadata = metadata
+				}
+			}
+		}
+	}
 	return docs, scores, nil
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/Tencent/WeKnora/internal/types"
 )
 
 func TestUpdateChunkEnabledStatusInCollectionSkipsEmptyChunkIDs(t *testing.T) {
@@ -42,3 +44,22 @@ func TestUpdateChunkEnabledStatusInCollectionsPropagatesFailure(t *testing.T) {
 	)
 	require.ErrorIs(t, err, wantErr)
 }
+
+func TestGetBaseFilterForQueryTranslatesMetadataFilter(t *testing.T) {
+	metadataFilter, err := (&types.MetadataFilter{Op: types.MetadataFilterAnd, Filters: []*types.MetadataFilter{
+		{Op: types.MetadataFilterIn, Key: "region", Values: []any{"EU", "UK"}},
+		{Op: types.MetadataFilterGt, Key: "effective_date", Value: "2025-01-01"},
+	}}).Normalize()
+	require.NoError(t, err)
+
+	repo := &milvusRepository{}
+	expr, params, err := repo.getBaseFilterForQuery(types.RetrieveParams{MetadataFilter: metadataFilter})
+	require.NoError(t, err)
+
+	region := types.MetadataField(types.MetadataKindString, "region")
+	date := types.MetadataField(types.MetadataKindDate, "effective_date")
+	require.Contains(t, expr, `metadata["`+region+`"] in {metadata__`+region+`___1}`)
+	require.Contains(t, expr, `metadata["`+date+`"] > {metadata__`+date+`___2}`)
+	require.Equal(t, []string{"EU", "UK"}, params["metadata__"+region+"___1"])
+	require.Equal(t, float64(1735689600), params["metadata__"+date+"___2"])
+}
@@ -16,19 +16,22 @@ type milvusRepository struct {
 	replicaNumber      int // 0 = use Milvus default (1); set at LoadCollection time
 	// Cache for initialized collections (dimension -> true)
 	initializedCollections sync.Map
+	// Cache of whether a collection stores document metadata (name -> bool)
+	metadataCollections sync.Map
 }
 
 type MilvusVectorEmbedding struct {
-	ID              string    `json:"id"`
-	Content         string    `json:"content"`
-	SourceID        string    `json:"source_id"`
-	SourceType      int       `json:"source_type"`
-	ChunkID         string    `json:"chunk_id"`
-	KnowledgeID     string    `json:"knowledge_id"`
-	KnowledgeBaseID string    `json:"knowledge_base_id"`
-	TagID           string    `json:"tag_id"`
-	Embedding       []float32 `json:"embedding"`
-	IsEnabled       bool      `json:"is_enabled"`
+	ID              string         `json:"id"`
+	Content         string         `json:"content"`
+	SourceID        string         `json:"source_id"`
+	SourceType      int            `json:"source_type"`
+	ChunkID         string         `json:"chunk_id"`
+	KnowledgeID     string         `json:"knowledge_id"`
+	KnowledgeBaseID string         `json:"knowledge_base_id"`
+	TagID           string         `json:"tag_id"`
+	Embedding       []float32      `json:"embedding"`
+	IsEnabled       bool           `json:"is_enabled"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
 }
 
 type MilvusVectorEmbeddingWithScore struct {
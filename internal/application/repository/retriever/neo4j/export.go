@@ -0,0 +1,119 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/graphwalk"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+)
+
+// ExportGraphs streams the stored graph one document at a time, calling fn
+// with the document's knowledge id and its nodes and relations. It backs the
+// migration from Neo4j to another graph store.
+func (n *Neo4jRepository) ExportGraphs(
+	ctx context.Context, fn func(knowledgeID string, graph *types.GraphData) error,
+) error {
+	if n.driver == nil {
+		return fmt.Errorf("neo4j is not connected")
+	}
+	knowledgeIDs, err := n.exportedKnowledgeIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, knowledgeID := range knowledgeIDs {
+		graph, err := n.exportKnowledgeGraph(ctx, knowledgeID)
+		if err != nil {
+			return err
+		}
+		if err := fn(knowledgeID, graph); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Neo4jRepository) exportedKnowledgeIDs(ctx context.Context) ([]string, error) {
+	session := n.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, `MATCH (n) WHERE n.kg IS NOT NULL RETURN DISTINCT n.kg AS kg`, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list graph documents: %v", err)
+		}
+		var ids []string
+		for res.Next(ctx) {
+			kg, _ := res.Record().Get("kg")
+			if id, ok := kg.(string); ok && id != "" {
+				ids = append(ids, id)
+			}
+		}
+		return ids, res.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids, _ := result.([]string)
+	return ids, nil
+}
+
+func (n *Neo4jRepository) exportKnowledgeGraph(ctx context.Context, knowledgeID string) (*types.GraphData, error) {
+	session := n.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		params := map[string]interface{}{"knowledge_id": knowledgeID}
+		graph := &types.GraphData{}
+
+		res, err := tx.Run(ctx, `MATCH (n {kg: $knowledge_id}) RETURN n`, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export nodes: %v", err)
+		}
+		for res.Next(ctx) {
+			node, _ := res.Record().Get("n")
+			nodeData := node.(neo4j.Node)
+			graph.Node = append(graph.Node, &types.GraphNode{
+				Name:       propString(nodeData.Props, "name"),
+				Chunks:     propStrings(nodeData.Props, "chunks"),
+				Attributes: propStrings(nodeData.Props, "attributes"),
+			})
+		}
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+
+		res, err = tx.Run(ctx, `
+			MATCH (s {kg: $knowledge_id})-[r]->(e {kg: $knowledge_id})
+			RETURN s, r, e
+		`, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export relationships: %v", err)
+		}
+		for res.Next(ctx) {
+			record := res.Record()
+			start, _ := record.Get("s")
+			rel, _ := record.Get("r")
+			end, _ := record.Get("e")
+			startNode := start.(neo4j.Node)
+			endNode := end.(neo4j.Node)
+			relData := rel.(neo4j.Relationship)
+			graph.Relation = append(graph.Relation, &types.GraphRelation{
+				Node1: propString(startNode.Props, "name"),
+				Node2: propString(endNode.Props, "name"),
+				Type:  relData.Type,
+				Chunks: graphwalk.EvidenceChunks(
+					propStrings(relData.Props, "chunks"),
+					propStrings(startNode.Props, "chunks"),
+					propStrings(endNode.Props, "chunks"),
+				),
+			})
+		}
+		return graph, res.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*types.GraphData), nil
+}
@@ -3,8 +3,10 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/graphwalk"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -88,7 +90,8 @@ func (n *Neo4jRepository) addGraph(ctx context.Context, namespace types.NameSpac
 			UNWIND $data AS row
 			CALL apoc.merge.node(row.source_labels, {name: row.source, kg: row.knowledge_id}, {}, {}) YIELD node as source
 			CALL apoc.merge.node(row.target_labels, {name: row.target, kg: row.knowledge_id}, {}, {}) YIELD node as target
-			CALL apoc.merge.relationship(source, row.type, {}, row.attributes, target) YIELD rel
+			CALL apoc.merge.relationship(source, row.type, {}, {}, target) YIELD rel
+			SET rel.chunks = apoc.coll.union(coalesce(rel.chunks, []), row.chunks)
 			RETURN distinct 'done'
 		`
 		relData := []map[string]interface{}{}
@@ -98,6 +101,7 @@ func (n *Neo4jRepository) addGraph(ctx context.Context, namespace types.NameSpac
 				"target":        rel.Node2,
 				"knowledge_id":  namespace.Knowledge,
 				"type":          rel.Type,
+				"chunks":        rel.Chunks,
 				"source_labels": n.Labels(namespace),
 				"target_labels": n.Labels(namespace),
 			})
@@ -216,6 +220,11 @@ func (n *Neo4jRepository) SearchNode(
 				Node1: nodeData.Props["name"].(string),
 				Node2: targetNodeData.Props["name"].(string),
 				Type:  relData.Type,
+				Chunks: graphwalk.EvidenceChunks(
+					propStrings(relData.Props, "chunks"),
+					propStrings(nodeData.Props, "chunks"),
+					propStrings(targetNodeData.Props, "chunks"),
+				),
 			})
 		}
 		return graphData, nil
@@ -227,6 +236,91 @@ func (n *Neo4jRepository) SearchNode(
 	return result.(*types.GraphData), nil
 }
 
+// GetGraph returns every relation in the namespace with its endpoints.
+// Nodes extracted from different documents are merged by name, and so are
+// relations with the same endpoints and type.
+func (n *Neo4jRepository) GetGraph(ctx context.Context, namespace types.NameSpace) (*types.GraphData, error) {
+	if n.driver == nil {
+		logger.Warnf(ctx, "NOT SUPPORT RETRIEVE GRAPH")
+		return nil, nil
+	}
+	session := n.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		labelExpr := n.Label(namespace)
+		query := `
+			MATCH (s:` + labelExpr + `)-[r]->(e:` + labelExpr + `)
+			RETURN s, r, e
+		`
+		res, err := tx.Run(ctx, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run query: %v", err)
+		}
+
+		graphData := &types.GraphData{}
+		nodeByName := make(map[string]*types.GraphNode)
+		relByKey := make(map[string]*types.GraphRelation)
+		for res.Next(ctx) {
+			record := res.Record()
+			start, _ := record.Get("s")
+			rel, _ := record.Get("r")
+			end, _ := record.Get("e")
+			startNode := start.(neo4j.Node)
+			endNode := end.(neo4j.Node)
+			relData := rel.(neo4j.Relationship)
+
+			for _, node := range []neo4j.Node{startNode, endNode} {
+				name := propString(node.Props, "name")
+				merged, ok := nodeByName[name]
+				if !ok {
+					merged = &types.GraphNode{Name: name}
+					nodeByName[name] = merged
+					graphData.Node = append(graphData.Node, merged)
+				}
+				merged.Chunks = mergeStrings(merged.Chunks, propStrings(node.Props, "chunks"))
+				merged.Attributes = mergeStrings(merged.Attributes, propStrings(node.Props, "attributes"))
+			}
+
+			source, target := propString(startNode.Props, "name"), propString(endNode.Props, "name")
+			key := source + "\x00" + relData.Type + "\x00" + target
+			merged, ok := relByKey[key]
+			if !ok {
+				merged = &types.GraphRelation{Node1: source, Node2: target, Type: relData.Type}
+				relByKey[key] = merged
+				graphData.Relation = append(graphData.Relation, merged)
+			}
+			merged.Chunks = mergeStrings(merged.Chunks, graphwalk.EvidenceChunks(
+				propStrings(relData.Props, "chunks"),
+				propStrings(startNode.Props, "chunks"),
+				propStrings(endNode.Props, "chunks"),
+			))
+		}
+		return graphData, res.Err()
+	})
+	if err != nil {
+		logger.Errorf(ctx, "get graph failed: %v", err)
+		return nil, err
+	}
+	return result.(*types.GraphData), nil
+}
+
+// mergeStrings appends the values of extra missing from list.
+func mergeStrings(list, extra []string) []string {
+	for _, v := range extra {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// propStrings reads a list property, tolerating a missing or null value.
+func propStrings(props map[string]any, key string) []string {
+	list, _ := props[key].([]any)
+	return listI2listS(list)
+}
+
 func listI2listS(list []any) []string {
 	result := make([]string, len(list))
 	for i, v := range list {
@@ -0,0 +1,168 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/application/repository/retriever/graphwalk"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/neo4j/neo4j-go-driver/v6/neo4j"
+)
+
+// ExpandNode returns the k-hop neighbourhood of the named nodes
+func (n *Neo4jRepository) ExpandNode(
+	ctx context.Context, namespace types.NameSpace, nodes []string, opts types.GraphTraversalOptions,
+) (*types.GraphData, error) {
+	if n.driver == nil {
+		logger.Warnf(ctx, "NOT SUPPORT RETRIEVE GRAPH")
+		return nil, nil
+	}
+	return graphwalk.Expand(ctx, &namespaceStore{repo: n, namespace: namespace}, nodes, opts)
+}
+
+// ShortestPath returns a shortest path between two named nodes
+func (n *Neo4jRepository) ShortestPath(
+	ctx context.Context, namespace types.NameSpace, source, target string, opts types.GraphTraversalOptions,
+) (*types.GraphPath, error) {
+	if n.driver == nil {
+		logger.Warnf(ctx, "NOT SUPPORT RETRIEVE GRAPH")
+		return nil, nil
+	}
+	return graphwalk.ShortestPath(ctx, &namespaceStore{repo: n, namespace: namespace}, source, target, opts)
+}
+
+// namespaceStore adapts the repository to graphwalk.Store for one namespace.
+type namespaceStore struct {
+	repo      *Neo4jRepository
+	namespace types.NameSpace
+}
+
+// ResolveNodes prefers exact (case-insensitive) name matches and falls back
+// to substring matches, mirroring SearchNode's CONTAINS semantics.
+func (s *namespaceStore) ResolveNodes(ctx context.Context, mentions []string) ([]string, error) {
+	session := s.repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	labelExpr := s.repo.Label(s.namespace)
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		var names []string
+		seen := make(map[string]bool)
+		for _, mention := range mentions {
+			mention = strings.TrimSpace(mention)
+			if mention == "" {
+				continue
+			}
+			query := `
+				MATCH (n:` + labelExpr + `)
+				WHERE toLower(n.name) CONTAINS toLower($mention)
+				WITH DISTINCT n.name AS name
+				RETURN name, toLower(name) = toLower($mention) AS exact
+				ORDER BY exact DESC, size(name) ASC
+				LIMIT $limit
+			`
+			res, err := tx.Run(ctx, query, map[string]interface{}{"mention": mention, "limit": graphwalk.MaxResolvedNodes})
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve nodes: %v", err)
+			}
+			var matched []string
+			for res.Next(ctx) {
+				record := res.Record()
+				name, _ := record.Get("name")
+				exact, _ := record.Get("exact")
+				nameStr, _ := name.(string)
+				if isExact, _ := exact.(bool); isExact {
+					// An exact hit is unambiguous; ignore looser matches.
+					matched = []string{nameStr}
+					break
+				}
+				matched = append(matched, nameStr)
+			}
+			for _, name := range matched {
+				if name != "" && !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		return names, nil
+	})
+	if err != nil {
+		logger.Errorf(ctx, "resolve graph nodes failed: %v", err)
+		return nil, err
+	}
+	names, _ := result.([]string)
+	return names, nil
+}
+
+// Neighbors returns the one-hop edges of the named nodes. Relations keep
+// their stored direction so a path can be explained as "A -[type]-> B".
+func (s *namespaceStore) Neighbors(
+	ctx context.Context, names []string, relationTypes []string,
+) (*types.GraphData, error) {
+	session := s.repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	labelExpr := s.repo.Label(s.namespace)
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		query := `
+			MATCH (n:` + labelExpr + `)-[r]-(m:` + labelExpr + `)
+			WHERE n.name IN $names AND (size($types) = 0 OR type(r) IN $types)
+			RETURN startNode(r) AS s, r, endNode(r) AS e
+		`
+		if relationTypes == nil {
+			relationTypes = []string{}
+		}
+		res, err := tx.Run(ctx, query, map[string]interface{}{"names": names, "types": relationTypes})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run query: %v", err)
+		}
+
+		graphData := &types.GraphData{}
+		relSeen := make(map[string]bool)
+		for res.Next(ctx) {
+			record := res.Record()
+			start, _ := record.Get("s")
+			rel, _ := record.Get("r")
+			end, _ := record.Get("e")
+			startNode := start.(neo4j.Node)
+			endNode := end.(neo4j.Node)
+			relData := rel.(neo4j.Relationship)
+
+			for _, node := range []neo4j.Node{startNode, endNode} {
+				graphData.Node = append(graphData.Node, &types.GraphNode{
+					Name:       propString(node.Props, "name"),
+					Chunks:     propStrings(node.Props, "chunks"),
+					Attributes: propStrings(node.Props, "attributes"),
+				})
+			}
+			// An edge between two seed nodes is matched from both ends.
+			if relSeen[relData.ElementId] {
+				continue
+			}
+			relSeen[relData.ElementId] = true
+			graphData.Relation = append(graphData.Relation, &types.GraphRelation{
+				Node1: propString(startNode.Props, "name"),
+				Node2: propString(endNode.Props, "name"),
+				Type:  relData.Type,
+				Chunks: graphwalk.EvidenceChunks(
+					propStrings(relData.Props, "chunks"),
+					propStrings(startNode.Props, "chunks"),
+					propStrings(endNode.Props, "chunks"),
+				),
+			})
+		}
+		return graphData, nil
+	})
+	if err != nil {
+		logger.Errorf(ctx, "graph neighbour query failed: %v", err)
+		return nil, err
+	}
+	return result.(*types.GraphData), nil
+}
+
+func propString(props map[string]any, key string) string {
+	v, _ := props[key].(string)
+	return v
+}
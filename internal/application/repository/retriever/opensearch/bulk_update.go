@@ -35,7 +35,7 @@ func (r *Repository) BatchUpdateChunkEnabledStatus(ctx context.Context, chunkSta
 			continue
 		}
 		sort.Strings(ids)
-		if err := r.updateByQueryScript(ctx, ids,
+		if err := r.updateByQueryScript(ctx, "chunk_id", ids,
 			"ctx._source.is_enabled = params.v", map[string]any{"v": v}); err != nil {
 			return err
 		}
@@ -60,7 +60,7 @@ func (r *Repository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[
 	for _, tag := range tags {
 		ids := groups[tag]
 		sort.Strings(ids)
-		if err := r.updateByQueryScript(ctx, ids,
+		if err := r.updateByQueryScript(ctx, "chunk_id", ids,
 			"ctx._source.tag_id = params.v", map[string]any{"v": tag}); err != nil {
 			return err
 		}
@@ -68,16 +68,36 @@ func (r *Repository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[
 	return nil
 }
 
+// BatchUpdateKnowledgeMetadata replaces the metadata of every doc of each
+// knowledge, one _update_by_query per knowledge.
+func (r *Repository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadata map[string]map[string]any,
+) error {
+	knowledgeIDs := make([]string, 0, len(knowledgeMetadata))
+	for id := range knowledgeMetadata {
+		knowledgeIDs = append(knowledgeIDs, id)
+	}
+	sort.Strings(knowledgeIDs)
+	for _, id := range knowledgeIDs {
+		if err := r.updateByQueryScript(ctx, "knowledge_id", []string{id},
+			"ctx._source.metadata = params.v", map[string]any{"v": knowledgeMetadata[id]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // updateByQueryScript runs an _update_by_query over the cross-dim <base>_*
-// pattern, matching the given chunk ids via a terms filter and applying a
-// constant Painless source with caller values flowing only through bound
-// params (Painless-injection-safe).
+// pattern, matching the given ids of a keyword field (chunk_id or
+// knowledge_id) via a terms filter and applying a constant Painless source
+// with caller values flowing only through bound params
+// (Painless-injection-safe).
 func (r *Repository) updateByQueryScript(
-	ctx context.Context, chunkIDs []string, source string, params map[string]any,
+	ctx context.Context, field string, ids []string, source string, params map[string]any,
 ) error {
 	body, err := json.Marshal(map[string]any{
 		"query": map[string]any{
-			"terms": map[string]any{"chunk_id": chunkIDs},
+			"terms": map[string]any{field: ids},
 		},
 		"script": map[string]any{
 			"lang":   "painless",
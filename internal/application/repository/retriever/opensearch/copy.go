@@ -23,16 +23,17 @@ const copyBatchSize = 500
 // embedding vector and is_recommended, which the retrieve-path hit struct
 // omits because retrieval does not need them.
 type copySourceDoc struct {
-	Content         string    `json:"content"`
-	SourceID        string    `json:"source_id"`
-	SourceType      int       `json:"source_type"`
-	ChunkID         string    `json:"chunk_id"`
-	KnowledgeID     string    `json:"knowledge_id"`
-	KnowledgeBaseID string    `json:"knowledge_base_id"`
-	TagID           string    `json:"tag_id"`
-	IsEnabled       bool      `json:"is_enabled"`
-	IsRecommended   bool      `json:"is_recommended"`
-	Embedding       []float32 `json:"embedding"`
+	Content         string         `json:"content"`
+	SourceID        string         `json:"source_id"`
+	SourceType      int            `json:"source_type"`
+	ChunkID         string         `json:"chunk_id"`
+	KnowledgeID     string         `json:"knowledge_id"`
+	KnowledgeBaseID string         `json:"knowledge_base_id"`
+	TagID           string         `json:"tag_id"`
+	IsEnabled       bool           `json:"is_enabled"`
+	IsRecommended   bool           `json:"is_recommended"`
+	Embedding       []float32      `json:"embedding"`
+	Metadata        map[string]any `json:"metadata"`
 }
 
 // transformSourceID mirrors the sibling drivers' source_id remap:
@@ -126,6 +127,7 @@ func (r *Repository) CopyIndices(
 				TagID:           d.TagID,
 				IsEnabled:       d.IsEnabled,
 				IsRecommended:   d.IsRecommended,
+				Metadata:        d.Metadata,
 			})
 		}
 		if len(infos) > 0 {
@@ -191,3 +191,53 @@ func TestInspectByQueryResponse(t *testing.T) {
 		}
 	})
 }
+
+// TestExportIndices_SearchAfterCursor verifies the export scan returns the
+// stored embedding keyed by source id and resumes via search_after from the
+// cursor of the previous page.
+func TestExportIndices_SearchAfterCursor(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/weknora_test_3/") {
+			t.Errorf("unexpected index path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"hits":{"hits":[
+			{"_id":"doc1","sort":["src1",0,"chunk1"],"_source":{"content":"c","source_id":"src1","source_type":0,"chunk_id":"chunk1","knowledge_id":"k1","knowledge_base_id":"kb1","is_enabled":true,"embedding":[0.1,0.2,0.3]}}
+		]}}`))
+	}
+	repo, ts := newTestRepo(t, handler)
+	defer ts.Close()
+
+	page, err := repo.ExportIndices(context.Background(), "kb1", 3, "manual", "", 1)
+	if err != nil {
+		t.Fatalf("ExportIndices: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].ID != "doc1" || page.Entries[0].ChunkID != "chunk1" {
+		t.Fatalf("unexpected entries %+v", page.Entries)
+	}
+	if got := page.Embeddings["src1"]; len(got) != 3 {
+		t.Errorf("want embedding keyed by source id, got %v", page.Embeddings)
+	}
+	if page.NextCursor != `["src1",0,"chunk1"]` {
+		t.Fatalf("unexpected cursor %q", page.NextCursor)
+	}
+
+	if _, err := repo.ExportIndices(context.Background(), "kb1", 3, "manual", page.NextCursor, 1); err != nil {
+		t.Fatalf("ExportIndices with cursor: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Contains(bodies[0], "search_after") {
+		t.Errorf("first page must not send search_after\n%s", bodies[0])
+	}
+	if !strings.Contains(bodies[1], `"search_after":["src1",0,"chunk1"]`) {
+		t.Errorf("second page missing search_after\n%s", bodies[1])
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	osapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 )
@@ -27,8 +28,9 @@ import (
 // dim-less keywords index). Missing "chunk_enabled" → IndexInfo's own
 // IsEnabled field is used as-is.
 
-// Save indexes a single chunk. Idempotent: _id is set to chunk_id so
-// re-indexing the same chunk overwrites instead of creating a duplicate.
+// Save indexes a single chunk. Idempotent: _id is set to chunk_id (or the
+// caller-supplied IndexInfo.ID) so re-indexing the same chunk overwrites
+// instead of creating a duplicate.
 func (r *Repository) Save(
 	ctx context.Context,
 	info *types.IndexInfo,
@@ -59,7 +61,7 @@ func (r *Repository) Save(
 	}
 	req := osapi.IndexReq{
 		Index:      targetIndex,
-		DocumentID: info.ChunkID, // _id = chunk_id (idempotent)
+		DocumentID: docID(info), // _id = chunk_id (idempotent)
 		Body:       bytes.NewReader(doc),
 	}
 	resp, err := r.client.Index(ctx, req)
@@ -153,7 +155,7 @@ func buildBulkBody(alias string, infos []*types.IndexInfo, emb [][]float32, para
 		action := map[string]any{
 			"index": map[string]any{
 				"_index": alias,
-				"_id":    info.ChunkID,
+				"_id":    docID(info),
 			},
 		}
 		actionJSON, err := json.Marshal(action)
@@ -244,6 +246,9 @@ func toDoc(info *types.IndexInfo, emb []float32, enabled bool) map[string]any {
 	if len(emb) > 0 {
 		doc["embedding"] = emb
 	}
+	if len(info.Metadata) > 0 {
+		doc[elasticsearchRetriever.MetadataProperty] = info.Metadata
+	}
 	return doc
 }
 
@@ -349,8 +354,24 @@ func (r *Repository) DeleteByKnowledgeIDList(
 	return r.deleteByList(ctx, knowledgeIDs, dim, "knowledge_id")
 }
 
+func (r *Repository) DeleteByKnowledgeBaseIDList(
+	ctx context.Context, knowledgeBaseIDs []string, dim int, _ string,
+) error {
+	return r.deleteByList(ctx, knowledgeBaseIDs, dim, "knowledge_base_id")
+}
+
+// docID is the document _id for info. Callers set IndexInfo.ID when the
+// same chunk is indexed twice in one index, e.g. a re-embedding shadow
+// index under another knowledge_base_id label.
+func docID(info *types.IndexInfo) string {
+	if info.ID != "" {
+		return info.ID
+	}
+	return info.ChunkID
+}
+
 // deleteByList factors the common cap / empty / ensureReady / dispatch
-// logic out of the three DeleteBy* methods. dim==0 routes to the
+// logic out of the DeleteBy* methods. dim==0 routes to the
 // dim-less keywords index.
 func (r *Repository) deleteByList(
 	ctx context.Context, ids []string, dim int, field string,
@@ -0,0 +1,148 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	osapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// exportSort orders the export scan. The ID fields are keyword-mapped, and
+// (source_id, source_type) identifies an entry within a knowledge base.
+var exportSort = []any{
+	map[string]any{"source_id": "asc"},
+	map[string]any{"source_type": "asc"},
+	map[string]any{"chunk_id": "asc"},
+}
+
+// exportIndex is the index holding a knowledge base's docs for dim; dim==0
+// is the dim-less keywords index, mirroring the BatchSave routing.
+func (r *Repository) exportIndex(dim int) string {
+	if dim == 0 {
+		return r.keywordsIndex()
+	}
+	return r.indexAlias(dim)
+}
+
+// ExportIndices pages through the docs of a knowledge base label with
+// search_after. Unlike the from/size scan of CopyIndices it is not bounded by
+// max_result_window. The cursor is the JSON-encoded sort values of the last
+// exported doc; a missing index exports nothing.
+func (r *Repository) ExportIndices(
+	ctx context.Context, knowledgeBaseID string, dim int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	query := map[string]any{
+		"size": limit,
+		"sort": exportSort,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": []any{
+					map[string]any{"term": map[string]any{"knowledge_base_id": knowledgeBaseID}},
+				},
+			},
+		},
+	}
+	if cursor != "" {
+		var after []any
+		if err := json.Unmarshal([]byte(cursor), &after); err != nil {
+			return nil, fmt.Errorf("opensearch: invalid export cursor: %w", err)
+		}
+		query["search_after"] = after
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("opensearch: marshal export body: %w", err)
+	}
+
+	page := &types.IndexExportPage{Embeddings: make(map[string][]float32)}
+	req := osapi.SearchReq{Indices: []string{r.exportIndex(dim)}, Body: bytes.NewReader(body)}
+	resp, err := r.client.Search(ctx, &req)
+	if err != nil {
+		if isNotFound(err) {
+			return page, nil
+		}
+		return nil, wrapTransport(err)
+	}
+	defer drainAndClose(resp.Inspect().Response.Body)
+	var parsed struct {
+		Hits struct {
+			Hits []struct {
+				ID     string          `json:"_id"`
+				Source copySourceDoc   `json:"_source"`
+				Sort   json.RawMessage `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Inspect().Response.Body, 64<<20)).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("opensearch: parse export response: %w", ErrTransport)
+	}
+
+	hits := parsed.Hits.Hits
+	page.Entries = make([]*types.IndexInfo, 0, len(hits))
+	for _, h := range hits {
+		d := &h.Source
+		page.Entries = append(page.Entries, &types.IndexInfo{
+			ID:              h.ID,
+			Content:         d.Content,
+			SourceID:        d.SourceID,
+			SourceType:      types.SourceType(d.SourceType),
+			ChunkID:         d.ChunkID,
+			KnowledgeID:     d.KnowledgeID,
+			KnowledgeBaseID: d.KnowledgeBaseID,
+			KnowledgeType:   knowledgeType,
+			TagID:           d.TagID,
+			IsEnabled:       d.IsEnabled,
+			IsRecommended:   d.IsRecommended,
+			Metadata:        d.Metadata,
+		})
+		if len(d.Embedding) > 0 {
+			page.Embeddings[d.SourceID] = d.Embedding
+		}
+	}
+	if len(hits) == limit {
+		page.NextCursor = string(hits[len(hits)-1].Sort)
+	}
+	return page, nil
+}
+
+// CountIndices counts the docs of a knowledge base label. The index is
+// refreshed first so docs written by the preceding bulk requests are
+// counted; a missing index counts as empty.
+func (r *Repository) CountIndices(
+	ctx context.Context, knowledgeBaseID string, dim int, _ string,
+) (int64, error) {
+	index := r.exportIndex(dim)
+	refreshResp, err := r.client.Indices.Refresh(ctx, &osapi.IndicesRefreshReq{Indices: []string{index}})
+	if err != nil {
+		if isNotFound(err) {
+			return 0, nil
+		}
+		return 0, wrapTransport(err)
+	}
+	if refreshResp != nil {
+		drainAndClose(refreshResp.Inspect().Response.Body)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"query": map[string]any{"term": map[string]any{"knowledge_base_id": knowledgeBaseID}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("opensearch: marshal count body: %w", err)
+	}
+	resp, err := r.client.Indices.Count(ctx, &osapi.IndicesCountReq{
+		Indices: []string{index},
+		Body:    bytes.NewReader(body),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return 0, nil
+		}
+		return 0, wrapTransport(err)
+	}
+	return int64(resp.Count), nil
+}
@@ -8,6 +8,7 @@ import (
 
 	osapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
 	"github.com/Tencent/WeKnora/internal/logger"
 )
 
@@ -37,7 +38,10 @@ func (r *Repository) createIndexAndAlias(ctx context.Context, dim int) error {
 		return fmt.Errorf("alias check %s: %w", alias, err)
 	}
 	if exists {
-		return nil // another writer already set it up
+		// another writer already set it up; indices created before
+		// metadata filtering still need the metadata templates.
+		r.putMetadataTemplates(ctx, alias)
+		return nil
 	}
 
 	body, err := buildIndexMapping(r.cfg, dim)
@@ -109,7 +113,8 @@ func buildIndexMapping(cfg internalCfg, dim int) ([]byte, error) {
 			} `json:"index"`
 		} `json:"settings"`
 		Mappings struct {
-			Properties map[string]any `json:"properties"`
+			DynamicTemplates []map[string]any `json:"dynamic_templates"`
+			Properties       map[string]any   `json:"properties"`
 		} `json:"mappings"`
 	}
 	var m mapping
@@ -118,6 +123,7 @@ func buildIndexMapping(cfg internalCfg, dim int) ([]byte, error) {
 	m.Settings.Index.NumberOfReplicas = cfg.replicas
 	m.Settings.Index.RefreshInterval = "1s"
 	m.Settings.Index.KnnAlgoParamEFSearch = cfg.efSearch
+	m.Mappings.DynamicTemplates = elasticsearchRetriever.MetadataDynamicTemplates()
 	m.Mappings.Properties = map[string]any{
 		"embedding": map[string]any{
 			"type":      "knn_vector",
@@ -165,13 +171,15 @@ func buildKeywordsMapping(cfg internalCfg) ([]byte, error) {
 			} `json:"index"`
 		} `json:"settings"`
 		Mappings struct {
-			Properties map[string]any `json:"properties"`
+			DynamicTemplates []map[string]any `json:"dynamic_templates"`
+			Properties       map[string]any   `json:"properties"`
 		} `json:"mappings"`
 	}
 	var m mapping
 	m.Settings.Index.NumberOfShards = cfg.shards
 	m.Settings.Index.NumberOfReplicas = cfg.replicas
 	m.Settings.Index.RefreshInterval = "1s"
+	m.Mappings.DynamicTemplates = elasticsearchRetriever.MetadataDynamicTemplates()
 	m.Mappings.Properties = map[string]any{
 		"content":           map[string]any{"type": "text", "analyzer": "standard"},
 		"chunk_id":          map[string]any{"type": "keyword"},
@@ -213,6 +221,7 @@ func (r *Repository) ensureKeywordsIndex(ctx context.Context) error {
 		return err
 	}
 	if exists {
+		r.putMetadataTemplates(ctx, name)
 		r.keywordsReady = true
 		r.keywordsErr = nil
 		return nil
@@ -242,6 +251,30 @@ func (r *Repository) ensureKeywordsIndex(ctx context.Context) error {
 	return nil
 }
 
+// putMetadataTemplates adds the metadata dynamic templates to an index
+// created before metadata filtering. Such an index has no metadata fields
+// yet, so the templates apply to every one written from now on. Best-effort:
+// a failure only leaves new metadata fields dynamically typed.
+func (r *Repository) putMetadataTemplates(ctx context.Context, index string) {
+	body, err := json.Marshal(map[string]any{
+		"dynamic_templates": elasticsearchRetriever.MetadataDynamicTemplates(),
+	})
+	if err != nil {
+		return
+	}
+	resp, err := r.client.Indices.Mapping.Put(ctx, osapi.MappingPutReq{
+		Indices: []string{index},
+		Body:    bytes.NewReader(body),
+	})
+	if err != nil {
+		logger.GetLogger(ctx).Warnf("[OpenSearch] failed to add metadata templates to %s: %v", index, wrapTransport(err))
+		return
+	}
+	if resp != nil {
+		drainAndClose(resp.Inspect().Response.Body)
+	}
+}
+
 // indicesCreate is the low-level wrapper around Indices.Create. Body is
 // the JSON mapping produced by buildIndexMapping.
 func (r *Repository) indicesCreate(ctx context.Context, name string, body []byte) error {
@@ -370,7 +403,8 @@ type embFingerprint struct {
 func extractEmbeddingFingerprint(body []byte) (embFingerprint, error) {
 	var m struct {
 		Mappings struct {
-			Properties map[string]any `json:"properties"`
+			DynamicTemplates []map[string]any `json:"dynamic_templates"`
+			Properties       map[string]any   `json:"properties"`
 		} `json:"mappings"`
 	}
 	if err := json.Unmarshal(body, &m); err != nil {
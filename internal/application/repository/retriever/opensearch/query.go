@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	elasticsearchRetriever "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch"
 	"github.com/Tencent/WeKnora/internal/types"
 )
 
@@ -28,6 +29,9 @@ type retrieveFilters struct {
 	ExcludeChunkIDs     []string
 	ExcludeKnowledgeIDs []string
 	IncludeDisabled     bool
+	// Metadata is a normalised metadata filter, translated clause by
+	// clause; its values are bound as term / range operands.
+	Metadata *types.MetadataFilter
 }
 
 // fromParams projects RetrieveParams to retrieveFilters, applying the
@@ -41,6 +45,7 @@ func fromParams(p types.RetrieveParams) *retrieveFilters {
 		TagIDs:              p.TagIDs,
 		ExcludeChunkIDs:     p.ExcludeChunkIDs,
 		ExcludeKnowledgeIDs: p.ExcludeKnowledgeIDs,
+		Metadata:            p.MetadataFilter,
 		// IncludeDisabled stays false — set explicitly by admin callers
 		// only. Driver receives this from a typed field, not from
 		// AdditionalParams, so the contract is checked at compile time.
@@ -86,6 +91,9 @@ func (f *retrieveFilters) toBoolMust() []map[string]any {
 			},
 		})
 	}
+	if f.Metadata != nil {
+		must = append(must, elasticsearchRetriever.MetadataFilterQuery(f.Metadata))
+	}
 	if !f.IncludeDisabled {
 		must = append(must, map[string]any{
 			"term": map[string]any{"is_enabled": true},
@@ -450,6 +450,79 @@ func TestBuildKeywordQuery_AppendsContentMatch(t *testing.T) {
 	}
 }
 
+func TestRetrieveFilters_TranslatesMetadataFilter(t *testing.T) {
+	t.Parallel()
+	filter, err := (&types.MetadataFilter{Op: types.MetadataFilterAnd, Filters: []*types.MetadataFilter{
+		{Op: types.MetadataFilterEq, Key: "region", Value: "EU"},
+		{Op: types.MetadataFilterGt, Key: "effective_date", Value: "2025-01-01"},
+	}}).Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	body, err := buildKeywordQuery("policy", 5, 0, fromParams(types.RetrieveParams{MetadataFilter: filter}))
+	if err != nil {
+		t.Fatalf("buildKeywordQuery: %v", err)
+	}
+	region := "metadata." + types.MetadataField(types.MetadataKindString, "region")
+	date := "metadata." + types.MetadataField(types.MetadataKindDate, "effective_date")
+	for _, want := range []string{
+		`"term":{"` + region + `":"EU"}`,
+		`"range":{"` + date + `":{"gt":1735689600}}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metadata clause %s missing: %s", want, body)
+		}
+	}
+}
+
+func TestBuildIndexMapping_TypesMetadataFields(t *testing.T) {
+	t.Parallel()
+	body, err := buildIndexMapping(internalCfg{shards: 1, knnEngine: "lucene"}, 8)
+	if err != nil {
+		t.Fatalf("buildIndexMapping: %v", err)
+	}
+	for _, want := range []string{
+		`"mapping":{"type":"keyword"},"path_match":"metadata.s_*"`,
+		`"mapping":{"type":"double"},"path_match":"metadata.d_*"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("dynamic template %s missing: %s", want, body)
+		}
+	}
+}
+
+func TestBatchUpdateKnowledgeMetadata_ScriptsPerKnowledge(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var bodies []string
+	repo, ts := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(raw))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"total":1,"updated":1,"failures":[]}`))
+	})
+	defer ts.Close()
+
+	err := repo.BatchUpdateKnowledgeMetadata(context.Background(), map[string]map[string]any{
+		"k1": {"s_726567696f6e": "EU"},
+		"k2": nil,
+	})
+	if err != nil {
+		t.Fatalf("BatchUpdateKnowledgeMetadata: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("want one update_by_query per knowledge, got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"terms":{"knowledge_id":["k1"]}`) ||
+		!strings.Contains(bodies[0], `"v":{"s_726567696f6e":"EU"}`) {
+		t.Errorf("k1 update body: %s", bodies[0])
+	}
+	if !strings.Contains(bodies[1], `"v":null`) {
+		t.Errorf("k2 must clear metadata: %s", bodies[1])
+	}
+}
+
 // ============================================================================
 // toDoc — keyword-only path omits embedding field; source_type is integer
 // ============================================================================
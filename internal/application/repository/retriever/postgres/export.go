@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// ExportIndices pages through the entries of a knowledge base label in
+// primary-key order. The cursor is the last exported row ID.
+func (g *pgRepository) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	var lastID uint64
+	if cursor != "" {
+		id, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid export cursor %q: %w", cursor, err)
+		}
+		lastID = id
+	}
+
+	var rows []*pgVector
+	if err := g.db.WithContext(ctx).
+		Where("knowledge_base_id = ? AND id > ?", knowledgeBaseID, lastID).
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		logger.GetLogger(ctx).Errorf("[Postgres] Failed to export indices: %v", err)
+		return nil, err
+	}
+
+	page := &types.IndexExportPage{
+		Entries:    make([]*types.IndexInfo, 0, len(rows)),
+		Embeddings: make(map[string][]float32, len(rows)),
+	}
+	for _, row := range rows {
+		page.Entries = append(page.Entries, &types.IndexInfo{
+			ID:              strconv.FormatUint(uint64(row.ID), 10),
+			Content:         row.Content,
+			SourceID:        row.SourceID,
+			SourceType:      types.SourceType(row.SourceType),
+			ChunkID:         row.ChunkID,
+			KnowledgeID:     row.KnowledgeID,
+			KnowledgeBaseID: row.KnowledgeBaseID,
+			KnowledgeType:   knowledgeType,
+			TagID:           row.TagID,
+			IsEnabled:       row.IsEnabled,
+			Metadata:        metadataFields(row.Metadata),
+		})
+		if row.Dimension > 0 {
+			page.Embeddings[row.SourceID] = row.Embedding.Slice()
+		}
+	}
+	if len(rows) == limit {
+		page.NextCursor = strconv.FormatUint(uint64(rows[len(rows)-1].ID), 10)
+	}
+	return page, nil
+}
+
+// CountIndices counts the entries of a knowledge base label.
+func (g *pgRepository) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	var count int64
+	if err := g.db.WithContext(ctx).Model(&pgVector{}).
+		Where("knowledge_base_id = ?", knowledgeBaseID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
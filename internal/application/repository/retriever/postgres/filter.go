@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"gorm.io/gorm"
+)
+
+// metadataFilterSQL translates a normalised metadata filter into a condition
+// on the metadata column. bind appends a value and returns its placeholder,
+// so the same translation serves gorm ("?") and raw ("$N") queries.
+//
+// Equality uses JSONB containment, which the GIN index on metadata serves;
+// ranges compare the numeric value of the field. Field names are
+// [a-z0-9_] (types.MetadataField) and are inlined as literals.
+func metadataFilterSQL(f *types.MetadataFilter, bind func(any) string) (string, error) {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		parts := make([]string, 0, len(f.Filters))
+		for _, child := range f.Filters {
+			part, err := metadataFilterSQL(child, bind)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		sep := " AND "
+		if f.Op == types.MetadataFilterOr {
+			sep = " OR "
+		}
+		return "(" + strings.Join(parts, sep) + ")", nil
+	case types.MetadataFilterEq:
+		return metadataContains(f.Field, f.Value, bind)
+	case types.MetadataFilterIn:
+		parts := make([]string, 0, len(f.Values))
+		for _, value := range f.Values {
+			part, err := metadataContains(f.Field, value, bind)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	}
+	if !f.Op.IsRange() {
+		return "", fmt.Errorf("unsupported metadata filter operator %q", f.Op)
+	}
+	op := map[types.MetadataFilterOp]string{
+		types.MetadataFilterGt:  ">",
+		types.MetadataFilterGte: ">=",
+		types.MetadataFilterLt:  "<",
+		types.MetadataFilterLte: "<=",
+	}[f.Op]
+	return fmt.Sprintf("(metadata->>'%s')::float8 %s %s", f.Field, op, bind(f.Value)), nil
+}
+
+func metadataContains(field string, value any, bind func(any) string) (string, error) {
+	doc, err := json.Marshal(map[string]any{field: value})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("metadata @> %s::jsonb", bind(string(doc))), nil
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata of every entry of each
+// knowledge.
+func (g *pgRepository) BatchUpdateKnowledgeMetadata(ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	if len(knowledgeMetadata) == 0 {
+		return nil
+	}
+	logger.GetLogger(ctx).Infof("[Postgres] Batch updating knowledge metadata, count: %d", len(knowledgeMetadata))
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for knowledgeID, fields := range knowledgeMetadata {
+			result := tx.Model(&pgVector{}).
+				Where("knowledge_id = ?", knowledgeID).
+				Update("metadata", metadataJSON(fields))
+			if result.Error != nil {
+				logger.GetLogger(ctx).Errorf("[Postgres] Failed to update metadata of knowledge %s: %v",
+					knowledgeID, result.Error)
+				return result.Error
+			}
+		}
+		return nil
+	})
+}
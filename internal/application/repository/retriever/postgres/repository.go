@@ -146,6 +146,21 @@ func (g *pgRepository) DeleteByKnowledgeIDList(ctx context.Context, knowledgeIDL
 	return nil
 }
 
+// DeleteByKnowledgeBaseIDList deletes indices by knowledge base IDs
+func (g *pgRepository) DeleteByKnowledgeBaseIDList(ctx context.Context, knowledgeBaseIDList []string, dimension int, knowledgeType string) error {
+	if len(knowledgeBaseIDList) == 0 {
+		return nil
+	}
+	logger.GetLogger(ctx).Infof("[Postgres] Deleting indices by knowledge base IDs, count: %d", len(knowledgeBaseIDList))
+	result := g.db.WithContext(ctx).Where("knowledge_base_id IN ?", knowledgeBaseIDList).Delete(&pgVector{})
+	if result.Error != nil {
+		logger.GetLogger(ctx).Errorf("[Postgres] Failed to delete indices by knowledge base IDs: %v", result.Error)
+		return result.Error
+	}
+	logger.GetLogger(ctx).Infof("[Postgres] Successfully deleted %d indices by knowledge base IDs", result.RowsAffected)
+	return nil
+}
+
 // Retrieve handles retrieval requests and routes to appropriate method
 func (g *pgRepository) Retrieve(ctx context.Context, params types.RetrieveParams) ([]*types.RetrieveResult, error) {
 	logger.GetLogger(ctx).Debugf("[Postgres] Processing retrieval request of type: %s", params.RetrieverType)
@@ -185,6 +200,12 @@ func (g *pgRepository) KeywordsRetrieve(ctx context.Context,
 			Values: common.ToInterfaceSlice(params.KnowledgeIDs),
 		})
 	}
+	if len(params.ExcludeKnowledgeIDs) > 0 {
+		conds = append(conds, clause.Not(clause.IN{
+			Column: "knowledge_id",
+			Values: common.ToInterfaceSlice(params.ExcludeKnowledgeIDs),
+		}))
+	}
 	// Filter by tag IDs if specified
 	if len(params.TagIDs) > 0 {
 		logger.GetLogger(ctx).Debugf("[Postgres] Filtering by tag IDs: %v", params.TagIDs)
@@ -194,6 +215,18 @@ func (g *pgRepository) KeywordsRetrieve(ctx context.Context,
 		})
 	}
 
+	if params.MetadataFilter != nil {
+		var vars []any
+		sql, err := metadataFilterSQL(params.MetadataFilter, func(v any) string {
+			vars = append(vars, v)
+			return "?"
+		})
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, clause.Expr{SQL: sql, Vars: vars})
+	}
+
 	// Use ParadeDB's ||| operator for matching any token
 	conds = append(conds, clause.Expr{
 		SQL:  "content ||| ?",
@@ -314,6 +347,16 @@ func (g *pgRepository) VectorRetrieve(ctx context.Context,
 		whereParts = append(whereParts, fmt.Sprintf("knowledge_id IN (%s)",
 			strings.Join(placeholders, ", ")))
 	}
+	if len(params.ExcludeKnowledgeIDs) > 0 {
+		placeholders := make([]string, len(params.ExcludeKnowledgeIDs))
+		paramStart := len(allVars) + 1
+		for i := range params.ExcludeKnowledgeIDs {
+			placeholders[i] = fmt.Sprintf("$%d", paramStart+i)
+			allVars = append(allVars, params.ExcludeKnowledgeIDs[i])
+		}
+		whereParts = append(whereParts, fmt.Sprintf("knowledge_id NOT IN (%s)",
+			strings.Join(placeholders, ", ")))
+	}
 	// Filter by tag IDs if specified
 	if len(params.TagIDs) > 0 {
 		logger.GetLogger(ctx).Debugf(
@@ -330,6 +373,17 @@ func (g *pgRepository) VectorRetrieve(ctx context.Context,
 			strings.Join(placeholders, ", ")))
 	}
 
+	if params.MetadataFilter != nil {
+		sql, err := metadataFilterSQL(params.MetadataFilter, func(v any) string {
+			allVars = append(allVars, v)
+			return fmt.Sprintf("$%d", len(allVars))
+		})
+		if err != nil {
+			return nil, err
+		}
+		whereParts = append(whereParts, sql)
+	}
+
 	// is_enabled filter
 	whereParts = append(whereParts, fmt.Sprintf("(is_enabled IS NULL OR is_enabled = $%d)", len(allVars)+1))
 	allVars = append(allVars, true)
@@ -581,6 +635,7 @@ func (g *pgRepository) CopyIndices(ctx context.Context,
 				KnowledgeBaseID: targetKnowledgeBaseID, // Update to target knowledge base ID
 				Dimension:       sourceVector.Dimension,
 				Embedding:       sourceVector.Embedding, // Copy the vector embedding directly, avoid recalculation
+				Metadata:        sourceVector.Metadata,
 			}
 
 			targetVectors = append(targetVectors, targetVector)
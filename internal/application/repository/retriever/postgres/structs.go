@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"encoding/json"
 	"maps"
 	"slices"
 	"strconv"
@@ -26,6 +27,7 @@ type pgVector struct {
 	Dimension       int                 `json:"dimension"         gorm:"column:dimension;not null"`
 	Embedding       pgvector.HalfVector `json:"embedding"         gorm:"column:embedding;not null"`
 	IsEnabled       bool                `json:"is_enabled"        gorm:"column:is_enabled;default:true;index"`
+	Metadata        types.JSON          `json:"metadata"          gorm:"column:metadata;type:jsonb"`
 }
 
 // pgVectorWithScore extends pgVector with similarity score field
@@ -67,6 +69,7 @@ func toDBVectorEmbedding(indexInfo *types.IndexInfo, additionalParams map[string
 		TagID:           indexInfo.TagID,
 		Content:         common.CleanInvalidUTF8(indexInfo.Content),
 		IsEnabled:       indexInfo.IsEnabled,
+		Metadata:        metadataJSON(indexInfo.Metadata),
 	}
 	// Add embedding data if available in additionalParams
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), "embedding") {
@@ -86,6 +89,30 @@ func toDBVectorEmbedding(indexInfo *types.IndexInfo, additionalParams map[string
 	return pgVector
 }
 
+// metadataJSON encodes the metadata fields of an index entry; nil stays NULL.
+func metadataJSON(fields map[string]any) types.JSON {
+	if len(fields) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return types.JSON(data)
+}
+
+// metadataFields decodes the metadata column of an index entry.
+func metadataFields(data types.JSON) map[string]any {
+	if len(data) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil || len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
 // fromDBVectorEmbeddingWithScore converts pgVectorWithScore to IndexWithScore domain model
 func fromDBVectorEmbeddingWithScore(embedding *pgVectorWithScore, matchType types.MatchType) *types.IndexWithScore {
 	return &types.IndexWithScore{
@@ -0,0 +1,114 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// ExportIndices scrolls through the points of a knowledge base label in the
+// dimension's collection, with their vectors. The cursor is the point ID the
+// next scroll starts from.
+func (q *qdrantRepository) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	page := &types.IndexExportPage{Embeddings: make(map[string][]float32)}
+	collectionName := q.getCollectionName(dimension)
+	exists, err := q.client.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	if !exists {
+		return page, nil
+	}
+
+	var offset *qdrant.PointId
+	if cursor != "" {
+		if num, err := strconv.ParseUint(cursor, 10, 64); err == nil {
+			offset = qdrant.NewIDNum(num)
+		} else {
+			offset = qdrant.NewID(cursor)
+		}
+	}
+	batchSize := uint32(limit)
+	points, next, err := q.client.ScrollAndOffset(ctx, &qdrant.ScrollPoints{
+		CollectionName: collectionName,
+		Filter: &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch(fieldKnowledgeBaseID, knowledgeBaseID)},
+		},
+		Limit:       &batchSize,
+		Offset:      offset,
+		WithPayload: qdrant.NewWithPayload(true),
+		WithVectors: qdrant.NewWithVectors(true),
+	})
+	if err != nil {
+		logger.GetLogger(ctx).Errorf("[Qdrant] Failed to export points: %v", err)
+		return nil, err
+	}
+
+	page.Entries = make([]*types.IndexInfo, 0, len(points))
+	for _, point := range points {
+		payload := point.Payload
+		info := &types.IndexInfo{
+			ID:              pointIDString(point.Id),
+			Content:         payload[fieldContent].GetStringValue(),
+			SourceID:        payload[fieldSourceID].GetStringValue(),
+			SourceType:      types.SourceType(payload[fieldSourceType].GetIntegerValue()),
+			ChunkID:         payload[fieldChunkID].GetStringValue(),
+			KnowledgeID:     payload[fieldKnowledgeID].GetStringValue(),
+			KnowledgeBaseID: payload[fieldKnowledgeBaseID].GetStringValue(),
+			KnowledgeType:   knowledgeType,
+			TagID:           payload[fieldTagID].GetStringValue(),
+			IsEnabled:       true,
+			Metadata:        metadataFromPayload(payload),
+		}
+		if v, ok := payload[fieldIsEnabled]; ok {
+			info.IsEnabled = v.GetBoolValue()
+		}
+		page.Entries = append(page.Entries, info)
+		if vector := point.Vectors.GetVector().GetDenseVector().GetData(); len(vector) > 0 {
+			page.Embeddings[info.SourceID] = vector
+		}
+	}
+	if next != nil {
+		page.NextCursor = pointIDString(next)
+	}
+	return page, nil
+}
+
+// CountIndices returns the exact number of points of a knowledge base label.
+func (q *qdrantRepository) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	collectionName := q.getCollectionName(dimension)
+	exists, err := q.client.CollectionExists(ctx, collectionName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	exact := true
+	count, err := q.client.Count(ctx, &qdrant.CountPoints{
+		CollectionName: collectionName,
+		Filter: &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch(fieldKnowledgeBaseID, knowledgeBaseID)},
+		},
+		Exact: &exact,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(count), nil
+}
+
+func pointIDString(id *qdrant.PointId) string {
+	if uuid := id.GetUuid(); uuid != "" {
+		return uuid
+	}
+	return strconv.FormatUint(id.GetNum(), 10)
+}
@@ -0,0 +1,169 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qdrant/go-client/qdrant"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// metadataCondition translates a normalised metadata filter into a condition
+// on the nested metadata payload. Numbers are matched with ranges, since
+// match conditions only take keywords, integers and booleans.
+func metadataCondition(f *types.MetadataFilter) (*qdrant.Condition, error) {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		children := make([]*qdrant.Condition, 0, len(f.Filters))
+		for _, child := range f.Filters {
+			cond, err := metadataCondition(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, cond)
+		}
+		if f.Op == types.MetadataFilterOr {
+			return qdrant.NewFilterAsCondition(&qdrant.Filter{Should: children}), nil
+		}
+		return qdrant.NewFilterAsCondition(&qdrant.Filter{Must: children}), nil
+	}
+
+	field := fieldMetadata + "." + f.Field
+	switch f.Op {
+	case types.MetadataFilterEq:
+		return metadataEqual(field, f.Value)
+	case types.MetadataFilterIn:
+		if keywords, ok := stringValues(f.Values); ok {
+			return qdrant.NewMatchKeywords(field, keywords...), nil
+		}
+		children := make([]*qdrant.Condition, 0, len(f.Values))
+		for _, value := range f.Values {
+			cond, err := metadataEqual(field, value)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, cond)
+		}
+		return qdrant.NewFilterAsCondition(&qdrant.Filter{Should: children}), nil
+	}
+	value, ok := f.Value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("metadata filter %q on %q needs a number", f.Op, f.Key)
+	}
+	r := &qdrant.Range{}
+	switch f.Op {
+	case types.MetadataFilterGt:
+		r.Gt = &value
+	case types.MetadataFilterGte:
+		r.Gte = &value
+	case types.MetadataFilterLt:
+		r.Lt = &value
+	case types.MetadataFilterLte:
+		r.Lte = &value
+	default:
+		return nil, fmt.Errorf("unsupported metadata filter operator %q", f.Op)
+	}
+	return qdrant.NewRange(field, r), nil
+}
+
+func metadataEqual(field string, value any) (*qdrant.Condition, error) {
+	switch v := value.(type) {
+	case string:
+		return qdrant.NewMatchKeyword(field, v), nil
+	case bool:
+		return qdrant.NewMatchBool(field, v), nil
+	case float64:
+		return qdrant.NewRange(field, &qdrant.Range{Gte: &v, Lte: &v}), nil
+	}
+	return nil, fmt.Errorf("unsupported metadata filter value %v", value)
+}
+
+func stringValues(values []any) ([]string, bool) {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, s)
+	}
+	return out, true
+}
+
+// metadataFromPayload decodes the metadata payload of a point.
+func metadataFromPayload(payload map[string]*qdrant.Value) map[string]any {
+	fields := payload[fieldMetadata].GetStructValue().GetFields()
+	if len(fields) == 0 {
+		return nil
+	}
+	metadata := make(map[string]any, len(fields))
+	for key, value := range fields {
+		switch kind := value.GetKind().(type) {
+		case *qdrant.Value_StringValue:
+			metadata[key] = kind.StringValue
+		case *qdrant.Value_DoubleValue:
+			metadata[key] = kind.DoubleValue
+		case *qdrant.Value_IntegerValue:
+			metadata[key] = float64(kind.IntegerValue)
+		case *qdrant.Value_BoolValue:
+			metadata[key] = kind.BoolValue
+		}
+	}
+	return metadata
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata payload of every point
+// of each knowledge.
+func (q *qdrantRepository) BatchUpdateKnowledgeMetadata(ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadata) == 0 {
+		return nil
+	}
+	log.Infof("[Qdrant] Batch updating knowledge metadata, count: %d", len(knowledgeMetadata))
+
+	collections, err := q.client.ListCollections(ctx)
+	if err != nil {
+		log.Errorf("[Qdrant] Failed to list collections: %v", err)
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, collectionName := range collections {
+		if len(collectionName) <= len(q.collectionBaseName) ||
+			collectionName[:len(q.collectionBaseName)] != q.collectionBaseName {
+			continue
+		}
+		for knowledgeID, metadata := range knowledgeMetadata {
+			// SetPayload merges top-level keys, so the metadata object is
+			// replaced as a whole.
+			payload, err := qdrant.TryValueMap(map[string]any{fieldMetadata: metadataPayload(metadata)})
+			if err != nil {
+				return fmt.Errorf("invalid metadata of knowledge %s: %w", knowledgeID, err)
+			}
+			_, err = q.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+				CollectionName: collectionName,
+				Payload:        payload,
+				PointsSelector: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+					Must: []*qdrant.Condition{qdrant.NewMatch(fieldKnowledgeID, knowledgeID)},
+				}),
+			})
+			if err != nil {
+				log.Errorf("[Qdrant] Failed to update metadata of knowledge %s in %s: %v",
+					knowledgeID, collectionName, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// metadataPayload is the payload value of IndexInfo.Metadata; documents
+// without metadata store an empty object.
+func metadataPayload(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return map[string]any{}
+	}
+	return metadata
+}
@@ -25,6 +25,7 @@ const (
 	fieldTagID            = "tag_id"
 	fieldEmbedding        = "embedding"
 	fieldIsEnabled        = "is_enabled"
+	fieldMetadata         = "metadata"
 )
 
 // NewQdrantRetrieveEngineRepository creates and initializes a new Qdrant repository.
@@ -181,7 +182,7 @@ func (q *qdrantRepository) Save(ctx context.Context,
 	}
 
 	collectionName := q.getCollectionName(dimension)
-	pointID := uuid.New().String()
+	pointID := pointIDFor(embedding)
 	point := &qdrant.PointStruct{
 		Id:      qdrant.NewID(pointID),
 		Vectors: qdrant.NewVectors(embeddingDB.Embedding...),
@@ -225,7 +226,7 @@ func (q *qdrantRepository) BatchSave(ctx context.Context,
 
 		dimension := len(embeddingDB.Embedding)
 		point := &qdrant.PointStruct{
-			Id:      qdrant.NewID(uuid.New().String()),
+			Id:      qdrant.NewID(pointIDFor(embedding)),
 			Vectors: qdrant.NewVectors(embeddingDB.Embedding...),
 			Payload: createPayload(embeddingDB),
 		}
@@ -329,6 +330,36 @@ func (q *qdrantRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return nil
 }
 
+// DeleteByKnowledgeBaseIDList removes points from the collection based on knowledge base IDs
+func (q *qdrantRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeBaseIDList) == 0 {
+		log.Warn("[Qdrant] Empty knowledge base ID list provided for deletion, skipping")
+		return nil
+	}
+
+	collectionName := q.getCollectionName(dimension)
+	log.Infof("[Qdrant] Deleting indices by knowledge base IDs from %s, count: %d", collectionName, len(knowledgeBaseIDList))
+
+	_, err := q.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: collectionName,
+		Points: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchKeywords(fieldKnowledgeBaseID, knowledgeBaseIDList...),
+			},
+		}),
+	})
+	if err != nil {
+		log.Errorf("[Qdrant] Failed to delete by knowledge base IDs: %v", err)
+		return fmt.Errorf("failed to delete by knowledge base IDs: %w", err)
+	}
+
+	log.Infof("[Qdrant] Successfully deleted documents by knowledge base IDs")
+	return nil
+}
+
 // DeleteBySourceIDList removes points from the collection based on source IDs
 func (q *qdrantRepository) DeleteBySourceIDList(ctx context.Context,
 	sourceIDList []string, dimension int, knowledgeType string,
@@ -486,13 +517,21 @@ func (q *qdrantRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMa
 	return nil
 }
 
-func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) *qdrant.Filter {
+func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) (*qdrant.Filter, error) {
 	must := make([]*qdrant.Condition, 0)
 	mustNot := make([]*qdrant.Condition, 0)
 
 	// Only retrieve enabled chunks
 	must = append(must, qdrant.NewMatchBool(fieldIsEnabled, true))
 
+	if params.MetadataFilter != nil {
+		cond, err := metadataCondition(params.MetadataFilter)
+		if err != nil {
+			return nil, err
+		}
+		must = append(must, cond)
+	}
+
 	// KnowledgeBaseIDs and KnowledgeIDs use AND logic
 	// - If only KnowledgeBaseIDs: search entire knowledge bases
 	// - If only KnowledgeIDs: search specific documents
@@ -521,7 +560,7 @@ func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) *qdrant.Fi
 		MustNot: mustNot,
 	}
 
-	return filter
+	return filter, nil
 }
 
 // Retrieve dispatches the retrieval operation to the appropriate method based on retriever type
@@ -566,7 +605,11 @@ func (q *qdrantRepository) VectorRetrieve(ctx context.Context,
 		return buildRetrieveResult(nil, types.VectorRetrieverType), nil
 	}
 
-	filter := q.getBaseFilter(params)
+	filter, err := q.getBaseFilter(params)
+	if err != nil {
+		log.Errorf("[Qdrant] Failed to build base filter: %v", err)
+		return nil, fmt.Errorf("failed to build filter: %w", err)
+	}
 
 	limit := uint64(params.TopK)
 	scoreThreshold := float32(params.Threshold)
@@ -648,7 +691,11 @@ func (q *qdrantRepository) KeywordsRetrieve(ctx context.Context,
 			continue
 		}
 
-		filter := q.getBaseFilter(params)
+		filter, err := q.getBaseFilter(params)
+		if err != nil {
+			log.Errorf("[Qdrant] Failed to build base filter: %v", err)
+			return nil, fmt.Errorf("failed to build filter: %w", err)
+		}
 
 		// Build should conditions for each token (OR logic)
 		// This allows matching documents that contain any of the query tokens
@@ -818,6 +865,9 @@ func (q *qdrantRepository) CopyIndices(ctx context.Context,
 				fieldTagID:           payload[fieldTagID].GetStringValue(),
 				fieldIsEnabled:       isEnabled,
 			})
+			if metadata, ok := payload[fieldMetadata]; ok {
+				newPayload[fieldMetadata] = metadata
+			}
 
 			var vectors *qdrant.Vectors
 			if vectorOutput := sourcePoint.Vectors.GetVector(); vectorOutput != nil {
@@ -868,6 +918,15 @@ func (q *qdrantRepository) CopyIndices(ctx context.Context,
 	return nil
 }
 
+// pointIDFor returns the caller-assigned ID when it is a UUID, so writing the
+// same entry twice overwrites the point, and a random UUID otherwise.
+func pointIDFor(embedding *types.IndexInfo) string {
+	if id, err := uuid.Parse(embedding.ID); err == nil {
+		return id.String()
+	}
+	return uuid.New().String()
+}
+
 func createPayload(embedding *QdrantVectorEmbedding) map[string]*qdrant.Value {
 	payload := map[string]any{
 		fieldContent:         embedding.Content,
@@ -878,6 +937,7 @@ func createPayload(embedding *QdrantVectorEmbedding) map[string]*qdrant.Value {
 		fieldKnowledgeBaseID: embedding.KnowledgeBaseID,
 		fieldTagID:           embedding.TagID,
 		fieldIsEnabled:       embedding.IsEnabled,
+		fieldMetadata:        metadataPayload(embedding.Metadata),
 	}
 	return qdrant.NewValueMap(payload)
 }
@@ -937,6 +997,7 @@ func toQdrantVectorEmbedding(embedding *types.IndexInfo, additionalParams map[st
 		KnowledgeBaseID: embedding.KnowledgeBaseID,
 		TagID:           embedding.TagID,
 		IsEnabled:       embedding.IsEnabled,
+		Metadata:        embedding.Metadata,
 	}
 	if additionalParams != nil {
 		if val, exists := additionalParams[fieldEmbedding]; exists {
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// ExportIndices pages through the entries of a knowledge base label in row ID
+// order, reading the vectors back from the vec0 tables. The cursor is the
+// last exported row ID.
+func (r *sqliteRepository) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, _ int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	var lastID uint64
+	if cursor != "" {
+		id, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid export cursor %q: %w", cursor, err)
+		}
+		lastID = id
+	}
+
+	var rows []sqliteEmbedding
+	if err := r.db.WithContext(ctx).
+		Where("knowledge_base_id = ? AND id > ?", knowledgeBaseID, lastID).
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	page := &types.IndexExportPage{
+		Entries:    make([]*types.IndexInfo, 0, len(rows)),
+		Embeddings: make(map[string][]float32, len(rows)),
+	}
+	dimIDs := make(map[int][]uint)
+	sourceByID := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		page.Entries = append(page.Entries, &types.IndexInfo{
+			ID:              strconv.FormatUint(uint64(row.ID), 10),
+			Content:         row.Content,
+			SourceID:        row.SourceID,
+			SourceType:      types.SourceType(row.SourceType),
+			ChunkID:         row.ChunkID,
+			KnowledgeID:     row.KnowledgeID,
+			KnowledgeBaseID: row.KnowledgeBaseID,
+			KnowledgeType:   knowledgeType,
+			TagID:           row.TagID,
+			IsEnabled:       row.IsEnabled == nil || *row.IsEnabled,
+			Metadata:        metadataFields(row.Metadata),
+		})
+		if row.Dimension > 0 {
+			dimIDs[row.Dimension] = append(dimIDs[row.Dimension], row.ID)
+			sourceByID[row.ID] = row.SourceID
+		}
+	}
+	for dim, ids := range dimIDs {
+		var vecs []struct {
+			RowID     uint   `gorm:"column:rowid"`
+			Embedding []byte `gorm:"column:embedding"`
+		}
+		query := fmt.Sprintf("SELECT rowid, embedding FROM %s WHERE rowid IN ?", vecTableName(dim))
+		if err := r.db.WithContext(ctx).Raw(query, ids).Scan(&vecs).Error; err != nil {
+			return nil, fmt.Errorf("read vectors of dimension %d: %w", dim, err)
+		}
+		for _, vec := range vecs {
+			page.Embeddings[sourceByID[vec.RowID]] = deserializeFloat32(vec.Embedding)
+		}
+	}
+	if len(rows) == limit {
+		page.NextCursor = strconv.FormatUint(uint64(rows[len(rows)-1].ID), 10)
+	}
+	return page, nil
+}
+
+// CountIndices counts the entries of a knowledge base label.
+func (r *sqliteRepository) CountIndices(ctx context.Context, knowledgeBaseID string, _ int, _ string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&sqliteEmbedding{}).
+		Where("knowledge_base_id = ?", knowledgeBaseID).
+		Count(&count).Error
+	return count, err
+}
+
+// deserializeFloat32 is the inverse of sqlite_vec.SerializeFloat32.
+func deserializeFloat32(blob []byte) []float32 {
+	vec := make([]float32, len(blob)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:]))
+	}
+	return vec
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"gorm.io/gorm"
+)
+
+// metadataJSON encodes the metadata fields of an index entry; nil stays NULL
+// so json_extract on it yields NULL rather than a malformed-JSON error.
+func metadataJSON(fields map[string]any) *string {
+	if len(fields) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
+// metadataFields decodes the metadata column of an index entry.
+func metadataFields(data *string) map[string]any {
+	if data == nil || *data == "" {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(*data), &fields); err != nil || len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// metadataFilterWhere translates a normalised metadata filter into a
+// condition on the metadata column of tableAlias. Field names are [a-z0-9_]
+// (types.MetadataField) and are inlined into the JSON path.
+func metadataFilterWhere(f *types.MetadataFilter, tableAlias string) (whereClause, error) {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		parts := make([]string, 0, len(f.Filters))
+		var args []interface{}
+		for _, child := range f.Filters {
+			part, err := metadataFilterWhere(child, tableAlias)
+			if err != nil {
+				return whereClause{}, err
+			}
+			parts = append(parts, part.clause)
+			args = append(args, part.args...)
+		}
+		sep := " AND "
+		if f.Op == types.MetadataFilterOr {
+			sep = " OR "
+		}
+		return whereClause{clause: "(" + strings.Join(parts, sep) + ")", args: args}, nil
+	}
+
+	column := fmt.Sprintf("json_extract(%s.metadata, '$.%s')", tableAlias, f.Field)
+	switch f.Op {
+	case types.MetadataFilterEq:
+		return whereClause{clause: column + " = ?", args: []interface{}{sqliteJSONValue(f.Value)}}, nil
+	case types.MetadataFilterIn:
+		args := make([]interface{}, len(f.Values))
+		for i, value := range f.Values {
+			args[i] = sqliteJSONValue(value)
+		}
+		return whereClause{clause: column + " IN (" + placeholders(len(args)) + ")", args: args}, nil
+	case types.MetadataFilterGt:
+		return whereClause{clause: column + " > ?", args: []interface{}{f.Value}}, nil
+	case types.MetadataFilterGte:
+		return whereClause{clause: column + " >= ?", args: []interface{}{f.Value}}, nil
+	case types.MetadataFilterLt:
+		return whereClause{clause: column + " < ?", args: []interface{}{f.Value}}, nil
+	case types.MetadataFilterLte:
+		return whereClause{clause: column + " <= ?", args: []interface{}{f.Value}}, nil
+	}
+	return whereClause{}, fmt.Errorf("unsupported metadata filter operator %q", f.Op)
+}
+
+// sqliteJSONValue converts a filter value to what json_extract returns for
+// it: JSON booleans come back as the integers 1 and 0.
+func sqliteJSONValue(value any) any {
+	if b, ok := value.(bool); ok {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	return value
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata of every entry of each
+// knowledge.
+func (r *sqliteRepository) BatchUpdateKnowledgeMetadata(ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for knowledgeID, fields := range knowledgeMetadata {
+			if err := tx.Model(&sqliteEmbedding{}).
+				Where("knowledge_id = ?", knowledgeID).
+				Update("metadata", metadataJSON(fields)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -22,15 +22,16 @@ type sqliteEmbedding struct {
 	ID              uint      `gorm:"primarykey;autoIncrement"`
 	CreatedAt       time.Time `gorm:"column:created_at"`
 	UpdatedAt       time.Time `gorm:"column:updated_at"`
-	SourceID        string    `gorm:"column:source_id;not null;uniqueIndex:idx_sqlite_emb_source"`
-	SourceType      int       `gorm:"column:source_type;not null;uniqueIndex:idx_sqlite_emb_source"`
+	SourceID        string    `gorm:"column:source_id;not null;uniqueIndex:idx_sqlite_emb_kb_source,priority:2"`
+	SourceType      int       `gorm:"column:source_type;not null;uniqueIndex:idx_sqlite_emb_kb_source,priority:3"`
 	ChunkID         string    `gorm:"column:chunk_id;index"`
 	KnowledgeID     string    `gorm:"column:knowledge_id;index"`
-	KnowledgeBaseID string    `gorm:"column:knowledge_base_id;index"`
+	KnowledgeBaseID string    `gorm:"column:knowledge_base_id;index;uniqueIndex:idx_sqlite_emb_kb_source,priority:1"`
 	TagID           string    `gorm:"column:tag_id;index"`
 	Content         string    `gorm:"column:content;not null"`
 	Dimension       int       `gorm:"column:dimension;not null"`
 	IsEnabled       *bool     `gorm:"column:is_enabled;default:true;index"`
+	Metadata        *string   `gorm:"column:metadata;type:text"`
 }
 
 func (sqliteEmbedding) TableName() string { return "lite_embeddings" }
@@ -43,6 +44,9 @@ type sqliteRepository struct {
 func NewSQLiteRetrieveEngineRepository(db *gorm.DB) interfaces.RetrieveEngineRepository {
 	logger.GetLogger(context.Background()).Info("[SQLite] Initializing SQLite retriever engine repository with sqlite-vec")
 
+	// Source uniqueness used to be global; a re-embedding shadow index
+	// stores the same source IDs under another knowledge_base_id label.
+	db.Exec("DROP INDEX IF EXISTS idx_sqlite_emb_source")
 	if err := db.AutoMigrate(&sqliteEmbedding{}); err != nil {
 		logger.GetLogger(context.Background()).Errorf("[SQLite] Failed to auto-migrate lite_embeddings: %v", err)
 	}
@@ -212,6 +216,13 @@ func (r *sqliteRepository) DeleteByKnowledgeIDList(ctx context.Context, knowledg
 	return r.db.WithContext(ctx).Where("knowledge_id IN ?", knowledgeIDList).Delete(&sqliteEmbedding{}).Error
 }
 
+func (r *sqliteRepository) DeleteByKnowledgeBaseIDList(ctx context.Context, knowledgeBaseIDList []string, _ int, _ string) error {
+	var rows []sqliteEmbedding
+	r.db.WithContext(ctx).Where("knowledge_base_id IN ?", knowledgeBaseIDList).Find(&rows)
+	r.deleteRowsAndVecs(ctx, rows)
+	return r.db.WithContext(ctx).Where("knowledge_base_id IN ?", knowledgeBaseIDList).Delete(&sqliteEmbedding{}).Error
+}
+
 func (r *sqliteRepository) CopyIndices(ctx context.Context,
 	_ string,
 	sourceToTargetKBIDMap map[string]string,
@@ -234,6 +245,7 @@ func (r *sqliteRepository) CopyIndices(ctx context.Context,
 			Content:         src.Content,
 			Dimension:       src.Dimension,
 			IsEnabled:       src.IsEnabled,
+			Metadata:        src.Metadata,
 		}
 		if err := r.db.WithContext(ctx).Create(&newRow).Error; err != nil {
 			logger.GetLogger(ctx).Warnf("[SQLite] CopyIndices: failed to copy chunk %s: %v", sourceChunkID, err)
@@ -306,7 +318,11 @@ func (r *sqliteRepository) keywordsRetrieve(ctx context.Context, params types.Re
 
 	args := []interface{}{ftsQuery}
 
-	for _, wp := range buildFilterWhere(params, "e") {
+	filters, err := buildFilterWhere(params, "e")
+	if err != nil {
+		return nil, err
+	}
+	for _, wp := range filters {
 		sql += " AND " + wp.clause
 		args = append(args, wp.args...)
 	}
@@ -400,7 +416,11 @@ func (r *sqliteRepository) vectorRetrieve(ctx context.Context, params types.Retr
 	}
 
 	// 追加过滤条件
-	for _, wp := range buildFilterWhere(params, "filtered") {
+	filters, err := buildFilterWhere(params, "filtered")
+	if err != nil {
+		return nil, err
+	}
+	for _, wp := range filters {
 		vecSQL += " AND " + wp.clause
 		args = append(args, wp.args...)
 	}
@@ -476,6 +496,7 @@ func toSQLiteEmbedding(info *types.IndexInfo) *sqliteEmbedding {
 		Content:         common.CleanInvalidUTF8(info.Content),
 		Dimension:       0,
 		IsEnabled:       &enabled,
+		Metadata:        metadataJSON(info.Metadata),
 	}
 }
 
@@ -546,7 +567,7 @@ type whereClause struct {
 	args   []interface{}
 }
 
-func buildFilterWhere(params types.RetrieveParams, tableAlias string) []whereClause {
+func buildFilterWhere(params types.RetrieveParams, tableAlias string) ([]whereClause, error) {
 	var parts []whereClause
 	if len(params.KnowledgeBaseIDs) > 0 {
 		parts = append(parts, whereClause{
@@ -560,13 +581,26 @@ func buildFilterWhere(params types.RetrieveParams, tableAlias string) []whereCla
 			args:   toInterfaceSlice(params.KnowledgeIDs),
 		})
 	}
+	if len(params.ExcludeKnowledgeIDs) > 0 {
+		parts = append(parts, whereClause{
+			clause: tableAlias + ".knowledge_id NOT IN (" + placeholders(len(params.ExcludeKnowledgeIDs)) + ")",
+			args:   toInterfaceSlice(params.ExcludeKnowledgeIDs),
+		})
+	}
 	if len(params.TagIDs) > 0 {
 		parts = append(parts, whereClause{
 			clause: tableAlias + ".tag_id IN (" + placeholders(len(params.TagIDs)) + ")",
 			args:   toInterfaceSlice(params.TagIDs),
 		})
 	}
-	return parts
+	if params.MetadataFilter != nil {
+		part, err := metadataFilterWhere(params.MetadataFilter, tableAlias)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
 }
 
 func placeholders(n int) string {
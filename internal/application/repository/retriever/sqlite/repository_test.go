@@ -76,6 +76,13 @@ func TestVectorRetrieveFiltersBeforeTopK(t *testing.T) {
 				params.KnowledgeIDs = []string{"knowledge-target"}
 			},
 		},
+		{
+			name:    "excluded knowledge",
+			blocker: sqliteTestIndex("blocker", "kb-target", "knowledge-denied", "tag-target", true),
+			configure: func(params *types.RetrieveParams) {
+				params.ExcludeKnowledgeIDs = []string{"knowledge-denied"}
+			},
+		},
 		{
 			name:    "tag",
 			blocker: sqliteTestIndex("blocker", "kb-target", "knowledge-target", "tag-other", true),
@@ -186,3 +193,82 @@ func TestRetrieveReturnsKeywordQueryError(t *testing.T) {
 	assert.Nil(t, results)
 	assert.Contains(t, err.Error(), "FTS5 query failed")
 }
+
+func TestExportIndicesPagesEntriesWithStoredVectors(t *testing.T) {
+	repository := newSQLiteRetrieverTestRepository(t)
+	saveSQLiteTestVector(t, repository,
+		sqliteTestIndex("chunk-1", "kb-target", "knowledge-target", "tag-target", true), []float32{1, 0})
+	saveSQLiteTestVector(t, repository,
+		sqliteTestIndex("chunk-2", "kb-target", "knowledge-target", "", false), []float32{0.5, 0.25})
+	saveSQLiteTestVector(t, repository,
+		sqliteTestIndex("chunk-3", "kb-target", "knowledge-target", "", true), []float32{0, 1})
+	saveSQLiteTestVector(t, repository,
+		sqliteTestIndex("other", "kb-other", "knowledge-other", "", true), []float32{1, 1})
+
+	ctx := context.Background()
+	count, err := repository.CountIndices(ctx, "kb-target", 2, types.KnowledgeBaseTypeDocument)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	first, err := repository.ExportIndices(ctx, "kb-target", 2, types.KnowledgeBaseTypeDocument, "", 2)
+	require.NoError(t, err)
+	require.Len(t, first.Entries, 2)
+	require.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, "chunk-1", first.Entries[0].ChunkID)
+	assert.Equal(t, "tag-target", first.Entries[0].TagID)
+	assert.False(t, first.Entries[1].IsEnabled)
+	assert.Equal(t, []float32{0.5, 0.25}, first.Embeddings["source-chunk-2"])
+
+	second, err := repository.ExportIndices(ctx, "kb-target", 2, types.KnowledgeBaseTypeDocument, first.NextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, second.Entries, 1)
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, "chunk-3", second.Entries[0].ChunkID)
+	assert.Equal(t, []float32{0, 1}, second.Embeddings["source-chunk-3"])
+}
+
+func TestRetrieveAppliesMetadataFilter(t *testing.T) {
+	repository := newSQLiteRetrieverTestRepository(t)
+	documents := map[string]string{
+		"eu-2025": `{"region": "EU", "effective_date": "2025-06-01", "reviewed": true}`,
+		"eu-2024": `{"region": "EU", "effective_date": "2024-06-01", "reviewed": true}`,
+		"us-2025": `{"region": "US", "effective_date": "2025-06-01", "reviewed": false}`,
+		"none":    ``,
+	}
+	for chunkID, metadata := range documents {
+		info := sqliteTestIndex(chunkID, "kb-target", "knowledge-"+chunkID, "", true)
+		info.Metadata = types.IndexMetadata(types.JSON(metadata))
+		saveSQLiteTestVector(t, repository, info, []float32{1, 0})
+	}
+
+	filter, err := (&types.MetadataFilter{Op: types.MetadataFilterAnd, Filters: []*types.MetadataFilter{
+		{Op: types.MetadataFilterEq, Key: "region", Value: "EU"},
+		{Op: types.MetadataFilterGt, Key: "effective_date", Value: "2025-01-01"},
+		{Op: types.MetadataFilterIn, Key: "reviewed", Values: []any{true}},
+	}}).Normalize()
+	require.NoError(t, err)
+
+	results, err := repository.Retrieve(context.Background(), types.RetrieveParams{
+		Embedding:      []float32{1, 0},
+		TopK:           10,
+		RetrieverType:  types.VectorRetrieverType,
+		MetadataFilter: filter,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Results, 1)
+	assert.Equal(t, "eu-2025", results[0].Results[0].ChunkID)
+
+	// A metadata edit reaches the filter without re-indexing.
+	require.NoError(t, repository.BatchUpdateKnowledgeMetadata(context.Background(), map[string]map[string]any{
+		"knowledge-us-2025": types.IndexMetadata(types.JSON(`{"region": "EU", "effective_date": "2026-01-01", "reviewed": true}`)),
+	}))
+	results, err = repository.Retrieve(context.Background(), types.RetrieveParams{
+		Embedding:      []float32{1, 0},
+		TopK:           10,
+		RetrieverType:  types.VectorRetrieverType,
+		MetadataFilter: filter,
+	})
+	require.NoError(t, err)
+	assert.Len(t, results[0].Results, 2)
+}
@@ -143,6 +143,10 @@ func (r *repository) DeleteByKnowledgeIDList(ctx context.Context, knowledgeIDLis
 	return r.deleteByFilter(ctx, dimension, tcvectordb.In(fieldKnowledgeID, knowledgeIDList))
 }
 
+func (r *repository) DeleteByKnowledgeBaseIDList(ctx context.Context, knowledgeBaseIDList []string, dimension int, knowledgeType string) error {
+	return r.deleteByFilter(ctx, dimension, tcvectordb.In(fieldKnowledgeBaseID, knowledgeBaseIDList))
+}
+
 func (r *repository) CopyIndices(
 	ctx context.Context,
 	sourceKnowledgeBaseID string,
@@ -236,6 +240,11 @@ func (r *repository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[
 }
 
 func (r *repository) Retrieve(ctx context.Context, params types.RetrieveParams) ([]*types.RetrieveResult, error) {
+	// Documents are stored without their metadata, so a metadata filter is
+	// rejected rather than silently ignored.
+	if params.MetadataFilter != nil {
+		return nil, fmt.Errorf("tencent vectordb retriever does not support metadata filters")
+	}
 	switch params.RetrieverType {
 	case types.VectorRetrieverType:
 		return r.VectorRetrieve(ctx, params)
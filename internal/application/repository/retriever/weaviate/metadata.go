@@ -0,0 +1,289 @@
+package weaviate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
+	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// metadataPropertyPrefix prefixes the flat properties holding
+// IndexInfo.Metadata, one per field.
+const metadataPropertyPrefix = "metadata_"
+
+func metadataProperty(field string) string {
+	return metadataPropertyPrefix + field
+}
+
+// metadataDataType returns the property data type of a metadata field from
+// its kind prefix (see types.MetadataField).
+func metadataDataType(field string) string {
+	switch {
+	case strings.HasPrefix(field, string(types.MetadataKindNumber)+"_"),
+		strings.HasPrefix(field, string(types.MetadataKindDate)+"_"):
+		return "number"
+	case strings.HasPrefix(field, string(types.MetadataKindBool)+"_"):
+		return "boolean"
+	}
+	return "text"
+}
+
+// ensureMetadataProperties creates the properties of metadata fields missing
+// from the collection. They are declared explicitly rather than left to auto
+// schema, so strings use field tokenization and compare as whole values.
+func (w *weaviateRepository) ensureMetadataProperties(ctx context.Context,
+	collectionName string, fields []string,
+) error {
+	var missing []string
+	for _, field := range fields {
+		if _, ok := w.metadataProperties.Load(collectionName + "/" + field); !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	class, err := w.client.Schema().ClassGetter().WithClassName(collectionName).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get collection %s: %w", collectionName, err)
+	}
+	existing := make(map[string]bool, len(class.Properties))
+	for _, property := range class.Properties {
+		existing[property.Name] = true
+	}
+	enabled := true
+	for _, field := range missing {
+		if !existing[metadataProperty(field)] {
+			property := &models.Property{
+				Name:            metadataProperty(field),
+				DataType:        []string{metadataDataType(field)},
+				IndexFilterable: &enabled,
+			}
+			if property.DataType[0] == "text" {
+				property.Tokenization = models.PropertyTokenizationField
+			}
+			err := w.client.Schema().PropertyCreator().
+				WithClassName(collectionName).
+				WithProperty(property).
+				Do(ctx)
+			if err != nil && !strings.Contains(err.Error(), "already exists") {
+				return fmt.Errorf("failed to create metadata property %s: %w", property.Name, err)
+			}
+		}
+		w.metadataProperties.Store(collectionName+"/"+field, true)
+	}
+	return nil
+}
+
+// metadataFilterFields lists the fields a metadata filter compares.
+func metadataFilterFields(f *types.MetadataFilter) []string {
+	if f == nil {
+		return nil
+	}
+	if f.Op == types.MetadataFilterAnd || f.Op == types.MetadataFilterOr {
+		var fields []string
+		for _, child := range f.Filters {
+			fields = append(fields, metadataFilterFields(child)...)
+		}
+		return fields
+	}
+	return []string{f.Field}
+}
+
+// metadataWhere translates a normalised metadata filter into a where filter
+// on the metadata properties.
+func metadataWhere(f *types.MetadataFilter) (*filters.WhereBuilder, error) {
+	switch f.Op {
+	case types.MetadataFilterAnd, types.MetadataFilterOr:
+		operands := make([]*filters.WhereBuilder, 0, len(f.Filters))
+		for _, child := range f.Filters {
+			operand, err := metadataWhere(child)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+		operator := filters.And
+		if f.Op == types.MetadataFilterOr {
+			operator = filters.Or
+		}
+		return filters.Where().WithOperator(operator).WithOperands(operands), nil
+	case types.MetadataFilterEq:
+		return metadataCompare(metadataProperty(f.Field), filters.Equal, f.Value)
+	case types.MetadataFilterIn:
+		operands := make([]*filters.WhereBuilder, 0, len(f.Values))
+		for _, value := range f.Values {
+			operand, err := metadataCompare(metadataProperty(f.Field), filters.Equal, value)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+		return filters.Where().WithOperator(filters.Or).WithOperands(operands), nil
+	}
+	operator, ok := map[types.MetadataFilterOp]filters.WhereOperator{
+		types.MetadataFilterGt:  filters.GreaterThan,
+		types.MetadataFilterGte: filters.GreaterThanEqual,
+		types.MetadataFilterLt:  filters.LessThan,
+		types.MetadataFilterLte: filters.LessThanEqual,
+	}[f.Op]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metadata filter operator %q", f.Op)
+	}
+	return metadataCompare(metadataProperty(f.Field), operator, f.Value)
+}
+
+func metadataCompare(property string, operator filters.WhereOperator, value any) (*filters.WhereBuilder, error) {
+	where := filters.Where().WithPath([]string{property}).WithOperator(operator)
+	switch v := value.(type) {
+	case string:
+		return where.WithValueText(v), nil
+	case float64:
+		return where.WithValueNumber(v), nil
+	case bool:
+		return where.WithValueBoolean(v), nil
+	}
+	return nil, fmt.Errorf("unsupported metadata filter value %v", value)
+}
+
+// addMetadataProperties adds the metadata fields of an entry to its object
+// properties.
+func addMetadataProperties(properties map[string]any, metadata map[string]any) {
+	for field, value := range metadata {
+		properties[metadataProperty(field)] = value
+	}
+}
+
+func metadataFields(metadata map[string]any) []string {
+	fields := make([]string, 0, len(metadata))
+	for field := range metadata {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// BatchUpdateKnowledgeMetadata replaces the metadata properties of every
+// object of each knowledge. Objects are rewritten as a whole, so fields
+// removed from the metadata do not linger.
+func (w *weaviateRepository) BatchUpdateKnowledgeMetadata(ctx context.Context,
+	knowledgeMetadata map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadata) == 0 {
+		return nil
+	}
+	log.Infof("[Weaviate] Batch updating knowledge metadata, count: %d", len(knowledgeMetadata))
+
+	collections, err := w.ListCollections(ctx)
+	if err != nil {
+		log.Errorf("[Weaviate] Failed to list collections: %v", err)
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, collectionName := range collections {
+		if len(collectionName) <= len(w.collectionBaseName) ||
+			collectionName[:len(w.collectionBaseName)] != w.collectionBaseName {
+			continue
+		}
+		for knowledgeID, metadata := range knowledgeMetadata {
+			if err := w.updateKnowledgeMetadata(ctx, collectionName, knowledgeID, metadata); err != nil {
+				log.Errorf("[Weaviate] Failed to update metadata of knowledge %s in %s: %v",
+					knowledgeID, collectionName, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *weaviateRepository) updateKnowledgeMetadata(ctx context.Context,
+	collectionName, knowledgeID string, metadata map[string]any,
+) error {
+	if err := w.ensureMetadataProperties(ctx, collectionName, metadataFields(metadata)); err != nil {
+		return err
+	}
+	ids, err := w.knowledgeObjectIDs(ctx, collectionName, knowledgeID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		objects, err := w.client.Data().ObjectsGetter().
+			WithClassName(collectionName).
+			WithID(id).
+			WithVector().
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get object %s: %w", id, err)
+		}
+		if len(objects) == 0 {
+			continue
+		}
+		object := objects[0]
+		properties := make(map[string]any)
+		if current, ok := object.Properties.(map[string]any); ok {
+			for name, value := range current {
+				if !strings.HasPrefix(name, metadataPropertyPrefix) {
+					properties[name] = value
+				}
+			}
+		}
+		addMetadataProperties(properties, metadata)
+		updater := w.client.Data().Updater().
+			WithClassName(collectionName).
+			WithID(id).
+			WithProperties(properties)
+		if len(object.Vector) > 0 {
+			updater = updater.WithVector(object.Vector)
+		}
+		if len(object.Vectors) > 0 {
+			updater = updater.WithVectors(object.Vectors)
+		}
+		if err := updater.Do(ctx); err != nil {
+			return fmt.Errorf("failed to update object %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// knowledgeObjectIDs returns the IDs of the objects of a knowledge.
+func (w *weaviateRepository) knowledgeObjectIDs(ctx context.Context,
+	collectionName, knowledgeID string,
+) ([]string, error) {
+	const pageSize = 100
+	var ids []string
+	for offset := 0; ; offset += pageSize {
+		result, err := w.client.GraphQL().Get().
+			WithClassName(collectionName).
+			WithWhere(filters.Where().
+				WithPath([]string{fieldKnowledgeID}).
+				WithOperator(filters.Equal).
+				WithValueText(knowledgeID)).
+			WithLimit(pageSize).
+			WithOffset(offset).
+			WithFields(graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "id"}}}).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("graphql query failed: %s", result.Errors[0].Message)
+		}
+		data, _ := result.Data["Get"].(map[string]any)
+		items, _ := data[collectionName].([]any)
+		for _, item := range items {
+			obj, _ := item.(map[string]any)
+			additional, _ := obj["_additional"].(map[string]any)
+			if id, ok := additional["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(items) < pageSize {
+			return ids, nil
+		}
+	}
+}
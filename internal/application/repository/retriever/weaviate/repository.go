@@ -201,9 +201,13 @@ func (w *weaviateRepository) Save(ctx context.Context,
 		return err
 	}
 	collectionName := w.getCollectionName(dimension)
+	if err := w.ensureMetadataProperties(ctx, collectionName, metadataFields(embeddingDB.Metadata)); err != nil {
+		log.Errorf("[Weaviate] Failed to create metadata properties: %v", err)
+		return err
+	}
 	dataSchema := createPayload(embeddingDB)
 
-	id := embedding.ChunkID
+	id := objectID(embedding)
 	// Create point in Weaviate
 	_, err := w.client.Data().Creator().
 		WithClassName(collectionName).
@@ -262,11 +266,15 @@ func (w *weaviateRepository) BatchSave(ctx context.Context,
 		collectionName := w.getCollectionName(dimension)
 		for _, embedding := range embeddings {
 			embeddingDB := toWeaviateVectorEmbedding(embedding, additionalParams)
+			if err := w.ensureMetadataProperties(ctx, collectionName, metadataFields(embeddingDB.Metadata)); err != nil {
+				log.Errorf("[Weaviate] Failed to create metadata properties: %v", err)
+				return err
+			}
 			dataSchema := createPayload(embeddingDB)
 
 			obj := &models.Object{
 				Class:      collectionName,
-				ID:         strfmt.UUID(embeddingDB.ChunkID),
+				ID:         strfmt.UUID(objectID(embedding)),
 				Properties: dataSchema,
 				Vector:     embeddingDB.Embedding,
 			}
@@ -345,6 +353,36 @@ func (w *weaviateRepository) DeleteByKnowledgeIDList(ctx context.Context,
 	return nil
 }
 
+// DeleteByKnowledgeBaseIDList removes points from the collection based on knowledge base IDs
+func (w *weaviateRepository) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeBaseIDList) == 0 {
+		log.Warn("[Weaviate] Empty knowledge base ID list provided for deletion, skipping")
+		return nil
+	}
+
+	collectionName := w.getCollectionName(dimension)
+	log.Infof("[Weaviate] Deleting indices by knowledge base IDs from %s, count: %d", collectionName, len(knowledgeBaseIDList))
+
+	_, err := w.client.Batch().ObjectsBatchDeleter().
+		WithClassName(collectionName).
+		WithWhere(filters.Where().
+			WithPath([]string{fieldKnowledgeBaseID}).
+			WithOperator(filters.ContainsAny).
+			WithValueText(knowledgeBaseIDList...)).
+		WithOutput("minimal").
+		Do(ctx)
+	if err != nil {
+		log.Errorf("[Weaviate] Failed to delete by knowledge base IDs: %v", err)
+		return fmt.Errorf("failed to delete by knowledge base IDs: %w", err)
+	}
+
+	log.Infof("[Weaviate] Successfully deleted documents by knowledge base IDs")
+	return nil
+}
+
 // DeleteBySourceIDList removes points from the collection based on source IDs
 func (w *weaviateRepository) DeleteBySourceIDList(ctx context.Context,
 	sourceIDList []string, dimension int, knowledgeType string,
@@ -474,13 +512,21 @@ func (w *weaviateRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTag
 
 }
 
-func (w *weaviateRepository) getBaseFilter(params types.RetrieveParams) *filters.WhereBuilder {
+func (w *weaviateRepository) getBaseFilter(params types.RetrieveParams) (*filters.WhereBuilder, error) {
 	var operands []*filters.WhereBuilder
 	operands = append(operands, filters.Where().
 		WithPath([]string{fieldIsEnabled}).
 		WithOperator(filters.Equal).
 		WithValueBoolean(true))
 
+	if params.MetadataFilter != nil {
+		operand, err := metadataWhere(params.MetadataFilter)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
 	if len(params.KnowledgeBaseIDs) > 0 {
 		operands = append(operands, filters.Where().
 			WithPath([]string{fieldKnowledgeBaseID}).
@@ -515,7 +561,7 @@ func (w *weaviateRepository) getBaseFilter(params types.RetrieveParams) *filters
 
 	return filters.Where().
 		WithOperator(filters.And).
-		WithOperands(operands)
+		WithOperands(operands), nil
 }
 
 // Retrieve dispatches the retrieval operation to the appropriate method based on retriever type
@@ -560,7 +606,17 @@ func (w *weaviateRepository) VectorRetrieve(ctx context.Context,
 		return buildRetrieveResult(nil, types.VectorRetrieverType), nil
 	}
 
-	where := w.getBaseFilter(params)
+	// Filtering on a property the collection does not have is an error, so
+	// the properties of filtered fields are created up front.
+	if err := w.ensureMetadataProperties(ctx, collectionName, metadataFilterFields(params.MetadataFilter)); err != nil {
+		log.Errorf("[Weaviate] Failed to create metadata properties: %v", err)
+		return nil, err
+	}
+	where, err := w.getBaseFilter(params)
+	if err != nil {
+		log.Errorf("[Weaviate] Failed to build base filter: %v", err)
+		return nil, fmt.Errorf("failed to build filter: %w", err)
+	}
 	limit := params.TopK
 	scoreThreshold := float32(params.Threshold)
 	fields := getEmbeddingFields()
@@ -626,7 +682,15 @@ func (w *weaviateRepository) KeywordsRetrieve(ctx context.Context,
 			continue
 		}
 
-		filter := w.getBaseFilter(params)
+		if err := w.ensureMetadataProperties(ctx, collectionName, metadataFilterFields(params.MetadataFilter)); err != nil {
+			log.Errorf("[Weaviate] Failed to create metadata properties: %v", err)
+			return nil, err
+		}
+		filter, err := w.getBaseFilter(params)
+		if err != nil {
+			log.Errorf("[Weaviate] Failed to build base filter: %v", err)
+			return nil, fmt.Errorf("failed to build filter: %w", err)
+		}
 
 		//bm25 search
 		bm25 := w.client.GraphQL().Bm25ArgBuilder().
@@ -695,6 +759,18 @@ func (w *weaviateRepository) CopyIndices(ctx context.Context,
 	var lastID string
 	totalCopied := 0
 	fields := getVectorFields()
+	class, err := w.client.Schema().ClassGetter().WithClassName(collectionName).Do(ctx)
+	if err != nil {
+		log.Errorf("[Weaviate] Failed to get collection %s: %v", collectionName, err)
+		return err
+	}
+	var metadataNames []string
+	for _, property := range class.Properties {
+		if strings.HasPrefix(property.Name, metadataPropertyPrefix) {
+			metadataNames = append(metadataNames, property.Name)
+			fields = append(fields, graphql.Field{Name: property.Name})
+		}
+	}
 
 	for {
 		result, err := w.client.GraphQL().Get().
@@ -779,20 +855,26 @@ func (w *weaviateRepository) CopyIndices(ctx context.Context,
 			}
 
 			isEnabled := true
+			properties := map[string]interface{}{
+				fieldContent:         data[fieldContent],
+				fieldSourceID:        targetSourceID,
+				fieldSourceType:      data[fieldSourceType],
+				fieldChunkID:         targetChunkID,
+				fieldKnowledgeID:     targetKnowledgeID,
+				fieldKnowledgeBaseID: targetKnowledgeBaseID,
+				fieldTagID:           data[fieldTagID],
+				fieldIsEnabled:       isEnabled,
+			}
+			for _, name := range metadataNames {
+				if value, ok := data[name]; ok && value != nil {
+					properties[name] = value
+				}
+			}
 			newObj := &models.Object{
-				Class: collectionName,
-				ID:    strfmt.UUID(uuid.New().String()),
-				Properties: map[string]interface{}{
-					fieldContent:         data[fieldContent],
-					fieldSourceID:        targetSourceID,
-					fieldSourceType:      data[fieldSourceType],
-					fieldChunkID:         targetChunkID,
-					fieldKnowledgeID:     targetKnowledgeID,
-					fieldKnowledgeBaseID: targetKnowledgeBaseID,
-					fieldTagID:           data[fieldTagID],
-					fieldIsEnabled:       isEnabled,
-				},
-				Vector: vector,
+				Class:      collectionName,
+				ID:         strfmt.UUID(uuid.New().String()),
+				Properties: properties,
+				Vector:     vector,
 			}
 			targetObjects = append(targetObjects, newObj)
 			currentBatchCount++
@@ -828,6 +910,16 @@ func (w *weaviateRepository) ListCollections(ctx context.Context) ([]string, err
 	return collectionNames, nil
 }
 
+// objectID is the Weaviate object ID for embedding: the chunk ID, unless the
+// caller supplied IndexInfo.ID (a UUID) to index the same chunk twice in
+// one collection, e.g. a re-embedding shadow index.
+func objectID(embedding *types.IndexInfo) string {
+	if embedding.ID != "" {
+		return embedding.ID
+	}
+	return embedding.ChunkID
+}
+
 func createPayload(embedding *WeaviateVectorEmbedding) map[string]interface{} {
 	payload := map[string]any{
 		fieldContent:         embedding.Content,
@@ -839,6 +931,7 @@ func createPayload(embedding *WeaviateVectorEmbedding) map[string]interface{} {
 		fieldTagID:           embedding.TagID,
 		fieldIsEnabled:       embedding.IsEnabled,
 	}
+	addMetadataProperties(payload, embedding.Metadata)
 	return payload
 }
 
@@ -1008,6 +1101,7 @@ func toWeaviateVectorEmbedding(embedding *types.IndexInfo, additionalParams map[
 		KnowledgeBaseID: embedding.KnowledgeBaseID,
 		TagID:           embedding.TagID,
 		IsEnabled:       embedding.IsEnabled,
+		Metadata:        embedding.Metadata,
 	}
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), fieldEmbedding) {
 		if embeddingMap, ok := additionalParams[fieldEmbedding].(map[string][]float32); ok {
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+// ErrUserGroupNotFound is returned when a user group does not exist
+var ErrUserGroupNotFound = errors.New("user group not found")
+
+type userGroupRepository struct {
+	db *gorm.DB
+}
+
+// NewUserGroupRepository creates a repository for user groups and their memberships
+func NewUserGroupRepository(db *gorm.DB) interfaces.UserGroupRepository {
+	return &userGroupRepository{db: db}
+}
+
+func (r *userGroupRepository) Create(ctx context.Context, group *types.UserGroup) error {
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+func (r *userGroupRepository) Update(ctx context.Context, group *types.UserGroup) error {
+	return r.db.WithContext(ctx).Save(group).Error
+}
+
+func (r *userGroupRepository) Delete(ctx context.Context, tenantID uint64, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&types.UserGroup{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrUserGroupNotFound
+		}
+		return tx.Where("group_id = ?", id).Delete(&types.UserGroupMember{}).Error
+	})
+}
+
+func (r *userGroupRepository) Get(ctx context.Context, tenantID uint64, id string) (*types.UserGroup, error) {
+	var group types.UserGroup
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserGroupNotFound
+	}
+	return &group, err
+}
+
+func (r *userGroupRepository) List(ctx context.Context, tenantID uint64) ([]*types.UserGroup, error) {
+	var groups []*types.UserGroup
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at ASC").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	var counts []struct {
+		GroupID string
+		Count   int
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&types.UserGroupMember{}).
+		Select("group_id, COUNT(*) AS count").
+		Where("tenant_id = ?", tenantID).
+		Group("group_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	byGroup := make(map[string]int, len(counts))
+	for _, c := range counts {
+		byGroup[c.GroupID] = c.Count
+	}
+	for _, group := range groups {
+		group.MemberCount = byGroup[group.ID]
+	}
+	return groups, nil
+}
+
+func (r *userGroupRepository) ListByIDs(ctx context.Context, tenantID uint64, ids []string) ([]*types.UserGroup, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var groups []*types.UserGroup
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id IN ?", tenantID, ids).
+		Find(&groups).Error
+	return groups, err
+}
+
+func (r *userGroupRepository) ListMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&types.UserGroupMember{}).
+		Where("group_id = ?", groupID).
+		Order("created_at ASC").
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *userGroupRepository) ReplaceMembers(ctx context.Context, tenantID uint64, groupID string, userIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&types.UserGroupMember{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		now := time.Now()
+		members := make([]*types.UserGroupMember, 0, len(userIDs))
+		for _, userID := range userIDs {
+			members = append(members, &types.UserGroupMember{
+				GroupID: groupID, UserID: userID, TenantID: tenantID, CreatedAt: now,
+			})
+		}
+		return tx.Create(&members).Error
+	})
+}
+
+func (r *userGroupRepository) ListGroupIDsByUser(ctx context.Context, userID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&types.UserGroupMember{}).
+		Where("user_id = ?", userID).
+		Pluck("group_id", &ids).Error
+	return ids, err
+}
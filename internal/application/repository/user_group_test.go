@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newUserGroupTestRepo(t *testing.T) *userGroupRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.UserGroup{}, &types.UserGroupMember{}))
+	return NewUserGroupRepository(db).(*userGroupRepository)
+}
+
+func TestUserGroupMembersAndCountsAreTenantScoped(t *testing.T) {
+	repo := newUserGroupTestRepo(t)
+	ctx := context.Background()
+
+	finance := &types.UserGroup{ID: uuid.NewString(), TenantID: 1, Name: "finance"}
+	legal := &types.UserGroup{ID: uuid.NewString(), TenantID: 1, Name: "legal"}
+	foreign := &types.UserGroup{ID: uuid.NewString(), TenantID: 2, Name: "finance"}
+	for _, group := range []*types.UserGroup{finance, legal, foreign} {
+		require.NoError(t, repo.Create(ctx, group))
+	}
+	require.NoError(t, repo.ReplaceMembers(ctx, 1, finance.ID, []string{"u1", "u2"}))
+	require.NoError(t, repo.ReplaceMembers(ctx, 1, finance.ID, []string{"u2", "u3"}))
+	require.NoError(t, repo.ReplaceMembers(ctx, 2, foreign.ID, []string{"u2"}))
+
+	members, err := repo.ListMemberIDs(ctx, finance.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, members)
+
+	groups, err := repo.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, 2, groups[0].MemberCount)
+	require.Equal(t, 0, groups[1].MemberCount)
+
+	ids, err := repo.ListGroupIDsByUser(ctx, "u2")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{finance.ID, foreign.ID}, ids)
+
+	byIDs, err := repo.ListByIDs(ctx, 1, []string{finance.ID, foreign.ID})
+	require.NoError(t, err)
+	require.Len(t, byIDs, 1)
+
+	require.ErrorIs(t, repo.Delete(ctx, 2, finance.ID), ErrUserGroupNotFound)
+	require.NoError(t, repo.Delete(ctx, 1, finance.ID))
+	_, err = repo.Get(ctx, 1, finance.ID)
+	require.ErrorIs(t, err, ErrUserGroupNotFound)
+	ids, err = repo.ListGroupIDsByUser(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, []string{foreign.ID}, ids)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWebhookSubscriptionNotFound is returned when a webhook subscription does not exist
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrWebhookDeliveryNotFound is returned when a webhook delivery does not exist
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a repository for webhook subscriptions and their deliveries
+func NewWebhookRepository(db *gorm.DB) interfaces.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *types.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *types.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(sub).Error
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, tenantID uint64, id string) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		Delete(&types.WebhookSubscription{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetSubscription(
+	ctx context.Context, tenantID uint64, id string,
+) (*types.WebhookSubscription, error) {
+	var sub types.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+	return &sub, err
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context, tenantID uint64) ([]*types.WebhookSubscription, error) {
+	var subs []*types.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at ASC").
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) ListEnabledSubscriptions(
+	ctx context.Context, tenantID uint64,
+) ([]*types.WebhookSubscription, error) {
+	var subs []*types.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND enabled = ?", tenantID, true).
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *types.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *types.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
+
+func (r *webhookRepository) DeleteDelivery(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&types.WebhookDelivery{}).Error
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, tenantID uint64, id string) (*types.WebhookDelivery, error) {
+	var delivery types.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return &delivery, err
+}
+
+func (r *webhookRepository) ListDeliveries(
+	ctx context.Context, tenantID uint64, subscriptionID string, status string, page *types.Pagination,
+) ([]*types.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&types.WebhookDelivery{}).
+		Where("tenant_id = ? AND subscription_id = ?", tenantID, subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []*types.WebhookDelivery
+	err := query.
+		Order("created_at DESC").
+		Offset(page.Offset()).
+		Limit(page.Limit()).
+		Find(&deliveries).Error
+	return deliveries, total, err
+}
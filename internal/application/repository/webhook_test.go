@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newWebhookTestRepo(t *testing.T) *webhookRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.WebhookSubscription{}, &types.WebhookDelivery{}))
+	return NewWebhookRepository(db).(*webhookRepository)
+}
+
+func TestWebhookSubscriptionRoundTripIsTenantScoped(t *testing.T) {
+	repo := newWebhookTestRepo(t)
+	ctx := context.Background()
+
+	sub := &types.WebhookSubscription{
+		ID: uuid.NewString(), TenantID: 1, Name: "indexer", URL: "https://hooks.example.com/kb",
+		Secret: "s3cret", EventTypes: types.StringArray{string(types.WebhookEventKnowledgeParsed)},
+		Enabled: false,
+	}
+	require.NoError(t, repo.CreateSubscription(ctx, sub))
+
+	got, err := repo.GetSubscription(ctx, 1, sub.ID)
+	require.NoError(t, err)
+	require.False(t, got.Enabled, "an explicit enabled=false must survive the insert")
+	require.Equal(t, "s3cret", got.Secret)
+	require.Equal(t, sub.EventTypes, got.EventTypes)
+
+	enabled, err := repo.ListEnabledSubscriptions(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, enabled)
+
+	_, err = repo.GetSubscription(ctx, 2, sub.ID)
+	require.ErrorIs(t, err, ErrWebhookSubscriptionNotFound)
+	require.ErrorIs(t, repo.DeleteSubscription(ctx, 2, sub.ID), ErrWebhookSubscriptionNotFound)
+	require.NoError(t, repo.DeleteSubscription(ctx, 1, sub.ID))
+	_, err = repo.GetSubscription(ctx, 1, sub.ID)
+	require.ErrorIs(t, err, ErrWebhookSubscriptionNotFound)
+}
+
+func TestWebhookListDeliveriesFiltersByStatusNewestFirst(t *testing.T) {
+	repo := newWebhookTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	newDelivery := func(subscriptionID, status string, created time.Time) string {
+		delivery := &types.WebhookDelivery{
+			ID: uuid.NewString(), TenantID: 1, SubscriptionID: subscriptionID, EventID: uuid.NewString(),
+			EventType: types.WebhookEventKnowledgeCreated, Payload: types.JSON(`{}`),
+			Status: status, CreatedAt: created,
+		}
+		require.NoError(t, repo.CreateDelivery(ctx, delivery))
+		return delivery.ID
+	}
+	newDelivery("sub", types.WebhookDeliverySuccess, now.Add(-2*time.Minute))
+	failed := newDelivery("sub", types.WebhookDeliveryFailed, now.Add(-time.Minute))
+	latest := newDelivery("sub", types.WebhookDeliverySuccess, now)
+	newDelivery("other", types.WebhookDeliveryFailed, now)
+
+	deliveries, total, err := repo.ListDeliveries(ctx, 1, "sub", "", &types.Pagination{})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.Equal(t, latest, deliveries[0].ID)
+
+	deliveries, total, err = repo.ListDeliveries(ctx, 1, "sub", types.WebhookDeliveryFailed, &types.Pagination{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, failed, deliveries[0].ID)
+
+	_, err = repo.GetDelivery(ctx, 2, failed)
+	require.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+)
+
+// AskAgent sends the query to the agent in the session and collects the
+// answer from the QA event stream, storing both as session messages of the
+// given channel. It is the non-streaming counterpart of the chat handlers for
+// callers without an HTTP response to stream into.
+func AskAgent(
+	ctx context.Context,
+	sessionService interfaces.SessionService,
+	messageService interfaces.MessageService,
+	session *types.Session,
+	agent *types.CustomAgent,
+	query string,
+	channel string,
+) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	requestID := uuid.New().String()
+	userMsg, err := messageService.CreateMessage(ctx, &types.Message{
+		SessionID:   session.ID,
+		Role:        "user",
+		Content:     query,
+		RequestID:   requestID,
+		CreatedAt:   time.Now(),
+		IsCompleted: true,
+		Channel:     channel,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create user message: %w", err)
+	}
+	assistantMsg, err := messageService.CreateMessage(ctx, &types.Message{
+		SessionID: session.ID,
+		Role:      "assistant",
+		RequestID: requestID,
+		CreatedAt: time.Now(),
+		Channel:   channel,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create assistant message: %w", err)
+	}
+
+	var mu sync.Mutex
+	var answer strings.Builder
+	var qaErr error
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	closeDone := func() { doneOnce.Do(func() { close(done) }) }
+
+	eventBus := event.NewEventBus()
+	eventBus.On(event.EventAgentFinalAnswer, func(ctx context.Context, evt event.Event) error {
+		data, ok := evt.Data.(event.AgentFinalAnswerData)
+		if !ok {
+			return nil
+		}
+		mu.Lock()
+		answer.WriteString(data.Content)
+		mu.Unlock()
+		if data.Done {
+			closeDone()
+		}
+		return nil
+	})
+	eventBus.On(event.EventError, func(ctx context.Context, evt event.Event) error {
+		data, ok := evt.Data.(event.ErrorData)
+		if !ok {
+			return nil
+		}
+		mu.Lock()
+		qaErr = fmt.Errorf("QA pipeline error: %s", data.Error)
+		mu.Unlock()
+		closeDone()
+		return nil
+	})
+	eventBus.On(event.EventAgentComplete, func(ctx context.Context, evt event.Event) error {
+		data, ok := evt.Data.(event.AgentCompleteData)
+		if !ok {
+			return nil
+		}
+		mu.Lock()
+		if answer.Len() == 0 && strings.TrimSpace(data.FinalAnswer) != "" {
+			answer.WriteString(data.FinalAnswer)
+		}
+		mu.Unlock()
+		closeDone()
+		return nil
+	})
+
+	go func() {
+		req := &types.QARequest{
+			Session:            session,
+			Query:              query,
+			AssistantMessageID: assistantMsg.ID,
+			UserMessageID:      userMsg.ID,
+			CustomAgent:        agent,
+			WebSearchEnabled:   agent.Config.WebSearchEnabled,
+		}
+		var err error
+		if agent.IsAgentMode() {
+			err = sessionService.AgentQA(ctx, req, eventBus)
+		} else {
+			err = sessionService.KnowledgeQA(ctx, req, eventBus)
+		}
+		if err != nil {
+			mu.Lock()
+			qaErr = fmt.Errorf("QA execution error: %w", err)
+			mu.Unlock()
+			closeDone()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		mu.Lock()
+		qaErr = fmt.Errorf("QA cancelled: %w", ctx.Err())
+		mu.Unlock()
+	}
+
+	mu.Lock()
+	content, runErr := answer.String(), qaErr
+	mu.Unlock()
+
+	assistantMsg.Content = content
+	assistantMsg.IsCompleted = true
+	if err := messageService.UpdateMessage(context.WithoutCancel(ctx), assistantMsg); err != nil {
+		logger.Warnf(ctx, "[AskAgent] failed to update assistant message: %v", err)
+	}
+	if strings.TrimSpace(content) == "" {
+		if runErr != nil {
+			return "", runErr
+		}
+		return "", errors.New("agent returned an empty answer")
+	}
+	return content, nil
+}
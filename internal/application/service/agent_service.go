@@ -99,6 +99,7 @@ type agentService struct {
 	knowledgeService      interfaces.KnowledgeService
 	fileService           interfaces.FileService
 	chunkService          interfaces.ChunkService
+	graphRepo             interfaces.RetrieveGraphRepository
 	duckdb                *sql.DB
 	webSearchStateService interfaces.WebSearchStateService
 	wikiPageService       interfaces.WikiPageService
@@ -121,6 +122,7 @@ func NewAgentService(
 	knowledgeService interfaces.KnowledgeService,
 	fileService interfaces.FileService,
 	chunkService interfaces.ChunkService,
+	graphRepo interfaces.RetrieveGraphRepository,
 	mcpServiceService interfaces.MCPServiceService,
 	mcpManager *mcp.MCPManager,
 	eventBus *event.EventBus,
@@ -146,6 +148,7 @@ func NewAgentService(
 		knowledgeService:      knowledgeService,
 		fileService:           fileService,
 		chunkService:          chunkService,
+		graphRepo:             graphRepo,
 		mcpServiceService:     mcpServiceService,
 		mcpManager:            mcpManager,
 		eventBus:              eventBus,
@@ -205,6 +208,9 @@ func (s *agentService) CreateAgentEngine(
 	if config.UseCustomSystemPrompt || config.SystemPrompt != "" {
 		systemPromptTemplate = config.ResolveSystemPrompt(config.WebSearchEnabled)
 	}
+	if prompt := s.renderMCPPrompt(ctx, config.MCPPrompt); prompt != "" {
+		systemPromptTemplate = prompt
+	}
 
 	// 5. Create engine
 	engine := agent.NewAgentEngine(
@@ -327,6 +333,27 @@ func (s *agentService) registerMCPTools(
 	}
 }
 
+// renderMCPPrompt renders the MCP prompt template selected as the agent's
+// system prompt. It returns "" when none is selected or it cannot be rendered,
+// so the configured system prompt stays in effect.
+func (s *agentService) renderMCPPrompt(ctx context.Context, ref *types.MCPPromptRef) string {
+	if ref == nil || ref.ServiceID == "" || ref.Name == "" || s.mcpServiceService == nil {
+		return ""
+	}
+	tenantID, ok := types.TenantIDFromContext(ctx)
+	if !ok || tenantID == 0 {
+		return ""
+	}
+	prompt, err := s.mcpServiceService.RenderMCPServicePrompt(ctx, tenantID, ref)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to render MCP prompt %s of service %s, using configured system prompt: %v",
+			ref.Name, ref.ServiceID, err)
+		return ""
+	}
+	logger.Infof(ctx, "Using MCP prompt %s of service %s as system prompt", ref.Name, ref.ServiceID)
+	return prompt
+}
+
 // resolveKBAndDocInfos loads knowledge base metadata and selected document info for prompt.
 func (s *agentService) resolveKBAndDocInfos(
 	ctx context.Context,
@@ -644,7 +671,7 @@ func (s *agentService) registerTools(
 				rerankModel,
 				chatModel,
 				s.cfg,
-			)
+			).WithMetadataFilter(config.MetadataFilter)
 		case tools.ToolGrepChunks:
 			toolToRegister = tools.NewGrepChunksTool(s.db, config.SearchTargets)
 			logger.Infof(ctx, "Registered grep_chunks tool with searchTargets: %d targets", len(config.SearchTargets))
@@ -652,7 +679,8 @@ func (s *agentService) registerTools(
 			toolToRegister = tools.NewListKnowledgeChunksTool(s.knowledgeService, s.chunkService, config.SearchTargets)
 		case tools.ToolQueryKnowledgeGraph:
 			toolToRegister = tools.NewQueryKnowledgeGraphTool(s.knowledgeBaseService, config.SearchTargets).
-				WithKnowledgeScope(s.knowledgeService)
+				WithKnowledgeScope(s.knowledgeService).
+				WithGraphTraversal(s.graphRepo, s.chunkService.GetRepository())
 		case tools.ToolGetDocumentInfo:
 			toolToRegister = tools.NewGetDocumentInfoTool(s.knowledgeService, s.chunkService, config.SearchTargets)
 		case tools.ToolSearchConversations:
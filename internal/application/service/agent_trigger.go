@@ -0,0 +1,427 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/im"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const agentTriggerWebhookTimeout = 10 * time.Second
+
+type agentTriggerService struct {
+	repo             interfaces.AgentTriggerRepository
+	scheduler        *AgentTriggerScheduler
+	agentService     interfaces.CustomAgentService
+	sessionService   interfaces.SessionService
+	messageService   interfaces.MessageService
+	tenantService    interfaces.TenantService
+	kbService        interfaces.KnowledgeBaseService
+	knowledgeService interfaces.KnowledgeService
+	imService        *im.Service
+}
+
+// NewAgentTriggerService creates the agent trigger service
+func NewAgentTriggerService(
+	repo interfaces.AgentTriggerRepository,
+	scheduler *AgentTriggerScheduler,
+	agentService interfaces.CustomAgentService,
+	sessionService interfaces.SessionService,
+	messageService interfaces.MessageService,
+	tenantService interfaces.TenantService,
+	kbService interfaces.KnowledgeBaseService,
+	knowledgeService interfaces.KnowledgeService,
+	imService *im.Service,
+) interfaces.AgentTriggerService {
+	return &agentTriggerService{
+		repo:             repo,
+		scheduler:        scheduler,
+		agentService:     agentService,
+		sessionService:   sessionService,
+		messageService:   messageService,
+		tenantService:    tenantService,
+		kbService:        kbService,
+		knowledgeService: knowledgeService,
+		imService:        imService,
+	}
+}
+
+func (s *agentTriggerService) CreateTrigger(
+	ctx context.Context, agentID string, trigger *types.AgentTrigger,
+) (*types.AgentTrigger, error) {
+	if _, err := s.agentService.GetAgentByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if err := s.validateTrigger(ctx, trigger); err != nil {
+		return nil, err
+	}
+	trigger.ID = uuid.New().String()
+	trigger.TenantID = types.MustTenantIDFromContext(ctx)
+	trigger.AgentID = agentID
+	trigger.CreatedBy, _ = types.UserIDFromContext(ctx)
+	trigger.LastRunAt = nil
+	trigger.LastRunStatus = ""
+	if err := s.repo.Create(ctx, trigger); err != nil {
+		return nil, err
+	}
+	if err := s.scheduler.AddOrUpdate(trigger); err != nil {
+		logger.Warnf(ctx, "[AgentTrigger] failed to schedule trigger %s: %v", trigger.ID, err)
+	}
+	return presentAgentTrigger(trigger), nil
+}
+
+func (s *agentTriggerService) ListTriggers(ctx context.Context, agentID string) ([]*types.AgentTrigger, error) {
+	triggers, err := s.repo.ListByAgent(ctx, types.MustTenantIDFromContext(ctx), agentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, trigger := range triggers {
+		presentAgentTrigger(trigger)
+	}
+	return triggers, nil
+}
+
+func (s *agentTriggerService) GetTrigger(ctx context.Context, agentID string, id string) (*types.AgentTrigger, error) {
+	trigger, err := s.getTrigger(ctx, agentID, id)
+	if err != nil {
+		return nil, err
+	}
+	return presentAgentTrigger(trigger), nil
+}
+
+func (s *agentTriggerService) UpdateTrigger(
+	ctx context.Context, agentID string, id string, update *types.AgentTrigger, webhookSecret *string,
+) (*types.AgentTrigger, error) {
+	trigger, err := s.getTrigger(ctx, agentID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret := trigger.Delivery.WebhookSecret
+	if webhookSecret != nil {
+		secret = strings.TrimSpace(*webhookSecret)
+	}
+	trigger.Name = update.Name
+	trigger.Enabled = update.Enabled
+	trigger.TriggerType = update.TriggerType
+	trigger.Schedule = update.Schedule
+	trigger.EventType = update.EventType
+	trigger.KnowledgeBaseIDs = update.KnowledgeBaseIDs
+	trigger.CooldownSeconds = update.CooldownSeconds
+	trigger.Prompt = update.Prompt
+	trigger.Delivery = update.Delivery
+	trigger.Delivery.WebhookSecret = secret
+	if err := s.validateTrigger(ctx, trigger); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, trigger); err != nil {
+		return nil, err
+	}
+	if err := s.scheduler.AddOrUpdate(trigger); err != nil {
+		logger.Warnf(ctx, "[AgentTrigger] failed to reschedule trigger %s: %v", trigger.ID, err)
+	}
+	return presentAgentTrigger(trigger), nil
+}
+
+func (s *agentTriggerService) DeleteTrigger(ctx context.Context, agentID string, id string) error {
+	err := s.repo.Delete(ctx, types.MustTenantIDFromContext(ctx), agentID, id)
+	if errors.Is(err, repository.ErrAgentTriggerNotFound) {
+		return werrors.NewNotFoundError("agent trigger not found")
+	}
+	if err != nil {
+		return err
+	}
+	s.scheduler.Remove(id)
+	return nil
+}
+
+// RunTrigger also runs disabled triggers, so a trigger can be tried out
+// before it is switched on
+func (s *agentTriggerService) RunTrigger(ctx context.Context, agentID string, id string) (*types.AgentTriggerRun, error) {
+	trigger, err := s.getTrigger(ctx, agentID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduler.enqueue(ctx, trigger, types.AgentTriggerSourceManual, nil, "")
+}
+
+func (s *agentTriggerService) ListRuns(
+	ctx context.Context, agentID string, id string, page *types.Pagination,
+) (*types.PageResult, error) {
+	trigger, err := s.getTrigger(ctx, agentID, id)
+	if err != nil {
+		return nil, err
+	}
+	runs, total, err := s.repo.ListRuns(ctx, trigger.TenantID, trigger.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, runs), nil
+}
+
+func (s *agentTriggerService) getTrigger(ctx context.Context, agentID string, id string) (*types.AgentTrigger, error) {
+	trigger, err := s.repo.Get(ctx, types.MustTenantIDFromContext(ctx), agentID, id)
+	if errors.Is(err, repository.ErrAgentTriggerNotFound) {
+		return nil, werrors.NewNotFoundError("agent trigger not found")
+	}
+	return trigger, err
+}
+
+// validateTrigger checks a trigger and clears the fields its type does not
+// use, so a trigger switched from schedule to event keeps no stale cron entry
+func (s *agentTriggerService) validateTrigger(ctx context.Context, trigger *types.AgentTrigger) error {
+	trigger.Name = strings.TrimSpace(trigger.Name)
+	if trigger.Delivery.Type == "" {
+		trigger.Delivery.Type = types.AgentTriggerDeliveryNone
+	}
+	if err := trigger.Validate(); err != nil {
+		return werrors.NewValidationError(err.Error())
+	}
+
+	switch trigger.TriggerType {
+	case types.AgentTriggerTypeSchedule:
+		trigger.Schedule = strings.TrimSpace(trigger.Schedule)
+		if err := ValidateAgentTriggerSchedule(trigger.Schedule); err != nil {
+			return werrors.NewValidationError(err.Error())
+		}
+		trigger.EventType = ""
+		trigger.KnowledgeBaseIDs = nil
+		trigger.CooldownSeconds = 0
+	case types.AgentTriggerTypeEvent:
+		trigger.Schedule = ""
+	}
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	d := &trigger.Delivery
+	switch d.Type {
+	case types.AgentTriggerDeliveryIM:
+		if _, err := s.imService.GetChannelByIDAndTenant(d.IMChannelID, tenantID); err != nil {
+			return werrors.NewValidationError("im_channel_id does not name an IM channel of this workspace")
+		}
+		*d = types.AgentTriggerDelivery{Type: d.Type, IMChannelID: d.IMChannelID, IMChatID: d.IMChatID, IMUserID: d.IMUserID}
+	case types.AgentTriggerDeliveryWebhook:
+		d.WebhookURL = strings.TrimSpace(d.WebhookURL)
+		if err := ValidateEmbedWebhookURL(d.WebhookURL); err != nil {
+			return werrors.NewValidationError(err.Error())
+		}
+		*d = types.AgentTriggerDelivery{Type: d.Type, WebhookURL: d.WebhookURL, WebhookSecret: d.WebhookSecret}
+	case types.AgentTriggerDeliveryKnowledge:
+		kb, err := s.kbService.GetKnowledgeBaseByID(ctx, d.KnowledgeBaseID)
+		if err != nil || kb.TenantID != tenantID {
+			return werrors.NewValidationError("knowledge_base_id does not name a knowledge base of this workspace")
+		}
+		*d = types.AgentTriggerDelivery{Type: d.Type, KnowledgeBaseID: d.KnowledgeBaseID}
+	default:
+		*d = types.AgentTriggerDelivery{Type: d.Type}
+	}
+	return nil
+}
+
+// presentAgentTrigger flags a stored webhook secret without returning it
+func presentAgentTrigger(trigger *types.AgentTrigger) *types.AgentTrigger {
+	trigger.Delivery.HasWebhookSecret = trigger.Delivery.WebhookSecret != ""
+	return trigger
+}
+
+// ProcessRun executes one trigger run: it asks the agent in a fresh session
+// and delivers the answer. Agent and delivery failures are recorded on the run
+// and not retried, since a retry would ask the agent (and deliver) again;
+// only failures to load the run return an error.
+func (s *agentTriggerService) ProcessRun(ctx context.Context, task *asynq.Task) error {
+	var payload types.AgentTriggerRunPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "[AgentTrigger] failed to unmarshal run payload: %v", err)
+		return err
+	}
+	run, err := s.repo.GetRun(ctx, payload.TenantID, payload.RunID)
+	if errors.Is(err, repository.ErrAgentTriggerRunNotFound) {
+		logger.Warnf(ctx, "[AgentTrigger] run %s not found, skipping", payload.RunID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status != types.AgentTriggerRunPending {
+		logger.Infof(ctx, "[AgentTrigger] run %s is %s, skipping", run.ID, run.Status)
+		return nil
+	}
+
+	now := time.Now()
+	run.Status = types.AgentTriggerRunRunning
+	run.StartedAt = &now
+	if err := s.repo.UpdateRun(ctx, run); err != nil {
+		return err
+	}
+
+	runErr := s.executeRun(ctx, payload, run)
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = types.AgentTriggerRunSuccess
+	if runErr != nil {
+		run.Status = types.AgentTriggerRunFailed
+		run.ErrorMessage = runErr.Error()
+		logger.Warnf(ctx, "[AgentTrigger] run %s of trigger %s failed: %v", run.ID, run.TriggerID, runErr)
+	}
+	// The run is over whatever happens next, so it is saved even when the
+	// task context was cancelled by the timeout.
+	saveCtx := context.WithoutCancel(ctx)
+	if err := s.repo.UpdateRun(saveCtx, run); err != nil {
+		logger.Warnf(ctx, "[AgentTrigger] failed to save run %s: %v", run.ID, err)
+	}
+	if err := s.repo.RecordRun(saveCtx, run.TriggerID, run.CreatedAt, run.Status); err != nil {
+		logger.Warnf(ctx, "[AgentTrigger] failed to record run of trigger %s: %v", run.TriggerID, err)
+	}
+	return nil
+}
+
+// executeRun asks the agent and delivers its answer, filling in the run
+func (s *agentTriggerService) executeRun(
+	ctx context.Context, payload types.AgentTriggerRunPayload, run *types.AgentTriggerRun,
+) error {
+	trigger, err := s.repo.GetByID(ctx, payload.TenantID, payload.TriggerID)
+	if err != nil {
+		return fmt.Errorf("load trigger: %w", err)
+	}
+	tenant, err := s.tenantService.GetTenantByID(ctx, payload.TenantID)
+	if err != nil {
+		return fmt.Errorf("load tenant: %w", err)
+	}
+	ctx = withAgentTriggerIdentity(ctx, tenant)
+
+	agent, err := s.agentService.GetAgentByID(ctx, trigger.AgentID)
+	if err != nil {
+		return fmt.Errorf("load agent: %w", err)
+	}
+
+	session, err := s.sessionService.CreateSession(ctx, &types.Session{
+		TenantID:    tenant.ID,
+		Title:       fmt.Sprintf("%s %s", trigger.Name, time.Now().Format("2006-01-02 15:04")),
+		Description: fmt.Sprintf("Auto-created by agent trigger %s", trigger.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+	run.SessionID = session.ID
+
+	answer, err := AskAgent(ctx, s.sessionService, s.messageService, session, agent, run.Query,
+		types.ChannelAgentTrigger)
+	if err != nil {
+		return err
+	}
+	run.Answer = answer
+
+	if err := s.deliver(ctx, trigger, run); err != nil {
+		run.DeliveryError = err.Error()
+		return fmt.Errorf("deliver answer: %w", err)
+	}
+	return nil
+}
+
+// withAgentTriggerIdentity runs the agent as the workspace's synthetic system
+// user with viewer rights, like IM channels do. The user is also set as the
+// principal so document access lists apply although the run is background
+// work. Nobody is present to answer an MCP OAuth prompt, so MCP authorization
+// is non-interactive.
+func withAgentTriggerIdentity(ctx context.Context, tenant *types.Tenant) context.Context {
+	userID := fmt.Sprintf("system-%d", tenant.ID)
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, tenant.ID)
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenant)
+	ctx = context.WithValue(ctx, types.UserIDContextKey, userID)
+	ctx = context.WithValue(ctx, types.TenantRoleContextKey, types.TenantRoleViewer)
+	ctx = types.WithPrincipal(ctx, types.Principal{Type: types.PrincipalWebUser, ID: userID})
+	return types.WithMCPOAuthNonInteractive(ctx)
+}
+
+// deliver sends the answer of a run where the trigger says
+func (s *agentTriggerService) deliver(ctx context.Context, trigger *types.AgentTrigger, run *types.AgentTriggerRun) error {
+	d := trigger.Delivery
+	switch d.Type {
+	case types.AgentTriggerDeliveryIM:
+		return s.imService.SendChannelMessage(ctx, trigger.TenantID,
+			d.IMChannelID, d.IMChatID, d.IMUserID, run.Answer)
+	case types.AgentTriggerDeliveryWebhook:
+		return postAgentTriggerWebhook(ctx, trigger, run)
+	case types.AgentTriggerDeliveryKnowledge:
+		knowledge, err := s.knowledgeService.CreateKnowledgeFromManual(ctx, d.KnowledgeBaseID,
+			&types.ManualKnowledgePayload{
+				Title:   fmt.Sprintf("%s %s", trigger.Name, run.StartedAt.Format("2006-01-02 15:04")),
+				Content: run.Answer,
+				Status:  types.ManualKnowledgeStatusPublish,
+			}, types.ChannelAgentTrigger)
+		if err != nil {
+			return err
+		}
+		run.KnowledgeID = knowledge.ID
+	}
+	return nil
+}
+
+// postAgentTriggerWebhook POSTs the run as JSON. With a secret the body is
+// signed like embed webhooks: X-WeKnora-Signature: sha256=<hex HMAC>.
+func postAgentTriggerWebhook(ctx context.Context, trigger *types.AgentTrigger, run *types.AgentTriggerRun) error {
+	url := trigger.Delivery.WebhookURL
+	if err := ValidateEmbedWebhookURL(url); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(map[string]any{
+		"type":         "agent_trigger.run",
+		"trigger_id":   trigger.ID,
+		"trigger_name": trigger.Name,
+		"agent_id":     trigger.AgentID,
+		"run_id":       run.ID,
+		"source":       run.Source,
+		"event":        run.Event,
+		"query":        run.Query,
+		"answer":       run.Answer,
+		"session_id":   run.SessionID,
+		"timestamp":    time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, agentTriggerWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WeKnora-Agent-Trigger/1.0")
+	if secret := trigger.Delivery.WebhookSecret; secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(raw)
+		req.Header.Set("X-WeKnora-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	client := secutils.NewSSRFSafeHTTPClient(secutils.SSRFSafeHTTPClientConfig{
+		Timeout:      agentTriggerWebhookTimeout,
+		MaxRedirects: 5,
+	})
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
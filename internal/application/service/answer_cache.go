@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/metrics"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// answerCacheCandidateLimit bounds how many of the most recent entries of a
+// scope a lookup compares the question against
+const answerCacheCandidateLimit = 200
+
+type answerCacheService struct {
+	repo interfaces.AnswerCacheRepository
+}
+
+// NewAnswerCacheService creates the semantic answer cache service
+func NewAnswerCacheService(repo interfaces.AnswerCacheRepository) interfaces.AnswerCacheService {
+	return &answerCacheService{repo: repo}
+}
+
+// Lookup only considers entries created after the last change to the scope's
+// knowledge bases, so an edited, added or deleted document invalidates every
+// answer cached against it without explicit bookkeeping.
+func (s *answerCacheService) Lookup(
+	ctx context.Context, scope *types.AnswerCacheScope,
+) (*types.AnswerCacheEntry, error) {
+	changedAt, err := s.repo.LastKnowledgeChange(ctx, scope.KnowledgeBaseIDs)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.ListCandidates(ctx, scope.TenantID, scope.AgentID, scope.ScopeHash,
+		changedAt, answerCacheCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *types.AnswerCacheEntry
+	bestScore := scope.Threshold
+	for _, candidate := range candidates {
+		// Vectors of another length come from a changed embedding model and
+		// score 0, so they never match.
+		score := cosineSimilarity(scope.QueryEmbedding, secutils.DecodeVector(candidate.QueryEmbedding))
+		if score >= bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	metrics.IncAnswerCacheLookup(best != nil)
+	if best == nil {
+		return nil, nil
+	}
+
+	logger.Infof(ctx, "[AnswerCache] hit for agent %s: entry %s, similarity %.4f", scope.AgentID, best.ID, bestScore)
+	if err := s.repo.RecordHit(ctx, best.ID); err != nil {
+		logger.Warnf(ctx, "[AnswerCache] failed to record hit on entry %s: %v", best.ID, err)
+	}
+	return best, nil
+}
+
+// Store dates the entry at scope.StartedAt, before retrieval ran, so a
+// knowledge change landing while the answer was generated leaves it stale.
+// Expired and stale entries of the scope are pruned on the way.
+func (s *answerCacheService) Store(
+	ctx context.Context, scope *types.AnswerCacheScope, answer string, references types.References,
+) error {
+	if changedAt, err := s.repo.LastKnowledgeChange(ctx, scope.KnowledgeBaseIDs); err == nil {
+		if _, err := s.repo.DeleteStale(ctx, scope.TenantID, scope.AgentID, scope.ScopeHash, changedAt); err != nil {
+			logger.Warnf(ctx, "[AnswerCache] failed to prune stale entries of agent %s: %v", scope.AgentID, err)
+		}
+	}
+	entry := &types.AnswerCacheEntry{
+		ID:               uuid.New().String(),
+		TenantID:         scope.TenantID,
+		AgentID:          scope.AgentID,
+		ScopeHash:        scope.ScopeHash,
+		KnowledgeBaseIDs: types.StringArray(scope.KnowledgeBaseIDs),
+		Query:            scope.Query,
+		QueryEmbedding:   secutils.EncodeVector(scope.QueryEmbedding),
+		Answer:           answer,
+		References:       references,
+		CreatedAt:        scope.StartedAt,
+		ExpiresAt:        scope.StartedAt.Add(scope.TTL),
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+func (s *answerCacheService) ListEntries(
+	ctx context.Context, agentID string, page *types.Pagination,
+) (*types.PageResult, error) {
+	entries, total, err := s.repo.List(ctx, types.MustTenantIDFromContext(ctx), agentID, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, entries), nil
+}
+
+func (s *answerCacheService) DeleteEntry(ctx context.Context, agentID string, entryID string) error {
+	err := s.repo.Delete(ctx, types.MustTenantIDFromContext(ctx), agentID, entryID)
+	if errors.Is(err, repository.ErrAnswerCacheEntryNotFound) {
+		return werrors.NewNotFoundError("answer cache entry not found")
+	}
+	return err
+}
+
+func (s *answerCacheService) ClearAgent(ctx context.Context, agentID string) (int64, error) {
+	return s.repo.DeleteByAgent(ctx, types.MustTenantIDFromContext(ctx), agentID)
+}
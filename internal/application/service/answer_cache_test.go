@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// fakeAnswerCacheRepo keeps entries in memory and applies the same staleness
+// rule as the database query.
+type fakeAnswerCacheRepo struct {
+	interfaces.AnswerCacheRepository
+	entries   []*types.AnswerCacheEntry
+	changedAt time.Time
+	hits      []string
+	stored    chan *types.AnswerCacheEntry
+}
+
+func (r *fakeAnswerCacheRepo) LastKnowledgeChange(context.Context, []string) (time.Time, error) {
+	return r.changedAt, nil
+}
+
+func (r *fakeAnswerCacheRepo) ListCandidates(
+	_ context.Context, _ uint64, _ string, _ string, since time.Time, _ int,
+) ([]*types.AnswerCacheEntry, error) {
+	var out []*types.AnswerCacheEntry
+	for _, entry := range r.entries {
+		if entry.CreatedAt.After(since) {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAnswerCacheRepo) RecordHit(_ context.Context, id string) error {
+	r.hits = append(r.hits, id)
+	return nil
+}
+
+func (r *fakeAnswerCacheRepo) DeleteStale(context.Context, uint64, string, string, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeAnswerCacheRepo) Create(_ context.Context, entry *types.AnswerCacheEntry) error {
+	r.stored <- entry
+	return nil
+}
+
+func cachedAnswer(id string, vector []float32, created time.Time) *types.AnswerCacheEntry {
+	return &types.AnswerCacheEntry{
+		ID: id, QueryEmbedding: secutils.EncodeVector(vector), Answer: "answer " + id, CreatedAt: created,
+	}
+}
+
+func TestAnswerCacheLookupReturnsClosestFreshEntry(t *testing.T) {
+	now := time.Now()
+	repo := &fakeAnswerCacheRepo{
+		changedAt: now.Add(-time.Hour),
+		entries: []*types.AnswerCacheEntry{
+			cachedAnswer("stale", []float32{1, 0}, now.Add(-2*time.Hour)),
+			cachedAnswer("close", []float32{0.99, 0.1}, now),
+			cachedAnswer("far", []float32{0.5, 0.5}, now),
+			cachedAnswer("other-model", []float32{1, 0, 0}, now),
+		},
+	}
+	svc := NewAnswerCacheService(repo)
+
+	entry, err := svc.Lookup(context.Background(), &types.AnswerCacheScope{
+		QueryEmbedding: []float32{1, 0}, Threshold: 0.95,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, "close", entry.ID)
+	require.Equal(t, []string{"close"}, repo.hits)
+
+	entry, err = svc.Lookup(context.Background(), &types.AnswerCacheScope{
+		QueryEmbedding: []float32{0, 1}, Threshold: 0.95,
+	})
+	require.NoError(t, err)
+	require.Nil(t, entry)
+}
+
+func TestRecordAnswerForCacheStoresCompletedAnswers(t *testing.T) {
+	repo := &fakeAnswerCacheRepo{stored: make(chan *types.AnswerCacheEntry, 1)}
+	svc := &sessionService{answerCacheService: NewAnswerCacheService(repo)}
+	scope := &types.AnswerCacheScope{
+		AgentID: "agent", Query: "how do I reset my password", QueryEmbedding: []float32{1, 0},
+		TTL: time.Hour, StartedAt: time.Now(),
+	}
+	emit := func(bus *event.EventBus, content string, done bool) {
+		_ = bus.Emit(context.Background(), event.Event{
+			Type: event.EventAgentFinalAnswer,
+			Data: event.AgentFinalAnswerData{Content: content, Done: done},
+		})
+	}
+
+	bus := event.NewEventBus()
+	chatManage := &types.ChatManage{}
+	chatManage.MergeResult = []*types.SearchResult{{ID: "chunk-1"}}
+	svc.recordAnswerForCache(bus, chatManage, scope)
+	emit(bus, "Open settings, ", false)
+	emit(bus, "then security.", true)
+
+	select {
+	case entry := <-repo.stored:
+		require.Equal(t, "Open settings, then security.", entry.Answer)
+		require.Equal(t, scope.StartedAt.Add(time.Hour), entry.ExpiresAt)
+		require.Equal(t, []float32{1, 0}, secutils.DecodeVector(entry.QueryEmbedding))
+	case <-time.After(5 * time.Second):
+		t.Fatal("completed answer was not stored")
+	}
+
+	// A stream that reported an error is not cached.
+	failedBus := event.NewEventBus()
+	svc.recordAnswerForCache(failedBus, chatManage, scope)
+	_ = failedBus.Emit(context.Background(), event.Event{Type: event.EventError})
+	emit(failedBus, "partial", true)
+	select {
+	case <-repo.stored:
+		t.Fatal("answer of a failed stream was stored")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -2,6 +2,9 @@ package chatpipeline
 
 import (
 	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/metrics"
 
 	"github.com/Tencent/WeKnora/internal/types"
 )
@@ -72,7 +75,10 @@ func (e *EventManager) Trigger(ctx context.Context,
 	eventType types.EventType, chatManage *types.ChatManage,
 ) *PluginError {
 	if handler, ok := e.handlers[eventType]; ok {
-		return handler(ctx, eventType, chatManage)
+		start := time.Now()
+		err := handler(ctx, eventType, chatManage)
+		metrics.ObservePipelineStage(string(eventType), err != nil, time.Since(start))
+		return err
 	}
 	return nil
 }
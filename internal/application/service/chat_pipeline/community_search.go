@@ -0,0 +1,122 @@
+package chatpipeline
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// communitySearchLimit is how many community summaries a global query reads.
+const communitySearchLimit = 10
+
+// PluginCommunitySearch retrieves graph community summaries as the context of
+// a global-mode query
+type PluginCommunitySearch struct {
+	communityService interfaces.GraphCommunityService
+}
+
+// NewPluginCommunitySearch creates a new community search plugin
+func NewPluginCommunitySearch(
+	eventManager *EventManager,
+	communityService interfaces.GraphCommunityService,
+) *PluginCommunitySearch {
+	res := &PluginCommunitySearch{communityService: communityService}
+	eventManager.Register(res)
+	return res
+}
+
+// ActivationEvents returns the list of event types this plugin responds to
+func (p *PluginCommunitySearch) ActivationEvents() []types.EventType {
+	return []types.EventType{types.COMMUNITY_SEARCH}
+}
+
+// OnEvent ranks the community summaries of the searched knowledge bases and
+// hands the best ones to the answer stage as merged results
+func (p *PluginCommunitySearch) OnEvent(ctx context.Context,
+	eventType types.EventType, chatManage *types.ChatManage, next func() *PluginError,
+) *PluginError {
+	if !chatManage.NeedsRetrieval() {
+		return next()
+	}
+	kbIDs := communityKnowledgeBaseIDs(chatManage)
+	if len(kbIDs) == 0 {
+		logger.Warnf(ctx, "Community search has no knowledge base to search")
+		return ErrSearchNothing
+	}
+	query := chatManage.RewriteQuery
+	if query == "" {
+		query = chatManage.Query
+	}
+
+	communities, err := p.communityService.SearchCommunities(
+		ctx, kbIDs, query, communitySearchLimit, communityExcludedKnowledgeIDs(chatManage),
+	)
+	if err != nil {
+		logger.Errorf(ctx, "Community search failed: %v", err)
+		return ErrSearch.WithError(err)
+	}
+	if len(communities) == 0 {
+		logger.Infof(ctx, "No graph communities built yet for knowledge bases %v", kbIDs)
+		return ErrSearchNothing
+	}
+
+	results := make([]*types.SearchResult, 0, len(communities))
+	for i, c := range communities {
+		results = append(results, &types.SearchResult{
+			ID:              "community_" + c.ID,
+			Content:         c.Title + "\n\n" + c.Summary,
+			KnowledgeBaseID: c.KnowledgeBaseID,
+			KnowledgeTitle:  c.Title,
+			// Keep the service's order; the score is only used for display.
+			Score:     1 - float64(i)/float64(len(communities)),
+			MatchType: types.MatchTypeCommunity,
+		})
+	}
+	chatManage.MergeResult = results
+	logger.Infof(ctx, "Community search returned %d summaries", len(results))
+	return next()
+}
+
+// communityKnowledgeBaseIDs lists the knowledge bases a request searches,
+// preferring the resolved search targets (which carry shared knowledge bases
+// the user was granted) over the raw request list.
+func communityKnowledgeBaseIDs(chatManage *types.ChatManage) []string {
+	seen := make(map[string]struct{})
+	var ids []string
+	add := func(id string) {
+		if id == "" {
+			return
+		}
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	for _, t := range chatManage.SearchTargets {
+		if t != nil {
+			add(t.KnowledgeBaseID)
+		}
+	}
+	if len(ids) == 0 {
+		for _, id := range chatManage.KnowledgeBaseIDs {
+			add(id)
+		}
+	}
+	return ids
+}
+
+// communityExcludedKnowledgeIDs collects the documents access lists hide from
+// the caller in the searched knowledge bases. Community summaries span the
+// whole graph, so one built over a hidden document must not be used.
+func communityExcludedKnowledgeIDs(chatManage *types.ChatManage) []string {
+	var ids []string
+	for _, t := range chatManage.SearchTargets {
+		if t != nil {
+			ids = append(ids, t.ExcludeKnowledgeIDs...)
+		}
+	}
+	return ids
+}
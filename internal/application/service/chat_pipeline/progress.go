@@ -14,6 +14,8 @@ import (
 const (
 	retrievalProgressTool       = "knowledge_search"
 	queryUnderstandProgressTool = "query_understand"
+	queryHyDEProgressTool       = "query_hyde"
+	queryDecomposeProgressTool  = "query_decompose"
 
 	retrievalSourceKnowledge = "knowledge"
 	retrievalSourceWeb       = "web"
@@ -42,7 +44,8 @@ func IsConsolidatedRetrievalStage(stage types.EventType, chatManage *types.ChatM
 		return false
 	}
 	switch stage {
-	case types.CHUNK_SEARCH_PARALLEL, types.CHUNK_RERANK, types.CHUNK_MERGE, types.FILTER_TOP_K:
+	case types.CHUNK_SEARCH_PARALLEL, types.CHUNK_RERANK, types.CHUNK_MERGE, types.FILTER_TOP_K,
+		types.COMMUNITY_SEARCH:
 		return chatManage.NeedsRetrieval()
 	case types.WEB_FETCH:
 		return chatManage.WebSearchEnabled
@@ -91,6 +94,9 @@ func BeginRetrievalProgress(ctx context.Context, chatManage *types.ChatManage) *
 	} else if chatManage.Query != "" {
 		args["query"] = chatManage.Query
 	}
+	if len(chatManage.SubQueries) > 0 {
+		args["sub_queries"] = chatManage.SubQueries
+	}
 
 	_ = chatManage.EventBus.Emit(ctx, types.Event{
 		Type:      types.EventType(event.EventAgentToolCall),
@@ -170,6 +176,106 @@ func EndQueryUnderstandProgress(
 	})
 }
 
+// ShouldEmitQueryTransformProgress reports whether a HyDE or decomposition
+// stage will actually call the model for this request.
+func ShouldEmitQueryTransformProgress(stage types.EventType, chatManage *types.ChatManage) bool {
+	if chatManage == nil || !chatManage.NeedsRetrieval() || !hasKBRetrievalTargets(chatManage) {
+		return false
+	}
+	switch stage {
+	case types.QUERY_HYDE:
+		return chatManage.EnableHyDE
+	case types.QUERY_DECOMPOSE:
+		return chatManage.EnableQueryDecomposition
+	default:
+		return false
+	}
+}
+
+// BeginQueryTransformProgress emits a pending query_hyde / query_decompose tool_call.
+func BeginQueryTransformProgress(ctx context.Context, stage types.EventType, chatManage *types.ChatManage) *StageProgress {
+	if chatManage == nil || chatManage.EventBus == nil || !ShouldEmitQueryTransformProgress(stage, chatManage) {
+		return nil
+	}
+
+	toolName := queryHyDEProgressTool
+	if stage == types.QUERY_DECOMPOSE {
+		toolName = queryDecomposeProgressTool
+	}
+	toolCallID := uuid.New().String()
+	args := map[string]any{}
+	if chatManage.RewriteQuery != "" {
+		args["query"] = chatManage.RewriteQuery
+	}
+
+	_ = chatManage.EventBus.Emit(ctx, types.Event{
+		Type:      types.EventType(event.EventAgentToolCall),
+		SessionID: chatManage.SessionID,
+		Data: event.AgentToolCallData{
+			ToolCallID: toolCallID,
+			ToolName:   toolName,
+			Arguments:  args,
+		},
+	})
+
+	return &StageProgress{toolCallID: toolCallID, toolName: toolName}
+}
+
+// EndQueryTransformProgress emits the matching tool_result with the drafted
+// hypothetical document or the sub-queries.
+func EndQueryTransformProgress(
+	ctx context.Context,
+	chatManage *types.ChatManage,
+	progress *StageProgress,
+	start time.Time,
+	stageErr *PluginError,
+) {
+	if progress == nil || chatManage == nil || chatManage.EventBus == nil {
+		return
+	}
+
+	success := stageErr == nil
+	output := ""
+	data := map[string]interface{}{}
+	switch progress.toolName {
+	case queryHyDEProgressTool:
+		data["hypothetical_document"] = chatManage.HypotheticalDocument
+		if success {
+			output = "已生成假设答案，用于向量检索"
+			if chatManage.HypotheticalDocument == "" {
+				output = "未生成假设答案，使用原问题检索"
+			}
+		}
+	case queryDecomposeProgressTool:
+		data["sub_queries"] = chatManage.SubQueries
+		if success {
+			output = fmt.Sprintf("拆分为 %d 个子问题", len(chatManage.SubQueries))
+			if len(chatManage.SubQueries) == 0 {
+				output = "问题无需拆分"
+			}
+		}
+	}
+
+	var errMsg string
+	if !success && stageErr.Err != nil {
+		errMsg = stageErr.Err.Error()
+	}
+
+	_ = chatManage.EventBus.Emit(ctx, types.Event{
+		Type:      types.EventType(event.EventAgentToolResult),
+		SessionID: chatManage.SessionID,
+		Data: event.AgentToolResultData{
+			ToolCallID: progress.toolCallID,
+			ToolName:   progress.toolName,
+			Output:     output,
+			Error:      errMsg,
+			Success:    success,
+			Duration:   time.Since(start).Milliseconds(),
+			Data:       data,
+		},
+	})
+}
+
 // EndRetrievalProgress emits the matching tool_result for the consolidated retrieval window.
 func EndRetrievalProgress(
 	ctx context.Context,
@@ -199,3 +199,31 @@ func TestRetrievalProgressWebOnlySearchSource(t *testing.T) {
 	assert.Equal(t, 2, resultData.Data["web_count"])
 	assert.Equal(t, retrievalSourceWeb, resultData.Data["search_source"])
 }
+
+func TestQueryTransformProgressReportsSubQueries(t *testing.T) {
+	bus := &recordingEventBus{}
+	cm := &types.ChatManage{
+		PipelineRequest: types.PipelineRequest{
+			SessionID:                "sess-1",
+			KnowledgeBaseIDs:         []string{"kb-1"},
+			EnableQueryDecomposition: true,
+		},
+		PipelineContext: types.PipelineContext{EventBus: bus},
+	}
+	assert.Nil(t, BeginQueryTransformProgress(context.Background(), types.QUERY_HYDE, cm), "HyDE is off")
+
+	progress := BeginQueryTransformProgress(context.Background(), types.QUERY_DECOMPOSE, cm)
+	require.NotNil(t, progress)
+	cm.SubQueries = []string{"a", "b"}
+	EndQueryTransformProgress(context.Background(), cm, progress, time.Now(), nil)
+
+	require.Len(t, bus.events, 2)
+	callData, ok := bus.events[0].Data.(event.AgentToolCallData)
+	require.True(t, ok)
+	assert.Equal(t, "query_decompose", callData.ToolName)
+	resultData, ok := bus.events[1].Data.(event.AgentToolResultData)
+	require.True(t, ok)
+	assert.True(t, resultData.Success)
+	assert.Equal(t, "拆分为 2 个子问题", resultData.Output)
+	assert.Equal(t, []string{"a", "b"}, resultData.Data["sub_queries"])
+}
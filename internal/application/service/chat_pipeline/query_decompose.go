@@ -0,0 +1,132 @@
+package chatpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// maxSubQueries caps the number of sub-queries searched per question; each
+// one costs an embedding call and a retrieval round-trip.
+const maxSubQueries = 4
+
+const decomposePrompt = `You split a user question into independent search queries for a document retrieval system.
+- If the question asks about several things (comparisons, multiple entities, multi-part or multi-step questions), return one self-contained query per part. Resolve pronouns so each query makes sense on its own.
+- If the question asks about a single thing, return an empty list.
+- Return at most 4 queries, in the same language as the question.
+Respond with JSON only: {"sub_queries": ["...", "..."]}`
+
+// PluginQueryDecompose splits compound questions into sub-queries. The
+// CHUNK_SEARCH_PARALLEL stage searches each of them next to the full query
+// and merges the hits before rerank, which still scores against the full
+// question.
+type PluginQueryDecompose struct {
+	modelService interfaces.ModelService
+}
+
+// NewPluginQueryDecompose creates a new decomposition plugin and registers it
+// with the event manager
+func NewPluginQueryDecompose(eventManager *EventManager, modelService interfaces.ModelService) *PluginQueryDecompose {
+	res := &PluginQueryDecompose{modelService: modelService}
+	eventManager.Register(res)
+	return res
+}
+
+// ActivationEvents returns the event types this plugin handles
+func (p *PluginQueryDecompose) ActivationEvents() []types.EventType {
+	return []types.EventType{types.QUERY_DECOMPOSE}
+}
+
+// OnEvent asks the model for sub-queries. Failures are logged and the
+// question is searched as a whole.
+func (p *PluginQueryDecompose) OnEvent(ctx context.Context,
+	eventType types.EventType, chatManage *types.ChatManage, next func() *PluginError,
+) *PluginError {
+	chatManage.SubQueries = nil
+	if !chatManage.EnableQueryDecomposition || !chatManage.NeedsRetrieval() || !hasKBRetrievalTargets(chatManage) {
+		pipelineInfo(ctx, "QueryDecompose", "skip", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"enabled":    chatManage.EnableQueryDecomposition,
+			"intent":     chatManage.Intent,
+		})
+		return next()
+	}
+
+	model, err := queryStageModel(ctx, p.modelService, chatManage)
+	if err != nil {
+		pipelineWarn(ctx, "QueryDecompose", "get_model", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"error":      err.Error(),
+		})
+		return next()
+	}
+
+	thinking := false
+	modelCtx := types.WithLLMCallMetadata(ctx, "query_decompose", "")
+	response, err := model.Chat(modelCtx, []chat.Message{
+		{Role: "system", Content: decomposePrompt},
+		{Role: "user", Content: chatManage.RewriteQuery},
+	}, &chat.ChatOptions{
+		Temperature:         0.1,
+		MaxCompletionTokens: 300,
+		Thinking:            &thinking,
+	})
+	if err != nil {
+		pipelineWarn(ctx, "QueryDecompose", "model_call", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"error":      err.Error(),
+		})
+		return next()
+	}
+
+	chatManage.SubQueries = parseSubQueries(response.Content, chatManage.RewriteQuery)
+	pipelineInfo(ctx, "QueryDecompose", "output", map[string]interface{}{
+		"session_id":      chatManage.SessionID,
+		"rewrite_query":   chatManage.RewriteQuery,
+		"sub_queries":     chatManage.SubQueries,
+		"original_output": response.Content,
+	})
+	return next()
+}
+
+// parseSubQueries extracts the sub-query list from the model output. Blank,
+// duplicate and query-identical entries are dropped and the list is capped at
+// maxSubQueries. A single remaining sub-query adds nothing over the full
+// query, so it yields nil as well.
+func parseSubQueries(raw, query string) []string {
+	content := strings.TrimSpace(raw)
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end <= start {
+		return nil
+	}
+	var out struct {
+		SubQueries []string `json:"sub_queries"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &out); err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(query)): true}
+	subQueries := make([]string, 0, len(out.SubQueries))
+	for _, q := range out.SubQueries {
+		q = strings.TrimSpace(q)
+		key := strings.ToLower(q)
+		if q == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		subQueries = append(subQueries, q)
+		if len(subQueries) == maxSubQueries {
+			break
+		}
+	}
+	if len(subQueries) < 2 {
+		return nil
+	}
+	return subQueries
+}
@@ -0,0 +1,85 @@
+package chatpipeline
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubQueries(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "compound question",
+			raw:  `{"sub_queries": ["Milvus 的索引类型", "Qdrant 的索引类型"]}`,
+			want: []string{"Milvus 的索引类型", "Qdrant 的索引类型"},
+		},
+		{
+			name: "markdown wrapped",
+			raw:  "```json\n{\"sub_queries\": [\"a\", \"b\"]}\n```",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "simple question",
+			raw:  `{"sub_queries": []}`,
+		},
+		{
+			name: "single part adds nothing",
+			raw:  `{"sub_queries": ["a"]}`,
+		},
+		{
+			name: "drops blanks, duplicates and the query itself",
+			raw:  `{"sub_queries": ["a", " ", "A", "Compare A and B", "b"]}`,
+			want: []string{"a", "b"},
+		},
+		{
+			name: "capped",
+			raw:  `{"sub_queries": ["a", "b", "c", "d", "e"]}`,
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "not json",
+			raw:  "a; b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseSubQueries(tt.raw, "compare a and b"))
+		})
+	}
+}
+
+func TestQueryDecomposeSetsSubQueries(t *testing.T) {
+	model := &replyChat{reply: `{"sub_queries": ["Milvus 支持哪些索引", "Qdrant 支持哪些索引"]}`}
+	plugin := &PluginQueryDecompose{modelService: &stubModelService{model: model}}
+
+	cm := newQueryStageChatManage("Milvus 和 Qdrant 各支持哪些索引")
+	cm.EnableQueryDecomposition = true
+	require.Nil(t, plugin.OnEvent(t.Context(), types.QUERY_DECOMPOSE, cm, func() *PluginError { return nil }))
+
+	require.Equal(t, []string{"Milvus 支持哪些索引", "Qdrant 支持哪些索引"}, cm.SubQueries)
+}
+
+func TestSubQueryChatManageSearchesOnlyTheKnowledgeBases(t *testing.T) {
+	cm := newQueryStageChatManage("Milvus 和 Qdrant 各支持哪些索引")
+	cm.WebSearchEnabled = true
+	cm.EnableQueryExpansion = true
+	cm.HypotheticalDocument = "draft"
+	cm.SubQueries = []string{"Milvus 支持哪些索引", "Qdrant 支持哪些索引"}
+	cm.SearchResult = []*types.SearchResult{{ID: "c1"}}
+
+	sub := newSubQueryChatManage(cm, cm.SubQueries[1])
+
+	require.Equal(t, "Qdrant 支持哪些索引", sub.RewriteQuery)
+	require.Empty(t, sub.HypotheticalDocument)
+	require.Empty(t, sub.SubQueries)
+	require.Empty(t, sub.SearchResult)
+	require.False(t, sub.WebSearchEnabled)
+	require.False(t, sub.EnableQueryExpansion)
+	require.Equal(t, cm.SearchTargets[0].KnowledgeBaseID, sub.SearchTargets[0].KnowledgeBaseID)
+	require.True(t, cm.WebSearchEnabled, "the parent request is untouched")
+}
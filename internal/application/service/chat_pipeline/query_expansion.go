@@ -67,6 +67,7 @@ func (p *PluginSearch) runQueryExpansion(ctx context.Context, chatManage *types.
 					MatchCount:            expTopK,
 					TagIDs:                t.TagIDs,
 					ScopeTagIDs:           t.ScopeTagIDs,
+					MetadataFilter:        chatManage.MetadataFilter,
 					DisableVectorMatch:    false,
 					DisableKeywordsMatch:  false,
 					SkipContextEnrichment: true, // Pipeline handles context assembly in merge stage
@@ -0,0 +1,110 @@
+package chatpipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// hydePrompt asks for a passage shaped like the documents being searched.
+// The draft is never shown to the user; only its embedding is used, so a
+// plausible but wrong fact costs little while the vocabulary and structure
+// of a real answer pull the vector search towards matching chunks.
+const hydePrompt = `Write a short passage that answers the question below, in the style of a paragraph from a reference document or manual.
+- Write 3 to 6 sentences of plain prose, no headings, lists or preamble.
+- Use the terminology a document on this topic would use.
+- If you are unsure of specific facts, still write the most plausible passage.
+- Answer in the same language as the question.`
+
+// PluginHyDE implements hypothetical document embeddings: it drafts an answer
+// to the query and lets the search stage embed that draft instead of the
+// query. Keyword retrieval keeps using the rewritten query.
+type PluginHyDE struct {
+	modelService interfaces.ModelService
+}
+
+// NewPluginHyDE creates a new HyDE plugin and registers it with the event manager
+func NewPluginHyDE(eventManager *EventManager, modelService interfaces.ModelService) *PluginHyDE {
+	res := &PluginHyDE{modelService: modelService}
+	eventManager.Register(res)
+	return res
+}
+
+// ActivationEvents returns the event types this plugin handles
+func (p *PluginHyDE) ActivationEvents() []types.EventType {
+	return []types.EventType{types.QUERY_HYDE}
+}
+
+// OnEvent drafts the hypothetical document. Failures are logged and the
+// search falls back to the query embedding.
+func (p *PluginHyDE) OnEvent(ctx context.Context,
+	eventType types.EventType, chatManage *types.ChatManage, next func() *PluginError,
+) *PluginError {
+	chatManage.HypotheticalDocument = ""
+	if !chatManage.EnableHyDE || !chatManage.NeedsRetrieval() || !hasKBRetrievalTargets(chatManage) {
+		pipelineInfo(ctx, "HyDE", "skip", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"enabled":    chatManage.EnableHyDE,
+			"intent":     chatManage.Intent,
+		})
+		return next()
+	}
+
+	model, err := queryStageModel(ctx, p.modelService, chatManage)
+	if err != nil {
+		pipelineWarn(ctx, "HyDE", "get_model", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"error":      err.Error(),
+		})
+		return next()
+	}
+
+	thinking := false
+	modelCtx := types.WithLLMCallMetadata(ctx, "query_hyde", "")
+	response, err := model.Chat(modelCtx, []chat.Message{
+		{Role: "system", Content: hydePrompt},
+		{Role: "user", Content: chatManage.RewriteQuery},
+	}, &chat.ChatOptions{
+		Temperature:         0.5,
+		MaxCompletionTokens: 300,
+		Thinking:            &thinking,
+	})
+	if err != nil {
+		pipelineWarn(ctx, "HyDE", "model_call", map[string]interface{}{
+			"session_id": chatManage.SessionID,
+			"error":      err.Error(),
+		})
+		return next()
+	}
+
+	chatManage.HypotheticalDocument = strings.TrimSpace(response.Content)
+	pipelineInfo(ctx, "HyDE", "output", map[string]interface{}{
+		"session_id":    chatManage.SessionID,
+		"rewrite_query": chatManage.RewriteQuery,
+		"document_len":  len([]rune(chatManage.HypotheticalDocument)),
+	})
+	return next()
+}
+
+// queryStageModel returns the model for the query transformation stages:
+// the query-understanding model when configured and resolvable, otherwise
+// the chat model.
+func queryStageModel(ctx context.Context,
+	modelService interfaces.ModelService, chatManage *types.ChatManage,
+) (chat.Chat, error) {
+	if chatManage.QueryUnderstandModelID != "" {
+		m, err := modelService.GetChatModel(ctx, chatManage.QueryUnderstandModelID)
+		if err == nil {
+			return m, nil
+		}
+		pipelineWarn(ctx, "QueryStage", "query_understand_model_fallback", map[string]interface{}{
+			"session_id":                chatManage.SessionID,
+			"query_understand_model_id": chatManage.QueryUnderstandModelID,
+			"error":                     err.Error(),
+		})
+	}
+	return modelService.GetChatModel(ctx, chatManage.ChatModelID)
+}
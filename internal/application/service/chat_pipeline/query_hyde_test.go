@@ -0,0 +1,64 @@
+package chatpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// replyChat answers every Chat call with a fixed reply and records the
+// messages it was sent.
+type replyChat struct {
+	reply    string
+	messages []chat.Message
+}
+
+func (m *replyChat) Chat(_ context.Context, messages []chat.Message, _ *chat.ChatOptions) (*types.ChatResponse, error) {
+	m.messages = messages
+	return &types.ChatResponse{Content: m.reply}, nil
+}
+
+func (m *replyChat) ChatStream(context.Context, []chat.Message, *chat.ChatOptions) (<-chan types.StreamResponse, error) {
+	return nil, nil
+}
+
+func (m *replyChat) GetModelName() string { return "mock" }
+func (m *replyChat) GetModelID() string   { return "mock" }
+
+func newQueryStageChatManage(query string) *types.ChatManage {
+	cm := &types.ChatManage{}
+	cm.Query = query
+	cm.RewriteQuery = query
+	cm.KnowledgeBaseIDs = []string{"kb-1"}
+	cm.SearchTargets = types.SearchTargets{{Type: types.SearchTargetTypeKnowledgeBase, KnowledgeBaseID: "kb-1"}}
+	return cm
+}
+
+func TestHyDEDraftsHypotheticalDocument(t *testing.T) {
+	model := &replyChat{reply: "  分片大小默认为 512 个字符，可在知识库设置中调整。 "}
+	plugin := &PluginHyDE{modelService: &stubModelService{model: model}}
+
+	cm := newQueryStageChatManage("分片大小怎么设置")
+	cm.EnableHyDE = true
+	require.Nil(t, plugin.OnEvent(t.Context(), types.QUERY_HYDE, cm, func() *PluginError { return nil }))
+
+	require.Equal(t, "分片大小默认为 512 个字符，可在知识库设置中调整。", cm.HypotheticalDocument)
+	require.Equal(t, "分片大小怎么设置", model.messages[1].Content, "the draft answers the rewritten query")
+	require.Equal(t, "分片大小怎么设置", cm.RewriteQuery, "keyword search keeps the query")
+}
+
+func TestHyDESkipsWhenRetrievalIsNotNeeded(t *testing.T) {
+	model := &replyChat{reply: "draft"}
+	plugin := &PluginHyDE{modelService: &stubModelService{model: model}}
+
+	cm := newQueryStageChatManage("你好")
+	cm.EnableHyDE = true
+	cm.Intent = types.IntentGreeting
+	require.Nil(t, plugin.OnEvent(t.Context(), types.QUERY_HYDE, cm, func() *PluginError { return nil }))
+
+	require.Empty(t, cm.HypotheticalDocument)
+	require.Nil(t, model.messages, "no model call for a greeting")
+}
@@ -354,6 +354,13 @@ func (p *PluginSearch) searchByTargets(
 	}
 
 	queryText := strings.TrimSpace(chatManage.RewriteQuery)
+	// With HyDE the vector search embeds the drafted answer; keyword search
+	// still matches the query text. Groups whose model could not be resolved
+	// leave embedding to HybridSearch and therefore search with the query.
+	embedText := queryText
+	if chatManage.HypotheticalDocument != "" {
+		embedText = chatManage.HypotheticalDocument
+	}
 
 	// Batch-fetch KB records to determine embedding model grouping.
 	// On failure, all targets fall into an empty-key group and HybridSearch
@@ -415,7 +422,7 @@ func (p *PluginSearch) searchByTargets(
 			disableVector := false
 			searchableTargets := targets
 			if modelKey != "" {
-				emb, err := p.knowledgeBaseService.GetQueryEmbedding(ctx, targets[0].KnowledgeBaseID, queryText)
+				emb, err := p.knowledgeBaseService.GetQueryEmbedding(ctx, targets[0].KnowledgeBaseID, embedText)
 				if err != nil {
 					searchableTargets = make([]*types.SearchTarget, 0, len(targets))
 					for _, target := range targets {
@@ -456,6 +463,7 @@ func (p *PluginSearch) searchByTargets(
 				"combined_kb_count":  len(fullKBIDs),
 				"individual_targets": len(knowledgeTargets),
 				"vector_len":         len(queryEmbedding),
+				"hyde":               embedText != queryText && len(queryEmbedding) > 0,
 			})
 
 			var innerWg sync.WaitGroup
@@ -473,6 +481,7 @@ func (p *PluginSearch) searchByTargets(
 						VectorThreshold:       chatManage.VectorThreshold,
 						KeywordThreshold:      chatManage.KeywordThreshold,
 						MatchCount:            chatManage.EmbeddingTopK,
+						MetadataFilter:        chatManage.MetadataFilter,
 						SkipContextEnrichment: true,
 						DisableVectorMatch:    disableVector,
 					}
@@ -552,6 +561,7 @@ func (p *PluginSearch) searchSingleTarget(
 		MatchCount:            chatManage.EmbeddingTopK,
 		TagIDs:                t.TagIDs,
 		ScopeTagIDs:           t.ScopeTagIDs,
+		MetadataFilter:        chatManage.MetadataFilter,
 		SkipContextEnrichment: true,
 		DisableVectorMatch:    disableVector,
 	}
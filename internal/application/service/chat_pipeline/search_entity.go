@@ -132,7 +132,18 @@ func (p *PluginSearchEntity) OnEvent(ctx context.Context,
 		Node:     allNodes,
 		Relation: allRelations,
 	}
-	logger.Infof(ctx, "Total entity search result: %d nodes, %d relations", len(allNodes), len(allRelations))
+	excluded := entityExcludedKnowledgeIDs(chatManage)
+	if len(excluded) > 0 {
+		graph, err := p.filterExcludedGraph(ctx, chatManage.GraphResult, excluded)
+		if err != nil {
+			logger.Errorf(ctx, "Failed to filter entity graph, session_id: %s, error: %v", chatManage.SessionID, err)
+			chatManage.GraphResult = &types.GraphData{}
+			return next()
+		}
+		chatManage.GraphResult = graph
+	}
+	logger.Infof(ctx, "Total entity search result: %d nodes, %d relations",
+		len(chatManage.GraphResult.Node), len(chatManage.GraphResult.Relation))
 
 	chunkIDs := filterSeenChunk(ctx, chatManage.GraphResult, chatManage.SearchResult)
 	if len(chunkIDs) == 0 {
@@ -144,6 +155,13 @@ func (p *PluginSearchEntity) OnEvent(ctx context.Context,
 		logger.Errorf(ctx, "Failed to list chunks, session_id: %s, error: %v", chatManage.SessionID, err)
 		return next()
 	}
+	visibleChunks := chunks[:0]
+	for _, chunk := range chunks {
+		if !excluded[chunk.KnowledgeID] {
+			visibleChunks = append(visibleChunks, chunk)
+		}
+	}
+	chunks = visibleChunks
 	knowledgeIDs := []string{}
 	for _, chunk := range chunks {
 		knowledgeIDs = append(knowledgeIDs, chunk.KnowledgeID)
@@ -184,6 +202,84 @@ func (p *PluginSearchEntity) OnEvent(ctx context.Context,
 	return next()
 }
 
+// entityExcludedKnowledgeIDs collects the documents access lists hide from
+// the caller in the searched knowledge bases.
+func entityExcludedKnowledgeIDs(chatManage *types.ChatManage) map[string]bool {
+	excluded := make(map[string]bool)
+	for _, t := range chatManage.SearchTargets {
+		if t == nil {
+			continue
+		}
+		for _, id := range t.ExcludeKnowledgeIDs {
+			excluded[id] = true
+		}
+	}
+	return excluded
+}
+
+// filterExcludedGraph drops the graph evidence extracted from excluded
+// documents. A node or relation whose chunks all come from such documents is
+// removed, as is every relation touching a removed node; chunks that can no
+// longer be resolved are treated as hidden.
+func (p *PluginSearchEntity) filterExcludedGraph(
+	ctx context.Context, graph *types.GraphData, excluded map[string]bool,
+) (*types.GraphData, error) {
+	var chunkIDs []string
+	for _, node := range graph.Node {
+		chunkIDs = append(chunkIDs, node.Chunks...)
+	}
+	for _, relation := range graph.Relation {
+		chunkIDs = append(chunkIDs, relation.Chunks...)
+	}
+	visible := make(map[string]bool, len(chunkIDs))
+	if len(chunkIDs) > 0 {
+		chunks, err := p.chunkRepo.ListChunksByID(ctx, types.MustTenantIDFromContext(ctx), chunkIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			if !excluded[chunk.KnowledgeID] {
+				visible[chunk.ID] = true
+			}
+		}
+	}
+	visibleOnly := func(ids []string) []string {
+		kept := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if visible[id] {
+				kept = append(kept, id)
+			}
+		}
+		return kept
+	}
+
+	filtered := &types.GraphData{Text: graph.Text}
+	removedNodes := make(map[string]bool)
+	for _, node := range graph.Node {
+		chunks := visibleOnly(node.Chunks)
+		if len(node.Chunks) > 0 && len(chunks) == 0 {
+			removedNodes[node.Name] = true
+			continue
+		}
+		kept := *node
+		kept.Chunks = chunks
+		filtered.Node = append(filtered.Node, &kept)
+	}
+	for _, relation := range graph.Relation {
+		if removedNodes[relation.Node1] || removedNodes[relation.Node2] {
+			continue
+		}
+		chunks := visibleOnly(relation.Chunks)
+		if len(relation.Chunks) > 0 && len(chunks) == 0 {
+			continue
+		}
+		kept := *relation
+		kept.Chunks = chunks
+		filtered.Relation = append(filtered.Relation, &kept)
+	}
+	return filtered, nil
+}
+
 // filterSeenChunk filters seen chunks from the graph
 func filterSeenChunk(ctx context.Context, graph *types.GraphData, searchResult []*types.SearchResult) []string {
 	seen := map[string]bool{}
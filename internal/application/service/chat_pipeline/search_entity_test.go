@@ -0,0 +1,94 @@
+package chatpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+type entityGraphRepo struct {
+	interfaces.RetrieveGraphRepository
+	graph *types.GraphData
+}
+
+func (r *entityGraphRepo) SearchNode(
+	_ context.Context, _ types.NameSpace, _ []string,
+) (*types.GraphData, error) {
+	return r.graph, nil
+}
+
+type entityKnowledgeRepo struct {
+	interfaces.KnowledgeRepository
+}
+
+func (r *entityKnowledgeRepo) GetKnowledgeBatch(
+	_ context.Context, _ uint64, ids []string,
+) ([]*types.Knowledge, error) {
+	knowledges := make([]*types.Knowledge, 0, len(ids))
+	for _, id := range ids {
+		knowledges = append(knowledges, &types.Knowledge{ID: id, KnowledgeBaseID: "kb", Title: id})
+	}
+	return knowledges, nil
+}
+
+func TestSearchEntitySkipsExcludedKnowledge(t *testing.T) {
+	chunkRepo := &expandChunkRepo{chunks: map[string]*types.Chunk{
+		"open-chunk":   {ID: "open-chunk", KnowledgeID: "open", Content: "open body"},
+		"denied-chunk": {ID: "denied-chunk", KnowledgeID: "denied", Content: "denied body"},
+	}}
+	graphRepo := &entityGraphRepo{graph: &types.GraphData{
+		Node: []*types.GraphNode{
+			{Name: "shared", Chunks: []string{"open-chunk", "denied-chunk"}},
+			{Name: "secret", Chunks: []string{"denied-chunk"}},
+			{Name: "public", Chunks: []string{"open-chunk"}},
+		},
+		Relation: []*types.GraphRelation{
+			{Node1: "shared", Node2: "public", Chunks: []string{"open-chunk"}},
+			{Node1: "shared", Node2: "secret", Chunks: []string{"open-chunk"}},
+			{Node1: "shared", Node2: "public", Type: "hidden", Chunks: []string{"denied-chunk"}},
+		},
+	}}
+	plugin := &PluginSearchEntity{
+		graphRepo:     graphRepo,
+		chunkRepo:     chunkRepo,
+		knowledgeRepo: &entityKnowledgeRepo{},
+	}
+	ctx := context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1))
+	chatManage := &types.ChatManage{
+		PipelineRequest: types.PipelineRequest{
+			SearchTargets: types.SearchTargets{
+				{KnowledgeBaseID: "kb", ExcludeKnowledgeIDs: []string{"denied"}},
+			},
+		},
+		PipelineState: types.PipelineState{
+			Entity:      []string{"shared"},
+			EntityKBIDs: []string{"kb"},
+		},
+	}
+
+	if err := plugin.OnEvent(ctx, types.ENTITY_SEARCH, chatManage, func() *PluginError { return nil }); err != nil {
+		t.Fatalf("OnEvent() error = %v", err)
+	}
+
+	if len(chatManage.SearchResult) != 1 || chatManage.SearchResult[0].KnowledgeID != "open" {
+		t.Fatalf("search results = %+v, want only the open document", chatManage.SearchResult)
+	}
+	for _, node := range chatManage.GraphResult.Node {
+		if node.Name == "secret" {
+			t.Fatalf("node backed only by the denied document was returned")
+		}
+		for _, chunkID := range node.Chunks {
+			if chunkID == "denied-chunk" {
+				t.Fatalf("node %s still cites the denied chunk", node.Name)
+			}
+		}
+	}
+	if len(chatManage.GraphResult.Node) != 2 {
+		t.Fatalf("node count = %d, want 2", len(chatManage.GraphResult.Node))
+	}
+	if len(chatManage.GraphResult.Relation) != 1 {
+		t.Fatalf("relations = %+v, want only the open shared-public edge", chatManage.GraphResult.Relation)
+	}
+}
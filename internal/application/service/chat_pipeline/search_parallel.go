@@ -2,6 +2,7 @@ package chatpipeline
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Tencent/WeKnora/internal/config"
 	"github.com/Tencent/WeKnora/internal/logger"
@@ -86,7 +87,8 @@ func (p *PluginSearchParallel) ActivationEvents() []types.EventType {
 	return []types.EventType{types.CHUNK_SEARCH_PARALLEL}
 }
 
-// OnEvent handles parallel search events - runs chunk search and entity search concurrently
+// OnEvent handles parallel search events - runs chunk search, one chunk search
+// per decomposed sub-query, and entity search concurrently
 func (p *PluginSearchParallel) OnEvent(ctx context.Context,
 	eventType types.EventType, chatManage *types.ChatManage, next func() *PluginError,
 ) *PluginError {
@@ -103,6 +105,7 @@ func (p *PluginSearchParallel) OnEvent(ctx context.Context,
 		"session_id":    chatManage.SessionID,
 		"has_entities":  len(chatManage.Entity) > 0,
 		"rewrite_query": chatManage.RewriteQuery,
+		"sub_queries":   len(chatManage.SubQueries),
 	})
 
 	// Deep-copy to avoid concurrent read/write on shared slice fields
@@ -150,10 +153,38 @@ func (p *PluginSearchParallel) OnEvent(ctx context.Context,
 		},
 	}
 
+	subCMs := make([]*types.ChatManage, len(chatManage.SubQueries))
+	for i, subQuery := range chatManage.SubQueries {
+		subCM := newSubQueryChatManage(chatManage, subQuery)
+		subCMs[i] = subCM
+		tasks = append(tasks, ParallelTask{
+			Name: fmt.Sprintf("sub_query_search_%d", i),
+			Run: func() *PluginError {
+				err := p.searchPlugin.OnEvent(ctx, types.CHUNK_SEARCH, subCM, noop)
+				pipelineInfo(ctx, "SearchParallel", "sub_query_search_done", map[string]interface{}{
+					"sub_query":    subCM.RewriteQuery,
+					"result_count": len(subCM.SearchResult),
+					"has_error":    err != nil && err != ErrSearchNothing,
+				})
+				if err == ErrSearchNothing {
+					return nil
+				}
+				return err
+			},
+		})
+	}
+
 	errs := RunParallel(tasks...)
 
-	// Merge results from both searches
-	chatManage.SearchResult = append(chunkCM.SearchResult, entityCM.SearchResult...)
+	// Merge results from all searches; the full query's hits come first so
+	// they win deduplication.
+	chatManage.SearchResult = append([]*types.SearchResult(nil), chunkCM.SearchResult...)
+	subQueryResults := 0
+	for _, subCM := range subCMs {
+		chatManage.SearchResult = append(chatManage.SearchResult, subCM.SearchResult...)
+		subQueryResults += len(subCM.SearchResult)
+	}
+	chatManage.SearchResult = append(chatManage.SearchResult, entityCM.SearchResult...)
 	chatManage.SearchResult = removeDuplicateResults(chatManage.SearchResult)
 
 	for name, err := range errs {
@@ -161,11 +192,12 @@ func (p *PluginSearchParallel) OnEvent(ctx context.Context,
 	}
 
 	pipelineInfo(ctx, "SearchParallel", "complete", map[string]interface{}{
-		"session_id":     chatManage.SessionID,
-		"chunk_results":  len(chunkCM.SearchResult),
-		"entity_results": len(entityCM.SearchResult),
-		"total_results":  len(chatManage.SearchResult),
-		"error_count":    len(errs),
+		"session_id":        chatManage.SessionID,
+		"chunk_results":     len(chunkCM.SearchResult),
+		"sub_query_results": subQueryResults,
+		"entity_results":    len(entityCM.SearchResult),
+		"total_results":     len(chatManage.SearchResult),
+		"error_count":       len(errs),
 	})
 
 	if len(chatManage.SearchResult) == 0 {
@@ -177,3 +209,18 @@ func (p *PluginSearchParallel) OnEvent(ctx context.Context,
 
 	return next()
 }
+
+// newSubQueryChatManage prepares the search input for one sub-query. Web
+// search and query expansion stay with the full query so a decomposed
+// question does not multiply external calls, and the HyDE draft, written for
+// the full question, is not reused.
+func newSubQueryChatManage(chatManage *types.ChatManage, subQuery string) *types.ChatManage {
+	subCM := chatManage.Clone()
+	subCM.RewriteQuery = subQuery
+	subCM.HypotheticalDocument = ""
+	subCM.SubQueries = nil
+	subCM.SearchResult = nil
+	subCM.WebSearchEnabled = false
+	subCM.EnableQueryExpansion = false
+	return subCM
+}
@@ -390,6 +390,9 @@ func (s *chunkService) UpdateDocumentChunk(
 	if chunk.ChunkType != types.ChunkTypeText {
 		return nil, fmt.Errorf("only text chunks can be edited")
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbRepository, chunk.KnowledgeBaseID); err != nil {
+		return nil, err
+	}
 	if expectedRevision != nil && *expectedRevision != chunk.ContentRevision {
 		return nil, ErrChunkRevisionConflict
 	}
@@ -653,7 +656,8 @@ func (s *chunkService) syncChunkIndex(ctx context.Context, chunk *types.Chunk) e
 	if err != nil {
 		return err
 	}
-	engine, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, chunk.TenantID, kb.VectorStoreID)
+	engine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbRepository, chunk.TenantID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -670,8 +674,8 @@ func (s *chunkService) syncChunkIndex(ctx context.Context, chunk *types.Chunk) e
 	items := []*types.IndexInfo{{
 		Content: buildKnowledgeIndexContent(knowledge, chunk.EmbeddingContent()), SourceID: chunk.ID,
 		SourceType: types.ChunkSourceType, ChunkID: chunk.ID,
-		KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: chunk.KnowledgeBaseID,
-		KnowledgeType: kb.Type, IsEnabled: true,
+		KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
+		KnowledgeType: kb.Type, IsEnabled: true, Metadata: knowledge.IndexMetadata(),
 	}}
 	meta, err := chunk.DocumentMetadata()
 	if err != nil {
@@ -685,8 +689,8 @@ func (s *chunkService) syncChunkIndex(ctx context.Context, chunk *types.Chunk) e
 			items = append(items, &types.IndexInfo{
 				Content: buildKnowledgeIndexContent(knowledge, question.Question), SourceID: types.GeneratedQuestionSourceID(chunk.ID, question.ID),
 				SourceType: types.ChunkSourceType, ChunkID: chunk.ID,
-				KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: chunk.KnowledgeBaseID,
-				KnowledgeType: kb.Type, IsEnabled: true,
+				KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
+				KnowledgeType: kb.Type, IsEnabled: true, Metadata: knowledge.IndexMetadata(),
 			})
 		}
 	}
@@ -704,6 +708,9 @@ func (s *chunkService) UpsertGeneratedQuestion(
 	if err != nil {
 		return nil, err
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbRepository, chunk.KnowledgeBaseID); err != nil {
+		return nil, err
+	}
 	meta, err := chunk.DocumentMetadata()
 	if err != nil {
 		return nil, err
@@ -764,6 +771,9 @@ func (s *chunkService) DeleteGeneratedQuestion(ctx context.Context, chunkID stri
 		})
 		return fmt.Errorf("failed to get chunk: %w", err)
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbRepository, chunk.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	// 2. Parse the metadata
 	meta, err := chunk.DocumentMetadata()
@@ -804,8 +814,8 @@ func (s *chunkService) DeleteGeneratedQuestion(ctx context.Context, chunkID stri
 	// The source_id format is: {chunk_id}-{question_id}
 	sourceID := types.GeneratedQuestionSourceID(chunkID, questionID)
 
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbRepository, tenantID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, map[string]interface{}{
 			"chunk_id": chunkID,
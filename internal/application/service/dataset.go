@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -12,11 +14,13 @@ import (
 )
 
 // DatasetService provides operations for working with datasets
-type DatasetService struct{}
+type DatasetService struct {
+	repo interfaces.EvaluationRepository // Storage for uploaded datasets
+}
 
 // NewDatasetService creates a new DatasetService instance
-func NewDatasetService() interfaces.DatasetService {
-	return &DatasetService{}
+func NewDatasetService(repo interfaces.EvaluationRepository) interfaces.DatasetService {
+	return &DatasetService{repo: repo}
 }
 
 // TextInfo represents text data with ID in parquet format
@@ -37,19 +41,117 @@ type QaInfo struct {
 	AID int64 `parquet:"aid"` // Answer ID
 }
 
-// GetDatasetByID retrieves QA pairs from dataset by ID
+// GetDatasetByID retrieves QA pairs from dataset by ID.
+// The bundled sample is served for DefaultEvaluationDatasetID; any other ID
+// must be a dataset uploaded by the current tenant.
 func (d *DatasetService) GetDatasetByID(ctx context.Context, datasetID string) ([]*types.QAPair, error) {
 	logger.Info(ctx, "Start getting dataset by ID")
 	logger.Infof(ctx, "Getting dataset with ID: %s", datasetID)
 
-	dataset := DefaultDataset()
-	dataset.PrintStats(ctx)
-	qaPairs := dataset.Iterate()
+	if datasetID == "" || datasetID == types.DefaultEvaluationDatasetID {
+		dataset := DefaultDataset()
+		dataset.PrintStats(ctx)
+		qaPairs := dataset.Iterate()
+
+		logger.Infof(ctx, "Retrieved %d QA pairs from dataset", len(qaPairs))
+		return qaPairs, nil
+	}
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	if _, err := d.repo.GetDataset(ctx, tenantID, datasetID); err != nil {
+		if errors.Is(err, repository.ErrEvaluationDatasetNotFound) {
+			return nil, werrors.NewNotFoundError("evaluation dataset not found")
+		}
+		return nil, err
+	}
+	items, err := d.repo.ListDatasetItems(ctx, tenantID, datasetID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to list dataset items: %v", err)
+		return nil, err
+	}
+	qaPairs := make([]*types.QAPair, 0, len(items))
+	for _, item := range items {
+		qaPairs = append(qaPairs, item.QAPair())
+	}
 
 	logger.Infof(ctx, "Retrieved %d QA pairs from dataset", len(qaPairs))
 	return qaPairs, nil
 }
 
+// UploadDataset parses an uploaded dataset and stores it for the current tenant
+func (d *DatasetService) UploadDataset(
+	ctx context.Context, upload *types.EvaluationDatasetUpload,
+) (*types.EvaluationDataset, error) {
+	tenantID := types.MustTenantIDFromContext(ctx)
+	logger.Infof(ctx, "Uploading evaluation dataset, tenant: %d, format: %s", tenantID, upload.Format)
+
+	var (
+		qaPairs []*types.QAPair
+		err     error
+	)
+	switch upload.Format {
+	case types.EvaluationDatasetFormatJSONL:
+		qaPairs, err = parseJSONLDataset(upload.JSONL)
+	case types.EvaluationDatasetFormatParquet:
+		qaPairs, err = parseParquetDataset(upload.Parquet)
+	default:
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("unsupported dataset format: %s", upload.Format))
+	}
+	if err != nil {
+		logger.Warnf(ctx, "Failed to parse evaluation dataset: %v", err)
+		return nil, werrors.NewBadRequestError("invalid dataset").WithDetails(err.Error())
+	}
+	if len(qaPairs) == 0 {
+		return nil, werrors.NewBadRequestError("dataset contains no questions")
+	}
+	passageCount := compactPassageIDs(qaPairs)
+
+	dataset := &types.EvaluationDataset{
+		TenantID:     tenantID,
+		Name:         upload.Name,
+		Description:  upload.Description,
+		Format:       upload.Format,
+		QACount:      len(qaPairs),
+		PassageCount: passageCount,
+	}
+	items := make([]*types.EvaluationDatasetItem, 0, len(qaPairs))
+	for _, pair := range qaPairs {
+		items = append(items, &types.EvaluationDatasetItem{
+			QID:      pair.QID,
+			Question: pair.Question,
+			AID:      pair.AID,
+			Answer:   pair.Answer,
+			PIDs:     types.IntList(pair.PIDs),
+			Passages: types.StringArray(pair.Passages),
+		})
+	}
+	if err := d.repo.CreateDataset(ctx, dataset, items); err != nil {
+		logger.Errorf(ctx, "Failed to store evaluation dataset: %v", err)
+		return nil, err
+	}
+
+	logger.Infof(ctx, "Evaluation dataset stored, ID: %s, questions: %d, passages: %d",
+		dataset.ID, dataset.QACount, dataset.PassageCount)
+	return dataset, nil
+}
+
+// ListDatasets lists the uploaded datasets of the current tenant
+func (d *DatasetService) ListDatasets(ctx context.Context) ([]*types.EvaluationDataset, error) {
+	return d.repo.ListDatasets(ctx, types.MustTenantIDFromContext(ctx))
+}
+
+// DeleteDataset deletes an uploaded dataset of the current tenant
+func (d *DatasetService) DeleteDataset(ctx context.Context, datasetID string) error {
+	if datasetID == types.DefaultEvaluationDatasetID {
+		return werrors.NewBadRequestError("the default dataset cannot be deleted")
+	}
+	err := d.repo.DeleteDataset(ctx, types.MustTenantIDFromContext(ctx), datasetID)
+	if errors.Is(err, repository.ErrEvaluationDatasetNotFound) {
+		return werrors.NewNotFoundError("evaluation dataset not found")
+	}
+	return err
+}
+
 // DefaultDataset loads and initializes the default dataset from parquet files
 func DefaultDataset() dataset {
 	datasetDir := "./dataset/samples"
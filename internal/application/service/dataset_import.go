@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/parquet-go/parquet-go"
+)
+
+// maxDatasetLineBytes bounds a single JSONL record. Records carry the full
+// text of every relevant passage, so the default 64KiB scanner limit is too
+// small for real corpora.
+const maxDatasetLineBytes = 16 << 20
+
+// Parquet files accepted for an uploaded dataset, matching ./dataset/samples
+const (
+	datasetFileQueries = "queries"
+	datasetFileCorpus  = "corpus"
+	datasetFileAnswers = "answers"
+	datasetFileQrels   = "qrels"
+	datasetFileQas     = "qas"
+)
+
+// DatasetParquetFiles lists the parquet parts of an uploaded dataset; queries,
+// corpus and qrels are required, answers and qas are optional.
+var DatasetParquetFiles = []string{
+	datasetFileQueries, datasetFileCorpus, datasetFileAnswers, datasetFileQrels, datasetFileQas,
+}
+
+// datasetJSONLRecord is one line of an uploaded JSONL dataset:
+//
+//	{"qid": 1, "question": "...", "answer": "...", "passages": [{"pid": 7, "text": "..."}]}
+//
+// qid and pid are optional; passages may also be given as plain strings, in
+// which case identical texts share one passage ID.
+type datasetJSONLRecord struct {
+	QID      *int                  `json:"qid"`
+	Question string                `json:"question"`
+	Answer   string                `json:"answer"`
+	Passages []datasetJSONLPassage `json:"passages"`
+}
+
+type datasetJSONLPassage struct {
+	PID  *int   `json:"pid"`
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON accepts both {"pid": 1, "text": "..."} and a bare string
+func (p *datasetJSONLPassage) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		p.Text = text
+		return nil
+	}
+	type plain datasetJSONLPassage
+	return json.Unmarshal(data, (*plain)(p))
+}
+
+// parseJSONLDataset converts a JSONL upload into QA pairs
+func parseJSONLDataset(data []byte) ([]*types.QAPair, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxDatasetLineBytes)
+
+	// Passages without an explicit pid are keyed by text; explicit pids are
+	// kept apart from generated ones and compacted afterwards.
+	textPIDs := make(map[string]int)
+	nextPID := -1
+
+	var pairs []*types.QAPair
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record datasetJSONLRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if strings.TrimSpace(record.Question) == "" {
+			return nil, fmt.Errorf("line %d: question is required", lineNo)
+		}
+		if len(record.Passages) == 0 {
+			return nil, fmt.Errorf("line %d: at least one relevant passage is required", lineNo)
+		}
+
+		pair := &types.QAPair{
+			QID:      len(pairs),
+			Question: record.Question,
+			Answer:   record.Answer,
+			AID:      len(pairs),
+		}
+		if record.QID != nil {
+			pair.QID = *record.QID
+		}
+		for _, passage := range record.Passages {
+			if strings.TrimSpace(passage.Text) == "" {
+				return nil, fmt.Errorf("line %d: passage text is required", lineNo)
+			}
+			var pid int
+			if passage.PID != nil {
+				pid = *passage.PID
+				if pid < 0 {
+					return nil, fmt.Errorf("line %d: pid must not be negative", lineNo)
+				}
+			} else if existing, ok := textPIDs[passage.Text]; ok {
+				pid = existing
+			} else {
+				// Negative placeholders cannot collide with explicit pids.
+				pid = nextPID
+				nextPID--
+				textPIDs[passage.Text] = pid
+			}
+			pair.PIDs = append(pair.PIDs, pid)
+			pair.Passages = append(pair.Passages, passage.Text)
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// parseParquetDataset converts the parquet parts of an upload into QA pairs
+func parseParquetDataset(files map[string][]byte) ([]*types.QAPair, error) {
+	for _, name := range []string{datasetFileQueries, datasetFileCorpus, datasetFileQrels} {
+		if len(files[name]) == 0 {
+			return nil, fmt.Errorf("missing required parquet file: %s", name)
+		}
+	}
+	queries, err := readParquetBytes[TextInfo](datasetFileQueries, files[datasetFileQueries])
+	if err != nil {
+		return nil, err
+	}
+	corpus, err := readParquetBytes[TextInfo](datasetFileCorpus, files[datasetFileCorpus])
+	if err != nil {
+		return nil, err
+	}
+	qrels, err := readParquetBytes[RelsInfo](datasetFileQrels, files[datasetFileQrels])
+	if err != nil {
+		return nil, err
+	}
+	answers, err := readParquetBytes[TextInfo](datasetFileAnswers, files[datasetFileAnswers])
+	if err != nil {
+		return nil, err
+	}
+	qas, err := readParquetBytes[QaInfo](datasetFileQas, files[datasetFileQas])
+	if err != nil {
+		return nil, err
+	}
+
+	res := dataset{
+		queries: make(map[int64]string),
+		corpus:  make(map[int64]string),
+		answers: make(map[int64]string),
+		qrels:   make(map[int64][]int64),
+		qas:     make(map[int64]int64),
+	}
+	for _, qi := range queries {
+		res.queries[qi.ID] = qi.Text
+	}
+	for _, ci := range corpus {
+		res.corpus[ci.ID] = ci.Text
+	}
+	for _, ai := range answers {
+		res.answers[ai.ID] = ai.Text
+	}
+	for _, ri := range qrels {
+		if _, ok := res.corpus[ri.PID]; !ok {
+			return nil, fmt.Errorf("qrels references unknown passage %d", ri.PID)
+		}
+		res.qrels[ri.QID] = append(res.qrels[ri.QID], ri.PID)
+	}
+	for _, qi := range qas {
+		res.qas[qi.QID] = qi.AID
+	}
+
+	// Questions without any relevant passage cannot be scored for retrieval.
+	pairs := res.Iterate()
+	filtered := pairs[:0]
+	for _, pair := range pairs {
+		if len(pair.PIDs) > 0 {
+			filtered = append(filtered, pair)
+		}
+	}
+	return filtered, nil
+}
+
+// readParquetBytes decodes one optional parquet part; empty input yields no rows
+func readParquetBytes[T any](name string, data []byte) ([]T, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	rows, err := parquet.Read[T](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read %s.parquet: %w", name, err)
+	}
+	return rows, nil
+}
+
+// compactPassageIDs renumbers passage IDs to 0..n-1 in order of first
+// appearance and returns n. getPassageList allocates a slice sized by the
+// largest PID, so sparse IDs from external corpora must not reach it as-is.
+func compactPassageIDs(pairs []*types.QAPair) int {
+	mapping := make(map[int]int)
+	for _, pair := range pairs {
+		for i, pid := range pair.PIDs {
+			compact, ok := mapping[pid]
+			if !ok {
+				compact = len(mapping)
+				mapping[pid] = compact
+			}
+			pair.PIDs[i] = compact
+		}
+	}
+	return len(mapping)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONLDataset(t *testing.T) {
+	data := []byte(`{"question": "q1", "answer": "a1", "passages": ["shared", "only q1"]}
+
+{"qid": 7, "question": "q2", "passages": ["shared", {"pid": 40, "text": "explicit"}]}
+`)
+	pairs, err := parseJSONLDataset(data)
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+	require.Equal(t, 0, pairs[0].QID)
+	require.Equal(t, 7, pairs[1].QID)
+	// Identical passage texts share one ID across questions.
+	require.Equal(t, pairs[0].PIDs[0], pairs[1].PIDs[0])
+
+	n := compactPassageIDs(pairs)
+	require.Equal(t, 3, n)
+	require.Equal(t, []int{0, 1}, pairs[0].PIDs)
+	require.Equal(t, []int{0, 2}, pairs[1].PIDs)
+}
+
+func TestParseJSONLDatasetRejectsInvalidRecords(t *testing.T) {
+	for name, line := range map[string]string{
+		"malformed":   `{"question": `,
+		"no question": `{"passages": ["p"]}`,
+		"no passages": `{"question": "q"}`,
+		"empty text":  `{"question": "q", "passages": [""]}`,
+		"negative id": `{"question": "q", "passages": [{"pid": -1, "text": "p"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseJSONLDataset([]byte(line))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseParquetDatasetMatchesBundledSample(t *testing.T) {
+	files := make(map[string][]byte)
+	for _, name := range DatasetParquetFiles {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", "dataset", "samples", name+".parquet"))
+		require.NoError(t, err)
+		files[name] = data
+	}
+	pairs, err := parseParquetDataset(files)
+	require.NoError(t, err)
+	require.NotEmpty(t, pairs)
+	for _, pair := range pairs {
+		require.NotEmpty(t, pair.Question)
+		require.Len(t, pair.Passages, len(pair.PIDs))
+	}
+
+	delete(files, datasetFileQrels)
+	_, err = parseParquetDataset(files)
+	require.ErrorContains(t, err, "qrels")
+}
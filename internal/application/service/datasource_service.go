@@ -14,7 +14,9 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/metrics"
 	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -34,6 +36,7 @@ type DataSourceService struct {
 	tenantRepo        interfaces.TenantRepository
 	tagService        interfaces.KnowledgeTagService
 	audit             interfaces.AuditLogService
+	aclService        interfaces.KnowledgeACLService
 }
 
 // NewDataSourceService creates a new data source service
@@ -48,6 +51,7 @@ func NewDataSourceService(
 	tenantRepo interfaces.TenantRepository,
 	tagService interfaces.KnowledgeTagService,
 	audit interfaces.AuditLogService,
+	aclService interfaces.KnowledgeACLService,
 ) interfaces.DataSourceService {
 	return &DataSourceService{
 		dsRepo:            dsRepo,
@@ -60,6 +64,7 @@ func NewDataSourceService(
 		tenantRepo:        tenantRepo,
 		tagService:        tagService,
 		audit:             audit,
+		aclService:        aclService,
 	}
 }
 
@@ -214,11 +219,34 @@ func (s *DataSourceService) UpdateDataSource(ctx context.Context, ds *types.Data
 		}
 	}
 
+	// Permission sync toggled: forget the permission fingerprints so the next
+	// sync reports the permissions of every item again, and drop what was
+	// synced when it is turned off.
+	if ds.SyncPermissions == nil {
+		ds.SyncPermissions = existing.SyncPermissions
+	}
+	permissionSyncToggled := ds.PermissionSyncEnabled() != existing.PermissionSyncEnabled()
+	if permissionSyncToggled {
+		if cursor, err := existing.ParseSyncCursor(); err == nil && cursor != nil {
+			cursor.SetPermissionFingerprints(nil)
+			if cursorJSON, err := cursor.ToJSON(); err == nil {
+				ds.LastSyncCursor = cursorJSON
+			}
+		}
+	}
+
 	if err := s.dsRepo.Update(ctx, ds); err != nil {
 		logger.Errorf(ctx, "failed to update data source: %v", err)
 		return nil, err
 	}
 
+	if permissionSyncToggled && !ds.PermissionSyncEnabled() && s.aclService != nil {
+		if err := s.aclService.ClearSourcePermissions(ctx, ds.ID); err != nil {
+			logger.Errorf(ctx, "failed to clear synced permissions of ds=%s: %v", ds.ID, err)
+			return nil, err
+		}
+	}
+
 	// Update cron schedule
 	if err := s.scheduler.AddOrUpdate(ds); err != nil {
 		logger.Warnf(ctx, "failed to update cron for ds=%s: %v", ds.ID, err)
@@ -499,6 +527,7 @@ func (s *DataSourceService) ManualSync(ctx context.Context, dsID string) (*types
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = err.Error()
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		if ds.Status != types.DataSourceStatusPaused {
 			ds.Status = types.DataSourceStatusError
 		}
@@ -624,6 +653,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = "knowledge base has been deleted"
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		return nil
 	}
 
@@ -637,6 +667,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = fmt.Sprintf("Connector not found: %s", ds.Type)
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		if !wasPaused {
 			ds.Status = types.DataSourceStatusError
 		}
@@ -653,6 +684,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = fmt.Sprintf("Invalid configuration: %v", err)
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		if !wasPaused {
 			ds.Status = types.DataSourceStatusError
 		}
@@ -663,6 +695,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 	// Surface the KB's multimodal/VLM state to the connector so it only extracts
 	// embedded images for OCR when the KB can actually ingest them (never persisted).
 	config.MultimodalEnabled = kb.IsMultimodalEnabled()
+	config.SyncPermissions = ds.PermissionSyncEnabled()
 
 	// Streaming path: connectors that support it interleave fetch→ingest→
 	// checkpoint so a large sync bounds memory and resumes after a timeout
@@ -710,6 +743,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = fmt.Sprintf("Fetch failed: %v", fetchErr)
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		if !wasPaused {
 			ds.Status = types.DataSourceStatusError
 		}
@@ -733,6 +767,7 @@ func (s *DataSourceService) ProcessSync(ctx context.Context, task *asynq.Task) e
 		syncLog.FinishedAt = timePtr(time.Now().UTC())
 		syncLog.ErrorMessage = fmt.Sprintf("Failed to get tenant info: %v", err)
 		_ = s.syncLogRepo.Update(ctx, syncLog)
+		observeSyncOutcome(ds.Type, syncLog)
 		if !wasPaused {
 			ds.Status = types.DataSourceStatusError
 		}
@@ -919,6 +954,11 @@ func (s *DataSourceService) applyFetchedItem(
 		return
 	}
 
+	if item.PermissionsOnly {
+		s.applyItemPermissions(ctx, ds, item, result)
+		return
+	}
+
 	if len(item.Content) == 0 && item.URL == "" {
 		// Check if this is an error item from the connector (failed to fetch content)
 		if errMsg, hasErr := item.Metadata["error"]; hasErr {
@@ -966,6 +1006,72 @@ func (s *DataSourceService) applyFetchedItem(
 	}
 }
 
+// applyItemPermissions updates the synced access list of an item whose
+// content is unchanged, together with the sub-items fanned out from it.
+func (s *DataSourceService) applyItemPermissions(
+	ctx context.Context, ds *types.DataSource, item *types.FetchedItem, result *types.SyncResult,
+) {
+	if !ds.PermissionSyncEnabled() || s.aclService == nil || item.Permissions == nil || item.ExternalID == "" {
+		result.Skipped++
+		return
+	}
+	repo := s.knowledgeService.GetRepository()
+	existing, err := repo.FindByDataSourceExternalID(ctx, ds.TenantID, ds.KnowledgeBaseID, ds.ID, item.ExternalID)
+	if err == nil && existing == nil {
+		// Never synced (e.g. it failed to ingest); nothing to restrict yet.
+		result.Skipped++
+		return
+	}
+	var ids []string
+	if err == nil {
+		ids = append(ids, existing.ID)
+		var children []*types.Knowledge
+		children, err = repo.FindByMetadataKeyPrefix(ctx, ds.TenantID, ds.KnowledgeBaseID,
+			"external_id", types.SubtreeChildPrefix(item.ExternalID))
+		for _, child := range children {
+			if child.GetMetadata()["datasource_id"] == ds.ID {
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+	if err == nil {
+		err = s.aclService.ApplySourcePermissions(ctx, ds, ids, item.Permissions)
+	}
+	if err != nil {
+		logger.Errorf(ctx, "failed to sync permissions of external_id=%s (ds=%s): %v", item.ExternalID, ds.ID, err)
+		result.Failed++
+		recordSyncError(result, types.SyncItemError{
+			Title:   item.Title,
+			Code:    "permission_sync_failed",
+			Message: "Failed to sync permissions; see server logs",
+		})
+		return
+	}
+	result.Updated++
+}
+
+// syncKnowledgeACL carries the access list of a replaced document over to
+// the document that replaced it, then applies the permissions the connector
+// reported for the item.
+func (s *DataSourceService) syncKnowledgeACL(
+	ctx context.Context, ds *types.DataSource, item *types.FetchedItem, replacedID string, knowledge *types.Knowledge,
+) error {
+	if s.aclService == nil || knowledge == nil {
+		return nil
+	}
+	if replacedID != "" {
+		if err := s.aclService.MoveKnowledgeACL(ctx, ds.KnowledgeBaseID, replacedID, knowledge.ID); err != nil {
+			return fmt.Errorf("move access list: %w", err)
+		}
+	}
+	if ds.PermissionSyncEnabled() && item.Permissions != nil {
+		if err := s.aclService.ApplySourcePermissions(ctx, ds, []string{knowledge.ID}, item.Permissions); err != nil {
+			return fmt.Errorf("apply source permissions: %w", err)
+		}
+	}
+	return nil
+}
+
 // streamStartCursor decides which cursor a streaming fetch should resume from.
 // A user-triggered full sync on its first attempt drops the cursor so every
 // item is re-fetched; a retried full sync (attempt > 0) and every incremental
@@ -1137,6 +1243,7 @@ func (s *DataSourceService) updateSyncRunResult(
 	if err := s.syncLogRepo.UpdateResult(ctx, syncLog); err != nil {
 		logger.Errorf(ctx, "failed to update sync log: %v", err)
 	}
+	observeSyncOutcome(ds.Type, syncLog)
 
 	if status == types.SyncLogStatusFailed {
 		if !wasPaused {
@@ -1167,6 +1274,28 @@ func (s *DataSourceService) updateSyncRunResult(
 			"total": result.Total, "created": result.Created, "updated": result.Updated,
 			"deleted": result.Deleted, "skipped": result.Skipped, "failed": result.Failed,
 		})
+	publishWorkspaceEvent(ctx, event.EventDataSourceSyncFinished, event.DataSourceSyncFinishedData{
+		TenantID:        ds.TenantID,
+		KnowledgeBaseID: ds.KnowledgeBaseID,
+		DataSourceID:    ds.ID,
+		DataSourceName:  ds.Name,
+		SyncLogID:       syncLog.ID,
+		Status:          status,
+		ItemsCreated:    result.Created,
+		ItemsUpdated:    result.Updated,
+		ItemsDeleted:    result.Deleted,
+		ItemsFailed:     result.Failed,
+		ErrorMessage:    errorMessage,
+	})
+}
+
+// observeSyncOutcome reports a finished sync to the operational metrics
+func observeSyncOutcome(connector string, syncLog *types.SyncLog) {
+	var elapsed time.Duration
+	if syncLog.FinishedAt != nil && !syncLog.StartedAt.IsZero() {
+		elapsed = syncLog.FinishedAt.Sub(syncLog.StartedAt)
+	}
+	metrics.ObserveDataSourceSync(connector, syncLog.Status, elapsed)
 }
 
 func allFetchedItemsFailedError(result *types.SyncResult) error {
@@ -1257,6 +1386,7 @@ func (s *DataSourceService) ingestItem(ctx context.Context, ds *types.DataSource
 
 	// Check if a knowledge item with this external_id already exists → delete it first (update)
 	isUpdate := false
+	replacedID := ""
 	if item.ExternalID != "" {
 		repo := s.knowledgeService.GetRepository()
 		// Scope the lookup to items owned by this data source so identical
@@ -1268,13 +1398,16 @@ func (s *DataSourceService) ingestItem(ctx context.Context, ds *types.DataSource
 			// Non-fatal: proceed with creation (may produce duplicate)
 		} else if existing != nil {
 			logger.Infof(ctx, "found existing knowledge %s for external_id=%s, deleting for update", existing.ID, item.ExternalID)
-			if err := s.knowledgeService.DeleteKnowledge(ctx, existing.ID); err != nil {
+			// The access list stays behind so syncKnowledgeACL can move it onto
+			// the replacement once it exists.
+			if err := s.knowledgeService.DeleteKnowledge(types.WithKnowledgeACLKept(ctx), existing.ID); err != nil {
 				logger.Warnf(ctx, "failed to delete existing knowledge %s: %v", existing.ID, err)
 			} else {
 				if herr := repo.HardDeleteKnowledge(ctx, ds.TenantID, existing.ID); herr != nil {
 					logger.Warnf(ctx, "failed to hard-delete replaced knowledge %s: %v", existing.ID, herr)
 				}
 				isUpdate = true
+				replacedID = existing.ID
 			}
 		}
 	}
@@ -1285,7 +1418,7 @@ func (s *DataSourceService) ingestItem(ctx context.Context, ds *types.DataSource
 		if err != nil {
 			return isUpdate, fmt.Errorf("build file header: %w", err)
 		}
-		if _, err := s.knowledgeService.CreateKnowledgeFromFile(
+		created, err := s.knowledgeService.CreateKnowledgeFromFile(
 			ctx,
 			ds.KnowledgeBaseID,
 			fh,
@@ -1295,16 +1428,23 @@ func (s *DataSourceService) ingestItem(ctx context.Context, ds *types.DataSource
 			tagIDs,        // auto-tag from data source
 			channel,
 			nil,
-		); err != nil {
+		)
+		if err != nil {
 			var dupErr *types.DuplicateKnowledgeError
 			if errors.As(err, &dupErr) && dupIsSameNode(dupErr, item) {
 				// Identical content is already present in the KB under THIS node's
 				// own external_id, so the parent effectively exists — reconcile the
 				// subtree so children removed from the doc do not linger.
 				s.sweepStaleSubtree(ctx, ds, item)
+				if aclErr := s.syncKnowledgeACL(ctx, ds, item, "", dupErr.Knowledge); aclErr != nil {
+					logger.Warnf(ctx, "failed to sync access list of external_id=%s: %v", item.ExternalID, aclErr)
+				}
 			}
 			return isUpdate, err
 		}
+		if err := s.syncKnowledgeACL(ctx, ds, item, replacedID, created); err != nil {
+			return isUpdate, err
+		}
 		s.sweepStaleSubtree(ctx, ds, item)
 		return isUpdate, nil
 	}
@@ -1330,9 +1470,15 @@ func (s *DataSourceService) ingestItem(ctx context.Context, ds *types.DataSource
 				// own external_id, so the parent effectively exists — reconcile the
 				// subtree so children removed from the doc do not linger.
 				s.sweepStaleSubtree(ctx, ds, item)
+				if aclErr := s.syncKnowledgeACL(ctx, ds, item, "", dupErr.Knowledge); aclErr != nil {
+					logger.Warnf(ctx, "failed to sync access list of external_id=%s: %v", item.ExternalID, aclErr)
+				}
 			}
 			return isUpdate, err
 		}
+		if err := s.syncKnowledgeACL(ctx, ds, item, replacedID, created); err != nil {
+			return isUpdate, err
+		}
 		// URL-created knowledge has no metadata, so a later deletion could
 		// never find it. Attach the datasource keys on fresh creation only;
 		// the duplicate path reuses an existing row that must not be re-tagged.
@@ -124,6 +124,18 @@ func (s *processSyncKBService) GetRepository() interfaces.KnowledgeBaseRepositor
 func (s *processSyncKBService) ProcessKBDelete(context.Context, *asynq.Task) error {
 	return nil
 }
+func (s *processSyncKBService) SetEmbeddingModel(context.Context, string, string) (*types.EmbeddingMigration, error) {
+	return nil, nil
+}
+func (s *processSyncKBService) ProcessEmbeddingMigration(context.Context, *asynq.Task) error {
+	return nil
+}
+func (s *processSyncKBService) MigrateVectorStore(context.Context, string, string) (*types.VectorStoreMigration, error) {
+	return nil, nil
+}
+func (s *processSyncKBService) ProcessVectorStoreMigration(context.Context, *asynq.Task) error {
+	return nil
+}
 
 var _ interfaces.KnowledgeBaseService = (*processSyncKBService)(nil)
 
@@ -705,3 +717,46 @@ func TestIngestItem_URLCreationMetadataAttachFailure(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "attach datasource metadata")
 }
+
+// aclDeletingKS deletes access lists the way knowledgeService.DeleteKnowledge
+// does once the row is gone.
+type aclDeletingKS struct {
+	sweepFakeKS
+	deleter *knowledgeService
+}
+
+func (k *aclDeletingKS) DeleteKnowledge(ctx context.Context, id string) error {
+	if err := k.sweepFakeKS.DeleteKnowledge(ctx, id); err != nil {
+		return err
+	}
+	k.deleter.deleteKnowledgeACLs(ctx, "kb-1", []string{id})
+	return nil
+}
+
+// TestIngestItem_ReplacementKeepsAccessList verifies that a changed source
+// item hands the access list of the document it replaces to the new one
+// instead of losing it when the old document is deleted first.
+func TestIngestItem_ReplacementKeepsAccessList(t *testing.T) {
+	acl := newKnowledgeACLTestService(t)
+	repo := &deletionLookupKnowledgeRepo{knowledge: &types.Knowledge{ID: "k-fin-own", KnowledgeBaseID: "kb-1"}}
+	ks := &aclDeletingKS{
+		sweepFakeKS: sweepFakeKS{repo: repo},
+		deleter:     &knowledgeService{knowledgeACL: acl},
+	}
+	svc := &DataSourceService{knowledgeService: ks, aclService: acl}
+	ds := &types.DataSource{ID: "ds-1", TenantID: 1, KnowledgeBaseID: "kb-1"}
+
+	isUpdate, err := svc.ingestItem(context.Background(), ds, &types.FetchedItem{
+		ExternalID: "file:1",
+		Content:    []byte("# updated\n"),
+		FileName:   "doc.md",
+	}, nil)
+	require.NoError(t, err)
+	assert.True(t, isUpdate)
+	assert.Equal(t, []string{"k-fin-own"}, ks.deleted)
+
+	moved, err := acl.repo.ListByTarget(context.Background(), "kb-1", types.KnowledgeACLTargetKnowledge, "new-knowledge")
+	require.NoError(t, err)
+	require.Len(t, moved, 1)
+	assert.Equal(t, "u2", moved[0].PrincipalID)
+}
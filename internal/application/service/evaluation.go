@@ -8,7 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/application/service/metric"
 	"github.com/Tencent/WeKnora/internal/config"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -24,6 +28,10 @@ qrels: qid -> pid
 arels: qid -> aid
 */
 
+// evaluationStaleAfter is how long a pending or running task may go without
+// progress before it is considered abandoned by a process that exited.
+const evaluationStaleAfter = 30 * time.Minute
+
 // EvaluationService handles evaluation tasks for knowledge base and chat models
 type EvaluationService struct {
 	config               *config.Config                  // Application configuration
@@ -32,8 +40,7 @@ type EvaluationService struct {
 	knowledgeService     interfaces.KnowledgeService     // Service for knowledge operations
 	sessionService       interfaces.SessionService       // Service for chat sessions
 	modelService         interfaces.ModelService         // Service for model operations
-
-	evaluationMemoryStorage *evaluationMemoryStorage // In-memory storage for evaluation tasks
+	repo                 interfaces.EvaluationRepository // Storage for tasks and per-question results
 }
 
 func NewEvaluationService(
@@ -43,86 +50,115 @@ func NewEvaluationService(
 	knowledgeService interfaces.KnowledgeService,
 	sessionService interfaces.SessionService,
 	modelService interfaces.ModelService,
+	repo interfaces.EvaluationRepository,
 ) interfaces.EvaluationService {
-	evaluationMemoryStorage := newEvaluationMemoryStorage()
 	return &EvaluationService{
-		config:                  config,
-		dataset:                 dataset,
-		knowledgeBaseService:    knowledgeBaseService,
-		knowledgeService:        knowledgeService,
-		sessionService:          sessionService,
-		modelService:            modelService,
-		evaluationMemoryStorage: evaluationMemoryStorage,
-	}
-}
-
-// evaluationMemoryStorage stores evaluation tasks in memory with thread-safe access
-type evaluationMemoryStorage struct {
-	store map[string]*types.EvaluationDetail // Map of taskID to evaluation details
-	mu    *sync.RWMutex                      // Read-write lock for concurrent access
-}
-
-func newEvaluationMemoryStorage() *evaluationMemoryStorage {
-	res := &evaluationMemoryStorage{
-		store: make(map[string]*types.EvaluationDetail),
-		mu:    &sync.RWMutex{},
+		config:               config,
+		dataset:              dataset,
+		knowledgeBaseService: knowledgeBaseService,
+		knowledgeService:     knowledgeService,
+		sessionService:       sessionService,
+		modelService:         modelService,
+		repo:                 repo,
 	}
-	return res
 }
 
-func (e *evaluationMemoryStorage) register(params *types.EvaluationDetail) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	logger.Infof(context.Background(), "Registering evaluation task: %s", params.Task.ID)
-	e.store[params.Task.ID] = params
-}
-
-func (e *evaluationMemoryStorage) get(taskID string) (*types.EvaluationDetail, error) {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-	logger.Infof(context.Background(), "Getting evaluation task: %s", taskID)
-	res, ok := e.store[taskID]
-	if !ok {
-		return nil, errors.New("task not found")
+// RecoverStaleEvaluations fails tasks whose worker is gone. Evaluation runs
+// in a goroutine of the process that accepted it, so a restart leaves its
+// row pending or running with no one left to finish it.
+func RecoverStaleEvaluations(ctx context.Context, repo interfaces.EvaluationRepository) {
+	n, err := repo.FailStaleTasks(ctx, time.Now().Add(-evaluationStaleAfter), "evaluation interrupted by server restart")
+	if err != nil {
+		logger.Warnf(ctx, "Failed to recover stale evaluation tasks: %v", err)
+		return
 	}
-	return res, nil
-}
-
-func (e *evaluationMemoryStorage) update(taskID string, fn func(params *types.EvaluationDetail)) error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	params, ok := e.store[taskID]
-	if !ok {
-		return errors.New("task not found")
+	if n > 0 {
+		logger.Infof(ctx, "Marked %d stale evaluation tasks as failed", n)
 	}
-	fn(params)
-	return nil
 }
 
 func (e *EvaluationService) EvaluationResult(ctx context.Context, taskID string) (*types.EvaluationDetail, error) {
 	logger.Info(ctx, "Start getting evaluation result")
 	logger.Infof(ctx, "Task ID: %s", taskID)
 
-	detail, err := e.evaluationMemoryStorage.get(taskID)
+	tenantID := types.MustTenantIDFromContext(ctx)
+	task, err := e.repo.GetTask(ctx, tenantID, taskID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get evaluation task: %v", err)
+		if errors.Is(err, repository.ErrEvaluationTaskNotFound) {
+			return nil, werrors.NewNotFoundError("evaluation task not found")
+		}
 		return nil, err
 	}
 
+	logger.Info(ctx, "Evaluation result retrieved successfully")
+	return &types.EvaluationDetail{
+		Task:   task,
+		Params: e.evaluationParams(task.ChatModelID, task.RerankModelID),
+		Metric: task.Metric,
+	}, nil
+}
+
+// ListEvaluations lists the evaluation tasks of the current tenant, newest first
+func (e *EvaluationService) ListEvaluations(ctx context.Context, limit int) ([]*types.EvaluationTask, error) {
+	return e.repo.ListTasks(ctx, types.MustTenantIDFromContext(ctx), limit)
+}
+
+// ListQuestionResults returns the per-question results of an evaluation task
+func (e *EvaluationService) ListQuestionResults(
+	ctx context.Context, taskID string,
+) ([]*types.EvaluationQuestionResult, error) {
 	tenantID := types.MustTenantIDFromContext(ctx)
-	logger.Infof(
-		ctx,
-		"Checking tenant ID match, task tenant ID: %d, current tenant ID: %d",
-		detail.Task.TenantID, tenantID,
-	)
-
-	if tenantID != detail.Task.TenantID {
-		logger.Error(ctx, "Tenant ID mismatch")
-		return nil, errors.New("tenant ID does not match")
+	if _, err := e.repo.GetTask(ctx, tenantID, taskID); err != nil {
+		if errors.Is(err, repository.ErrEvaluationTaskNotFound) {
+			return nil, werrors.NewNotFoundError("evaluation task not found")
+		}
+		return nil, err
 	}
+	return e.repo.ListQuestionResults(ctx, tenantID, taskID)
+}
 
-	logger.Info(ctx, "Evaluation result retrieved successfully")
-	return detail, nil
+// evaluationParams builds the pipeline parameters an evaluation runs with.
+// Only the model IDs vary per task; everything else comes from the
+// conversation config, so the parameters are rebuilt rather than stored.
+func (e *EvaluationService) evaluationParams(chatModelID, rerankModelID string) *types.ChatManage {
+	return &types.ChatManage{
+		PipelineRequest: types.PipelineRequest{
+			VectorThreshold:  e.config.Conversation.VectorThreshold,
+			KeywordThreshold: e.config.Conversation.KeywordThreshold,
+			EmbeddingTopK:    e.config.Conversation.EmbeddingTopK,
+			MaxRounds:        e.config.Conversation.MaxRounds,
+			RerankModelID:    rerankModelID,
+			RerankTopK:       e.config.Conversation.RerankTopK,
+			RerankThreshold:  e.config.Conversation.RerankThreshold,
+			ChatModelID:      chatModelID,
+			SummaryConfig: types.SummaryConfig{
+				MaxTokens:           e.config.Conversation.Summary.MaxTokens,
+				RepeatPenalty:       e.config.Conversation.Summary.RepeatPenalty,
+				TopK:                e.config.Conversation.Summary.TopK,
+				TopP:                e.config.Conversation.Summary.TopP,
+				Prompt:              e.config.Conversation.Summary.Prompt,
+				ContextTemplate:     e.config.Conversation.Summary.ContextTemplate,
+				FrequencyPenalty:    e.config.Conversation.Summary.FrequencyPenalty,
+				PresencePenalty:     e.config.Conversation.Summary.PresencePenalty,
+				NoMatchPrefix:       e.config.Conversation.Summary.NoMatchPrefix,
+				Temperature:         e.config.Conversation.Summary.Temperature,
+				Seed:                e.config.Conversation.Summary.Seed,
+				MaxCompletionTokens: e.config.Conversation.Summary.MaxCompletionTokens,
+			},
+			FallbackResponse:    e.config.Conversation.FallbackResponse,
+			RewritePromptSystem: e.config.Conversation.RewritePromptSystem,
+			RewritePromptUser:   e.config.Conversation.RewritePromptUser,
+		},
+	}
+}
+
+// updateTask persists task progress, logging rather than failing the run:
+// a missed progress write is recovered by the next one.
+func (e *EvaluationService) updateTask(ctx context.Context, task *types.EvaluationTask) {
+	if err := e.repo.UpdateTaskProgress(ctx, task); err != nil {
+		logger.Errorf(ctx, "Failed to update evaluation task %s: %v", task.ID, err)
+	}
 }
 
 // Evaluation starts a new evaluation task with given parameters
@@ -130,17 +166,30 @@ func (e *EvaluationService) EvaluationResult(ctx context.Context, taskID string)
 // knowledgeBaseID: ID of the knowledge base to use (empty to create new)
 // chatModelID: ID of the chat model to evaluate
 // rerankModelID: ID of the rerank model to evaluate
+// judgeModelID: ID of the chat model scoring judge metrics (empty to skip them)
+// fusionConfig: hybrid fusion of the run's knowledge base (nil for the tenant default)
 func (e *EvaluationService) Evaluation(ctx context.Context,
-	datasetID string, knowledgeBaseID string, chatModelID string, rerankModelID string,
+	datasetID string, knowledgeBaseID string, chatModelID string, rerankModelID string, judgeModelID string,
+	fusionConfig *types.FusionConfig,
 ) (*types.EvaluationDetail, error) {
 	logger.Info(ctx, "Start evaluation")
-	logger.Infof(ctx, "Dataset ID: %s, Knowledge Base ID: %s, Chat Model ID: %s, Rerank Model ID: %s",
-		datasetID, knowledgeBaseID, chatModelID, rerankModelID)
+	logger.Infof(ctx, "Dataset ID: %s, Knowledge Base ID: %s, Chat Model ID: %s, Rerank Model ID: %s, Judge Model ID: %s",
+		datasetID, knowledgeBaseID, chatModelID, rerankModelID, judgeModelID)
 
 	// Get tenant ID from context for multi-tenancy support
 	tenantID := types.MustTenantIDFromContext(ctx)
 	logger.Infof(ctx, "Tenant ID: %d", tenantID)
 
+	// Reject an unusable judge model before any knowledge base is created
+	if _, err := e.evaluationJudge(ctx, judgeModelID); err != nil {
+		return nil, err
+	}
+	if err := fusionConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError("invalid fusion_config: " + err.Error())
+	}
+
+	sourceKnowledgeBaseID := knowledgeBaseID
+
 	// Handle knowledge base creation if not provided
 	if knowledgeBaseID == "" {
 		logger.Info(ctx, "No knowledge base ID provided, creating new knowledge base")
@@ -174,6 +223,7 @@ func (e *EvaluationService) Evaluation(ctx context.Context,
 			Description:      "evaluation",
 			EmbeddingModelID: embeddingModelID,
 			SummaryModelID:   llmModelID,
+			FusionConfig:     fusionConfig,
 		})
 		if err != nil {
 			logger.Errorf(ctx, "Failed to create knowledge base: %v", err)
@@ -195,6 +245,7 @@ func (e *EvaluationService) Evaluation(ctx context.Context,
 			Description:      "evaluation",
 			EmbeddingModelID: kb.EmbeddingModelID,
 			SummaryModelID:   kb.SummaryModelID,
+			FusionConfig:     fusionConfig,
 		})
 		if err != nil {
 			logger.Errorf(ctx, "Failed to create knowledge base: %v", err)
@@ -206,7 +257,7 @@ func (e *EvaluationService) Evaluation(ctx context.Context,
 
 	// Set default values for optional parameters
 	if datasetID == "" {
-		datasetID = "default"
+		datasetID = types.DefaultEvaluationDatasetID
 		logger.Info(ctx, "Using default dataset")
 	}
 
@@ -259,46 +310,29 @@ func (e *EvaluationService) Evaluation(ctx context.Context,
 	// Prepare evaluation detail with all parameters
 	detail := &types.EvaluationDetail{
 		Task: &types.EvaluationTask{
-			ID:        taskID,
-			TenantID:  tenantID,
-			DatasetID: datasetID,
-			Status:    types.EvaluationStatuePending,
-			StartTime: time.Now(),
-		},
-		Params: &types.ChatManage{
-			PipelineRequest: types.PipelineRequest{
-				VectorThreshold:  e.config.Conversation.VectorThreshold,
-				KeywordThreshold: e.config.Conversation.KeywordThreshold,
-				EmbeddingTopK:    e.config.Conversation.EmbeddingTopK,
-				MaxRounds:        e.config.Conversation.MaxRounds,
-				RerankModelID:    rerankModelID,
-				RerankTopK:       e.config.Conversation.RerankTopK,
-				RerankThreshold:  e.config.Conversation.RerankThreshold,
-				ChatModelID:      chatModelID,
-				SummaryConfig: types.SummaryConfig{
-					MaxTokens:           e.config.Conversation.Summary.MaxTokens,
-					RepeatPenalty:       e.config.Conversation.Summary.RepeatPenalty,
-					TopK:                e.config.Conversation.Summary.TopK,
-					TopP:                e.config.Conversation.Summary.TopP,
-					Prompt:              e.config.Conversation.Summary.Prompt,
-					ContextTemplate:     e.config.Conversation.Summary.ContextTemplate,
-					FrequencyPenalty:    e.config.Conversation.Summary.FrequencyPenalty,
-					PresencePenalty:     e.config.Conversation.Summary.PresencePenalty,
-					NoMatchPrefix:       e.config.Conversation.Summary.NoMatchPrefix,
-					Temperature:         e.config.Conversation.Summary.Temperature,
-					Seed:                e.config.Conversation.Summary.Seed,
-					MaxCompletionTokens: e.config.Conversation.Summary.MaxCompletionTokens,
-				},
-				FallbackResponse:    e.config.Conversation.FallbackResponse,
-				RewritePromptSystem: e.config.Conversation.RewritePromptSystem,
-				RewritePromptUser:   e.config.Conversation.RewritePromptUser,
-			},
+			ID:              taskID,
+			TenantID:        tenantID,
+			DatasetID:       datasetID,
+			KnowledgeBaseID: sourceKnowledgeBaseID,
+			ChatModelID:     chatModelID,
+			RerankModelID:   rerankModelID,
+			JudgeModelID:    judgeModelID,
+			FusionConfig:    fusionConfig,
+			Status:          types.EvaluationStatuePending,
+			StartTime:       time.Now(),
 		},
+		Params: e.evaluationParams(chatModelID, rerankModelID),
 	}
 
-	// Store evaluation task in memory storage
+	// Persist evaluation task so it can be read from any replica
 	logger.Info(ctx, "Registering evaluation task")
-	e.evaluationMemoryStorage.register(detail)
+	if err := e.repo.CreateTask(ctx, detail.Task); err != nil {
+		logger.Errorf(ctx, "Failed to create evaluation task: %v", err)
+		return nil, err
+	}
+
+	// The background run owns its own copy; the returned detail is a snapshot.
+	task := *detail.Task
 
 	// Start evaluation in background goroutine
 	logger.Info(ctx, "Starting evaluation in background")
@@ -308,26 +342,68 @@ func (e *EvaluationService) Evaluation(ctx context.Context,
 		logger.Infof(newCtx, "Background evaluation started for task ID: %s", taskID)
 
 		// Update task status to running
-		detail.Task.Status = types.EvaluationStatueRunning
+		task.Status = types.EvaluationStatueRunning
+		e.updateTask(newCtx, &task)
 		logger.Info(newCtx, "Evaluation task status set to running")
 
 		// Execute actual evaluation
-		if err := e.EvalDataset(newCtx, detail, knowledgeBaseID); err != nil {
-			detail.Task.Status = types.EvaluationStatueFailed
-			detail.Task.ErrMsg = err.Error()
+		err := e.EvalDataset(newCtx, &types.EvaluationDetail{Task: &task, Params: detail.Params}, knowledgeBaseID)
+		endTime := time.Now()
+		task.EndTime = &endTime
+		if err != nil {
+			task.Status = types.EvaluationStatueFailed
+			task.ErrMsg = err.Error()
+			e.updateTask(newCtx, &task)
+			publishEvaluationCompleted(newCtx, &task)
 			logger.Errorf(newCtx, "Evaluation task failed: %v, task ID: %s", err, taskID)
 			return
 		}
 
 		// Mark task as completed successfully
 		logger.Infof(newCtx, "Evaluation task completed successfully, task ID: %s", taskID)
-		detail.Task.Status = types.EvaluationStatueSuccess
+		task.Status = types.EvaluationStatueSuccess
+		e.updateTask(newCtx, &task)
+		publishEvaluationCompleted(newCtx, &task)
 	}()
 
 	logger.Infof(ctx, "Evaluation task created successfully, task ID: %s", taskID)
 	return detail, nil
 }
 
+// publishEvaluationCompleted announces the end of an evaluation run
+func publishEvaluationCompleted(ctx context.Context, task *types.EvaluationTask) {
+	data := event.EvaluationCompletedData{
+		TenantID:        task.TenantID,
+		TaskID:          task.ID,
+		DatasetID:       task.DatasetID,
+		KnowledgeBaseID: task.KnowledgeBaseID,
+		Status:          "success",
+		ErrorMessage:    task.ErrMsg,
+		Total:           task.Total,
+		Finished:        task.Finished,
+	}
+	if task.Status == types.EvaluationStatueFailed {
+		data.Status = "failed"
+	}
+	if task.Metric != nil {
+		data.Metric = task.Metric
+	}
+	publishWorkspaceEvent(ctx, event.EventEvaluationCompleted, data)
+}
+
+// evaluationJudge builds the LLM judge of a run, or nil when no judge model is set
+func (e *EvaluationService) evaluationJudge(ctx context.Context, judgeModelID string) (interfaces.EvaluationJudge, error) {
+	if judgeModelID == "" {
+		return nil, nil
+	}
+	model, err := e.modelService.GetChatModel(ctx, judgeModelID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get judge model %s: %v", judgeModelID, err)
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("judge model %s is not available: %v", judgeModelID, err))
+	}
+	return metric.NewLLMJudge(model), nil
+}
+
 // EvalDataset performs the actual evaluation of a dataset
 // Processes each QA pair in parallel and records metrics
 func (e *EvaluationService) EvalDataset(ctx context.Context, detail *types.EvaluationDetail, knowledgeBaseID string) error {
@@ -343,10 +419,9 @@ func (e *EvaluationService) EvalDataset(ctx context.Context, detail *types.Evalu
 	logger.Infof(ctx, "Dataset retrieved successfully with %d QA pairs", len(dataset))
 
 	// Update total QA pairs count in task details
-	e.evaluationMemoryStorage.update(detail.Task.ID, func(params *types.EvaluationDetail) {
-		params.Task.Total = len(dataset)
-		logger.Infof(ctx, "Updated task total to %d QA pairs", params.Task.Total)
-	})
+	detail.Task.Total = len(dataset)
+	e.updateTask(ctx, detail.Task)
+	logger.Infof(ctx, "Updated task total to %d QA pairs", detail.Task.Total)
 
 	// Extract and organize passages from dataset
 	passages := getPassageList(dataset)
@@ -381,7 +456,11 @@ func (e *EvaluationService) EvalDataset(ctx context.Context, detail *types.Evalu
 	var finished int
 	var mu sync.Mutex
 	var g errgroup.Group
-	metricHook := NewHookMetric(len(dataset))
+	judge, err := e.evaluationJudge(ctx, detail.Task.JudgeModelID)
+	if err != nil {
+		return err
+	}
+	metricHook := NewHookMetric(len(dataset), judge)
 
 	// Set worker limit based on available CPUs
 	g.SetLimit(max(runtime.GOMAXPROCS(0)-1, 1))
@@ -422,18 +501,27 @@ func (e *EvaluationService) EvalDataset(ctx context.Context, detail *types.Evalu
 			metricHook.recordSearchResult(i, chatManage.SearchResult)
 			metricHook.recordRerankResult(i, chatManage.RerankResult)
 			metricHook.recordChatResponse(i, chatManage.ChatResponse)
-			metricHook.recordFinish(i)
+			metricHook.recordFinish(ctx, i)
+
+			// Persist the per-question result
+			if result := metricHook.questionResult(i); result != nil {
+				result.TaskID = detail.Task.ID
+				result.TenantID = detail.Task.TenantID
+				if err := e.repo.CreateQuestionResult(ctx, result); err != nil {
+					logger.Errorf(ctx, "Failed to store result of QA pair %d: %v", i, err)
+				}
+			}
 
-			// Update progress metrics
+			// Update progress metrics. The snapshot is persisted outside the
+			// lock so a slow write does not hold up the other workers.
 			mu.Lock()
 			finished += 1
-			metricResult := metricHook.MetricResult()
+			detail.Task.Metric = metricHook.MetricResult()
+			detail.Task.Finished = finished
+			progress := *detail.Task
 			mu.Unlock()
-			e.evaluationMemoryStorage.update(detail.Task.ID, func(params *types.EvaluationDetail) {
-				params.Metric = metricResult
-				params.Task.Finished = finished
-				logger.Infof(ctx, "Updated task progress: %d/%d completed", finished, params.Task.Total)
-			})
+			e.updateTask(ctx, &progress)
+			logger.Infof(ctx, "Updated task progress: %d/%d completed", progress.Finished, progress.Total)
 			return nil
 		})
 	}
@@ -446,10 +534,9 @@ func (e *EvaluationService) EvalDataset(ctx context.Context, detail *types.Evalu
 	}
 
 	// Final update of evaluation metrics
-	e.evaluationMemoryStorage.update(detail.Task.ID, func(params *types.EvaluationDetail) {
-		params.Metric = metricHook.MetricResult()
-		params.Task.Finished = finished
-	})
+	detail.Task.Metric = metricHook.MetricResult()
+	detail.Task.Finished = finished
+	detail.Metric = detail.Task.Metric
 
 	logger.Infof(ctx, "Dataset evaluation completed successfully, task ID: %s", detail.Task.ID)
 	return nil
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// CompareEvaluations diffs two finished runs of the same dataset per question
+// and in aggregate, and applies the optional regression gate.
+func (e *EvaluationService) CompareEvaluations(ctx context.Context,
+	baseTaskID, targetTaskID string, gate *types.EvaluationGate,
+) (*types.EvaluationComparison, error) {
+	if gate != nil {
+		for _, name := range gate.Metrics {
+			if !slices.Contains(types.EvaluationMetricNames, name) {
+				return nil, werrors.NewBadRequestError(fmt.Sprintf("unknown metric: %s", name))
+			}
+		}
+		if gate.MaxDrop < 0 {
+			return nil, werrors.NewBadRequestError("max_drop must not be negative")
+		}
+	}
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	base, baseResults, err := e.finishedRun(ctx, tenantID, baseTaskID)
+	if err != nil {
+		return nil, err
+	}
+	target, targetResults, err := e.finishedRun(ctx, tenantID, targetTaskID)
+	if err != nil {
+		return nil, err
+	}
+	if base.DatasetID != target.DatasetID {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf(
+			"runs used different datasets (%s vs %s)", base.DatasetID, target.DatasetID))
+	}
+
+	comparison := compareEvaluationRuns(base, target, baseResults, targetResults, gate)
+	logger.Infof(ctx, "Compared evaluation %s against baseline %s, passed: %v, regressed questions: %d",
+		targetTaskID, baseTaskID, comparison.Passed, comparison.RegressedQuestions)
+	return comparison, nil
+}
+
+// finishedRun loads a successful task with its per-question results
+func (e *EvaluationService) finishedRun(ctx context.Context, tenantID uint64, taskID string) (
+	*types.EvaluationTask, []*types.EvaluationQuestionResult, error,
+) {
+	task, err := e.repo.GetTask(ctx, tenantID, taskID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEvaluationTaskNotFound) {
+			return nil, nil, werrors.NewNotFoundError(fmt.Sprintf("evaluation task %s not found", taskID))
+		}
+		return nil, nil, err
+	}
+	if task.Status != types.EvaluationStatueSuccess || task.Metric == nil {
+		return nil, nil, werrors.NewBadRequestError(fmt.Sprintf("evaluation task %s has not completed", taskID))
+	}
+	results, err := e.repo.ListQuestionResults(ctx, tenantID, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, results, nil
+}
+
+// compareEvaluationRuns builds the comparison; questions are matched by QID
+// and listed in the baseline's order.
+func compareEvaluationRuns(
+	base, target *types.EvaluationTask,
+	baseResults, targetResults []*types.EvaluationQuestionResult,
+	gate *types.EvaluationGate,
+) *types.EvaluationComparison {
+	comparison := &types.EvaluationComparison{
+		BaseTaskID:   base.ID,
+		TargetTaskID: target.ID,
+		DatasetID:    base.DatasetID,
+		Gate:         gate,
+		Aggregate:    metricDeltas(base.Metric, target.Metric, gate),
+		Questions:    make([]types.QuestionComparison, 0, len(baseResults)),
+		Passed:       true,
+	}
+	for _, d := range comparison.Aggregate {
+		if d.Regressed {
+			comparison.Passed = false
+		}
+	}
+
+	targetByQID := make(map[int]*types.EvaluationQuestionResult, len(targetResults))
+	for _, r := range targetResults {
+		targetByQID[r.QID] = r
+	}
+	matched := 0
+	for _, b := range baseResults {
+		t, ok := targetByQID[b.QID]
+		if !ok {
+			continue
+		}
+		matched++
+		q := types.QuestionComparison{
+			QID:             b.QID,
+			Question:        b.Question,
+			BaseGenerated:   b.Generated,
+			TargetGenerated: t.Generated,
+			Deltas:          metricDeltas(b.Metric, t.Metric, gate),
+		}
+		for _, d := range q.Deltas {
+			if d.Regressed {
+				q.Regressed = true
+				comparison.RegressedQuestions++
+				break
+			}
+		}
+		comparison.Questions = append(comparison.Questions, q)
+	}
+	comparison.UnmatchedQuestions = len(baseResults) + len(targetResults) - 2*matched
+	return comparison
+}
+
+// metricDeltas compares every metric either run reports; gated metrics are
+// flagged when they drop by more than the gate allows.
+func metricDeltas(base, target *types.MetricResult, gate *types.EvaluationGate) []types.MetricDelta {
+	deltas := make([]types.MetricDelta, 0, len(types.EvaluationMetricNames))
+	for _, name := range types.EvaluationMetricNames {
+		b, baseOK := base.MetricValue(name)
+		t, targetOK := target.MetricValue(name)
+		if !baseOK && !targetOK {
+			// Judge metrics are absent from runs without a judge model.
+			continue
+		}
+		d := types.MetricDelta{Metric: name, Base: b, Target: t, Delta: t - b}
+		if gate != nil && slices.Contains(gate.Metrics, name) {
+			// A tiny epsilon keeps float noise from tripping a zero-tolerance gate.
+			d.Regressed = b-t > gate.MaxDrop+1e-9
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
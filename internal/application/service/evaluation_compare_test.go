@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func mrrResult(mrr, rougeL float64) *types.MetricResult {
+	return &types.MetricResult{
+		RetrievalMetrics:  types.RetrievalMetrics{MRR: mrr},
+		GenerationMetrics: types.GenerationMetrics{ROUGEL: rougeL},
+	}
+}
+
+func findDelta(t *testing.T, deltas []types.MetricDelta, name string) types.MetricDelta {
+	t.Helper()
+	for _, d := range deltas {
+		if d.Metric == name {
+			return d
+		}
+	}
+	t.Fatalf("metric %s missing", name)
+	return types.MetricDelta{}
+}
+
+func TestCompareEvaluationRunsGate(t *testing.T) {
+	base := &types.EvaluationTask{ID: "base", DatasetID: "ds", Metric: mrrResult(0.80, 0.50)}
+	target := &types.EvaluationTask{ID: "target", DatasetID: "ds", Metric: mrrResult(0.75, 0.40)}
+	baseResults := []*types.EvaluationQuestionResult{
+		{QID: 1, Question: "q1", Metric: mrrResult(1, 0.5)},
+		{QID: 2, Question: "q2", Metric: mrrResult(0.5, 0.5)},
+		{QID: 3, Question: "only in base", Metric: mrrResult(1, 1)},
+	}
+	targetResults := []*types.EvaluationQuestionResult{
+		{QID: 2, Metric: mrrResult(0.5, 0.1)},
+		{QID: 1, Metric: mrrResult(0.5, 0.5)},
+	}
+
+	// Only the gated metric decides pass/fail; rougel drops more but is not gated.
+	cmp := compareEvaluationRuns(base, target, baseResults, targetResults,
+		&types.EvaluationGate{Metrics: []string{types.MetricMRR}, MaxDrop: 0.1})
+	require.True(t, cmp.Passed)
+	require.InDelta(t, -0.05, findDelta(t, cmp.Aggregate, types.MetricMRR).Delta, 1e-9)
+	require.False(t, findDelta(t, cmp.Aggregate, types.MetricROUGEL).Regressed)
+	require.Len(t, cmp.Questions, 2)
+	require.Equal(t, 1, cmp.Questions[0].QID)
+	require.True(t, cmp.Questions[0].Regressed)
+	require.False(t, cmp.Questions[1].Regressed)
+	require.Equal(t, 1, cmp.RegressedQuestions)
+	require.Equal(t, 1, cmp.UnmatchedQuestions)
+
+	cmp = compareEvaluationRuns(base, target, baseResults, targetResults,
+		&types.EvaluationGate{Metrics: []string{types.MetricMRR}, MaxDrop: 0.01})
+	require.False(t, cmp.Passed)
+	require.True(t, findDelta(t, cmp.Aggregate, types.MetricMRR).Regressed)
+}
+
+func TestCompareEvaluationRunsWithoutGatePasses(t *testing.T) {
+	base := &types.EvaluationTask{ID: "base", Metric: mrrResult(0.9, 0.9)}
+	target := &types.EvaluationTask{ID: "target", Metric: mrrResult(0.1, 0.1)}
+	cmp := compareEvaluationRuns(base, target, nil, nil, nil)
+	require.True(t, cmp.Passed)
+	// Judge metrics are absent from both runs and therefore not compared
+	require.Len(t, cmp.Aggregate, len(types.EvaluationMetricNames)-4)
+	require.Empty(t, cmp.Questions)
+}
+
+func TestCompareEvaluationRunsGatesJudgeMetrics(t *testing.T) {
+	base := mrrResult(0.5, 0.5)
+	base.JudgeMetrics = &types.JudgeMetrics{Faithfulness: 0.9, AnswerRelevance: 0.8}
+	target := mrrResult(0.5, 0.5)
+	target.JudgeMetrics = &types.JudgeMetrics{Faithfulness: 0.6, AnswerRelevance: 0.8}
+	cmp := compareEvaluationRuns(
+		&types.EvaluationTask{ID: "base", Metric: base},
+		&types.EvaluationTask{ID: "target", Metric: target}, nil, nil,
+		&types.EvaluationGate{Metrics: []string{types.MetricFaithfulness}, MaxDrop: 0.1})
+	require.False(t, cmp.Passed)
+	require.Len(t, cmp.Aggregate, len(types.EvaluationMetricNames))
+	require.True(t, findDelta(t, cmp.Aggregate, types.MetricFaithfulness).Regressed)
+	require.False(t, findDelta(t, cmp.Aggregate, types.MetricAnswerRelevance).Regressed)
+}
+
+func TestCompareEvaluationRunsZeroToleranceIgnoresFloatNoise(t *testing.T) {
+	base := &types.EvaluationTask{Metric: mrrResult(0.3, 0)}
+	target := &types.EvaluationTask{Metric: mrrResult(0.1+0.2, 0)}
+	cmp := compareEvaluationRuns(base, target, nil, nil,
+		&types.EvaluationGate{Metrics: []string{types.MetricMRR}})
+	require.True(t, cmp.Passed)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// TuneFusionWeights compares finished evaluation runs of one dataset that
+// searched with different score fusion settings, and stores the weights of
+// the best run on the knowledge base as its learned fusion. The knowledge
+// base's other fusion settings are kept; its strategy becomes learned.
+func (e *EvaluationService) TuneFusionWeights(ctx context.Context,
+	req *types.FusionTuneRequest,
+) (*types.FusionConfig, error) {
+	if req.KnowledgeBaseID == "" {
+		return nil, werrors.NewBadRequestError("knowledge_base_id is required")
+	}
+	if len(req.TaskIDs) == 0 {
+		return nil, werrors.NewBadRequestError("task_ids is required")
+	}
+	metricName := req.Metric
+	if metricName == "" {
+		metricName = types.MetricNDCG10
+	}
+	if !slices.Contains(types.EvaluationMetricNames, metricName) {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("unknown metric: %s", metricName))
+	}
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	runs := make([]*types.EvaluationTask, 0, len(req.TaskIDs))
+	for _, taskID := range req.TaskIDs {
+		task, _, err := e.finishedRun(ctx, tenantID, taskID)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, task)
+	}
+	learned, err := pickFusionWeights(runs, req.KnowledgeBaseID, metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	kb, err := e.knowledgeBaseService.GetKnowledgeBaseByID(ctx, req.KnowledgeBaseID)
+	if errors.Is(err, repository.ErrKnowledgeBaseNotFound) || (err == nil && kb.TenantID != tenantID) {
+		return nil, werrors.NewNotFoundError("knowledge base not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	fusion := types.FusionConfig{}
+	if kb.FusionConfig != nil {
+		fusion = *kb.FusionConfig
+	}
+	fusion.FusionStrategy = types.FusionStrategyLearned
+	fusion.LearnedWeights = learned
+	if _, err := e.knowledgeBaseService.UpdateKnowledgeBase(ctx, kb.ID, kb.Name, kb.Description,
+		&types.KnowledgeBaseConfig{
+			ChunkingConfig:        kb.ChunkingConfig,
+			ImageProcessingConfig: kb.ImageProcessingConfig,
+			FusionConfig:          &fusion,
+		}); err != nil {
+		return nil, err
+	}
+
+	logger.Infof(ctx, "Learned fusion weights for knowledge base %s from %d runs: vector=%.3f keyword=%.3f (%s=%.4f, task %s)",
+		kb.ID, learned.RunCount, learned.VectorWeight, learned.KeywordWeight,
+		metricName, learned.MetricValue, learned.SourceTaskID)
+	return &fusion, nil
+}
+
+// pickFusionWeights returns the effective score fusion weights of the run
+// scoring highest on metricName. Every run must have searched kbID, since
+// weights tuned on another corpus say nothing about this one, and cover the
+// same dataset; runs that did not search with a score-based fusion have no
+// weights to learn from and are skipped. Ties go to the run listed first.
+func pickFusionWeights(runs []*types.EvaluationTask, kbID, metricName string) (*types.LearnedFusionWeights, error) {
+	var best *types.LearnedFusionWeights
+	for _, run := range runs {
+		if run.KnowledgeBaseID != kbID {
+			return nil, werrors.NewBadRequestError(fmt.Sprintf(
+				"run %s searched knowledge base %q, not %s", run.ID, run.KnowledgeBaseID, kbID))
+		}
+		if run.DatasetID != runs[0].DatasetID {
+			return nil, werrors.NewBadRequestError(fmt.Sprintf(
+				"runs used different datasets (%s vs %s)", runs[0].DatasetID, run.DatasetID))
+		}
+		if !run.FusionConfig.GetEffectiveFusionStrategy().IsScoreBased() {
+			continue
+		}
+		value, ok := run.Metric.MetricValue(metricName)
+		if !ok {
+			continue
+		}
+		if best != nil && value <= best.MetricValue {
+			continue
+		}
+		vectorWeight, keywordWeight := run.FusionConfig.GetEffectiveScoreWeights()
+		best = &types.LearnedFusionWeights{
+			VectorWeight:  vectorWeight,
+			KeywordWeight: keywordWeight,
+			Normalization: run.FusionConfig.GetEffectiveNormalization(),
+			SourceTaskID:  run.ID,
+			Metric:        metricName,
+			MetricValue:   value,
+		}
+	}
+	if best == nil {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf(
+			"no run used a score-based fusion_config and reported %s", metricName))
+	}
+	if best.VectorWeight+best.KeywordWeight == 0 {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("best run %s has zero fusion weights", best.SourceTaskID))
+	}
+	best.RunCount = len(runs)
+	best.TunedAt = time.Now()
+	return best, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickFusionWeights(t *testing.T) {
+	ndcg := func(v float64) *types.MetricResult {
+		return &types.MetricResult{RetrievalMetrics: types.RetrievalMetrics{NDCG10: v}}
+	}
+	runs := []*types.EvaluationTask{
+		{ID: "rrf", KnowledgeBaseID: "kb", DatasetID: "ds", Metric: ndcg(0.9)}, // tenant default, nothing to learn
+		{ID: "vector-heavy", KnowledgeBaseID: "kb", DatasetID: "ds", Metric: ndcg(0.6), FusionConfig: &types.FusionConfig{
+			FusionStrategy: types.FusionStrategyWeighted, ScoreVectorWeight: 0.8, ScoreKeywordWeight: 0.2,
+		}},
+		{ID: "keyword-heavy", KnowledgeBaseID: "kb", DatasetID: "ds", Metric: ndcg(0.7), FusionConfig: &types.FusionConfig{
+			FusionStrategy: types.FusionStrategyDBSF, ScoreVectorWeight: 0.3, ScoreKeywordWeight: 0.7,
+		}},
+	}
+
+	learned, err := pickFusionWeights(runs, "kb", types.MetricNDCG10)
+	require.NoError(t, err)
+	require.Equal(t, "keyword-heavy", learned.SourceTaskID)
+	require.Equal(t, 0.3, learned.VectorWeight)
+	require.Equal(t, 0.7, learned.KeywordWeight)
+	require.Equal(t, types.FusionStrategyDBSF, learned.Normalization)
+	require.Equal(t, 3, learned.RunCount)
+
+	_, err = pickFusionWeights(runs[:1], "kb", types.MetricNDCG10)
+	require.Error(t, err, "a set without score-based runs has nothing to learn")
+
+	_, err = pickFusionWeights(runs, "other-kb", types.MetricNDCG10)
+	require.Error(t, err, "weights of runs over another knowledge base must not be applied")
+	runs[1].KnowledgeBaseID = ""
+	_, err = pickFusionWeights(runs, "kb", types.MetricNDCG10)
+	require.Error(t, err, "a run over the default knowledge bases is a mismatch too")
+	runs[1].KnowledgeBaseID = "kb"
+
+	runs[2].DatasetID = "other"
+	_, err = pickFusionWeights(runs, "kb", types.MetricNDCG10)
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	chatpipeline "github.com/Tencent/WeKnora/internal/application/service/chat_pipeline"
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	"github.com/Tencent/WeKnora/internal/config"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/chat"
 	"github.com/Tencent/WeKnora/internal/models/embedding"
@@ -96,8 +97,8 @@ func NewChunkExtractTask(
 	attempt int,
 	chunkIndex int,
 ) (bool, error) {
-	if strings.ToLower(os.Getenv("NEO4J_ENABLE")) != "true" {
-		logger.Warn(ctx, "NEO4J is not enabled, skip chunk extract task")
+	if !types.GraphStoreEnabled() {
+		logger.Warn(ctx, "graph store is not enabled, skip chunk extract task")
 		return false, nil
 	}
 	taskPayload := types.ExtractChunkPayload{
@@ -185,6 +186,9 @@ type ChunkExtractService struct {
 	knowledgeRepo     interfaces.KnowledgeRepository
 	chunkRepo         interfaces.ChunkRepository
 	graphEngine       interfaces.RetrieveGraphRepository
+	// communityService is told after every graph write so the knowledge
+	// base's community summaries are rebuilt once extraction settles.
+	communityService interfaces.GraphCommunityService
 	// spanTracker records this graph-extract task's subspan under the
 	// parent attempt's postprocess stage so the trace viewer shows real
 	// per-chunk graph extraction time rather than the upstream's enqueue.
@@ -199,6 +203,7 @@ func NewChunkExtractService(
 	knowledgeRepo interfaces.KnowledgeRepository,
 	chunkRepo interfaces.ChunkRepository,
 	graphEngine interfaces.RetrieveGraphRepository,
+	communityService interfaces.GraphCommunityService,
 	spanTracker SpanTracker,
 ) interfaces.TaskHandler {
 	return &ChunkExtractService{
@@ -208,6 +213,7 @@ func NewChunkExtractService(
 		knowledgeRepo:     knowledgeRepo,
 		chunkRepo:         chunkRepo,
 		graphEngine:       graphEngine,
+		communityService:  communityService,
 		spanTracker:       spanTracker,
 	}
 }
@@ -367,6 +373,9 @@ func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) error {
 	for _, node := range graph.Node {
 		node.Chunks = []string{chunk.ID}
 	}
+	for _, rel := range graph.Relation {
+		rel.Chunks = []string{chunk.ID}
+	}
 	if err = s.graphEngine.AddGraph(ctx,
 		types.NameSpace{KnowledgeBase: chunk.KnowledgeBaseID, Knowledge: chunk.KnowledgeID},
 		[]*types.GraphData{graph},
@@ -377,6 +386,11 @@ func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) error {
 	}
 	graphOut["nodes_added"] = len(graph.Node)
 	graphOut["relations_added"] = len(graph.Relation)
+	if s.communityService != nil && len(graph.Relation) > 0 {
+		if err := s.communityService.ScheduleRebuild(ctx, p.TenantID, chunk.KnowledgeBaseID); err != nil {
+			logger.Warnf(ctx, "failed to schedule graph community rebuild: %v", err)
+		}
+	}
 	// Capture a couple of sample nodes/relations so the trace viewer can
 	// answer "what did the LLM actually extract?" without round-tripping
 	// to the graph store. Cap to two each — anything more bloats span
@@ -485,7 +499,8 @@ func (s *DataTableSummaryService) Handle(ctx context.Context, t *asynq.Task) err
 	}
 
 	// 4. 索引到向量数据库
-	if err := s.indexToVectorDB(ctx, chunks, resources.retrieveEngine, resources.embeddingModel); err != nil {
+	if err := s.indexToVectorDB(ctx, chunks, resources.knowledge, resources.knowledgeBase,
+		resources.retrieveEngine, resources.embeddingModel); err != nil {
 		s.cleanupOnFailure(ctx, resources, chunks, err)
 		return err
 	}
@@ -559,8 +574,9 @@ func (s *DataTableSummaryService) prepareResources(ctx context.Context, payload
 
 	// Resolve the engine via the factory using the KB's VectorStore binding
 	// (nil -> tenant effective engines fallback; verified tenant ownership otherwise).
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, payload.TenantID, vectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.knowledgeBaseService, payload.TenantID, vectorStoreID,
+		knowledge.KnowledgeBaseID)
 	if err != nil {
 		logger.Errorf(ctx, "failed to get retrieve engine: %v", err)
 		return nil, err
@@ -726,11 +742,14 @@ func (s *DataTableSummaryService) buildChunks(resources *extractionResources, ta
 func (s *DataTableSummaryService) indexToVectorDB(
 	ctx context.Context,
 	chunks []*types.Chunk,
+	knowledge *types.Knowledge,
+	kb *types.KnowledgeBase,
 	engine *retriever.CompositeRetrieveEngine,
 	embedder embedding.Embedder,
 ) error {
 	// 构建索引信息列表
 	indexInfoList := make([]*types.IndexInfo, 0, len(chunks))
+	indexMetadata := knowledge.IndexMetadata()
 	for _, chunk := range chunks {
 		indexInfoList = append(indexInfoList, &types.IndexInfo{
 			Content:         chunk.Content,
@@ -738,8 +757,9 @@ func (s *DataTableSummaryService) indexToVectorDB(
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
-			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 			IsEnabled:       true,
+			Metadata:        indexMetadata,
 		})
 	}
 
@@ -780,6 +800,7 @@ func (s *DataTableSummaryService) cleanupOnFailure(ctx context.Context, resource
 		logger.Errorf(ctx, "Failed to update knowledge status: %v", err)
 	} else {
 		logger.Infof(ctx, "Updated knowledge %s status to failed", resources.knowledge.ID)
+		publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, resources.knowledge)
 	}
 
 	// 提取chunk IDs
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 )
@@ -66,6 +65,9 @@ func (s *knowledgeService) syncFAQChunkStatusBatch(
 	if len(pairs) == 0 {
 		return nil
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, dstKB.ID); err != nil {
+		return err
+	}
 	tenantID := types.MustTenantIDFromContext(ctx)
 	enabledUpdates := make(map[string]bool)
 	recommendedUpdates := make(map[string]bool)
@@ -132,8 +134,8 @@ func (s *knowledgeService) syncFAQChunkStatusBatch(
 	if len(enabledUpdates) == 0 && len(tagUpdates) == 0 && len(recommendedUpdates) == 0 {
 		return nil
 	}
-	engine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantID, dstKB.VectorStoreID)
+	engine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, dstKB.VectorStoreID, dstKB.ID)
 	if err != nil {
 		return err
 	}
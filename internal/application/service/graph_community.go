@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/common"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/searchutil"
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// communityBuildDelay debounces rebuilds: graph extraction runs once
+	// per chunk, so a document upload triggers a burst of schedules that
+	// should end in one rebuild after the burst settles.
+	communityBuildDelay = 2 * time.Minute
+
+	// communityMaxSummaries caps how many communities one rebuild
+	// summarizes (one LLM call each); smaller ones beyond the cap are
+	// dropped.
+	communityMaxSummaries = 50
+	// communitySummaryConcurrency bounds parallel summary calls.
+	communitySummaryConcurrency = 4
+	// communityPromptEntities / communityPromptRelations bound how much of a
+	// large community is shown to the model.
+	communityPromptEntities  = 30
+	communityPromptRelations = 60
+	// communityMaxChunkIDs bounds the evidence chunks stored per community.
+	communityMaxChunkIDs = 100
+	// communityChunkLookupBatch bounds the chunk IDs resolved per query when
+	// mapping evidence chunks to their documents.
+	communityChunkLookupBatch = 500
+
+	// communityQueryWeight is the share of a community's search score that
+	// comes from query term overlap; the rest comes from its rank, so broad
+	// questions that share no terms with any summary still get the most
+	// important communities.
+	communityQueryWeight = 0.7
+)
+
+const communitySummaryPrompt = `You are analysing one cluster of closely related entities taken from a knowledge graph that was extracted from a document collection.
+
+Entities (most connected first):
+%s
+
+Relations:
+%s
+
+Write a report about this cluster:
+- "title": a short name for the theme that ties the entities together (at most 10 words)
+- "summary": one or two paragraphs (at most 200 words) describing what the cluster is about, the key entities and how they relate, and anything notable such as causes, conflicts or dependencies
+
+Only use the information above. Write in the same language as the entity names.
+Return a JSON object: {"title": "...", "summary": "..."}`
+
+// communityReport is the JSON shape the summary prompt asks for.
+type communityReport struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// graphCommunityService implements interfaces.GraphCommunityService
+type graphCommunityService struct {
+	repo         interfaces.GraphCommunityRepository
+	kbRepo       interfaces.KnowledgeBaseRepository
+	chunkRepo    interfaces.ChunkRepository
+	graphEngine  interfaces.RetrieveGraphRepository
+	modelService interfaces.ModelService
+	task         interfaces.TaskEnqueuer
+
+	// scheduled remembers when a rebuild was last enqueued per knowledge
+	// base. asynq.TaskID already coalesces a burst in Redis mode; this also
+	// covers Lite mode, whose executor ignores TaskID.
+	mu        sync.Mutex
+	scheduled map[string]time.Time
+}
+
+// NewGraphCommunityService creates a new graph community service
+func NewGraphCommunityService(
+	repo interfaces.GraphCommunityRepository,
+	kbRepo interfaces.KnowledgeBaseRepository,
+	chunkRepo interfaces.ChunkRepository,
+	graphEngine interfaces.RetrieveGraphRepository,
+	modelService interfaces.ModelService,
+	task interfaces.TaskEnqueuer,
+) interfaces.GraphCommunityService {
+	return &graphCommunityService{
+		repo:         repo,
+		kbRepo:       kbRepo,
+		chunkRepo:    chunkRepo,
+		graphEngine:  graphEngine,
+		modelService: modelService,
+		task:         task,
+		scheduled:    make(map[string]time.Time),
+	}
+}
+
+// ListCommunities returns the stored communities of a knowledge base
+func (s *graphCommunityService) ListCommunities(
+	ctx context.Context, kbID string, excludeKnowledgeIDs []string,
+) ([]*types.GraphCommunity, error) {
+	communities, err := s.repo.ListByKnowledgeBases(ctx, []string{kbID})
+	if err != nil || len(excludeKnowledgeIDs) == 0 {
+		return communities, err
+	}
+	excluded := make(map[string]bool, len(excludeKnowledgeIDs))
+	for _, id := range excludeKnowledgeIDs {
+		excluded[id] = true
+	}
+	kept := make([]*types.GraphCommunity, 0, len(communities))
+	for _, c := range communities {
+		hidden, err := s.communityCitesKnowledge(ctx, c, excluded)
+		if err != nil {
+			return nil, err
+		}
+		if !hidden {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+// ScheduleRebuild enqueues a debounced rebuild for a knowledge base
+func (s *graphCommunityService) ScheduleRebuild(ctx context.Context, tenantID uint64, kbID string) error {
+	if kbID == "" || !types.GraphStoreEnabled() {
+		return nil
+	}
+	now := time.Now()
+	s.mu.Lock()
+	if last, ok := s.scheduled[kbID]; ok && now.Sub(last) < communityBuildDelay {
+		s.mu.Unlock()
+		return nil
+	}
+	s.scheduled[kbID] = now
+	s.mu.Unlock()
+
+	payload := types.GraphCommunityBuildPayload{TenantID: tenantID, KnowledgeBaseID: kbID}
+	langfuse.InjectTracing(ctx, &payload)
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	t := asynq.NewTask(types.TypeGraphCommunityBuild, b,
+		asynq.Queue(types.QueueGraph),
+		asynq.MaxRetry(3),
+		asynq.Timeout(60*time.Minute),
+		asynq.ProcessIn(communityBuildDelay),
+		asynq.TaskID(communityBuildTaskID(kbID, now)),
+	)
+	if _, err := s.task.Enqueue(t); err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) || errors.Is(err, asynq.ErrDuplicateTask) {
+			return nil // a rebuild that has not loaded the graph yet is queued — coalesced
+		}
+		s.mu.Lock()
+		delete(s.scheduled, kbID)
+		s.mu.Unlock()
+		return fmt.Errorf("failed to enqueue community build: %w", err)
+	}
+	logger.Infof(ctx, "scheduled graph community rebuild for knowledge base %s", kbID)
+	return nil
+}
+
+// communityBuildTaskID names the rebuild task for a change made at the given
+// time. Changes within one communityBuildDelay window share a task; a task
+// only starts once its window has passed, so a change made while a rebuild
+// is running gets a task of its own instead of being dropped as a duplicate.
+func communityBuildTaskID(kbID string, at time.Time) string {
+	window := at.UnixNano() / int64(communityBuildDelay)
+	return fmt.Sprintf("graph-community-%s-%d", kbID, window)
+}
+
+// ProcessCommunityBuild re-clusters a knowledge base's graph and replaces
+// its community summaries
+func (s *graphCommunityService) ProcessCommunityBuild(ctx context.Context, t *asynq.Task) error {
+	var p types.GraphCommunityBuildPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		logger.Errorf(ctx, "failed to unmarshal community build payload: %v", err)
+		return err
+	}
+	ctx = logger.WithRequestID(ctx, uuid.New().String())
+	ctx = logger.WithField(ctx, "graph_community", p.KnowledgeBaseID)
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, p.TenantID)
+
+	kb, err := s.kbRepo.GetKnowledgeBaseByID(ctx, p.KnowledgeBaseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+			logger.Infof(ctx, "knowledge base %s is gone, skip community build", p.KnowledgeBaseID)
+			return nil
+		}
+		return err
+	}
+	return s.rebuild(ctx, kb)
+}
+
+// rebuild runs community detection on the knowledge base's graph, summarizes
+// each community and swaps the stored set
+func (s *graphCommunityService) rebuild(ctx context.Context, kb *types.KnowledgeBase) error {
+	graph, err := s.graphEngine.GetGraph(ctx, types.NameSpace{KnowledgeBase: kb.ID})
+	if err != nil {
+		logger.Errorf(ctx, "failed to load graph: %v", err)
+		return err
+	}
+	detected := detectCommunities(graph)
+	if len(detected) > communityMaxSummaries {
+		detected = detected[:communityMaxSummaries]
+	}
+	if len(detected) == 0 {
+		logger.Infof(ctx, "no graph communities found for knowledge base %s", kb.ID)
+		return s.repo.ReplaceByKnowledgeBase(ctx, kb.ID, nil)
+	}
+
+	chunkKnowledge, err := s.chunkKnowledgeIDs(ctx, detected)
+	if err != nil {
+		logger.Errorf(ctx, "failed to resolve community source documents: %v", err)
+		return err
+	}
+
+	// Without a usable summary model every community is described by its
+	// relations, which still beats leaving summaries of a graph that changed.
+	var chatModel chat.Chat
+	if kb.SummaryModelID == "" {
+		logger.Warnf(ctx, "knowledge base %s has no summary model, communities keep relation lists", kb.ID)
+	} else {
+		chatModel, err = s.modelService.GetChatModel(ctx, kb.SummaryModelID)
+		if errors.Is(err, ErrModelNotFound) {
+			logger.Warnf(ctx, "summary model %s of knowledge base %s not found, communities keep relation lists",
+				kb.SummaryModelID, kb.ID)
+		} else if err != nil {
+			logger.Errorf(ctx, "failed to get chat model: %v", err)
+			return err
+		}
+	}
+
+	communities := make([]*types.GraphCommunity, len(detected))
+	var failed int
+	var failedMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, communitySummaryConcurrency)
+	for i, dc := range detected {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report := fallbackCommunityReport(dc)
+			if chatModel != nil {
+				summary, err := summarizeCommunity(ctx, chatModel, dc)
+				if err != nil {
+					logger.Warnf(ctx, "failed to summarize graph community %d: %v", i, err)
+					failedMu.Lock()
+					failed++
+					failedMu.Unlock()
+				} else {
+					report = summary
+				}
+			}
+			knowledgeIDs := types.StringArray{}
+			for _, id := range dc.chunkIDs {
+				if kid := chunkKnowledge[id]; kid != "" && !slices.Contains(knowledgeIDs, kid) {
+					knowledgeIDs = append(knowledgeIDs, kid)
+				}
+			}
+			chunkIDs := dc.chunkIDs
+			if len(chunkIDs) > communityMaxChunkIDs {
+				chunkIDs = chunkIDs[:communityMaxChunkIDs]
+			}
+			communities[i] = &types.GraphCommunity{
+				ID:              uuid.New().String(),
+				TenantID:        kb.TenantID,
+				KnowledgeBaseID: kb.ID,
+				Title:           report.Title,
+				Summary:         report.Summary,
+				Entities:        types.StringArray(dc.entities),
+				RelationCount:   len(dc.relations),
+				ChunkIDs:        types.StringArray(chunkIDs),
+				KnowledgeIDs:    knowledgeIDs,
+				Rank:            dc.rank,
+			}
+		}()
+	}
+	wg.Wait()
+	// Keep the previous summaries rather than replacing them with bare
+	// relation lists when the model is unusable; asynq retries the task.
+	if failed == len(detected) {
+		return fmt.Errorf("all %d community summaries failed", failed)
+	}
+
+	if err := s.repo.ReplaceByKnowledgeBase(ctx, kb.ID, communities); err != nil {
+		logger.Errorf(ctx, "failed to save graph communities: %v", err)
+		return err
+	}
+	logger.Infof(ctx, "built %d graph communities for knowledge base %s (%d summaries fell back)",
+		len(communities), kb.ID, failed)
+	return nil
+}
+
+// chunkKnowledgeIDs maps every evidence chunk of the detected communities
+// to the document it belongs to. Chunks deleted since extraction are absent.
+func (s *graphCommunityService) chunkKnowledgeIDs(
+	ctx context.Context, detected []*detectedCommunity,
+) (map[string]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, dc := range detected {
+		for _, id := range dc.chunkIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	result := make(map[string]string, len(ids))
+	for batch := range slices.Chunk(ids, communityChunkLookupBatch) {
+		chunks, err := s.chunkRepo.ListChunksByIDOnly(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			result[chunk.ID] = chunk.KnowledgeID
+		}
+	}
+	return result, nil
+}
+
+// summarizeCommunity asks the model for a title and summary of a community
+func summarizeCommunity(ctx context.Context, chatModel chat.Chat, dc *detectedCommunity) (communityReport, error) {
+	entities := dc.entities
+	if len(entities) > communityPromptEntities {
+		entities = entities[:communityPromptEntities]
+	}
+	relations := dc.relations
+	if len(relations) > communityPromptRelations {
+		relations = relations[:communityPromptRelations]
+	}
+	prompt := fmt.Sprintf(communitySummaryPrompt,
+		"- "+strings.Join(entities, "\n- "), formatCommunityRelations(relations))
+
+	modelCtx := types.WithLLMCallMetadata(ctx, "graph_community_summary", "")
+	response, err := chatModel.Chat(modelCtx, []chat.Message{
+		{Role: "user", Content: prompt},
+	}, &chat.ChatOptions{Temperature: 0.2})
+	if err != nil {
+		return communityReport{}, err
+	}
+	var report communityReport
+	if err := common.ParseLLMJsonResponse(response.Content, &report); err != nil {
+		return communityReport{}, fmt.Errorf("invalid community report: %w", err)
+	}
+	report.Title = strings.TrimSpace(report.Title)
+	report.Summary = strings.TrimSpace(report.Summary)
+	if report.Summary == "" {
+		return communityReport{}, errors.New("empty community summary")
+	}
+	if report.Title == "" {
+		report.Title = fallbackCommunityReport(dc).Title
+	}
+	return report, nil
+}
+
+// fallbackCommunityReport describes a community without the model, so one
+// failed call does not drop the community from global retrieval.
+func fallbackCommunityReport(dc *detectedCommunity) communityReport {
+	title := strings.Join(dc.entities[:min(3, len(dc.entities))], ", ")
+	relations := dc.relations
+	if len(relations) > communityPromptRelations {
+		relations = relations[:communityPromptRelations]
+	}
+	return communityReport{Title: title, Summary: formatCommunityRelations(relations)}
+}
+
+func formatCommunityRelations(relations []*types.GraphRelation) string {
+	lines := make([]string, 0, len(relations))
+	for _, rel := range relations {
+		lines = append(lines, fmt.Sprintf("- %s --[%s]--> %s", rel.Node1, rel.Type, rel.Node2))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SearchCommunities ranks communities by query term overlap blended with
+// their rank
+func (s *graphCommunityService) SearchCommunities(
+	ctx context.Context, kbIDs []string, query string, limit int, excludeKnowledgeIDs []string,
+) ([]*types.GraphCommunity, error) {
+	communities, err := s.repo.ListByKnowledgeBases(ctx, kbIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(excludeKnowledgeIDs) == 0 {
+		return rankCommunities(communities, query, limit), nil
+	}
+
+	// Check ranked candidates one by one until limit remain, so the chunk
+	// lookups stay proportional to the answer rather than the whole graph.
+	excluded := make(map[string]bool, len(excludeKnowledgeIDs))
+	for _, id := range excludeKnowledgeIDs {
+		excluded[id] = true
+	}
+	var kept []*types.GraphCommunity
+	for _, c := range rankCommunities(communities, query, len(communities)) {
+		if len(kept) == limit {
+			break
+		}
+		hidden, err := s.communityCitesKnowledge(ctx, c, excluded)
+		if err != nil {
+			return nil, err
+		}
+		if !hidden {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+// communityCitesKnowledge reports whether a community was built from any of
+// the given documents.
+func (s *graphCommunityService) communityCitesKnowledge(
+	ctx context.Context, c *types.GraphCommunity, knowledgeIDs map[string]bool,
+) (bool, error) {
+	if c.KnowledgeIDs != nil {
+		for _, id := range c.KnowledgeIDs {
+			if knowledgeIDs[id] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	// Communities built before source documents were recorded only keep
+	// the first communityMaxChunkIDs chunks; a full list may be truncated,
+	// so it cannot prove the community is clear of the given documents.
+	if len(c.ChunkIDs) >= communityMaxChunkIDs {
+		return true, nil
+	}
+	if len(c.ChunkIDs) == 0 {
+		return false, nil
+	}
+	chunks, err := s.chunkRepo.ListChunksByIDOnly(ctx, c.ChunkIDs)
+	if err != nil {
+		return false, fmt.Errorf("list chunks of community %s: %w", c.ID, err)
+	}
+	for _, chunk := range chunks {
+		if knowledgeIDs[chunk.KnowledgeID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rankCommunities orders communities by the share of query terms found in
+// their title, summary and entities, blended with their rank, and keeps the
+// first limit.
+func rankCommunities(communities []*types.GraphCommunity, query string, limit int) []*types.GraphCommunity {
+	if len(communities) == 0 || limit <= 0 {
+		return nil
+	}
+	queryTerms := searchutil.TokenizeSimple(query)
+	var maxRank float64
+	for _, c := range communities {
+		maxRank = max(maxRank, c.Rank)
+	}
+	scores := make(map[string]float64, len(communities))
+	for _, c := range communities {
+		var coverage float64
+		if len(queryTerms) > 0 {
+			text := strings.ToLower(c.Title + "\n" + c.Summary + "\n" + strings.Join(c.Entities, "\n"))
+			var hits int
+			for term := range queryTerms {
+				if strings.Contains(text, term) {
+					hits++
+				}
+			}
+			coverage = float64(hits) / float64(len(queryTerms))
+		}
+		var rank float64
+		if maxRank > 0 {
+			rank = c.Rank / maxRank
+		}
+		scores[c.ID] = communityQueryWeight*coverage + (1-communityQueryWeight)*rank
+	}
+	ranked := append([]*types.GraphCommunity(nil), communities...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].ID] > scores[ranked[j].ID]
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const (
+	// louvainMaxLevels bounds how many times the graph is coarsened. Real
+	// entity graphs converge in three or four levels.
+	louvainMaxLevels = 16
+	// louvainMaxPasses bounds node-moving sweeps within one level.
+	louvainMaxPasses = 32
+)
+
+// detectedCommunity is one cluster found by detectCommunities, before it is
+// summarized.
+type detectedCommunity struct {
+	// entities are the member names, most connected (inside the community)
+	// first.
+	entities []string
+	// relations are the merged relations whose endpoints are both members.
+	relations []*types.GraphRelation
+	// weight is the relation weight inside the community.
+	weight float64
+	// rank is weight as a share of the whole graph's relation weight.
+	rank float64
+	// chunkIDs are the evidence chunks of relations, in first-seen order.
+	chunkIDs []string
+}
+
+// louvainGraph is an undirected weighted graph in the form the Louvain
+// method works on. adj is symmetric; a self loop adj[i][i] holds twice the
+// weight folded into node i by aggregation, so degree[i] is always the
+// plain row sum and total is twice the graph's edge weight.
+type louvainGraph struct {
+	adj    []map[int]float64
+	degree []float64
+	total  float64
+}
+
+// detectCommunities partitions the graph with the Louvain modularity method.
+// Relations are treated as undirected and weighted by how many chunks
+// evidence them, so a pair mentioned together throughout a corpus binds
+// tighter than a one-off mention. Communities with fewer than two entities
+// are dropped, and the rest are returned highest rank first. The result is
+// deterministic for a given graph.
+func detectCommunities(graph *types.GraphData) []*detectedCommunity {
+	if graph == nil || len(graph.Relation) == 0 {
+		return nil
+	}
+
+	var names []string
+	index := make(map[string]int)
+	nodeID := func(name string) int {
+		if id, ok := index[name]; ok {
+			return id
+		}
+		index[name] = len(names)
+		names = append(names, name)
+		return index[name]
+	}
+	// Sort the relations first so node numbering, and with it the
+	// partition, does not depend on store iteration order.
+	relations := make([]*types.GraphRelation, 0, len(graph.Relation))
+	for _, rel := range graph.Relation {
+		if rel != nil && rel.Node1 != "" && rel.Node2 != "" && rel.Node1 != rel.Node2 {
+			relations = append(relations, rel)
+		}
+	}
+	if len(relations) == 0 {
+		return nil
+	}
+	sort.SliceStable(relations, func(i, j int) bool {
+		a, b := relations[i], relations[j]
+		if a.Node1 != b.Node1 {
+			return a.Node1 < b.Node1
+		}
+		if a.Node2 != b.Node2 {
+			return a.Node2 < b.Node2
+		}
+		return a.Type < b.Type
+	})
+	for _, rel := range relations {
+		nodeID(rel.Node1)
+		nodeID(rel.Node2)
+	}
+
+	g := &louvainGraph{
+		adj:    make([]map[int]float64, len(names)),
+		degree: make([]float64, len(names)),
+	}
+	for i := range g.adj {
+		g.adj[i] = make(map[int]float64)
+	}
+	for _, rel := range relations {
+		i, j, w := index[rel.Node1], index[rel.Node2], relationWeight(rel)
+		g.adj[i][j] += w
+		g.adj[j][i] += w
+		g.degree[i] += w
+		g.degree[j] += w
+		g.total += 2 * w
+	}
+
+	membership := louvain(g)
+
+	byCommunity := make(map[int]*detectedCommunity)
+	internalDegree := make(map[string]float64)
+	for _, rel := range relations {
+		c := membership[index[rel.Node1]]
+		if membership[index[rel.Node2]] != c {
+			continue
+		}
+		dc, ok := byCommunity[c]
+		if !ok {
+			dc = &detectedCommunity{}
+			byCommunity[c] = dc
+		}
+		w := relationWeight(rel)
+		dc.relations = append(dc.relations, rel)
+		dc.weight += w
+		internalDegree[rel.Node1] += w
+		internalDegree[rel.Node2] += w
+		for _, id := range rel.Chunks {
+			if id != "" && !slices.Contains(dc.chunkIDs, id) {
+				dc.chunkIDs = append(dc.chunkIDs, id)
+			}
+		}
+	}
+	for id, name := range names {
+		if dc, ok := byCommunity[membership[id]]; ok {
+			dc.entities = append(dc.entities, name)
+		}
+	}
+
+	result := make([]*detectedCommunity, 0, len(byCommunity))
+	for _, dc := range byCommunity {
+		if len(dc.entities) < 2 {
+			continue
+		}
+		sort.SliceStable(dc.entities, func(i, j int) bool {
+			a, b := dc.entities[i], dc.entities[j]
+			if internalDegree[a] != internalDegree[b] {
+				return internalDegree[a] > internalDegree[b]
+			}
+			return a < b
+		})
+		dc.rank = dc.weight * 2 / g.total
+		result = append(result, dc)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].weight != result[j].weight {
+			return result[i].weight > result[j].weight
+		}
+		return result[i].entities[0] < result[j].entities[0]
+	})
+	return result
+}
+
+// relationWeight is the number of chunks evidencing a relation, at least 1.
+func relationWeight(rel *types.GraphRelation) float64 {
+	if n := len(rel.Chunks); n > 1 {
+		return float64(n)
+	}
+	return 1
+}
+
+// louvain returns the community of every node of g, numbered from 0.
+func louvain(g *louvainGraph) []int {
+	membership := make([]int, len(g.adj))
+	for i := range membership {
+		membership[i] = i
+	}
+	if g.total == 0 {
+		return membership
+	}
+	for level := 0; level < louvainMaxLevels; level++ {
+		community, moved := louvainLevel(g)
+		if !moved {
+			break
+		}
+		count := renumber(community)
+		for i := range membership {
+			membership[i] = community[membership[i]]
+		}
+		if count == len(g.adj) {
+			break
+		}
+		g = aggregate(g, community, count)
+	}
+	renumber(membership)
+	return membership
+}
+
+// louvainLevel greedily moves each node into the neighbouring community with
+// the largest modularity gain until no move improves modularity. It reports
+// whether any node changed community.
+func louvainLevel(g *louvainGraph) ([]int, bool) {
+	n := len(g.adj)
+	community := make([]int, n)
+	totals := make([]float64, n)
+	for i := range community {
+		community[i] = i
+		totals[i] = g.degree[i]
+	}
+
+	moved := false
+	neighbours := make(map[int]float64)
+	var candidates []int
+	for pass := 0; pass < louvainMaxPasses; pass++ {
+		improved := false
+		for i := 0; i < n; i++ {
+			current := community[i]
+			clear(neighbours)
+			candidates = candidates[:0]
+			for j, w := range g.adj[i] {
+				if j == i {
+					continue
+				}
+				c := community[j]
+				if _, seen := neighbours[c]; !seen {
+					candidates = append(candidates, c)
+				}
+				neighbours[c] += w
+			}
+			slices.Sort(candidates)
+
+			totals[current] -= g.degree[i]
+			best := current
+			bestGain := neighbours[current] - totals[current]*g.degree[i]/g.total
+			for _, c := range candidates {
+				gain := neighbours[c] - totals[c]*g.degree[i]/g.total
+				if gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+			totals[best] += g.degree[i]
+			if best != current {
+				community[i] = best
+				improved = true
+				moved = true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return community, moved
+}
+
+// renumber maps community labels onto 0..k-1 in order of first appearance
+// and returns k.
+func renumber(community []int) int {
+	labels := make(map[int]int)
+	for i, c := range community {
+		label, ok := labels[c]
+		if !ok {
+			label = len(labels)
+			labels[c] = label
+		}
+		community[i] = label
+	}
+	return len(labels)
+}
+
+// aggregate collapses every community of g into a single node.
+func aggregate(g *louvainGraph, community []int, count int) *louvainGraph {
+	next := &louvainGraph{
+		adj:    make([]map[int]float64, count),
+		degree: make([]float64, count),
+		total:  g.total,
+	}
+	for i := range next.adj {
+		next.adj[i] = make(map[int]float64)
+	}
+	for i, row := range g.adj {
+		ci := community[i]
+		for j, w := range row {
+			next.adj[ci][community[j]] += w
+		}
+		next.degree[ci] += g.degree[i]
+	}
+	return next
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/stretchr/testify/require"
+)
+
+func communityRel(a, typ, b string, chunks ...string) *types.GraphRelation {
+	return &types.GraphRelation{Node1: a, Type: typ, Node2: b, Chunks: chunks}
+}
+
+// Two triangles joined by a single weak edge split into two communities, and
+// the better evidenced one ranks first.
+func TestDetectCommunitiesSplitsWeaklyJoinedClusters(t *testing.T) {
+	graph := &types.GraphData{Relation: []*types.GraphRelation{
+		communityRel("Pump 7", "part_of", "Cooling Loop", "c1", "c2"),
+		communityRel("Cooling Loop", "monitored_by", "Sensor S", "c1", "c2"),
+		communityRel("Sensor S", "attached_to", "Pump 7", "c2", "c3"),
+		communityRel("Supplier X", "supplies", "Part P", "c4"),
+		communityRel("Part P", "audited_by", "Auditor A", "c4"),
+		communityRel("Auditor A", "contracted_by", "Supplier X", "c5"),
+		communityRel("Part P", "installed_in", "Pump 7", "c6"),
+		communityRel("Pump 7", "self", "Pump 7", "c6"),
+	}}
+
+	communities := detectCommunities(graph)
+	require.Len(t, communities, 2)
+	require.ElementsMatch(t, []string{"Pump 7", "Cooling Loop", "Sensor S"}, communities[0].entities)
+	require.ElementsMatch(t, []string{"Supplier X", "Part P", "Auditor A"}, communities[1].entities)
+	require.Len(t, communities[0].relations, 3)
+	require.Equal(t, []string{"c1", "c2", "c3"}, communities[0].chunkIDs)
+	require.Greater(t, communities[0].rank, communities[1].rank)
+
+	// Store iteration order must not change the partition.
+	reversed := &types.GraphData{}
+	for i := len(graph.Relation) - 1; i >= 0; i-- {
+		reversed.Relation = append(reversed.Relation, graph.Relation[i])
+	}
+	again := detectCommunities(reversed)
+	require.Len(t, again, 2)
+	require.Equal(t, communities[0].entities, again[0].entities)
+	require.Equal(t, communities[1].entities, again[1].entities)
+}
+
+func TestDetectCommunitiesEmptyGraph(t *testing.T) {
+	require.Empty(t, detectCommunities(nil))
+	require.Empty(t, detectCommunities(&types.GraphData{Relation: []*types.GraphRelation{communityRel("A", "is", "A")}}))
+}
+
+func TestRankCommunitiesPrefersQueryOverlapThenRank(t *testing.T) {
+	communities := []*types.GraphCommunity{
+		{ID: "big", Title: "Supplier network", Summary: "Suppliers and audits", Rank: 0.6},
+		{ID: "pump", Title: "Cooling equipment", Summary: "Pump 7 failures", Entities: []string{"Pump 7"}, Rank: 0.3},
+		{ID: "small", Title: "Office moves", Summary: "Relocations", Rank: 0.1},
+	}
+
+	ranked := rankCommunities(communities, "why did the pump fail", 2)
+	require.Len(t, ranked, 2)
+	require.Equal(t, "pump", ranked[0].ID)
+	require.Equal(t, "big", ranked[1].ID)
+
+	// A broad question that matches nothing falls back to importance.
+	ranked = rankCommunities(communities, "overview", 3)
+	require.Equal(t, []string{"big", "pump", "small"},
+		[]string{ranked[0].ID, ranked[1].ID, ranked[2].ID})
+
+	require.Empty(t, rankCommunities(nil, "pump", 3))
+}
+
+type communityStubRepo struct {
+	interfaces.GraphCommunityRepository
+	communities []*types.GraphCommunity
+}
+
+func (r *communityStubRepo) ListByKnowledgeBases(context.Context, []string) ([]*types.GraphCommunity, error) {
+	return r.communities, nil
+}
+
+func (r *communityStubRepo) ReplaceByKnowledgeBase(
+	_ context.Context, _ string, communities []*types.GraphCommunity,
+) error {
+	r.communities = communities
+	return nil
+}
+
+type communityGraphEngine struct {
+	interfaces.RetrieveGraphRepository
+	graph *types.GraphData
+}
+
+func (e communityGraphEngine) GetGraph(context.Context, types.NameSpace) (*types.GraphData, error) {
+	return e.graph, nil
+}
+
+type communityChunkRepo struct {
+	interfaces.ChunkRepository
+	knowledgeOf map[string]string
+}
+
+func (r *communityChunkRepo) ListChunksByIDOnly(_ context.Context, ids []string) ([]*types.Chunk, error) {
+	var chunks []*types.Chunk
+	for _, id := range ids {
+		chunks = append(chunks, &types.Chunk{ID: id, KnowledgeID: r.knowledgeOf[id]})
+	}
+	return chunks, nil
+}
+
+func TestSearchCommunitiesDropsCommunitiesOfExcludedKnowledge(t *testing.T) {
+	repo := &communityStubRepo{communities: []*types.GraphCommunity{
+		{ID: "secret", Title: "Pump contracts", Rank: 0.9, ChunkIDs: []string{"c1", "c2"}},
+		{ID: "open", Title: "Pump manuals", Rank: 0.5, ChunkIDs: []string{"c3"}},
+		{ID: "other", Title: "Office moves", Rank: 0.1, ChunkIDs: []string{"c4"}},
+	}}
+	chunks := &communityChunkRepo{knowledgeOf: map[string]string{
+		"c1": "k-public", "c2": "k-hidden", "c3": "k-public", "c4": "k-public",
+	}}
+	svc := NewGraphCommunityService(repo, nil, chunks, nil, nil, nil)
+
+	got, err := svc.SearchCommunities(context.Background(), []string{"kb"}, "pump", 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"secret", "open"}, []string{got[0].ID, got[1].ID})
+
+	// The hidden community gives its slot to the next best one.
+	got, err = svc.SearchCommunities(context.Background(), []string{"kb"}, "pump", 2, []string{"k-hidden"})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, []string{"open", "other"}, []string{got[0].ID, got[1].ID})
+}
+
+// Stored chunk IDs are truncated, so the source documents decide; legacy
+// communities with a possibly truncated chunk list are dropped outright.
+func TestSearchCommunitiesChecksEverySourceDocument(t *testing.T) {
+	legacyChunks := make([]string, communityMaxChunkIDs)
+	for i := range legacyChunks {
+		legacyChunks[i] = "c1"
+	}
+	repo := &communityStubRepo{communities: []*types.GraphCommunity{
+		{ID: "truncated", Title: "Pump contracts", Rank: 0.9, ChunkIDs: []string{"c1"},
+			KnowledgeIDs: []string{"k-public", "k-hidden"}},
+		{ID: "legacy", Title: "Pump history", Rank: 0.7, ChunkIDs: legacyChunks},
+		{ID: "open", Title: "Pump manuals", Rank: 0.5, ChunkIDs: []string{"c1"},
+			KnowledgeIDs: []string{"k-public"}},
+	}}
+	chunks := &communityChunkRepo{knowledgeOf: map[string]string{"c1": "k-public"}}
+	svc := NewGraphCommunityService(repo, nil, chunks, nil, nil, nil)
+
+	got, err := svc.SearchCommunities(context.Background(), []string{"kb"}, "pump", 3, []string{"k-hidden"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "open", got[0].ID)
+}
+
+func TestListCommunitiesHidesCommunitiesOfDeniedDocuments(t *testing.T) {
+	repo := &communityStubRepo{communities: []*types.GraphCommunity{
+		{ID: "mixed", Title: "Pump contracts", KnowledgeIDs: []string{"k-public", "k-hidden"}},
+		{ID: "open", Title: "Pump manuals", KnowledgeIDs: []string{"k-public"}},
+	}}
+	svc := NewGraphCommunityService(repo, nil, &communityChunkRepo{}, nil, nil, nil)
+
+	got, err := svc.ListCommunities(context.Background(), "kb", []string{"k-hidden"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "open", got[0].ID)
+
+	got, err = svc.ListCommunities(context.Background(), "kb", nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+}
+
+// Without a summary model the rebuild still replaces stale communities,
+// describing each by its relations, and records every source document.
+func TestRebuildWithoutSummaryModelKeepsRelationLists(t *testing.T) {
+	graph := &types.GraphData{Relation: []*types.GraphRelation{
+		communityRel("Pump 7", "part_of", "Cooling Loop", "c1"),
+		communityRel("Cooling Loop", "monitored_by", "Sensor S", "c2"),
+		communityRel("Sensor S", "attached_to", "Pump 7", "c3"),
+	}}
+	repo := &communityStubRepo{communities: []*types.GraphCommunity{{ID: "stale"}}}
+	chunks := &communityChunkRepo{knowledgeOf: map[string]string{"c1": "k1", "c2": "k2", "c3": "k1"}}
+	svc := NewGraphCommunityService(repo, nil, chunks, communityGraphEngine{graph: graph}, nil, nil)
+
+	kb := &types.KnowledgeBase{ID: "kb"}
+	require.NoError(t, svc.(*graphCommunityService).rebuild(context.Background(), kb))
+	require.Len(t, repo.communities, 1)
+	got := repo.communities[0]
+	require.Equal(t, fallbackCommunityReport(detectCommunities(graph)[0]).Title, got.Title)
+	require.ElementsMatch(t, []string{"k1", "k2"}, got.KnowledgeIDs)
+}
+
+func TestCommunityBuildTaskIDStartsNewTaskOnceRebuildRuns(t *testing.T) {
+	at := time.Unix(0, 0).Add(10 * communityBuildDelay)
+	require.Equal(t, communityBuildTaskID("kb", at), communityBuildTaskID("kb", at.Add(communityBuildDelay/2)))
+	// The task scheduled at "at" starts one delay later; changes from then
+	// on must not coalesce into it.
+	require.NotEqual(t, communityBuildTaskID("kb", at), communityBuildTaskID("kb", at.Add(communityBuildDelay)))
+	require.NotEqual(t, communityBuildTaskID("kb", at), communityBuildTaskID("other", at))
+}
@@ -164,6 +164,11 @@ func (s *ImageMultimodalService) Handle(ctx context.Context, task *asynq.Task) e
 		s.checkAndFinalizeAllImages(ctx, payload)
 		return nil
 	}
+	// Hold the image while its knowledge base moves to another vector store;
+	// the task is requeued without counting an attempt.
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	// Open a per-image subspan under the parent attempt's multimodal
 	// stage. If the parent stage row is missing (legacy in-flight
@@ -469,13 +474,21 @@ func (s *ImageMultimodalService) indexChunks(ctx context.Context, payload types.
 
 	// Resolve engine via the factory using the KB's VectorStore binding
 	// (nil → tenant effective engines fallback; verified tenant ownership otherwise).
-	engine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, payload.TenantID, kb.VectorStoreID)
+	engine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, payload.TenantID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		logger.Warnf(ctx, "[ImageMultimodal] Failed to init retrieve engine: %v", err)
 		return
 	}
 
+	// Image chunks carry the custom metadata of their document, so metadata
+	// filters apply to them too.
+	var indexMetadata map[string]any
+	if s.knowledgeRepo != nil && payload.KnowledgeID != "" {
+		if knowledge, kerr := s.knowledgeRepo.GetKnowledgeByIDOnly(ctx, payload.KnowledgeID); kerr == nil {
+			indexMetadata = knowledge.IndexMetadata()
+		}
+	}
 	indexInfoList := make([]*types.IndexInfo, 0, len(chunks))
 	for _, chunk := range chunks {
 		indexInfoList = append(indexInfoList, &types.IndexInfo{
@@ -484,7 +497,8 @@ func (s *ImageMultimodalService) indexChunks(ctx context.Context, payload types.
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
-			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
+			Metadata:        indexMetadata,
 		})
 	}
 
@@ -79,8 +79,10 @@ type knowledgeService struct {
 	// pipeline. Best-effort: a nil tracker (test harness) is safely
 	// handled because the public surface is the SpanTracker interface,
 	// which has a no-op fallback. See knowledge_span_tracker.go.
-	spanTracker SpanTracker
-	audit       interfaces.AuditLogService
+	spanTracker      SpanTracker
+	audit            interfaces.AuditLogService
+	knowledgeACL     interfaces.KnowledgeACLService
+	communityService interfaces.GraphCommunityService
 }
 
 const (
@@ -118,35 +120,39 @@ func NewKnowledgeService(
 	taskPendingRepo interfaces.TaskPendingOpsRepository,
 	spanTracker SpanTracker,
 	audit interfaces.AuditLogService,
+	knowledgeACL interfaces.KnowledgeACLService,
+	communityService interfaces.GraphCommunityService,
 ) (interfaces.KnowledgeService, error) {
 	return &knowledgeService{
-		config:          config,
-		repo:            repo,
-		kbService:       kbService,
-		tenantRepo:      tenantRepo,
-		tenantService:   tenantService,
-		documentReader:  documentReader,
-		chunkService:    chunkService,
-		chunkRepo:       chunkRepo,
-		tagRepo:         tagRepo,
-		tagService:      tagService,
-		fileSvc:         fileSvc,
-		storageResolver: storageResolver,
-		resourceCatalog: resourceCatalog,
-		modelService:    modelService,
-		task:            task,
-		taskInspector:   taskInspector,
-		graphEngine:     graphEngine,
-		retrieveEngine:  retrieveEngine,
-		ownership:       ownership,
-		redisClient:     redisClient,
-		kbShareService:  kbShareService,
-		imageResolver:   imageResolver,
-		wikiRepo:        wikiRepo,
-		wikiService:     wikiService,
-		taskPendingRepo: taskPendingRepo,
-		spanTracker:     spanTracker,
-		audit:           audit,
+		config:           config,
+		repo:             repo,
+		kbService:        kbService,
+		tenantRepo:       tenantRepo,
+		tenantService:    tenantService,
+		documentReader:   documentReader,
+		chunkService:     chunkService,
+		chunkRepo:        chunkRepo,
+		tagRepo:          tagRepo,
+		tagService:       tagService,
+		fileSvc:          fileSvc,
+		storageResolver:  storageResolver,
+		resourceCatalog:  resourceCatalog,
+		modelService:     modelService,
+		task:             task,
+		taskInspector:    taskInspector,
+		graphEngine:      graphEngine,
+		retrieveEngine:   retrieveEngine,
+		ownership:        ownership,
+		redisClient:      redisClient,
+		kbShareService:   kbShareService,
+		imageResolver:    imageResolver,
+		wikiRepo:         wikiRepo,
+		wikiService:      wikiService,
+		taskPendingRepo:  taskPendingRepo,
+		spanTracker:      spanTracker,
+		audit:            audit,
+		knowledgeACL:     knowledgeACL,
+		communityService: communityService,
 	}, nil
 }
 
@@ -238,9 +244,14 @@ func finalizeSubtaskDetached(
 	}
 	dctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalizeSubtaskDetachedTimeout)
 	defer cancel()
-	if _, _, err := repo.FinalizeSubtask(dctx, knowledgeID); err != nil {
+	_, promoted, err := repo.FinalizeSubtask(dctx, knowledgeID)
+	if err != nil {
 		logger.Warnf(ctx, "finalize subtask decrement failed source=%s knowledge=%s err=%v",
 			source, knowledgeID, err)
+		return
+	}
+	if promoted {
+		publishKnowledgeIngestedByID(dctx, repo, knowledgeID)
 	}
 }
 
@@ -747,10 +758,32 @@ func (s *knowledgeService) UpdateKnowledge(ctx context.Context, knowledge *types
 			logger.Infof(ctx, "Enqueued summary refresh after metadata update, knowledge ID: %s", record.ID)
 		}
 	}
+	if metadataChanged {
+		if err := s.syncIndexMetadata(ctx, record); err != nil {
+			logger.Warnf(ctx, "Metadata saved but index metadata sync failed for %s: %v", record.ID, err)
+		}
+	}
 	logger.Infof(ctx, "Knowledge updated successfully, ID: %s", knowledge.ID)
 	return nil
 }
 
+// syncIndexMetadata restamps the index entries of a knowledge with its custom
+// metadata, so metadata filters see an edit without re-parsing the document.
+func (s *knowledgeService) syncIndexMetadata(ctx context.Context, knowledge *types.Knowledge) error {
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, knowledge.KnowledgeBaseID)
+	if err != nil {
+		return err
+	}
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, knowledge.TenantID, kb.VectorStoreID, kb.ID)
+	if err != nil {
+		return err
+	}
+	return retrieveEngine.BatchUpdateKnowledgeMetadata(ctx, map[string]map[string]any{
+		knowledge.ID: knowledge.IndexMetadata(),
+	})
+}
+
 // GetKnowledgeBatch retrieves multiple knowledge entries by their IDs
 func (s *knowledgeService) GetKnowledgeBatch(ctx context.Context,
 	tenantID uint64, ids []string,
@@ -0,0 +1,768 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+)
+
+type knowledgeACLService struct {
+	repo          interfaces.KnowledgeACLRepository
+	groupRepo     interfaces.UserGroupRepository
+	userRepo      interfaces.UserRepository
+	memberRepo    interfaces.TenantMemberRepository
+	kbRepo        interfaces.KnowledgeBaseRepository
+	knowledgeRepo interfaces.KnowledgeRepository
+}
+
+// NewKnowledgeACLService creates the document access list service
+func NewKnowledgeACLService(
+	repo interfaces.KnowledgeACLRepository,
+	groupRepo interfaces.UserGroupRepository,
+	userRepo interfaces.UserRepository,
+	memberRepo interfaces.TenantMemberRepository,
+	kbRepo interfaces.KnowledgeBaseRepository,
+	knowledgeRepo interfaces.KnowledgeRepository,
+) interfaces.KnowledgeACLService {
+	return &knowledgeACLService{
+		repo:          repo,
+		groupRepo:     groupRepo,
+		userRepo:      userRepo,
+		memberRepo:    memberRepo,
+		kbRepo:        kbRepo,
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+func (s *knowledgeACLService) GetKnowledgeACL(ctx context.Context, knowledgeID string) (*types.KnowledgeACL, error) {
+	knowledge, err := s.ownKnowledge(ctx, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByKnowledgeBases(ctx, []string{knowledge.KnowledgeBaseID})
+	if err != nil {
+		return nil, err
+	}
+	index := types.NewKnowledgeACLIndex(entries)
+	acl := &types.KnowledgeACL{
+		KnowledgeBaseID: knowledge.KnowledgeBaseID,
+		TargetType:      types.KnowledgeACLTargetKnowledge,
+		TargetID:        knowledge.ID,
+		Principals:      principalsOf(index.KnowledgeEntries(knowledge.ID)),
+	}
+	if inherited, from, ok := index.InheritedFolder(knowledge.FolderPath); ok {
+		acl.InheritedFrom = &from
+		acl.InheritedPrincipals = principalsOf(inherited)
+	}
+	if err := s.fillPrincipalNames(ctx, knowledge.TenantID, []*types.KnowledgeACL{acl}); err != nil {
+		return nil, err
+	}
+	return acl, nil
+}
+
+func (s *knowledgeACLService) SetKnowledgeACL(
+	ctx context.Context, knowledgeID string, principals []types.KnowledgeACLPrincipal,
+) (*types.KnowledgeACL, error) {
+	knowledge, err := s.ownKnowledge(ctx, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replace(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID,
+		types.KnowledgeACLTargetKnowledge, knowledge.ID, principals); err != nil {
+		return nil, err
+	}
+	return s.GetKnowledgeACL(ctx, knowledge.ID)
+}
+
+func (s *knowledgeACLService) GetFolderACL(ctx context.Context, kbID string, folderPath string) (*types.KnowledgeACL, error) {
+	kb, path, err := s.resolveFolder(ctx, kbID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByKnowledgeBases(ctx, []string{kb.ID})
+	if err != nil {
+		return nil, err
+	}
+	index := types.NewKnowledgeACLIndex(entries)
+	acl := &types.KnowledgeACL{
+		KnowledgeBaseID: kb.ID,
+		TargetType:      types.KnowledgeACLTargetFolder,
+		TargetID:        path,
+		Principals:      principalsOf(index.FolderEntries(path)),
+	}
+	if inherited, from, ok := index.InheritedFolder(parentFolderPath(path)); ok {
+		acl.InheritedFrom = &from
+		acl.InheritedPrincipals = principalsOf(inherited)
+	}
+	if err := s.fillPrincipalNames(ctx, kb.TenantID, []*types.KnowledgeACL{acl}); err != nil {
+		return nil, err
+	}
+	return acl, nil
+}
+
+func (s *knowledgeACLService) SetFolderACL(
+	ctx context.Context, kbID string, folderPath string, principals []types.KnowledgeACLPrincipal,
+) (*types.KnowledgeACL, error) {
+	kb, path, err := s.resolveFolder(ctx, kbID, folderPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replace(ctx, kb.TenantID, kb.ID, types.KnowledgeACLTargetFolder, path, principals); err != nil {
+		return nil, err
+	}
+	return s.GetFolderACL(ctx, kb.ID, path)
+}
+
+func (s *knowledgeACLService) ListKnowledgeBaseACLs(ctx context.Context, kbID string) ([]*types.KnowledgeACL, error) {
+	kb, err := s.ownKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByKnowledgeBases(ctx, []string{kb.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	type target struct {
+		kind types.KnowledgeACLTargetType
+		id   string
+	}
+	grouped := map[target][]*types.KnowledgeACLEntry{}
+	var order []target
+	for _, entry := range entries {
+		key := target{kind: entry.TargetType, id: entry.TargetID}
+		if _, seen := grouped[key]; !seen {
+			order = append(order, key)
+		}
+		grouped[key] = append(grouped[key], entry)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].kind != order[j].kind {
+			return order[i].kind == types.KnowledgeACLTargetFolder
+		}
+		return order[i].id < order[j].id
+	})
+
+	acls := make([]*types.KnowledgeACL, 0, len(order))
+	for _, key := range order {
+		acls = append(acls, &types.KnowledgeACL{
+			KnowledgeBaseID: kb.ID,
+			TargetType:      key.kind,
+			TargetID:        key.id,
+			Principals:      principalsOf(grouped[key]),
+		})
+	}
+	if err := s.fillPrincipalNames(ctx, kb.TenantID, acls); err != nil {
+		return nil, err
+	}
+	return acls, nil
+}
+
+// ResolveReader maps the caller in ctx to the identity document access lists
+// are checked against. Internal calls without a caller are not restricted,
+// and neither is background work unless it runs on behalf of an explicit
+// principal, as agent triggers do. Tenant owners and admins, and full-access
+// API keys of the tenant, see every document of their tenant's knowledge
+// bases. Anyone else without a WeKnora account (IM users, embed visitors,
+// external API users, restricted API keys, agent triggers) only sees
+// unrestricted documents.
+func (s *knowledgeACLService) ResolveReader(ctx context.Context) (*types.KnowledgeACLReader, error) {
+	if types.IsBackgroundTask(ctx) {
+		// Task payloads may restore the enqueuing user's ID for attribution;
+		// only a principal set by the task itself restricts what it reads.
+		if _, explicit := ctx.Value(types.PrincipalContextKey).(types.Principal); !explicit {
+			return nil, nil
+		}
+	}
+	principal, ok := types.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	reader := &types.KnowledgeACLReader{
+		GroupIDs:        map[string]bool{},
+		BypassTenantIDs: map[uint64]bool{},
+	}
+	switch principal.Type {
+	case types.PrincipalWebUser:
+		if types.IsSyntheticUserID(principal.ID) {
+			return reader, nil
+		}
+		reader.UserID = principal.ID
+		memberships, err := s.memberRepo.ListByUser(ctx, principal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list tenant memberships: %w", err)
+		}
+		for _, member := range memberships {
+			if member.Status == types.TenantMemberStatusActive &&
+				member.Role.HasPermission(types.TenantRoleAdmin) {
+				reader.BypassTenantIDs[member.TenantID] = true
+			}
+		}
+		groupIDs, err := s.groupRepo.ListGroupIDsByUser(ctx, principal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list user groups: %w", err)
+		}
+		for _, id := range groupIDs {
+			reader.GroupIDs[id] = true
+		}
+	case types.PrincipalAPITenant:
+		// The principal carries the key's own tenant; the tenant in ctx may
+		// already be the source tenant of an organization-shared knowledge
+		// base, whose documents the key must not bypass.
+		if scope, ok := types.TenantAPIKeyScopeFromContext(ctx); ok && scope.FullAccess {
+			if tenantID, err := strconv.ParseUint(principal.ID, 10, 64); err == nil && tenantID > 0 {
+				reader.BypassTenantIDs[tenantID] = true
+			}
+		}
+	}
+	return reader, nil
+}
+
+func (s *knowledgeACLService) DeniedKnowledgeIDs(ctx context.Context, kbIDs []string) (map[string][]string, error) {
+	if len(kbIDs) == 0 {
+		return nil, nil
+	}
+	reader, err := s.ResolveReader(ctx)
+	if err != nil || reader == nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListByKnowledgeBases(ctx, dedupStrings(kbIDs))
+	if err != nil {
+		return nil, fmt.Errorf("list knowledge acl entries: %w", err)
+	}
+	byKB := map[string][]*types.KnowledgeACLEntry{}
+	for _, entry := range entries {
+		if reader.BypassTenantIDs[entry.TenantID] {
+			continue
+		}
+		byKB[entry.KnowledgeBaseID] = append(byKB[entry.KnowledgeBaseID], entry)
+	}
+
+	denied := map[string][]string{}
+	for kbID, kbEntries := range byKB {
+		index := types.NewKnowledgeACLIndex(kbEntries)
+		seen := map[string]bool{}
+		deny := func(id string) {
+			if !seen[id] {
+				seen[id] = true
+				denied[kbID] = append(denied[kbID], id)
+			}
+		}
+		// A document's own list overrides its folders, so it is decided here
+		// regardless of where it is stored.
+		for _, id := range index.KnowledgeIDs() {
+			if !index.Allows(reader, id, "") {
+				deny(id)
+			}
+		}
+		// Only folders that do not grant the reader can hide anything; the
+		// documents below them are checked one by one because a closer folder
+		// or their own list may still grant access.
+		var closed []string
+		for _, path := range index.FolderPaths() {
+			if !grantsReader(reader, index.FolderEntries(path)) {
+				closed = append(closed, path)
+			}
+		}
+		if len(closed) == 0 {
+			continue
+		}
+		rows, err := s.repo.ListKnowledgeInFolders(ctx, kbID, closed)
+		if err != nil {
+			return nil, fmt.Errorf("list knowledge in restricted folders: %w", err)
+		}
+		for _, row := range rows {
+			if !index.Allows(reader, row.ID, row.FolderPath) {
+				deny(row.ID)
+			}
+		}
+	}
+	return denied, nil
+}
+
+func (s *knowledgeACLService) CanReadKnowledge(ctx context.Context, knowledge *types.Knowledge) (bool, error) {
+	if knowledge == nil {
+		return false, nil
+	}
+	reader, err := s.ResolveReader(ctx)
+	if err != nil {
+		return false, err
+	}
+	if reader == nil || reader.BypassTenantIDs[knowledge.TenantID] {
+		return true, nil
+	}
+	entries, err := s.repo.ListByKnowledgeBases(ctx, []string{knowledge.KnowledgeBaseID})
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return true, nil
+	}
+	return types.NewKnowledgeACLIndex(entries).Allows(reader, knowledge.ID, knowledge.FolderPath), nil
+}
+
+func (s *knowledgeACLService) ApplySourcePermissions(
+	ctx context.Context, ds *types.DataSource, knowledgeIDs []string, permissions *types.ItemPermissions,
+) error {
+	if ds == nil || permissions == nil || len(knowledgeIDs) == 0 {
+		return nil
+	}
+	var entries []*types.KnowledgeACLEntry
+	if !permissions.Public {
+		accounts, err := s.memberEmails(ctx, ds.TenantID, permissions.Principals)
+		if err != nil {
+			return err
+		}
+		seen := map[types.KnowledgeACLPrincipal]bool{}
+		for _, source := range permissions.Principals {
+			principal := types.KnowledgeACLPrincipal{
+				Type: types.KnowledgeACLPrincipalSource,
+				ID:   sourcePrincipalID(source),
+			}
+			if userID, ok := accounts[strings.ToLower(strings.TrimSpace(source.Email))]; ok {
+				principal = types.KnowledgeACLPrincipal{Type: types.KnowledgeACLPrincipalUser, ID: userID}
+			}
+			if principal.ID == "" || seen[principal] {
+				continue
+			}
+			seen[principal] = true
+			for _, knowledgeID := range knowledgeIDs {
+				entries = append(entries, &types.KnowledgeACLEntry{
+					ID:              uuid.New().String(),
+					TenantID:        ds.TenantID,
+					KnowledgeBaseID: ds.KnowledgeBaseID,
+					TargetType:      types.KnowledgeACLTargetKnowledge,
+					TargetID:        knowledgeID,
+					PrincipalType:   principal.Type,
+					PrincipalID:     principal.ID,
+					DataSourceID:    ds.ID,
+				})
+			}
+		}
+		// A private item whose source lists no reader at all is readable by
+		// nobody there; keep it restricted here too instead of opening it up.
+		if len(seen) == 0 {
+			for _, knowledgeID := range knowledgeIDs {
+				entries = append(entries, &types.KnowledgeACLEntry{
+					ID:              uuid.New().String(),
+					TenantID:        ds.TenantID,
+					KnowledgeBaseID: ds.KnowledgeBaseID,
+					TargetType:      types.KnowledgeACLTargetKnowledge,
+					TargetID:        knowledgeID,
+					PrincipalType:   types.KnowledgeACLPrincipalSource,
+					PrincipalID:     "none",
+					DataSourceID:    ds.ID,
+				})
+			}
+		}
+	}
+	if err := s.repo.ReplaceSynced(ctx, ds.KnowledgeBaseID, ds.ID, knowledgeIDs, entries); err != nil {
+		return fmt.Errorf("replace synced access list: %w", err)
+	}
+	return nil
+}
+
+func (s *knowledgeACLService) MoveKnowledgeACL(
+	ctx context.Context, kbID string, fromKnowledgeID string, toKnowledgeID string,
+) error {
+	if fromKnowledgeID == "" || toKnowledgeID == "" || fromKnowledgeID == toKnowledgeID {
+		return nil
+	}
+	return s.repo.MoveKnowledgeTarget(ctx, kbID, fromKnowledgeID, toKnowledgeID)
+}
+
+func (s *knowledgeACLService) ClearSourcePermissions(ctx context.Context, dataSourceID string) error {
+	return s.repo.DeleteByDataSource(ctx, dataSourceID)
+}
+
+func (s *knowledgeACLService) CopyKnowledgeACL(ctx context.Context, src, dst *types.Knowledge) error {
+	entries, err := s.repo.ListByKnowledgeBases(ctx, dedupStrings([]string{src.KnowledgeBaseID, dst.KnowledgeBaseID}))
+	if err != nil {
+		return fmt.Errorf("list knowledge acl entries: %w", err)
+	}
+	var srcEntries, dstEntries []*types.KnowledgeACLEntry
+	for _, entry := range entries {
+		if entry.KnowledgeBaseID == src.KnowledgeBaseID {
+			srcEntries = append(srcEntries, entry)
+		}
+		if entry.KnowledgeBaseID == dst.KnowledgeBaseID {
+			dstEntries = append(dstEntries, entry)
+		}
+	}
+	effective, from, restricted := types.NewKnowledgeACLIndex(srcEntries).Effective(src.ID, src.FolderPath)
+	if !restricted {
+		return nil
+	}
+	// An inherited list only needs copying when the destination folder does
+	// not already give the document the same readers, as it does in a clone
+	// whose folder lists were copied.
+	if from != "" {
+		inherited, _, ok := types.NewKnowledgeACLIndex(dstEntries).InheritedFolder(dst.FolderPath)
+		if ok && samePrincipals(principalsOf(inherited), principalsOf(effective)) {
+			return nil
+		}
+	}
+	// The copy is set by hand in its knowledge base: the source's data source
+	// does not sync there.
+	copied := make([]*types.KnowledgeACLEntry, 0, len(effective))
+	seen := map[types.KnowledgeACLPrincipal]bool{}
+	for _, entry := range effective {
+		principal := types.KnowledgeACLPrincipal{Type: entry.PrincipalType, ID: entry.PrincipalID}
+		if seen[principal] {
+			continue
+		}
+		seen[principal] = true
+		copied = append(copied, &types.KnowledgeACLEntry{
+			ID:              uuid.New().String(),
+			TenantID:        dst.TenantID,
+			KnowledgeBaseID: dst.KnowledgeBaseID,
+			TargetType:      types.KnowledgeACLTargetKnowledge,
+			TargetID:        dst.ID,
+			PrincipalType:   entry.PrincipalType,
+			PrincipalID:     entry.PrincipalID,
+			CreatedBy:       entry.CreatedBy,
+		})
+	}
+	return s.repo.ReplaceTarget(ctx, dst.KnowledgeBaseID, types.KnowledgeACLTargetKnowledge, dst.ID, copied)
+}
+
+// samePrincipals reports whether two access lists name the same principals
+func samePrincipals(a, b []types.KnowledgeACLPrincipal) bool {
+	key := func(p types.KnowledgeACLPrincipal) types.KnowledgeACLPrincipal {
+		return types.KnowledgeACLPrincipal{Type: p.Type, ID: p.ID}
+	}
+	set := map[types.KnowledgeACLPrincipal]bool{}
+	for _, p := range a {
+		set[key(p)] = true
+	}
+	other := map[types.KnowledgeACLPrincipal]bool{}
+	for _, p := range b {
+		if !set[key(p)] {
+			return false
+		}
+		other[key(p)] = true
+	}
+	return len(other) == len(set)
+}
+
+func (s *knowledgeACLService) CopyFolderACLs(ctx context.Context, srcKBID string, dstKB *types.KnowledgeBase) error {
+	entries, err := s.repo.ListByKnowledgeBases(ctx, []string{srcKBID, dstKB.ID})
+	if err != nil {
+		return fmt.Errorf("list knowledge acl entries: %w", err)
+	}
+	var srcEntries, dstEntries []*types.KnowledgeACLEntry
+	for _, entry := range entries {
+		if entry.KnowledgeBaseID == dstKB.ID {
+			dstEntries = append(dstEntries, entry)
+		} else {
+			srcEntries = append(srcEntries, entry)
+		}
+	}
+	src, dst := types.NewKnowledgeACLIndex(srcEntries), types.NewKnowledgeACLIndex(dstEntries)
+	for _, path := range src.FolderPaths() {
+		if len(dst.FolderEntries(path)) > 0 {
+			continue
+		}
+		folderEntries := src.FolderEntries(path)
+		copied := make([]*types.KnowledgeACLEntry, 0, len(folderEntries))
+		for _, entry := range folderEntries {
+			copied = append(copied, &types.KnowledgeACLEntry{
+				ID:              uuid.New().String(),
+				TenantID:        dstKB.TenantID,
+				KnowledgeBaseID: dstKB.ID,
+				TargetType:      types.KnowledgeACLTargetFolder,
+				TargetID:        path,
+				PrincipalType:   entry.PrincipalType,
+				PrincipalID:     entry.PrincipalID,
+				CreatedBy:       entry.CreatedBy,
+			})
+		}
+		if err := s.repo.ReplaceTarget(ctx, dstKB.ID, types.KnowledgeACLTargetFolder, path, copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *knowledgeACLService) DeleteKnowledgeACLs(ctx context.Context, kbID string, knowledgeIDs []string) error {
+	return s.repo.DeleteByKnowledge(ctx, kbID, knowledgeIDs)
+}
+
+func (s *knowledgeACLService) DeleteKnowledgeBaseACLs(ctx context.Context, kbID string) error {
+	return s.repo.DeleteByKnowledgeBase(ctx, kbID)
+}
+
+// memberEmails maps the lower-cased email of every active member of a tenant
+// to its user ID, when any of principals carries an email
+func (s *knowledgeACLService) memberEmails(
+	ctx context.Context, tenantID uint64, principals []types.SourcePrincipal,
+) (map[string]string, error) {
+	accounts := map[string]string{}
+	hasEmail := false
+	for _, p := range principals {
+		if strings.TrimSpace(p.Email) != "" {
+			hasEmail = true
+			break
+		}
+	}
+	if !hasEmail {
+		return accounts, nil
+	}
+	members, err := s.memberRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant members: %w", err)
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.Status == types.TenantMemberStatusActive {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return accounts, nil
+	}
+	users, err := s.userRepo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant users: %w", err)
+	}
+	for id, user := range users {
+		if email := strings.ToLower(strings.TrimSpace(user.Email)); email != "" {
+			accounts[email] = id
+		}
+	}
+	return accounts, nil
+}
+
+// sourcePrincipalID identifies a source reader without a WeKnora account in
+// an access list entry, hashing identifiers too long for the column
+func sourcePrincipalID(p types.SourcePrincipal) string {
+	id := strings.TrimSpace(p.ID)
+	if id == "" {
+		id = strings.ToLower(strings.TrimSpace(p.Email))
+	}
+	if id == "" {
+		return ""
+	}
+	kind := p.Type
+	if kind == "" {
+		kind = types.SourcePrincipalUser
+	}
+	key := kind + ":" + id
+	if len(key) > 64 {
+		sum := sha256.Sum256([]byte(key))
+		key = kind + ":" + hex.EncodeToString(sum[:])[:32]
+	}
+	return key
+}
+
+// ownKnowledgeBase loads a knowledge base of the current tenant. Access lists
+// name the users and groups of the tenant owning the knowledge base, so they
+// are only managed from that tenant, never through an organization share.
+func (s *knowledgeACLService) ownKnowledgeBase(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil || kb == nil || kb.TenantID != types.MustTenantIDFromContext(ctx) {
+		return nil, werrors.NewNotFoundError("knowledge base not found")
+	}
+	return kb, nil
+}
+
+// ownKnowledge loads a document of the current tenant
+func (s *knowledgeACLService) ownKnowledge(ctx context.Context, knowledgeID string) (*types.Knowledge, error) {
+	knowledge, err := s.knowledgeRepo.GetKnowledgeByIDOnly(ctx, knowledgeID)
+	if err != nil || knowledge == nil || knowledge.TenantID != types.MustTenantIDFromContext(ctx) {
+		return nil, werrors.NewNotFoundError("knowledge not found")
+	}
+	return knowledge, nil
+}
+
+// resolveFolder loads the knowledge base and normalizes a folder path. The
+// root folder cannot carry an access list: restricting a whole knowledge base
+// is what knowledge base sharing is for.
+func (s *knowledgeACLService) resolveFolder(
+	ctx context.Context, kbID string, folderPath string,
+) (*types.KnowledgeBase, string, error) {
+	kb, err := s.ownKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, "", err
+	}
+	path := types.NormalizeKnowledgeFolderPath(folderPath)
+	if path == "" {
+		return nil, "", werrors.NewBadRequestError("folder_path is required")
+	}
+	return kb, path, nil
+}
+
+// replace validates principals and stores them as the access list of a target
+func (s *knowledgeACLService) replace(
+	ctx context.Context, tenantID uint64, kbID string,
+	targetType types.KnowledgeACLTargetType, targetID string,
+	principals []types.KnowledgeACLPrincipal,
+) error {
+	// Principals synced from a data source may be sent back as read; the
+	// data source keeps them up to date, so they are left out here.
+	principals = slices.DeleteFunc(slices.Clone(principals), func(p types.KnowledgeACLPrincipal) bool {
+		return p.DataSourceID != ""
+	})
+	if len(principals) > types.MaxKnowledgeACLPrincipals {
+		return werrors.NewValidationError(
+			fmt.Sprintf("an access list can hold at most %d principals", types.MaxKnowledgeACLPrincipals))
+	}
+	var userIDs, groupIDs []string
+	seen := map[types.KnowledgeACLPrincipal]bool{}
+	unique := make([]types.KnowledgeACLPrincipal, 0, len(principals))
+	for _, p := range principals {
+		p = types.KnowledgeACLPrincipal{Type: p.Type, ID: strings.TrimSpace(p.ID)}
+		if err := p.Validate(); err != nil {
+			return werrors.NewValidationError(err.Error())
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		unique = append(unique, p)
+		if p.Type == types.KnowledgeACLPrincipalUser {
+			userIDs = append(userIDs, p.ID)
+		} else {
+			groupIDs = append(groupIDs, p.ID)
+		}
+	}
+	if len(userIDs) > 0 {
+		members, err := s.memberRepo.ListByTenant(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		active := make(map[string]bool, len(members))
+		for _, member := range members {
+			if member.Status == types.TenantMemberStatusActive {
+				active[member.UserID] = true
+			}
+		}
+		for _, id := range userIDs {
+			if !active[id] {
+				return werrors.NewValidationError("user is not a member of this workspace: " + id)
+			}
+		}
+	}
+	if len(groupIDs) > 0 {
+		groups, err := s.groupRepo.ListByIDs(ctx, tenantID, groupIDs)
+		if err != nil {
+			return err
+		}
+		found := make(map[string]bool, len(groups))
+		for _, group := range groups {
+			found[group.ID] = true
+		}
+		for _, id := range groupIDs {
+			if !found[id] {
+				return werrors.NewValidationError("user group not found: " + id)
+			}
+		}
+	}
+
+	createdBy, _ := types.UserIDFromContext(ctx)
+	entries := make([]*types.KnowledgeACLEntry, 0, len(unique))
+	for _, p := range unique {
+		entries = append(entries, &types.KnowledgeACLEntry{
+			ID:              uuid.New().String(),
+			TenantID:        tenantID,
+			KnowledgeBaseID: kbID,
+			TargetType:      targetType,
+			TargetID:        targetID,
+			PrincipalType:   p.Type,
+			PrincipalID:     p.ID,
+			CreatedBy:       createdBy,
+		})
+	}
+	if err := s.repo.ReplaceTarget(ctx, kbID, targetType, targetID, entries); err != nil {
+		return err
+	}
+	logger.Infof(ctx, "Set access list of %s %s in kb %s: %d principals", targetType, targetID, kbID, len(entries))
+	return nil
+}
+
+// fillPrincipalNames sets the display name of every principal of acls
+func (s *knowledgeACLService) fillPrincipalNames(ctx context.Context, tenantID uint64, acls []*types.KnowledgeACL) error {
+	var userIDs, groupIDs []string
+	for _, acl := range acls {
+		for _, list := range [][]types.KnowledgeACLPrincipal{acl.Principals, acl.InheritedPrincipals} {
+			for _, p := range list {
+				switch p.Type {
+				case types.KnowledgeACLPrincipalUser:
+					userIDs = append(userIDs, p.ID)
+				case types.KnowledgeACLPrincipalGroup:
+					groupIDs = append(groupIDs, p.ID)
+				}
+			}
+		}
+	}
+	names := map[types.KnowledgeACLPrincipal]string{}
+	if len(userIDs) > 0 {
+		users, err := s.userRepo.GetUsersByIDs(ctx, dedupStrings(userIDs))
+		if err != nil {
+			return err
+		}
+		for id, user := range users {
+			names[types.KnowledgeACLPrincipal{Type: types.KnowledgeACLPrincipalUser, ID: id}] = user.Username
+		}
+	}
+	if len(groupIDs) > 0 {
+		groups, err := s.groupRepo.ListByIDs(ctx, tenantID, dedupStrings(groupIDs))
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			names[types.KnowledgeACLPrincipal{Type: types.KnowledgeACLPrincipalGroup, ID: group.ID}] = group.Name
+		}
+	}
+	for _, acl := range acls {
+		for _, list := range [][]types.KnowledgeACLPrincipal{acl.Principals, acl.InheritedPrincipals} {
+			for i := range list {
+				if list[i].Type == types.KnowledgeACLPrincipalSource {
+					list[i].Name = list[i].ID
+					continue
+				}
+				list[i].Name = names[types.KnowledgeACLPrincipal{Type: list[i].Type, ID: list[i].ID}]
+			}
+		}
+	}
+	return nil
+}
+
+func principalsOf(entries []*types.KnowledgeACLEntry) []types.KnowledgeACLPrincipal {
+	principals := make([]types.KnowledgeACLPrincipal, 0, len(entries))
+	for _, entry := range entries {
+		principals = append(principals, types.KnowledgeACLPrincipal{
+			Type:         entry.PrincipalType,
+			ID:           entry.PrincipalID,
+			DataSourceID: entry.DataSourceID,
+		})
+	}
+	return principals
+}
+
+func grantsReader(reader *types.KnowledgeACLReader, entries []*types.KnowledgeACLEntry) bool {
+	for _, entry := range entries {
+		if reader.Matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func parentFolderPath(path string) string {
+	if idx := strings.LastIndex(path, "/"); idx >= 0 {
+		return path[:idx]
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newKnowledgeACLTestService seeds kb-1 of tenant 1 with:
+//
+//	k-open     (root, unrestricted)
+//	k-fin      (finance, inherits the finance folder list: group finance)
+//	k-fin-sub  (finance/q1, inherits the finance folder list)
+//	k-fin-own  (finance, own list: user u2)
+//
+// u1 is a contributor in the finance group, u2 a contributor outside it and
+// u3 an admin of the tenant.
+func newKnowledgeACLTestService(t *testing.T) *knowledgeACLService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.Knowledge{}, &types.KnowledgeACLEntry{},
+		&types.UserGroup{}, &types.UserGroupMember{}, &types.TenantMember{}))
+	ctx := context.Background()
+
+	for _, k := range []*types.Knowledge{
+		{ID: "k-open", TenantID: 1, KnowledgeBaseID: "kb-1"},
+		{ID: "k-fin", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance"},
+		{ID: "k-fin-sub", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance/q1"},
+		{ID: "k-fin-own", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance"},
+	} {
+		require.NoError(t, db.Create(k).Error)
+	}
+
+	memberRepo := repository.NewTenantMemberRepository(db)
+	for userID, role := range map[string]types.TenantRole{
+		"u1": types.TenantRoleContributor,
+		"u2": types.TenantRoleContributor,
+		"u3": types.TenantRoleAdmin,
+	} {
+		require.NoError(t, memberRepo.Create(ctx, &types.TenantMember{UserID: userID, TenantID: 1, Role: role}))
+	}
+
+	groupRepo := repository.NewUserGroupRepository(db)
+	require.NoError(t, groupRepo.Create(ctx, &types.UserGroup{ID: "g-fin", TenantID: 1, Name: "finance"}))
+	require.NoError(t, groupRepo.ReplaceMembers(ctx, 1, "g-fin", []string{"u1"}))
+
+	aclRepo := repository.NewKnowledgeACLRepository(db)
+	entry := func(targetType types.KnowledgeACLTargetType, targetID string,
+		principalType types.KnowledgeACLPrincipalType, principalID string,
+	) []*types.KnowledgeACLEntry {
+		return []*types.KnowledgeACLEntry{{
+			ID: uuid.NewString(), TenantID: 1, KnowledgeBaseID: "kb-1",
+			TargetType: targetType, TargetID: targetID,
+			PrincipalType: principalType, PrincipalID: principalID,
+		}}
+	}
+	require.NoError(t, aclRepo.ReplaceTarget(ctx, "kb-1", types.KnowledgeACLTargetFolder, "finance",
+		entry(types.KnowledgeACLTargetFolder, "finance", types.KnowledgeACLPrincipalGroup, "g-fin")))
+	require.NoError(t, aclRepo.ReplaceTarget(ctx, "kb-1", types.KnowledgeACLTargetKnowledge, "k-fin-own",
+		entry(types.KnowledgeACLTargetKnowledge, "k-fin-own", types.KnowledgeACLPrincipalUser, "u2")))
+
+	return NewKnowledgeACLService(aclRepo, groupRepo, nil, memberRepo, nil, nil).(*knowledgeACLService)
+}
+
+func TestDeniedKnowledgeIDsFollowsNearestAccessList(t *testing.T) {
+	svc := newKnowledgeACLTestService(t)
+	webUser := func(userID string) context.Context {
+		ctx := context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1))
+		return types.WithPrincipal(ctx, types.Principal{Type: types.PrincipalWebUser, ID: userID})
+	}
+	apiKey := func(keyTenantID uint64, fullAccess bool) context.Context {
+		// The request runs in tenant 1 either way, as it does for a key
+		// reading an organization-shared knowledge base.
+		ctx := context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1))
+		ctx = types.WithTenantAPIKeyScope(ctx, types.TenantAPIKeyScope{KeyID: 7, FullAccess: fullAccess})
+		return types.WithPrincipal(ctx, types.Principal{
+			Type: types.PrincipalAPITenant, ID: strconv.FormatUint(keyTenantID, 10),
+		})
+	}
+	// ProcessRun receives a context marked as background work by the task
+	// middleware and sets the trigger identity on top of it.
+	agentTriggerRun := withAgentTriggerIdentity(types.WithBackgroundTask(context.Background()), &types.Tenant{ID: 1})
+	allRestricted := []string{"k-fin", "k-fin-sub", "k-fin-own"}
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		denied []string
+	}{
+		{"group member reads the folder but not a document listing someone else", webUser("u1"), []string{"k-fin-own"}},
+		{"user granted on a document only reads that document", webUser("u2"), []string{"k-fin", "k-fin-sub"}},
+		{"tenant admin bypasses access lists", webUser("u3"), nil},
+		{"agent trigger run reads unrestricted documents only", agentTriggerRun, allRestricted},
+		{"restricted API key reads unrestricted documents only", apiKey(1, false), allRestricted},
+		{"full-access key of the owning tenant bypasses access lists", apiKey(1, true), nil},
+		{"full-access key of another tenant does not bypass", apiKey(2, true), allRestricted},
+		{"background work on behalf of a user ID is not restricted", types.WithBackgroundTask(
+			context.WithValue(context.Background(), types.UserIDContextKey, "u2")), nil},
+		{"internal call without a caller is not restricted", context.Background(), nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			denied, err := svc.DeniedKnowledgeIDs(tc.ctx, []string{"kb-1", "kb-2"})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.denied, denied["kb-1"])
+			require.Empty(t, denied["kb-2"])
+		})
+	}
+}
+
+func TestCanReadKnowledgeMatchesDeniedKnowledgeIDs(t *testing.T) {
+	svc := newKnowledgeACLTestService(t)
+	ctx := types.WithPrincipal(context.Background(), types.Principal{Type: types.PrincipalWebUser, ID: "u2"})
+
+	for id, want := range map[string]bool{"k-open": true, "k-fin": false, "k-fin-sub": false, "k-fin-own": true} {
+		var folder string
+		switch id {
+		case "k-fin", "k-fin-own":
+			folder = "finance"
+		case "k-fin-sub":
+			folder = "finance/q1"
+		}
+		readable, err := svc.CanReadKnowledge(ctx, &types.Knowledge{
+			ID: id, TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: folder,
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, readable, id)
+	}
+}
+
+// deniedOnlyKnowledgeACL answers DeniedKnowledgeIDs from a fixed map
+type deniedOnlyKnowledgeACL struct {
+	interfaces.KnowledgeACLService
+	denied map[string][]string
+}
+
+func (s *deniedOnlyKnowledgeACL) DeniedKnowledgeIDs(context.Context, []string) (map[string][]string, error) {
+	return s.denied, nil
+}
+
+func TestApplyKnowledgeACLHidesDocumentsFromTargets(t *testing.T) {
+	svc := &sessionService{knowledgeACLService: &deniedOnlyKnowledgeACL{denied: map[string][]string{
+		"kb-1": {"doc-hidden"},
+		"kb-2": {"doc-a", "doc-b"},
+	}}}
+	targets, err := svc.applyKnowledgeACL(context.Background(), types.SearchTargets{
+		{Type: types.SearchTargetTypeKnowledgeBase, KnowledgeBaseID: "kb-1"},
+		{Type: types.SearchTargetTypeKnowledge, KnowledgeBaseID: "kb-2", KnowledgeIDs: []string{"doc-a", "doc-b"}},
+		{Type: types.SearchTargetTypeKnowledge, KnowledgeBaseID: "kb-1", KnowledgeIDs: []string{"doc-hidden", "doc-open"}},
+		{Type: types.SearchTargetTypeKnowledgeBase, KnowledgeBaseID: "kb-3"},
+	})
+	require.NoError(t, err)
+	require.Len(t, targets, 3, "a target left without readable documents must be dropped, not widened")
+
+	require.Equal(t, "kb-1", targets[0].KnowledgeBaseID)
+	require.Equal(t, []string{"doc-hidden"}, targets[0].ExcludeKnowledgeIDs)
+	require.Equal(t, []string{"doc-open"}, targets[1].KnowledgeIDs)
+	require.Equal(t, "kb-3", targets[2].KnowledgeBaseID)
+	require.Empty(t, targets[2].ExcludeKnowledgeIDs)
+}
+
+// emailUserRepo answers GetUsersByIDs from a fixed user-to-email map
+type emailUserRepo struct {
+	interfaces.UserRepository
+	emails map[string]string
+}
+
+func (r *emailUserRepo) GetUsersByIDs(_ context.Context, ids []string) (map[string]*types.User, error) {
+	out := map[string]*types.User{}
+	for _, id := range ids {
+		if email, ok := r.emails[id]; ok {
+			out[id] = &types.User{ID: id, Email: email}
+		}
+	}
+	return out, nil
+}
+
+func TestApplySourcePermissionsMapsReadersByEmail(t *testing.T) {
+	svc := newKnowledgeACLTestService(t)
+	svc.userRepo = &emailUserRepo{emails: map[string]string{"u1": "alice@example.com", "u2": "bob@example.com"}}
+	ctx := context.Background()
+	ds := &types.DataSource{ID: "ds-1", TenantID: 1, KnowledgeBaseID: "kb-1"}
+	deniedFor := func(userID string) []string {
+		reader := types.WithPrincipal(context.Background(), types.Principal{Type: types.PrincipalWebUser, ID: userID})
+		denied, err := svc.DeniedKnowledgeIDs(reader, []string{"kb-1"})
+		require.NoError(t, err)
+		return denied["kb-1"]
+	}
+
+	require.NoError(t, svc.ApplySourcePermissions(ctx, ds, []string{"k-open"}, &types.ItemPermissions{
+		Principals: []types.SourcePrincipal{
+			{Type: types.SourcePrincipalUser, ID: "ou_bob", Email: " Bob@Example.com"},
+			{Type: types.SourcePrincipalUser, ID: "ou_carol", Email: "carol@example.com"},
+			{Type: types.SourcePrincipalGroup, ID: "chat:oc_1"},
+		},
+	}))
+	require.NotContains(t, deniedFor("u2"), "k-open", "a reader mapped by email can read the document")
+	require.Contains(t, deniedFor("u1"), "k-open", "a member who is not a source reader is denied")
+
+	// Replacing the manual list of the document keeps the synced entries.
+	require.NoError(t, svc.repo.ReplaceTarget(ctx, "kb-1", types.KnowledgeACLTargetKnowledge, "k-open", nil))
+	require.Contains(t, deniedFor("u1"), "k-open")
+
+	// A private item without readers stays restricted.
+	require.NoError(t, svc.ApplySourcePermissions(ctx, ds, []string{"k-open"}, &types.ItemPermissions{}))
+	require.Contains(t, deniedFor("u2"), "k-open")
+
+	// Sharing the item with everyone drops the synced entries.
+	require.NoError(t, svc.ApplySourcePermissions(ctx, ds, []string{"k-open"}, &types.ItemPermissions{Public: true}))
+	require.NotContains(t, deniedFor("u1"), "k-open")
+
+	// Clearing the data source's permissions leaves manual entries alone.
+	require.NoError(t, svc.ApplySourcePermissions(ctx, ds, []string{"k-open"}, &types.ItemPermissions{}))
+	require.NoError(t, svc.ClearSourcePermissions(ctx, ds.ID))
+	require.Equal(t, []string{"k-fin-own"}, deniedFor("u1"))
+}
+
+func TestCopyKnowledgeACLKeepsDocumentsRestrictedInAnotherKnowledgeBase(t *testing.T) {
+	svc := newKnowledgeACLTestService(t)
+	ctx := context.Background()
+	u2 := types.WithPrincipal(context.WithValue(ctx, types.TenantIDContextKey, uint64(1)),
+		types.Principal{Type: types.PrincipalWebUser, ID: "u2"})
+	copyTo := func(src *types.Knowledge, kbID string) *types.Knowledge {
+		dst := *src
+		dst.KnowledgeBaseID = kbID
+		require.NoError(t, svc.CopyKnowledgeACL(ctx, src, &dst))
+		return &dst
+	}
+
+	// A move keeps the document ID: both its own list and a list it inherits
+	// from its folder follow it, while an unrestricted document stays open.
+	copyTo(&types.Knowledge{ID: "k-fin-own", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance"}, "kb-2")
+	copyTo(&types.Knowledge{ID: "k-fin", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance"}, "kb-2")
+	copyTo(&types.Knowledge{ID: "k-open", TenantID: 1, KnowledgeBaseID: "kb-1"}, "kb-2")
+	denied, err := svc.DeniedKnowledgeIDs(u2, []string{"kb-2"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"k-fin"}, denied["kb-2"])
+
+	// A clone whose folder lists were copied inherits the same readers, so
+	// no document list is added on top of them.
+	require.NoError(t, svc.CopyFolderACLs(ctx, "kb-1", &types.KnowledgeBase{ID: "kb-3", TenantID: 1}))
+	copyTo(&types.Knowledge{ID: "k-fin-sub", TenantID: 1, KnowledgeBaseID: "kb-1", FolderPath: "finance/q1"}, "kb-3")
+	own, err := svc.repo.ListByTarget(ctx, "kb-3", types.KnowledgeACLTargetKnowledge, "k-fin-sub")
+	require.NoError(t, err)
+	require.Empty(t, own)
+	folder, err := svc.repo.ListByTarget(ctx, "kb-3", types.KnowledgeACLTargetFolder, "finance")
+	require.NoError(t, err)
+	require.Len(t, folder, 1)
+
+	require.NoError(t, svc.DeleteKnowledgeACLs(ctx, "kb-2", []string{"k-fin", "k-fin-own"}))
+	denied, err = svc.DeniedKnowledgeIDs(u2, []string{"kb-2"})
+	require.NoError(t, err)
+	require.Empty(t, denied["kb-2"])
+	require.NoError(t, svc.DeleteKnowledgeBaseACLs(ctx, "kb-3"))
+	folder, err = svc.repo.ListByTarget(ctx, "kb-3", types.KnowledgeACLTargetFolder, "finance")
+	require.NoError(t, err)
+	require.Empty(t, folder)
+}
@@ -12,7 +12,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
@@ -421,11 +420,14 @@ func (s *knowledgeService) CloneChunk(ctx context.Context, src, dst *types.Knowl
 	// source/target KBs to bind to different stores must perform their own
 	// cross-store migration before invoking this.
 	var sourceStoreID *string
+	sourceIndexKBID := src.KnowledgeBaseID
 	if srcKB, loadErr := s.kbService.GetKnowledgeBaseByID(ctx, src.KnowledgeBaseID); loadErr == nil && srcKB != nil {
 		sourceStoreID = srcKB.VectorStoreID
+		sourceIndexKBID = srcKB.IndexKnowledgeBaseID()
 	}
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantID, sourceStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, sourceStoreID,
+		src.KnowledgeBaseID, dst.KnowledgeBaseID)
 	if err != nil {
 		return err
 	}
@@ -433,7 +435,7 @@ func (s *knowledgeService) CloneChunk(ctx context.Context, src, dst *types.Knowl
 	if err != nil {
 		return err
 	}
-	if err := retrieveEngine.CopyIndices(ctx, src.KnowledgeBaseID, dst.KnowledgeBaseID,
+	if err := retrieveEngine.CopyIndices(ctx, sourceIndexKBID, dstKB.IndexKnowledgeBaseID(),
 		map[string]string{src.ID: dst.ID},
 		srcTodst,
 		embeddingModel.GetDimensions(),
@@ -465,6 +467,9 @@ func (s *knowledgeService) ProcessKBClone(ctx context.Context, t *asynq.Task) er
 
 	// Add tenant ID to context
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.SourceID, payload.TargetID); err != nil {
+		return err
+	}
 
 	// Get tenant info and add to context
 	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
@@ -733,8 +738,9 @@ func (s *knowledgeService) cloneFAQKnowledgeBase(
 	if srcKB != nil {
 		sourceStoreID = srcKB.VectorStoreID
 	}
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, types.MustTenantIDFromContext(ctx), sourceStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, types.MustTenantIDFromContext(ctx), sourceStoreID,
+		srcKB.ID, dstKB.ID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to init retrieve engine: %v", err)
 		handleError(progress, err, "Failed to initialize retrieve engine")
@@ -911,7 +917,8 @@ func (s *knowledgeService) cloneFAQKnowledgeBase(
 }
 
 // getOrCreateFAQKnowledge gets or creates the FAQ knowledge entry for a knowledge base
-// If srcKnowledge is provided, it will copy relevant fields from source when creating new knowledge
+// If srcKnowledge is provided, it will copy relevant fields from source when creating new knowledge,
+// and the entry takes the source's access list since the source's entries are cloned into it
 func (s *knowledgeService) getOrCreateFAQKnowledge(ctx context.Context, kb *types.KnowledgeBase, srcKnowledge *types.Knowledge) (*types.Knowledge, error) {
 	// FAQ knowledge base should have exactly one Knowledge entry
 	knowledgeList, err := s.repo.ListKnowledgeByKnowledgeBaseID(ctx, kb.TenantID, kb.ID)
@@ -920,6 +927,9 @@ func (s *knowledgeService) getOrCreateFAQKnowledge(ctx context.Context, kb *type
 	}
 
 	if len(knowledgeList) > 0 {
+		if err := s.copyFAQKnowledgeACL(ctx, srcKnowledge, knowledgeList[0]); err != nil {
+			return nil, err
+		}
 		return knowledgeList[0], nil
 	}
 
@@ -948,9 +958,24 @@ func (s *knowledgeService) getOrCreateFAQKnowledge(ctx context.Context, kb *type
 	if err := s.repo.CreateKnowledge(ctx, knowledge); err != nil {
 		return nil, err
 	}
+	if err := s.copyFAQKnowledgeACL(ctx, srcKnowledge, knowledge); err != nil {
+		return nil, err
+	}
 	return knowledge, nil
 }
 
+// copyFAQKnowledgeACL restricts the FAQ entry a clone writes into like the
+// source FAQ entry
+func (s *knowledgeService) copyFAQKnowledgeACL(ctx context.Context, src, dst *types.Knowledge) error {
+	if s.knowledgeACL == nil || src == nil {
+		return nil
+	}
+	if err := s.knowledgeACL.CopyKnowledgeACL(ctx, src, dst); err != nil {
+		return fmt.Errorf("copy FAQ access list: %w", err)
+	}
+	return nil
+}
+
 // saveKBCloneProgress saves the KB clone progress to Redis
 func (s *knowledgeService) saveKBCloneProgress(ctx context.Context, progress *types.KBCloneProgress) error {
 	key := getKBCloneProgressKey(progress.TaskID)
@@ -1036,6 +1061,9 @@ func (s *knowledgeService) ProcessKnowledgeMove(ctx context.Context, t *asynq.Ta
 
 	// Add tenant ID to context
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.SourceKBID, payload.TargetKBID); err != nil {
+		return err
+	}
 
 	// Get tenant info and add to context
 	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
@@ -1193,6 +1221,18 @@ func (s *knowledgeService) moveOneKnowledge(
 				"(source KB %s, target KB %s); use reparse mode", sourceKB.ID, targetKB.ID)
 	}
 
+	// Access lists are kept per knowledge base. Give the document its list in
+	// the target before any of its chunks land there; the source entries are
+	// dropped once the move succeeded.
+	if s.knowledgeACL != nil {
+		moved := *knowledge
+		moved.TenantID = targetKB.TenantID
+		moved.KnowledgeBaseID = targetKB.ID
+		if err := s.knowledgeACL.CopyKnowledgeACL(ctx, knowledge, &moved); err != nil {
+			return fmt.Errorf("failed to move access list of knowledge %s: %w", knowledgeID, err)
+		}
+	}
+
 	// Mark as processing during move
 	knowledge.ParseStatus = types.ParseStatusProcessing
 	if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
@@ -1220,12 +1260,15 @@ func (s *knowledgeService) moveOneKnowledge(
 		if targetKB.IsWikiEnabled() {
 			EnqueueWikiIngest(ctx, s.task, s.taskPendingRepo, tenantID, targetKB.ID, knowledge.ID)
 		}
-		return nil
 	case "reparse":
-		return s.moveKnowledgeReparse(ctx, knowledge, sourceKB, targetKB)
+		if err := s.moveKnowledgeReparse(ctx, knowledge, sourceKB, targetKB); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unknown move mode: %s", mode)
 	}
+	s.deleteKnowledgeACLs(ctx, sourceKB.ID, []string{knowledge.ID})
+	return nil
 }
 
 // moveKnowledgeReuseVectors moves knowledge by copying vector indices and updating DB references.
@@ -1270,8 +1313,9 @@ func (s *knowledgeService) moveKnowledgeReuseVectors(
 		if sourceKB != nil {
 			sourceStoreID = sourceKB.VectorStoreID
 		}
-		retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-			ctx, s.retrieveEngine, s.ownership, tenantID, sourceStoreID)
+		retrieveEngine, err := createIndexWriterForKB(
+			ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, sourceStoreID,
+			sourceKB.ID, targetKB.ID)
 		if err != nil {
 			return fmt.Errorf("failed to init retrieve engine: %w", err)
 		}
@@ -1282,7 +1326,7 @@ func (s *knowledgeService) moveKnowledgeReuseVectors(
 
 		// Copy indices from source KB to target KB
 		knowledgeIDMapping := map[string]string{knowledge.ID: knowledge.ID}
-		if err := retrieveEngine.CopyIndices(ctx, sourceKB.ID, targetKB.ID,
+		if err := retrieveEngine.CopyIndices(ctx, sourceKB.IndexKnowledgeBaseID(), targetKB.IndexKnowledgeBaseID(),
 			knowledgeIDMapping, chunkIDMapping,
 			embeddingModel.GetDimensions(), sourceKB.Type,
 		); err != nil {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/infrastructure/chunker"
 	"github.com/Tencent/WeKnora/internal/infrastructure/docparser"
 	"github.com/Tencent/WeKnora/internal/logger"
@@ -197,6 +198,7 @@ func (s *knowledgeService) CreateKnowledgeFromFile(ctx context.Context,
 		}
 		return nil, err
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeCreated, knowledge)
 	// Set tag relations
 	if err := s.setAndAttachKnowledgeTags(ctx, tenantID, kbID, knowledge, tagIDs); err != nil {
 		logger.Errorf(ctx, "Failed to set knowledge tags, knowledge ID: %s, error: %v", knowledge.ID, err)
@@ -396,6 +398,7 @@ func (s *knowledgeService) CreateKnowledgeFromURL(ctx context.Context,
 		logger.Errorf(ctx, "Failed to create knowledge record: %v", err)
 		return nil, err
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeCreated, knowledge)
 	// Set tag relations
 	if err := s.setAndAttachKnowledgeTags(ctx, tenantID, kbID, knowledge, tagIDs); err != nil {
 		logger.Errorf(ctx, "Failed to set knowledge tags, knowledge ID: %s, error: %v", knowledge.ID, err)
@@ -639,6 +642,7 @@ func (s *knowledgeService) createKnowledgeFromFileURL(
 		logger.Errorf(ctx, "Failed to create knowledge record: %v", err)
 		return nil, err
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeCreated, knowledge)
 	// Set tag relations
 	if err := s.setAndAttachKnowledgeTags(ctx, tenantID, kbID, knowledge, tagIDs); err != nil {
 		logger.Errorf(ctx, "Failed to set knowledge tags, knowledge ID: %s, error: %v", knowledge.ID, err)
@@ -810,6 +814,7 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 		logger.Errorf(ctx, "Failed to create manual knowledge record: %v", err)
 		return nil, err
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeCreated, knowledge)
 	// Set tag relations
 	if err := s.setAndAttachKnowledgeTags(ctx, tenantID, kbID, knowledge, payload.TagIDs); err != nil {
 		logger.Errorf(ctx, "Failed to set knowledge tags, knowledge ID: %s, error: %v", knowledge.ID, err)
@@ -825,6 +830,7 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 			knowledge.ParseStatus = "failed"
 			knowledge.ErrorMessage = "Failed to enqueue processing task"
 			s.repo.UpdateKnowledge(ctx, knowledge)
+			publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, knowledge)
 			recordKBActivity(ctx, s.audit, tenantID, kbID, types.AuditActionKnowledgeCreated,
 				"knowledge", knowledge.ID, types.AuditOutcomeFailed, map[string]any{
 					"title": knowledge.Title, "source_type": "manual", "status": status,
@@ -903,6 +909,7 @@ func (s *knowledgeService) createKnowledgeFromPassageInternal(ctx context.Contex
 		logger.Errorf(ctx, "Failed to create knowledge record: %v", err)
 		return nil, err
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeCreated, knowledge)
 	// Process passages
 	if syncMode {
 		logger.Info(ctx, "Processing passage synchronously")
@@ -1092,6 +1099,7 @@ func (s *knowledgeService) UpdateManualKnowledge(ctx context.Context,
 		existing.ParseStatus = "failed"
 		existing.ErrorMessage = "Failed to enqueue processing task"
 		s.repo.UpdateKnowledge(ctx, existing)
+		publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, existing)
 		recordKBActivity(ctx, s.audit, tenantID, existing.KnowledgeBaseID, types.AuditActionKnowledgeUpdated,
 			"knowledge", existing.ID, types.AuditOutcomeFailed, map[string]any{
 				"title": existing.Title, "status": status,
@@ -1147,7 +1155,9 @@ func (s *knowledgeService) markKnowledgeEnqueueFailed(ctx context.Context, knowl
 	knowledge.ErrorMessage = "Failed to enqueue processing task"
 	if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
 		logger.Errorf(ctx, "Failed to mark knowledge as failed after enqueue error: %v", err)
+		return
 	}
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, knowledge)
 }
 
 func ensureManualFileName(title string) string {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -62,6 +63,9 @@ func (s *knowledgeService) DeleteKnowledge(ctx context.Context, id string) error
 	if err != nil {
 		return err
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, knowledge.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	// Mark as deleting first to prevent async task conflicts
 	// This ensures that any running async tasks will detect the deletion and abort
@@ -115,12 +119,14 @@ func (s *knowledgeService) DeleteKnowledge(ctx context.Context, id string) error
 			if kb != nil {
 				boundStoreID = kb.VectorStoreID
 			}
-			retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
+			retrieveEngine, err := createIndexWriterForKB(
 				ctx,
 				s.retrieveEngine,
 				s.ownership,
+				s.kbService,
 				tenantID,
 				boundStoreID,
+				knowledge.KnowledgeBaseID,
 			)
 			if err != nil {
 				logger.GetLogger(ctx).WithField("error", err).Errorf("DeleteKnowledge delete knowledge embedding failed")
@@ -183,6 +189,8 @@ func (s *knowledgeService) DeleteKnowledge(ctx context.Context, id string) error
 	if err := s.repo.DeleteKnowledge(ctx, tenantID, id); err != nil {
 		return err
 	}
+	s.deleteKnowledgeACLs(ctx, knowledge.KnowledgeBaseID, []string{id})
+	s.scheduleCommunityRebuild(ctx, tenantID, knowledge.KnowledgeBaseID)
 
 	// Best-effort physical cleanup. Errors here only leak storage; they must not
 	// fail the delete now that the row is already gone.
@@ -200,6 +208,7 @@ func (s *knowledgeService) DeleteKnowledge(ctx context.Context, id string) error
 	recordKBActivity(ctx, s.audit, tenantID, knowledge.KnowledgeBaseID, types.AuditActionKnowledgeDeleted,
 		"knowledge", knowledge.ID, types.AuditOutcomeSuccess,
 		map[string]any{"title": knowledge.Title, "type": knowledge.Type})
+	publishKnowledgeLifecycle(ctx, event.EventKnowledgeDeleted, knowledge)
 	return nil
 }
 
@@ -498,6 +507,13 @@ func (s *knowledgeService) DeleteKnowledgeList(ctx context.Context, ids []string
 	if err != nil {
 		return err
 	}
+	kbIDs := make([]string, 0, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		kbIDs = append(kbIDs, knowledge.KnowledgeBaseID)
+	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, kbIDs...); err != nil {
+		return err
+	}
 
 	// Mark all as deleting first to prevent async task conflicts.
 	// Remember which entries still had queued / in-flight downstream tasks
@@ -679,7 +695,10 @@ func (s *knowledgeService) DeleteKnowledgeList(ctx context.Context, ids []string
 		for _, knowledge := range knowledges {
 			knowledgeIDs = append(knowledgeIDs, knowledge.ID)
 			titles = append(titles, knowledge.Title)
+			publishKnowledgeLifecycle(ctx, event.EventKnowledgeDeleted, knowledge)
 		}
+		s.deleteKnowledgeACLs(ctx, kbID, knowledgeIDs)
+		s.scheduleCommunityRebuild(ctx, tenantInfo.ID, kbID)
 		details := map[string]any{"count": len(knowledgeIDs)}
 		if len(knowledgeIDs) <= 20 {
 			details["knowledge_ids"] = knowledgeIDs
@@ -691,6 +710,31 @@ func (s *knowledgeService) DeleteKnowledgeList(ctx context.Context, ids []string
 	return nil
 }
 
+// deleteKnowledgeACLs drops the access lists of deleted documents. It runs
+// after the rows are gone, so a failure only leaves unused entries behind.
+// A deletion marked by types.WithKnowledgeACLKept leaves them for the
+// replacement document.
+func (s *knowledgeService) deleteKnowledgeACLs(ctx context.Context, kbID string, knowledgeIDs []string) {
+	if s.knowledgeACL == nil || types.IsKnowledgeACLKept(ctx) {
+		return
+	}
+	if err := s.knowledgeACL.DeleteKnowledgeACLs(ctx, kbID, knowledgeIDs); err != nil {
+		logger.Warnf(ctx, "Failed to delete access lists of %d knowledge in KB %s: %v", len(knowledgeIDs), kbID, err)
+	}
+}
+
+// scheduleCommunityRebuild refreshes the graph communities of a knowledge
+// base whose graph lost a document's entities and relations, so global
+// retrieval stops summarizing them.
+func (s *knowledgeService) scheduleCommunityRebuild(ctx context.Context, tenantID uint64, kbID string) {
+	if s.communityService == nil {
+		return
+	}
+	if err := s.communityService.ScheduleRebuild(ctx, tenantID, kbID); err != nil {
+		logger.Warnf(ctx, "Failed to schedule graph community rebuild for KB %s: %v", kbID, err)
+	}
+}
+
 func (s *knowledgeService) cleanupKnowledgeResources(ctx context.Context, knowledge *types.Knowledge) error {
 	logger.GetLogger(ctx).Infof("Cleaning knowledge resources before manual update, knowledge ID: %s", knowledge.ID)
 
@@ -716,8 +760,9 @@ func (s *knowledgeService) cleanupKnowledgeResources(ctx context.Context, knowle
 			logger.GetLogger(ctx).WithField("error", loadErr).WithField("knowledge_base_id", knowledge.KnowledgeBaseID).
 				Warnf("cleanupKnowledgeResources: failed to load KB for vector store resolution; falling back to tenant effective engines")
 		}
-		retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-			ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, boundStoreID)
+		retrieveEngine, err := createIndexWriterForKB(
+			ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, boundStoreID,
+			knowledge.KnowledgeBaseID)
 		if err != nil {
 			logger.GetLogger(ctx).WithField("error", err).Error("Failed to init retrieve engine during cleanup")
 			cleanupErr = errors.Join(cleanupErr, err)
@@ -761,6 +806,8 @@ func (s *knowledgeService) cleanupKnowledgeResources(ctx context.Context, knowle
 	if err := s.graphEngine.DelGraph(ctx, []types.NameSpace{namespace}); err != nil {
 		logger.GetLogger(ctx).WithField("error", err).Error("Failed to delete manual knowledge graph data")
 		cleanupErr = errors.Join(cleanupErr, err)
+	} else {
+		s.scheduleCommunityRebuild(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID)
 	}
 
 	if knowledge.StorageSize > 0 {
@@ -11,7 +11,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
@@ -141,7 +140,7 @@ func (s *knowledgeService) CreateFAQEntry(ctx context.Context,
 		return nil, werrors.NewBadRequestError("请求体不能为空")
 	}
 
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return nil, err
 	}
@@ -342,7 +341,7 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 	if payload == nil {
 		return nil, werrors.NewBadRequestError("请求体不能为空")
 	}
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return nil, err
 	}
@@ -450,8 +449,8 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 		oldSimilarQuestionCount := len(oldSimilarQuestions)
 		newSimilarQuestionCount := len(meta.SimilarQuestions)
 		if questionIndexMode == types.FAQQuestionIndexModeSeparate && oldSimilarQuestionCount > newSimilarQuestionCount {
-			retrieveEngine, engineErr := retriever.CreateRetrieveEngineForKB(
-				ctx, s.retrieveEngine, s.ownership, types.MustTenantIDFromContext(ctx), kb.VectorStoreID)
+			retrieveEngine, engineErr := createIndexWriterForKB(
+				ctx, s.retrieveEngine, s.ownership, s.kbService, types.MustTenantIDFromContext(ctx), kb.VectorStoreID, kb.ID)
 			if engineErr == nil {
 				sourceIDsToDelete := make([]string, 0, oldSimilarQuestionCount-newSimilarQuestionCount)
 				for i := newSimilarQuestionCount; i < oldSimilarQuestionCount; i++ {
@@ -510,7 +509,7 @@ func (s *knowledgeService) AddSimilarQuestions(ctx context.Context,
 		return nil, werrors.NewBadRequestError("相似问列表不能为空")
 	}
 
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return nil, err
 	}
@@ -655,7 +654,7 @@ func (s *knowledgeService) AddSimilarQuestions(ctx context.Context,
 func (s *knowledgeService) UpdateFAQEntryStatus(ctx context.Context,
 	kbID string, entryID string, isEnabled bool,
 ) error {
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return err
 	}
@@ -678,8 +677,8 @@ func (s *knowledgeService) UpdateFAQEntryStatus(ctx context.Context,
 
 	// Sync update to retriever engines
 	chunkStatusMap := map[string]bool{chunk.ID: isEnabled}
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -704,7 +703,7 @@ func (s *knowledgeService) UpdateFAQEntryFieldsBatch(ctx context.Context,
 	if req == nil || (len(req.ByID) == 0 && len(req.ByTag) == 0) {
 		return nil
 	}
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return err
 	}
@@ -875,8 +874,8 @@ func (s *knowledgeService) UpdateFAQEntryFieldsBatch(ctx context.Context,
 
 	// Sync to retriever engines
 	if len(enabledUpdates) > 0 || len(tagUpdates) > 0 {
-		retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-			ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+		retrieveEngine, err := createIndexWriterForKB(
+			ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, kb.VectorStoreID, kb.ID)
 		if err != nil {
 			return err
 		}
@@ -900,7 +899,7 @@ func (s *knowledgeService) UpdateFAQEntryFieldsBatch(ctx context.Context,
 
 // UpdateFAQEntryTag updates the tag assigned to an FAQ entry.
 func (s *knowledgeService) UpdateFAQEntryTag(ctx context.Context, kbID string, entryID string, tagID *string) error {
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return err
 	}
@@ -937,8 +936,8 @@ func (s *knowledgeService) UpdateFAQEntryTag(ctx context.Context, kbID string, e
 	}
 
 	// Sync tag update to retriever engines
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -951,7 +950,7 @@ func (s *knowledgeService) UpdateFAQEntryTagBatch(ctx context.Context, kbID stri
 	if len(updates) == 0 {
 		return nil
 	}
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return err
 	}
@@ -1035,8 +1034,8 @@ func (s *knowledgeService) UpdateFAQEntryTagBatch(ctx context.Context, kbID stri
 		for _, chunk := range chunksToUpdate {
 			tagUpdates[chunk.ID] = chunk.TagID
 		}
-		retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-			ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+		retrieveEngine, err := createIndexWriterForKB(
+			ctx, s.retrieveEngine, s.ownership, s.kbService, tenantID, kb.VectorStoreID, kb.ID)
 		if err != nil {
 			return err
 		}
@@ -1389,7 +1388,7 @@ func (s *knowledgeService) DeleteFAQEntries(ctx context.Context,
 	if len(entrySeqIDs) == 0 {
 		return werrors.NewBadRequestError("请选择需要删除的 FAQ 条目")
 	}
-	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	kb, err := s.validateFAQKnowledgeBaseForWrite(ctx, kbID)
 	if err != nil {
 		return err
 	}
@@ -1637,6 +1636,23 @@ func (s *knowledgeService) validateFAQKnowledgeBase(ctx context.Context, kbID st
 	return kb, nil
 }
 
+// validateFAQKnowledgeBaseForWrite is validateFAQKnowledgeBase for edits,
+// which the knowledge base refuses while it moves to another vector store or
+// embedding model.
+func (s *knowledgeService) validateFAQKnowledgeBaseForWrite(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if kb.VectorStoreMigration.Active() {
+		return nil, ErrVectorStoreMigrating
+	}
+	if kb.EmbeddingMigration.Active() {
+		return nil, ErrEmbeddingModelMigrating
+	}
+	return kb, nil
+}
+
 func (s *knowledgeService) findFAQKnowledge(
 	ctx context.Context,
 	tenantID uint64,
@@ -2020,8 +2036,7 @@ func buildFAQIndexContent(meta *types.FAQChunkMetadata, mode types.FAQIndexMode)
 }
 
 // buildFAQIndexInfoList 构建FAQ索引信息列表，支持分别索引模式
-func (s *knowledgeService) buildFAQIndexInfoList(
-	ctx context.Context,
+func buildFAQIndexInfoList(
 	kb *types.KnowledgeBase,
 	chunk *types.Chunk,
 ) ([]*types.IndexInfo, error) {
@@ -2054,7 +2069,7 @@ func (s *knowledgeService) buildFAQIndexInfoList(
 				SourceType:      types.ChunkSourceType,
 				ChunkID:         chunk.ID,
 				KnowledgeID:     chunk.KnowledgeID,
-				KnowledgeBaseID: chunk.KnowledgeBaseID,
+				KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 				KnowledgeType:   types.KnowledgeTypeFAQ,
 				TagID:           chunk.TagID,
 				IsEnabled:       chunk.IsEnabled,
@@ -2083,7 +2098,7 @@ func (s *knowledgeService) buildFAQIndexInfoList(
 		SourceType:      types.ChunkSourceType,
 		ChunkID:         chunk.ID,
 		KnowledgeID:     chunk.KnowledgeID,
-		KnowledgeBaseID: chunk.KnowledgeBaseID,
+		KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 		KnowledgeType:   types.KnowledgeTypeFAQ,
 		TagID:           chunk.TagID,
 		IsEnabled:       chunk.IsEnabled,
@@ -2109,7 +2124,7 @@ func (s *knowledgeService) buildFAQIndexInfoList(
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
-			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 			KnowledgeType:   types.KnowledgeTypeFAQ,
 			TagID:           chunk.TagID,
 			IsEnabled:       chunk.IsEnabled,
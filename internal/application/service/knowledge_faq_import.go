@@ -11,7 +11,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/embedding"
@@ -1829,8 +1828,8 @@ func (s *knowledgeService) incrementalIndexFAQEntry(
 	logger.Debugf(ctx, "incrementalIndexFAQEntry: starting for chunk=%s, oldSimilarQuestions=%d, newSimilarQuestions=%d",
 		chunk.ID, len(oldSimilarQuestions), len(newMeta.SimilarQuestions))
 
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, types.MustTenantIDFromContext(ctx), kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, types.MustTenantIDFromContext(ctx), kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -1888,7 +1887,7 @@ func (s *knowledgeService) incrementalIndexFAQEntry(
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
-			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 			KnowledgeType:   types.KnowledgeTypeFAQ,
 			TagID:           chunk.TagID,
 			IsEnabled:       chunk.IsEnabled,
@@ -1935,7 +1934,7 @@ func (s *knowledgeService) incrementalIndexFAQEntry(
 				SourceType:      types.ChunkSourceType,
 				ChunkID:         chunk.ID,
 				KnowledgeID:     chunk.KnowledgeID,
-				KnowledgeBaseID: chunk.KnowledgeBaseID,
+				KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 				KnowledgeType:   types.KnowledgeTypeFAQ,
 				TagID:           chunk.TagID,
 				IsEnabled:       chunk.IsEnabled,
@@ -2007,8 +2006,8 @@ func (s *knowledgeService) indexFAQChunks(ctx context.Context,
 	logger.Debugf(ctx, "indexFAQChunks: starting to index %d chunks", len(chunks))
 
 	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -2018,7 +2017,7 @@ func (s *knowledgeService) indexFAQChunks(ctx context.Context,
 	indexInfo := make([]*types.IndexInfo, 0)
 	chunkIDs := make([]string, 0, len(chunks))
 	for _, chunk := range chunks {
-		infoList, err := s.buildFAQIndexInfoList(ctx, kb, chunk)
+		infoList, err := buildFAQIndexInfoList(kb, chunk)
 		if err != nil {
 			return err
 		}
@@ -2119,8 +2118,8 @@ func (s *knowledgeService) deleteFAQChunkVectors(ctx context.Context,
 		return err
 	}
 	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		return err
 	}
@@ -2128,7 +2127,7 @@ func (s *knowledgeService) deleteFAQChunkVectors(ctx context.Context,
 	indexInfo := make([]*types.IndexInfo, 0)
 	chunkIDs := make([]string, 0, len(chunks))
 	for _, chunk := range chunks {
-		infoList, err := s.buildFAQIndexInfoList(ctx, kb, chunk)
+		infoList, err := buildFAQIndexInfoList(kb, chunk)
 		if err != nil {
 			return err
 		}
@@ -2230,6 +2229,9 @@ func (s *knowledgeService) ProcessFAQImport(ctx context.Context, t *asynq.Task)
 	ctx = logger.WithRequestID(ctx, uuid.New().String())
 	ctx = logger.WithField(ctx, "faq_import", payload.TaskID)
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KBID); err != nil {
+		return err
+	}
 
 	// 获取任务重试信息，用于判断是否是最后一次重试
 	retryCount, _ := asynq.GetRetryCount(ctx)
@@ -2408,8 +2410,8 @@ func (s *knowledgeService) ProcessFAQImport(ctx context.Context, t *asynq.Task)
 		// 删除索引数据
 		embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
 		if err == nil {
-			retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-				ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+			retrieveEngine, err := createIndexWriterForKB(
+				ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 			if err == nil {
 				chunkIDs := make([]string, 0, len(chunksDeleted))
 				for _, chunk := range chunksDeleted {
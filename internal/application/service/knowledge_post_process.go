@@ -274,6 +274,7 @@ func (s *KnowledgePostProcessService) Handle(ctx context.Context, task *asynq.Ta
 		} else {
 			logger.Infof(ctx, "[KnowledgePostProcess] Knowledge %s marked completed (no enrichment subtasks).",
 				payload.KnowledgeID)
+			publishKnowledgeIngested(ctx, knowledge)
 		}
 	default:
 		// Flip processing to finalizing before fan-out so a parallel
@@ -450,7 +451,10 @@ func (s *KnowledgePostProcessService) Handle(ctx context.Context, task *asynq.Ta
 			for i := 0; i < shortfall; i++ {
 				rctx, cancel := context.WithTimeout(
 					context.WithoutCancel(ctx), finalizeSubtaskDetachedTimeout)
-				_, _, err := s.knowledgeRepo.FinalizeSubtask(rctx, payload.KnowledgeID)
+				_, promoted, err := s.knowledgeRepo.FinalizeSubtask(rctx, payload.KnowledgeID)
+				if err == nil && promoted {
+					publishKnowledgeIngestedByID(rctx, s.knowledgeRepo, payload.KnowledgeID)
+				}
 				cancel()
 				if err != nil {
 					logger.Warnf(ctx, "[KnowledgePostProcess] Failed to release subtask slot for %s: %v",
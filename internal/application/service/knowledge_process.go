@@ -10,8 +10,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/infrastructure/chunker"
 	"github.com/Tencent/WeKnora/internal/infrastructure/docparser"
 	"github.com/Tencent/WeKnora/internal/logger"
@@ -99,6 +99,14 @@ func (s *knowledgeService) cloneKnowledge(
 		logger.GetLogger(ctx).WithField("error", err).Errorf("MoveKnowledge create knowledge failed")
 		return
 	}
+	// The copy must be as restricted as the source before any chunk of it
+	// becomes searchable.
+	if s.knowledgeACL != nil {
+		if err = s.knowledgeACL.CopyKnowledgeACL(ctx, src, dst); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("MoveKnowledge copy access list failed")
+			return
+		}
+	}
 	tenantInfo.StorageUsed += dst.StorageSize
 	if err = s.tenantRepo.AdjustStorageUsed(ctx, tenantInfo.ID, dst.StorageSize); err != nil {
 		logger.GetLogger(ctx).WithField("error", err).Errorf("MoveKnowledge update tenant storage used failed")
@@ -283,8 +291,8 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 
 	// 删除旧的索引数据 — only when vector/keyword indexing is enabled
 	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 	if err == nil && embeddingModel != nil {
 		if err := retrieveEngine.DeleteByKnowledgeIDList(ctx, []string{knowledge.ID}, embeddingModel.GetDimensions(), knowledge.Type); err != nil {
 			logger.Warnf(ctx, "Failed to delete existing index data (may not exist): %v", err)
@@ -299,6 +307,8 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 	if err := s.graphEngine.DelGraph(ctx, []types.NameSpace{namespace}); err != nil {
 		logger.Warnf(ctx, "Failed to delete existing graph data (may not exist): %v", err)
 		// 不返回错误，继续处理
+	} else {
+		s.scheduleCommunityRebuild(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID)
 	}
 
 	logger.Infof(ctx, "Cleanup completed, starting to process new chunks")
@@ -511,6 +521,7 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		// Prepend the document title to improve semantic alignment between
 		// question-style queries and statement-style chunk content.
 		indexInfoList := make([]*types.IndexInfo, 0, len(textChunks))
+		indexMetadata := knowledge.IndexMetadata()
 		for _, chunk := range textChunks {
 			// chunk.EmbeddingContent prepends ContextHeader (heading breadcrumb)
 			// when the chunker populated it during Tier-1 splitting; falls back
@@ -523,8 +534,9 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 				SourceType:      types.ChunkSourceType,
 				ChunkID:         chunk.ID,
 				KnowledgeID:     knowledge.ID,
-				KnowledgeBaseID: knowledge.KnowledgeBaseID,
+				KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 				IsEnabled:       true,
+				Metadata:        indexMetadata,
 			})
 		}
 
@@ -999,6 +1011,9 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 	if payload.Language != "" {
 		ctx = context.WithValue(ctx, types.LanguageContextKey, payload.Language)
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	// A newer attempt (re-upload / edit / reparse) has superseded this one:
 	// skip before opening the span or registering the FinalizeSubtask defer
@@ -1316,8 +1331,8 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 		}
 		ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
 
-		retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-			ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+		retrieveEngine, err := createIndexWriterForKB(
+			ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 		if err != nil {
 			logger.Errorf(ctx, "Failed to init retrieve engine: %v", err)
 			summaryErr = err
@@ -1337,8 +1352,9 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         summaryChunk.ID,
 			KnowledgeID:     knowledge.ID,
-			KnowledgeBaseID: knowledge.KnowledgeBaseID,
+			KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 			IsEnabled:       true,
+			Metadata:        knowledge.IndexMetadata(),
 		}}
 
 		if err := retrieveEngine.BatchIndex(ctx, embeddingModel, indexInfo); err != nil {
@@ -1367,6 +1383,9 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 		logger.Errorf(ctx, "Failed to unmarshal question generation payload: %v", err)
 		return nil // Don't retry on unmarshal error
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
 	if len(payload.ChunkIDs) > 0 || payload.ChunkID != "" {
 		return s.processQuestionGenerationForChunks(ctx, t, payload)
 	}
@@ -1609,8 +1628,8 @@ func (s *knowledgeService) processQuestionGenerationForKnowledge(ctx context.Con
 	}
 	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
 
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		exitStatus = "init_retrieve_engine_failed"
 		logger.Errorf(ctx, "Failed to init retrieve engine: %v", err)
@@ -1723,8 +1742,9 @@ func (s *knowledgeService) processQuestionGenerationForKnowledge(ctx context.Con
 				SourceType:      types.ChunkSourceType,
 				ChunkID:         chunk.ID,
 				KnowledgeID:     knowledge.ID,
-				KnowledgeBaseID: knowledge.KnowledgeBaseID,
+				KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 				IsEnabled:       true,
+				Metadata:        knowledge.IndexMetadata(),
 			})
 		}
 		logger.Debugf(ctx, "Generated %d questions for chunk %s", len(questions), chunk.ID)
@@ -1919,8 +1939,8 @@ func (s *knowledgeService) processQuestionGenerationForChunks(ctx context.Contex
 	}
 	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
 
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, tenantInfo.ID, kb.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, tenantInfo.ID, kb.VectorStoreID, kb.ID)
 	if err != nil {
 		exitStatus = "init_retrieve_engine_failed"
 		logger.Errorf(ctx, "Failed to init retrieve engine: %v", err)
@@ -2052,8 +2072,9 @@ func (s *knowledgeService) processQuestionGenerationForChunks(ctx context.Contex
 				SourceType:      types.ChunkSourceType,
 				ChunkID:         chunk.ID,
 				KnowledgeID:     knowledge.ID,
-				KnowledgeBaseID: knowledge.KnowledgeBaseID,
+				KnowledgeBaseID: kb.IndexKnowledgeBaseID(),
 				IsEnabled:       true,
+				Metadata:        knowledge.IndexMetadata(),
 			})
 		}
 	}
@@ -2402,6 +2423,9 @@ func (s *knowledgeService) ReparseKnowledge(
 		logger.Errorf(ctx, "Failed to load knowledge: %v", err)
 		return nil, err
 	}
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, existing.KnowledgeBaseID); err != nil {
+		return nil, err
+	}
 
 	// Allocate a fresh span tree attempt up front. Doing this BEFORE
 	// the cleanup + enqueue means: (a) the UI immediately sees a new
@@ -2848,9 +2872,10 @@ func (s *knowledgeService) updateChunkVector(ctx context.Context, kbID string, c
 			SourceType:      types.ChunkSourceType,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
-			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			KnowledgeBaseID: sourceKB.IndexKnowledgeBaseID(),
 			KnowledgeType:   sourceKB.Type,
 			IsEnabled:       chunk.IsEnabled,
+			Metadata:        knowledge.IndexMetadata(),
 		})
 		meta, metaErr := chunk.DocumentMetadata()
 		if metaErr != nil {
@@ -2862,16 +2887,18 @@ func (s *knowledgeService) updateChunkVector(ctx context.Context, kbID string, c
 					indexInfo = append(indexInfo, &types.IndexInfo{
 						Content: buildKnowledgeIndexContent(knowledge, q.Question), SourceID: types.GeneratedQuestionSourceID(chunk.ID, q.ID),
 						SourceType: types.ChunkSourceType, ChunkID: chunk.ID,
-						KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: chunk.KnowledgeBaseID,
+						KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: sourceKB.IndexKnowledgeBaseID(),
 						KnowledgeType: sourceKB.Type, IsEnabled: true,
+						Metadata: knowledge.IndexMetadata(),
 					})
 				}
 			}
 		}
 	}
 
-	retrieveEngine, err := retriever.CreateRetrieveEngineForKB(
-		ctx, s.retrieveEngine, s.ownership, types.MustTenantIDFromContext(ctx), sourceKB.VectorStoreID)
+	retrieveEngine, err := createIndexWriterForKB(
+		ctx, s.retrieveEngine, s.ownership, s.kbService, types.MustTenantIDFromContext(ctx), sourceKB.VectorStoreID,
+		sourceKB.ID)
 	if err != nil {
 		return err
 	}
@@ -3070,6 +3097,9 @@ func (s *knowledgeService) ProcessManualUpdate(ctx context.Context, t *asynq.Tas
 	ctx = logger.WithRequestID(ctx, payload.RequestId)
 	ctx = logger.WithField(ctx, "manual_process", payload.KnowledgeID)
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
 	if err != nil {
@@ -3102,6 +3132,14 @@ func (s *knowledgeService) ProcessManualUpdate(ctx context.Context, t *asynq.Tas
 		return nil
 	}
 
+	// Same failure announcement as ProcessDocument
+	failedBefore := knowledge.ParseStatus == types.ParseStatusFailed
+	defer func() {
+		if !failedBefore && knowledge.ParseStatus == types.ParseStatusFailed {
+			publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, knowledge)
+		}
+	}()
+
 	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, payload.KnowledgeBaseID)
 	if err != nil {
 		logger.Errorf(ctx, "ProcessManualUpdate: failed to get knowledge base: %v", err)
@@ -3124,6 +3162,7 @@ func (s *knowledgeService) ProcessManualUpdate(ctx context.Context, t *asynq.Tas
 		logger.Errorf(ctx, "ProcessManualUpdate: failed to update status to processing: %v", err)
 		return nil
 	}
+	failedBefore = false
 
 	// Allocate a fresh span-tracking attempt for this manual (re)index.
 	// Without it attemptFromCtx stays 0, so processChunks drops all stage
@@ -3171,6 +3210,12 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 		ctx = context.WithValue(ctx, types.LanguageContextKey, payload.Language)
 	}
 
+	// Hold the document while its knowledge base moves to another vector
+	// store: the worker requeues the task without counting an attempt.
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
+
 	// 获取任务重试信息，用于判断是否是最后一次重试
 	retryCount, _ := asynq.GetRetryCount(ctx)
 	maxRetry, _ := asynq.GetMaxRetry(ctx)
@@ -3225,6 +3270,16 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 		// 这里可以根据错误类型判断是否可恢复，暂时允许重试
 	}
 
+	// Announce a parse that ends in failed. Every failure path below marks
+	// this same knowledge object, so one check on the way out covers them;
+	// a row that was already failed only counts once this attempt started.
+	failedBefore := knowledge.ParseStatus == types.ParseStatusFailed
+	defer func() {
+		if !failedBefore && knowledge.ParseStatus == types.ParseStatusFailed {
+			publishKnowledgeLifecycle(ctx, event.EventKnowledgeFailed, knowledge)
+		}
+	}()
+
 	// 检查是否有部分处理（有chunks但状态不是completed）
 	if knowledge.ParseStatus != "completed" && knowledge.ParseStatus != "pending" &&
 		knowledge.ParseStatus != "processing" {
@@ -3259,6 +3314,7 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 		logger.Errorf(ctx, "failed to update knowledge status to processing: %v", err)
 		return nil
 	}
+	failedBefore = false
 
 	// Resolve the attempt for span tracking. The enqueue site sets
 	// payload.Attempt to a fresh number for the initial parse and to
@@ -3730,9 +3786,21 @@ func (s *knowledgeService) callDocReaderWithTimeout(
 	callCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	callCtx, readSpan := langfuse.GetManager().StartSpan(callCtx, langfuse.SpanOptions{
+		Name: "docreader.read",
+		Metadata: map[string]interface{}{
+			"file_name":     req.FileName,
+			"file_type":     req.FileType,
+			"parser_engine": req.ParserEngine,
+			"has_url":       req.URL != "",
+		},
+	})
 	start := time.Now()
 	result, err := reader.Read(callCtx, req)
 	elapsed := time.Since(start)
+	readSpan.Finish(docReaderSpanOutput(result), map[string]interface{}{
+		"duration_ms": elapsed.Milliseconds(),
+	}, err)
 	if err != nil {
 		// Promote DeadlineExceeded into a clearer message; retain underlying
 		// error via %w so errors.Is(callCtx.Err(), context.DeadlineExceeded)
@@ -3748,6 +3816,19 @@ func (s *knowledgeService) callDocReaderWithTimeout(
 	return result, nil
 }
 
+// docReaderSpanOutput summarizes a parse result for the docreader.read span
+// without copying the (potentially huge) markdown body into the trace.
+func docReaderSpanOutput(result *types.ReadResult) map[string]interface{} {
+	if result == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"text_length":  len(result.MarkdownContent),
+		"images_found": len(result.ImageRefs),
+		"parse_error":  result.Error,
+	}
+}
+
 // isLikelyRateLimitError performs a fuzzy classification of an error as a
 // rate-limit / quota / backpressure failure. We only need a hint — the
 // caller maps to one of two error_codes so the UI can offer "retry later"
@@ -90,6 +90,12 @@ func (e *parentChildRetrieveEngine) DeleteByKnowledgeIDList(
 	return nil
 }
 
+func (e *parentChildRetrieveEngine) DeleteByKnowledgeBaseIDList(
+	context.Context, []string, int, string,
+) error {
+	return nil
+}
+
 func (e *parentChildRetrieveEngine) EstimateStorageSize(
 	context.Context, embedding.Embedder, []*types.IndexInfo, []types.RetrieverType,
 ) int64 {
@@ -133,6 +139,15 @@ func (parentChildTenantRepo) AdjustStorageUsed(context.Context, uint64, int64) e
 	return nil
 }
 
+type parentChildKBService struct {
+	interfaces.KnowledgeBaseService
+	kb *types.KnowledgeBase
+}
+
+func (s parentChildKBService) GetKnowledgeBaseByID(context.Context, string) (*types.KnowledgeBase, error) {
+	return s.kb, nil
+}
+
 type parentChildTaskEnqueuer struct{}
 
 func (parentChildTaskEnqueuer) Enqueue(*asynq.Task, ...asynq.Option) (*asynq.TaskInfo, error) {
@@ -158,8 +173,15 @@ func TestProcessChunksIndexesEveryTextChild(t *testing.T) {
 		}},
 	}
 	ctx := context.WithValue(context.Background(), types.TenantInfoContextKey, tenant)
+	kb := &types.KnowledgeBase{
+		ID:               "kb-1",
+		TenantID:         1,
+		EmbeddingModelID: "embedding-1",
+		IndexingStrategy: types.IndexingStrategy{VectorEnabled: true},
+	}
 	svc := &knowledgeService{
 		repo:           &parentChildKnowledgeRepo{knowledge: knowledge},
+		kbService:      parentChildKBService{kb: kb},
 		chunkService:   chunkService,
 		modelService:   parentChildModelService{embedder: parentChildEmbedder{}},
 		retrieveEngine: parentChildRetrieveRegistry{engine: retrieveEngine},
@@ -167,12 +189,6 @@ func TestProcessChunksIndexesEveryTextChild(t *testing.T) {
 		tenantRepo:     parentChildTenantRepo{},
 		task:           parentChildTaskEnqueuer{},
 	}
-	kb := &types.KnowledgeBase{
-		ID:               "kb-1",
-		TenantID:         1,
-		EmbeddingModelID: "embedding-1",
-		IndexingStrategy: types.IndexingStrategy{VectorEnabled: true},
-	}
 	chunks := []types.ParsedChunk{
 		{Content: "linked child", Seq: 0, Start: 0, End: 12, ParentIndex: 0},
 		{Content: "standalone child", Seq: 1, Start: 12, End: 28, ParentIndex: -1},
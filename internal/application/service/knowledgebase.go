@@ -41,6 +41,7 @@ type knowledgeBaseService struct {
 	fileSvc         interfaces.FileService
 	storageResolver interfaces.StorageBackendResolver
 	graphEngine     interfaces.RetrieveGraphRepository
+	communityRepo   interfaces.GraphCommunityRepository
 	asynqClient     interfaces.TaskEnqueuer
 	taskInspector   interfaces.TaskInspector
 	taskPendingRepo interfaces.TaskPendingOpsRepository
@@ -48,6 +49,7 @@ type knowledgeBaseService struct {
 	syncLogRepo     interfaces.SyncLogRepository
 	dsScheduler     *datasource.Scheduler
 	audit           interfaces.AuditLogService
+	knowledgeACL    interfaces.KnowledgeACLService
 }
 
 // NewKnowledgeBaseService creates a new knowledge base service
@@ -63,6 +65,7 @@ func NewKnowledgeBaseService(repo interfaces.KnowledgeBaseRepository,
 	fileSvc interfaces.FileService,
 	storageResolver interfaces.StorageBackendResolver,
 	graphEngine interfaces.RetrieveGraphRepository,
+	communityRepo interfaces.GraphCommunityRepository,
 	asynqClient interfaces.TaskEnqueuer,
 	taskInspector interfaces.TaskInspector,
 	taskPendingRepo interfaces.TaskPendingOpsRepository,
@@ -70,6 +73,7 @@ func NewKnowledgeBaseService(repo interfaces.KnowledgeBaseRepository,
 	syncLogRepo interfaces.SyncLogRepository,
 	dsScheduler *datasource.Scheduler,
 	audit interfaces.AuditLogService,
+	knowledgeACL interfaces.KnowledgeACLService,
 ) interfaces.KnowledgeBaseService {
 	return &knowledgeBaseService{
 		repo:            repo,
@@ -84,6 +88,7 @@ func NewKnowledgeBaseService(repo interfaces.KnowledgeBaseRepository,
 		fileSvc:         fileSvc,
 		storageResolver: storageResolver,
 		graphEngine:     graphEngine,
+		communityRepo:   communityRepo,
 		asynqClient:     asynqClient,
 		taskInspector:   taskInspector,
 		taskPendingRepo: taskPendingRepo,
@@ -91,6 +96,7 @@ func NewKnowledgeBaseService(repo interfaces.KnowledgeBaseRepository,
 		syncLogRepo:     syncLogRepo,
 		dsScheduler:     dsScheduler,
 		audit:           audit,
+		knowledgeACL:    knowledgeACL,
 	}
 }
 
@@ -120,6 +126,10 @@ func (s *knowledgeBaseService) CreateKnowledgeBase(ctx context.Context,
 	kb.CreatedAt = time.Now()
 	kb.TenantID = types.MustTenantIDFromContext(ctx)
 	kb.UpdatedAt = time.Now()
+	// A new knowledge base always starts on its own index label.
+	kb.EmbeddingIndexID = ""
+	kb.EmbeddingMigration = nil
+	kb.VectorStoreMigration = nil
 	// Record the creator so RBAC's RequireOwnershipOrRole can let
 	// Contributors edit their own KBs without granting them tenant-wide
 	// edit rights. The X-API-Key auth path attaches a synthetic
@@ -131,6 +141,9 @@ func (s *knowledgeBaseService) CreateKnowledgeBase(ctx context.Context,
 	if uid, ok := types.UserIDFromContext(ctx); ok && !types.IsSyntheticUserID(uid) {
 		kb.CreatorID = uid
 	}
+	if err := kb.FusionConfig.Validate(); err != nil {
+		return nil, apperrors.NewBadRequestError("invalid fusion_config: " + err.Error())
+	}
 	kb.EnsureDefaults()
 	applyTenantDefaultStorageProvider(ctx, kb)
 	if err := s.applyAndValidateStorageBackend(ctx, kb); err != nil {
@@ -166,6 +179,7 @@ func (s *knowledgeBaseService) CreateKnowledgeBase(ctx context.Context,
 		"knowledge_base", kb.ID, types.AuditOutcomeSuccess, map[string]any{
 			"name": kb.Name, "type": kb.Type,
 		})
+	publishKnowledgeBaseChanged(ctx, kb, "created")
 
 	logger.Infof(ctx, "Knowledge base created successfully, ID: %s, name: %s", kb.ID, kb.Name)
 	return kb, nil
@@ -551,6 +565,17 @@ func (s *knowledgeBaseService) UpdateKnowledgeBase(ctx context.Context,
 				kb.ExtractConfig = &types.ExtractConfig{Enabled: true}
 			}
 		}
+		// An empty fusion config drops the override back to the tenant default
+		if config.FusionConfig != nil {
+			if err := config.FusionConfig.Validate(); err != nil {
+				return nil, apperrors.NewBadRequestError("invalid fusion_config: " + err.Error())
+			}
+			if *config.FusionConfig == (types.FusionConfig{}) {
+				kb.FusionConfig = nil
+			} else {
+				kb.FusionConfig = config.FusionConfig
+			}
+		}
 	}
 	kb.UpdatedAt = time.Now()
 	kb.EnsureDefaults()
@@ -566,6 +591,7 @@ func (s *knowledgeBaseService) UpdateKnowledgeBase(ctx context.Context,
 		"knowledge_base", kb.ID, types.AuditOutcomeSuccess, map[string]any{
 			"name": kb.Name, "changed_fields": changedFields,
 		})
+	publishKnowledgeBaseChanged(ctx, kb, "updated")
 
 	logger.Infof(ctx, "Knowledge base updated successfully, ID: %s, name: %s", kb.ID, kb.Name)
 	return kb, nil
@@ -737,6 +763,10 @@ func (s *knowledgeBaseService) DeleteKnowledgeBase(ctx context.Context, id strin
 	}
 	recordKBActivity(ctx, s.audit, tenantID, id, types.AuditActionKBDeleted,
 		"knowledge_base", id, types.AuditOutcomeSuccess, map[string]any{"name": deletedName})
+	if kb == nil {
+		kb = &types.KnowledgeBase{ID: id, TenantID: tenantID, Name: deletedName}
+	}
+	publishKnowledgeBaseChanged(ctx, kb, "deleted")
 
 	// Stop both ephemeral queue work and durable wiki operations that target
 	// the now-deleted KB. ProcessKBDelete repeats this with document IDs and
@@ -964,6 +994,19 @@ func (s *knowledgeBaseService) ProcessKBDelete(ctx context.Context, t *asynq.Tas
 		}
 	}
 
+	// Graph community summaries are derived from the whole knowledge base
+	// graph, so they go with the knowledge base rather than per document.
+	if s.communityRepo != nil {
+		if err := s.communityRepo.DeleteByKnowledgeBase(ctx, kbID); err != nil {
+			logger.Warnf(ctx, "Failed to delete graph communities: %v", err)
+		}
+	}
+	if s.knowledgeACL != nil {
+		if err := s.knowledgeACL.DeleteKnowledgeBaseACLs(ctx, kbID); err != nil {
+			logger.Warnf(ctx, "Failed to delete access lists: %v", err)
+		}
+	}
+
 	logger.Infof(ctx, "KB delete task completed successfully, knowledge base ID: %s", kbID)
 	return nil
 }
@@ -1047,52 +1090,6 @@ func (s *knowledgeBaseService) deleteDataSourcesForKnowledgeBase(ctx context.Con
 	return dataSourceIDs
 }
 
-// SetEmbeddingModel sets the embedding model for a knowledge base
-func (s *knowledgeBaseService) SetEmbeddingModel(ctx context.Context, id string, modelID string) error {
-	if id == "" {
-		logger.Error(ctx, "Knowledge base ID is empty")
-		return errors.New("knowledge base ID cannot be empty")
-	}
-
-	if modelID == "" {
-		logger.Error(ctx, "Model ID is empty")
-		return errors.New("model ID cannot be empty")
-	}
-
-	logger.Infof(ctx, "Setting embedding model for knowledge base, knowledge base ID: %s, model ID: %s", id, modelID)
-
-	// Get the knowledge base
-	kb, err := s.repo.GetKnowledgeBaseByID(ctx, id)
-	if err != nil {
-		logger.ErrorWithFields(ctx, err, map[string]interface{}{
-			"knowledge_base_id": id,
-		})
-		return err
-	}
-
-	// Update the knowledge base's embedding model
-	kb.EmbeddingModelID = modelID
-	kb.UpdatedAt = time.Now()
-
-	logger.Info(ctx, "Saving knowledge base embedding model update")
-	err = s.repo.UpdateKnowledgeBase(ctx, kb)
-	if err != nil {
-		logger.ErrorWithFields(ctx, err, map[string]interface{}{
-			"knowledge_base_id":  id,
-			"embedding_model_id": modelID,
-		})
-		return err
-	}
-
-	logger.Infof(
-		ctx,
-		"Knowledge base embedding model set successfully, knowledge base ID: %s, model ID: %s",
-		id,
-		modelID,
-	)
-	return nil
-}
-
 // CopyKnowledgeBase copies a knowledge base to a new knowledge base (shallow copy).
 // Source and target must belong to the tenant in context; cross-tenant access is rejected.
 //
@@ -1102,7 +1099,7 @@ func (s *knowledgeBaseService) SetEmbeddingModel(ctx context.Context, id string,
 //     EmbeddingModelID and VectorStoreID must match the target's. Mismatched
 //     embedding models would silently mix incompatible vector spaces;
 //     mismatched vector stores would require copying physical vector data
-//     between stores, which is not yet supported.
+//     between stores, which is what MigrateVectorStore is for.
 //   - When dstKB == "" (create a new target), VectorStoreID is copied from
 //     the source so the new KB shares the same physical vector index. GORM
 //     `<-:create` allows INSERT, so the new row is well-formed.
@@ -1143,7 +1140,7 @@ func (s *knowledgeBaseService) CopyKnowledgeBase(ctx context.Context,
 		if !sourceKB.SharesStoreWith(targetKB) {
 			return nil, nil, apperrors.NewBadRequestError(
 				"source and target knowledge bases are bound to different vector stores; " +
-					"cross-store cloning is not yet supported")
+					"migrate one of them to the other's vector store before cloning")
 		}
 
 		// Defense 3: the concrete storage instance must match. Comparing only
@@ -1198,6 +1195,13 @@ func (s *knowledgeBaseService) CopyKnowledgeBase(ctx context.Context,
 			return nil, nil, err
 		}
 	}
+	// Copied documents get their own lists as they are cloned; the folder
+	// lists make documents added to the target later follow the source.
+	if s.knowledgeACL != nil {
+		if err := s.knowledgeACL.CopyFolderACLs(ctx, sourceKB.ID, targetKB); err != nil {
+			return nil, nil, fmt.Errorf("copy folder access lists: %w", err)
+		}
+	}
 	return sourceKB, targetKB, nil
 }
 
@@ -1236,6 +1240,8 @@ func (s *knowledgeBaseService) DuplicateKnowledgeBase(
 	targetKB.CreatedAt = now
 	targetKB.UpdatedAt = now
 	targetKB.DeletedAt.Valid = false
+	targetKB.EmbeddingMigration = nil
+	targetKB.VectorStoreMigration = nil
 	targetKB.DeletedAt.Time = time.Time{}
 	targetKB.IsTemporary = false
 	targetKB.IsPinned = false
@@ -1258,10 +1264,21 @@ func (s *knowledgeBaseService) DuplicateKnowledgeBase(
 	if err := s.repo.CreateKnowledgeBase(ctx, targetKB); err != nil {
 		return nil, err
 	}
+	// Folder access lists are settings of the knowledge base: documents later
+	// added to a restricted folder of the copy are restricted like the source.
+	if s.knowledgeACL != nil {
+		if err := s.knowledgeACL.CopyFolderACLs(ctx, sourceKB.ID, targetKB); err != nil {
+			if delErr := s.repo.DeleteKnowledgeBase(ctx, targetKB.ID); delErr != nil {
+				logger.Warnf(ctx, "Failed to remove duplicate %s after access list copy failed: %v", targetKB.ID, delErr)
+			}
+			return nil, fmt.Errorf("copy folder access lists: %w", err)
+		}
+	}
 	recordKBActivity(ctx, s.audit, tenantID, targetKB.ID, types.AuditActionKBDuplicated,
 		"knowledge_base", targetKB.ID, types.AuditOutcomeSuccess, map[string]any{
 			"source_kb_id": sourceKB.ID, "name": targetKB.Name,
 		})
+	publishKnowledgeBaseChanged(ctx, targetKB, "created")
 	return targetKB, nil
 }
 
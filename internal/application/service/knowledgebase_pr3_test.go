@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Tencent/WeKnora/internal/application/repository"
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	apperrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/storageallowlist"
@@ -115,6 +116,39 @@ func (r *fakeKBRepo) ListKnowledgeBasesByTenantID(_ context.Context, tenantID ui
 func (r *fakeKBRepo) UpdateKnowledgeBase(_ context.Context, _ *types.KnowledgeBase) error {
 	return nil
 }
+func (r *fakeKBRepo) UpdateEmbeddingMigration(_ context.Context, id string, m *types.EmbeddingMigration) error {
+	if kb := r.rows[id]; kb != nil {
+		kb.EmbeddingMigration = m
+	}
+	return nil
+}
+func (r *fakeKBRepo) SwitchEmbeddingIndex(
+	_ context.Context, id string, modelID string, indexID string, m *types.EmbeddingMigration,
+) error {
+	kb := r.rows[id]
+	if kb == nil {
+		return repository.ErrKnowledgeBaseNotFound
+	}
+	kb.EmbeddingModelID = modelID
+	kb.EmbeddingIndexID = indexID
+	kb.EmbeddingMigration = m
+	return nil
+}
+func (r *fakeKBRepo) UpdateVectorStoreMigration(_ context.Context, id string, m *types.VectorStoreMigration) error {
+	if kb := r.rows[id]; kb != nil {
+		kb.VectorStoreMigration = m
+	}
+	return nil
+}
+func (r *fakeKBRepo) SwitchVectorStore(_ context.Context, id string, storeID string, m *types.VectorStoreMigration) error {
+	kb := r.rows[id]
+	if kb == nil {
+		return repository.ErrKnowledgeBaseNotFound
+	}
+	kb.VectorStoreID = &storeID
+	kb.VectorStoreMigration = m
+	return nil
+}
 func (r *fakeKBRepo) DeleteKnowledgeBase(_ context.Context, _ string) error { return nil }
 func (r *fakeKBRepo) TogglePinKnowledgeBase(_ context.Context, _ string, _ uint64) (*types.KnowledgeBase, error) {
 	return nil, nil
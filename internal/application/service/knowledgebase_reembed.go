@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	apperrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// reembedBatchSize is the number of chunks embedded per BatchIndex call.
+	reembedBatchSize = 100
+	// reembedProgressInterval throttles progress writes to the knowledge base row.
+	reembedProgressInterval = 5 * time.Second
+	// reembedTaskTimeout bounds one attempt of the re-embedding task.
+	reembedTaskTimeout = 24 * time.Hour
+)
+
+// embeddingMigrationBusyStatuses are the parse states in which a document may
+// still write index entries with the current model or into the current
+// vector store, so neither kind of migration may start while one is in them.
+var embeddingMigrationBusyStatuses = []string{types.ParseStatusProcessing, types.ParseStatusFinalizing}
+
+// SetEmbeddingModel switches a knowledge base to another embedding model.
+//
+// A knowledge base without indexed documents is switched in place. Otherwise
+// a background task re-embeds every document into a shadow index; queries keep
+// using the current model and vectors until the task switches over, and the
+// returned migration reports its progress.
+func (s *knowledgeBaseService) SetEmbeddingModel(
+	ctx context.Context, id string, modelID string,
+) (*types.EmbeddingMigration, error) {
+	if id == "" {
+		logger.Error(ctx, "Knowledge base ID is empty")
+		return nil, errors.New("knowledge base ID cannot be empty")
+	}
+	if modelID == "" {
+		logger.Error(ctx, "Model ID is empty")
+		return nil, apperrors.NewBadRequestError("model ID cannot be empty")
+	}
+
+	logger.Infof(ctx, "Setting embedding model for knowledge base, knowledge base ID: %s, model ID: %s",
+		id, secutils.SanitizeForLog(modelID))
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	kb, err := s.repo.GetKnowledgeBaseByIDAndTenant(ctx, id, tenantID)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, map[string]interface{}{
+			"knowledge_base_id": id,
+		})
+		return nil, err
+	}
+	if kb.EmbeddingMigration.Active() {
+		return nil, apperrors.NewConflictError("an embedding model migration is already in progress")
+	}
+	if kb.VectorStoreMigration.Active() {
+		return nil, apperrors.NewConflictError("a vector store migration is in progress")
+	}
+	if kb.EmbeddingModelID == modelID {
+		return nil, nil
+	}
+
+	model, err := s.modelService.GetModelByID(ctx, modelID)
+	if err != nil || model == nil {
+		return nil, apperrors.NewBadRequestError("embedding model not found")
+	}
+	if model.Type != types.ModelTypeEmbedding {
+		return nil, apperrors.NewBadRequestError("model is not an embedding model")
+	}
+
+	// Documents being parsed would have their remaining index writes fenced
+	// halfway through; pending ones are held by the worker until the switch
+	// and then indexed with the new model.
+	processing, err := s.kgRepo.CountKnowledgeByStatus(ctx, tenantID, id, embeddingMigrationBusyStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if processing > 0 {
+		return nil, apperrors.NewConflictError("documents are still being processed; retry when they finish")
+	}
+
+	// Nothing has been embedded yet: just point the knowledge base at the new model.
+	count, err := s.kgRepo.CountKnowledgeByKnowledgeBaseID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if kb.EmbeddingModelID == "" || !kb.NeedsEmbeddingModel() || count == 0 {
+		if err := s.repo.SwitchEmbeddingIndex(ctx, id, modelID, kb.EmbeddingIndexID, nil); err != nil {
+			return nil, err
+		}
+		logger.Infof(ctx, "Knowledge base embedding model set in place, knowledge base ID: %s", id)
+		return nil, nil
+	}
+
+	now := time.Now()
+	migration := &types.EmbeddingMigration{
+		TaskID:        secutils.GenerateTaskID("kb_reembed", tenantID, id),
+		SourceModelID: kb.EmbeddingModelID,
+		TargetModelID: modelID,
+		IndexID:       uuid.New().String(),
+		Status:        types.EmbeddingMigrationPending,
+		Total:         int(count),
+		UpdatedAt:     now,
+	}
+	if err := s.repo.UpdateEmbeddingMigration(ctx, id, migration); err != nil {
+		return nil, err
+	}
+
+	payload := types.KBReembedPayload{
+		TenantID:        tenantID,
+		KnowledgeBaseID: id,
+		TaskID:          migration.TaskID,
+	}
+	langfuse.InjectTracing(ctx, &payload)
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	task := asynq.NewTask(types.TypeKBReembed, payloadBytes,
+		asynq.TaskID(migration.TaskID), asynq.Queue(types.QueueMaintenance),
+		asynq.MaxRetry(3), asynq.Timeout(reembedTaskTimeout))
+	if _, err := s.asynqClient.Enqueue(task); err != nil {
+		logger.Errorf(ctx, "Failed to enqueue KB re-embed task: %v", err)
+		migration.Status = types.EmbeddingMigrationFailed
+		migration.Error = "failed to enqueue task"
+		migration.FinishedAt = &now
+		_ = s.repo.UpdateEmbeddingMigration(ctx, id, migration)
+		return nil, fmt.Errorf("failed to enqueue re-embed task: %w", err)
+	}
+
+	logger.Infof(ctx, "KB re-embed task enqueued: %s, knowledge base ID: %s, %s -> %s",
+		migration.TaskID, id, migration.SourceModelID, migration.TargetModelID)
+	return migration, nil
+}
+
+// ProcessEmbeddingMigration handles the re-embedding task.
+//
+// Every document is embedded with the target model into a shadow index: the
+// same vector store, labelled with the migration's IndexID instead of the
+// knowledge base's current index label. Searches keep filtering on the
+// current label, so they are served from the old vectors until
+// SwitchEmbeddingIndex flips model and label together. The old vectors are
+// dropped afterwards. On the last failed attempt the shadow index is dropped
+// and the knowledge base stays on the old model.
+func (s *knowledgeBaseService) ProcessEmbeddingMigration(ctx context.Context, t *asynq.Task) error {
+	var payload types.KBReembedPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "Failed to unmarshal KB re-embed payload: %v", err)
+		return asynq.SkipRetry
+	}
+
+	tenantID := payload.TenantID
+	kbID := payload.KnowledgeBaseID
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, tenantID)
+	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant info: %w", err)
+	}
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
+
+	kb, err := s.repo.GetKnowledgeBaseByIDAndTenant(ctx, kbID, tenantID)
+	if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+		logger.Warnf(ctx, "KB re-embed task skipped, knowledge base %s no longer exists", kbID)
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		return err
+	}
+	migration := kb.EmbeddingMigration
+	if !migration.Active() || migration.TaskID != payload.TaskID {
+		logger.Infof(ctx, "KB re-embed task %s is stale for knowledge base %s, skipping", payload.TaskID, kbID)
+		return nil
+	}
+
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	isLastRetry := retryCount >= maxRetry
+	logger.Infof(ctx, "Processing KB re-embed task: %s, knowledge base: %s, retry: %d/%d",
+		payload.TaskID, kbID, retryCount, maxRetry)
+
+	engine, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	if errors.Is(err, retriever.ErrVectorStoreForbidden) || errors.Is(err, retriever.ErrVectorStoreNotFound) {
+		s.failEmbeddingMigration(ctx, kb, nil, nil, err)
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		return err
+	}
+	targetModel, err := s.modelService.GetEmbeddingModel(ctx, migration.TargetModelID)
+	if err != nil {
+		s.failEmbeddingMigration(ctx, kb, engine, nil, err)
+		return asynq.SkipRetry
+	}
+
+	// fail gives up on the last attempt and otherwise lets asynq retry. A
+	// retry rebuilds the shadow index from scratch.
+	fail := func(err error) error {
+		if isLastRetry {
+			s.failEmbeddingMigration(ctx, kb, engine, targetModel, err)
+			return asynq.SkipRetry
+		}
+		migration.Error = err.Error()
+		migration.UpdatedAt = time.Now()
+		_ = s.repo.UpdateEmbeddingMigration(ctx, kbID, migration)
+		return err
+	}
+
+	shadowLabels := []string{migration.IndexID}
+	if err := engine.DeleteByKnowledgeBaseIDList(ctx, shadowLabels, targetModel.GetDimensions(), kb.Type); err != nil {
+		return fail(fmt.Errorf("clear shadow index: %w", err))
+	}
+	now := time.Now()
+	if migration.StartedAt == nil {
+		migration.StartedAt = &now
+	}
+	migration.Status = types.EmbeddingMigrationRunning
+	migration.Processed = 0
+	migration.Error = ""
+	migration.UpdatedAt = now
+	if err := s.repo.UpdateEmbeddingMigration(ctx, kbID, migration); err != nil {
+		return err
+	}
+
+	shadow := *kb
+	shadow.EmbeddingIndexID = migration.IndexID
+	seen := make(map[string]bool)
+	done := make(map[string]bool)
+	lastSave := time.Now()
+
+	// Every other index write of the knowledge base is fenced while the
+	// migration is active, so only completed documents carry entries to
+	// re-embed; pending ones are indexed with the new model after the switch.
+	// The second pass picks up a document that completed while the first was
+	// running. Each document is embedded once per attempt, as not every store
+	// overwrites a re-indexed entry.
+	for pass := 0; pass < 2; pass++ {
+		knowledgeList, err := s.kgRepo.ListKnowledgeByKnowledgeBaseID(ctx, tenantID, kbID)
+		if err != nil {
+			return fail(fmt.Errorf("list knowledge: %w", err))
+		}
+		for _, knowledge := range knowledgeList {
+			if knowledge.ParseStatus != types.ParseStatusCompleted {
+				continue
+			}
+			if !seen[knowledge.ID] {
+				seen[knowledge.ID] = true
+				if pass > 0 {
+					migration.Total++
+				}
+			}
+			if done[knowledge.ID] {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.reembedKnowledge(ctx, engine, &shadow, targetModel, knowledge); err != nil {
+				return fail(fmt.Errorf("re-embed knowledge %s: %w", knowledge.ID, err))
+			}
+			done[knowledge.ID] = true
+			migration.Processed++
+			if time.Since(lastSave) >= reembedProgressInterval {
+				migration.UpdatedAt = time.Now()
+				if err := s.repo.UpdateEmbeddingMigration(ctx, kbID, migration); err != nil {
+					logger.Warnf(ctx, "Failed to save re-embed progress for knowledge base %s: %v", kbID, err)
+				}
+				lastSave = time.Now()
+			}
+		}
+		if pass == 0 {
+			migration.Total = len(seen)
+		}
+	}
+
+	// A document that started parsing before the fence went up may still be
+	// writing with the old model; its entries would be lost with the old
+	// index, so the switch waits for it.
+	processing, err := s.kgRepo.CountKnowledgeByStatus(ctx, tenantID, kbID, embeddingMigrationBusyStatuses)
+	if err != nil {
+		return fail(fmt.Errorf("count processing knowledge: %w", err))
+	}
+	if processing > 0 {
+		return fail(fmt.Errorf("%d documents are still being processed", processing))
+	}
+
+	// Cut over: model and index label change in one row update, so no query
+	// ever pairs the new model with the old vectors or vice versa.
+	oldLabel := kb.IndexKnowledgeBaseID()
+	finished := time.Now()
+	migration.Status = types.EmbeddingMigrationCompleted
+	migration.Processed = migration.Total
+	migration.FinishedAt = &finished
+	migration.UpdatedAt = finished
+	err = s.repo.SwitchEmbeddingIndex(ctx, kbID, migration.TargetModelID, migration.IndexID, migration)
+	if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+		logger.Warnf(ctx, "Knowledge base %s was deleted during re-embedding, dropping shadow index", kbID)
+		if err := engine.DeleteByKnowledgeBaseIDList(ctx, shadowLabels, targetModel.GetDimensions(), kb.Type); err != nil {
+			logger.Warnf(ctx, "Failed to drop shadow index of knowledge base %s: %v", kbID, err)
+		}
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		migration.Status = types.EmbeddingMigrationRunning
+		migration.FinishedAt = nil
+		return fail(fmt.Errorf("switch embedding index: %w", err))
+	}
+
+	// The switch is committed; failing to drop the old vectors only leaves
+	// unreachable rows behind, so it does not fail the task.
+	if sourceModel, err := s.modelService.GetEmbeddingModel(ctx, migration.SourceModelID); err != nil {
+		logger.Warnf(ctx, "Failed to load source embedding model %s, old vectors of knowledge base %s kept: %v",
+			migration.SourceModelID, kbID, err)
+	} else if err := engine.DeleteByKnowledgeBaseIDList(
+		ctx, []string{oldLabel}, sourceModel.GetDimensions(), kb.Type,
+	); err != nil {
+		logger.Warnf(ctx, "Failed to delete old vectors of knowledge base %s: %v", kbID, err)
+	}
+
+	logger.Infof(ctx, "KB re-embed task completed: %s, knowledge base: %s, documents: %d",
+		payload.TaskID, kbID, migration.Total)
+	return nil
+}
+
+// failEmbeddingMigration marks the migration failed and drops the shadow
+// index. engine and targetModel may be nil when the failure happened before
+// they could be resolved; nothing has been written in that case.
+func (s *knowledgeBaseService) failEmbeddingMigration(
+	ctx context.Context,
+	kb *types.KnowledgeBase,
+	engine *retriever.CompositeRetrieveEngine,
+	targetModel embedding.Embedder,
+	cause error,
+) {
+	migration := kb.EmbeddingMigration
+	logger.Errorf(ctx, "KB re-embed task %s failed for knowledge base %s: %v", migration.TaskID, kb.ID, cause)
+	if engine != nil && targetModel != nil {
+		if err := engine.DeleteByKnowledgeBaseIDList(
+			ctx, []string{migration.IndexID}, targetModel.GetDimensions(), kb.Type,
+		); err != nil {
+			logger.Warnf(ctx, "Failed to drop shadow index of knowledge base %s: %v", kb.ID, err)
+		}
+	}
+	now := time.Now()
+	migration.Status = types.EmbeddingMigrationFailed
+	migration.Error = cause.Error()
+	migration.FinishedAt = &now
+	migration.UpdatedAt = now
+	if err := s.repo.UpdateEmbeddingMigration(ctx, kb.ID, migration); err != nil {
+		logger.Warnf(ctx, "Failed to save failed re-embed state for knowledge base %s: %v", kb.ID, err)
+	}
+}
+
+// reembedKnowledge writes the index entries of one document into the shadow
+// index of kb. Entries mirror what ingestion writes: text chunks with the
+// document title, generated questions, FAQ entries per the FAQ index mode,
+// and the derived summary/image/table chunks as-is. Parent chunks are never
+// indexed.
+func (s *knowledgeBaseService) reembedKnowledge(
+	ctx context.Context,
+	engine *retriever.CompositeRetrieveEngine,
+	kb *types.KnowledgeBase,
+	embedder embedding.Embedder,
+	knowledge *types.Knowledge,
+) error {
+	chunks, err := s.chunkRepo.ListChunksByKnowledgeID(ctx, knowledge.TenantID, knowledge.ID)
+	if err != nil {
+		return err
+	}
+	label := kb.IndexKnowledgeBaseID()
+	indexMetadata := knowledge.IndexMetadata()
+	indexInfo := make([]*types.IndexInfo, 0, len(chunks))
+	for _, chunk := range chunks {
+		switch chunk.ChunkType {
+		case types.ChunkTypeFAQ:
+			if kb.Type != types.KnowledgeBaseTypeFAQ {
+				continue
+			}
+			infos, err := buildFAQIndexInfoList(kb, chunk)
+			if err != nil {
+				return err
+			}
+			indexInfo = append(indexInfo, infos...)
+		case types.ChunkTypeText:
+			indexInfo = append(indexInfo, &types.IndexInfo{
+				Content:    buildKnowledgeIndexContent(knowledge, chunk.EmbeddingContent()),
+				SourceID:   chunk.ID,
+				SourceType: types.ChunkSourceType,
+				ChunkID:    chunk.ID, KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: label,
+				KnowledgeType: kb.Type, IsEnabled: chunk.IsEnabled, Metadata: indexMetadata,
+			})
+			meta, err := chunk.DocumentMetadata()
+			if err != nil {
+				return err
+			}
+			if meta == nil {
+				continue
+			}
+			for _, q := range meta.GeneratedQuestions {
+				if strings.TrimSpace(q.Question) == "" {
+					continue
+				}
+				indexInfo = append(indexInfo, &types.IndexInfo{
+					Content:    buildKnowledgeIndexContent(knowledge, q.Question),
+					SourceID:   types.GeneratedQuestionSourceID(chunk.ID, q.ID),
+					SourceType: types.ChunkSourceType,
+					ChunkID:    chunk.ID, KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: label,
+					KnowledgeType: kb.Type, IsEnabled: chunk.IsEnabled, Metadata: indexMetadata,
+				})
+			}
+		case types.ChunkTypeSummary, types.ChunkTypeImageOCR, types.ChunkTypeImageCaption,
+			types.ChunkTypeTableSummary, types.ChunkTypeTableColumn:
+			indexInfo = append(indexInfo, &types.IndexInfo{
+				Content:    chunk.Content,
+				SourceID:   chunk.ID,
+				SourceType: types.ChunkSourceType,
+				ChunkID:    chunk.ID, KnowledgeID: chunk.KnowledgeID, KnowledgeBaseID: label,
+				KnowledgeType: kb.Type, IsEnabled: chunk.IsEnabled, Metadata: indexMetadata,
+			})
+		}
+	}
+	// Stores that key rows by chunk or source ID would otherwise overwrite
+	// (or skip) the live entry; derive a stable per-label ID instead.
+	namespace := uuid.MustParse(label)
+	for _, info := range indexInfo {
+		info.ID = uuid.NewSHA1(namespace, []byte(info.SourceID)).String()
+	}
+	for batch := range slices.Chunk(indexInfo, reembedBatchSize) {
+		if err := engine.BatchIndex(ctx, embedder, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
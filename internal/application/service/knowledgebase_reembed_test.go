@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type reembedKnowledgeRepo struct {
+	interfaces.KnowledgeRepository
+	knowledge []*types.Knowledge
+}
+
+func (r *reembedKnowledgeRepo) CountKnowledgeByKnowledgeBaseID(
+	context.Context, uint64, string,
+) (int64, error) {
+	return int64(len(r.knowledge)), nil
+}
+
+func (r *reembedKnowledgeRepo) CountKnowledgeByStatus(
+	_ context.Context, _ uint64, _ string, statuses []string,
+) (int64, error) {
+	var n int64
+	for _, k := range r.knowledge {
+		if slices.Contains(statuses, k.ParseStatus) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *reembedKnowledgeRepo) ListKnowledgeByKnowledgeBaseID(
+	context.Context, uint64, string,
+) ([]*types.Knowledge, error) {
+	return r.knowledge, nil
+}
+
+type reembedChunkRepo struct {
+	interfaces.ChunkRepository
+	chunks map[string][]*types.Chunk
+}
+
+func (r *reembedChunkRepo) ListChunksByKnowledgeID(
+	_ context.Context, _ uint64, knowledgeID string,
+) ([]*types.Chunk, error) {
+	return r.chunks[knowledgeID], nil
+}
+
+type reembedModelService struct {
+	interfaces.ModelService
+	dimensions map[string]int
+}
+
+func (s reembedModelService) GetModelByID(_ context.Context, id string) (*types.Model, error) {
+	return &types.Model{ID: id, Type: types.ModelTypeEmbedding}, nil
+}
+
+func (s reembedModelService) GetEmbeddingModel(_ context.Context, id string) (embedding.Embedder, error) {
+	return reembedEmbedder{dimensions: s.dimensions[id]}, nil
+}
+
+type reembedEmbedder struct {
+	parentChildEmbedder
+	dimensions int
+}
+
+func (e reembedEmbedder) GetDimensions() int { return e.dimensions }
+
+type reembedTenantRepo struct {
+	interfaces.TenantRepository
+	tenant *types.Tenant
+}
+
+func (r reembedTenantRepo) GetTenantByID(context.Context, uint64) (*types.Tenant, error) {
+	return r.tenant, nil
+}
+
+type reembedDeletion struct {
+	labels    []string
+	dimension int
+}
+
+type reembedRetrieveEngine struct {
+	parentChildRetrieveEngine
+	indexErr error
+	indexed  []*types.IndexInfo
+	deleted  []reembedDeletion
+}
+
+func (e *reembedRetrieveEngine) BatchIndex(
+	_ context.Context, _ embedding.Embedder, infos []*types.IndexInfo, _ []types.RetrieverType,
+) error {
+	if e.indexErr != nil {
+		return e.indexErr
+	}
+	e.indexed = append(e.indexed, infos...)
+	return nil
+}
+
+func (e *reembedRetrieveEngine) DeleteByKnowledgeBaseIDList(
+	_ context.Context, labels []string, dimension int, _ string,
+) error {
+	e.deleted = append(e.deleted, reembedDeletion{labels: labels, dimension: dimension})
+	return nil
+}
+
+const (
+	reembedKBID        = "4f8d9a3e-2b1c-4c5d-9e6f-7a8b9c0d1e2f"
+	reembedSourceModel = "embedding-old"
+	reembedTargetModel = "embedding-new"
+)
+
+func newReembedTestService(
+	knowledge []*types.Knowledge, chunks map[string][]*types.Chunk,
+) (*knowledgeBaseService, *fakeKBRepo, *reembedRetrieveEngine, *metadataUpdateTaskEnqueuer) {
+	repo := newFakeKBRepo()
+	repo.rows[reembedKBID] = &types.KnowledgeBase{
+		ID:               reembedKBID,
+		TenantID:         1,
+		Type:             types.KnowledgeBaseTypeDocument,
+		EmbeddingModelID: reembedSourceModel,
+		IndexingStrategy: types.IndexingStrategy{VectorEnabled: true},
+	}
+	engine := &reembedRetrieveEngine{}
+	enqueuer := &metadataUpdateTaskEnqueuer{}
+	tenant := &types.Tenant{
+		ID: 1,
+		RetrieverEngines: types.RetrieverEngines{Engines: []types.RetrieverEngineParams{
+			{
+				RetrieverType:       types.VectorRetrieverType,
+				RetrieverEngineType: types.PostgresRetrieverEngineType,
+			},
+		}},
+	}
+	svc := &knowledgeBaseService{
+		repo:      repo,
+		kgRepo:    &reembedKnowledgeRepo{knowledge: knowledge},
+		chunkRepo: &reembedChunkRepo{chunks: chunks},
+		modelService: reembedModelService{dimensions: map[string]int{
+			reembedSourceModel: 768,
+			reembedTargetModel: 1024,
+		}},
+		retrieveEngine: parentChildRetrieveRegistry{engine: engine},
+		tenantRepo:     reembedTenantRepo{tenant: tenant},
+		asynqClient:    enqueuer,
+	}
+	return svc, repo, engine, enqueuer
+}
+
+func TestSetEmbeddingModelSwitchesEmptyKnowledgeBaseInPlace(t *testing.T) {
+	svc, repo, _, enqueuer := newReembedTestService(nil, nil)
+
+	migration, err := svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.NoError(t, err)
+	require.Nil(t, migration)
+	require.Empty(t, enqueuer.tasks)
+
+	kb := repo.rows[reembedKBID]
+	require.Equal(t, reembedTargetModel, kb.EmbeddingModelID)
+	require.Equal(t, reembedKBID, kb.IndexKnowledgeBaseID())
+	require.Nil(t, kb.EmbeddingMigration)
+}
+
+func TestEmbeddingMigrationBuildsShadowIndexBeforeSwitching(t *testing.T) {
+	knowledge := []*types.Knowledge{{
+		ID:              "knowledge-1",
+		TenantID:        1,
+		KnowledgeBaseID: reembedKBID,
+		Title:           "handbook.pdf",
+		ParseStatus:     types.ParseStatusCompleted,
+	}}
+	chunks := map[string][]*types.Chunk{"knowledge-1": {
+		{ID: "chunk-1", KnowledgeID: "knowledge-1", ChunkType: types.ChunkTypeText, Content: "text", IsEnabled: true},
+		{ID: "chunk-2", KnowledgeID: "knowledge-1", ChunkType: types.ChunkTypeParentText, Content: "parent"},
+		{ID: "chunk-3", KnowledgeID: "knowledge-1", ChunkType: types.ChunkTypeSummary, Content: "summary"},
+	}}
+	svc, repo, engine, enqueuer := newReembedTestService(knowledge, chunks)
+
+	migration, err := svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.NoError(t, err)
+	require.NotNil(t, migration)
+	require.Equal(t, types.EmbeddingMigrationPending, migration.Status)
+	require.Len(t, enqueuer.tasks, 1)
+	require.Equal(t, types.TypeKBReembed, enqueuer.tasks[0].Type())
+
+	// Queries keep using the old model until the task switches over.
+	kb := repo.rows[reembedKBID]
+	require.Equal(t, reembedSourceModel, kb.EmbeddingModelID)
+	require.Equal(t, reembedKBID, kb.IndexKnowledgeBaseID())
+
+	_, err = svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.Error(t, err, "a second migration must be rejected while one is active")
+
+	require.NoError(t, svc.ProcessEmbeddingMigration(context.Background(), enqueuer.tasks[0]))
+
+	require.Len(t, engine.indexed, 2)
+	ids := map[string]bool{}
+	for _, info := range engine.indexed {
+		require.Equal(t, migration.IndexID, info.KnowledgeBaseID)
+		require.NotEmpty(t, info.ID)
+		require.NotEqual(t, info.ChunkID, info.ID)
+		ids[info.ID] = true
+	}
+	require.Len(t, ids, 2)
+
+	kb = repo.rows[reembedKBID]
+	require.Equal(t, reembedTargetModel, kb.EmbeddingModelID)
+	require.Equal(t, migration.IndexID, kb.IndexKnowledgeBaseID())
+	require.Equal(t, types.EmbeddingMigrationCompleted, kb.EmbeddingMigration.Status)
+	require.Equal(t, 1, kb.EmbeddingMigration.Total)
+	require.Equal(t, 1, kb.EmbeddingMigration.Processed)
+
+	require.Equal(t, []reembedDeletion{
+		{labels: []string{migration.IndexID}, dimension: 1024},
+		{labels: []string{reembedKBID}, dimension: 768},
+	}, engine.deleted)
+}
+
+func TestEmbeddingMigrationKeepsOldModelWhenLastAttemptFails(t *testing.T) {
+	knowledge := []*types.Knowledge{{
+		ID: "knowledge-1", TenantID: 1, KnowledgeBaseID: reembedKBID, ParseStatus: types.ParseStatusCompleted,
+	}}
+	chunks := map[string][]*types.Chunk{"knowledge-1": {
+		{ID: "chunk-1", KnowledgeID: "knowledge-1", ChunkType: types.ChunkTypeText, Content: "text"},
+	}}
+	svc, repo, engine, enqueuer := newReembedTestService(knowledge, chunks)
+	engine.indexErr = errors.New("embedding service unavailable")
+
+	migration, err := svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.NoError(t, err)
+
+	// Outside an asynq worker the retry count and max retry are both zero, so
+	// this is treated as the last attempt.
+	err = svc.ProcessEmbeddingMigration(context.Background(), enqueuer.tasks[0])
+	require.ErrorIs(t, err, asynq.SkipRetry)
+
+	kb := repo.rows[reembedKBID]
+	require.Equal(t, reembedSourceModel, kb.EmbeddingModelID)
+	require.Equal(t, reembedKBID, kb.IndexKnowledgeBaseID())
+	require.Equal(t, types.EmbeddingMigrationFailed, kb.EmbeddingMigration.Status)
+	require.Contains(t, kb.EmbeddingMigration.Error, "embedding service unavailable")
+	require.Equal(t, reembedDeletion{labels: []string{migration.IndexID}, dimension: 1024},
+		engine.deleted[len(engine.deleted)-1])
+
+	// A stale task for a migration that is no longer active is a no-op.
+	require.NoError(t, svc.ProcessEmbeddingMigration(context.Background(), enqueuer.tasks[0]))
+}
+
+func TestEmbeddingMigrationFencesIndexWrites(t *testing.T) {
+	knowledge := []*types.Knowledge{{
+		ID: "knowledge-1", TenantID: 1, KnowledgeBaseID: reembedKBID, ParseStatus: types.ParseStatusCompleted,
+	}}
+	svc, repo, _, enqueuer := newReembedTestService(knowledge, nil)
+	ctx := ctxWithTenant(1)
+
+	_, err := svc.SetEmbeddingModel(ctx, reembedKBID, reembedTargetModel)
+	require.NoError(t, err)
+
+	// Writes with the old model during the re-embed would be dropped with
+	// the old index, so they are held like those of a store migration.
+	err = requireNoVectorStoreMigration(ctx, repo, reembedKBID)
+	require.ErrorIs(t, err, ErrEmbeddingModelMigrating)
+	require.ErrorIs(t, err, ErrVectorStoreMigrating)
+
+	require.NoError(t, svc.ProcessEmbeddingMigration(context.Background(), enqueuer.tasks[0]))
+	require.NoError(t, requireNoVectorStoreMigration(ctx, repo, reembedKBID))
+}
+
+func TestEmbeddingMigrationWaitsForProcessingDocuments(t *testing.T) {
+	knowledge := []*types.Knowledge{{
+		ID: "knowledge-1", TenantID: 1, KnowledgeBaseID: reembedKBID, ParseStatus: types.ParseStatusProcessing,
+	}}
+	svc, repo, _, enqueuer := newReembedTestService(knowledge, nil)
+
+	_, err := svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.Error(t, err)
+	require.Nil(t, repo.rows[reembedKBID].EmbeddingMigration)
+	require.Empty(t, enqueuer.tasks)
+
+	// A document that started parsing just before the migration keeps the
+	// knowledge base on the old model.
+	knowledge[0].ParseStatus = types.ParseStatusCompleted
+	_, err = svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.NoError(t, err)
+	knowledge[0].ParseStatus = types.ParseStatusFinalizing
+	err = svc.ProcessEmbeddingMigration(context.Background(), enqueuer.tasks[0])
+	require.ErrorIs(t, err, asynq.SkipRetry)
+
+	kb := repo.rows[reembedKBID]
+	require.Equal(t, reembedSourceModel, kb.EmbeddingModelID)
+	require.Equal(t, types.EmbeddingMigrationFailed, kb.EmbeddingMigration.Status)
+	require.Contains(t, kb.EmbeddingMigration.Error, "still being processed")
+}
@@ -97,6 +97,11 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 	// Normalize once, before anything reads MatchCount. params is a value
 	// copy, so this stays local to the call.
 	params.MatchCount = normalizedMatchCount(params.MatchCount)
+	metadataFilter, err := params.MetadataFilter.Normalize()
+	if err != nil {
+		return nil, apperrors.NewBadRequestError(err.Error())
+	}
+	params.MetadataFilter = metadataFilter
 
 	// Determine the set of KB IDs to search.
 	searchKBIDs := params.KnowledgeBaseIDs
@@ -140,6 +145,12 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 		return nil, err
 	}
 
+	// Documents the caller may not read are excluded inside the retrievers,
+	// so the top-K is filled from readable documents only.
+	if err := s.excludeDeniedKnowledge(ctx, searchKBIDs, &params); err != nil {
+		return nil, err
+	}
+
 	// Explicit embedding-model consistency check. Multi-KB searches that
 	// span different embedding spaces would otherwise silently produce
 	// meaningless cross-model scores. Same-model wiki/graph KBs are
@@ -231,7 +242,10 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 		return nil, err
 	}
 
-	// Separate and fuse retrieval results.
+	// Separate and fuse retrieval results. Score-based fusion first rescales
+	// each engine's list onto [0, 1].
+	fusionCfg := resolveFusionConfig(kb, tenantInfo)
+	retrieveResults = normalizeForScoreFusion(retrieveResults, fusionCfg)
 	vectorResults, keywordResults := classifyRetrievalResults(ctx, retrieveResults)
 	if len(vectorResults) == 0 && len(keywordResults) == 0 {
 		logger.Info(ctx, "No search results found")
@@ -240,11 +254,7 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 	logger.Infof(ctx, "Result count before fusion: vector=%d, keyword=%d",
 		len(vectorResults), len(keywordResults))
 
-	var retrievalCfg *types.RetrievalConfig
-	if tenantInfo != nil {
-		retrievalCfg = tenantInfo.RetrievalConfig
-	}
-	deduplicatedChunks := fuseOrDeduplicate(ctx, vectorResults, keywordResults, retrievalCfg)
+	deduplicatedChunks := fuseOrDeduplicate(ctx, vectorResults, keywordResults, fusionCfg)
 
 	kb.EnsureDefaults()
 
@@ -371,16 +381,16 @@ func (s *knowledgeBaseService) buildRetrievalParams(
 	for _, kb := range groupKBs {
 		if kb.IsVectorEnabled() && kb.EmbeddingModelID != "" {
 			if kb.Type == types.KnowledgeBaseTypeFAQ {
-				faqVectorKBIDs = append(faqVectorKBIDs, kb.ID)
+				faqVectorKBIDs = append(faqVectorKBIDs, kb.IndexKnowledgeBaseID())
 			} else {
-				docVectorKBIDs = append(docVectorKBIDs, kb.ID)
+				docVectorKBIDs = append(docVectorKBIDs, kb.IndexKnowledgeBaseID())
 			}
 		}
 		// FAQ KBs are retrieved exclusively via the FAQ vector index and
 		// have no keyword index; only document-type KBs participate in
 		// keyword retrieval.
 		if kb.IsKeywordEnabled() && kb.Type != types.KnowledgeBaseTypeFAQ {
-			docKeywordKBIDs = append(docKeywordKBIDs, kb.ID)
+			docKeywordKBIDs = append(docKeywordKBIDs, kb.IndexKnowledgeBaseID())
 		}
 	}
 
@@ -396,15 +406,17 @@ func (s *knowledgeBaseService) buildRetrievalParams(
 
 		appendVectorParams := func(kbIDs []string, knowledgeType string) {
 			retrieveParams = append(retrieveParams, types.RetrieveParams{
-				Query:            params.QueryText,
-				Embedding:        queryEmbedding,
-				KnowledgeBaseIDs: kbIDs,
-				TopK:             matchCount,
-				Threshold:        params.VectorThreshold,
-				RetrieverType:    types.VectorRetrieverType,
-				KnowledgeIDs:     params.KnowledgeIDs,
-				TagIDs:           params.TagIDs,
-				KnowledgeType:    knowledgeType,
+				Query:               params.QueryText,
+				Embedding:           queryEmbedding,
+				KnowledgeBaseIDs:    kbIDs,
+				TopK:                matchCount,
+				Threshold:           params.VectorThreshold,
+				RetrieverType:       types.VectorRetrieverType,
+				KnowledgeIDs:        params.KnowledgeIDs,
+				ExcludeKnowledgeIDs: params.ExcludeKnowledgeIDs,
+				TagIDs:              params.TagIDs,
+				KnowledgeType:       knowledgeType,
+				MetadataFilter:      params.MetadataFilter,
 			})
 		}
 
@@ -426,13 +438,15 @@ func (s *knowledgeBaseService) buildRetrievalParams(
 		len(docKeywordKBIDs) > 0 {
 		logger.Info(ctx, "Keyword retrieval supported, preparing keyword retrieval parameters")
 		retrieveParams = append(retrieveParams, types.RetrieveParams{
-			Query:            params.QueryText,
-			KnowledgeBaseIDs: docKeywordKBIDs,
-			TopK:             matchCount,
-			Threshold:        params.KeywordThreshold,
-			RetrieverType:    types.KeywordsRetrieverType,
-			KnowledgeIDs:     params.KnowledgeIDs,
-			TagIDs:           params.TagIDs,
+			Query:               params.QueryText,
+			KnowledgeBaseIDs:    docKeywordKBIDs,
+			TopK:                matchCount,
+			Threshold:           params.KeywordThreshold,
+			RetrieverType:       types.KeywordsRetrieverType,
+			KnowledgeIDs:        params.KnowledgeIDs,
+			ExcludeKnowledgeIDs: params.ExcludeKnowledgeIDs,
+			TagIDs:              params.TagIDs,
+			MetadataFilter:      params.MetadataFilter,
 		})
 		logger.Info(ctx, "Keyword retrieval parameters setup completed")
 	}
@@ -433,6 +433,9 @@ func (f *fakeRetrieveEngineService) DeleteBySourceIDList(context.Context, []stri
 func (f *fakeRetrieveEngineService) DeleteByKnowledgeIDList(context.Context, []string, int, string) error {
 	panic("unused")
 }
+func (f *fakeRetrieveEngineService) DeleteByKnowledgeBaseIDList(context.Context, []string, int, string) error {
+	panic("unused")
+}
 func (f *fakeRetrieveEngineService) BatchUpdateChunkEnabledStatus(context.Context, map[string]bool) error {
 	panic("unused")
 }
@@ -5,6 +5,7 @@ import (
 
 	"slices"
 
+	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 )
@@ -28,9 +29,9 @@ func classifyRetrievalResults(ctx context.Context, retrieveResults []*types.Retr
 	return
 }
 
-// fuseOrDeduplicate either fuses vector+keyword results via RRF or deduplicates vector-only results.
-// retrievalCfg may be nil — defaults are then used for RRF parameters.
-func fuseOrDeduplicate(ctx context.Context, vectorResults, keywordResults []*types.IndexWithScore, retrievalCfg *types.RetrievalConfig) []*types.IndexWithScore {
+// fuseOrDeduplicate either fuses vector+keyword results or deduplicates single-retriever results.
+// fusionCfg may be nil — RRF with default parameters is then used.
+func fuseOrDeduplicate(ctx context.Context, vectorResults, keywordResults []*types.IndexWithScore, fusionCfg *types.FusionConfig) []*types.IndexWithScore {
 	if len(keywordResults) == 0 {
 		// Vector-only: keep original embedding scores (important for FAQ)
 		result := deduplicateByScore(vectorResults)
@@ -43,12 +44,81 @@ func fuseOrDeduplicate(ctx context.Context, vectorResults, keywordResults []*typ
 		logger.Infof(ctx, "Result count after deduplication: %d", len(result))
 		return result
 	}
-	// Hybrid: use RRF fusion to merge vector + keyword results
-	result := fuseWithRRF(ctx, vectorResults, keywordResults, retrievalCfg)
-	logger.Infof(ctx, "Result count after RRF fusion: %d", len(result))
+	// Hybrid: merge vector + keyword results with the configured strategy
+	strategy := fusionCfg.GetEffectiveFusionStrategy()
+	var result []*types.IndexWithScore
+	if strategy.IsScoreBased() {
+		result = fuseWithScores(ctx, vectorResults, keywordResults, fusionCfg)
+	} else {
+		result = fuseWithRRF(ctx, vectorResults, keywordResults, fusionCfg)
+	}
+	logger.Infof(ctx, "Result count after %s fusion: %d", strategy, len(result))
 	return result
 }
 
+// resolveFusionConfig picks the fusion settings of a search: the primary
+// knowledge base's override when it has one, the tenant default otherwise.
+func resolveFusionConfig(kb *types.KnowledgeBase, tenant *types.Tenant) *types.FusionConfig {
+	if kb != nil && kb.FusionConfig != nil {
+		return kb.FusionConfig
+	}
+	if tenant != nil {
+		return tenant.RetrievalConfig.GetFusionConfig()
+	}
+	return nil
+}
+
+// normalizeForScoreFusion rescales every retriever list onto [0, 1] on its
+// own, so scores of different engines and retriever types can be summed. It
+// returns copies; the retrievers' hits keep their raw scores. Lists are only
+// rescaled for a hybrid search under a score-based strategy: RRF ignores
+// scores, and single-retriever results keep their raw scores (FAQ direct
+// answers compare the raw embedding similarity).
+func normalizeForScoreFusion(
+	retrieveResults []*types.RetrieveResult, fusionCfg *types.FusionConfig,
+) []*types.RetrieveResult {
+	if !fusionCfg.GetEffectiveFusionStrategy().IsScoreBased() {
+		return retrieveResults
+	}
+	hasVector, hasKeyword := false, false
+	for _, rr := range retrieveResults {
+		if len(rr.Results) == 0 {
+			continue
+		}
+		if rr.RetrieverType == types.VectorRetrieverType {
+			hasVector = true
+		} else {
+			hasKeyword = true
+		}
+	}
+	if !hasVector || !hasKeyword {
+		return retrieveResults
+	}
+
+	normalize := retriever.NormalizeMinMax
+	if fusionCfg.GetEffectiveNormalization() == types.FusionStrategyDBSF {
+		normalize = retriever.NormalizeDistribution
+	}
+	normalized := make([]*types.RetrieveResult, 0, len(retrieveResults))
+	for _, rr := range retrieveResults {
+		scores := make([]float64, len(rr.Results))
+		for i, r := range rr.Results {
+			scores[i] = r.Score
+		}
+		scores = normalize(scores)
+		results := make([]*types.IndexWithScore, len(rr.Results))
+		for i, r := range rr.Results {
+			hit := *r
+			hit.Score = scores[i]
+			results[i] = &hit
+		}
+		copied := *rr
+		copied.Results = results
+		normalized = append(normalized, &copied)
+	}
+	return normalized
+}
+
 // sortByScoreDesc is a reusable sort comparator for IndexWithScore slices (descending by Score).
 func sortByScoreDesc(a, b *types.IndexWithScore) int {
 	if a.Score > b.Score {
@@ -79,11 +149,11 @@ func deduplicateByScore(results []*types.IndexWithScore) []*types.IndexWithScore
 
 // fuseWithRRF merges vector and keyword retrieval results using Reciprocal Rank Fusion.
 // RRF score = vectorWeight/(k+vectorRank) + keywordWeight/(k+keywordRank).
-// k, vectorWeight and keywordWeight are sourced from retrievalCfg (with defaults).
+// k, vectorWeight and keywordWeight are sourced from fusionCfg (with defaults).
 // The merged results are sorted by RRF score descending.
-func fuseWithRRF(ctx context.Context, vectorResults, keywordResults []*types.IndexWithScore, retrievalCfg *types.RetrievalConfig) []*types.IndexWithScore {
-	rrfK := retrievalCfg.GetEffectiveRRFK()
-	vectorWeight, keywordWeight := retrievalCfg.GetEffectiveRRFWeights()
+func fuseWithRRF(ctx context.Context, vectorResults, keywordResults []*types.IndexWithScore, fusionCfg *types.FusionConfig) []*types.IndexWithScore {
+	rrfK := fusionCfg.GetEffectiveRRFK()
+	vectorWeight, keywordWeight := fusionCfg.GetEffectiveRRFWeights()
 
 	// Build rank maps for each retriever (already sorted by score from retriever)
 	vectorRanks := make(map[string]int, len(vectorResults))
@@ -140,3 +210,52 @@ func fuseWithRRF(ctx context.Context, vectorResults, keywordResults []*types.Ind
 
 	return result
 }
+
+// fuseWithScores merges vector and keyword results by weighted sum of their
+// normalized scores: vectorWeight*vectorScore + keywordWeight*keywordScore,
+// where a chunk missing from a list contributes 0 for it. The inputs must
+// already be normalized per engine (see normalizeForScoreFusion). Unlike RRF,
+// a keyword hit that scores far above the rest of its list keeps that lead,
+// which is what exact product-code or identifier queries need.
+func fuseWithScores(ctx context.Context, vectorResults, keywordResults []*types.IndexWithScore, fusionCfg *types.FusionConfig) []*types.IndexWithScore {
+	vectorWeight, keywordWeight := fusionCfg.GetEffectiveScoreWeights()
+
+	// Best normalized score of each chunk per retriever; a chunk can appear
+	// in several lists of the same retriever type when several engines ran.
+	vectorScores := make(map[string]float64, len(vectorResults))
+	chunkInfoMap := make(map[string]*types.IndexWithScore)
+	for _, r := range vectorResults {
+		if best, exists := vectorScores[r.ChunkID]; !exists || r.Score > best {
+			vectorScores[r.ChunkID] = r.Score
+			chunkInfoMap[r.ChunkID] = r
+		}
+	}
+	keywordScores := make(map[string]float64, len(keywordResults))
+	for _, r := range keywordResults {
+		if best, exists := keywordScores[r.ChunkID]; !exists || r.Score > best {
+			keywordScores[r.ChunkID] = r.Score
+		}
+		if _, exists := chunkInfoMap[r.ChunkID]; !exists {
+			chunkInfoMap[r.ChunkID] = r
+		}
+	}
+
+	result := make([]*types.IndexWithScore, 0, len(chunkInfoMap))
+	for chunkID, info := range chunkInfoMap {
+		info.Score = vectorWeight*vectorScores[chunkID] + keywordWeight*keywordScores[chunkID]
+		result = append(result, info)
+	}
+	slices.SortFunc(result, sortByScoreDesc)
+
+	for i, chunk := range result {
+		if i >= 15 {
+			break
+		}
+		vScore, vOk := vectorScores[chunk.ChunkID]
+		kScore, kOk := keywordScores[chunk.ChunkID]
+		logger.Debugf(ctx, "Score fusion rank %d: chunk_id=%s, fused_score=%.6f, vector_score=%.4f(%v), keyword_score=%.4f(%v)",
+			i, chunk.ChunkID, chunk.Score, vScore, vOk, kScore, kOk)
+	}
+
+	return result
+}
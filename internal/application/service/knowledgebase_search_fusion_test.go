@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func hits(scores map[string]float64, order ...string) []*types.IndexWithScore {
+	out := make([]*types.IndexWithScore, 0, len(order))
+	for _, id := range order {
+		out = append(out, &types.IndexWithScore{ChunkID: id, Score: scores[id]})
+	}
+	return out
+}
+
+func chunkIDs(results []*types.IndexWithScore) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ChunkID)
+	}
+	return ids
+}
+
+// fusionInput is a product-code query: the keyword engine finds the exact
+// code far ahead of everything else, while vector search ranks it second.
+func fusionInput() []*types.RetrieveResult {
+	return []*types.RetrieveResult{
+		{
+			RetrieverType:       types.VectorRetrieverType,
+			RetrieverEngineType: types.PostgresRetrieverEngineType,
+			Results: hits(map[string]float64{"a": 0.82, "code": 0.80, "b": 0.79, "c": 0.70},
+				"a", "code", "b", "c"),
+		},
+		{
+			RetrieverType:       types.KeywordsRetrieverType,
+			RetrieverEngineType: types.PostgresRetrieverEngineType,
+			Results:             hits(map[string]float64{"code": 25, "a": 2, "d": 1.5}, "code", "a", "d"),
+		},
+	}
+}
+
+func fuse(t *testing.T, cfg *types.FusionConfig) []*types.IndexWithScore {
+	t.Helper()
+	ctx := context.Background()
+	vector, keyword := classifyRetrievalResults(ctx, normalizeForScoreFusion(fusionInput(), cfg))
+	return fuseOrDeduplicate(ctx, vector, keyword, cfg)
+}
+
+func TestFuseOrDeduplicateStrategies(t *testing.T) {
+	// RRF only sees ranks, so "a" (1st and 2nd) beats the exact code match.
+	rrf := fuse(t, nil)
+	require.Equal(t, "a", rrf[0].ChunkID)
+
+	weighted := fuse(t, &types.FusionConfig{FusionStrategy: types.FusionStrategyWeighted})
+	require.Equal(t, "code", weighted[0].ChunkID)
+	require.ElementsMatch(t, []string{"a", "b", "c", "d", "code"}, chunkIDs(weighted))
+	// min-max: code = 0.5*(0.10/0.12) + 0.5*1
+	require.InDelta(t, 0.5*0.10/0.12+0.5, weighted[0].Score, 1e-9)
+
+	dbsf := fuse(t, &types.FusionConfig{FusionStrategy: types.FusionStrategyDBSF})
+	require.Equal(t, "code", dbsf[0].ChunkID)
+
+	// A vector-only weight set keeps the vector order.
+	learned := fuse(t, &types.FusionConfig{
+		FusionStrategy: types.FusionStrategyLearned,
+		LearnedWeights: &types.LearnedFusionWeights{VectorWeight: 1, Normalization: types.FusionStrategyWeighted},
+	})
+	require.Equal(t, []string{"a", "code", "b"}, chunkIDs(learned)[:3])
+}
+
+func TestNormalizeForScoreFusionKeepsRawScores(t *testing.T) {
+	input := fusionInput()
+	cfg := &types.FusionConfig{FusionStrategy: types.FusionStrategyWeighted}
+	normalized := normalizeForScoreFusion(input, cfg)
+	require.Equal(t, 1.0, normalized[1].Results[0].Score)
+	require.Equal(t, 25.0, input[1].Results[0].Score, "retriever hits must keep their raw score")
+
+	// RRF and single-retriever searches are passed through untouched.
+	require.Equal(t, input, normalizeForScoreFusion(input, nil))
+	require.Equal(t, input[:1], normalizeForScoreFusion(input[:1], cfg))
+}
+
+func TestResolveFusionConfig(t *testing.T) {
+	tenant := &types.Tenant{RetrievalConfig: &types.RetrievalConfig{
+		FusionConfig: types.FusionConfig{FusionStrategy: types.FusionStrategyDBSF},
+	}}
+	require.Equal(t, types.FusionStrategyDBSF,
+		resolveFusionConfig(&types.KnowledgeBase{}, tenant).GetEffectiveFusionStrategy())
+	kb := &types.KnowledgeBase{FusionConfig: &types.FusionConfig{FusionStrategy: types.FusionStrategyWeighted}}
+	require.Equal(t, types.FusionStrategyWeighted, resolveFusionConfig(kb, tenant).GetEffectiveFusionStrategy())
+	require.Equal(t, types.FusionStrategyRRF, resolveFusionConfig(nil, nil).GetEffectiveFusionStrategy())
+}
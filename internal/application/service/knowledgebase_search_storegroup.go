@@ -234,6 +234,31 @@ func (s *knowledgeBaseService) authorizeKBAccess(
 	return nil
 }
 
+// excludeDeniedKnowledge adds the documents of kbIDs that document access
+// lists hide from the caller to params.ExcludeKnowledgeIDs. A lookup failure
+// fails the search rather than returning unfiltered results.
+func (s *knowledgeBaseService) excludeDeniedKnowledge(
+	ctx context.Context,
+	kbIDs []string,
+	params *types.SearchParams,
+) error {
+	if s.knowledgeACL == nil {
+		return nil
+	}
+	denied, err := s.knowledgeACL.DeniedKnowledgeIDs(ctx, kbIDs)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, map[string]interface{}{
+			"knowledge_base_ids": kbIDs,
+			"reason":             "knowledge access list lookup failed",
+		})
+		return apperrors.NewInternalServerError("failed to verify document access")
+	}
+	for _, kbID := range kbIDs {
+		params.ExcludeKnowledgeIDs = append(params.ExcludeKnowledgeIDs, denied[kbID]...)
+	}
+	return nil
+}
+
 // validateSameEmbeddingModel rejects multi-KB searches that span more than
 // one resolved embedding-model identity key. Single-KB calls no-op.
 //
@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	apperrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// storeMigrationPageSize is the number of entries read from the source
+	// store per page; progress is checkpointed after every page.
+	storeMigrationPageSize = 500
+	// storeMigrationTaskTimeout bounds one attempt of the migration task.
+	storeMigrationTaskTimeout = 24 * time.Hour
+	// storeMigrationVerifyAttempts and storeMigrationVerifyInterval give
+	// near-real-time stores (Elasticsearch, OpenSearch, Milvus) time to make
+	// freshly written entries countable before the copy is rejected.
+	storeMigrationVerifyAttempts = 3
+	storeMigrationVerifyInterval = 2 * time.Second
+)
+
+// ErrVectorStoreMigrating is returned by index writes to a knowledge base
+// whose entries are being copied to another vector store. Task workers put a
+// task that fails with it back in the queue without counting an attempt.
+var ErrVectorStoreMigrating = errors.New("knowledge base is migrating to another vector store")
+
+// ErrEmbeddingModelMigrating is returned by index writes to a knowledge base
+// that is being re-embedded with another model: entries written with the old
+// model would be missing or stale after the switch. It matches
+// ErrVectorStoreMigrating, so writes and tasks are held the same way.
+var ErrEmbeddingModelMigrating error = embeddingModelMigratingError{}
+
+type embeddingModelMigratingError struct{}
+
+func (embeddingModelMigratingError) Error() string {
+	return "knowledge base is switching to another embedding model"
+}
+
+func (embeddingModelMigratingError) Is(target error) bool {
+	return target == ErrVectorStoreMigrating
+}
+
+// knowledgeBaseGetter is the knowledge base lookup the write fence needs; both
+// the knowledge base repository and service provide it.
+type knowledgeBaseGetter interface {
+	GetKnowledgeBaseByID(ctx context.Context, id string) (*types.KnowledgeBase, error)
+}
+
+// createIndexWriterForKB is CreateRetrieveEngineForKB for index writes. The
+// migration copies a snapshot of the source store and drops it after the
+// switch, so every write through the returned engine re-reads the listed
+// knowledge bases (the written one and, for copies and moves, the others
+// involved) and fails with ErrVectorStoreMigrating while any of them is
+// migrating. Re-embedding snapshots the index the same way and fences writes
+// with ErrEmbeddingModelMigrating.
+func createIndexWriterForKB(
+	ctx context.Context,
+	registry interfaces.RetrieveEngineRegistry,
+	ownership retriever.TenantStoreOwnership,
+	kbs knowledgeBaseGetter,
+	tenantID uint64,
+	vectorStoreID *string,
+	kbIDs ...string,
+) (*retriever.CompositeRetrieveEngine, error) {
+	engine, err := retriever.CreateRetrieveEngineForKB(ctx, registry, ownership, tenantID, vectorStoreID)
+	if err != nil {
+		return nil, err
+	}
+	return engine.WithWriteFence(vectorStoreMigrationFence(kbs, kbIDs...)), nil
+}
+
+// requireNoVectorStoreMigration fails with ErrVectorStoreMigrating when any of
+// the knowledge bases is migrating. Edits that update the database before the
+// index check it up front, so a fenced index write cannot leave the two apart.
+func requireNoVectorStoreMigration(ctx context.Context, kbs knowledgeBaseGetter, kbIDs ...string) error {
+	return vectorStoreMigrationFence(kbs, kbIDs...)(ctx)
+}
+
+// vectorStoreMigrationFence returns a write fence that fails while any of the
+// knowledge bases has an active vector store or embedding model migration.
+func vectorStoreMigrationFence(kbs knowledgeBaseGetter, kbIDs ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, id := range kbIDs {
+			if id == "" {
+				continue
+			}
+			kb, err := kbs.GetKnowledgeBaseByID(ctx, id)
+			if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("check vector store migration of knowledge base %s: %w", id, err)
+			}
+			if kb.VectorStoreMigration.Active() {
+				return ErrVectorStoreMigrating
+			}
+			if kb.EmbeddingMigration.Active() {
+				return ErrEmbeddingModelMigrating
+			}
+		}
+		return nil
+	}
+}
+
+// MigrateVectorStore moves a knowledge base's index entries to another vector
+// store.
+//
+// A background task copies every entry with its stored embedding, so the
+// embedding model is not called. Queries keep using the current store until
+// the copy is verified and the knowledge base is rebound; the returned
+// migration reports its progress.
+func (s *knowledgeBaseService) MigrateVectorStore(
+	ctx context.Context, id string, targetStoreID string,
+) (*types.VectorStoreMigration, error) {
+	if id == "" {
+		logger.Error(ctx, "Knowledge base ID is empty")
+		return nil, errors.New("knowledge base ID cannot be empty")
+	}
+	if targetStoreID == "" {
+		return nil, apperrors.NewBadRequestError("vector store ID cannot be empty")
+	}
+
+	logger.Infof(ctx, "Migrating knowledge base vector store, knowledge base ID: %s, target store: %s",
+		id, secutils.SanitizeForLog(targetStoreID))
+
+	tenantID := types.MustTenantIDFromContext(ctx)
+	kb, err := s.repo.GetKnowledgeBaseByIDAndTenant(ctx, id, tenantID)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, map[string]interface{}{
+			"knowledge_base_id": id,
+		})
+		return nil, err
+	}
+	if kb.VectorStoreMigration.Active() {
+		return nil, apperrors.NewConflictError("a vector store migration is already in progress")
+	}
+	if kb.EmbeddingMigration.Active() {
+		return nil, apperrors.NewConflictError("an embedding model migration is in progress")
+	}
+	sourceStoreID := ""
+	if kb.VectorStoreID != nil {
+		sourceStoreID = *kb.VectorStoreID
+	}
+	if sourceStoreID == targetStoreID {
+		return nil, apperrors.NewBadRequestError("knowledge base already uses this vector store")
+	}
+	if err := s.validateVectorStoreBinding(ctx, tenantID, targetStoreID); err != nil {
+		return nil, err
+	}
+	// Documents already being parsed would have their index writes fenced
+	// halfway through; pending ones are held by the worker until the switch.
+	processing, err := s.kgRepo.CountKnowledgeByStatus(ctx, tenantID, kb.ID, embeddingMigrationBusyStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if processing > 0 {
+		return nil, apperrors.NewConflictError("documents are still being processed; retry when they finish")
+	}
+
+	source, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, tenantID, &targetStoreID)
+	if err != nil {
+		return nil, err
+	}
+	if kb.NeedsEmbeddingModel() && !target.SupportRetriever(types.VectorRetrieverType) {
+		return nil, apperrors.NewBadRequestError("target vector store does not support vector retrieval")
+	}
+	if kb.IndexingStrategy.KeywordEnabled && !target.SupportRetriever(types.KeywordsRetrieverType) {
+		return nil, apperrors.NewBadRequestError("target vector store does not support keyword retrieval")
+	}
+
+	// Fail fast on engines that cannot read their entries back, instead of
+	// queueing a task that can never succeed.
+	dimension, err := s.storeMigrationDimension(ctx, kb)
+	if err != nil {
+		return nil, err
+	}
+	label := kb.IndexKnowledgeBaseID()
+	total, err := source.CountIndices(ctx, label, dimension, kb.Type)
+	if errors.Is(err, retriever.ErrIndexExportUnsupported) {
+		return nil, apperrors.NewBadRequestError("current vector store does not support migration")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := target.CountIndices(ctx, label, dimension, kb.Type); errors.Is(err, retriever.ErrIndexExportUnsupported) {
+		return nil, apperrors.NewBadRequestError("target vector store does not support migration")
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	migration := &types.VectorStoreMigration{
+		TaskID:        secutils.GenerateTaskID("kb_vector_store_migrate", tenantID, id),
+		SourceStoreID: sourceStoreID,
+		TargetStoreID: targetStoreID,
+		Status:        types.VectorStoreMigrationPending,
+		Total:         total,
+		UpdatedAt:     now,
+	}
+	if err := s.repo.UpdateVectorStoreMigration(ctx, id, migration); err != nil {
+		return nil, err
+	}
+
+	payload := types.KBVectorStoreMigratePayload{
+		TenantID:        tenantID,
+		KnowledgeBaseID: id,
+		TaskID:          migration.TaskID,
+	}
+	langfuse.InjectTracing(ctx, &payload)
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	task := asynq.NewTask(types.TypeKBVectorStoreMigrate, payloadBytes,
+		asynq.TaskID(migration.TaskID), asynq.Queue(types.QueueMaintenance),
+		asynq.MaxRetry(3), asynq.Timeout(storeMigrationTaskTimeout))
+	if _, err := s.asynqClient.Enqueue(task); err != nil {
+		logger.Errorf(ctx, "Failed to enqueue KB vector store migration task: %v", err)
+		migration.Status = types.VectorStoreMigrationFailed
+		migration.Error = "failed to enqueue task"
+		migration.FinishedAt = &now
+		_ = s.repo.UpdateVectorStoreMigration(ctx, id, migration)
+		return nil, fmt.Errorf("failed to enqueue vector store migration task: %w", err)
+	}
+
+	logger.Infof(ctx, "KB vector store migration task enqueued: %s, knowledge base ID: %s, entries: %d",
+		migration.TaskID, id, total)
+	return migration, nil
+}
+
+// ProcessVectorStoreMigration handles the vector store migration task.
+//
+// Entries are exported page by page from the source store and written to the
+// target store with their stored embeddings. The cursor is saved after every
+// page, so a retry resumes where the previous attempt stopped. While the
+// migration is active every index write to the knowledge base is fenced (see
+// createIndexWriterForKB): edits are refused and ingestion tasks wait in the
+// queue, so the source does not change under the copy and matching counts
+// mean a complete copy. Once the target holds as many entries as the source,
+// SwitchVectorStore rebinds the knowledge base and the source entries are
+// dropped. On the last failed
+// attempt the partial copy is dropped and the knowledge base stays on the
+// source store.
+func (s *knowledgeBaseService) ProcessVectorStoreMigration(ctx context.Context, t *asynq.Task) error {
+	var payload types.KBVectorStoreMigratePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "Failed to unmarshal KB vector store migration payload: %v", err)
+		return asynq.SkipRetry
+	}
+
+	tenantID := payload.TenantID
+	kbID := payload.KnowledgeBaseID
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, tenantID)
+	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant info: %w", err)
+	}
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
+
+	kb, err := s.repo.GetKnowledgeBaseByIDAndTenant(ctx, kbID, tenantID)
+	if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+		logger.Warnf(ctx, "KB vector store migration task skipped, knowledge base %s no longer exists", kbID)
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		return err
+	}
+	migration := kb.VectorStoreMigration
+	if !migration.Active() || migration.TaskID != payload.TaskID {
+		logger.Infof(ctx, "KB vector store migration task %s is stale for knowledge base %s, skipping",
+			payload.TaskID, kbID)
+		return nil
+	}
+
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	isLastRetry := retryCount >= maxRetry
+	logger.Infof(ctx, "Processing KB vector store migration task: %s, knowledge base: %s, retry: %d/%d",
+		payload.TaskID, kbID, retryCount, maxRetry)
+
+	source, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, tenantID, kb.VectorStoreID)
+	if errors.Is(err, retriever.ErrVectorStoreForbidden) || errors.Is(err, retriever.ErrVectorStoreNotFound) {
+		s.failVectorStoreMigration(ctx, kb, nil, 0, err)
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		return err
+	}
+	targetStoreID := migration.TargetStoreID
+	target, err := retriever.CreateRetrieveEngineForKB(ctx, s.retrieveEngine, s.ownership, tenantID, &targetStoreID)
+	if errors.Is(err, retriever.ErrVectorStoreForbidden) || errors.Is(err, retriever.ErrVectorStoreNotFound) {
+		s.failVectorStoreMigration(ctx, kb, nil, 0, err)
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		return err
+	}
+	dimension, err := s.storeMigrationDimension(ctx, kb)
+	if err != nil {
+		s.failVectorStoreMigration(ctx, kb, nil, 0, err)
+		return asynq.SkipRetry
+	}
+	label := kb.IndexKnowledgeBaseID()
+	labels := []string{label}
+
+	// fail gives up on the last attempt and otherwise lets asynq retry from
+	// the last checkpoint.
+	fail := func(err error) error {
+		if isLastRetry {
+			s.failVectorStoreMigration(ctx, kb, target, dimension, err)
+			return asynq.SkipRetry
+		}
+		migration.Error = err.Error()
+		migration.UpdatedAt = time.Now()
+		_ = s.repo.UpdateVectorStoreMigration(ctx, kbID, migration)
+		return err
+	}
+
+	// A fresh start drops whatever an earlier, abandoned copy left behind in
+	// the target. Counting first skips the delete on stores that would reject
+	// it for a missing collection.
+	if migration.Status == types.VectorStoreMigrationPending || migration.Cursor == "" {
+		leftover, err := target.CountIndices(ctx, label, dimension, kb.Type)
+		if err != nil {
+			return fail(fmt.Errorf("count target entries: %w", err))
+		}
+		if leftover > 0 {
+			if err := target.DeleteByKnowledgeBaseIDList(ctx, labels, dimension, kb.Type); err != nil {
+				return fail(fmt.Errorf("clear target store: %w", err))
+			}
+		}
+		total, err := source.CountIndices(ctx, label, dimension, kb.Type)
+		if err != nil {
+			return fail(fmt.Errorf("count source entries: %w", err))
+		}
+		migration.Total = total
+		migration.Copied = 0
+	}
+	now := time.Now()
+	if migration.StartedAt == nil {
+		migration.StartedAt = &now
+	}
+	migration.Status = types.VectorStoreMigrationRunning
+	migration.Error = ""
+	migration.UpdatedAt = now
+	if err := s.repo.UpdateVectorStoreMigration(ctx, kbID, migration); err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := source.ExportIndices(ctx, label, dimension, kb.Type, migration.Cursor, storeMigrationPageSize)
+		if err != nil {
+			return fail(fmt.Errorf("export entries: %w", err))
+		}
+		// Source IDs are store-specific (row IDs, point IDs); derive a stable
+		// ID per entry so stores that key rows by it overwrite on a resumed page.
+		for _, entry := range page.Entries {
+			entry.ID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(label+"/"+entry.ID)).String()
+		}
+		if err := target.ImportIndices(ctx, page); err != nil {
+			return fail(fmt.Errorf("import entries: %w", err))
+		}
+		migration.Copied += int64(len(page.Entries))
+		migration.Cursor = page.NextCursor
+		migration.UpdatedAt = time.Now()
+		if page.NextCursor == "" {
+			break
+		}
+		if err := s.repo.UpdateVectorStoreMigration(ctx, kbID, migration); err != nil {
+			logger.Warnf(ctx, "Failed to checkpoint vector store migration of knowledge base %s: %v", kbID, err)
+		}
+	}
+
+	sourceCount, targetCount, err := s.verifyVectorStoreMigration(ctx, source, target, label, dimension, kb.Type)
+	if err != nil {
+		return fail(err)
+	}
+	migration.SourceCount = sourceCount
+	migration.TargetCount = targetCount
+	if sourceCount != targetCount {
+		// Restart from scratch on the next attempt: the copy cannot be trusted
+		// and the cursor no longer tells which entries are missing.
+		migration.Cursor = ""
+		migration.Copied = 0
+		return fail(fmt.Errorf("verification failed: source has %d entries, target has %d",
+			sourceCount, targetCount))
+	}
+
+	// Cut over: from here on every read and write of the knowledge base goes
+	// to the target store.
+	finished := time.Now()
+	migration.Status = types.VectorStoreMigrationCompleted
+	migration.Copied = targetCount
+	migration.Cursor = ""
+	migration.FinishedAt = &finished
+	migration.UpdatedAt = finished
+	err = s.repo.SwitchVectorStore(ctx, kbID, targetStoreID, migration)
+	if errors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+		logger.Warnf(ctx, "Knowledge base %s was deleted during vector store migration, dropping copy", kbID)
+		if err := target.DeleteByKnowledgeBaseIDList(ctx, labels, dimension, kb.Type); err != nil {
+			logger.Warnf(ctx, "Failed to drop migrated entries of knowledge base %s: %v", kbID, err)
+		}
+		return asynq.SkipRetry
+	}
+	if err != nil {
+		migration.Status = types.VectorStoreMigrationRunning
+		migration.FinishedAt = nil
+		return fail(fmt.Errorf("switch vector store: %w", err))
+	}
+
+	// The switch is committed; failing to drop the source entries only leaves
+	// unreachable rows behind, so it does not fail the task.
+	if err := source.DeleteByKnowledgeBaseIDList(ctx, labels, dimension, kb.Type); err != nil {
+		logger.Warnf(ctx, "Failed to delete source entries of knowledge base %s: %v", kbID, err)
+	}
+
+	logger.Infof(ctx, "KB vector store migration task completed: %s, knowledge base: %s, entries: %d",
+		payload.TaskID, kbID, targetCount)
+	return nil
+}
+
+// verifyVectorStoreMigration counts the label's entries in both stores. A
+// mismatch is re-counted a few times, as some stores expose new entries to
+// counts only after a refresh.
+func (s *knowledgeBaseService) verifyVectorStoreMigration(
+	ctx context.Context,
+	source, target *retriever.CompositeRetrieveEngine,
+	label string, dimension int, knowledgeType string,
+) (int64, int64, error) {
+	var sourceCount, targetCount int64
+	for attempt := 0; attempt < storeMigrationVerifyAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, 0, ctx.Err()
+			case <-time.After(storeMigrationVerifyInterval):
+			}
+		}
+		var err error
+		if sourceCount, err = source.CountIndices(ctx, label, dimension, knowledgeType); err != nil {
+			return 0, 0, fmt.Errorf("count source entries: %w", err)
+		}
+		if targetCount, err = target.CountIndices(ctx, label, dimension, knowledgeType); err != nil {
+			return 0, 0, fmt.Errorf("count target entries: %w", err)
+		}
+		if sourceCount == targetCount {
+			break
+		}
+	}
+	return sourceCount, targetCount, nil
+}
+
+// storeMigrationDimension returns the vector dimension of the knowledge
+// base's entries, or 0 when it keeps no vectors.
+func (s *knowledgeBaseService) storeMigrationDimension(ctx context.Context, kb *types.KnowledgeBase) (int, error) {
+	if !kb.NeedsEmbeddingModel() || kb.EmbeddingModelID == "" {
+		return 0, nil
+	}
+	embedder, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
+	if err != nil {
+		return 0, fmt.Errorf("load embedding model: %w", err)
+	}
+	return embedder.GetDimensions(), nil
+}
+
+// failVectorStoreMigration marks the migration failed and drops the partial
+// copy from the target store. target is nil when the failure happened before
+// it could be resolved; nothing has been written in that case.
+func (s *knowledgeBaseService) failVectorStoreMigration(
+	ctx context.Context,
+	kb *types.KnowledgeBase,
+	target *retriever.CompositeRetrieveEngine,
+	dimension int,
+	cause error,
+) {
+	migration := kb.VectorStoreMigration
+	logger.Errorf(ctx, "KB vector store migration task %s failed for knowledge base %s: %v",
+		migration.TaskID, kb.ID, cause)
+	if target != nil {
+		if err := target.DeleteByKnowledgeBaseIDList(
+			ctx, []string{kb.IndexKnowledgeBaseID()}, dimension, kb.Type,
+		); err != nil {
+			logger.Warnf(ctx, "Failed to drop partial copy of knowledge base %s: %v", kb.ID, err)
+		}
+	}
+	now := time.Now()
+	migration.Status = types.VectorStoreMigrationFailed
+	migration.Error = cause.Error()
+	migration.Cursor = ""
+	migration.FinishedAt = &now
+	migration.UpdatedAt = now
+	if err := s.repo.UpdateVectorStoreMigration(ctx, kb.ID, migration); err != nil {
+		logger.Warnf(ctx, "Failed to save failed vector store migration state for knowledge base %s: %v", kb.ID, err)
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+// storeMigrationEngine is an in-memory vector store that can export and
+// import its entries. Pages hold pageSize entries and the cursor is the
+// offset of the next one.
+type storeMigrationEngine struct {
+	parentChildRetrieveEngine
+	pageSize   int
+	entries    []*types.IndexInfo
+	embeddings map[string][]float32
+	importErr  error
+	imported   int
+}
+
+func (e *storeMigrationEngine) labelEntries(label string) []*types.IndexInfo {
+	var out []*types.IndexInfo
+	for _, entry := range e.entries {
+		if entry.KnowledgeBaseID == label {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func (e *storeMigrationEngine) ExportIndices(
+	_ context.Context, label string, _ int, _ string, cursor string, _ int,
+) (*types.IndexExportPage, error) {
+	offset := 0
+	if cursor != "" {
+		offset, _ = strconv.Atoi(cursor)
+	}
+	entries := e.labelEntries(label)
+	end := min(offset+e.pageSize, len(entries))
+	page := &types.IndexExportPage{Embeddings: map[string][]float32{}}
+	for _, entry := range entries[offset:end] {
+		clone := *entry
+		page.Entries = append(page.Entries, &clone)
+		page.Embeddings[entry.SourceID] = e.embeddings[entry.SourceID]
+	}
+	if end < len(entries) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func (e *storeMigrationEngine) CountIndices(_ context.Context, label string, _ int, _ string) (int64, error) {
+	return int64(len(e.labelEntries(label))), nil
+}
+
+func (e *storeMigrationEngine) ImportIndices(
+	_ context.Context, page *types.IndexExportPage, _ []types.RetrieverType,
+) error {
+	if e.importErr != nil {
+		return e.importErr
+	}
+	for _, entry := range page.Entries {
+		e.entries = append(e.entries, entry)
+		e.embeddings[entry.SourceID] = page.Embeddings[entry.SourceID]
+		e.imported++
+	}
+	return nil
+}
+
+func (e *storeMigrationEngine) DeleteByKnowledgeBaseIDList(
+	_ context.Context, labels []string, _ int, _ string,
+) error {
+	kept := e.entries[:0]
+	for _, entry := range e.entries {
+		if entry.KnowledgeBaseID != labels[0] {
+			kept = append(kept, entry)
+		}
+	}
+	e.entries = kept
+	return nil
+}
+
+// storeMigrationRegistry serves the source engine as the env store and the
+// target engine as the DB store validKBStoreUUID.
+type storeMigrationRegistry struct {
+	interfaces.RetrieveEngineRegistry
+	source, target interfaces.RetrieveEngineService
+}
+
+func (r storeMigrationRegistry) GetRetrieveEngineService(
+	types.RetrieverEngineType,
+) (interfaces.RetrieveEngineService, error) {
+	return r.source, nil
+}
+
+func (r storeMigrationRegistry) GetOrLoadByStoreID(
+	_ context.Context, _ uint64, storeID string,
+) (interfaces.RetrieveEngineService, error) {
+	if storeID != validKBStoreUUID {
+		return nil, errors.New("not registered")
+	}
+	return r.target, nil
+}
+
+// storeMigrationCtx is a request context as the auth middleware builds it:
+// env-store resolution reads the tenant's engines from it.
+func storeMigrationCtx(svc *knowledgeBaseService) context.Context {
+	tenant, _ := svc.tenantRepo.GetTenantByID(context.Background(), 1)
+	return context.WithValue(ctxWithTenant(1), types.TenantInfoContextKey, tenant)
+}
+
+func newStoreMigrationTestService(
+	t *testing.T, entries int,
+) (*knowledgeBaseService, *fakeKBRepo, *storeMigrationEngine, *storeMigrationEngine, *metadataUpdateTaskEnqueuer) {
+	t.Helper()
+	svc, repo, _, enqueuer := newReembedTestService(nil, nil)
+	source := &storeMigrationEngine{pageSize: 2, embeddings: map[string][]float32{}}
+	for i := range entries {
+		sourceID := "chunk-" + strconv.Itoa(i)
+		source.entries = append(source.entries, &types.IndexInfo{
+			ID:              strconv.Itoa(i + 1),
+			SourceID:        sourceID,
+			ChunkID:         sourceID,
+			KnowledgeID:     "knowledge-1",
+			KnowledgeBaseID: reembedKBID,
+			Content:         "content " + sourceID,
+			IsEnabled:       true,
+		})
+		source.embeddings[sourceID] = []float32{float32(i), 0.5}
+	}
+	target := &storeMigrationEngine{pageSize: 2, embeddings: map[string][]float32{}}
+	svc.retrieveEngine = storeMigrationRegistry{source: source, target: target}
+	svc.ownership = &fakeOwnership{owned: map[string]uint64{validKBStoreUUID: 1}}
+	return svc, repo, source, target, enqueuer
+}
+
+func TestVectorStoreMigrationCopiesStoredEmbeddingsAndSwitches(t *testing.T) {
+	svc, repo, source, target, enqueuer := newStoreMigrationTestService(t, 5)
+
+	migration, err := svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+	require.NoError(t, err)
+	require.Equal(t, types.VectorStoreMigrationPending, migration.Status)
+	require.Equal(t, int64(5), migration.Total)
+	require.Len(t, enqueuer.tasks, 1)
+	require.Equal(t, types.TypeKBVectorStoreMigrate, enqueuer.tasks[0].Type())
+
+	// Queries stay on the current store until the task cuts over.
+	require.Nil(t, repo.rows[reembedKBID].VectorStoreID)
+	_, err = svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+	require.Error(t, err, "a second migration must be rejected while one is active")
+	_, err = svc.SetEmbeddingModel(ctxWithTenant(1), reembedKBID, reembedTargetModel)
+	require.Error(t, err, "re-embedding must wait for the store migration")
+
+	require.NoError(t, svc.ProcessVectorStoreMigration(context.Background(), enqueuer.tasks[0]))
+
+	require.Len(t, target.entries, 5)
+	ids := map[string]bool{}
+	for i, entry := range target.entries {
+		require.Equal(t, reembedKBID, entry.KnowledgeBaseID)
+		require.Equal(t, source.embeddings["chunk-"+strconv.Itoa(i)], target.embeddings[entry.SourceID],
+			"stored embeddings are copied, not recomputed")
+		ids[entry.ID] = true
+	}
+	require.Len(t, ids, 5)
+	require.Empty(t, source.entries, "source entries are dropped after the switch")
+
+	kb := repo.rows[reembedKBID]
+	require.NotNil(t, kb.VectorStoreID)
+	require.Equal(t, validKBStoreUUID, *kb.VectorStoreID)
+	require.Equal(t, types.VectorStoreMigrationCompleted, kb.VectorStoreMigration.Status)
+	require.Equal(t, int64(5), kb.VectorStoreMigration.SourceCount)
+	require.Equal(t, int64(5), kb.VectorStoreMigration.TargetCount)
+	require.Equal(t, 100, kb.VectorStoreMigration.Progress())
+}
+
+func TestVectorStoreMigrationResumesFromCheckpoint(t *testing.T) {
+	svc, repo, source, target, enqueuer := newStoreMigrationTestService(t, 5)
+	_, err := svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+	require.NoError(t, err)
+
+	// An earlier attempt copied the first page and checkpointed after it.
+	for _, entry := range source.entries[:2] {
+		clone := *entry
+		target.entries = append(target.entries, &clone)
+	}
+	migration := repo.rows[reembedKBID].VectorStoreMigration
+	migration.Status = types.VectorStoreMigrationRunning
+	migration.Copied = 2
+	migration.Cursor = "2"
+
+	require.NoError(t, svc.ProcessVectorStoreMigration(context.Background(), enqueuer.tasks[0]))
+
+	require.Equal(t, 3, target.imported, "only entries after the checkpoint are copied")
+	require.Len(t, target.entries, 5)
+	kb := repo.rows[reembedKBID]
+	require.Equal(t, validKBStoreUUID, *kb.VectorStoreID)
+	require.Equal(t, types.VectorStoreMigrationCompleted, kb.VectorStoreMigration.Status)
+}
+
+func TestVectorStoreMigrationKeepsSourceStoreWhenLastAttemptFails(t *testing.T) {
+	svc, repo, source, target, enqueuer := newStoreMigrationTestService(t, 3)
+	target.importErr = errors.New("target store unavailable")
+
+	_, err := svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+	require.NoError(t, err)
+
+	// Outside an asynq worker the retry count and max retry are both zero, so
+	// this is treated as the last attempt.
+	err = svc.ProcessVectorStoreMigration(context.Background(), enqueuer.tasks[0])
+	require.ErrorIs(t, err, asynq.SkipRetry)
+
+	kb := repo.rows[reembedKBID]
+	require.Nil(t, kb.VectorStoreID)
+	require.Equal(t, types.VectorStoreMigrationFailed, kb.VectorStoreMigration.Status)
+	require.Contains(t, kb.VectorStoreMigration.Error, "target store unavailable")
+	require.Len(t, source.entries, 3)
+
+	// A stale task for a migration that is no longer active is a no-op.
+	require.NoError(t, svc.ProcessVectorStoreMigration(context.Background(), enqueuer.tasks[0]))
+}
+
+func TestMigrateVectorStoreRejectsInvalidTargets(t *testing.T) {
+	svc, _, _, _, enqueuer := newStoreMigrationTestService(t, 1)
+
+	_, err := svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, "")
+	require.Error(t, err)
+	_, err = svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, "0193b8a0-1111-7000-8000-000000000002")
+	require.Error(t, err, "stores of other tenants or unknown stores are rejected")
+
+	svc.retrieveEngine = storeMigrationRegistry{
+		source: &parentChildRetrieveEngine{},
+		target: &storeMigrationEngine{embeddings: map[string][]float32{}},
+	}
+	_, err = svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+	require.Error(t, err, "engines without export support cannot be migrated")
+	require.Empty(t, enqueuer.tasks)
+}
+
+func TestVectorStoreMigrationFencesIndexWrites(t *testing.T) {
+	svc, repo, source, _, enqueuer := newStoreMigrationTestService(t, 3)
+	ctx := storeMigrationCtx(svc)
+	writer, err := createIndexWriterForKB(ctx, svc.retrieveEngine, svc.ownership, repo, 1, nil, reembedKBID)
+	require.NoError(t, err)
+
+	_, err = svc.MigrateVectorStore(ctx, reembedKBID, validKBStoreUUID)
+	require.NoError(t, err)
+
+	// Writes during the copy would be lost with the source store, so they
+	// are refused and the source stays as the migration found it.
+	err = writer.DeleteByKnowledgeBaseIDList(ctx, []string{reembedKBID}, 2, types.KnowledgeTypeManual)
+	require.ErrorIs(t, err, ErrVectorStoreMigrating)
+	require.Len(t, source.entries, 3)
+	require.ErrorIs(t, requireNoVectorStoreMigration(ctx, repo, reembedKBID), ErrVectorStoreMigrating)
+
+	require.NoError(t, svc.ProcessVectorStoreMigration(context.Background(), enqueuer.tasks[0]))
+	require.NoError(t, requireNoVectorStoreMigration(ctx, repo, reembedKBID))
+}
+
+func TestMigrateVectorStoreWaitsForProcessingDocuments(t *testing.T) {
+	for _, status := range embeddingMigrationBusyStatuses {
+		t.Run(status, func(t *testing.T) {
+			svc, repo, _, _, enqueuer := newStoreMigrationTestService(t, 1)
+			svc.kgRepo = &reembedKnowledgeRepo{knowledge: []*types.Knowledge{
+				{ID: "knowledge-1", KnowledgeBaseID: reembedKBID, ParseStatus: status},
+			}}
+
+			_, err := svc.MigrateVectorStore(storeMigrationCtx(svc), reembedKBID, validKBStoreUUID)
+			require.Error(t, err)
+			require.Nil(t, repo.rows[reembedKBID].VectorStoreMigration)
+			require.Empty(t, enqueuer.tasks)
+		})
+	}
+}
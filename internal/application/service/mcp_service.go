@@ -433,6 +433,17 @@ func (s *mcpServiceService) TestMCPService(
 		resources = []*types.MCPResource{}
 	}
 
+	// List prompts, only when advertised: unlike tools and resources they
+	// are optional enough that most servers answer with "method not found"
+	var prompts []*types.MCPPrompt
+	if client.ServerCapabilities().Prompts != nil {
+		prompts, err = client.ListPrompts(testCtx)
+		if err != nil {
+			logger.GetLogger(ctx).Warnf("Failed to list prompts: %v", err)
+			prompts = nil
+		}
+	}
+
 	return &types.MCPTestResult{
 		Success: true,
 		Message: fmt.Sprintf(
@@ -443,6 +454,7 @@ func (s *mcpServiceService) TestMCPService(
 		Description: initResult.ServerInfo.Description,
 		Tools:       tools,
 		Resources:   resources,
+		Prompts:     prompts,
 	}, nil
 }
 
@@ -613,3 +625,79 @@ func (s *mcpServiceService) GetMCPServiceResources(
 
 	return resources, nil
 }
+
+// GetMCPServicePrompts retrieves the list of prompt templates from an MCP service
+func (s *mcpServiceService) GetMCPServicePrompts(
+	ctx context.Context,
+	tenantID uint64,
+	id string,
+) ([]*types.MCPPrompt, error) {
+	// Get service
+	service, err := s.mcpServiceRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("MCP service not found")
+	}
+
+	// Get or create client
+	client, err := s.mcpManager.GetOrCreateClient(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+	if client.ServerCapabilities().Prompts == nil {
+		return []*types.MCPPrompt{}, nil
+	}
+
+	// List prompts
+	prompts, err := client.ListPrompts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	return prompts, nil
+}
+
+// mcpPromptRenderTimeout bounds rendering one MCP prompt, which runs while
+// an agent turn is being prepared.
+const mcpPromptRenderTimeout = 30 * time.Second
+
+// RenderMCPServicePrompt renders a prompt template of an MCP service. The
+// service must belong to the tenant and be enabled.
+func (s *mcpServiceService) RenderMCPServicePrompt(
+	ctx context.Context,
+	tenantID uint64,
+	ref *types.MCPPromptRef,
+) (string, error) {
+	if ref == nil || ref.ServiceID == "" || ref.Name == "" {
+		return "", fmt.Errorf("MCP prompt service ID and name are required")
+	}
+	service, err := s.mcpServiceRepo.GetByID(ctx, tenantID, ref.ServiceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get MCP service: %w", err)
+	}
+	if service == nil {
+		return "", fmt.Errorf("MCP service not found")
+	}
+	if !service.Enabled {
+		return "", fmt.Errorf("MCP service %s is disabled", service.Name)
+	}
+
+	client, err := s.mcpManager.GetOrCreateClient(ctx, service)
+	if err != nil {
+		return "", fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	renderCtx, cancel := context.WithTimeout(ctx, mcpPromptRenderTimeout)
+	defer cancel()
+	result, err := client.GetPrompt(renderCtx, ref.Name, ref.Arguments)
+	if err != nil {
+		return "", err
+	}
+	text := result.Text()
+	if text == "" {
+		return "", fmt.Errorf("MCP prompt %s has no text content", ref.Name)
+	}
+	return text, nil
+}
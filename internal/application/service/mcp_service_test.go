@@ -447,3 +447,14 @@ func TestListMCPServices_ReturnsRawCredentials(t *testing.T) {
 	assert.Equal(t, "real-api", got[0].AuthConfig.APIKey)
 	assert.Equal(t, "real-token", got[0].AuthConfig.Token)
 }
+
+// ---- RenderMCPServicePrompt ----
+
+func TestRenderMCPServicePrompt_RejectsDisabledService(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+	_ = repo.Create(ctx, &types.MCPService{ID: "svc-1", TenantID: 1, Name: "docs", Enabled: false})
+
+	_, err := svc.RenderMCPServicePrompt(ctx, 1, &types.MCPPromptRef{ServiceID: "svc-1", Name: "system"})
+	require.ErrorContains(t, err, "disabled")
+}
@@ -0,0 +1,118 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// judgeChatModel answers each judge prompt from a canned reply keyed by a
+// fragment of the system prompt
+type judgeChatModel struct {
+	replies map[string]string
+	calls   []string
+	err     error
+}
+
+func (m *judgeChatModel) Chat(
+	_ context.Context, messages []chat.Message, _ *chat.ChatOptions,
+) (*types.ChatResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	system := messages[0].Content
+	for key, reply := range m.replies {
+		if strings.Contains(system, key) {
+			m.calls = append(m.calls, key)
+			return &types.ChatResponse{Content: reply}, nil
+		}
+	}
+	return nil, errors.New("unexpected prompt")
+}
+
+func (m *judgeChatModel) ChatStream(
+	context.Context, []chat.Message, *chat.ChatOptions,
+) (<-chan types.StreamResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *judgeChatModel) GetModelName() string { return "judge" }
+func (m *judgeChatModel) GetModelID() string   { return "judge" }
+
+func TestLLMJudge_Judge(t *testing.T) {
+	model := &judgeChatModel{replies: map[string]string{
+		"grounded in the supplied context": `{"claims":4,"supported":3}`,
+		"how directly an answer":           "```json\n{\"score\":0.9}\n```",
+		"each numbered context chunk":      `{"relevant":[false,true,true]}`,
+		"reference answer can be found":    `{"statements":2,"attributed":1}`,
+	}}
+	got, err := NewLLMJudge(model).Judge(context.Background(), &types.JudgeInput{
+		Question:    "q",
+		Answer:      "a",
+		GroundTruth: "gt",
+		Contexts:    []string{"c1", "c2", "c3"},
+	})
+	if err != nil {
+		t.Fatalf("Judge() error = %v", err)
+	}
+	// AP over [false,true,true] = (1/2 + 2/3) / 2
+	want := types.JudgeMetrics{
+		Faithfulness:     0.75,
+		AnswerRelevance:  0.9,
+		ContextPrecision: (0.5 + 2.0/3.0) / 2,
+		ContextRecall:    0.5,
+	}
+	for name, pair := range map[string][2]float64{
+		"faithfulness":      {got.Faithfulness, want.Faithfulness},
+		"answer_relevance":  {got.AnswerRelevance, want.AnswerRelevance},
+		"context_precision": {got.ContextPrecision, want.ContextPrecision},
+		"context_recall":    {got.ContextRecall, want.ContextRecall},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+}
+
+func TestLLMJudge_SkipsContextMetricsWithoutInputs(t *testing.T) {
+	model := &judgeChatModel{replies: map[string]string{
+		"grounded in the supplied context": `{"claims":0,"supported":0}`,
+		"how directly an answer":           `{"score":1.7}`,
+	}}
+	got, err := NewLLMJudge(model).Judge(context.Background(), &types.JudgeInput{Question: "q", Answer: "a"})
+	if err != nil {
+		t.Fatalf("Judge() error = %v", err)
+	}
+	if len(model.calls) != 2 {
+		t.Errorf("judge made %d calls, want 2: %v", len(model.calls), model.calls)
+	}
+	if got.Faithfulness != 0 || got.AnswerRelevance != 1 || got.ContextPrecision != 0 || got.ContextRecall != 0 {
+		t.Errorf("Judge() = %+v", got)
+	}
+}
+
+func TestLLMJudge_PropagatesModelError(t *testing.T) {
+	model := &judgeChatModel{err: errors.New("boom")}
+	if _, err := NewLLMJudge(model).Judge(context.Background(), &types.JudgeInput{Question: "q"}); err == nil {
+		t.Fatal("Judge() error = nil, want model error")
+	}
+}
+
+func TestJudgeContexts_CapsAndTruncates(t *testing.T) {
+	contexts := make([]string, judgeMaxContexts+5)
+	for i := range contexts {
+		contexts[i] = strings.Repeat("x", judgeMaxContextRunes+10)
+	}
+	got := judgeContexts(contexts)
+	if strings.Count(got, "[") != judgeMaxContexts {
+		t.Errorf("judgeContexts kept %d chunks, want %d", strings.Count(got, "["), judgeMaxContexts)
+	}
+	if strings.Contains(got, strings.Repeat("x", judgeMaxContextRunes+1)) {
+		t.Error("judgeContexts did not truncate long chunks")
+	}
+}
@@ -50,8 +50,9 @@ var metricCalculators = []struct {
 	}},
 }
 
-// Append calculates and stores metrics for given input
-func (m *MetricList) Append(metricInput *types.MetricInput) {
+// Append calculates and stores metrics for given input, returning the
+// metrics of this input alone
+func (m *MetricList) Append(metricInput *types.MetricInput) *types.MetricResult {
 	result := &types.MetricResult{}
 	// Calculate all configured metrics
 	for _, c := range metricCalculators {
@@ -60,6 +61,7 @@ func (m *MetricList) Append(metricInput *types.MetricInput) {
 	}
 	logger.Infof(context.Background(), "metric: %v", result)
 	m.results = append(m.results, result)
+	return result
 }
 
 // Avg calculates average of all stored metric results
@@ -79,14 +81,44 @@ func (m *MetricList) Avg() *types.MetricResult {
 		}
 		*config.getField(avgResult) = sum / count
 	}
+	avgResult.JudgeMetrics = avgJudgeMetrics(m.results)
 	return avgResult
 }
 
+// avgJudgeMetrics averages judge scores over the results that have them,
+// so questions the judge failed on do not drag the average towards zero
+func avgJudgeMetrics(results []*types.MetricResult) *types.JudgeMetrics {
+	var sum types.JudgeMetrics
+	judged := 0
+	for _, r := range results {
+		if r.JudgeMetrics == nil {
+			continue
+		}
+		judged++
+		sum.Faithfulness += r.JudgeMetrics.Faithfulness
+		sum.AnswerRelevance += r.JudgeMetrics.AnswerRelevance
+		sum.ContextPrecision += r.JudgeMetrics.ContextPrecision
+		sum.ContextRecall += r.JudgeMetrics.ContextRecall
+	}
+	if judged == 0 {
+		return nil
+	}
+	count := float64(judged)
+	return &types.JudgeMetrics{
+		Faithfulness:     sum.Faithfulness / count,
+		AnswerRelevance:  sum.AnswerRelevance / count,
+		ContextPrecision: sum.ContextPrecision / count,
+		ContextRecall:    sum.ContextRecall / count,
+	}
+}
+
 // HookMetric tracks evaluation metrics for QA pairs
 type HookMetric struct {
 	qaPairMetricList []*qaPairMetric // Per-QA pair metrics
 	metricResults    *MetricList     // Aggregated results
 	mu               *sync.RWMutex   // Thread safety
+
+	judge interfaces.EvaluationJudge // Optional LLM judge, nil to skip judge metrics
 }
 
 // qaPairMetric stores metrics for a single QA pair
@@ -95,14 +127,18 @@ type qaPairMetric struct {
 	searchResult []*types.SearchResult
 	rerankResult []*types.SearchResult
 	chatResponse *types.ChatResponse
+
+	metricInput *types.MetricInput  // Input the metrics were computed from
+	metric      *types.MetricResult // Metrics of this QA pair alone
 }
 
-// NewHookMetric creates a new HookMetric with given capacity
-func NewHookMetric(capacity int) *HookMetric {
+// NewHookMetric creates a new HookMetric with given capacity; judge may be nil
+func NewHookMetric(capacity int, judge interfaces.EvaluationJudge) *HookMetric {
 	return &HookMetric{
 		metricResults:    &MetricList{},
 		qaPairMetricList: make([]*qaPairMetric, capacity),
 		mu:               &sync.RWMutex{},
+		judge:            judge,
 	}
 }
 
@@ -132,7 +168,7 @@ func (h *HookMetric) recordChatResponse(index int, chatResponse *types.ChatRespo
 }
 
 // recordFinish finalizes metrics for a QA pair
-func (h *HookMetric) recordFinish(index int) {
+func (h *HookMetric) recordFinish(ctx context.Context, index int) {
 	// Prepare retrieval source: prefer rerank results, fall back to search results
 	retrievalSource := h.qaPairMetricList[index].rerankResult
 	if len(retrievalSource) == 0 {
@@ -180,10 +216,53 @@ func (h *HookMetric) recordFinish(index int) {
 		GeneratedGT:    qaPair.Answer,
 	}
 
+	// The judge calls a model, so it runs before taking the lock
+	var judgeMetrics *types.JudgeMetrics
+	if h.judge != nil {
+		contexts := make([]string, 0, len(retrievalSource))
+		for _, r := range retrievalSource {
+			contexts = append(contexts, r.Content)
+		}
+		var err error
+		judgeMetrics, err = h.judge.Judge(ctx, &types.JudgeInput{
+			Question:    qaPair.Question,
+			Answer:      generatedTexts,
+			GroundTruth: qaPair.Answer,
+			Contexts:    contexts,
+		})
+		if err != nil {
+			logger.Warnf(ctx, "Failed to judge QA pair %d: %v", index, err)
+			judgeMetrics = nil
+		}
+	}
+
 	// Thread-safe append of metrics
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.metricResults.Append(metricInput)
+	h.qaPairMetricList[index].metricInput = metricInput
+	result := h.metricResults.Append(metricInput)
+	result.JudgeMetrics = judgeMetrics
+	h.qaPairMetricList[index].metric = result
+}
+
+// questionResult builds the persisted per-question result of a finished QA pair
+func (h *HookMetric) questionResult(index int) *types.EvaluationQuestionResult {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	record := h.qaPairMetricList[index]
+	if record == nil || record.metricInput == nil {
+		return nil
+	}
+	return &types.EvaluationQuestionResult{
+		QuestionIndex: index,
+		QID:           record.qaPair.QID,
+		Question:      record.qaPair.Question,
+		Expected:      record.metricInput.GeneratedGT,
+		Generated:     record.metricInput.GeneratedTexts,
+		RetrievalGT:   types.IntList(record.qaPair.PIDs),
+		RetrievalIDs:  types.IntList(record.metricInput.RetrievalIDs),
+		Metric:        record.metric,
+	}
 }
 
 // MetricResult returns the averaged metric results
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// stubJudge scores answers from a fixed table and fails on unknown ones
+type stubJudge struct {
+	scores map[string]*types.JudgeMetrics
+	inputs []*types.JudgeInput
+}
+
+func (s *stubJudge) Judge(_ context.Context, input *types.JudgeInput) (*types.JudgeMetrics, error) {
+	s.inputs = append(s.inputs, input)
+	if m, ok := s.scores[input.Answer]; ok {
+		return m, nil
+	}
+	return nil, errors.New("judge unavailable")
+}
+
+func finishJudgedPair(hook *HookMetric, index int, answer string) {
+	hook.recordInit(index)
+	hook.recordQaPair(index, &types.QAPair{
+		QID: index, Question: "question", Answer: "reference",
+		PIDs: []int{1}, Passages: []string{"passage"},
+	})
+	hook.recordSearchResult(index, []*types.SearchResult{{Content: "passage"}})
+	hook.recordChatResponse(index, &types.ChatResponse{Content: answer})
+	hook.recordFinish(context.Background(), index)
+}
+
+func TestHookMetricJudgeMetrics(t *testing.T) {
+	judge := &stubJudge{scores: map[string]*types.JudgeMetrics{
+		"good": {Faithfulness: 1, AnswerRelevance: 1, ContextPrecision: 1, ContextRecall: 1},
+		"weak": {Faithfulness: 0.5, AnswerRelevance: 0, ContextPrecision: 1, ContextRecall: 0},
+	}}
+	hook := NewHookMetric(3, judge)
+	finishJudgedPair(hook, 0, "good")
+	finishJudgedPair(hook, 1, "weak")
+	finishJudgedPair(hook, 2, "judge fails")
+
+	require.Len(t, judge.inputs, 3)
+	require.Equal(t, &types.JudgeInput{
+		Question: "question", Answer: "good", GroundTruth: "reference", Contexts: []string{"passage"},
+	}, judge.inputs[0])
+
+	require.Nil(t, hook.questionResult(2).Metric.JudgeMetrics)
+	require.Equal(t, 0.5, hook.questionResult(1).Metric.JudgeMetrics.Faithfulness)
+
+	// The failed question is left out of the judge averages
+	avg := hook.MetricResult().JudgeMetrics
+	require.NotNil(t, avg)
+	require.Equal(t, types.JudgeMetrics{
+		Faithfulness: 0.75, AnswerRelevance: 0.5, ContextPrecision: 1, ContextRecall: 0.5,
+	}, *avg)
+}
+
+func TestHookMetricWithoutJudge(t *testing.T) {
+	hook := NewHookMetric(1, nil)
+	finishJudgedPair(hook, 0, "answer")
+
+	result := hook.MetricResult()
+	require.Nil(t, result.JudgeMetrics)
+	_, ok := result.MetricValue(types.MetricFaithfulness)
+	require.False(t, ok)
+}
@@ -37,6 +37,18 @@ func (s *stubKBRepoForModelDelete) ListKnowledgeBasesByTenantID(context.Context,
 func (s *stubKBRepoForModelDelete) UpdateKnowledgeBase(context.Context, *types.KnowledgeBase) error {
 	return nil
 }
+func (s *stubKBRepoForModelDelete) UpdateEmbeddingMigration(context.Context, string, *types.EmbeddingMigration) error {
+	return nil
+}
+func (s *stubKBRepoForModelDelete) SwitchEmbeddingIndex(context.Context, string, string, string, *types.EmbeddingMigration) error {
+	return nil
+}
+func (s *stubKBRepoForModelDelete) UpdateVectorStoreMigration(context.Context, string, *types.VectorStoreMigration) error {
+	return nil
+}
+func (s *stubKBRepoForModelDelete) SwitchVectorStore(context.Context, string, string, *types.VectorStoreMigration) error {
+	return nil
+}
 func (s *stubKBRepoForModelDelete) DeleteKnowledgeBase(context.Context, string) error { return nil }
 func (s *stubKBRepoForModelDelete) CountByVectorStoreID(context.Context, *gorm.DB, uint64, string) (int64, error) {
 	return 0, nil
@@ -7,9 +7,11 @@ import (
 	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Tencent/WeKnora/internal/common"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/metrics"
 	"github.com/Tencent/WeKnora/internal/models/embedding"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -25,6 +27,26 @@ type engineInfo struct {
 // delegating operations to all registered engines
 type CompositeRetrieveEngine struct {
 	engineInfos []*engineInfo
+	// writeFence, when set, runs before every write and vetoes it by
+	// returning an error. See WithWriteFence.
+	writeFence func(ctx context.Context) error
+}
+
+// WithWriteFence returns a copy of the engine whose writes first consult
+// fence and fail with its error instead of reaching the stores. Reads are not
+// fenced.
+func (c *CompositeRetrieveEngine) WithWriteFence(fence func(ctx context.Context) error) *CompositeRetrieveEngine {
+	fenced := *c
+	fenced.writeFence = fence
+	return &fenced
+}
+
+// checkWriteFence runs the write fence, if any.
+func (c *CompositeRetrieveEngine) checkWriteFence(ctx context.Context) error {
+	if c.writeFence == nil {
+		return nil
+	}
+	return c.writeFence(ctx)
 }
 
 // Retrieve performs retrieval operations by delegating to the appropriate engine
@@ -40,7 +62,10 @@ func (c *CompositeRetrieveEngine) Retrieve(ctx context.Context,
 					continue
 				}
 				if slices.Contains(engineInfo.retrieverType, param.RetrieverType) {
+					start := time.Now()
 					result, err := engineInfo.retrieveEngine.Retrieve(ctx, param)
+					metrics.ObserveRetrieve(string(engineInfo.retrieveEngine.EngineType()),
+						string(param.RetrieverType), err, time.Since(start))
 					if err != nil {
 						return err
 					}
@@ -105,6 +130,9 @@ func (c *CompositeRetrieveEngine) BatchUpdateChunkEnabledStatus(
 	ctx context.Context,
 	chunkStatusMap map[string]bool,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.BatchUpdateChunkEnabledStatus(ctx, chunkStatusMap); err != nil {
 			return err
@@ -118,6 +146,9 @@ func (c *CompositeRetrieveEngine) BatchUpdateChunkTagID(
 	ctx context.Context,
 	chunkTagMap map[string]string,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.BatchUpdateChunkTagID(ctx, chunkTagMap); err != nil {
 			return err
@@ -198,6 +229,9 @@ func (c *CompositeRetrieveEngine) concurrentExecWithError(
 func (c *CompositeRetrieveEngine) Index(ctx context.Context,
 	embedder embedding.Embedder, indexInfo *types.IndexInfo,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	err := c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.Index(ctx, embedder, indexInfo, engineInfo.retrieverType); err != nil {
 			logger.Errorf(ctx, "Repository %s failed to save: %v", engineInfo.retrieveEngine.EngineType(), err)
@@ -212,6 +246,9 @@ func (c *CompositeRetrieveEngine) Index(ctx context.Context,
 func (c *CompositeRetrieveEngine) BatchIndex(ctx context.Context,
 	embedder embedding.Embedder, indexInfoList []*types.IndexInfo,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	// Deduplicate sourceIDs
 	indexInfoList = common.Deduplicate(func(info *types.IndexInfo) string { return info.SourceID }, indexInfoList...)
 	err := c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
@@ -233,6 +270,9 @@ func (c *CompositeRetrieveEngine) BatchIndex(ctx context.Context,
 func (c *CompositeRetrieveEngine) DeleteByChunkIDList(ctx context.Context,
 	chunkIDList []string, dimension int, knowledgeType string,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.DeleteByChunkIDList(ctx, chunkIDList, dimension, knowledgeType); err != nil {
 			logger.GetLogger(ctx).Errorf("Repository %s failed to delete chunk ID list: %v",
@@ -247,6 +287,9 @@ func (c *CompositeRetrieveEngine) DeleteByChunkIDList(ctx context.Context,
 func (c *CompositeRetrieveEngine) DeleteBySourceIDList(ctx context.Context,
 	sourceIDList []string, dimension int, knowledgeType string,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.DeleteBySourceIDList(ctx, sourceIDList, dimension, knowledgeType); err != nil {
 			logger.GetLogger(ctx).Errorf("Repository %s failed to delete source ID list: %v",
@@ -267,6 +310,9 @@ func (c *CompositeRetrieveEngine) CopyIndices(
 	dimension int,
 	knowledgeType string,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.CopyIndices(
 			ctx,
@@ -288,6 +334,9 @@ func (c *CompositeRetrieveEngine) CopyIndices(
 func (c *CompositeRetrieveEngine) DeleteByKnowledgeIDList(ctx context.Context,
 	knowledgeIDList []string, dimension int, knowledgeType string,
 ) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
 	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
 		if err := engineInfo.retrieveEngine.DeleteByKnowledgeIDList(ctx, knowledgeIDList, dimension, knowledgeType); err != nil {
 			logger.GetLogger(ctx).Errorf("Repository %s failed to delete knowledge ID list: %v",
@@ -298,6 +347,26 @@ func (c *CompositeRetrieveEngine) DeleteByKnowledgeIDList(ctx context.Context,
 	})
 }
 
+// DeleteByKnowledgeBaseIDList deletes vector embeddings by knowledge base (index label) ID list
+// from all registered repositories
+func (c *CompositeRetrieveEngine) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
+	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
+		if err := engineInfo.retrieveEngine.DeleteByKnowledgeBaseIDList(
+			ctx, knowledgeBaseIDList, dimension, knowledgeType,
+		); err != nil {
+			logger.GetLogger(ctx).Errorf("Repository %s failed to delete knowledge base ID list: %v",
+				engineInfo.retrieveEngine.EngineType(), err)
+			return err
+		}
+		return nil
+	})
+}
+
 // EstimateStorageSize estimates the storage size required for the provided index information
 func (c *CompositeRetrieveEngine) EstimateStorageSize(ctx context.Context,
 	embedder embedding.Embedder, indexInfoList []*types.IndexInfo,
@@ -0,0 +1,140 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// ErrIndexExportUnsupported is returned when a vector store engine cannot read
+// its entries back with their embeddings, so it cannot be the source or target
+// of a vector store migration.
+var ErrIndexExportUnsupported = errors.New("vector store engine does not support index export")
+
+// importBatchSize bounds one BatchSave call of ImportIndices; it stays well
+// under the per-request caps of the bulk-API backends.
+const importBatchSize = 100
+
+// indexImporter is implemented by engine services that can write exported
+// entries with their stored embeddings.
+type indexImporter interface {
+	ImportIndices(ctx context.Context, page *types.IndexExportPage, retrieverTypes []types.RetrieverType) error
+}
+
+// ExportIndices forwards to the repository when it supports export.
+func (v *KeywordsVectorHybridRetrieveEngineService) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	exporter, ok := v.indexRepository.(interfaces.RetrieveEngineIndexExporter)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", v.engineType, ErrIndexExportUnsupported)
+	}
+	return exporter.ExportIndices(ctx, knowledgeBaseID, dimension, knowledgeType, cursor, limit)
+}
+
+// CountIndices forwards to the repository when it supports export.
+func (v *KeywordsVectorHybridRetrieveEngineService) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	exporter, ok := v.indexRepository.(interfaces.RetrieveEngineIndexExporter)
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", v.engineType, ErrIndexExportUnsupported)
+	}
+	return exporter.CountIndices(ctx, knowledgeBaseID, dimension, knowledgeType)
+}
+
+// ImportIndices saves exported entries as they are, reusing their stored
+// embeddings instead of calling an embedding model. Entries keep their ID, so
+// stores that key rows by it overwrite rather than duplicate on a retry.
+func (v *KeywordsVectorHybridRetrieveEngineService) ImportIndices(ctx context.Context,
+	page *types.IndexExportPage, retrieverTypes []types.RetrieverType,
+) error {
+	withEmbedding := slices.Contains(retrieverTypes, types.VectorRetrieverType)
+	for batch := range slices.Chunk(page.Entries, importBatchSize) {
+		params := make(map[string]any)
+		if withEmbedding {
+			embeddingMap := make(map[string][]float32, len(batch))
+			for _, indexInfo := range batch {
+				if vector, ok := page.Embeddings[indexInfo.SourceID]; ok {
+					embeddingMap[indexInfo.SourceID] = vector
+				}
+			}
+			params["embedding"] = embeddingMap
+		}
+		if err := v.indexRepository.BatchSave(ctx, batch, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportEngine returns the engine whose entries are exported: the one serving
+// vector retrieval, as it holds the embeddings, else the keywords engine.
+func (c *CompositeRetrieveEngine) exportEngine() (interfaces.RetrieveEngineIndexExporter, error) {
+	var selected *engineInfo
+	for _, engineInfo := range c.engineInfos {
+		if engineInfo == nil {
+			continue
+		}
+		if slices.Contains(engineInfo.retrieverType, types.VectorRetrieverType) {
+			selected = engineInfo
+			break
+		}
+		if selected == nil {
+			selected = engineInfo
+		}
+	}
+	if selected == nil {
+		return nil, ErrIndexExportUnsupported
+	}
+	exporter, ok := selected.retrieveEngine.(interfaces.RetrieveEngineIndexExporter)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", selected.retrieveEngine.EngineType(), ErrIndexExportUnsupported)
+	}
+	return exporter, nil
+}
+
+// ExportIndices reads one page of a knowledge base label's entries, with
+// their embeddings, from the engine that holds them.
+func (c *CompositeRetrieveEngine) ExportIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+) (*types.IndexExportPage, error) {
+	exporter, err := c.exportEngine()
+	if err != nil {
+		return nil, err
+	}
+	return exporter.ExportIndices(ctx, knowledgeBaseID, dimension, knowledgeType, cursor, limit)
+}
+
+// CountIndices counts a knowledge base label's entries in the engine
+// ExportIndices reads from.
+func (c *CompositeRetrieveEngine) CountIndices(ctx context.Context,
+	knowledgeBaseID string, dimension int, knowledgeType string,
+) (int64, error) {
+	exporter, err := c.exportEngine()
+	if err != nil {
+		return 0, err
+	}
+	return exporter.CountIndices(ctx, knowledgeBaseID, dimension, knowledgeType)
+}
+
+// ImportIndices writes exported entries into all registered engines.
+func (c *CompositeRetrieveEngine) ImportIndices(ctx context.Context, page *types.IndexExportPage) error {
+	if len(page.Entries) == 0 {
+		return nil
+	}
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
+	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
+		importer, ok := engineInfo.retrieveEngine.(indexImporter)
+		if !ok {
+			return fmt.Errorf("%s: %w", engineInfo.retrieveEngine.EngineType(), ErrIndexExportUnsupported)
+		}
+		return importer.ImportIndices(ctx, page, engineInfo.retrieverType)
+	})
+}
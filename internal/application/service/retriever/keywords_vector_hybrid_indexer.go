@@ -289,6 +289,13 @@ func (v *KeywordsVectorHybridRetrieveEngineService) DeleteByKnowledgeIDList(ctx
 	return v.indexRepository.DeleteByKnowledgeIDList(ctx, knowledgeIDList, dimension, knowledgeType)
 }
 
+// DeleteByKnowledgeBaseIDList deletes vectors by their knowledge base (index label) IDs
+func (v *KeywordsVectorHybridRetrieveEngineService) DeleteByKnowledgeBaseIDList(ctx context.Context,
+	knowledgeBaseIDList []string, dimension int, knowledgeType string,
+) error {
+	return v.indexRepository.DeleteByKnowledgeBaseIDList(ctx, knowledgeBaseIDList, dimension, knowledgeType)
+}
+
 // Support returns the retriever types supported by this engine
 func (v *KeywordsVectorHybridRetrieveEngineService) Support() []types.RetrieverType {
 	return v.indexRepository.Support()
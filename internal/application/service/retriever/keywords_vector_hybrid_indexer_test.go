@@ -2,6 +2,8 @@ package retriever
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -116,3 +118,46 @@ func assertImagePayloadRemoved(t *testing.T, content string, payload string) {
 		t.Fatalf("embedding input should preserve surrounding text, got %q", content)
 	}
 }
+
+type recordingSaveRepository struct {
+	saveOnlyRepository
+	batches []map[string]any
+	saved   int
+}
+
+func (r *recordingSaveRepository) BatchSave(
+	ctx context.Context,
+	indexInfoList []*types.IndexInfo,
+	params map[string]any,
+) error {
+	r.batches = append(r.batches, params)
+	r.saved += len(indexInfoList)
+	return nil
+}
+
+func TestImportIndicesReusesStoredEmbeddings(t *testing.T) {
+	page := &types.IndexExportPage{Embeddings: map[string][]float32{}}
+	for i := 0; i < importBatchSize+1; i++ {
+		sourceID := fmt.Sprintf("source-%d", i)
+		page.Entries = append(page.Entries, &types.IndexInfo{SourceID: sourceID, Content: "text"})
+		page.Embeddings[sourceID] = []float32{float32(i)}
+	}
+	repo := &recordingSaveRepository{}
+	service := &KeywordsVectorHybridRetrieveEngineService{indexRepository: repo}
+
+	err := service.ImportIndices(context.Background(), page, []types.RetrieverType{types.VectorRetrieverType})
+	if err != nil {
+		t.Fatalf("ImportIndices returned error: %v", err)
+	}
+	if len(repo.batches) != 2 || repo.saved != importBatchSize+1 {
+		t.Fatalf("expected 2 batches with %d entries, got %d batches with %d", importBatchSize+1, len(repo.batches), repo.saved)
+	}
+	last := repo.batches[1]["embedding"].(map[string][]float32)
+	if got := last[fmt.Sprintf("source-%d", importBatchSize)]; len(got) != 1 || got[0] != float32(importBatchSize) {
+		t.Fatalf("stored embedding not passed through, got %v", got)
+	}
+
+	if _, err := service.CountIndices(context.Background(), "kb", 1, types.KnowledgeBaseTypeDocument); !errors.Is(err, ErrIndexExportUnsupported) {
+		t.Fatalf("expected ErrIndexExportUnsupported for a repository without export, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package retriever
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// BatchUpdateKnowledgeMetadata forwards to the repository when it stores
+// document metadata. Engines that do not store it reject metadata filters at
+// query time, so skipping them here never serves stale metadata.
+func (v *KeywordsVectorHybridRetrieveEngineService) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadata map[string]map[string]any,
+) error {
+	updater, ok := v.indexRepository.(interfaces.RetrieveEngineMetadataUpdater)
+	if !ok {
+		logger.Debugf(ctx, "[%s] Engine does not store document metadata, skipping update", v.engineType)
+		return nil
+	}
+	return updater.BatchUpdateKnowledgeMetadata(ctx, knowledgeMetadata)
+}
+
+// BatchUpdateKnowledgeMetadata updates document metadata in all registered
+// engines.
+func (c *CompositeRetrieveEngine) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadata map[string]map[string]any,
+) error {
+	if len(knowledgeMetadata) == 0 {
+		return nil
+	}
+	if err := c.checkWriteFence(ctx); err != nil {
+		return err
+	}
+	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
+		updater, ok := engineInfo.retrieveEngine.(interfaces.RetrieveEngineMetadataUpdater)
+		if !ok {
+			return nil
+		}
+		return updater.BatchUpdateKnowledgeMetadata(ctx, knowledgeMetadata)
+	})
+}
@@ -15,7 +15,8 @@ import (
 // Only vector scores are normalized. Keyword (BM25) scores have an unbounded
 // positive range; rescaling them would collapse the long tail. Downstream
 // RRF fusion is rank-based and immune to scale, so keyword scores pass
-// through unchanged.
+// through unchanged. The score-based fusion strategies rescale each list on
+// its own with NormalizeMinMax / NormalizeDistribution instead.
 type ScoreNormalizer interface {
 	Normalize(
 		ctx context.Context,
@@ -173,3 +174,62 @@ func clamp01(s float64) float64 {
 	}
 	return s
 }
+
+// NormalizeMinMax rescales one engine's result list to [0, 1] in place of the
+// unbounded raw scale: the best hit maps to 1 and the worst to 0. Score-based
+// fusion calls it once per (engine, retriever type) list, which is what makes
+// BM25 scores addable to cosine similarities. A list whose scores are all
+// equal maps to 1, since every hit is as good as the best one.
+func NormalizeMinMax(scores []float64) []float64 {
+	out := make([]float64, len(scores))
+	if len(scores) == 0 {
+		return out
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range scores {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			continue
+		}
+		lo = math.Min(lo, s)
+		hi = math.Max(hi, s)
+	}
+	for i, s := range scores {
+		if hi <= lo {
+			out[i] = 1
+			continue
+		}
+		out[i] = clamp01((s - lo) / (hi - lo))
+	}
+	return out
+}
+
+// NormalizeDistribution is the distribution-based counterpart of
+// NormalizeMinMax: the list is mapped from [mean-3σ, mean+3σ] onto [0, 1] and
+// clamped. A single outlying BM25 score therefore does not push every other
+// hit of the list towards 0 the way min-max does.
+func NormalizeDistribution(scores []float64) []float64 {
+	out := make([]float64, len(scores))
+	var sum, sumSq float64
+	n := 0
+	for _, s := range scores {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			continue
+		}
+		sum += s
+		sumSq += s * s
+		n++
+	}
+	if n == 0 {
+		return out
+	}
+	mean := sum / float64(n)
+	std := math.Sqrt(math.Max(sumSq/float64(n)-mean*mean, 0))
+	for i, s := range scores {
+		if std == 0 {
+			out[i] = 1
+			continue
+		}
+		out[i] = clamp01((s - (mean - 3*std)) / (6 * std))
+	}
+	return out
+}
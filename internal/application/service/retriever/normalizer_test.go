@@ -328,3 +328,42 @@ func TestClamp01(t *testing.T) {
 func TestEngineAwareNormalizer_InterfaceSatisfied(t *testing.T) {
 	var _ ScoreNormalizer = EngineAwareNormalizer{}
 }
+
+func TestNormalizeMinMax(t *testing.T) {
+	t.Parallel()
+	got := NormalizeMinMax([]float64{12, 4, 8, math.NaN()})
+	want := []float64{1, 0, 0.5, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("NormalizeMinMax[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	for _, s := range NormalizeMinMax([]float64{3, 3}) {
+		if s != 1 {
+			t.Fatalf("constant list should map to 1, got %v", s)
+		}
+	}
+	if len(NormalizeMinMax(nil)) != 0 {
+		t.Fatal("empty list should stay empty")
+	}
+}
+
+func TestNormalizeDistribution(t *testing.T) {
+	t.Parallel()
+	// mean 10, σ 1 → [7, 13] maps onto [0, 1]
+	got := NormalizeDistribution([]float64{9, 11, 9, 11})
+	if math.Abs(got[0]-1.0/3) > 1e-9 || math.Abs(got[1]-2.0/3) > 1e-9 {
+		t.Fatalf("NormalizeDistribution = %v", got)
+	}
+	// An outlier does not push the rest of the list towards 0 as min-max does.
+	scores := []float64{1, 1.2, 1.4, 1.6, 1.8, 2, 2.2, 2.4, 2.6, 40}
+	dist, minMax := NormalizeDistribution(scores), NormalizeMinMax(scores)
+	if minMax[8] > 0.05 || dist[0] < 0.4 {
+		t.Fatalf("bulk of the list: min-max %v, distribution %v", minMax[:9], dist[:9])
+	}
+	for _, s := range dist {
+		if s < 0 || s > 1 {
+			t.Fatalf("score %v out of [0, 1]", s)
+		}
+	}
+}
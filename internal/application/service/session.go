@@ -129,6 +129,8 @@ type sessionService struct {
 	sandboxPinner         *SessionSandboxPinner
 	sandboxPolicy         WorkspaceSandboxPolicy
 	memoryService         interfaces.MemoryService // Service for cross-session long-term memory
+	answerCacheService    interfaces.AnswerCacheService
+	knowledgeACLService   interfaces.KnowledgeACLService // Resolves the documents hidden from the caller by access lists
 }
 
 // NewSessionService creates a new session service instance with all required dependencies
@@ -151,6 +153,8 @@ func NewSessionService(cfg *config.Config,
 	sandboxPinner *SessionSandboxPinner,
 	sandboxPolicy WorkspaceSandboxPolicy,
 	memoryService interfaces.MemoryService,
+	answerCacheService interfaces.AnswerCacheService,
+	knowledgeACLService interfaces.KnowledgeACLService,
 ) interfaces.SessionService {
 	return &sessionService{
 		cfg:                   cfg,
@@ -172,6 +176,8 @@ func NewSessionService(cfg *config.Config,
 		sandboxPinner:         sandboxPinner,
 		sandboxPolicy:         sandboxPolicy,
 		memoryService:         memoryService,
+		answerCacheService:    answerCacheService,
+		knowledgeACLService:   knowledgeACLService,
 	}
 }
 
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// answerCacheScope returns the answer cache scope of a knowledge QA request,
+// or nil when the agent has no answer cache or the answer depends on more than
+// the question and the knowledge bases: images, attachments, quoted context,
+// web results, graph communities or earlier turns of the conversation.
+func (s *sessionService) answerCacheScope(
+	ctx context.Context, req *types.QARequest, chatManage *types.ChatManage,
+) *types.AnswerCacheScope {
+	if s.answerCacheService == nil || req.CustomAgent == nil || !req.CustomAgent.Config.AnswerCacheEnabled {
+		return nil
+	}
+	if req.WebSearchEnabled || len(req.ImageURLs) > 0 || req.ImageDescription != "" ||
+		len(req.Attachments) > 0 || req.QuotedContext != "" ||
+		chatManage.RetrievalMode == types.RetrievalModeGlobal {
+		return nil
+	}
+	knowledgeBaseIDs := chatManage.SearchTargets.GetAllKnowledgeBaseIDs()
+	if len(knowledgeBaseIDs) == 0 {
+		return nil
+	}
+	if chatManage.MaxRounds > 0 && s.sessionHasEarlierTurns(ctx, req) {
+		return nil
+	}
+	sort.Strings(knowledgeBaseIDs)
+
+	// The question is embedded with the first knowledge base's model; the
+	// model ID is part of the scope so a re-embedded knowledge base starts
+	// from an empty cache.
+	kb, err := s.knowledgeBaseService.GetKnowledgeBaseByID(ctx, knowledgeBaseIDs[0])
+	if err != nil {
+		logger.Warnf(ctx, "[AnswerCache] skipped, failed to load knowledge base %s: %v", knowledgeBaseIDs[0], err)
+		return nil
+	}
+	embedding, err := s.knowledgeBaseService.GetQueryEmbedding(ctx, kb.ID, req.Query)
+	if err != nil {
+		logger.Warnf(ctx, "[AnswerCache] skipped, failed to embed query: %v", err)
+		return nil
+	}
+	scopeHash, err := answerCacheScopeHash(req.CustomAgent, chatManage, kb.EmbeddingModelID)
+	if err != nil {
+		logger.Warnf(ctx, "[AnswerCache] skipped, failed to hash scope: %v", err)
+		return nil
+	}
+	return &types.AnswerCacheScope{
+		TenantID:         req.CustomAgent.TenantID,
+		AgentID:          req.CustomAgent.ID,
+		ScopeHash:        scopeHash,
+		KnowledgeBaseIDs: knowledgeBaseIDs,
+		Query:            req.Query,
+		QueryEmbedding:   embedding,
+		Threshold:        req.CustomAgent.Config.AnswerCacheThresholdOrDefault(),
+		TTL:              req.CustomAgent.Config.AnswerCacheTTL(),
+		StartedAt:        time.Now(),
+	}
+}
+
+// sessionHasEarlierTurns reports whether the session holds messages besides
+// the ones created for this request, in which case the question may lean on
+// the conversation and its answer is not reusable. Errors count as history.
+func (s *sessionService) sessionHasEarlierTurns(ctx context.Context, req *types.QARequest) bool {
+	messages, err := s.messageRepo.GetRecentMessagesBySession(ctx, req.Session.ID, 3)
+	if err != nil {
+		logger.Warnf(ctx, "[AnswerCache] skipped, failed to load session messages: %v", err)
+		return true
+	}
+	for _, message := range messages {
+		if message.ID != req.UserMessageID && message.ID != req.AssistantMessageID {
+			return true
+		}
+	}
+	return false
+}
+
+// answerCacheScopeHash hashes everything besides the question that shapes a
+// knowledge QA answer. The agent's UpdatedAt stands in for its configuration,
+// so editing the prompt or retrieval settings starts a fresh cache.
+func answerCacheScopeHash(
+	agent *types.CustomAgent, chatManage *types.ChatManage, embeddingModelID string,
+) (string, error) {
+	targets := make([]types.SearchTarget, 0, len(chatManage.SearchTargets))
+	for _, target := range chatManage.SearchTargets {
+		if target == nil {
+			continue
+		}
+		t := *target
+		t.KnowledgeIDs = slices.Sorted(slices.Values(t.KnowledgeIDs))
+		t.TagIDs = slices.Sorted(slices.Values(t.TagIDs))
+		t.ScopeTagIDs = slices.Sorted(slices.Values(t.ScopeTagIDs))
+		// Access lists make the same question see different documents, so
+		// the hidden set keeps readers with different views apart.
+		t.ExcludeKnowledgeIDs = slices.Sorted(slices.Values(t.ExcludeKnowledgeIDs))
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].KnowledgeBaseID != targets[j].KnowledgeBaseID {
+			return targets[i].KnowledgeBaseID < targets[j].KnowledgeBaseID
+		}
+		if targets[i].Type != targets[j].Type {
+			return targets[i].Type < targets[j].Type
+		}
+		return strings.Join(targets[i].KnowledgeIDs, ",") < strings.Join(targets[j].KnowledgeIDs, ",")
+	})
+	data, err := json.Marshal(struct {
+		AgentRevision    time.Time             `json:"agent_revision"`
+		ChatModelID      string                `json:"chat_model_id"`
+		EmbeddingModelID string                `json:"embedding_model_id"`
+		Targets          []types.SearchTarget  `json:"targets"`
+		MetadataFilter   *types.MetadataFilter `json:"metadata_filter"`
+		RetrievalMode    string                `json:"retrieval_mode"`
+		Language         string                `json:"language"`
+	}{
+		AgentRevision:    agent.UpdatedAt.UTC(),
+		ChatModelID:      chatManage.ChatModelID,
+		EmbeddingModelID: embeddingModelID,
+		Targets:          targets,
+		MetadataFilter:   chatManage.MetadataFilter,
+		RetrievalMode:    chatManage.RetrievalMode,
+		Language:         chatManage.Language,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replayCachedAnswer streams a cached answer through the events a generated
+// answer uses: references first, then the whole answer as one final chunk.
+func (s *sessionService) replayCachedAnswer(
+	ctx context.Context, chatManage *types.ChatManage, entry *types.AnswerCacheEntry,
+) {
+	chatManage.MergeResult = entry.References
+	emitKnowledgeReferencesEvent(ctx, chatManage)
+	if err := chatManage.EventBus.Emit(ctx, types.Event{
+		ID:        generateEventID("answer"),
+		Type:      types.EventType(event.EventAgentFinalAnswer),
+		SessionID: chatManage.SessionID,
+		Data: event.AgentFinalAnswerData{
+			Content: entry.Answer,
+			Done:    true,
+		},
+	}); err != nil {
+		logger.Errorf(ctx, "Failed to emit cached answer event: %v", err)
+	}
+}
+
+// recordAnswerForCache collects the streamed answer and stores it in the
+// answer cache once it completes. Fallbacks, failed streams, answers without
+// references and answers that drew on the user's long-term memory are not
+// cached: a cached answer is served to every user of the agent.
+func (s *sessionService) recordAnswerForCache(
+	eventBus *event.EventBus, chatManage *types.ChatManage, scope *types.AnswerCacheScope,
+) {
+	var answer strings.Builder
+	failed, completed := false, false
+	eventBus.On(event.EventError, func(ctx context.Context, evt event.Event) error {
+		failed = true
+		return nil
+	})
+	eventBus.On(event.EventAgentFinalAnswer, func(ctx context.Context, evt event.Event) error {
+		data, ok := evt.Data.(event.AgentFinalAnswerData)
+		if !ok || completed {
+			return nil
+		}
+		answer.WriteString(data.Content)
+		if data.IsFallback {
+			failed = true
+		}
+		if !data.Done {
+			return nil
+		}
+		completed = true
+		content := answer.String()
+		if failed || strings.TrimSpace(content) == "" ||
+			len(chatManage.MergeResult) == 0 || len(chatManage.UsedMemories) > 0 {
+			return nil
+		}
+		references := chatManage.MergeResult
+		go func() {
+			storeCtx := context.WithoutCancel(ctx)
+			if err := s.answerCacheService.Store(storeCtx, scope, content, references); err != nil {
+				logger.Warnf(storeCtx, "[AnswerCache] failed to store answer for agent %s: %v", scope.AgentID, err)
+			}
+		}()
+		return nil
+	})
+}
@@ -111,6 +111,7 @@ func (s *sessionService) KnowledgeQA(
 			KnowledgeBaseIDs:        knowledgeBaseIDs,
 			KnowledgeIDs:            knowledgeIDs,
 			SearchTargets:           searchTargets,
+			MetadataFilter:          req.MetadataFilter,
 			VectorThreshold:         s.cfg.Conversation.VectorThreshold,
 			KeywordThreshold:        s.cfg.Conversation.KeywordThreshold,
 			EmbeddingTopK:           s.cfg.Conversation.EmbeddingTopK,
@@ -136,6 +137,7 @@ func (s *sessionService) KnowledgeQA(
 			ChatModelSupportsVision: chatModelSupportsVision,
 			Attachments:             req.Attachments,
 			Language:                types.LanguageNameFromContext(ctx),
+			RetrievalMode:           types.NormalizeRetrievalMode(req.RetrievalMode),
 		},
 		PipelineState: types.PipelineState{
 			RewriteQuery:     req.Query,
@@ -189,12 +191,25 @@ func (s *sessionService) KnowledgeQA(
 			Add(types.MEMORY_RECALL).
 			Add(types.CHAT_COMPLETION_STREAM).
 			Build()
+	} else if hasKB && chatManage.RetrievalMode == types.RetrievalModeGlobal {
+		// Global — answer from the knowledge bases' graph community
+		// summaries instead of individual chunks.
+		pipeline = types.NewPipelineBuilder().
+			AddIf(hasHistory, types.LOAD_HISTORY).
+			Add(types.MEMORY_RECALL).
+			Add(types.QUERY_UNDERSTAND).
+			Add(types.COMMUNITY_SEARCH).
+			Add(types.INTO_CHAT_MESSAGE).
+			Add(types.CHAT_COMPLETION_STREAM).
+			Build()
 	} else {
 		// RAG — dynamically assemble based on feature flags.
 		pipeline = types.NewPipelineBuilder().
 			AddIf(hasHistory, types.LOAD_HISTORY).
 			Add(types.MEMORY_RECALL).
 			Add(types.QUERY_UNDERSTAND).
+			AddIf(chatManage.EnableHyDE, types.QUERY_HYDE).
+			AddIf(chatManage.EnableQueryDecomposition, types.QUERY_DECOMPOSE).
 			Add(types.CHUNK_SEARCH_PARALLEL).
 			Add(types.CHUNK_RERANK).
 			AddIf(req.WebSearchEnabled, types.WEB_FETCH).
@@ -206,8 +221,8 @@ func (s *sessionService) KnowledgeQA(
 			Build()
 	}
 
-	logger.Infof(ctx, "Assembled pipeline (%d stages), hasKB=%v, webSearch=%v, history=%v",
-		len(pipeline), hasKB, req.WebSearchEnabled, hasHistory)
+	logger.Infof(ctx, "Assembled pipeline (%d stages), hasKB=%v, webSearch=%v, history=%v, mode=%s",
+		len(pipeline), hasKB, req.WebSearchEnabled, hasHistory, chatManage.RetrievalMode)
 
 	// Start knowledge QA event processing (set session tenant so pipeline session/message lookups use session owner)
 	ctx = context.WithValue(ctx, types.SessionTenantIDContextKey, req.Session.TenantID)
@@ -220,6 +235,25 @@ func (s *sessionService) KnowledgeQA(
 		"knowledge_base_ids": knowledgeBaseIDs,
 		"search_targets":     len(searchTargets),
 	}, nil, nil)
+
+	// Repeated questions of an agent with the answer cache enabled replay a
+	// cached answer instead of running the pipeline; misses record the
+	// generated answer for the next asker.
+	if hasKB {
+		if cacheScope := s.answerCacheScope(ctx, req, chatManage); cacheScope != nil {
+			entry, err := s.answerCacheService.Lookup(ctx, cacheScope)
+			switch {
+			case err != nil:
+				logger.Warnf(ctx, "[AnswerCache] lookup failed, running the pipeline: %v", err)
+			case entry != nil:
+				s.replayCachedAnswer(ctx, chatManage, entry)
+				return nil
+			default:
+				s.recordAnswerForCache(eventBus, chatManage, cacheScope)
+			}
+		}
+	}
+
 	err = s.KnowledgeQAByEvent(ctx, chatManage, pipeline)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, map[string]interface{}{
@@ -612,7 +646,44 @@ func (s *sessionService) buildSearchTargets(
 	logger.Infof(ctx, "Built %d search targets: %d full KB, %d partial/tag KB, kbTenantMap=%v",
 		len(targets), len(knowledgeBaseIDs), len(targets)-len(knowledgeBaseIDs), kbTenantMap)
 
-	return targets, nil
+	return s.applyKnowledgeACL(ctx, targets)
+}
+
+// applyKnowledgeACL records on each target the documents that access lists
+// hide from the caller, so every tool reading through the targets skips
+// them. Targets limited to specific documents drop the hidden ones and are
+// removed when none is left. A lookup failure fails the request rather than
+// widening the scope.
+func (s *sessionService) applyKnowledgeACL(
+	ctx context.Context, targets types.SearchTargets,
+) (types.SearchTargets, error) {
+	if s.knowledgeACLService == nil || len(targets) == 0 {
+		return targets, nil
+	}
+	denied, err := s.knowledgeACLService.DeniedKnowledgeIDs(ctx, targets.GetAllKnowledgeBaseIDs())
+	if err != nil {
+		return nil, fmt.Errorf("resolve document access lists: %w", err)
+	}
+	if len(denied) == 0 {
+		return targets, nil
+	}
+	filtered := make(types.SearchTargets, 0, len(targets))
+	for _, target := range targets {
+		hidden := denied[target.KnowledgeBaseID]
+		if len(hidden) == 0 {
+			filtered = append(filtered, target)
+			continue
+		}
+		target.ExcludeKnowledgeIDs = append([]string(nil), hidden...)
+		if target.Type == types.SearchTargetTypeKnowledge {
+			target.KnowledgeIDs = subtractStrings(target.KnowledgeIDs, hidden)
+			if len(target.KnowledgeIDs) == 0 {
+				continue
+			}
+		}
+		filtered = append(filtered, target)
+	}
+	return filtered, nil
 }
 
 func mergeTagScopesByKB(scopes []types.TagScope) map[string][]string {
@@ -666,6 +737,20 @@ func intersectStrings(left []string, right []string) []string {
 	return out
 }
 
+func subtractStrings(left []string, right []string) []string {
+	rightSet := make(map[string]bool, len(right))
+	for _, value := range right {
+		rightSet[value] = true
+	}
+	out := make([]string, 0, len(left))
+	for _, value := range left {
+		if !rightSet[value] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
 // KnowledgeQAByEvent processes knowledge QA through a series of events in the pipeline
 func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 	chatManage *types.ChatManage, eventList []types.EventType,
@@ -686,6 +771,7 @@ func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 	var retrievalStart time.Time
 	var understandProgress *chatpipeline.StageProgress
 	var understandStart time.Time
+	var transformProgress *chatpipeline.StageProgress
 	for _, eventType := range eventList {
 		stageStart := time.Now()
 		// Wrap each pipeline stage in a Langfuse span so the trace timeline
@@ -715,6 +801,9 @@ func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 			understandStart = stageStart
 			understandProgress = chatpipeline.BeginQueryUnderstandProgress(stageCtx, chatManage)
 		}
+		if eventType == types.QUERY_HYDE || eventType == types.QUERY_DECOMPOSE {
+			transformProgress = chatpipeline.BeginQueryTransformProgress(stageCtx, eventType, chatManage)
+		}
 		if chatpipeline.IsConsolidatedRetrievalStage(eventType, chatManage) && retrievalProgress == nil {
 			retrievalStart = stageStart
 			retrievalProgress = chatpipeline.BeginRetrievalProgress(stageCtx, chatManage)
@@ -731,6 +820,10 @@ func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 			chatpipeline.EndQueryUnderstandProgress(stageCtx, chatManage, understandProgress, understandStart, err)
 			understandProgress = nil
 		}
+		if transformProgress != nil {
+			chatpipeline.EndQueryTransformProgress(stageCtx, chatManage, transformProgress, stageStart, err)
+			transformProgress = nil
+		}
 		// Close the consolidated retrieval progress window as soon as retrieval
 		// is done: either the planned last retrieval stage completed, or a
 		// retrieval stage short-circuited the pipeline (ErrSearchNothing or a
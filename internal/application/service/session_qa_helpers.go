@@ -217,6 +217,8 @@ func (s *sessionService) applyAgentOverridesToChatManage(
 	// Override rewrite settings
 	cm.EnableRewrite = customAgent.Config.EnableRewrite
 	cm.EnableQueryExpansion = customAgent.Config.EnableQueryExpansion
+	cm.EnableHyDE = customAgent.Config.EnableHyDE
+	cm.EnableQueryDecomposition = customAgent.Config.EnableQueryDecomposition
 	if customAgent.Config.RewritePromptSystem != "" {
 		cm.RewritePromptSystem = customAgent.Config.RewritePromptSystem
 	}
@@ -281,6 +281,35 @@ var registry = map[string]settingSpec{
 			"每次调用实时读取，修改后立即生效、无需重启。0 或负数表示关闭默认限制" +
 			"（各模型仍会尊重自身在模型管理里配置的上限）。仅影响后台任务，不影响交互式对话。",
 	},
+	// embedding.cache_* configure the content-hash embedding cache installed
+	// at boot (container.registerEmbeddingCache): Redis when available,
+	// otherwise an in-process LRU. Read once at startup.
+	"embedding.cache_enabled": {
+		Type:            "bool",
+		EnvName:         "WEKNORA_EMBEDDING_CACHE_ENABLED",
+		Default:         true,
+		Category:        "worker",
+		RequiresRestart: true,
+		Description: "是否缓存向量化结果。按「模型配置 + 文本内容哈希」缓存，重解析、知识库复制、FAQ 重新导入、" +
+			"数据源同步未变更页面以及重复查询都直接复用已有向量，不再调用 Embedding 接口。修改后需重启。",
+	},
+	"embedding.cache_ttl_hours": {
+		Type:            "int",
+		EnvName:         "WEKNORA_EMBEDDING_CACHE_TTL_HOURS",
+		Default:         int64(168),
+		Category:        "worker",
+		RequiresRestart: true,
+		Description:     "Redis 中向量缓存的过期时间（小时），0 表示不过期（由 Redis 淘汰策略回收）。修改后需重启。",
+	},
+	"embedding.cache_max_entries": {
+		Type:            "int",
+		EnvName:         "WEKNORA_EMBEDDING_CACHE_MAX_ENTRIES",
+		Default:         int64(20000),
+		Category:        "worker",
+		RequiresRestart: true,
+		Description: "无 Redis（Lite 模式）时进程内向量缓存的最大条目数，按最近最少使用淘汰。" +
+			"1024 维向量每条约 4KB。修改后需重启。",
+	},
 }
 
 // systemSettingService wires the repository, audit log, and (P2)
@@ -411,6 +411,9 @@ func (s *knowledgeTagService) ProcessIndexDelete(ctx context.Context, t *asynq.T
 
 	// Set tenant context for downstream services
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	if err := requireNoVectorStoreMigration(ctx, s.kbService, payload.KnowledgeBaseID); err != nil {
+		return err
+	}
 
 	logger.Infof(ctx, "Processing index delete task for %d chunks in KB %s", len(payload.ChunkIDs), payload.KnowledgeBaseID)
 
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+)
+
+const (
+	userGroupNameMaxLength = 255
+	// userGroupMaxMembers bounds one membership replacement request
+	userGroupMaxMembers = 1000
+)
+
+type userGroupService struct {
+	repo       interfaces.UserGroupRepository
+	aclRepo    interfaces.KnowledgeACLRepository
+	memberRepo interfaces.TenantMemberRepository
+	userRepo   interfaces.UserRepository
+}
+
+// NewUserGroupService creates the user group service
+func NewUserGroupService(
+	repo interfaces.UserGroupRepository,
+	aclRepo interfaces.KnowledgeACLRepository,
+	memberRepo interfaces.TenantMemberRepository,
+	userRepo interfaces.UserRepository,
+) interfaces.UserGroupService {
+	return &userGroupService{repo: repo, aclRepo: aclRepo, memberRepo: memberRepo, userRepo: userRepo}
+}
+
+func (s *userGroupService) ListGroups(ctx context.Context) ([]*types.UserGroup, error) {
+	return s.repo.List(ctx, types.MustTenantIDFromContext(ctx))
+}
+
+func (s *userGroupService) GetGroup(ctx context.Context, id string) (*types.UserGroup, error) {
+	group, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.repo.ListMemberIDs(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.GetUsersByIDs(ctx, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	group.Members = make([]*types.UserGroupMemberInfo, 0, len(memberIDs))
+	for _, userID := range memberIDs {
+		info := &types.UserGroupMemberInfo{UserID: userID}
+		if user := users[userID]; user != nil {
+			info.Username = user.Username
+			info.Email = user.Email
+		}
+		group.Members = append(group.Members, info)
+	}
+	group.MemberCount = len(group.Members)
+	return group, nil
+}
+
+func (s *userGroupService) CreateGroup(ctx context.Context, group *types.UserGroup) (*types.UserGroup, error) {
+	name, err := validateUserGroupName(group.Name)
+	if err != nil {
+		return nil, err
+	}
+	group.ID = uuid.New().String()
+	group.TenantID = types.MustTenantIDFromContext(ctx)
+	group.Name = name
+	group.Description = strings.TrimSpace(group.Description)
+	group.CreatedBy, _ = types.UserIDFromContext(ctx)
+	if err := s.repo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *userGroupService) UpdateGroup(
+	ctx context.Context, id string, name string, description string,
+) (*types.UserGroup, error) {
+	group, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if group.Name, err = validateUserGroupName(name); err != nil {
+		return nil, err
+	}
+	group.Description = strings.TrimSpace(description)
+	if err := s.repo.Update(ctx, group); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, group.ID)
+}
+
+func (s *userGroupService) DeleteGroup(ctx context.Context, id string) error {
+	tenantID := types.MustTenantIDFromContext(ctx)
+	granted, err := s.aclRepo.CountByPrincipal(ctx, tenantID, types.KnowledgeACLPrincipalGroup, id)
+	if err != nil {
+		return err
+	}
+	if granted > 0 {
+		return werrors.NewConflictError(
+			fmt.Sprintf("user group is used by %d access list entries; remove it from them first", granted))
+	}
+	if err := s.repo.Delete(ctx, tenantID, id); err != nil {
+		if errors.Is(err, repository.ErrUserGroupNotFound) {
+			return werrors.NewNotFoundError("user group not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *userGroupService) SetGroupMembers(ctx context.Context, id string, userIDs []string) (*types.UserGroup, error) {
+	group, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userIDs = dedupStrings(userIDs)
+	if len(userIDs) > userGroupMaxMembers {
+		return nil, werrors.NewValidationError(
+			fmt.Sprintf("a user group can have at most %d members", userGroupMaxMembers))
+	}
+	if len(userIDs) > 0 {
+		members, err := s.memberRepo.ListByTenant(ctx, group.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		active := make(map[string]bool, len(members))
+		for _, member := range members {
+			if member.Status == types.TenantMemberStatusActive {
+				active[member.UserID] = true
+			}
+		}
+		for _, userID := range userIDs {
+			if !active[userID] {
+				return nil, werrors.NewValidationError("user is not a member of this workspace: " + userID)
+			}
+		}
+	}
+	if err := s.repo.ReplaceMembers(ctx, group.TenantID, group.ID, userIDs); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, group.ID)
+}
+
+func (s *userGroupService) get(ctx context.Context, id string) (*types.UserGroup, error) {
+	group, err := s.repo.Get(ctx, types.MustTenantIDFromContext(ctx), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserGroupNotFound) {
+			return nil, werrors.NewNotFoundError("user group not found")
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+func validateUserGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", werrors.NewValidationError("name is required")
+	}
+	if utf8.RuneCountInString(name) > userGroupNameMaxLength {
+		return "", werrors.NewValidationError(
+			fmt.Sprintf("name must be at most %d characters", userGroupNameMaxLength))
+	}
+	return name, nil
+}
@@ -163,6 +163,9 @@ func (m *mockEngineService) DeleteBySourceIDList(_ context.Context, _ []string,
 func (m *mockEngineService) DeleteByKnowledgeIDList(_ context.Context, _ []string, _ int, _ string) error {
 	return nil
 }
+func (m *mockEngineService) DeleteByKnowledgeBaseIDList(_ context.Context, _ []string, _ int, _ string) error {
+	return nil
+}
 func (m *mockEngineService) BatchUpdateChunkEnabledStatus(_ context.Context, _ map[string]bool) error {
 	return nil
 }
@@ -831,6 +834,9 @@ CREATE TABLE IF NOT EXISTS knowledge_bases (
     chunking_config TEXT NOT NULL DEFAULT '{}',
     image_processing_config TEXT NOT NULL DEFAULT '{}',
     embedding_model_id VARCHAR(64) NOT NULL,
+    embedding_index_id VARCHAR(36) NOT NULL DEFAULT '',
+    embedding_migration TEXT,
+    vector_store_migration TEXT,
     summary_model_id VARCHAR(64) NOT NULL,
     cos_config TEXT NOT NULL DEFAULT '{}',
     storage_provider_config TEXT DEFAULT NULL,
@@ -847,6 +853,7 @@ CREATE TABLE IF NOT EXISTS knowledge_bases (
 		storage_backend_id VARCHAR(36),
     wiki_config TEXT,
     indexing_strategy TEXT,
+    fusion_config TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     deleted_at DATETIME
@@ -950,6 +957,18 @@ func (r *realKBRepo) ListKnowledgeBasesByTenantID(_ context.Context, _ uint64) (
 func (r *realKBRepo) UpdateKnowledgeBase(_ context.Context, _ *types.KnowledgeBase) error {
 	return nil
 }
+func (r *realKBRepo) UpdateEmbeddingMigration(_ context.Context, _ string, _ *types.EmbeddingMigration) error {
+	return nil
+}
+func (r *realKBRepo) SwitchEmbeddingIndex(_ context.Context, _ string, _ string, _ string, _ *types.EmbeddingMigration) error {
+	return nil
+}
+func (r *realKBRepo) UpdateVectorStoreMigration(_ context.Context, _ string, _ *types.VectorStoreMigration) error {
+	return nil
+}
+func (r *realKBRepo) SwitchVectorStore(_ context.Context, _ string, _ string, _ *types.VectorStoreMigration) error {
+	return nil
+}
 func (r *realKBRepo) DeleteKnowledgeBase(_ context.Context, _ string) error {
 	return nil
 }
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+	// webhookResponseBodyLimit bounds the receiver response kept on a delivery
+	webhookResponseBodyLimit = 1024
+)
+
+type webhookService struct {
+	repo       interfaces.WebhookRepository
+	dispatcher *WebhookDispatcher
+	kbService  interfaces.KnowledgeBaseService
+	client     *http.Client
+}
+
+// NewWebhookService creates the tenant webhook service
+func NewWebhookService(
+	repo interfaces.WebhookRepository,
+	dispatcher *WebhookDispatcher,
+	kbService interfaces.KnowledgeBaseService,
+) interfaces.WebhookService {
+	return &webhookService{
+		repo:       repo,
+		dispatcher: dispatcher,
+		kbService:  kbService,
+		client: secutils.NewSSRFSafeHTTPClient(secutils.SSRFSafeHTTPClientConfig{
+			Timeout:      webhookTimeout,
+			MaxRedirects: 5,
+		}),
+	}
+}
+
+func (s *webhookService) CreateSubscription(
+	ctx context.Context, sub *types.WebhookSubscription,
+) (*types.WebhookSubscription, error) {
+	if err := s.validateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	sub.ID = uuid.New().String()
+	sub.TenantID = types.MustTenantIDFromContext(ctx)
+	sub.CreatedBy, _ = types.UserIDFromContext(ctx)
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return presentWebhookSubscription(sub), nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]*types.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx, types.MustTenantIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		presentWebhookSubscription(sub)
+	}
+	return subs, nil
+}
+
+func (s *webhookService) GetSubscription(ctx context.Context, id string) (*types.WebhookSubscription, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return presentWebhookSubscription(sub), nil
+}
+
+func (s *webhookService) UpdateSubscription(
+	ctx context.Context, id string, update *types.WebhookSubscription, secret *string,
+) (*types.WebhookSubscription, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Name = update.Name
+	sub.URL = update.URL
+	sub.EventTypes = update.EventTypes
+	sub.KnowledgeBaseIDs = update.KnowledgeBaseIDs
+	sub.Enabled = update.Enabled
+	if secret != nil {
+		sub.Secret = *secret
+	}
+	if err := s.validateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return presentWebhookSubscription(sub), nil
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id string) error {
+	err := s.repo.DeleteSubscription(ctx, types.MustTenantIDFromContext(ctx), id)
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return werrors.NewNotFoundError("webhook subscription not found")
+	}
+	return err
+}
+
+func (s *webhookService) ListDeliveries(
+	ctx context.Context, id string, status string, page *types.Pagination,
+) (*types.PageResult, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, total, err := s.repo.ListDeliveries(ctx, sub.TenantID, sub.ID, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, deliveries), nil
+}
+
+// ReplayDelivery enqueues the stored body of a delivery again, whatever the
+// outcome of the original. The replay is a new delivery with its own
+// attempts; the event ID in the body stays the same.
+func (s *webhookService) ReplayDelivery(
+	ctx context.Context, id string, deliveryID string,
+) (*types.WebhookDelivery, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.repo.GetDelivery(ctx, sub.TenantID, deliveryID)
+	if errors.Is(err, repository.ErrWebhookDeliveryNotFound) || (err == nil && original.SubscriptionID != sub.ID) {
+		return nil, werrors.NewNotFoundError("webhook delivery not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	replay := &types.WebhookDelivery{
+		TenantID:       sub.TenantID,
+		SubscriptionID: sub.ID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		ReplayOf:       original.ID,
+	}
+	if err := s.dispatcher.enqueue(ctx, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+func (s *webhookService) getSubscription(ctx context.Context, id string) (*types.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, types.MustTenantIDFromContext(ctx), id)
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return nil, werrors.NewNotFoundError("webhook subscription not found")
+	}
+	return sub, err
+}
+
+// validateSubscription checks a subscription, its URL against SSRF rules and
+// that its knowledge base filter only names knowledge bases of the tenant
+func (s *webhookService) validateSubscription(ctx context.Context, sub *types.WebhookSubscription) error {
+	sub.Name = strings.TrimSpace(sub.Name)
+	sub.URL = strings.TrimSpace(sub.URL)
+	sub.Secret = strings.TrimSpace(sub.Secret)
+	if err := sub.Validate(); err != nil {
+		return werrors.NewValidationError(err.Error())
+	}
+	if err := ValidateEmbedWebhookURL(sub.URL); err != nil {
+		return werrors.NewValidationError(err.Error())
+	}
+	tenantID := types.MustTenantIDFromContext(ctx)
+	for _, kbID := range sub.KnowledgeBaseIDs {
+		kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+		if err != nil || kb.TenantID != tenantID {
+			return werrors.NewValidationError(
+				fmt.Sprintf("knowledge_base_ids: %s is not a knowledge base of this workspace", kbID))
+		}
+	}
+	return nil
+}
+
+// presentWebhookSubscription flags a stored secret without returning it
+func presentWebhookSubscription(sub *types.WebhookSubscription) *types.WebhookSubscription {
+	sub.HasSecret = sub.Secret != ""
+	return sub
+}
+
+// ProcessDelivery POSTs one delivery. A failed attempt is recorded and
+// returned so asynq retries it with backoff; after the last attempt the
+// delivery is failed and can only be replayed. Deliveries of deleted or
+// disabled subscriptions fail without retry.
+func (s *webhookService) ProcessDelivery(ctx context.Context, task *asynq.Task) error {
+	var payload types.WebhookDeliveryPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "[Webhook] failed to unmarshal delivery payload: %v", err)
+		return nil
+	}
+	delivery, err := s.repo.GetDelivery(ctx, payload.TenantID, payload.DeliveryID)
+	if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+		logger.Warnf(ctx, "[Webhook] delivery %s not found, skipping", payload.DeliveryID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status == types.WebhookDeliverySuccess || delivery.Status == types.WebhookDeliveryFailed {
+		return nil
+	}
+
+	sub, err := s.repo.GetSubscription(ctx, delivery.TenantID, delivery.SubscriptionID)
+	switch {
+	case errors.Is(err, repository.ErrWebhookSubscriptionNotFound):
+		return s.finishDelivery(ctx, delivery, types.WebhookDeliveryFailed, "subscription was deleted")
+	case err != nil:
+		return err
+	case !sub.Enabled:
+		return s.finishDelivery(ctx, delivery, types.WebhookDeliveryFailed, "subscription is disabled")
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus, delivery.ResponseBody, err = s.post(ctx, sub, delivery)
+	if err == nil {
+		return s.finishDelivery(ctx, delivery, types.WebhookDeliverySuccess, "")
+	}
+
+	logger.Warnf(ctx, "[Webhook] delivery %s to subscription=%s failed (attempt %d): %v",
+		delivery.ID, sub.ID, delivery.Attempts, err)
+	if isFinalAsynqAttempt(ctx) {
+		return s.finishDelivery(ctx, delivery, types.WebhookDeliveryFailed, err.Error())
+	}
+	if saveErr := s.finishDelivery(ctx, delivery, types.WebhookDeliveryRetrying, err.Error()); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// finishDelivery stores the outcome of an attempt. The write outlives a
+// cancelled worker context so a timed-out attempt is still logged.
+func (s *webhookService) finishDelivery(
+	ctx context.Context, delivery *types.WebhookDelivery, status string, errMsg string,
+) error {
+	delivery.Status = status
+	delivery.Error = errMsg
+	return s.repo.UpdateDelivery(context.WithoutCancel(ctx), delivery)
+}
+
+// post sends the stored body of a delivery. With a secret the body is signed
+// like embed webhooks: X-WeKnora-Signature: sha256=<hex HMAC>. Any status
+// outside 2xx is an error.
+func (s *webhookService) post(
+	ctx context.Context, sub *types.WebhookSubscription, delivery *types.WebhookDelivery,
+) (int, string, error) {
+	if err := ValidateEmbedWebhookURL(sub.URL); err != nil {
+		return 0, "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WeKnora-Webhook/1.0")
+	req.Header.Set("X-WeKnora-Event", string(delivery.EventType))
+	req.Header.Set("X-WeKnora-Delivery", delivery.ID)
+	if sub.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(sub.Secret))
+		_, _ = mac.Write(delivery.Payload)
+		req.Header.Set("X-WeKnora-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// webhookMaxRetry spreads the attempts of a delivery over about an hour and a
+// half with asynq's default backoff, enough to ride out a receiver deployment
+const webhookMaxRetry = 8
+
+// WebhookDispatcher turns workspace events into webhook deliveries. For each
+// enabled subscription of the event's tenant that selects the event it
+// records a pending delivery holding the exact body and hands it to the
+// worker; webhookService POSTs it from there, so a slow receiver never holds
+// up the change that published the event.
+type WebhookDispatcher struct {
+	repo         interfaces.WebhookRepository
+	taskEnqueuer interfaces.TaskEnqueuer
+}
+
+// NewWebhookDispatcher creates the webhook dispatcher
+func NewWebhookDispatcher(
+	repo interfaces.WebhookRepository,
+	taskEnqueuer interfaces.TaskEnqueuer,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{repo: repo, taskEnqueuer: taskEnqueuer}
+}
+
+// Start subscribes to the workspace events webhooks can receive
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	for _, eventType := range []event.EventType{
+		event.EventKnowledgeCreated,
+		event.EventKnowledgeIngested,
+		event.EventKnowledgeFailed,
+		event.EventKnowledgeDeleted,
+		event.EventKnowledgeBaseChanged,
+		event.EventDataSourceSyncFinished,
+		event.EventWikiPageRevised,
+		event.EventEvaluationCompleted,
+	} {
+		event.On(eventType, d.handleEvent)
+	}
+	logger.Infof(ctx, "[Webhook] dispatcher subscribed to workspace events")
+}
+
+// handleEvent records and enqueues a delivery per matching subscription
+func (d *WebhookDispatcher) handleEvent(ctx context.Context, evt event.Event) error {
+	webhookEvent := webhookEventFromBus(evt)
+	if webhookEvent == nil || webhookEvent.TenantID == 0 {
+		return nil
+	}
+	subs, err := d.repo.ListEnabledSubscriptions(ctx, webhookEvent.TenantID)
+	if err != nil {
+		logger.Warnf(ctx, "[Webhook] failed to load subscriptions of tenant %d: %v", webhookEvent.TenantID, err)
+		return nil
+	}
+	var body []byte
+	for _, sub := range subs {
+		if !sub.Matches(webhookEvent.Type, webhookEvent.KnowledgeBaseID) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(webhookEvent); err != nil {
+				logger.Warnf(ctx, "[Webhook] failed to marshal %s event: %v", webhookEvent.Type, err)
+				return nil
+			}
+		}
+		delivery := &types.WebhookDelivery{
+			TenantID:       sub.TenantID,
+			SubscriptionID: sub.ID,
+			EventID:        webhookEvent.ID,
+			EventType:      webhookEvent.Type,
+			Payload:        types.JSON(body),
+		}
+		if err := d.enqueue(ctx, delivery); err != nil {
+			logger.Warnf(ctx, "[Webhook] failed to enqueue %s delivery for subscription=%s: %v",
+				webhookEvent.Type, sub.ID, err)
+		}
+	}
+	return nil
+}
+
+// enqueue records a pending delivery and hands it to the worker. A delivery
+// that could not be enqueued is deleted so the log only shows real attempts.
+func (d *WebhookDispatcher) enqueue(ctx context.Context, delivery *types.WebhookDelivery) error {
+	delivery.ID = uuid.New().String()
+	delivery.Status = types.WebhookDeliveryPending
+	if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("create delivery: %w", err)
+	}
+	payload, err := json.Marshal(&types.WebhookDeliveryPayload{
+		TenantID:   delivery.TenantID,
+		DeliveryID: delivery.ID,
+	})
+	if err != nil {
+		_ = d.repo.DeleteDelivery(ctx, delivery.ID)
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	_, err = d.taskEnqueuer.Enqueue(asynq.NewTask(types.TypeWebhookDeliver, payload),
+		asynq.Queue(types.QueueWebhook),
+		asynq.MaxRetry(webhookMaxRetry),
+		asynq.Timeout(time.Minute),
+	)
+	if err != nil {
+		_ = d.repo.DeleteDelivery(ctx, delivery.ID)
+		return fmt.Errorf("enqueue delivery: %w", err)
+	}
+	return nil
+}
+
+// webhookEventFromBus converts a workspace event into the body sent to
+// subscriptions, or nil for events webhooks do not carry
+func webhookEventFromBus(evt event.Event) *types.WebhookEvent {
+	webhookEvent := &types.WebhookEvent{
+		ID:         evt.ID,
+		OccurredAt: time.Now().UTC(),
+		Data:       evt.Data,
+	}
+	if webhookEvent.ID == "" {
+		webhookEvent.ID = uuid.New().String()
+	}
+	switch data := evt.Data.(type) {
+	case event.KnowledgeLifecycleData:
+		switch evt.Type {
+		case event.EventKnowledgeCreated:
+			webhookEvent.Type = types.WebhookEventKnowledgeCreated
+		case event.EventKnowledgeFailed:
+			webhookEvent.Type = types.WebhookEventKnowledgeFailed
+		case event.EventKnowledgeDeleted:
+			webhookEvent.Type = types.WebhookEventKnowledgeDeleted
+		default:
+			return nil
+		}
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	case event.KnowledgeIngestedData:
+		webhookEvent.Type = types.WebhookEventKnowledgeParsed
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	case event.KnowledgeBaseChangedData:
+		webhookEvent.Type = types.WebhookEventKnowledgeBaseChanged
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	case event.DataSourceSyncFinishedData:
+		webhookEvent.Type = types.WebhookEventDataSourceSyncFinished
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	case event.WikiPageRevisedData:
+		webhookEvent.Type = types.WebhookEventWikiPageRevised
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	case event.EvaluationCompletedData:
+		webhookEvent.Type = types.WebhookEventEvaluationCompleted
+		webhookEvent.TenantID, webhookEvent.KnowledgeBaseID = data.TenantID, data.KnowledgeBaseID
+	default:
+		return nil
+	}
+	return webhookEvent
+}
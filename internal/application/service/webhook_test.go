@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newWebhookServiceForTest(t *testing.T) (*webhookService, *agentTriggerTaskRecorder, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.NewString()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&types.WebhookSubscription{}, &types.WebhookDelivery{}))
+	recorder := &agentTriggerTaskRecorder{taskIDs: map[string]bool{}}
+	repo := repository.NewWebhookRepository(db)
+	svc := NewWebhookService(repo, NewWebhookDispatcher(repo, recorder), nil).(*webhookService)
+	return svc, recorder, db
+}
+
+func createWebhookSubscription(t *testing.T, db *gorm.DB, url string, eventTypes, kbIDs types.StringArray) *types.WebhookSubscription {
+	t.Helper()
+	sub := &types.WebhookSubscription{
+		ID: uuid.NewString(), TenantID: 1, Name: "indexer", URL: url, Secret: "s3cret",
+		EventTypes: eventTypes, KnowledgeBaseIDs: kbIDs, Enabled: true,
+	}
+	require.NoError(t, db.Create(sub).Error)
+	return sub
+}
+
+func TestWebhookDispatcherRecordsDeliveriesForMatchingSubscriptions(t *testing.T) {
+	svc, recorder, db := newWebhookServiceForTest(t)
+	ctx := context.Background()
+	all := createWebhookSubscription(t, db, "https://hooks.example.com/all", nil, nil)
+	createWebhookSubscription(t, db, "https://hooks.example.com/deleted",
+		types.StringArray{string(types.WebhookEventKnowledgeDeleted)}, nil)
+	createWebhookSubscription(t, db, "https://hooks.example.com/kb-2", nil, types.StringArray{"kb-2"})
+
+	evt := event.Event{ID: "evt-1", Type: event.EventKnowledgeIngested, Data: event.KnowledgeIngestedData{
+		TenantID: 1, KnowledgeBaseID: "kb-1", KnowledgeID: "k-1", Title: "Q3 report",
+	}}
+	require.NoError(t, svc.dispatcher.handleEvent(ctx, evt))
+
+	require.Len(t, recorder.tasks, 1)
+	require.Equal(t, types.TypeWebhookDeliver, recorder.tasks[0].Type())
+	var payload types.WebhookDeliveryPayload
+	require.NoError(t, json.Unmarshal(recorder.tasks[0].Payload(), &payload))
+
+	delivery, err := svc.repo.GetDelivery(ctx, 1, payload.DeliveryID)
+	require.NoError(t, err)
+	require.Equal(t, all.ID, delivery.SubscriptionID)
+	require.Equal(t, types.WebhookDeliveryPending, delivery.Status)
+	require.Equal(t, types.WebhookEventKnowledgeParsed, delivery.EventType)
+
+	var body types.WebhookEvent
+	require.NoError(t, json.Unmarshal(delivery.Payload, &body))
+	require.Equal(t, "evt-1", body.ID)
+	require.Equal(t, "kb-1", body.KnowledgeBaseID)
+}
+
+func TestWebhookProcessDeliverySignsBodyAndRecordsOutcome(t *testing.T) {
+	withSSRFWhitelist(t, "127.0.0.1")
+	svc, recorder, db := newWebhookServiceForTest(t)
+	ctx := context.Background()
+
+	var gotBody []byte
+	var gotHeaders http.Header
+	status := http.StatusInternalServerError
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	sub := createWebhookSubscription(t, db, receiver.URL, nil, nil)
+	require.NoError(t, svc.dispatcher.handleEvent(ctx, event.Event{
+		ID: "evt-1", Type: event.EventKnowledgeDeleted, Data: event.KnowledgeLifecycleData{
+			TenantID: 1, KnowledgeBaseID: "kb-1", KnowledgeID: "k-1",
+		},
+	}))
+	require.Len(t, recorder.tasks, 1)
+	task := recorder.tasks[0]
+
+	// A non-2xx response is returned for asynq to retry; outside a worker it
+	// is never the final attempt.
+	require.Error(t, svc.ProcessDelivery(ctx, task))
+	var payload types.WebhookDeliveryPayload
+	require.NoError(t, json.Unmarshal(task.Payload(), &payload))
+	delivery, err := svc.repo.GetDelivery(ctx, 1, payload.DeliveryID)
+	require.NoError(t, err)
+	require.Equal(t, types.WebhookDeliveryRetrying, delivery.Status)
+	require.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+
+	status = http.StatusNoContent
+	require.NoError(t, svc.ProcessDelivery(ctx, task))
+	delivery, err = svc.repo.GetDelivery(ctx, 1, payload.DeliveryID)
+	require.NoError(t, err)
+	require.Equal(t, types.WebhookDeliverySuccess, delivery.Status)
+	require.Equal(t, 2, delivery.Attempts)
+
+	mac := hmac.New(sha256.New, []byte(sub.Secret))
+	_, _ = mac.Write(gotBody)
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), gotHeaders.Get("X-WeKnora-Signature"))
+	require.Equal(t, string(types.WebhookEventKnowledgeDeleted), gotHeaders.Get("X-WeKnora-Event"))
+	require.Equal(t, delivery.ID, gotHeaders.Get("X-WeKnora-Delivery"))
+	require.JSONEq(t, string(delivery.Payload), string(gotBody))
+
+	// A settled delivery is not sent again by a duplicate task.
+	gotBody = nil
+	require.NoError(t, svc.ProcessDelivery(ctx, task))
+	require.Nil(t, gotBody)
+}
+
+func TestWebhookProcessDeliveryFailsWithoutRetryForDisabledSubscription(t *testing.T) {
+	svc, _, db := newWebhookServiceForTest(t)
+	ctx := context.Background()
+	sub := createWebhookSubscription(t, db, "https://hooks.example.com/off", nil, nil)
+	require.NoError(t, db.Model(sub).Update("enabled", false).Error)
+
+	delivery := &types.WebhookDelivery{
+		ID: uuid.NewString(), TenantID: 1, SubscriptionID: sub.ID, EventID: "evt-1",
+		EventType: types.WebhookEventKnowledgeCreated, Payload: types.JSON(`{}`),
+		Status: types.WebhookDeliveryPending,
+	}
+	require.NoError(t, svc.repo.CreateDelivery(ctx, delivery))
+	raw, err := json.Marshal(types.WebhookDeliveryPayload{TenantID: 1, DeliveryID: delivery.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.ProcessDelivery(ctx, asynq.NewTask(types.TypeWebhookDeliver, raw)))
+	got, err := svc.repo.GetDelivery(ctx, 1, delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.WebhookDeliveryFailed, got.Status)
+	require.Zero(t, got.Attempts)
+}
+
+func TestWebhookReplayDeliveryKeepsBodyAndEventID(t *testing.T) {
+	svc, recorder, db := newWebhookServiceForTest(t)
+	ctx := context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1))
+	sub := createWebhookSubscription(t, db, "https://hooks.example.com/all", nil, nil)
+
+	original := &types.WebhookDelivery{
+		ID: uuid.NewString(), TenantID: 1, SubscriptionID: sub.ID, EventID: "evt-1",
+		EventType: types.WebhookEventKnowledgeCreated, Payload: types.JSON(`{"id":"evt-1"}`),
+		Status: types.WebhookDeliveryFailed, Attempts: 9,
+	}
+	require.NoError(t, svc.repo.CreateDelivery(ctx, original))
+
+	replay, err := svc.ReplayDelivery(ctx, sub.ID, original.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, original.ID, replay.ID)
+	require.Equal(t, original.ID, replay.ReplayOf)
+	require.Equal(t, "evt-1", replay.EventID)
+	require.Equal(t, types.WebhookDeliveryPending, replay.Status)
+	require.Zero(t, replay.Attempts)
+	require.Len(t, recorder.tasks, 1)
+
+	_, err = svc.ReplayDelivery(ctx, uuid.NewString(), original.ID)
+	require.Error(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/application/repository"
+	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -104,6 +105,7 @@ func (s *wikiPageService) CreatePage(ctx context.Context, page *types.WikiPage)
 
 	// Update inbound links on target pages
 	s.updateInLinks(ctx, page.KnowledgeBaseID, page.Slug, page.OutLinks)
+	publishWikiPageRevised(ctx, page)
 
 	return page, nil
 }
@@ -180,6 +182,7 @@ func (s *wikiPageService) UpdatePage(ctx context.Context, page *types.WikiPage)
 		// Bound per-page history; best-effort — a failed prune only means
 		// slightly more storage until the next content change.
 		s.pruneRevisions(ctx, existing.ID, existing.Version)
+		publishWikiPageRevised(ctx, existing)
 	} else {
 		// No user-visible change — persist bookkeeping fields but preserve
 		// the version so downstream consumers can rely on it.
@@ -1339,9 +1342,32 @@ func (s *wikiPageService) CreateIssue(ctx context.Context, issue *types.WikiPage
 	if err := s.repo.CreateIssue(ctx, issue); err != nil {
 		return nil, fmt.Errorf("create wiki page issue: %w", err)
 	}
+	publishWorkspaceEvent(ctx, event.EventWikiIssueFlagged, event.WikiIssueFlaggedData{
+		TenantID:        issue.TenantID,
+		KnowledgeBaseID: issue.KnowledgeBaseID,
+		IssueID:         issue.ID,
+		Slug:            issue.Slug,
+		IssueType:       issue.IssueType,
+		Description:     issue.Description,
+		ReportedBy:      issue.ReportedBy,
+	})
 	return issue, nil
 }
 
+// publishWikiPageRevised announces a new version of a wiki page
+func publishWikiPageRevised(ctx context.Context, page *types.WikiPage) {
+	publishWorkspaceEvent(ctx, event.EventWikiPageRevised, event.WikiPageRevisedData{
+		TenantID:        page.TenantID,
+		KnowledgeBaseID: page.KnowledgeBaseID,
+		PageID:          page.ID,
+		Slug:            page.Slug,
+		Title:           page.Title,
+		PageType:        page.PageType,
+		Version:         page.Version,
+		EditSource:      page.LastEditSource,
+	})
+}
+
 // ListIssues retrieves issues for a knowledge base
 func (s *wikiPageService) ListIssues(ctx context.Context, kbID string, slug string, status string) ([]*types.WikiPageIssue, error) {
 	return s.repo.ListIssues(ctx, kbID, slug, status)
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+// publishWorkspaceEvent emits a workspace event on the global event bus.
+// Listeners run inline and only enqueue work of their own, so a failing
+// listener is logged and never fails the state change that published it.
+func publishWorkspaceEvent(ctx context.Context, eventType event.EventType, data any) {
+	if err := event.Emit(ctx, event.Event{Type: eventType, Data: data}); err != nil {
+		logger.Warnf(ctx, "publish %s event failed: %v", eventType, err)
+	}
+}
+
+// publishKnowledgeIngested announces that a knowledge item reached completed
+func publishKnowledgeIngested(ctx context.Context, knowledge *types.Knowledge) {
+	publishWorkspaceEvent(ctx, event.EventKnowledgeIngested, event.KnowledgeIngestedData{
+		TenantID:        knowledge.TenantID,
+		KnowledgeBaseID: knowledge.KnowledgeBaseID,
+		KnowledgeID:     knowledge.ID,
+		Title:           knowledge.Title,
+		Channel:         knowledge.Channel,
+	})
+}
+
+// publishKnowledgeIngestedByID loads the knowledge a FinalizeSubtask call
+// just promoted to completed and announces it
+func publishKnowledgeIngestedByID(ctx context.Context, repo interfaces.KnowledgeRepository, knowledgeID string) {
+	if !event.HasHandlers(event.EventKnowledgeIngested) {
+		return
+	}
+	knowledge, err := repo.GetKnowledgeByIDOnly(ctx, knowledgeID)
+	if err != nil || knowledge == nil {
+		logger.Warnf(ctx, "load knowledge %s for ingested event failed: %v", knowledgeID, err)
+		return
+	}
+	publishKnowledgeIngested(ctx, knowledge)
+}
+
+// publishKnowledgeLifecycle announces that a knowledge item was created,
+// failed processing or was deleted
+func publishKnowledgeLifecycle(ctx context.Context, eventType event.EventType, knowledge *types.Knowledge) {
+	if knowledge == nil {
+		return
+	}
+	publishWorkspaceEvent(ctx, eventType, event.KnowledgeLifecycleData{
+		TenantID:        knowledge.TenantID,
+		KnowledgeBaseID: knowledge.KnowledgeBaseID,
+		KnowledgeID:     knowledge.ID,
+		Title:           knowledge.Title,
+		Type:            knowledge.Type,
+		FileName:        knowledge.FileName,
+		FileType:        knowledge.FileType,
+		Source:          knowledge.Source,
+		Channel:         knowledge.Channel,
+		ParseStatus:     knowledge.ParseStatus,
+		ErrorMessage:    knowledge.ErrorMessage,
+	})
+}
+
+// publishKnowledgeBaseChanged announces that a knowledge base was created,
+// updated or deleted
+func publishKnowledgeBaseChanged(ctx context.Context, kb *types.KnowledgeBase, action string) {
+	if kb == nil {
+		return
+	}
+	publishWorkspaceEvent(ctx, event.EventKnowledgeBaseChanged, event.KnowledgeBaseChangedData{
+		TenantID:        kb.TenantID,
+		KnowledgeBaseID: kb.ID,
+		Name:            kb.Name,
+		Type:            kb.Type,
+		Action:          action,
+	})
+}
@@ -35,6 +35,7 @@ import (
 
 	"github.com/Tencent/WeKnora/internal/agent/approval"
 	"github.com/Tencent/WeKnora/internal/application/repository"
+	dbGraphRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/dbgraph"
 	dorisRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/doris"
 	elasticsearchRepoV7 "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch/v7"
 	elasticsearchRepoV8 "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch/v8"
@@ -55,6 +56,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/config"
 	"github.com/Tencent/WeKnora/internal/database"
 	"github.com/Tencent/WeKnora/internal/datasource"
+	confluenceConnector "github.com/Tencent/WeKnora/internal/datasource/connector/confluence"
 	"github.com/Tencent/WeKnora/internal/datasource/connector/feishu/core"
 	"github.com/Tencent/WeKnora/internal/datasource/connector/feishu/drive"
 	"github.com/Tencent/WeKnora/internal/datasource/connector/feishu/wiki"
@@ -80,6 +82,8 @@ import (
 	infra_web_search "github.com/Tencent/WeKnora/internal/infrastructure/web_search"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/mcp"
+	"github.com/Tencent/WeKnora/internal/mcpserver"
+	"github.com/Tencent/WeKnora/internal/metrics"
 	"github.com/Tencent/WeKnora/internal/models/chat"
 	"github.com/Tencent/WeKnora/internal/models/embedding"
 	"github.com/Tencent/WeKnora/internal/models/limiter"
@@ -160,7 +164,7 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(repository.NewUserRepository))
 	must(container.Provide(repository.NewAuthTokenRepository))
 	must(container.Provide(repository.NewSystemSettingRepository))
-	must(container.Provide(neo4jRepo.NewNeo4jRepository))
+	must(container.Provide(initGraphRepository))
 	must(container.Provide(repository.NewMCPServiceRepository))
 	must(container.Provide(repository.NewMCPToolApprovalRepository))
 	must(container.Provide(repository.NewMCPOAuthRepository))
@@ -177,8 +181,15 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(repository.NewSyncLogRepository))
 	must(container.Provide(repository.NewWikiPageRepository))
 	must(container.Provide(repository.NewMemoryRepository))
+	must(container.Provide(repository.NewGraphCommunityRepository))
 	must(container.Provide(repository.NewTaskPendingOpsRepository))
 	must(container.Provide(repository.NewTaskDeadLetterRepository))
+	must(container.Provide(repository.NewEvaluationRepository))
+	must(container.Provide(repository.NewAnswerCacheRepository))
+	must(container.Provide(repository.NewAgentTriggerRepository))
+	must(container.Provide(repository.NewWebhookRepository))
+	must(container.Provide(repository.NewKnowledgeACLRepository))
+	must(container.Provide(repository.NewUserGroupRepository))
 
 	// MCP manager for managing MCP client connections
 	logger.Debugf(ctx, "[Container] Registering MCP manager...")
@@ -203,6 +214,8 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(service.NewTenantInvitationService))
 	must(container.Provide(service.NewAuditLogService))
 	must(container.Provide(service.NewAuditLogRetentionRunner))
+	must(container.Provide(service.NewKnowledgeACLService))
+	must(container.Provide(service.NewUserGroupService))
 	must(container.Provide(service.NewKnowledgeBaseService))
 	must(container.Provide(service.NewOrganizationService))
 	must(container.Provide(service.NewKBShareService)) // KBShareService must be registered before KnowledgeService and KnowledgeTagService
@@ -215,6 +228,8 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(service.NewModelService))
 	must(container.Provide(service.NewDatasetService))
 	must(container.Provide(service.NewEvaluationService))
+	must(container.Invoke(recoverStaleEvaluations))
+	must(container.Provide(service.NewAnswerCacheService))
 	must(container.Provide(service.NewUserService))
 	must(container.Provide(service.NewSystemSettingService))
 	must(container.Provide(func(
@@ -227,6 +242,7 @@ func BuildContainer(container *dig.Container) *dig.Container {
 		return s
 	}))
 	must(container.Provide(service.NewWeKnoraCloudService))
+	must(container.Provide(service.NewGraphCommunityService))
 
 	// Extract services - register individual extracters with names
 	must(container.Provide(service.NewChunkExtractService, dig.Name("chunkExtractor")))
@@ -320,10 +336,13 @@ func BuildContainer(container *dig.Container) *dig.Container {
 		// dequeue of pending/scheduled/retry tasks + active-task cancel).
 		must(container.Provide(router.NewAsynqInspector))
 		must(container.Provide(router.NewAsynqTaskInspector))
+		// Queue depths for /metrics are read through the same inspector.
+		must(container.Invoke(metrics.RegisterTaskQueues))
 		// Install the distributed per-model chat concurrency governor. Only
 		// available with Redis (the shared semaphore backend); Lite mode is
 		// single-process and low-volume, so it runs ungated.
 		must(container.Invoke(registerModelConcurrencyLimiter))
+		must(container.Invoke(registerEmbeddingCache))
 	} else {
 		syncExec := router.NewSyncTaskExecutor()
 		must(container.Provide(func() interfaces.TaskEnqueuer { return syncExec }))
@@ -335,6 +354,7 @@ func BuildContainer(container *dig.Container) *dig.Container {
 		// Even without Redis, background ingestion/enrichment can burst the
 		// worker pool against one provider, so install an in-process governor.
 		must(container.Invoke(registerLiteModelConcurrencyLimiter))
+		must(container.Invoke(registerLiteEmbeddingCache))
 	}
 	must(container.Provide(service.NewTemporaryDocumentService))
 	must(container.Invoke(startTemporaryDocumentCleanup))
@@ -365,10 +385,13 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Invoke(chatpipeline.NewPluginChatCompletionStream))
 	must(container.Invoke(chatpipeline.NewPluginFilterTopK))
 	must(container.Invoke(chatpipeline.NewPluginQueryUnderstand))
+	must(container.Invoke(chatpipeline.NewPluginHyDE))
+	must(container.Invoke(chatpipeline.NewPluginQueryDecompose))
 	must(container.Invoke(chatpipeline.NewPluginLoadHistory))
 	must(container.Invoke(chatpipeline.NewPluginMemoryRecall))
 	must(container.Invoke(chatpipeline.NewPluginExtractEntity))
 	must(container.Invoke(chatpipeline.NewPluginSearchEntity))
+	must(container.Invoke(chatpipeline.NewPluginCommunitySearch))
 	must(container.Invoke(chatpipeline.NewPluginSearchParallel))
 	must(container.Invoke(chatpipeline.NewPluginWikiBoost))
 	must(container.Invoke(chatpipeline.NewPluginMemoryAffinity))
@@ -385,12 +408,14 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(handler.NewChunkHandler))
 	must(container.Provide(handler.NewFAQHandler))
 	must(container.Provide(handler.NewTagHandler))
+	must(container.Provide(handler.NewGraphCommunityHandler))
 	must(container.Provide(session.NewHandler))
 	must(container.Provide(handler.NewMessageHandler))
 	must(container.Provide(handler.NewMessageSuggestionHandler))
 	must(container.Provide(handler.NewModelHandler))
 	must(container.Provide(handler.NewSandboxConfigHandler))
 	must(container.Provide(handler.NewEvaluationHandler))
+	must(container.Provide(handler.NewAnswerCacheHandler))
 	must(container.Provide(handler.NewInitializationHandler))
 	must(container.Provide(handler.NewAuthHandler))
 	must(container.Provide(handler.NewSystemHandler))
@@ -420,6 +445,20 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(imPkg.NewService))
 	must(container.Invoke(registerIMService))
 	must(container.Provide(handler.NewIMHandler))
+	// Agent triggers deliver answers through IM channels, so they are wired
+	// after the IM service
+	must(container.Provide(service.NewAgentTriggerScheduler))
+	must(container.Provide(service.NewAgentTriggerService))
+	must(container.Invoke(startAgentTriggerScheduler))
+	must(container.Provide(handler.NewAgentTriggerHandler))
+	must(container.Provide(service.NewWebhookDispatcher))
+	must(container.Provide(service.NewWebhookService))
+	must(container.Invoke(startWebhookDispatcher))
+	must(container.Provide(handler.NewWebhookHandler))
+	must(container.Provide(handler.NewKnowledgeACLHandler))
+	must(container.Provide(handler.NewUserGroupHandler))
+	must(container.Provide(mcpserver.New))
+	must(container.Provide(handler.NewMCPServerHandler))
 	must(container.Provide(handler.NewEmbedChannelHandler))
 	must(container.Provide(handler.NewWeKnoraCloudHandler))
 	logger.Debugf(ctx, "[Container] HTTP handlers registered")
@@ -574,6 +613,51 @@ func registerLiteModelConcurrencyLimiter(ss interfaces.SystemSettingService) {
 		"[ModelLimiter] background model concurrency governed per-model, limit=%d (in-process, lite mode)", limit)
 }
 
+// embeddingCacheEnabled reports whether the embedding cache is switched on.
+func embeddingCacheEnabled(ss interfaces.SystemSettingService) bool {
+	if ss == nil {
+		return true
+	}
+	return ss.GetBool(context.Background(), "embedding.cache_enabled", "WEKNORA_EMBEDDING_CACHE_ENABLED", true)
+}
+
+// registerEmbeddingCache installs the Redis-backed embedding cache, shared by
+// every replica, so unchanged content is embedded once per model.
+func registerEmbeddingCache(rdb *redis.Client, ss interfaces.SystemSettingService) {
+	if !embeddingCacheEnabled(ss) {
+		logger.Infof(context.Background(), "[EmbeddingCache] disabled (embedding.cache_enabled=false)")
+		return
+	}
+	ttlHours := int64(168)
+	if ss != nil {
+		ttlHours = ss.GetInt(context.Background(), "embedding.cache_ttl_hours",
+			"WEKNORA_EMBEDDING_CACHE_TTL_HOURS", ttlHours)
+	}
+	embedding.SetCache(embedding.NewRedisCache(rdb, time.Duration(ttlHours)*time.Hour))
+	logger.Infof(context.Background(), "[EmbeddingCache] enabled (redis, ttl=%dh)", ttlHours)
+}
+
+// registerLiteEmbeddingCache installs an in-process LRU embedding cache for
+// Lite mode.
+func registerLiteEmbeddingCache(ss interfaces.SystemSettingService) {
+	if !embeddingCacheEnabled(ss) {
+		logger.Infof(context.Background(), "[EmbeddingCache] disabled (embedding.cache_enabled=false)")
+		return
+	}
+	maxEntries := int64(20000)
+	if ss != nil {
+		maxEntries = ss.GetInt(context.Background(), "embedding.cache_max_entries",
+			"WEKNORA_EMBEDDING_CACHE_MAX_ENTRIES", maxEntries)
+	}
+	cache := embedding.NewMemoryCache(int(maxEntries))
+	if cache == nil {
+		logger.Infof(context.Background(), "[EmbeddingCache] disabled (embedding.cache_max_entries<=0)")
+		return
+	}
+	embedding.SetCache(cache)
+	logger.Infof(context.Background(), "[EmbeddingCache] enabled (in-process, max_entries=%d)", maxEntries)
+}
+
 func initRedisClient() (*redis.Client, error) {
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr == "" {
@@ -1516,9 +1600,26 @@ func initOllamaService() (*ollama.OllamaService, error) {
 	return ollama.GetOllamaService()
 }
 
+// initGraphRepository selects the knowledge graph backend from GRAPH_DRIVER.
+// When the graph is disabled the Neo4j repository is returned with a nil
+// driver, which makes every call a no-op.
+func initGraphRepository(driver neo4j.Driver, db *gorm.DB) interfaces.RetrieveGraphRepository {
+	if types.GraphDriver() == types.GraphDriverDatabase {
+		logger.Infof(context.Background(), "Knowledge graph stored in the relational database")
+		return dbGraphRepo.NewRepository(db)
+	}
+	if types.GraphDriver() != types.GraphDriverNeo4j {
+		driver = nil
+	}
+	return neo4jRepo.NewNeo4jRepository(driver)
+}
+
+// initNeo4jClient connects to Neo4j when it is the graph backend, or when
+// GRAPH_MIGRATE_FROM_NEO4J asks for its graph to be copied into the
+// relational store.
 func initNeo4jClient() (neo4j.Driver, error) {
 	ctx := context.Background()
-	if strings.ToLower(os.Getenv("NEO4J_ENABLE")) != "true" {
+	if types.GraphDriver() != types.GraphDriverNeo4j && !types.GraphMigrateFromNeo4j() {
 		logger.Debugf(ctx, "NOT SUPPORT RETRIEVE GRAPH")
 		return nil, nil
 	}
@@ -1672,9 +1773,11 @@ func initConnectorRegistry() (*datasource.ConnectorRegistry, error) {
 	if err := registry.Register(gitlabConnector.NewConnector()); err != nil {
 		errs = errors.Join(errs, fmt.Errorf("register gitlab connector: %w", err))
 	}
+	if err := registry.Register(confluenceConnector.NewConnector()); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("register confluence connector: %w", err))
+	}
 
 	// Future connectors will be registered here:
-	// if err := registry.Register(confluenceConnector.NewConnector()); err != nil { ... }
 	// if err := registry.Register(githubConnector.NewConnector()); err != nil { ... }
 
 	if errs != nil {
@@ -1695,6 +1798,25 @@ func startDataSourceScheduler(scheduler *datasource.Scheduler, cleaner interface
 	})
 }
 
+// startAgentTriggerScheduler starts the agent trigger cron and event
+// subscriptions and registers cleanup.
+func startAgentTriggerScheduler(scheduler *service.AgentTriggerScheduler, cleaner interfaces.ResourceCleaner) {
+	if err := scheduler.Start(context.Background()); err != nil {
+		logger.Warnf(context.Background(), "[Container] agent trigger scheduler start failed: %v", err)
+	}
+
+	cleaner.RegisterWithName("AgentTriggerScheduler", func() error {
+		scheduler.Stop()
+		return nil
+	})
+}
+
+// startWebhookDispatcher subscribes tenant webhooks to workspace events.
+// The bus has no unsubscribe, so there is nothing to clean up.
+func startWebhookDispatcher(dispatcher *service.WebhookDispatcher) {
+	dispatcher.Start(context.Background())
+}
+
 // startHousekeepingService starts the knowledge housekeeping cron and registers
 // cleanup. This is the safety net that recovers any knowledge stuck in
 // "processing" past a configurable threshold (see HousekeepingService for
@@ -1738,6 +1860,12 @@ func startTemporaryDocumentCleanup(svc interfaces.TemporaryDocumentService, clea
 	})
 }
 
+// recoverStaleEvaluations fails evaluation tasks abandoned by a previous
+// process so clients polling them see a terminal status.
+func recoverStaleEvaluations(repo interfaces.EvaluationRepository) {
+	service.RecoverStaleEvaluations(context.Background(), repo)
+}
+
 // startAuditLogRetention spins up the daily audit_logs purge sweep
 // and registers shutdown cleanup. Mirrors the data-source-scheduler
 // pattern: container init kicks the goroutine, ResourceCleaner stops
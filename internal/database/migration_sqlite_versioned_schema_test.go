@@ -12,28 +12,51 @@ import (
 // versionedSQLiteTables is the set of tables that SQLite migrations must
 // create to stay in sync with the versioned (PostgreSQL) migrations:
 // 000041 task queue, 000053 system settings, 000055 processing spans,
-// 000063 knowledge multi-tags.
+// 000063 knowledge multi-tags, 000085 evaluation persistence, 000087
+// relational graph store, 000088 graph communities, 000092 answer cache,
+// 000094 agent triggers, 000095 webhooks, 000096 knowledge access lists,
+// 000097 data source permission sync.
 var versionedSQLiteTables = []string{
 	"task_pending_ops",
 	"task_dead_letters",
 	"system_settings",
 	"knowledge_processing_spans",
 	"knowledge_tag_relations",
+	"evaluation_tasks",
+	"evaluation_results",
+	"evaluation_datasets",
+	"evaluation_dataset_items",
+	"graph_nodes",
+	"graph_relations",
+	"graph_communities",
+	"answer_cache_entries",
+	"agent_triggers",
+	"agent_trigger_runs",
+	"webhook_subscriptions",
+	"webhook_deliveries",
+	"knowledge_acl_entries",
+	"user_groups",
+	"user_group_members",
 }
 
 // versionedSQLiteColumns maps each existing table to the columns that the
 // versioned migrations add and the SQLite baseline was missing.
 var versionedSQLiteColumns = map[string][]string{
-	"tenants":            {"api_principal_config"},           // 000064
-	"users":              {"is_system_admin"},                // 000053
-	"knowledges":         {"pending_subtasks_count"},         // 000056
-	"messages":           {"attachments"},                    // 000034
-	"tenant_invitations": {"token", "accepted_count"},        // 000054
-	"embed_channels":     {"allow_memory"},                   // 000060
-	"mcp_oauth_tokens":   {"principal_type", "principal_id"}, // 000064
+	"tenants":               {"api_principal_config"},                                                                 // 000064
+	"users":                 {"is_system_admin"},                                                                      // 000053
+	"knowledges":            {"pending_subtasks_count"},                                                               // 000056
+	"messages":              {"attachments"},                                                                          // 000034
+	"tenant_invitations":    {"token", "accepted_count"},                                                              // 000054
+	"embed_channels":        {"allow_memory"},                                                                         // 000060
+	"mcp_oauth_tokens":      {"principal_type", "principal_id"},                                                       // 000064
+	"evaluation_tasks":      {"judge_model_id", "fusion_config"},                                                      // 000086, 000093
+	"knowledge_bases":       {"embedding_index_id", "embedding_migration", "vector_store_migration", "fusion_config"}, // 000089, 000090, 000093
+	"data_sources":          {"sync_permissions"},                                                                     // 000097
+	"knowledge_acl_entries": {"data_source_id"},                                                                       // 000097
+	"graph_communities":     {"knowledge_ids"},                                                                        // 000098
 }
 
-const expectedSQLiteMigrationVersion = 11
+const expectedSQLiteMigrationVersion = 24
 
 func TestSQLiteMigrationsCreateVersionedSchema(t *testing.T) {
 	repoRoot := sqliteRepoRoot(t)
@@ -8,6 +8,13 @@ import (
 
 // Connector is the interface that all external data source connectors must implement.
 // Each connector (Feishu, Notion, Confluence, etc.) provides an implementation of this interface.
+//
+// Permission sync is optional. A connector that can read the source's sharing
+// settings advertises the "permission_sync" capability and, when
+// config.SyncPermissions is set, reports who may read each item in
+// FetchedItem.Permissions. Because sharing can change without the content
+// changing, it tracks permission fingerprints with a PermissionTracker and
+// emits a PermissionsOnly item for an unchanged item whose permissions moved.
 type Connector interface {
 	// Type returns the connector type identifier (e.g., "feishu", "notion")
 	Type() string
@@ -143,7 +150,7 @@ type ConnectorMetadata struct {
 	Icon         string   `json:"icon,omitempty"`
 	Priority     int      `json:"priority"`     // Priority order for UI display (lower = higher priority)
 	AuthType     string   `json:"auth_type"`    // "oauth2", "api_key", "token", etc.
-	Capabilities []string `json:"capabilities"` // "incremental", "webhook", "deletion_sync", etc.
+	Capabilities []string `json:"capabilities"` // "incremental", "webhook", "deletion_sync", "permission_sync", etc.
 }
 
 // GetConnectorMetadata returns metadata for all available connectors
@@ -155,7 +162,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync documents, wikis, and content from Feishu",
 		Priority:     0,
 		AuthType:     "oauth2",
-		Capabilities: []string{"incremental", "deletion_sync"},
+		Capabilities: []string{"incremental", "deletion_sync", "permission_sync"},
 	},
 	types.ConnectorTypeLark: {
 		Type:         types.ConnectorTypeLark,
@@ -163,7 +170,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync documents, wikis, and content from Lark (Feishu international)",
 		Priority:     0,
 		AuthType:     "oauth2",
-		Capabilities: []string{"incremental", "deletion_sync"},
+		Capabilities: []string{"incremental", "deletion_sync", "permission_sync"},
 	},
 	types.ConnectorTypeFeishuDrive: {
 		Type:         types.ConnectorTypeFeishuDrive,
@@ -171,7 +178,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync documents and files from a Feishu Drive folder",
 		Priority:     0,
 		AuthType:     "oauth2",
-		Capabilities: []string{"incremental", "deletion_sync"},
+		Capabilities: []string{"incremental", "deletion_sync", "permission_sync"},
 	},
 	types.ConnectorTypeLarkDrive: {
 		Type:         types.ConnectorTypeLarkDrive,
@@ -179,7 +186,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync documents and files from a Lark Drive folder",
 		Priority:     0,
 		AuthType:     "oauth2",
-		Capabilities: []string{"incremental", "deletion_sync"},
+		Capabilities: []string{"incremental", "deletion_sync", "permission_sync"},
 	},
 	types.ConnectorTypeNotion: {
 		Type:         types.ConnectorTypeNotion,
@@ -192,10 +199,10 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 	types.ConnectorTypeConfluence: {
 		Type:         types.ConnectorTypeConfluence,
 		Name:         "Confluence",
-		Description:  "Sync spaces and pages from Atlassian Confluence",
+		Description:  "Sync spaces, pages and attachments from Atlassian Confluence Cloud or Data Center",
 		Priority:     2,
 		AuthType:     "api_key",
-		Capabilities: []string{"incremental"},
+		Capabilities: []string{"incremental", "deletion_sync"},
 	},
 	types.ConnectorTypeYuque: {
 		Type:         types.ConnectorTypeYuque,
@@ -203,7 +210,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync knowledge bases and documents from Yuque",
 		Priority:     3,
 		AuthType:     "api_key",
-		Capabilities: []string{"incremental"},
+		Capabilities: []string{"incremental", "permission_sync"},
 	},
 	types.ConnectorTypeIMA: {
 		Type:         types.ConnectorTypeIMA,
@@ -283,7 +290,7 @@ var ConnectorMetadataRegistry = map[string]ConnectorMetadata{
 		Description:  "Sync files from GitLab projects",
 		Priority:     8,
 		AuthType:     "token",
-		Capabilities: []string{"incremental", "hierarchical"},
+		Capabilities: []string{"incremental", "hierarchical", "permission_sync"},
 	},
 }
 
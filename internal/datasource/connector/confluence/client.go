@@ -0,0 +1,398 @@
+// Package confluence implements the WeKnora data source connector for
+// Atlassian Confluence Cloud and Data Center, syncing pages and their
+// attachments out of spaces and page trees via the REST API (v1), which both
+// deployments serve.
+package confluence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/utils"
+)
+
+const (
+	defaultTimeout  = 60 * time.Second
+	defaultPageSize = 50
+	userAgent       = "WeKnora-Confluence-Connector/1.0"
+
+	// Attachments can be large office files served slowly by Data Center.
+	downloadTimeout = 120 * time.Second
+
+	// Max attachment body we accept; larger files are skipped before download.
+	maxDownloadBytes = 100 * 1024 * 1024
+
+	// Expansions for a page fetched for ingestion. export_view is the rendered
+	// HTML (macros expanded), which converts to far better Markdown than the
+	// storage format's XML.
+	pageExpand = "body.export_view,version,space,ancestors"
+)
+
+// errNotFound is returned for a 404: the content was deleted (or its
+// permission revoked) between listing and fetching.
+var errNotFound = errors.New("confluence content not found")
+
+// retryBackoff is the wait before each retry of a rate-limited or failed
+// request. A variable so tests can shorten it.
+var retryBackoff = []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}
+
+// client wraps the Confluence REST API. It is safe for concurrent use.
+type client struct {
+	cfg        *Config
+	apiURL     string
+	siteURL    string
+	httpClient *http.Client
+	// downloadClient has a longer timeout for attachment bodies.
+	downloadClient    *http.Client
+	logCredentialOnce sync.Once
+}
+
+// newClient constructs a client for the configured deployment.
+func newClient(cfg *Config) *client {
+	return &client{
+		cfg:            cfg,
+		apiURL:         cfg.apiURL(),
+		siteURL:        cfg.siteURL(),
+		httpClient:     datasource.NewConnectorHTTPClient(defaultTimeout),
+		downloadClient: datasource.NewConnectorHTTPClient(downloadTimeout),
+	}
+}
+
+// authorize sets the credential header: Bearer for a personal access token,
+// Basic for email (username) plus API token (password).
+func (c *client) authorize(req *http.Request) {
+	if pat := strings.TrimSpace(c.cfg.PersonalAccessToken); pat != "" && c.cfg.GetDeployment() == DeploymentDataCenter {
+		req.Header.Set("Authorization", "Bearer "+pat)
+		return
+	}
+	req.SetBasicAuth(strings.TrimSpace(c.cfg.Email), strings.TrimSpace(c.cfg.APIToken))
+}
+
+// get executes an authenticated GET against <apiURL><path> and decodes the
+// JSON body into result. 429 and network errors are retried with backoff,
+// 5xx once; 401/403 map to ErrInvalidCredentials and 404 to errNotFound.
+func (c *client) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	const max5xxRetries = 1
+
+	c.logCredentialOnce.Do(func() {
+		logger.Infof(ctx, "[Confluence] client configured deployment=%s user=%s token=%s base=%s",
+			c.cfg.GetDeployment(), c.cfg.Email,
+			redact(c.cfg.APIToken+c.cfg.PersonalAccessToken), c.apiURL)
+	})
+
+	u := c.apiURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= len(retryBackoff); attempt++ {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		c.authorize(httpReq)
+		httpReq.Header.Set("Accept", "application/json")
+		httpReq.Header.Set("User-Agent", userAgent)
+
+		if attempt == 0 {
+			logger.Debugf(ctx, "[Confluence] GET %s", path)
+		} else {
+			logger.Infof(ctx, "[Confluence] GET %s (retry %d/%d)", path, attempt, len(retryBackoff))
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			lastErr = fmt.Errorf("execute request: %w", err)
+			if attempt < len(retryBackoff) {
+				if sErr := sleepCtx(ctx, retryBackoff[attempt]); sErr != nil {
+					return sErr
+				}
+				continue
+			}
+			return lastErr
+		}
+
+		respBody, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if readErr != nil {
+			lastErr = fmt.Errorf("read response: %w", readErr)
+			if attempt < len(retryBackoff) {
+				if sErr := sleepCtx(ctx, retryBackoff[attempt]); sErr != nil {
+					return sErr
+				}
+				continue
+			}
+			return lastErr
+		}
+		bodyPreview := truncate(string(respBody), 500)
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("%w: status=%d body=%s",
+				datasource.ErrInvalidCredentials, resp.StatusCode, bodyPreview)
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%w: %s", errNotFound, path)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			lastErr = fmt.Errorf("confluence rate limited: status=429 body=%s", bodyPreview)
+			if attempt < len(retryBackoff) {
+				if sErr := sleepCtx(ctx, retryAfter(resp, retryBackoff[attempt])); sErr != nil {
+					return sErr
+				}
+				continue
+			}
+			return lastErr
+		case resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("confluence server error: status=%d body=%s", resp.StatusCode, bodyPreview)
+			if attempt < max5xxRetries {
+				if sErr := sleepCtx(ctx, retryBackoff[attempt]); sErr != nil {
+					return sErr
+				}
+				continue
+			}
+			return lastErr
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			return fmt.Errorf("confluence api http error: status=%d body=%s", resp.StatusCode, bodyPreview)
+		}
+
+		if result != nil {
+			if err := json.Unmarshal(respBody, result); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// listContent pages through a content listing endpoint until it is exhausted.
+func (c *client) listContent(ctx context.Context, path string, query url.Values) ([]content, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	var out []content
+	for start := 0; ; {
+		query.Set("start", strconv.Itoa(start))
+		query.Set("limit", strconv.Itoa(defaultPageSize))
+		var page contentList
+		if err := c.get(ctx, path, query, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Results...)
+		if len(page.Results) == 0 || page.Links.Next == "" {
+			return out, nil
+		}
+		start += len(page.Results)
+	}
+}
+
+// ListSpaces — GET /space. Returns every current space the credential can read.
+func (c *client) ListSpaces(ctx context.Context) ([]space, error) {
+	query := url.Values{"status": {"current"}, "expand": {"description.plain"}}
+	var out []space
+	for start := 0; ; {
+		query.Set("start", strconv.Itoa(start))
+		query.Set("limit", strconv.Itoa(defaultPageSize))
+		var page spaceList
+		if err := c.get(ctx, "/space", query, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Results...)
+		if len(page.Results) == 0 || page.Links.Next == "" {
+			return out, nil
+		}
+		start += len(page.Results)
+	}
+}
+
+// Ping checks the credentials with the cheapest authenticated call.
+func (c *client) Ping(ctx context.Context) error {
+	query := url.Values{"limit": {"1"}}
+	return c.get(ctx, "/space", query, &spaceList{})
+}
+
+// ListRootPages — GET /space/{key}/content/page?depth=root. The top level of
+// a space's page tree.
+func (c *client) ListRootPages(ctx context.Context, spaceKey string) ([]content, error) {
+	query := url.Values{"depth": {"root"}, "expand": {"children.page"}}
+	return c.listContent(ctx, "/space/"+url.PathEscape(spaceKey)+"/content/page", query)
+}
+
+// ListChildPages — GET /content/{id}/child/page. Direct children only.
+func (c *client) ListChildPages(ctx context.Context, pageID string) ([]content, error) {
+	query := url.Values{"expand": {"children.page"}}
+	return c.listContent(ctx, "/content/"+url.PathEscape(pageID)+"/child/page", query)
+}
+
+// ListSpacePages — GET /content?spaceKey=KEY&type=page. Every current page of
+// a space with its version, the listing a space resource is synced from.
+func (c *client) ListSpacePages(ctx context.Context, spaceKey string) ([]content, error) {
+	query := url.Values{
+		"spaceKey": {spaceKey},
+		"type":     {"page"},
+		"status":   {"current"},
+		"expand":   {"version"},
+	}
+	return c.listContent(ctx, "/content", query)
+}
+
+// ListDescendantPages — GET /content/{id}/descendant/page. Every page below
+// pageID at any depth, with its version.
+func (c *client) ListDescendantPages(ctx context.Context, pageID string) ([]content, error) {
+	query := url.Values{"expand": {"version"}}
+	return c.listContent(ctx, "/content/"+url.PathEscape(pageID)+"/descendant/page", query)
+}
+
+// GetContent — GET /content/{id} with the given expansions.
+func (c *client) GetContent(ctx context.Context, id, expand string) (*content, error) {
+	query := url.Values{}
+	if expand != "" {
+		query.Set("expand", expand)
+	}
+	var out content
+	if err := c.get(ctx, "/content/"+url.PathEscape(id), query, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAttachments — GET /content/{id}/child/attachment.
+func (c *client) ListAttachments(ctx context.Context, pageID string) ([]content, error) {
+	query := url.Values{"expand": {"version"}}
+	return c.listContent(ctx, "/content/"+url.PathEscape(pageID)+"/child/attachment", query)
+}
+
+// SearchAttachmentsSince — GET /content/search with CQL. Returns attachments
+// of a space modified at or after since, with their container page.
+//
+// Adding or replacing an attachment does not bump the page version, so this
+// is how the incremental sync notices attachment changes on an otherwise
+// unchanged page. CQL compares dates at minute precision in the server's
+// time zone; the caller passes a generously early since and filters the
+// result against its cursor.
+func (c *client) SearchAttachmentsSince(ctx context.Context, spaceKey string, since time.Time) ([]content, error) {
+	cql := fmt.Sprintf(`type = attachment AND space = "%s" AND lastmodified >= "%s"`,
+		strings.ReplaceAll(spaceKey, `"`, `\"`), since.Format("2006/01/02 15:04"))
+	query := url.Values{"cql": {cql}, "expand": {"container,version"}}
+	return c.listContent(ctx, "/content/search", query)
+}
+
+// Download fetches an attachment body from its `_links.download` path.
+// Enforces maxDownloadBytes to avoid a runaway body blowing up sync memory.
+func (c *client) Download(ctx context.Context, downloadPath string) ([]byte, string, error) {
+	target, err := c.resolveSiteLink(downloadPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := utils.ValidateURLForSSRF(target); err != nil {
+		return nil, "", fmt.Errorf("download URL rejected: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create download request: %w", err)
+	}
+	c.authorize(req)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.downloadClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("download: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("download http error: status=%d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("read download body: %w", err)
+	}
+	if int64(len(body)) > maxDownloadBytes {
+		return nil, "", fmt.Errorf("download body exceeds %d bytes", maxDownloadBytes)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// resolveSiteLink turns a `_links` path into an absolute URL. Absolute links
+// are only followed on the configured host, since the request carries the
+// user's credentials.
+func (c *client) resolveSiteLink(link string) (string, error) {
+	if link == "" {
+		return "", fmt.Errorf("empty link")
+	}
+	if !strings.Contains(link, "://") {
+		return c.siteURL + "/" + strings.TrimLeft(link, "/"), nil
+	}
+	target, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("parse link: %w", err)
+	}
+	site, err := url.Parse(c.siteURL)
+	if err != nil {
+		return "", fmt.Errorf("parse site url: %w", err)
+	}
+	if !strings.EqualFold(target.Host, site.Host) {
+		return "", fmt.Errorf("link host %q does not match site host %q", target.Host, site.Host)
+	}
+	return link, nil
+}
+
+// webURL returns the browser URL of a content entity or space.
+func (c *client) webURL(l links) string {
+	if l.WebUI == "" {
+		return ""
+	}
+	u, err := c.resolveSiteLink(l.WebUI)
+	if err != nil {
+		return ""
+	}
+	return u
+}
+
+// retryAfter honours a Retry-After header (seconds), capped at a minute.
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && secs > 0 {
+			return min(time.Duration(secs)*time.Second, time.Minute)
+		}
+	}
+	return fallback
+}
+
+// sleepCtx pauses for d, returning early if ctx is cancelled.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
+
+// redact returns a masked form of a credential for logging (never log full).
+func redact(t string) string {
+	if len(t) < 12 {
+		return "***"
+	}
+	return t[:6] + "..." + t[len(t)-4:]
+}
@@ -0,0 +1,479 @@
+package confluence
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestConnector_Type(t *testing.T) {
+	if got := NewConnector().Type(); got != types.ConnectorTypeConfluence {
+		t.Errorf("Type() = %q, want %q", got, types.ConnectorTypeConfluence)
+	}
+}
+
+// seedSpace builds space ENG with a small tree:
+//
+//	100 Handbook
+//	├── 110 Onboarding
+//	│   └── 111 Laptop setup
+//	└── 120 Policies
+//	200 Runbooks
+func seedSpace(f *fakeConfluence) {
+	f.addSpace("ENG", "Engineering")
+	f.addSpace("OPS", "Operations")
+	f.putPage(fakePage{ID: "100", Title: "Handbook", SpaceKey: "ENG", When: "2024-01-01T00:00:00.000Z",
+		Body: "<p>Welcome to <strong>Engineering</strong>.</p>"})
+	f.putPage(fakePage{ID: "110", Title: "Onboarding", SpaceKey: "ENG", ParentID: "100", When: "2024-01-01T00:00:00.000Z",
+		Body: "<table><tr><th>Step</th><th>Owner</th></tr><tr><td>Badge</td><td>IT</td></tr></table>"})
+	f.putPage(fakePage{ID: "111", Title: "Laptop setup", SpaceKey: "ENG", ParentID: "110", When: "2024-01-01T00:00:00.000Z",
+		Body: "<p>Install the VPN.</p>"})
+	f.putPage(fakePage{ID: "120", Title: "Policies", SpaceKey: "ENG", ParentID: "100", When: "2024-01-01T00:00:00.000Z",
+		Body: "<p>Be kind.</p>"})
+	f.putPage(fakePage{ID: "200", Title: "Runbooks", SpaceKey: "ENG", When: "2024-01-01T00:00:00.000Z",
+		Body: "<p>Page the on-call.</p>"})
+}
+
+func TestConfigAPIURL(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "data center keeps its context path",
+			cfg:  Config{BaseURL: "wiki.example.com/confluence/"},
+			want: "https://wiki.example.com/confluence/rest/api",
+		},
+		{
+			name: "cloud inferred from host, /wiki suffix trimmed",
+			cfg:  Config{BaseURL: "https://acme.atlassian.net/wiki"},
+			want: "https://acme.atlassian.net/wiki/rest/api",
+		},
+		{
+			name: "explicit cloud on a custom domain",
+			cfg:  Config{BaseURL: "https://docs.example.com", Deployment: DeploymentCloud},
+			want: "https://docs.example.com/wiki/rest/api",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cfg.apiURL(); got != tc.want {
+				t.Errorf("apiURL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigRejectsIncompleteConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		creds   map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:    "cloud needs email and token",
+			creds:   map[string]interface{}{"base_url": "https://acme.atlassian.net", "personal_access_token": "p"},
+			wantErr: datasource.ErrInvalidCredentials,
+		},
+		{
+			name:    "data center needs some credential",
+			creds:   map[string]interface{}{"base_url": "https://wiki.example.com"},
+			wantErr: datasource.ErrInvalidCredentials,
+		},
+		{
+			name:    "missing base url",
+			creds:   map[string]interface{}{"personal_access_token": "p"},
+			wantErr: datasource.ErrInvalidConfig,
+		},
+		{
+			name:    "unknown deployment",
+			creds:   map[string]interface{}{"base_url": "https://x", "deployment": "onprem", "personal_access_token": "p"},
+			wantErr: datasource.ErrInvalidConfig,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseConfluenceConfig(&types.DataSourceConfig{Credentials: tc.creds})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	c := NewConnector()
+
+	if err := c.Validate(context.Background(), f.config()); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	bad := f.config()
+	bad.Credentials["personal_access_token"] = "wrong"
+	if err := c.Validate(context.Background(), bad); !errors.Is(err, datasource.ErrInvalidCredentials) {
+		t.Fatalf("Validate with a bad token = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+// TestCloudUsesWikiPathAndBasicAuth checks the Cloud layout end to end: the
+// API under /wiki/rest/api and Basic auth with email plus API token.
+func TestCloudUsesWikiPathAndBasicAuth(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	cfg := &types.DataSourceConfig{Credentials: map[string]interface{}{
+		"base_url":   f.server.URL,
+		"deployment": DeploymentCloud,
+		"email":      fakeEmail,
+		"api_token":  fakeAPIToken,
+	}}
+	res, err := NewConnector().ListResources(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("got %d spaces, want 2", len(res))
+	}
+	if !strings.HasPrefix(res[0].URL, f.server.URL+"/wiki/spaces/") {
+		t.Errorf("space URL = %q, want it under the /wiki site", res[0].URL)
+	}
+}
+
+func TestListResources_LazyTree(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	c := NewConnector()
+	cfg := f.config()
+
+	spaces, err := c.ListResources(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("root: %v", err)
+	}
+	if len(spaces) != 2 || spaces[0].ExternalID != "space:ENG" || !spaces[0].HasChildren {
+		t.Fatalf("unexpected spaces: %+v", spaces)
+	}
+
+	roots, err := c.ListResources(context.Background(), cfg, "space:ENG")
+	if err != nil {
+		t.Fatalf("space: %v", err)
+	}
+	if len(roots) != 2 || roots[0].ExternalID != "100" || roots[1].ExternalID != "200" {
+		t.Fatalf("unexpected root pages: %+v", roots)
+	}
+	if !roots[0].HasChildren || roots[1].HasChildren {
+		t.Errorf("HasChildren = %t/%t, want true/false", roots[0].HasChildren, roots[1].HasChildren)
+	}
+	if roots[0].ParentID != "space:ENG" {
+		t.Errorf("ParentID = %q, want space:ENG", roots[0].ParentID)
+	}
+
+	children, err := c.ListResources(context.Background(), cfg, "100")
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if len(children) != 2 || children[0].ExternalID != "110" || children[1].ExternalID != "120" {
+		t.Fatalf("unexpected children: %+v", children)
+	}
+}
+
+func TestResolveResourceAncestors(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+
+	got, err := NewConnector().ResolveResourceAncestors(context.Background(), f.config(),
+		[]string{"111", "space:OPS", "missing"})
+	if err != nil {
+		t.Fatalf("ResolveResourceAncestors: %v", err)
+	}
+	want := map[string]bool{"space:ENG": true, "100": true, "110": true}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, id := range got {
+		if !want[id] {
+			t.Fatalf("unexpected ancestor %q in %v", id, got)
+		}
+	}
+}
+
+// recordingHandler collects emitted items and checkpoints.
+type recordingHandler struct {
+	items       []types.FetchedItem
+	checkpoints []*types.SyncCursor
+}
+
+func (h *recordingHandler) Emit(_ context.Context, item types.FetchedItem) error {
+	h.items = append(h.items, item)
+	return nil
+}
+
+func (h *recordingHandler) Checkpoint(_ context.Context, cursor *types.SyncCursor) error {
+	h.checkpoints = append(h.checkpoints, cursor)
+	return nil
+}
+
+func TestFetchStream_FullSyncEmitsPagesAndAttachments(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	f.setAttachments("110",
+		fakeAttachment{ID: "att1", Title: "checklist.pdf", Body: "%PDF-1.4 checklist", When: "2024-01-01T00:00:00.000Z"},
+		fakeAttachment{ID: "att2", Title: "logo.png", Body: "png", When: "2024-01-01T00:00:00.000Z"},
+	)
+	old := checkpointInterval
+	checkpointInterval = 2
+	t.Cleanup(func() { checkpointInterval = old })
+
+	h := &recordingHandler{}
+	cursor, err := NewConnector().FetchStream(context.Background(), f.config("space:ENG"), nil, h)
+	if err != nil {
+		t.Fatalf("FetchStream: %v", err)
+	}
+	if len(h.items) != 6 {
+		t.Fatalf("got %d items, want 5 pages + 1 attachment: %s", len(h.items), describeItems(h.items))
+	}
+
+	page := mustFindItem(t, h.items, "110")
+	body := string(page.Content)
+	if !strings.HasPrefix(body, "# Onboarding\n") || !strings.Contains(body, "| Badge |") {
+		t.Errorf("page markdown = %q, want title heading and a Markdown table", body)
+	}
+	if page.Metadata["channel"] != types.ChannelConfluence || page.Metadata["space_key"] != "ENG" ||
+		page.Metadata["page_path"] != "Handbook" {
+		t.Errorf("unexpected metadata: %v", page.Metadata)
+	}
+	if !strings.Contains(page.URL, "pageId=110") {
+		t.Errorf("page URL = %q", page.URL)
+	}
+
+	childID := types.SubtreeChildID("110", "file", "att1")
+	if !page.ReplacesSubtree || len(page.SubtreeKeep) != 2 || page.SubtreeKeep[0] != childID {
+		t.Errorf("parent must replace its subtree and keep every listed attachment, got keep=%v", page.SubtreeKeep)
+	}
+	att := mustFindItem(t, h.items, childID)
+	if string(att.Content) != "%PDF-1.4 checklist" || att.FileName != "checklist.pdf" {
+		t.Errorf("unexpected attachment item: %+v", att)
+	}
+	parentAt, childAt := -1, -1
+	for i, it := range h.items {
+		switch it.ExternalID {
+		case "110":
+			parentAt = i
+		case childID:
+			childAt = i
+		}
+	}
+	if parentAt > childAt {
+		t.Error("the parent page must be emitted before its attachments")
+	}
+
+	if len(h.checkpoints) == 0 {
+		t.Error("expected intermediate checkpoints")
+	}
+	state := decodeCursor(t, cursor)
+	if len(state.Pages["space:ENG"]) != 5 {
+		t.Errorf("cursor pages = %v", state.Pages)
+	}
+	if state.Attachments["110"]["att2"] == "" {
+		t.Error("every listed attachment version must be recorded, even unparsed ones")
+	}
+}
+
+func TestFetchIncremental_SkipsUnchangedRefetchesEditedAndTombstonesDeleted(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	f.setAttachments("120", fakeAttachment{ID: "att9", Title: "policy.docx", Body: "docx", When: "2024-01-01T00:00:00.000Z"})
+	c := NewConnector()
+	cfg := f.config("space:ENG")
+
+	_, cursor, err := c.FetchIncremental(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	f.putPage(fakePage{ID: "111", Title: "Laptop setup", SpaceKey: "ENG", ParentID: "110",
+		When: "2024-02-01T00:00:00.000Z", Body: "<p>Install the VPN and the MDM profile.</p>"})
+	f.deletePage("120")
+	fetchesBefore := f.callCount("/content/100")
+
+	items, cursor2, err := c.FetchIncremental(context.Background(), cfg, cursor)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	edited := mustFindItem(t, items, "111")
+	if !strings.Contains(string(edited.Content), "MDM profile") {
+		t.Errorf("edited page content = %q", edited.Content)
+	}
+	if _, ok := findItem(items, "100"); ok {
+		t.Errorf("unchanged page must be skipped: %s", describeItems(items))
+	}
+	if f.callCount("/content/100") != fetchesBefore {
+		t.Error("unchanged page body must not be fetched")
+	}
+	for _, id := range []string{"120", types.SubtreeChildID("120", "file", "att9")} {
+		if it := mustFindItem(t, items, id); !it.IsDeleted {
+			t.Errorf("%s should be a tombstone", id)
+		}
+	}
+	state := decodeCursor(t, cursor2)
+	if state.Pages["space:ENG"]["111"] != "2024-02-01T00:00:00.000Z" {
+		t.Errorf("cursor did not advance for the edited page: %v", state.Pages)
+	}
+	if _, ok := state.Pages["space:ENG"]["120"]; ok {
+		t.Error("deleted page must leave the cursor")
+	}
+	if !strings.Contains(f.cql, `space = "ENG"`) || !strings.Contains(f.cql, "type = attachment") {
+		t.Errorf("unexpected attachment CQL %q", f.cql)
+	}
+}
+
+// TestFetchIncremental_AttachmentChangeRefetchesPage: adding an attachment
+// does not bump the page version, so the CQL attachment search is what makes
+// the page sync again, and only once.
+func TestFetchIncremental_AttachmentChangeRefetchesPage(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	c := NewConnector()
+	cfg := f.config("space:ENG")
+
+	_, cursor, err := c.FetchIncremental(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	f.setAttachments("200", fakeAttachment{ID: "att5", Title: "runbook.pdf", Body: "%PDF runbook", When: "2024-03-01T00:00:00.000Z"})
+	items, cursor, err := c.FetchIncremental(context.Background(), cfg, cursor)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	mustFindItem(t, items, "200")
+	mustFindItem(t, items, types.SubtreeChildID("200", "file", "att5"))
+	if len(items) != 2 {
+		t.Errorf("only the page with the new attachment should sync: %s", describeItems(items))
+	}
+
+	// The CQL window still covers the attachment, but the cursor has it now.
+	items, _, err = c.FetchIncremental(context.Background(), cfg, cursor)
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("an already-synced attachment must not re-trigger its page: %s", describeItems(items))
+	}
+}
+
+func TestFetchIncremental_FailureIsRetriedAndNotDeleted(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	c := NewConnector()
+	cfg := f.config("space:ENG")
+
+	_, cursor, err := c.FetchIncremental(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	f.putPage(fakePage{ID: "200", Title: "Runbooks", SpaceKey: "ENG", When: "2024-02-01T00:00:00.000Z",
+		Body: "<p>v2</p>", Fails: true})
+	items, cursor, err := c.FetchIncremental(context.Background(), cfg, cursor)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	failed := mustFindItem(t, items, "200")
+	if failed.IsDeleted || failed.Metadata["error"] == "" || len(failed.Content) != 0 {
+		t.Fatalf("expected an error item, got %+v", failed)
+	}
+	if got := decodeCursor(t, cursor).Pages["space:ENG"]["200"]; got != "2024-01-01T00:00:00.000Z" {
+		t.Fatalf("a failed page must keep its previous version in the cursor, got %q", got)
+	}
+
+	f.putPage(fakePage{ID: "200", Title: "Runbooks", SpaceKey: "ENG", When: "2024-02-01T00:00:00.000Z", Body: "<p>v2</p>"})
+	items, _, err = c.FetchIncremental(context.Background(), cfg, cursor)
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if it := mustFindItem(t, items, "200"); !strings.Contains(string(it.Content), "v2") {
+		t.Errorf("retried content = %q", it.Content)
+	}
+}
+
+func TestFetchAll_PageResourceCoversDescendantsOnly(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+
+	items, err := NewConnector().FetchAll(context.Background(), f.config(), []string{"110"})
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("want the page and its descendant, got %s", describeItems(items))
+	}
+	mustFindItem(t, items, "110")
+	if it := mustFindItem(t, items, "111"); it.Metadata["page_path"] != "Handbook / Onboarding" {
+		t.Errorf("page_path = %q", it.Metadata["page_path"])
+	}
+}
+
+// TestFetchStream_CheckpointIsResumable: a checkpoint taken mid-run must keep
+// the previous entries of pages not yet visited, otherwise resuming from it
+// would forget a deletion that happened before the interrupted run.
+func TestFetchStream_CheckpointIsResumable(t *testing.T) {
+	f := newFakeConfluence(t)
+	seedSpace(f)
+	c := NewConnector()
+	cfg := f.config("space:ENG")
+
+	_, cursor, err := c.FetchIncremental(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	old := checkpointInterval
+	checkpointInterval = 1
+	t.Cleanup(func() { checkpointInterval = old })
+
+	f.putPage(fakePage{ID: "100", Title: "Handbook", SpaceKey: "ENG", When: "2024-02-01T00:00:00.000Z", Body: "<p>v2</p>"})
+	f.deletePage("200")
+	h := &recordingHandler{}
+	if _, err := c.FetchStream(context.Background(), cfg, cursor, h); err != nil {
+		t.Fatalf("FetchStream: %v", err)
+	}
+	if len(h.checkpoints) == 0 {
+		t.Fatal("expected a checkpoint")
+	}
+
+	// Resume from the first checkpoint, as a retried sync would.
+	resumed := &recordingHandler{}
+	if _, err := c.FetchStream(context.Background(), cfg, h.checkpoints[0], resumed); err != nil {
+		t.Fatalf("resumed FetchStream: %v", err)
+	}
+	if it := mustFindItem(t, resumed.items, "200"); !it.IsDeleted {
+		t.Errorf("resumed run lost the deletion: %s", describeItems(resumed.items))
+	}
+	if _, ok := findItem(resumed.items, "100"); ok {
+		t.Errorf("page synced before the checkpoint must not be fetched again: %s", describeItems(resumed.items))
+	}
+}
+
+func TestFetchStream_RequiresResources(t *testing.T) {
+	f := newFakeConfluence(t)
+	if _, err := NewConnector().FetchStream(context.Background(), f.config(), nil, &recordingHandler{}); err == nil {
+		t.Fatal("expected an error without resource IDs")
+	}
+}
+
+func TestResolveSiteLinkRejectsForeignHost(t *testing.T) {
+	cli := newClient(&Config{BaseURL: "https://wiki.example.com", PersonalAccessToken: "p"})
+	if _, err := cli.resolveSiteLink("https://evil.example.net/download/x"); err == nil {
+		t.Fatal("credentials must not be sent to another host")
+	}
+	got, err := cli.resolveSiteLink("/download/attachments/1/a.pdf?version=2")
+	if err != nil || got != "https://wiki.example.com/download/attachments/1/a.pdf?version=2" {
+		t.Fatalf("resolveSiteLink = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,415 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// TestMain whitelists loopback for SSRF so the httptest servers (127.0.0.1)
+// are reachable, and shortens retry waits. Production keeps the default
+// strict SSRF policy.
+func TestMain(m *testing.M) {
+	_ = os.Setenv("SSRF_WHITELIST", "127.0.0.1,localhost")
+	secutils.ResetSSRFWhitelistForTest()
+	retryBackoff = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	os.Exit(m.Run())
+}
+
+const (
+	fakePAT      = "pat-secret"
+	fakeEmail    = "me@example.com"
+	fakeAPIToken = "api-token"
+	// fakeMaxLimit caps page sizes like the real API does, so every listing
+	// in the tests goes through pagination.
+	fakeMaxLimit = 2
+)
+
+type fakePage struct {
+	ID       string
+	Title    string
+	SpaceKey string
+	ParentID string
+	Body     string
+	When     string
+	// Fails makes GET /content/{id} return a 500.
+	Fails bool
+}
+
+type fakeAttachment struct {
+	ID    string
+	Title string
+	Body  string
+	When  string
+	// DownloadFails makes the download return a 500.
+	DownloadFails bool
+}
+
+// fakeConfluence is an in-process stand-in for the Confluence REST API. Only
+// the endpoints the connector calls are implemented. It serves the Data
+// Center layout at /rest/api and the Cloud layout at /wiki/rest/api.
+type fakeConfluence struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	spaces      []space
+	pages       map[string]*fakePage
+	attachments map[string][]fakeAttachment
+	// calls counts requests per path (without the API prefix).
+	calls map[string]int
+	// cql records the last CQL query.
+	cql string
+}
+
+func newFakeConfluence(t *testing.T) *fakeConfluence {
+	t.Helper()
+	f := &fakeConfluence{
+		pages:       map[string]*fakePage{},
+		attachments: map[string][]fakeAttachment{},
+		calls:       map[string]int{},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeConfluence) addSpace(key, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.spaces = append(f.spaces, space{Key: key, Name: name, Type: "global",
+		Links: links{WebUI: "/spaces/" + key}})
+}
+
+func (f *fakeConfluence) putPage(p fakePage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cp := p
+	f.pages[p.ID] = &cp
+}
+
+func (f *fakeConfluence) deletePage(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.pages, id)
+	delete(f.attachments, id)
+}
+
+func (f *fakeConfluence) setAttachments(pageID string, atts ...fakeAttachment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attachments[pageID] = atts
+}
+
+func (f *fakeConfluence) callCount(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[path]
+}
+
+func (f *fakeConfluence) config(resourceIDs ...string) *types.DataSourceConfig {
+	return &types.DataSourceConfig{
+		Type: types.ConnectorTypeConfluence,
+		Credentials: map[string]interface{}{
+			"base_url":              f.server.URL,
+			"personal_access_token": fakePAT,
+		},
+		ResourceIDs: resourceIDs,
+	}
+}
+
+func (f *fakeConfluence) serve(w http.ResponseWriter, r *http.Request) {
+	path, cloud := strings.CutPrefix(r.URL.Path, "/wiki")
+	user, pass, basic := r.BasicAuth()
+	switch {
+	case cloud && !(basic && user == fakeEmail && pass == fakeAPIToken):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	case !cloud && r.Header.Get("Authorization") != "Bearer "+fakePAT:
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if rest, ok := strings.CutPrefix(path, "/download/attachments/"); ok {
+		f.handleDownload(w, rest)
+		return
+	}
+	path, ok := strings.CutPrefix(path, "/rest/api")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[path]++
+	q := r.URL.Query()
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "/space":
+		f.writeSpaces(w, q)
+	case len(parts) == 4 && parts[0] == "space" && parts[2] == "content" && parts[3] == "page":
+		var roots []content
+		for _, p := range f.sortedPages() {
+			if p.SpaceKey == parts[1] && p.ParentID == "" {
+				roots = append(roots, f.toContent(p, q.Get("expand")))
+			}
+		}
+		f.writeList(w, q, roots)
+	case path == "/content":
+		var all []content
+		for _, p := range f.sortedPages() {
+			if p.SpaceKey == q.Get("spaceKey") {
+				all = append(all, f.toContent(p, q.Get("expand")))
+			}
+		}
+		f.writeList(w, q, all)
+	case path == "/content/search":
+		f.cql = q.Get("cql")
+		// The fake ignores the date filter: the connector must cope with a
+		// window that overlaps attachments it already synced.
+		var atts []content
+		for _, p := range f.sortedPages() {
+			if !strings.Contains(f.cql, `space = "`+p.SpaceKey+`"`) {
+				continue
+			}
+			for _, a := range f.attachments[p.ID] {
+				c := f.attachmentContent(p.ID, a)
+				c.Container = &content{ID: p.ID, Type: "page"}
+				atts = append(atts, c)
+			}
+		}
+		f.writeList(w, q, atts)
+	case len(parts) == 2 && parts[0] == "content":
+		p, ok := f.pages[parts[1]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if p.Fails {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, f.toContent(p, q.Get("expand")))
+	case len(parts) == 4 && parts[0] == "content" && parts[2] == "child" && parts[3] == "page":
+		var children []content
+		for _, p := range f.sortedPages() {
+			if p.ParentID == parts[1] {
+				children = append(children, f.toContent(p, q.Get("expand")))
+			}
+		}
+		f.writeList(w, q, children)
+	case len(parts) == 4 && parts[0] == "content" && parts[2] == "descendant" && parts[3] == "page":
+		if _, ok := f.pages[parts[1]]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var out []content
+		for _, p := range f.sortedPages() {
+			if f.isDescendant(p, parts[1]) {
+				out = append(out, f.toContent(p, q.Get("expand")))
+			}
+		}
+		f.writeList(w, q, out)
+	case len(parts) == 4 && parts[0] == "content" && parts[2] == "child" && parts[3] == "attachment":
+		var out []content
+		for _, a := range f.attachments[parts[1]] {
+			out = append(out, f.attachmentContent(parts[1], a))
+		}
+		f.writeList(w, q, out)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeConfluence) handleDownload(w http.ResponseWriter, rest string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["download:"+rest]++
+	pageID, attID, _ := strings.Cut(rest, "/")
+	for _, a := range f.attachments[pageID] {
+		if a.ID != attID {
+			continue
+		}
+		if a.DownloadFails {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(a.Body))
+		return
+	}
+	http.Error(w, "missing", http.StatusNotFound)
+}
+
+func (f *fakeConfluence) sortedPages() []*fakePage {
+	out := make([]*fakePage, 0, len(f.pages))
+	for _, p := range f.pages {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (f *fakeConfluence) isDescendant(p *fakePage, ancestorID string) bool {
+	for parent := p.ParentID; parent != ""; {
+		if parent == ancestorID {
+			return true
+		}
+		next, ok := f.pages[parent]
+		if !ok {
+			return false
+		}
+		parent = next.ParentID
+	}
+	return false
+}
+
+func (f *fakeConfluence) toContent(p *fakePage, expand string) content {
+	c := content{
+		ID: p.ID, Type: "page", Status: "current", Title: p.Title,
+		Links: links{WebUI: "/pages/viewpage.action?pageId=" + p.ID},
+	}
+	if strings.Contains(expand, "version") {
+		c.Version = &version{Number: 1, When: p.When}
+	}
+	if strings.Contains(expand, "space") {
+		c.Space = &space{Key: p.SpaceKey, Name: "Space " + p.SpaceKey}
+	}
+	if strings.Contains(expand, "ancestors") {
+		var chain []content
+		for parent := p.ParentID; parent != ""; {
+			a, ok := f.pages[parent]
+			if !ok {
+				break
+			}
+			chain = append([]content{{ID: a.ID, Type: "page", Title: a.Title}}, chain...)
+			parent = a.ParentID
+		}
+		c.Ancestors = chain
+	}
+	if strings.Contains(expand, "body.export_view") {
+		c.Body.ExportView = &bodyValue{Value: p.Body}
+	}
+	if strings.Contains(expand, "children.page") {
+		n := 0
+		for _, other := range f.pages {
+			if other.ParentID == p.ID {
+				n++
+			}
+		}
+		c.Children.Page = &contentList{Size: n}
+	}
+	return c
+}
+
+func (f *fakeConfluence) attachmentContent(pageID string, a fakeAttachment) content {
+	c := content{
+		ID: a.ID, Type: "attachment", Status: "current", Title: a.Title,
+		Version: &version{Number: 1, When: a.When},
+		Links: links{
+			WebUI:    "/pages/viewpageattachments.action?pageId=" + pageID,
+			Download: "/download/attachments/" + pageID + "/" + a.ID,
+		},
+	}
+	c.Extensions.FileSize = int64(len(a.Body))
+	return c
+}
+
+func (f *fakeConfluence) writeSpaces(w http.ResponseWriter, q map[string][]string) {
+	start, limit := pageWindow(q)
+	end := min(start+limit, len(f.spaces))
+	resp := spaceList{Results: []space{}}
+	if start < len(f.spaces) {
+		resp.Results = f.spaces[start:end]
+	}
+	if end < len(f.spaces) {
+		resp.Links.Next = fmt.Sprintf("/rest/api/space?start=%d", end)
+	}
+	writeJSON(w, resp)
+}
+
+func (f *fakeConfluence) writeList(w http.ResponseWriter, q map[string][]string, all []content) {
+	start, limit := pageWindow(q)
+	end := min(start+limit, len(all))
+	resp := contentList{Results: []content{}}
+	if start < len(all) {
+		resp.Results = all[start:end]
+	}
+	resp.Size = len(resp.Results)
+	if end < len(all) {
+		resp.Links.Next = fmt.Sprintf("?start=%d", end)
+	}
+	writeJSON(w, resp)
+}
+
+func pageWindow(q map[string][]string) (int, int) {
+	get := func(k string) int {
+		if v, ok := q[k]; ok && len(v) > 0 {
+			n, _ := strconv.Atoi(v[0])
+			return n
+		}
+		return 0
+	}
+	limit := get("limit")
+	if limit <= 0 || limit > fakeMaxLimit {
+		limit = fakeMaxLimit
+	}
+	return get("start"), limit
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// decodeCursor converts the connector cursor back into its typed form.
+func decodeCursor(t *testing.T, cur *types.SyncCursor) *confluenceCursor {
+	t.Helper()
+	if cur == nil {
+		t.Fatal("cursor is nil")
+	}
+	out := decodeConfluenceCursor(cur)
+	if out == nil {
+		t.Fatalf("cursor does not decode: %#v", cur.ConnectorCursor)
+	}
+	return out
+}
+
+// findItem returns the fetched item with the given external id.
+func findItem(items []types.FetchedItem, externalID string) (types.FetchedItem, bool) {
+	for _, it := range items {
+		if it.ExternalID == externalID {
+			return it, true
+		}
+	}
+	return types.FetchedItem{}, false
+}
+
+func mustFindItem(t *testing.T, items []types.FetchedItem, externalID string) types.FetchedItem {
+	t.Helper()
+	it, ok := findItem(items, externalID)
+	if !ok {
+		t.Fatalf("item %s not found in %s", externalID, describeItems(items))
+	}
+	return it
+}
+
+func describeItems(items []types.FetchedItem) string {
+	var parts []string
+	for _, it := range items {
+		parts = append(parts, fmt.Sprintf("{id=%s title=%q deleted=%t}", it.ExternalID, it.Title, it.IsDeleted))
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,505 @@
+package confluence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+
+	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// Checkpoint tuning: a checkpoint is written every checkpointInterval
+// processed pages, or after checkpointMaxInterval when fetches are slow.
+// Variables so tests can tighten them.
+var (
+	checkpointInterval    = 50
+	checkpointMaxInterval = 30 * time.Second
+)
+
+// attachmentLookback widens the CQL attachment-change window. CQL compares
+// dates at minute precision in the server's time zone, which the client does
+// not know; the cursor's per-attachment versions filter out the overlap.
+const attachmentLookback = 24 * time.Hour
+
+// parseableAttachmentExts are attachment extensions worth ingesting as their
+// own knowledge entries; images and other files are left to the page body.
+var parseableAttachmentExts = map[string]bool{
+	".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true,
+	".ppt": true, ".pptx": true, ".txt": true, ".md": true, ".csv": true,
+}
+
+// syncRun is the state of one FetchStream / FetchAll / FetchIncremental call.
+//
+// Change detection compares each listed page's version.when with the cursor;
+// an unchanged page is skipped and keeps its cursor entry. A fetch failure
+// keeps the previous entry so the page is retried next run instead of being
+// recorded as synced. Deletions are detected per resource as pages recorded
+// last run but missing from this run's listing, and only when a cursor exists.
+type syncRun struct {
+	cli       *client
+	resources map[string]bool
+	h         datasource.StreamHandler
+
+	prev *confluenceCursor
+	next *confluenceCursor
+
+	// done marks resources whose listing has been fully processed.
+	done map[string]bool
+	// fetched marks pages already fetched this run, so a page selected both
+	// directly and through its space is only ingested once.
+	fetched map[string]bool
+	// attachmentChanges caches the CQL result per space key.
+	attachmentChanges map[string]map[string]bool
+
+	processed      int
+	lastCheckpoint time.Time
+}
+
+// runSync walks every resource and emits changed pages, their attachments,
+// error items and tombstones. prev == nil is a full sync.
+func runSync(
+	ctx context.Context, cli *client, resourceIDs []string,
+	prev *confluenceCursor, h datasource.StreamHandler,
+) (*confluenceCursor, error) {
+	r := &syncRun{
+		cli:       cli,
+		resources: make(map[string]bool, len(resourceIDs)),
+		h:         h,
+		prev:      prev,
+		next: &confluenceCursor{
+			LastSyncTime: time.Now().UTC(),
+			Pages:        make(map[string]map[string]string),
+			Attachments:  make(map[string]map[string]string),
+		},
+		done:              make(map[string]bool),
+		fetched:           make(map[string]bool),
+		attachmentChanges: make(map[string]map[string]bool),
+		lastCheckpoint:    time.Now(),
+	}
+	for _, id := range resourceIDs {
+		r.resources[id] = true
+	}
+	for _, resourceID := range resourceIDs {
+		if r.done[resourceID] {
+			continue
+		}
+		if err := r.syncResource(ctx, resourceID); err != nil {
+			return nil, err
+		}
+		r.done[resourceID] = true
+	}
+	return r.next, nil
+}
+
+func (r *syncRun) syncResource(ctx context.Context, resourceID string) error {
+	pages, spaceKey, err := r.listResourcePages(ctx, resourceID)
+	if err != nil {
+		return fmt.Errorf("list pages of resource %s: %w", resourceID, err)
+	}
+
+	var prevPages map[string]string
+	if r.prev != nil {
+		prevPages = r.prev.Pages[resourceID]
+	}
+	var changedAttachments map[string]bool
+	if prevPages != nil && spaceKey != "" {
+		if changedAttachments, err = r.changedAttachmentPages(ctx, spaceKey); err != nil {
+			return fmt.Errorf("search attachment changes in space %s: %w", spaceKey, err)
+		}
+	}
+
+	cur := make(map[string]string, len(pages))
+	r.next.Pages[resourceID] = cur
+	listed := make(map[string]bool, len(pages))
+	var fetchedCount, unchanged, failed, deleted int
+
+	for _, p := range pages {
+		listed[p.ID] = true
+		when := p.when()
+		prevWhen, hadPrev := prevPages[p.ID]
+
+		if r.fetched[p.ID] {
+			cur[p.ID] = when
+			continue
+		}
+		if hadPrev && prevWhen == when && !changedAttachments[p.ID] {
+			cur[p.ID] = when
+			r.carryAttachments(p.ID)
+			unchanged++
+			continue
+		}
+
+		items, versions, complete, ferr := r.fetchPage(ctx, resourceID, p.ID)
+		switch {
+		case errors.Is(ferr, errNotFound):
+			// Deleted between listing and fetching. Keep the old entry so the
+			// next listing sees it missing and tombstones it.
+			if hadPrev {
+				cur[p.ID] = prevWhen
+				r.carryAttachments(p.ID)
+			}
+		case ferr != nil:
+			failed++
+			// Do NOT advance the cursor: the content was never fetched.
+			if hadPrev {
+				cur[p.ID] = prevWhen
+				r.carryAttachments(p.ID)
+			}
+			logger.Warnf(ctx, "[Confluence] fetch page %s (%q) failed, will retry next sync: %v", p.ID, p.Title, ferr)
+			if err := r.h.Emit(ctx, types.FetchedItem{
+				ExternalID:       p.ID,
+				Title:            p.Title,
+				SourceResourceID: resourceID,
+				Metadata: map[string]string{
+					"channel": types.ChannelConfluence,
+					"page_id": p.ID,
+					"error":   ferr.Error(),
+				},
+			}); err != nil {
+				return err
+			}
+		default:
+			fetchedCount++
+			r.fetched[p.ID] = true
+			r.next.Attachments[p.ID] = versions
+			if complete {
+				cur[p.ID] = when
+			} else {
+				// An attachment failed: record the old (or no) edit time so
+				// the page and its attachments are fetched again next run.
+				cur[p.ID] = prevWhen
+			}
+			for _, it := range items {
+				if err := r.h.Emit(ctx, it); err != nil {
+					return err
+				}
+			}
+		}
+
+		r.processed++
+		if r.processed%checkpointInterval == 0 || time.Since(r.lastCheckpoint) >= checkpointMaxInterval {
+			if err := r.h.Checkpoint(ctx, r.snapshot().toSyncCursor()); err != nil {
+				logger.Warnf(ctx, "[Confluence] stream Checkpoint failed: %v", err)
+			}
+			r.lastCheckpoint = time.Now()
+		}
+	}
+
+	for id := range prevPages {
+		if listed[id] {
+			continue
+		}
+		deleted++
+		if err := r.h.Emit(ctx, types.FetchedItem{
+			ExternalID: id, IsDeleted: true, SourceResourceID: resourceID,
+		}); err != nil {
+			return err
+		}
+		for attID := range r.prev.Attachments[id] {
+			if err := r.h.Emit(ctx, types.FetchedItem{
+				ExternalID:       types.SubtreeChildID(id, "file", attID),
+				IsDeleted:        true,
+				SourceResourceID: resourceID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Infof(ctx, "[Confluence] resource=%s listed=%d fetched=%d unchanged=%d failed=%d deleted=%d",
+		resourceID, len(pages), fetchedCount, unchanged, failed, deleted)
+	return nil
+}
+
+// listResourcePages lists every page a resource covers, with versions, and
+// the key of the space it lives in. A space or root page that no longer
+// exists yields an empty listing, so its previously synced pages are
+// tombstoned.
+func (r *syncRun) listResourcePages(ctx context.Context, resourceID string) ([]content, string, error) {
+	if key, ok := strings.CutPrefix(resourceID, spaceResourcePrefix); ok {
+		pages, err := r.cli.ListSpacePages(ctx, key)
+		if errors.Is(err, errNotFound) {
+			logger.Warnf(ctx, "[Confluence] space %s not found, treating as empty", key)
+			return nil, key, nil
+		}
+		return pages, key, err
+	}
+
+	root, err := r.cli.GetContent(ctx, resourceID, "version,space")
+	if errors.Is(err, errNotFound) {
+		logger.Warnf(ctx, "[Confluence] page %s not found, treating as empty", resourceID)
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	descendants, err := r.cli.ListDescendantPages(ctx, resourceID)
+	if err != nil {
+		return nil, "", err
+	}
+	spaceKey := ""
+	if root.Space != nil {
+		spaceKey = root.Space.Key
+	}
+	return append([]content{*root}, descendants...), spaceKey, nil
+}
+
+// changedAttachmentPages returns the IDs of pages in a space with an
+// attachment added or replaced since the last sync that the cursor has not
+// recorded yet.
+func (r *syncRun) changedAttachmentPages(ctx context.Context, spaceKey string) (map[string]bool, error) {
+	if changed, ok := r.attachmentChanges[spaceKey]; ok {
+		return changed, nil
+	}
+	changed := make(map[string]bool)
+	if !r.prev.LastSyncTime.IsZero() {
+		atts, err := r.cli.SearchAttachmentsSince(ctx, spaceKey, r.prev.LastSyncTime.Add(-attachmentLookback))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range atts {
+			if a.Container == nil || a.Container.ID == "" {
+				continue
+			}
+			if r.prev.Attachments[a.Container.ID][a.ID] != a.when() {
+				changed[a.Container.ID] = true
+			}
+		}
+	}
+	r.attachmentChanges[spaceKey] = changed
+	return changed, nil
+}
+
+// carryAttachments keeps a skipped page's attachment versions in the cursor.
+func (r *syncRun) carryAttachments(pageID string) {
+	if r.prev == nil {
+		return
+	}
+	if atts, ok := r.prev.Attachments[pageID]; ok {
+		r.next.Attachments[pageID] = atts
+	}
+}
+
+// snapshot builds a checkpoint. It must be a complete resumable cursor, so
+// previous entries of resources and pages not processed yet are kept, and
+// LastSyncTime stays at the previous sync until the run completes so a
+// resumed run still looks for attachment changes since then.
+func (r *syncRun) snapshot() *confluenceCursor {
+	s := &confluenceCursor{
+		LastSyncTime: r.next.LastSyncTime,
+		Pages:        make(map[string]map[string]string),
+		Attachments:  make(map[string]map[string]string),
+	}
+	if r.prev != nil {
+		s.LastSyncTime = r.prev.LastSyncTime
+		for res, pages := range r.prev.Pages {
+			if r.resources[res] && !r.done[res] {
+				s.Pages[res] = maps.Clone(pages)
+			}
+		}
+		maps.Copy(s.Attachments, r.prev.Attachments)
+	}
+	for res, pages := range r.next.Pages {
+		if r.done[res] || s.Pages[res] == nil {
+			s.Pages[res] = pages
+			continue
+		}
+		maps.Copy(s.Pages[res], pages)
+	}
+	maps.Copy(s.Attachments, r.next.Attachments)
+	return s
+}
+
+// fetchPage fetches a page body and its attachments. It returns the items
+// to emit (the page first, then its attachments), the attachment versions
+// for the cursor, and whether every attachment was fetched.
+func (r *syncRun) fetchPage(
+	ctx context.Context, resourceID, pageID string,
+) ([]types.FetchedItem, map[string]string, bool, error) {
+	page, err := r.cli.GetContent(ctx, pageID, pageExpand)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	atts, err := r.cli.ListAttachments(ctx, pageID)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("list attachments: %w", err)
+	}
+
+	meta := pageMetadata(page)
+	main := types.FetchedItem{
+		ExternalID:       page.ID,
+		Title:            page.Title,
+		Content:          []byte(pageMarkdown(page)),
+		ContentType:      "text/markdown",
+		FileName:         sanitizeFileName(page.Title) + ".md",
+		URL:              r.cli.webURL(page.Links),
+		UpdatedAt:        parseWhen(page.when()),
+		SourceResourceID: resourceID,
+		Metadata:         meta,
+		ReplacesSubtree:  true, // sweep attachments removed from the page
+	}
+
+	versions := make(map[string]string, len(atts))
+	keep := make([]string, 0, len(atts))
+	var children []types.FetchedItem
+	complete := true
+	for _, a := range atts {
+		versions[a.ID] = a.when()
+		childID := types.SubtreeChildID(page.ID, "file", a.ID)
+		keep = append(keep, childID) // present on the page → never sweep as stale
+		if !parseableAttachmentExts[strings.ToLower(filepath.Ext(a.Title))] {
+			continue
+		}
+		if a.Extensions.FileSize > maxDownloadBytes {
+			logger.Infof(ctx, "[Confluence] page %s: skipping attachment %q (%d bytes > %d)",
+				page.ID, a.Title, a.Extensions.FileSize, maxDownloadBytes)
+			continue
+		}
+
+		childMeta := maps.Clone(meta)
+		childMeta["attachment"] = "true"
+		childMeta["attachment_id"] = a.ID
+		childMeta["parent_page_id"] = page.ID
+
+		data, ct, derr := r.cli.Download(ctx, a.Links.Download)
+		if derr != nil {
+			logger.Warnf(ctx, "[Confluence] page %s: attachment %q (%s) download failed: %v",
+				page.ID, a.Title, a.ID, derr)
+			complete = false
+			childMeta["error"] = derr.Error()
+			children = append(children, types.FetchedItem{
+				ExternalID:       childID,
+				Title:            a.Title,
+				SourceResourceID: resourceID,
+				Metadata:         childMeta,
+			})
+			continue
+		}
+		if a.Extensions.MediaType != "" {
+			ct = a.Extensions.MediaType
+		}
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		children = append(children, types.FetchedItem{
+			ExternalID:       childID,
+			Title:            a.Title,
+			Content:          data,
+			ContentType:      ct,
+			FileName:         sanitizeFileName(a.Title),
+			URL:              r.cli.webURL(a.Links),
+			UpdatedAt:        parseWhen(a.when()),
+			SourceResourceID: resourceID,
+			Metadata:         childMeta,
+		})
+	}
+	main.SubtreeKeep = keep
+	return append([]types.FetchedItem{main}, children...), versions, complete, nil
+}
+
+// pageMetadata builds the metadata map preserved on every ingested item.
+func pageMetadata(page *content) map[string]string {
+	m := map[string]string{
+		"channel": types.ChannelConfluence,
+		"page_id": page.ID,
+	}
+	if page.Space != nil {
+		m["space_key"] = page.Space.Key
+		m["space_name"] = page.Space.Name
+	}
+	if page.Version != nil {
+		m["version"] = strconv.Itoa(page.Version.Number)
+		if page.Version.By.DisplayName != "" {
+			m["last_modified_by"] = page.Version.By.DisplayName
+		}
+	}
+	if len(page.Ancestors) > 0 {
+		titles := make([]string, 0, len(page.Ancestors))
+		for _, a := range page.Ancestors {
+			titles = append(titles, a.Title)
+		}
+		m["page_path"] = strings.Join(titles, " / ")
+		m["parent_page_id"] = page.Ancestors[len(page.Ancestors)-1].ID
+	}
+	return m
+}
+
+// pageMarkdown renders a page as Markdown under its title. The rendered
+// export_view is preferred; the storage format is a fallback for servers
+// that do not expand it. A page whose HTML fails to convert keeps the raw
+// HTML rather than losing its text.
+func pageMarkdown(page *content) string {
+	html := ""
+	if page.Body.ExportView != nil {
+		html = page.Body.ExportView.Value
+	}
+	if strings.TrimSpace(html) == "" && page.Body.Storage != nil {
+		html = page.Body.Storage.Value
+	}
+
+	var b strings.Builder
+	b.WriteString("# ")
+	b.WriteString(page.Title)
+	b.WriteString("\n\n")
+	if strings.TrimSpace(html) == "" {
+		return b.String()
+	}
+	conv := converter.NewConverter(converter.WithPlugins(
+		base.NewBasePlugin(),
+		commonmark.NewCommonmarkPlugin(),
+		table.NewTablePlugin(),
+	))
+	md, err := conv.ConvertString(html)
+	if err != nil || strings.TrimSpace(md) == "" {
+		md = html
+	}
+	b.WriteString(strings.TrimSpace(md))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// parseWhen parses a version.when timestamp, falling back to now.
+func parseWhen(when string) time.Time {
+	if t, err := time.Parse(time.RFC3339, when); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// sanitizeFileName removes filesystem-hostile characters and truncates to a
+// safe UTF-8 boundary.
+func sanitizeFileName(name string) string {
+	if name == "" {
+		return "untitled"
+	}
+	replacer := strings.NewReplacer(
+		"/", "_", "\\", "_", ":", "_", "*", "_",
+		"?", "_", "\"", "_", "<", "_", ">", "_", "|", "_",
+	)
+	result := replacer.Replace(name)
+	const maxBytes = 200
+	if len(result) > maxBytes {
+		result = result[:maxBytes]
+		for len(result) > 0 {
+			r, size := utf8.DecodeLastRuneInString(result)
+			if r != utf8.RuneError || size != 1 {
+				break
+			}
+			result = result[:len(result)-1]
+		}
+	}
+	return result
+}
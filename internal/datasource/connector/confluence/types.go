@@ -0,0 +1,259 @@
+package confluence
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// Deployment flavours. They differ in where the REST API is mounted and in
+// how a request authenticates.
+const (
+	// DeploymentCloud is Confluence Cloud (*.atlassian.net): the API lives
+	// under /wiki/rest/api and requests use Basic auth with an Atlassian
+	// account email and API token.
+	DeploymentCloud = "cloud"
+	// DeploymentDataCenter is a self-hosted Confluence Data Center / Server:
+	// the API lives under <base>/rest/api (base may carry a context path such
+	// as /confluence) and requests use a personal access token as Bearer, or
+	// Basic auth with username and password.
+	DeploymentDataCenter = "datacenter"
+)
+
+// spaceResourcePrefix marks a resource ID that selects a whole space. Any
+// other resource ID is a page ID and selects that page and its descendants.
+const spaceResourcePrefix = "space:"
+
+// Config holds Confluence-specific configuration decoded from
+// DataSourceConfig.Credentials. Secrets are stored encrypted at rest by
+// DataSourceConfig.ToJSON (see internal/types/datasource.go).
+type Config struct {
+	// BaseURL is the site URL, e.g. https://acme.atlassian.net or
+	// https://wiki.example.com/confluence. Required.
+	BaseURL string `json:"base_url"`
+
+	// Deployment is "cloud" or "datacenter". Empty infers it from the host:
+	// *.atlassian.net is Cloud, anything else is Data Center.
+	Deployment string `json:"deployment,omitempty"`
+
+	// Email and APIToken authenticate with Basic auth. On Data Center they
+	// are a username and password.
+	Email    string `json:"email,omitempty"`
+	APIToken string `json:"api_token,omitempty"`
+
+	// PersonalAccessToken authenticates with Bearer auth (Data Center 7.9+).
+	// It takes precedence over Email/APIToken when both are set.
+	PersonalAccessToken string `json:"personal_access_token,omitempty"`
+}
+
+// GetBaseURL returns the normalized base URL (scheme added, no trailing slash).
+// A Cloud URL pasted with its /wiki suffix is trimmed back to the site root.
+func (c *Config) GetBaseURL() string {
+	u := strings.TrimSpace(c.BaseURL)
+	if u == "" {
+		return ""
+	}
+	if !strings.Contains(u, "://") {
+		u = "https://" + u
+	}
+	u = strings.TrimRight(u, "/")
+	if c.GetDeployment() == DeploymentCloud {
+		u = strings.TrimSuffix(u, "/wiki")
+	}
+	return u
+}
+
+// GetDeployment returns the configured deployment, inferring it from the
+// host when unset.
+func (c *Config) GetDeployment() string {
+	switch strings.ToLower(strings.TrimSpace(c.Deployment)) {
+	case DeploymentCloud:
+		return DeploymentCloud
+	case DeploymentDataCenter, "server", "dc":
+		return DeploymentDataCenter
+	}
+	raw := strings.TrimSpace(c.BaseURL)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	if u, err := url.Parse(raw); err == nil && strings.HasSuffix(strings.ToLower(u.Hostname()), ".atlassian.net") {
+		return DeploymentCloud
+	}
+	return DeploymentDataCenter
+}
+
+// siteURL is the root that web UI and download links are relative to.
+func (c *Config) siteURL() string {
+	if c.GetDeployment() == DeploymentCloud {
+		return c.GetBaseURL() + "/wiki"
+	}
+	return c.GetBaseURL()
+}
+
+// apiURL is the REST API root.
+func (c *Config) apiURL() string {
+	return c.siteURL() + "/rest/api"
+}
+
+// parseConfluenceConfig extracts and validates Confluence-specific configuration.
+// Uses JSON marshal/unmarshal roundtrip so extra fields are ignored gracefully.
+func parseConfluenceConfig(config *types.DataSourceConfig) (*Config, error) {
+	if config == nil {
+		return nil, fmt.Errorf("%w: config is nil", datasource.ErrInvalidConfig)
+	}
+	credBytes, err := json.Marshal(config.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("marshal credentials: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(credBytes, &cfg); err != nil {
+		return nil, fmt.Errorf("parse confluence credentials: %w", err)
+	}
+	if cfg.GetBaseURL() == "" {
+		return nil, fmt.Errorf("%w: base_url is required", datasource.ErrInvalidConfig)
+	}
+	switch d := strings.TrimSpace(cfg.Deployment); d {
+	case "", DeploymentCloud, DeploymentDataCenter, "server", "dc":
+	default:
+		return nil, fmt.Errorf("%w: unknown deployment %q", datasource.ErrInvalidConfig, d)
+	}
+	hasPAT := strings.TrimSpace(cfg.PersonalAccessToken) != ""
+	hasBasic := strings.TrimSpace(cfg.Email) != "" && strings.TrimSpace(cfg.APIToken) != ""
+	if cfg.GetDeployment() == DeploymentCloud && !hasBasic {
+		return nil, fmt.Errorf("%w: email and api_token are required for Confluence Cloud",
+			datasource.ErrInvalidCredentials)
+	}
+	if !hasPAT && !hasBasic {
+		return nil, fmt.Errorf("%w: personal_access_token (or email and api_token) is required",
+			datasource.ErrInvalidCredentials)
+	}
+	if err := datasource.ValidateConnectorBaseURL(cfg.GetBaseURL()); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// links mirrors the `_links` object. Paths are relative to the site URL.
+type links struct {
+	WebUI    string `json:"webui"`
+	Download string `json:"download"`
+	Next     string `json:"next"`
+}
+
+// space mirrors a Confluence space (GET /space).
+type space struct {
+	ID          int64  `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description struct {
+		Plain struct {
+			Value string `json:"value"`
+		} `json:"plain"`
+	} `json:"description"`
+	Links links `json:"_links"`
+}
+
+// spaceList is a page of spaces.
+type spaceList struct {
+	Results []space `json:"results"`
+	Links   links   `json:"_links"`
+}
+
+// version is the version block of a content entity. When is the
+// last-modified time and is what the sync cursor records.
+type version struct {
+	Number int    `json:"number"`
+	When   string `json:"when"`
+	By     struct {
+		DisplayName string `json:"displayName"`
+	} `json:"by"`
+}
+
+// bodyValue is one representation of a page body.
+type bodyValue struct {
+	Value string `json:"value"`
+}
+
+// content mirrors a Confluence content entity (page or attachment). Only the
+// fields the connector reads are decoded; which ones are populated depends on
+// the request's expand parameter.
+type content struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	Title     string    `json:"title"`
+	Space     *space    `json:"space,omitempty"`
+	Version   *version  `json:"version,omitempty"`
+	Ancestors []content `json:"ancestors,omitempty"`
+	Container *content  `json:"container,omitempty"`
+	Body      struct {
+		ExportView *bodyValue `json:"export_view,omitempty"`
+		Storage    *bodyValue `json:"storage,omitempty"`
+	} `json:"body"`
+	Children struct {
+		Page *contentList `json:"page,omitempty"`
+	} `json:"children"`
+	Extensions struct {
+		MediaType string `json:"mediaType"`
+		FileSize  int64  `json:"fileSize"`
+	} `json:"extensions"`
+	Links links `json:"_links"`
+}
+
+// when returns the last-modified time string, "" when not expanded.
+func (c content) when() string {
+	if c.Version == nil {
+		return ""
+	}
+	return c.Version.When
+}
+
+// contentList is a page of content entities.
+type contentList struct {
+	Results []content `json:"results"`
+	Size    int       `json:"size"`
+	Links   links     `json:"_links"`
+}
+
+// confluenceCursor is the persisted incremental-sync state.
+type confluenceCursor struct {
+	LastSyncTime time.Time `json:"last_sync_time"`
+	// Pages maps resource ID → page ID → version.when of the last successful
+	// fetch. It drives both change detection and deletion detection.
+	Pages map[string]map[string]string `json:"pages"`
+	// Attachments maps page ID → attachment ID → version.when, so attachment
+	// changes reported by CQL can be told apart from ones already synced.
+	Attachments map[string]map[string]string `json:"attachments,omitempty"`
+}
+
+// toSyncCursor wraps the cursor into its wire form. The JSON roundtrip gives
+// the returned cursor its own copy of the maps.
+func (c *confluenceCursor) toSyncCursor() *types.SyncCursor {
+	cursorMap := make(map[string]interface{})
+	b, _ := json.Marshal(c)
+	_ = json.Unmarshal(b, &cursorMap)
+	return &types.SyncCursor{
+		LastSyncTime:    c.LastSyncTime,
+		ConnectorCursor: cursorMap,
+	}
+}
+
+// decodeConfluenceCursor reads the typed cursor from a persisted SyncCursor.
+// It returns nil for a nil or empty cursor, which means a full sync.
+func decodeConfluenceCursor(cursor *types.SyncCursor) *confluenceCursor {
+	if cursor == nil || cursor.ConnectorCursor == nil {
+		return nil
+	}
+	var c confluenceCursor
+	b, _ := json.Marshal(cursor.ConnectorCursor)
+	if err := json.Unmarshal(b, &c); err != nil || c.Pages == nil {
+		return nil
+	}
+	return &c
+}
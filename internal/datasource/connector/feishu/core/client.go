@@ -15,6 +15,7 @@ import (
 
 	"github.com/Tencent/WeKnora/internal/datasource"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
 )
 
 // Client wraps the Feishu Open Platform API for document/wiki operations.
@@ -32,6 +33,12 @@ type Client struct {
 	tokenMu    sync.Mutex
 	tokenCache string
 	tokenExpAt time.Time
+
+	// Permission sync caches, per client (i.e. per sync run): user emails by
+	// "<member_type>:<member_id>" and wiki space readers by space ID.
+	permMu       sync.Mutex
+	userEmails   map[string]string
+	spaceReaders map[string]*types.ItemPermissions
 }
 
 type WikiNodeListFailure struct {
@@ -25,6 +25,11 @@ import (
 //     skipped on a transient export failure (Tencent/WeKnora#2136). This now
 //     also holds for the FetchIncremental path (previously it advanced the
 //     cursor before fetching, a latent #2136 bug).
+//   - With permission sync on, every supported node's permissions are read
+//     on every run: an unchanged node whose permissions moved is emitted as a
+//     PermissionsOnly item, and a fetched node carries its permissions. A
+//     failed permission read keeps the previous fingerprint and leaves the
+//     synced access list as it was.
 //   - Logs use the "stream progress/summary" wording uniformly; the
 //     FetchIncremental path additionally gains per-100 progress + tally
 //     summary logs it did not Emit before (log-only change).
@@ -48,6 +53,10 @@ type NodeOps[N any] interface {
 	// type that yields no item.
 	Fetch(ctx context.Context, client *Client, n N, resourceID string, multimodal bool) ([]*types.FetchedItem, error)
 
+	// Permissions reads who may view the node in Feishu. Only called when
+	// permission sync is on.
+	Permissions(ctx context.Context, client *Client, n N) (*types.ItemPermissions, error)
+
 	// ListFailureItems converts a partial-listing error into error FetchedItems.
 	ListFailureItems(resourceID string, partial error) []types.FetchedItem
 	// ResourceNoun is the noun in user-visible error text: "nodes" / "files".
@@ -99,6 +108,24 @@ func runSync[N any](
 
 	newTimes := make(map[string]map[string]string)
 	lastSync := time.Now()
+	tracker := datasource.NewPermissionTracker(cursor, config.SyncPermissions)
+	readPermissions := func(node N, tok string) *types.ItemPermissions {
+		if !tracker.Enabled() || !IsSupportedDocType(ops.ObjType(node)) {
+			return nil
+		}
+		perms, err := ops.Permissions(ctx, client, node)
+		if err != nil {
+			logger.Warnf(ctx, "%s read permissions of %s failed, keeping the synced ones: %v", ops.LogTag(), tok, err)
+			tracker.Keep(tok)
+			return nil
+		}
+		return perms
+	}
+	encodeCursor := func(final bool) *types.SyncCursor {
+		next := ops.EncodeCursor(newTimes, lastSync)
+		tracker.Save(next, final)
+		return next
+	}
 
 	processed := 0
 	lastCheckpoint := time.Now()
@@ -146,12 +173,24 @@ func runSync[N any](
 			// and Skip re-fetching.
 			if hadPrev && prevEdit == editTimeStr {
 				newTimes[resourceID][tok] = editTimeStr
+				if perms := readPermissions(node, tok); tracker.Record(tok, perms) {
+					if eerr := h.Emit(ctx, types.FetchedItem{
+						ExternalID:       tok,
+						Title:            ops.Title(node),
+						SourceResourceID: resourceID,
+						Permissions:      perms,
+						PermissionsOnly:  true,
+					}); eerr != nil {
+						return nil, eerr
+					}
+				}
 				continue
 			}
 
 			items, ferr := ops.Fetch(ctx, client, node, resourceID, config.MultimodalEnabled)
 			if ferr != nil {
 				tally.fail()
+				tracker.Keep(tok)
 				// Do NOT advance the cursor: the content was never fetched.
 				// Retain the prior edit time (if any) so prev != current next
 				// run and the node is retried, instead of being permanently
@@ -173,7 +212,10 @@ func runSync[N any](
 				newTimes[resourceID][tok] = editTimeStr
 				if len(items) > 0 {
 					tally.fetch()
+					perms := readPermissions(node, tok)
+					tracker.Record(tok, perms)
 					for _, it := range items {
+						it.Permissions = perms
 						if eerr := h.Emit(ctx, *it); eerr != nil {
 							return nil, eerr
 						}
@@ -186,7 +228,7 @@ func runSync[N any](
 
 			processed++
 			if processed%FeishuStreamCheckpointInterval == 0 || time.Since(lastCheckpoint) >= FeishuStreamCheckpointMaxInterval {
-				if cerr := h.Checkpoint(ctx, ops.EncodeCursor(newTimes, lastSync)); cerr != nil {
+				if cerr := h.Checkpoint(ctx, encodeCursor(false)); cerr != nil {
 					logger.Warnf(ctx, "%s stream Checkpoint failed: %v", ops.LogTag(), cerr)
 				}
 				lastCheckpoint = time.Now()
@@ -218,7 +260,7 @@ func runSync[N any](
 		logger.Infof(ctx, "%s stream summary resource=%s %s", ops.LogTag(), resourceID, tally.summary())
 	}
 
-	return ops.EncodeCursor(newTimes, lastSync), nil
+	return encodeCursor(true), nil
 }
 
 // FetchStreamEngine runs the streaming sync. FetchStream / FetchIncremental
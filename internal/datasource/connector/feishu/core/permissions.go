@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// permissions.go reads who may view a document for permission sync. A
+// document shared by link with the whole tenant (or anyone) is public; any
+// other document is readable by its collaborators and, for wiki nodes, by the
+// members of its space. Users are reported with their email so the service
+// can map them to WeKnora accounts; chats, departments and user groups cannot
+// be expanded with the app's scopes and are reported as source groups, which
+// keep the document restricted without granting anyone.
+//
+// Scopes: drive:drive (or docs:permission.member:readonly) for collaborators,
+// wiki:wiki:readonly for space members, contact:user.email:readonly for emails.
+
+// publicLinkShareEntities are the link share settings that let every member
+// of the tenant read a document.
+var publicLinkShareEntities = map[string]bool{
+	"tenant_readable": true,
+	"tenant_editable": true,
+	"anyone_readable": true,
+	"anyone_editable": true,
+}
+
+// DocPermissions returns who may read a Drive document or file.
+func (c *Client) DocPermissions(ctx context.Context, token, objType string) (*types.ItemPermissions, error) {
+	public, err := c.docIsPublic(ctx, token, objType)
+	if err != nil {
+		return nil, err
+	}
+	if public {
+		return &types.ItemPermissions{Public: true}, nil
+	}
+	members, err := c.docMembers(ctx, token, objType)
+	if err != nil {
+		return nil, err
+	}
+	perms := &types.ItemPermissions{}
+	for _, m := range members {
+		perms.Principals = append(perms.Principals, c.sourcePrincipal(ctx, m))
+	}
+	return perms, nil
+}
+
+// WikiNodePermissions returns who may read a wiki node: everyone for a node
+// of a public space or shared with the tenant, else the members of its space
+// plus the collaborators of its document.
+func (c *Client) WikiNodePermissions(ctx context.Context, node WikiNode) (*types.ItemPermissions, error) {
+	space, err := c.wikiSpaceReaders(ctx, node.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if space.Public {
+		return space, nil
+	}
+	doc, err := c.DocPermissions(ctx, node.ObjToken, node.ObjType)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Public {
+		return doc, nil
+	}
+	perms := &types.ItemPermissions{}
+	perms.Principals = append(perms.Principals, space.Principals...)
+	perms.Principals = append(perms.Principals, doc.Principals...)
+	return perms, nil
+}
+
+func (c *Client) docIsPublic(ctx context.Context, token, objType string) (bool, error) {
+	path := fmt.Sprintf("/open-apis/drive/v1/permissions/%s/public?type=%s",
+		url.PathEscape(token), url.QueryEscape(objType))
+	var resp permissionPublicResponse
+	if err := c.DoRequest(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return false, fmt.Errorf("get public permission: %w", err)
+	}
+	if resp.Code != 0 {
+		return false, fmt.Errorf("get public permission error: code=%d msg=%s", resp.Code, resp.Msg)
+	}
+	return publicLinkShareEntities[resp.Data.PermissionPublic.LinkShareEntity], nil
+}
+
+func (c *Client) docMembers(ctx context.Context, token, objType string) ([]PermissionMember, error) {
+	path := fmt.Sprintf("/open-apis/drive/v1/permissions/%s/members?type=%s&fields=name",
+		url.PathEscape(token), url.QueryEscape(objType))
+	var resp permissionMembersResponse
+	if err := c.DoRequest(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("list collaborators: %w", err)
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("list collaborators error: code=%d msg=%s", resp.Code, resp.Msg)
+	}
+	return resp.Data.Items, nil
+}
+
+// wikiSpaceReaders returns the readers a wiki space grants to all of its
+// nodes, cached per client since every node of the space shares them.
+func (c *Client) wikiSpaceReaders(ctx context.Context, spaceID string) (*types.ItemPermissions, error) {
+	c.permMu.Lock()
+	cached, ok := c.spaceReaders[spaceID]
+	c.permMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var info wikiSpaceInfoResponse
+	path := fmt.Sprintf("/open-apis/wiki/v2/spaces/%s", url.PathEscape(spaceID))
+	if err := c.DoRequest(ctx, http.MethodGet, path, nil, &info); err != nil {
+		return nil, fmt.Errorf("get wiki space: %w", err)
+	}
+	if info.Code != 0 {
+		return nil, fmt.Errorf("get wiki space error: code=%d msg=%s", info.Code, info.Msg)
+	}
+
+	readers := &types.ItemPermissions{Public: info.Data.Space.Visibility == "public"}
+	if !readers.Public {
+		pageToken := ""
+		for {
+			path := fmt.Sprintf("/open-apis/wiki/v2/spaces/%s/members?page_size=50", url.PathEscape(spaceID))
+			if pageToken != "" {
+				path += "&page_token=" + url.QueryEscape(pageToken)
+			}
+			var resp wikiSpaceMembersResponse
+			if err := c.DoRequest(ctx, http.MethodGet, path, nil, &resp); err != nil {
+				return nil, fmt.Errorf("list wiki space members: %w", err)
+			}
+			if resp.Code != 0 {
+				return nil, fmt.Errorf("list wiki space members error: code=%d msg=%s", resp.Code, resp.Msg)
+			}
+			for _, m := range resp.Data.Members {
+				readers.Principals = append(readers.Principals, c.sourcePrincipal(ctx, m))
+			}
+			if !resp.Data.HasMore || resp.Data.PageToken == "" {
+				break
+			}
+			pageToken = resp.Data.PageToken
+		}
+	}
+
+	c.permMu.Lock()
+	if c.spaceReaders == nil {
+		c.spaceReaders = make(map[string]*types.ItemPermissions)
+	}
+	c.spaceReaders[spaceID] = readers
+	c.permMu.Unlock()
+	return readers, nil
+}
+
+// userIDTypes maps the member types that identify a single user to the
+// user_id_type of the contact API.
+var userIDTypes = map[string]string{
+	"openid":  "open_id",
+	"unionid": "union_id",
+	"userid":  "user_id",
+}
+
+// sourcePrincipal converts a collaborator or space member, looking up the
+// email of users. A failed lookup leaves the email empty: the user then
+// restricts the document without being mapped to an account.
+func (c *Client) sourcePrincipal(ctx context.Context, m PermissionMember) types.SourcePrincipal {
+	if m.MemberType == "email" {
+		return types.SourcePrincipal{Type: types.SourcePrincipalUser, ID: m.MemberID, Email: m.MemberID, Name: m.Name}
+	}
+	idType, isUser := userIDTypes[m.MemberType]
+	if !isUser {
+		return types.SourcePrincipal{Type: types.SourcePrincipalGroup, ID: m.MemberType + ":" + m.MemberID, Name: m.Name}
+	}
+	return types.SourcePrincipal{
+		Type:  types.SourcePrincipalUser,
+		ID:    m.MemberID,
+		Email: c.userEmail(ctx, idType, m.MemberID),
+		Name:  m.Name,
+	}
+}
+
+func (c *Client) userEmail(ctx context.Context, idType, id string) string {
+	key := idType + ":" + id
+	c.permMu.Lock()
+	email, ok := c.userEmails[key]
+	c.permMu.Unlock()
+	if ok {
+		return email
+	}
+
+	var resp contactUserResponse
+	path := fmt.Sprintf("/open-apis/contact/v3/users/%s?user_id_type=%s", url.PathEscape(id), idType)
+	if err := c.DoRequest(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		logger.Warnf(ctx, "[Feishu] get user %s for permission sync: %v", key, err)
+	} else if resp.Code != 0 {
+		logger.Warnf(ctx, "[Feishu] get user %s for permission sync: code=%d msg=%s", key, resp.Code, resp.Msg)
+	} else {
+		email = resp.Data.User.EnterpriseEmail
+		if email == "" {
+			email = resp.Data.User.Email
+		}
+		email = strings.TrimSpace(email)
+	}
+
+	c.permMu.Lock()
+	if c.userEmails == nil {
+		c.userEmails = make(map[string]string)
+	}
+	c.userEmails[key] = email
+	c.permMu.Unlock()
+	return email
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestWikiNodePermissions_PrivateSpaceJoinsSpaceMembersAndCollaborators(t *testing.T) {
+	ts, cfg := retryTestServer("/open-apis/wiki/v2/spaces/sp1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"code": 0, "data": map[string]interface{}{
+			"space": WikiSpace{SpaceID: "sp1", Visibility: "private"},
+		}})
+	})
+	defer ts.Close()
+	mux := ts.Config.Handler.(*http.ServeMux)
+	mux.HandleFunc("/open-apis/wiki/v2/spaces/sp1/members", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, wikiSpaceMembersResponse{Data: wikiSpaceMembersData{Members: []PermissionMember{
+			{MemberType: "openchat", MemberID: "oc_1"},
+		}}})
+	})
+	mux.HandleFunc("/open-apis/drive/v1/permissions/doc1/public", func(w http.ResponseWriter, r *http.Request) {
+		resp := permissionPublicResponse{}
+		resp.Data.PermissionPublic.LinkShareEntity = "closed"
+		writeJSON(w, resp)
+	})
+	mux.HandleFunc("/open-apis/drive/v1/permissions/doc1/members", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, permissionMembersResponse{Data: permissionMembersData{Items: []PermissionMember{
+			{MemberType: "openid", MemberID: "ou_1", Name: "Alice"},
+			{MemberType: "email", MemberID: "bob@example.com"},
+		}}})
+	})
+	var userLookups int
+	mux.HandleFunc("/open-apis/contact/v3/users/ou_1", func(w http.ResponseWriter, r *http.Request) {
+		userLookups++
+		resp := contactUserResponse{}
+		resp.Data.User.EnterpriseEmail = "alice@corp.example.com"
+		writeJSON(w, resp)
+	})
+
+	c := NewClient(cfg)
+	node := WikiNode{SpaceID: "sp1", ObjToken: "doc1", ObjType: "docx"}
+	for i := 0; i < 2; i++ {
+		perms, err := c.WikiNodePermissions(context.Background(), node)
+		if err != nil {
+			t.Fatalf("WikiNodePermissions: %v", err)
+		}
+		want := []types.SourcePrincipal{
+			{Type: types.SourcePrincipalGroup, ID: "openchat:oc_1"},
+			{Type: types.SourcePrincipalUser, ID: "ou_1", Email: "alice@corp.example.com", Name: "Alice"},
+			{Type: types.SourcePrincipalUser, ID: "bob@example.com", Email: "bob@example.com"},
+		}
+		if perms.Public || len(perms.Principals) != len(want) {
+			t.Fatalf("permissions = %+v", perms)
+		}
+		for j := range want {
+			if perms.Principals[j] != want[j] {
+				t.Errorf("principal %d = %+v, want %+v", j, perms.Principals[j], want[j])
+			}
+		}
+	}
+	if userLookups != 1 {
+		t.Errorf("user lookups = %d, want 1 (emails are cached)", userLookups)
+	}
+}
+
+func TestDocPermissions_TenantLinkIsPublic(t *testing.T) {
+	ts, cfg := retryTestServer("/open-apis/drive/v1/permissions/doc1/public", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "file" {
+			t.Errorf("type = %q, want file", r.URL.Query().Get("type"))
+		}
+		resp := permissionPublicResponse{}
+		resp.Data.PermissionPublic.LinkShareEntity = "tenant_readable"
+		writeJSON(w, resp)
+	})
+	defer ts.Close()
+
+	perms, err := NewClient(cfg).DocPermissions(context.Background(), "doc1", "file")
+	if err != nil {
+		t.Fatalf("DocPermissions: %v", err)
+	}
+	if !perms.Public || len(perms.Principals) != 0 {
+		t.Fatalf("permissions = %+v, want public", perms)
+	}
+}
@@ -357,3 +357,77 @@ type FeishuDriveCursor struct {
 	// Used to detect which files have changed since last sync.
 	FileTimes map[string]map[string]string `json:"file_times,omitempty"`
 }
+
+// --- Permission API responses (permission sync) ---
+
+// permissionPublicData is the data payload of permissionPublicResponse.
+type permissionPublicData struct {
+	PermissionPublic struct {
+		// LinkShareEntity is who the share link opens the document to:
+		// tenant_readable / tenant_editable / anyone_readable /
+		// anyone_editable / closed (and partner_tenant_* variants).
+		LinkShareEntity string `json:"link_share_entity"`
+	} `json:"permission_public"`
+}
+
+// permissionPublicResponse is the response for GET /open-apis/drive/v1/permissions/:token/public.
+type permissionPublicResponse struct {
+	ApiResponse
+	Data permissionPublicData `json:"data"`
+}
+
+// PermissionMember is a collaborator of a document or a member of a wiki space.
+type PermissionMember struct {
+	// MemberType is how MemberID is expressed: email / openid / unionid /
+	// userid for users; openchat / opendepartmentid / groupid / wikispaceid
+	// for groups of users.
+	MemberType string `json:"member_type"`
+	MemberID   string `json:"member_id"`
+	Perm       string `json:"perm"`
+	MemberRole string `json:"member_role"`
+	Name       string `json:"name"`
+}
+
+// permissionMembersData is the data payload of permissionMembersResponse.
+type permissionMembersData struct {
+	Items []PermissionMember `json:"items"`
+}
+
+// permissionMembersResponse is the response for GET /open-apis/drive/v1/permissions/:token/members.
+type permissionMembersResponse struct {
+	ApiResponse
+	Data permissionMembersData `json:"data"`
+}
+
+// wikiSpaceMembersData is the data payload of wikiSpaceMembersResponse.
+type wikiSpaceMembersData struct {
+	Members   []PermissionMember `json:"members"`
+	HasMore   bool               `json:"has_more"`
+	PageToken string             `json:"page_token"`
+}
+
+// wikiSpaceMembersResponse is the response for GET /open-apis/wiki/v2/spaces/:space_id/members.
+type wikiSpaceMembersResponse struct {
+	ApiResponse
+	Data wikiSpaceMembersData `json:"data"`
+}
+
+// wikiSpaceInfoResponse is the response for GET /open-apis/wiki/v2/spaces/:space_id.
+type wikiSpaceInfoResponse struct {
+	ApiResponse
+	Data struct {
+		Space WikiSpace `json:"space"`
+	} `json:"data"`
+}
+
+// contactUserResponse is the response for GET /open-apis/contact/v3/users/:user_id.
+type contactUserResponse struct {
+	ApiResponse
+	Data struct {
+		User struct {
+			Name            string `json:"name"`
+			Email           string `json:"email"`
+			EnterpriseEmail string `json:"enterprise_email"`
+		} `json:"user"`
+	} `json:"data"`
+}
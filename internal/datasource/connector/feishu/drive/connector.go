@@ -310,6 +310,10 @@ func (o driveOps) Fetch(ctx context.Context, client *core.Client, n core.DriveFi
 	return fetchDriveFileContent(ctx, client, n, resourceID, multimodal, o.region)
 }
 
+func (o driveOps) Permissions(ctx context.Context, client *core.Client, n core.DriveFile) (*types.ItemPermissions, error) {
+	return client.DocPermissions(ctx, n.Token, n.Type)
+}
+
 func (o driveOps) ListFailureItems(resourceID string, partial error) []types.FetchedItem {
 	var pe *core.PartialDriveFileListError
 	if errors.As(partial, &pe) {
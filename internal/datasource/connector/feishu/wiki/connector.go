@@ -257,6 +257,10 @@ func (o wikiOps) Fetch(ctx context.Context, client *core.Client, n core.WikiNode
 	return fetchNodeContent(ctx, client, n, spaceID, resourceID, multimodal, o.region)
 }
 
+func (o wikiOps) Permissions(ctx context.Context, client *core.Client, n core.WikiNode) (*types.ItemPermissions, error) {
+	return client.WikiNodePermissions(ctx, n)
+}
+
 func (o wikiOps) ListFailureItems(resourceID string, partial error) []types.FetchedItem {
 	spaceID, _ := parseWikiResourceID(resourceID)
 	var pe *core.PartialWikiNodeListError
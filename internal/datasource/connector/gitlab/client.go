@@ -35,10 +35,22 @@ type project struct {
 	Name              string `json:"name"`
 	WebURL            string `json:"web_url"`
 	DefaultBranch     string `json:"default_branch"`
+	Visibility        string `json:"visibility"` // public, internal (any signed-in user) or private
 	Namespace         struct {
 		ID int64 `json:"id"`
 	} `json:"namespace"`
 }
+type member struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	AccessLevel int    `json:"access_level"`
+	// Email is only returned to administrators; PublicEmail is what the user
+	// chose to show on their profile
+	Email       string `json:"email"`
+	PublicEmail string `json:"public_email"`
+}
 type treeEntry struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -207,6 +219,26 @@ func (c *client) getTreePage(ctx context.Context, endpoint string, query url.Val
 	}
 	return resp.Header.Get("X-Next-Page"), nil
 }
+
+// members lists the direct and inherited members of a project.
+func (c *client) members(ctx context.Context, id string) ([]member, error) {
+	q := url.Values{"per_page": {"100"}, "page": {"1"}}
+	endpoint := "/projects/" + projectPath(id) + "/members/all"
+	var all []member
+	for {
+		var page []member
+		nextPage, err := c.getTreePage(ctx, endpoint, q, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if nextPage == "" {
+			return all, nil
+		}
+		q.Set("page", nextPage)
+	}
+}
+
 func (c *client) raw(ctx context.Context, id, ref, file string) ([]byte, error) {
 	q := url.Values{"ref": {ref}}
 	encodedFile := gitlabFilePathEscape(file)
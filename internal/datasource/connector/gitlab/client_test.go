@@ -297,6 +297,80 @@ func TestFetchStreamFiltersUnsupportedFilesAndCheckpointsProjects(t *testing.T)
 	}
 }
 
+func TestFetchStreamReportsProjectPermissions(t *testing.T) {
+	allowLocalGitLabServer(t)
+
+	members := `[
+		{"id":1,"username":"alice","name":"Alice","state":"active","access_level":30,"email":"alice@example.com"},
+		{"id":2,"username":"guest","name":"Guest","state":"active","access_level":10},
+		{"id":3,"username":"gone","name":"Gone","state":"blocked","access_level":40}
+	]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v4/projects/1":
+			_, _ = w.Write([]byte(`{"id":1,"name":"docs","path_with_namespace":"group/docs","default_branch":"main","visibility":"private"}`))
+		case "/api/v4/projects/1/repository/commits/main":
+			_, _ = w.Write([]byte(`{"id":"commit-1"}`))
+		case "/api/v4/projects/1/members/all":
+			_, _ = w.Write([]byte(members))
+		case "/api/v4/projects/1/repository/tree":
+			_, _ = w.Write([]byte(`[{"name":"README.md","type":"blob","path":"README.md"}]`))
+		default:
+			if strings.Contains(r.URL.EscapedPath(), "/repository/files/") {
+				_, _ = w.Write([]byte("# readme"))
+				return
+			}
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := &types.DataSourceConfig{
+		Credentials: map[string]interface{}{"base_url": server.URL, "access_token": "token"},
+		Settings: map[string]interface{}{
+			"projects": []interface{}{map[string]interface{}{"project_id": "1"}},
+		},
+		SyncPermissions: true,
+	}
+	first := &gitLabStreamRecorder{}
+	next, err := NewConnector().FetchStream(context.Background(), config, nil, first)
+	if err != nil {
+		t.Fatalf("FetchStream() error = %v", err)
+	}
+	if len(first.items) != 1 || first.items[0].Permissions == nil {
+		t.Fatalf("emitted items = %#v", first.items)
+	}
+	perms := first.items[0].Permissions
+	if perms.Public || len(perms.Principals) != 1 || perms.Principals[0].Email != "alice@example.com" {
+		t.Fatalf("permissions = %#v, want only the active reporter alice", perms)
+	}
+
+	// Same commit, same members: nothing to report.
+	unchanged := &gitLabStreamRecorder{}
+	if next, err = NewConnector().FetchStream(context.Background(), config, next, unchanged); err != nil {
+		t.Fatalf("FetchStream() error = %v", err)
+	}
+	if len(unchanged.items) != 0 {
+		t.Fatalf("emitted items = %#v, want none", unchanged.items)
+	}
+
+	// Same commit, new member: the file is re-reported for its permissions only.
+	members = `[
+		{"id":1,"username":"alice","state":"active","access_level":30,"email":"alice@example.com"},
+		{"id":4,"username":"bob","state":"active","access_level":20,"public_email":"bob@example.com"}
+	]`
+	changed := &gitLabStreamRecorder{}
+	if _, err = NewConnector().FetchStream(context.Background(), config, next, changed); err != nil {
+		t.Fatalf("FetchStream() error = %v", err)
+	}
+	if len(changed.items) != 1 || !changed.items[0].PermissionsOnly ||
+		changed.items[0].ExternalID != first.items[0].ExternalID ||
+		len(changed.items[0].Permissions.Principals) != 2 {
+		t.Fatalf("emitted items = %#v, want one permissions-only README.md", changed.items)
+	}
+}
+
 func TestIsSupportedFile(t *testing.T) {
 	for _, tc := range []struct {
 		file string
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/datasource"
+	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
 )
 
@@ -248,6 +249,7 @@ func (c *Connector) FetchStream(
 	for projectID, commit := range prev.Projects {
 		next.Projects[projectID] = commit
 	}
+	tracker := datasource.NewPermissionTracker(old, ds.SyncPermissions)
 
 	for _, selection := range cfg.Projects {
 		project, err := c.client.project(ctx, selection.ProjectID)
@@ -263,12 +265,31 @@ func (c *Connector) FetchStream(
 			return nil, err
 		}
 
+		// Every file of a project shares the project's permissions, so they
+		// are read and fingerprinted once per project.
+		var perms *types.ItemPermissions
+		permsChanged := false
+		if tracker.Enabled() {
+			key := "project:" + selection.ProjectID
+			if perms, err = c.projectPermissions(ctx, project); err != nil {
+				logger.Warnf(ctx, "[GitLab] read permissions of project %s failed, keeping the synced ones: %v",
+					selection.ProjectID, err)
+				tracker.Keep(key)
+				perms = nil
+			} else {
+				permsChanged = tracker.Record(key, perms)
+			}
+		}
+
 		previous := prev.Projects[selection.ProjectID]
 		switch {
 		case previous == "":
-			err = c.streamFiles(ctx, project, ref, selection.Paths, h)
+			err = c.streamFiles(ctx, project, ref, selection.Paths, perms, h)
 		case previous != head:
-			err = c.streamChanges(ctx, project, ref, previous, head, selection.Paths, h)
+			err = c.streamChanges(ctx, project, ref, previous, head, selection.Paths, perms, h)
+		}
+		if err == nil && previous != "" && permsChanged {
+			err = c.streamPermissions(ctx, project, ref, selection.Paths, perms, h)
 		}
 		if err != nil {
 			return nil, err
@@ -276,11 +297,65 @@ func (c *Connector) FetchStream(
 
 		next.Projects[selection.ProjectID] = head
 		checkpoint := gitLabCursor(next)
+		tracker.Save(checkpoint, false)
 		if err := h.Checkpoint(ctx, checkpoint); err != nil {
 			return nil, err
 		}
 	}
-	return gitLabCursor(next), nil
+	final := gitLabCursor(next)
+	tracker.Save(final, true)
+	return final, nil
+}
+
+// reporterAccessLevel is the lowest project role that can read the
+// repository of a private project; Guests cannot.
+const reporterAccessLevel = 20
+
+// projectPermissions returns who may read a project's files. Public and
+// internal projects are readable by everyone signed in to GitLab; a private
+// project by its active members with at least the Reporter role.
+func (c *Connector) projectPermissions(ctx context.Context, p *project) (*types.ItemPermissions, error) {
+	if p.Visibility == "public" || p.Visibility == "internal" {
+		return &types.ItemPermissions{Public: true}, nil
+	}
+	members, err := c.client.members(ctx, fmt.Sprint(p.ID))
+	if err != nil {
+		return nil, fmt.Errorf("gitlab project members %d: %w", p.ID, err)
+	}
+	perms := &types.ItemPermissions{}
+	for _, m := range members {
+		if m.AccessLevel < reporterAccessLevel || (m.State != "" && m.State != "active") {
+			continue
+		}
+		email := m.Email
+		if email == "" {
+			email = m.PublicEmail
+		}
+		perms.Principals = append(perms.Principals, types.SourcePrincipal{
+			Type:  types.SourcePrincipalUser,
+			ID:    m.Username,
+			Email: email,
+			Name:  m.Name,
+		})
+	}
+	return perms, nil
+}
+
+// streamPermissions emits a PermissionsOnly item for every file in scope, for
+// a project whose permissions changed while its files did not.
+func (c *Connector) streamPermissions(
+	ctx context.Context, project *project, ref string, roots []string,
+	perms *types.ItemPermissions, h datasource.StreamHandler,
+) error {
+	return c.walkFiles(ctx, fmt.Sprint(project.ID), ref, roots, func(file string) error {
+		return h.Emit(ctx, types.FetchedItem{
+			ExternalID:       c.externalID(project, ref, file),
+			Title:            project.PathWithNamespace + "/" + file,
+			SourceResourceID: fmt.Sprint(project.ID),
+			Permissions:      perms,
+			PermissionsOnly:  true,
+		})
+	})
 }
 
 func gitLabCursor(value cursor) *types.SyncCursor {
@@ -292,13 +367,14 @@ func gitLabCursor(value cursor) *types.SyncCursor {
 }
 
 func (c *Connector) streamChanges(
-	ctx context.Context, project *project, ref, from, to string, roots []string, h datasource.StreamHandler,
+	ctx context.Context, project *project, ref, from, to string, roots []string,
+	perms *types.ItemPermissions, h datasource.StreamHandler,
 ) error {
 	diff, err := c.client.compare(ctx, fmt.Sprint(project.ID), from, to)
 	if err != nil || diff.CompareTimeout {
 		// A compare can be unavailable after history rewrites or be truncated by
 		// GitLab. Re-enumerating the configured scope preserves file updates.
-		return c.streamFiles(ctx, project, ref, roots, h)
+		return c.streamFiles(ctx, project, ref, roots, perms, h)
 	}
 	for _, change := range diff.Diffs {
 		if change.DeletedFile {
@@ -319,6 +395,7 @@ func (c *Connector) streamChanges(
 			if err != nil {
 				return err
 			}
+			item.Permissions = perms
 			if err := h.Emit(ctx, item); err != nil {
 				return err
 			}
@@ -328,13 +405,15 @@ func (c *Connector) streamChanges(
 }
 
 func (c *Connector) streamFiles(
-	ctx context.Context, project *project, ref string, roots []string, h datasource.StreamHandler,
+	ctx context.Context, project *project, ref string, roots []string,
+	perms *types.ItemPermissions, h datasource.StreamHandler,
 ) error {
 	return c.walkFiles(ctx, fmt.Sprint(project.ID), ref, roots, func(file string) error {
 		item, err := c.item(ctx, project, ref, file)
 		if err != nil {
 			return err
 		}
+		item.Permissions = perms
 		return h.Emit(ctx, item)
 	})
 }
@@ -403,7 +482,7 @@ func (c *Connector) item(ctx context.Context, p *project, ref, file string) (typ
 	if err != nil {
 		return types.FetchedItem{}, err
 	}
-	id := fmt.Sprintf("gitlab:%s:%d:%s:%s", c.canonicalBase, p.ID, ref, file)
+	id := c.externalID(p, ref, file)
 	return types.FetchedItem{ExternalID: id, Title: p.PathWithNamespace + "/" + file, FileName: knowledgeRelativePath(p.Name, ref, file), Content: body, ContentType: "text/plain", UpdatedAt: time.Now().UTC(), SourceResourceID: fmt.Sprint(p.ID), Metadata: map[string]string{"channel": types.ConnectorTypeGitLab, "source_type": "gitlab", "gitlab_project_id": fmt.Sprint(p.ID), "gitlab_ref": ref, "gitlab_path": file, "gitlab_url": p.WebURL + "/-/blob/" + ref + "/" + file}}, nil
 }
 
@@ -413,8 +492,11 @@ func knowledgeRelativePath(projectName, ref, file string) string {
 	root := strings.TrimSpace(projectName) + "-" + strings.ReplaceAll(strings.TrimSpace(ref), "/", "-")
 	return path.Join(root, file)
 }
+func (c *Connector) externalID(p *project, ref, file string) string {
+	return fmt.Sprintf("gitlab:%s:%d:%s:%s", c.canonicalBase, p.ID, ref, file)
+}
 func (c *Connector) deleted(p *project, ref, file string) types.FetchedItem {
-	return types.FetchedItem{ExternalID: fmt.Sprintf("gitlab:%s:%d:%s:%s", c.canonicalBase, p.ID, ref, file), IsDeleted: true, Metadata: map[string]string{"channel": types.ConnectorTypeGitLab, "gitlab_path": file}}
+	return types.FetchedItem{ExternalID: c.externalID(p, ref, file), IsDeleted: true, Metadata: map[string]string{"channel": types.ConnectorTypeGitLab, "gitlab_path": file}}
 }
 func (c *Connector) inScope(file string, roots []string) bool {
 	if len(roots) == 0 {
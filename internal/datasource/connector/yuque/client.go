@@ -261,6 +261,39 @@ func (c *client) listReposPaginated(ctx context.Context, basePath string) ([]v2R
 	return all, nil
 }
 
+// GetRepo fetches a book's detail, including its visibility and owner.
+func (c *client) GetRepo(ctx context.Context, bookID int64) (v2RepoDetail, error) {
+	path := fmt.Sprintf("/api/v2/repos/%d", bookID)
+	var resp v2RepoDetailResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, &resp); err != nil {
+		return v2RepoDetail{}, err
+	}
+	return resp.Data, nil
+}
+
+// ListGroupUsers lists the members of a group, handling pagination.
+func (c *client) ListGroupUsers(ctx context.Context, login string) ([]v2GroupUser, error) {
+	basePath := fmt.Sprintf("/api/v2/groups/%s/users", login)
+	var all []v2GroupUser
+	offset := 0
+	for {
+		q := buildQuery(map[string]string{
+			"offset": fmt.Sprintf("%d", offset),
+			"limit":  fmt.Sprintf("%d", defaultPageSize),
+		})
+		var resp v2GroupUserListResponse
+		if err := c.doRequest(ctx, http.MethodGet, basePath+q, &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Data...)
+		if len(resp.Data) < defaultPageSize {
+			break
+		}
+		offset += defaultPageSize
+	}
+	return all, nil
+}
+
 // ListBookDocs lists all documents in a book, handling pagination.
 // Returns summaries only (body not included — use GetDocDetail to fetch body per doc).
 func (c *client) ListBookDocs(ctx context.Context, bookID int64) ([]v2Doc, error) {
@@ -141,10 +141,44 @@ func (c *Connector) ListResources(
 
 // FetchAll performs a full sync of all books specified in resourceIDs.
 func (c *Connector) FetchAll(ctx context.Context, config *types.DataSourceConfig, resourceIDs []string) ([]types.FetchedItem, error) {
-	items, _, err := c.walk(ctx, config, resourceIDs, nil, false)
+	items, _, err := c.walk(ctx, config, resourceIDs, nil, false, nil)
 	return items, err
 }
 
+// bookPermissions returns who may read the docs of a book. Public and
+// internal books are readable by everyone in the space; a private group book
+// by the group's members, a private personal book by its owner. Yuque does
+// not expose member emails, so readers are reported by login only: they keep
+// the docs restricted and access is granted through the manual access list.
+func bookPermissions(ctx context.Context, cli *client, bookID int64) (*types.ItemPermissions, error) {
+	repo, err := cli.GetRepo(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Public != 0 {
+		return &types.ItemPermissions{Public: true}, nil
+	}
+	perms := &types.ItemPermissions{}
+	if repo.User.Type != "Group" {
+		if repo.User.Login != "" {
+			perms.Principals = append(perms.Principals, types.SourcePrincipal{
+				Type: types.SourcePrincipalUser, ID: repo.User.Login, Name: repo.User.Name,
+			})
+		}
+		return perms, nil
+	}
+	members, err := cli.ListGroupUsers(ctx, repo.User.Login)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		perms.Principals = append(perms.Principals, types.SourcePrincipal{
+			Type: types.SourcePrincipalUser, ID: m.User.Login, Name: m.User.Name,
+		})
+	}
+	return perms, nil
+}
+
 // walk is the shared implementation for FetchAll / FetchIncremental.
 // If incremental is false, prev is ignored and no cursor is returned (returns nil for cursor).
 func (c *Connector) walk(
@@ -153,6 +187,7 @@ func (c *Connector) walk(
 	resourceIDs []string,
 	prev *yuqueCursor,
 	incremental bool,
+	tracker *datasource.PermissionTracker,
 ) ([]types.FetchedItem, *yuqueCursor, error) {
 	cfg, err := parseYuqueConfig(config)
 	if err != nil {
@@ -174,6 +209,21 @@ func (c *Connector) walk(
 			return nil, nil, fmt.Errorf("list docs for book %d: %w", bookID, err)
 		}
 
+		// All docs of a book share the book's permissions. A book whose
+		// permissions changed re-reports them for its unchanged docs too.
+		var perms *types.ItemPermissions
+		permsChanged := false
+		if config.SyncPermissions {
+			key := "book:" + bookIDStr
+			if perms, err = bookPermissions(ctx, cli, bookID); err != nil {
+				logger.Warnf(ctx, "[Yuque] read permissions of book %d failed, keeping the synced ones: %v", bookID, err)
+				tracker.Keep(key)
+				perms = nil
+			} else {
+				permsChanged = tracker.Record(key, perms)
+			}
+		}
+
 		currentDocs := make(map[string]bool)
 		newCursor.BookDocTimes[bookIDStr] = make(map[string]string)
 
@@ -205,6 +255,15 @@ func (c *Connector) walk(
 			if incremental && prev != nil && prev.BookDocTimes != nil {
 				if prevTimes, ok := prev.BookDocTimes[bookIDStr]; ok {
 					if prevTimes[docIDStr] == d.ContentUpdatedAt {
+						if permsChanged {
+							out = append(out, types.FetchedItem{
+								ExternalID:       docIDStr,
+								Title:            d.Title,
+								SourceResourceID: bookIDStr,
+								Permissions:      perms,
+								PermissionsOnly:  true,
+							})
+						}
 						continue
 					}
 				}
@@ -266,6 +325,7 @@ func (c *Connector) walk(
 				URL:              buildDocURL(cfg.GetBaseURL(), detail.Book.Namespace, d.Slug),
 				UpdatedAt:        parseContentUpdatedAt(d.ContentUpdatedAt),
 				SourceResourceID: bookIDStr,
+				Permissions:      perms,
 				Metadata: map[string]string{
 					"doc_id":     docIDStr,
 					"book_id":    bookIDStr,
@@ -333,7 +393,8 @@ func (c *Connector) FetchIncremental(
 		prev = &p
 	}
 
-	items, newCursor, err := c.walk(ctx, config, resourceIDs, prev, true)
+	tracker := datasource.NewPermissionTracker(cursor, config.SyncPermissions)
+	items, newCursor, err := c.walk(ctx, config, resourceIDs, prev, true, tracker)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -343,8 +404,10 @@ func (c *Connector) FetchIncremental(
 	b, _ := json.Marshal(newCursor)
 	_ = json.Unmarshal(b, &cursorMap)
 
-	return items, &types.SyncCursor{
+	next := &types.SyncCursor{
 		LastSyncTime:    newCursor.LastSyncTime,
 		ConnectorCursor: cursorMap,
-	}, nil
+	}
+	tracker.Save(next, true)
+	return items, next, nil
 }
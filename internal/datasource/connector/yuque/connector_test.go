@@ -2,6 +2,7 @@ package yuque
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -481,3 +482,41 @@ func TestConnector_ListResources_ContinuesOnGroupFailure(t *testing.T) {
 		t.Errorf("expected Mine + OK Book, got %v", names)
 	}
 }
+
+func TestConnector_FetchIncremental_ReportsBookPermissions(t *testing.T) {
+	f := newFakeYuque()
+	defer f.Close()
+	f.handleJSON("/api/v2/repos/20/docs", 200, v2DocListResponse{Data: []v2Doc{
+		{ID: 1, Type: "Doc", Status: "1", Title: "A", Slug: "a", ContentUpdatedAt: "2026-04-20T10:00:00Z"},
+	}})
+	f.handleJSON("/api/v2/repos/docs/1", 200, v2DocDetailResponse{Data: v2DocDetail{ID: 1, Title: "A", Body: "a", Status: "1"}})
+	book := v2RepoDetail{ID: 20, Public: 0, User: v2User{Type: "Group", Login: "team"}}
+	f.mux.HandleFunc("/api/v2/repos/20", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(v2RepoDetailResponse{Data: book})
+	})
+	f.handleJSON("/api/v2/groups/team/users", 200, v2GroupUserListResponse{Data: []v2GroupUser{
+		{User: v2User{Login: "alice", Name: "Alice"}},
+	}})
+	config := makeDSConfig(f, []string{"20"})
+	config.SyncPermissions = true
+
+	items, cursor, err := NewConnector().FetchIncremental(context.Background(), config, nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if len(items) != 1 || items[0].Permissions == nil || items[0].Permissions.Public ||
+		len(items[0].Permissions.Principals) != 1 || items[0].Permissions.Principals[0].ID != "alice" {
+		t.Fatalf("items = %+v, want doc 1 restricted to the group members", items)
+	}
+
+	// The book is made public without touching the doc: the doc is reported
+	// again for its permissions only.
+	book.Public = 1
+	items, _, err = NewConnector().FetchIncremental(context.Background(), config, cursor)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(items) != 1 || !items[0].PermissionsOnly || !items[0].Permissions.Public {
+		t.Fatalf("items = %+v, want one permissions-only public item", items)
+	}
+}
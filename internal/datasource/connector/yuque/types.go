@@ -164,6 +164,30 @@ type v2Repo struct {
 	UpdatedAt   string `json:"updated_at"` // RFC3339 string
 }
 
+// v2RepoDetailResponse wraps GET /api/v2/repos/{book_id}.
+type v2RepoDetailResponse struct {
+	Data v2RepoDetail `json:"data"`
+}
+
+type v2RepoDetail struct {
+	ID        int64  `json:"id"`
+	Namespace string `json:"namespace"`
+	Public    int    `json:"public"` // 0:private, 1:public, 2:internal
+	User      v2User `json:"user"`   // owner: a user or a group
+}
+
+// v2GroupUserListResponse wraps GET /api/v2/groups/{login}/users.
+type v2GroupUserListResponse struct {
+	Data []v2GroupUser `json:"data"`
+}
+
+type v2GroupUser struct {
+	ID     int64  `json:"id"`
+	Role   int    `json:"role"` // 0:owner, 1:member, 2:read-only
+	UserID int64  `json:"user_id"`
+	User   v2User `json:"user"`
+}
+
 // v2DocListResponse wraps GET /api/v2/repos/{book_id}/docs.
 type v2DocListResponse struct {
 	Meta struct {
@@ -0,0 +1,75 @@
+package datasource
+
+import "github.com/Tencent/WeKnora/internal/types"
+
+// PermissionTracker detects permission changes of items whose content did
+// not change. Connectors that report types.FetchedItem.Permissions record
+// the permissions of every item they visit; an item whose fingerprint moved
+// since the previous sync is re-emitted as a PermissionsOnly item, and the
+// fingerprints are saved in the cursor under
+// types.PermissionFingerprintsCursorKey for the next sync.
+//
+// A nil tracker, returned when permission sync is off, records nothing and
+// reports no change, so connectors can call it unconditionally.
+type PermissionTracker struct {
+	prev map[string]string
+	next map[string]string
+}
+
+// NewPermissionTracker starts tracking from the fingerprints stored in
+// cursor. It returns nil when enabled is false.
+func NewPermissionTracker(cursor *types.SyncCursor, enabled bool) *PermissionTracker {
+	if !enabled {
+		return nil
+	}
+	return &PermissionTracker{prev: cursor.PermissionFingerprints(), next: map[string]string{}}
+}
+
+// Enabled reports whether permissions are tracked, i.e. whether the
+// connector should read them from the source at all
+func (t *PermissionTracker) Enabled() bool {
+	return t != nil
+}
+
+// Record stores the fingerprint of an item's permissions and reports whether
+// it differs from the previous sync. An item without a recorded fingerprint
+// counts as changed, so enabling permission sync reports every item once.
+func (t *PermissionTracker) Record(key string, permissions *types.ItemPermissions) bool {
+	if t == nil || permissions == nil {
+		return false
+	}
+	fingerprint := permissions.Fingerprint()
+	t.next[key] = fingerprint
+	return t.prev[key] != fingerprint
+}
+
+// Keep carries the previous fingerprint of an item over, for items whose
+// permissions could not be read this sync
+func (t *PermissionTracker) Keep(key string) {
+	if t == nil {
+		return
+	}
+	if fingerprint, ok := t.prev[key]; ok {
+		t.next[key] = fingerprint
+	}
+}
+
+// Save stores the fingerprints in cursor. A checkpoint (final == false) keeps
+// the previous fingerprints of items not visited yet, so a sync resumed from
+// it does not report them again; the final cursor only keeps the items this
+// sync visited, dropping those that disappeared from the source.
+func (t *PermissionTracker) Save(cursor *types.SyncCursor, final bool) {
+	if t == nil || cursor == nil {
+		return
+	}
+	fingerprints := make(map[string]string, len(t.next))
+	if !final {
+		for key, fingerprint := range t.prev {
+			fingerprints[key] = fingerprint
+		}
+	}
+	for key, fingerprint := range t.next {
+		fingerprints[key] = fingerprint
+	}
+	cursor.SetPermissionFingerprints(fingerprints)
+}
@@ -0,0 +1,72 @@
+package datasource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestPermissionTrackerReportsChangesAcrossSyncs(t *testing.T) {
+	alice := &types.ItemPermissions{Principals: []types.SourcePrincipal{
+		{Type: types.SourcePrincipalUser, ID: "alice", Email: "alice@example.com"},
+	}}
+	aliceAgain := &types.ItemPermissions{Principals: []types.SourcePrincipal{
+		{Type: types.SourcePrincipalUser, ID: "alice", Email: "ALICE@example.com"},
+	}}
+	public := &types.ItemPermissions{Public: true}
+
+	first := NewPermissionTracker(nil, true)
+	if !first.Record("a", alice) || !first.Record("b", public) {
+		t.Fatal("items without a previous fingerprint must count as changed")
+	}
+	cursor := &types.SyncCursor{ConnectorCursor: map[string]interface{}{"other": "kept"}}
+	first.Save(cursor, true)
+
+	// The cursor round-trips through JSON between syncs.
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored types.SyncCursor
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewPermissionTracker(&stored, true)
+	if second.Record("a", aliceAgain) {
+		t.Fatal("email case must not change the fingerprint")
+	}
+	if !second.Record("b", alice) {
+		t.Fatal("a public item turned private must count as changed")
+	}
+	checkpoint := &types.SyncCursor{}
+	second.Save(checkpoint, false)
+	if len(checkpoint.PermissionFingerprints()) != 2 {
+		t.Fatalf("checkpoint fingerprints = %v", checkpoint.PermissionFingerprints())
+	}
+
+	third := NewPermissionTracker(&stored, true)
+	third.Keep("a")
+	final := &types.SyncCursor{}
+	third.Save(final, true)
+	if got := final.PermissionFingerprints(); len(got) != 1 || got["a"] == "" {
+		t.Fatalf("final fingerprints = %v, want only the kept item", got)
+	}
+	if stored.ConnectorCursor["other"] != "kept" {
+		t.Fatalf("connector cursor = %v", stored.ConnectorCursor)
+	}
+}
+
+func TestPermissionTrackerDisabledIsNoop(t *testing.T) {
+	tracker := NewPermissionTracker(nil, false)
+	if tracker.Enabled() || tracker.Record("a", &types.ItemPermissions{Public: true}) {
+		t.Fatal("a disabled tracker must not report changes")
+	}
+	cursor := &types.SyncCursor{}
+	tracker.Keep("a")
+	tracker.Save(cursor, true)
+	if cursor.ConnectorCursor != nil {
+		t.Fatalf("cursor = %v, want untouched", cursor.ConnectorCursor)
+	}
+}
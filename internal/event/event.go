@@ -79,6 +79,18 @@ const (
 
 	// Control events
 	EventStop EventType = "stop" // 停止对话生成
+
+	// Workspace events, published on the global bus for background listeners
+	// such as agent triggers and tenant webhooks
+	EventKnowledgeCreated       EventType = "knowledge.created"        // 知识创建
+	EventKnowledgeIngested      EventType = "knowledge.ingested"       // 知识处理完成
+	EventKnowledgeFailed        EventType = "knowledge.failed"         // 知识处理失败
+	EventKnowledgeDeleted       EventType = "knowledge.deleted"        // 知识删除
+	EventKnowledgeBaseChanged   EventType = "knowledge_base.changed"   // 知识库创建/更新/删除
+	EventDataSourceSyncFinished EventType = "datasource.sync_finished" // 数据源同步结束
+	EventWikiIssueFlagged       EventType = "wiki.issue_flagged"       // Wiki 页面问题标记
+	EventWikiPageRevised        EventType = "wiki.page_revised"        // Wiki 页面产生新版本
+	EventEvaluationCompleted    EventType = "evaluation.completed"     // 评测结束
 )
 
 // Event represents an event in the system
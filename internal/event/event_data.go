@@ -272,3 +272,94 @@ type MCPOAuthResolvedData struct {
 	TimedOut   bool   `json:"timed_out,omitempty"`
 	Canceled   bool   `json:"canceled,omitempty"`
 }
+
+// KnowledgeIngestedData is published when a knowledge item finishes
+// processing, including its enrichment subtasks
+type KnowledgeIngestedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	KnowledgeID     string `json:"knowledge_id"`
+	Title           string `json:"title"`
+	Channel         string `json:"channel,omitempty"`
+}
+
+// DataSourceSyncFinishedData is published when a datasource sync ends, with
+// status success, partial or failed
+type DataSourceSyncFinishedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	DataSourceID    string `json:"data_source_id"`
+	DataSourceName  string `json:"data_source_name"`
+	SyncLogID       string `json:"sync_log_id"`
+	Status          string `json:"status"`
+	ItemsCreated    int    `json:"items_created"`
+	ItemsUpdated    int    `json:"items_updated"`
+	ItemsDeleted    int    `json:"items_deleted"`
+	ItemsFailed     int    `json:"items_failed"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+}
+
+// WikiIssueFlaggedData is published when an issue is flagged on a wiki page
+type WikiIssueFlaggedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	IssueID         string `json:"issue_id"`
+	Slug            string `json:"slug"`
+	IssueType       string `json:"issue_type"`
+	Description     string `json:"description"`
+	ReportedBy      string `json:"reported_by,omitempty"`
+}
+
+// KnowledgeLifecycleData is published when a knowledge item is created,
+// fails processing or is deleted; the event type tells which
+type KnowledgeLifecycleData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	KnowledgeID     string `json:"knowledge_id"`
+	Title           string `json:"title"`
+	Type            string `json:"type"`
+	FileName        string `json:"file_name,omitempty"`
+	FileType        string `json:"file_type,omitempty"`
+	Source          string `json:"source,omitempty"`
+	Channel         string `json:"channel,omitempty"`
+	ParseStatus     string `json:"parse_status"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+}
+
+// KnowledgeBaseChangedData is published when a knowledge base is created,
+// updated or deleted
+type KnowledgeBaseChangedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	// Action is created, updated or deleted
+	Action string `json:"action"`
+}
+
+// WikiPageRevisedData is published when a wiki page gets a new version
+type WikiPageRevisedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	PageID          string `json:"page_id"`
+	Slug            string `json:"slug"`
+	Title           string `json:"title"`
+	PageType        string `json:"page_type"`
+	Version         int    `json:"version"`
+	EditSource      string `json:"edit_source,omitempty"`
+}
+
+// EvaluationCompletedData is published when an evaluation run ends, with
+// status success or failed
+type EvaluationCompletedData struct {
+	TenantID        uint64 `json:"tenant_id"`
+	TaskID          string `json:"task_id"`
+	DatasetID       string `json:"dataset_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id,omitempty"`
+	Status          string `json:"status"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+	Total           int    `json:"total"`
+	Finished        int    `json:"finished"`
+	// Metric is the aggregated metric of a successful run
+	Metric any `json:"metric,omitempty"`
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AgentTriggerHandler manages the scheduled and event-driven triggers of an
+// agent
+type AgentTriggerHandler struct {
+	service interfaces.AgentTriggerService
+}
+
+// NewAgentTriggerHandler creates a new AgentTriggerHandler instance
+func NewAgentTriggerHandler(service interfaces.AgentTriggerService) *AgentTriggerHandler {
+	return &AgentTriggerHandler{service: service}
+}
+
+// agentTriggerRequest is the body of create and update. Enabled defaults to
+// true; a nil webhook_secret keeps the stored secret on update and an empty
+// one clears it.
+type agentTriggerRequest struct {
+	Name             string                      `json:"name"`
+	Enabled          *bool                       `json:"enabled"`
+	TriggerType      types.AgentTriggerType      `json:"trigger_type"`
+	Schedule         string                      `json:"schedule"`
+	EventType        types.AgentTriggerEventType `json:"event_type"`
+	KnowledgeBaseIDs []string                    `json:"knowledge_base_ids"`
+	CooldownSeconds  int                         `json:"cooldown_seconds"`
+	Prompt           string                      `json:"prompt"`
+	Delivery         agentTriggerDeliveryRequest `json:"delivery"`
+}
+
+type agentTriggerDeliveryRequest struct {
+	types.AgentTriggerDelivery
+	WebhookSecret *string `json:"webhook_secret"`
+}
+
+func (r *agentTriggerRequest) toTrigger() *types.AgentTrigger {
+	trigger := &types.AgentTrigger{
+		Name:             r.Name,
+		Enabled:          r.Enabled == nil || *r.Enabled,
+		TriggerType:      r.TriggerType,
+		Schedule:         r.Schedule,
+		EventType:        r.EventType,
+		KnowledgeBaseIDs: types.StringArray(r.KnowledgeBaseIDs),
+		CooldownSeconds:  r.CooldownSeconds,
+		Prompt:           r.Prompt,
+		Delivery:         r.Delivery.AgentTriggerDelivery,
+	}
+	if r.Delivery.WebhookSecret != nil {
+		trigger.Delivery.WebhookSecret = *r.Delivery.WebhookSecret
+	}
+	return trigger
+}
+
+// respondAgentTriggerError passes user-facing AppErrors through and wraps the rest
+func respondAgentTriggerError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// ListTriggers godoc
+// @Summary      获取智能体的触发器
+// @Description  列出智能体的定时与事件触发器，webhook 密钥只以 has_webhook_secret 标记返回
+// @Tags         智能体
+// @Produce      json
+// @Param        id   path      string  true  "智能体ID"
+// @Success      200  {object}  map[string]interface{}  "触发器列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers [get]
+func (h *AgentTriggerHandler) ListTriggers(c *gin.Context) {
+	triggers, err := h.service.ListTriggers(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    triggers,
+	})
+}
+
+// CreateTrigger godoc
+// @Summary      创建智能体触发器
+// @Description  按 cron 表达式（含秒）定时运行智能体，或在知识入库、数据源同步结束、Wiki 问题标记时运行，并把回答投递到 IM、webhook 或知识库
+// @Tags         智能体
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "智能体ID"
+// @Param        request  body      map[string]interface{}  true  "触发器配置"
+// @Success      201      {object}  map[string]interface{}  "创建的触发器"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers [post]
+func (h *AgentTriggerHandler) CreateTrigger(c *gin.Context) {
+	var req agentTriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	trigger, err := h.service.CreateTrigger(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), req.toTrigger())
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    trigger,
+	})
+}
+
+// GetTrigger godoc
+// @Summary      获取智能体触发器详情
+// @Description  获取智能体的一个触发器，包含最近一次运行的时间与状态
+// @Tags         智能体
+// @Produce      json
+// @Param        id          path      string  true  "智能体ID"
+// @Param        trigger_id  path      string  true  "触发器ID"
+// @Success      200         {object}  map[string]interface{}  "触发器"
+// @Failure      404         {object}  errors.AppError         "触发器不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers/{trigger_id} [get]
+func (h *AgentTriggerHandler) GetTrigger(c *gin.Context) {
+	trigger, err := h.service.GetTrigger(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("trigger_id")))
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    trigger,
+	})
+}
+
+// UpdateTrigger godoc
+// @Summary      更新智能体触发器
+// @Description  整体替换触发器配置；不传 delivery.webhook_secret 时保留原密钥，传空字符串时清除
+// @Tags         智能体
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string                  true  "智能体ID"
+// @Param        trigger_id  path      string                  true  "触发器ID"
+// @Param        request     body      map[string]interface{}  true  "触发器配置"
+// @Success      200         {object}  map[string]interface{}  "更新后的触发器"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Failure      404         {object}  errors.AppError         "触发器不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers/{trigger_id} [put]
+func (h *AgentTriggerHandler) UpdateTrigger(c *gin.Context) {
+	var req agentTriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	trigger, err := h.service.UpdateTrigger(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("trigger_id")),
+		req.toTrigger(), req.Delivery.WebhookSecret)
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    trigger,
+	})
+}
+
+// DeleteTrigger godoc
+// @Summary      删除智能体触发器
+// @Description  删除触发器并取消其定时计划，已有的运行记录保留
+// @Tags         智能体
+// @Produce      json
+// @Param        id          path      string  true  "智能体ID"
+// @Param        trigger_id  path      string  true  "触发器ID"
+// @Success      200         {object}  map[string]interface{}  "删除成功"
+// @Failure      404         {object}  errors.AppError         "触发器不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers/{trigger_id} [delete]
+func (h *AgentTriggerHandler) DeleteTrigger(c *gin.Context) {
+	err := h.service.DeleteTrigger(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("trigger_id")))
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// RunTrigger godoc
+// @Summary      立即运行智能体触发器
+// @Description  不论触发器类型与启用状态，立即排队运行一次，返回待执行的运行记录
+// @Tags         智能体
+// @Produce      json
+// @Param        id          path      string  true  "智能体ID"
+// @Param        trigger_id  path      string  true  "触发器ID"
+// @Success      202         {object}  map[string]interface{}  "运行记录"
+// @Failure      404         {object}  errors.AppError         "触发器不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers/{trigger_id}/run [post]
+func (h *AgentTriggerHandler) RunTrigger(c *gin.Context) {
+	run, err := h.service.RunTrigger(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("trigger_id")))
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    run,
+	})
+}
+
+// ListRuns godoc
+// @Summary      获取智能体触发器的运行记录
+// @Description  分页列出触发器的运行记录，按创建时间倒序，包含触发事件、回答与投递结果
+// @Tags         智能体
+// @Produce      json
+// @Param        id          path      string  true   "智能体ID"
+// @Param        trigger_id  path      string  true   "触发器ID"
+// @Param        page        query     int     false  "页码"
+// @Param        page_size   query     int     false  "每页数量"
+// @Success      200         {object}  map[string]interface{}  "运行记录"
+// @Failure      404         {object}  errors.AppError         "触发器不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/triggers/{trigger_id}/runs [get]
+func (h *AgentTriggerHandler) ListRuns(c *gin.Context) {
+	var page types.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	result, err := h.service.ListRuns(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("trigger_id")), &page)
+	if err != nil {
+		respondAgentTriggerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AnswerCacheHandler exposes the semantic answer cache of an agent to its
+// owner and workspace admins
+type AnswerCacheHandler struct {
+	service interfaces.AnswerCacheService
+}
+
+// NewAnswerCacheHandler creates a new AnswerCacheHandler instance
+func NewAnswerCacheHandler(service interfaces.AnswerCacheService) *AnswerCacheHandler {
+	return &AnswerCacheHandler{service: service}
+}
+
+// respondAnswerCacheError passes user-facing AppErrors through and wraps the rest
+func respondAnswerCacheError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// ListEntries godoc
+// @Summary      获取智能体的答案缓存
+// @Description  分页列出智能体缓存的问答，按创建时间倒序，包含命中次数与过期时间
+// @Tags         智能体
+// @Produce      json
+// @Param        id         path      string  true   "智能体ID"
+// @Param        page       query     int     false  "页码"
+// @Param        page_size  query     int     false  "每页数量"
+// @Success      200        {object}  map[string]interface{}  "缓存条目"
+// @Failure      400        {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/answer-cache [get]
+func (h *AnswerCacheHandler) ListEntries(c *gin.Context) {
+	var page types.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	result, err := h.service.ListEntries(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")), &page)
+	if err != nil {
+		respondAnswerCacheError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ClearEntries godoc
+// @Summary      清空智能体的答案缓存
+// @Description  删除智能体的全部缓存问答
+// @Tags         智能体
+// @Produce      json
+// @Param        id   path      string  true  "智能体ID"
+// @Success      200  {object}  map[string]interface{}  "删除的条目数"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/answer-cache [delete]
+func (h *AnswerCacheHandler) ClearEntries(c *gin.Context) {
+	deleted, err := h.service.ClearAgent(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondAnswerCacheError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"deleted": deleted},
+	})
+}
+
+// DeleteEntry godoc
+// @Summary      删除一条答案缓存
+// @Description  删除智能体的一条缓存问答
+// @Tags         智能体
+// @Produce      json
+// @Param        id        path      string  true  "智能体ID"
+// @Param        entry_id  path      string  true  "缓存条目ID"
+// @Success      200       {object}  map[string]interface{}  "删除成功"
+// @Failure      404       {object}  errors.AppError         "缓存条目不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agents/{id}/answer-cache/{entry_id} [delete]
+func (h *AnswerCacheHandler) DeleteEntry(c *gin.Context) {
+	err := h.service.DeleteEntry(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("entry_id")))
+	if err != nil {
+		respondAnswerCacheError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
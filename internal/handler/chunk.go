@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	stderrors "errors"
 	"net/http"
 
@@ -26,15 +27,36 @@ import (
 // lookup KBCreatorLookupFromKnowledgeIDParam still walks
 // knowledge_id -> kb_id to resolve creator_id (separate axis from
 // access — that lookup answers "is the caller the creator of THIS
-// resource", not "does the caller's tenant have access").
+// resource", not "does the caller's tenant have access"). The read
+// handlers also use it to apply per-document access lists, which the
+// KB-level guards cannot see.
 type ChunkHandler struct {
-	service   interfaces.ChunkService
-	kgService interfaces.KnowledgeService
+	service      interfaces.ChunkService
+	kgService    interfaces.KnowledgeService
+	knowledgeACL interfaces.KnowledgeACLService
 }
 
 // NewChunkHandler creates a new chunk handler.
-func NewChunkHandler(service interfaces.ChunkService, kgService interfaces.KnowledgeService) *ChunkHandler {
-	return &ChunkHandler{service: service, kgService: kgService}
+func NewChunkHandler(
+	service interfaces.ChunkService,
+	kgService interfaces.KnowledgeService,
+	knowledgeACL interfaces.KnowledgeACLService,
+) *ChunkHandler {
+	return &ChunkHandler{service: service, kgService: kgService, knowledgeACL: knowledgeACL}
+}
+
+// requireKnowledgeReadable applies the document access list of knowledgeID.
+// The route guards only check access to the parent knowledge base, so
+// without it the chunks of a hidden document would stay readable here.
+func (h *ChunkHandler) requireKnowledgeReadable(ctx context.Context, knowledgeID, notFound string) error {
+	if h.knowledgeACL == nil {
+		return nil
+	}
+	knowledge, err := h.kgService.GetKnowledgeByIDOnly(ctx, knowledgeID)
+	if err != nil {
+		return errors.NewNotFoundError(notFound)
+	}
+	return requireKnowledgeReadable(ctx, h.knowledgeACL, knowledge, notFound)
 }
 
 // GetChunkByIDOnly godoc
@@ -75,6 +97,10 @@ func (h *ChunkHandler) GetChunkByIDOnly(c *gin.Context) {
 		c.Error(errors.NewInternalServerError(err.Error()))
 		return
 	}
+	if err := h.requireKnowledgeReadable(ctx, chunk.KnowledgeID, "Chunk not found"); err != nil {
+		c.Error(err)
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -106,6 +132,10 @@ func (h *ChunkHandler) ListKnowledgeChunks(c *gin.Context) {
 		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
 		return
 	}
+	if err := h.requireKnowledgeReadable(ctx, knowledgeID, "Knowledge not found"); err != nil {
+		c.Error(err)
+		return
+	}
 
 	// Parse pagination parameters
 	var pagination types.Pagination
@@ -231,6 +261,10 @@ func (h *ChunkHandler) UpdateChunk(c *gin.Context) {
 			c.Error(errors.NewConflictError("Chunk was modified by another user; refresh and retry"))
 			return
 		}
+		if stderrors.Is(err, service.ErrVectorStoreMigrating) {
+			c.Error(asVectorStoreMigrationConflict(err))
+			return
+		}
 		c.Error(errors.NewInternalServerError(err.Error()))
 		return
 	}
@@ -250,11 +284,15 @@ func (h *ChunkHandler) UpdateChunk(c *gin.Context) {
 }
 
 func (h *ChunkHandler) ListChunkRevisions(c *gin.Context) {
-	chunk, _, err := h.fetchChunkAndVerifyOwnership(c)
+	chunk, knowledgeID, err := h.fetchChunkAndVerifyOwnership(c)
 	if err != nil {
 		c.Error(err)
 		return
 	}
+	if err := h.requireKnowledgeReadable(c.Request.Context(), knowledgeID, "Chunk not found"); err != nil {
+		c.Error(err)
+		return
+	}
 	items, err := h.service.ListChunkRevisions(c.Request.Context(), chunk.ID)
 	if err != nil {
 		c.Error(errors.NewInternalServerError(err.Error()))
@@ -288,6 +326,10 @@ func (h *ChunkHandler) RevertChunk(c *gin.Context) {
 		c.Error(errors.NewConflictError("Chunk was modified by another user; refresh and retry"))
 		return
 	}
+	if stderrors.Is(err, service.ErrVectorStoreMigrating) {
+		c.Error(asVectorStoreMigrationConflict(err))
+		return
+	}
 	if err != nil {
 		c.Error(errors.NewBadRequestError(err.Error()))
 		return
@@ -321,6 +363,10 @@ func (h *ChunkHandler) UpsertGeneratedQuestion(c *gin.Context) {
 		return
 	}
 	item, err := h.service.UpsertGeneratedQuestion(c.Request.Context(), chunkID, req.QuestionID, req.Question)
+	if stderrors.Is(err, service.ErrVectorStoreMigrating) {
+		c.Error(asVectorStoreMigrationConflict(err))
+		return
+	}
 	if err != nil {
 		c.Error(errors.NewBadRequestError(err.Error()))
 		return
@@ -449,6 +495,10 @@ func (h *ChunkHandler) DeleteGeneratedQuestion(c *gin.Context) {
 
 	if err := h.service.DeleteGeneratedQuestion(ctx, chunkID, req.QuestionID); err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
+		if stderrors.Is(err, service.ErrVectorStoreMigrating) {
+			c.Error(asVectorStoreMigrationConflict(err))
+			return
+		}
 		c.Error(errors.NewBadRequestError(err.Error()))
 		return
 	}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+type aclChunkService struct {
+	interfaces.ChunkService
+}
+
+func (aclChunkService) GetChunkByIDOnly(_ context.Context, id string) (*types.Chunk, error) {
+	return &types.Chunk{ID: id, KnowledgeID: "kn-hidden"}, nil
+}
+
+func (aclChunkService) GetChunkByID(_ context.Context, id string) (*types.Chunk, error) {
+	return &types.Chunk{ID: id, KnowledgeID: "kn-hidden"}, nil
+}
+
+func (aclChunkService) ListPagedChunksByKnowledgeID(
+	_ context.Context, _ string, page *types.Pagination, _ []types.ChunkType,
+) (*types.PageResult, error) {
+	return types.NewPageResult(1, page, []*types.Chunk{{ID: "ch-1"}}), nil
+}
+
+func (aclChunkService) ListChunkRevisions(_ context.Context, _ string) ([]*types.ChunkRevision, error) {
+	return nil, nil
+}
+
+type aclKnowledgeService struct {
+	interfaces.KnowledgeService
+}
+
+func (aclKnowledgeService) GetKnowledgeByIDOnly(_ context.Context, id string) (*types.Knowledge, error) {
+	return &types.Knowledge{ID: id, TenantID: 1, KnowledgeBaseID: "kb-1"}, nil
+}
+
+type stubKnowledgeACL struct {
+	interfaces.KnowledgeACLService
+	readable bool
+}
+
+func (s stubKnowledgeACL) CanReadKnowledge(_ context.Context, _ *types.Knowledge) (bool, error) {
+	return s.readable, nil
+}
+
+func TestChunkReadRoutesApplyDocumentAccessLists(t *testing.T) {
+	routes := []struct {
+		name   string
+		params gin.Params
+		serve  func(h *ChunkHandler, c *gin.Context)
+	}{
+		{"list", gin.Params{{Key: "knowledge_id", Value: "kn-hidden"}}, (*ChunkHandler).ListKnowledgeChunks},
+		{"by-id", gin.Params{{Key: "id", Value: "ch-1"}}, (*ChunkHandler).GetChunkByIDOnly},
+		{"revisions", gin.Params{{Key: "knowledge_id", Value: "kn-hidden"}, {Key: "id", Value: "ch-1"}}, (*ChunkHandler).ListChunkRevisions},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, route := range routes {
+		for _, readable := range []bool{false, true} {
+			h := NewChunkHandler(aclChunkService{}, aclKnowledgeService{}, stubKnowledgeACL{readable: readable})
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodGet, "/chunks", nil)
+			c.Params = route.params
+
+			route.serve(h, c)
+
+			if !readable {
+				var appErr *errors.AppError
+				if len(c.Errors) != 1 || !stderrors.As(c.Errors[0].Err, &appErr) || appErr.HTTPCode != http.StatusNotFound {
+					t.Errorf("%s: hidden document errors = %v, want not found", route.name, c.Errors)
+				}
+				continue
+			}
+			if len(c.Errors) != 0 || rec.Code != http.StatusOK {
+				t.Errorf("%s: readable document got status %d, errors %v", route.name, rec.Code, c.Errors)
+			}
+		}
+	}
+}
@@ -30,6 +30,7 @@ type DataSourceResponse struct {
 	Status               string               `json:"status"`
 	ConflictStrategy     string               `json:"conflict_strategy"`
 	SyncDeletions        bool                 `json:"sync_deletions"`
+	SyncPermissions      bool                 `json:"sync_permissions"`
 	LastSyncAt           *time.Time           `json:"last_sync_at"`
 	LastSyncCursor       json.RawMessage      `json:"last_sync_cursor,omitempty"`
 	LastSyncResult       json.RawMessage      `json:"last_sync_result,omitempty"`
@@ -81,6 +82,7 @@ func NewDataSourceResponse(ds *types.DataSource) *DataSourceResponse {
 		Status:               ds.Status,
 		ConflictStrategy:     ds.ConflictStrategy,
 		SyncDeletions:        ds.SyncDeletions,
+		SyncPermissions:      ds.PermissionSyncEnabled(),
 		LastSyncAt:           ds.LastSyncAt,
 		LastSyncCursor:       json.RawMessage(ds.LastSyncCursor),
 		LastSyncResult:       json.RawMessage(ds.LastSyncResult),
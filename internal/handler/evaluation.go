@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/Tencent/WeKnora/internal/application/service"
 	"github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/types"
@@ -11,14 +16,31 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// defaultEvaluationListLimit caps GET /evaluation/tasks when no limit is given
+const defaultEvaluationListLimit = 50
+
 // EvaluationHandler handles evaluation related HTTP requests
 type EvaluationHandler struct {
 	evaluationService interfaces.EvaluationService // Service for evaluation operations
+	datasetService    interfaces.DatasetService    // Service for evaluation datasets
 }
 
 // NewEvaluationHandler creates a new EvaluationHandler instance
-func NewEvaluationHandler(evaluationService interfaces.EvaluationService) *EvaluationHandler {
-	return &EvaluationHandler{evaluationService: evaluationService}
+func NewEvaluationHandler(
+	evaluationService interfaces.EvaluationService,
+	datasetService interfaces.DatasetService,
+) *EvaluationHandler {
+	return &EvaluationHandler{evaluationService: evaluationService, datasetService: datasetService}
+}
+
+// respondEvaluationError passes user-facing AppErrors through and wraps the rest
+func respondEvaluationError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
 }
 
 // EvaluationRequest contains parameters for evaluation request
@@ -27,6 +49,10 @@ type EvaluationRequest struct {
 	KnowledgeBaseID string `json:"knowledge_base_id"` // ID of knowledge base to use
 	ChatModelID     string `json:"chat_id"`           // ID of chat model to use
 	RerankModelID   string `json:"rerank_id"`         // ID of rerank model to use
+	JudgeModelID    string `json:"judge_model_id"`    // ID of chat model scoring judge metrics, optional
+
+	// FusionConfig is the hybrid fusion the run searches with, optional
+	FusionConfig *types.FusionConfig `json:"fusion_config"`
 }
 
 // Evaluation godoc
@@ -60,12 +86,13 @@ func (e *EvaluationHandler) Evaluation(c *gin.Context) {
 		return
 	}
 
-	logger.Infof(ctx, "Executing evaluation, tenant: %v, dataset: %s, knowledge_base: %s, chat: %s, rerank: %s",
+	logger.Infof(ctx, "Executing evaluation, tenant: %v, dataset: %s, knowledge_base: %s, chat: %s, rerank: %s, judge: %s",
 		tenantID,
 		secutils.SanitizeForLog(request.DatasetID),
 		secutils.SanitizeForLog(request.KnowledgeBaseID),
 		secutils.SanitizeForLog(request.ChatModelID),
 		secutils.SanitizeForLog(request.RerankModelID),
+		secutils.SanitizeForLog(request.JudgeModelID),
 	)
 
 	task, err := e.evaluationService.Evaluation(ctx,
@@ -73,10 +100,11 @@ func (e *EvaluationHandler) Evaluation(c *gin.Context) {
 		secutils.SanitizeForLog(request.KnowledgeBaseID),
 		secutils.SanitizeForLog(request.ChatModelID),
 		secutils.SanitizeForLog(request.RerankModelID),
+		secutils.SanitizeForLog(request.JudgeModelID),
+		request.FusionConfig,
 	)
 	if err != nil {
-		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(errors.NewInternalServerError(err.Error()))
+		respondEvaluationError(c, err)
 		return
 	}
 
@@ -118,8 +146,7 @@ func (e *EvaluationHandler) GetEvaluationResult(c *gin.Context) {
 
 	result, err := e.evaluationService.EvaluationResult(ctx, secutils.SanitizeForLog(request.TaskID))
 	if err != nil {
-		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(errors.NewInternalServerError(err.Error()))
+		respondEvaluationError(c, err)
 		return
 	}
 
@@ -129,3 +156,287 @@ func (e *EvaluationHandler) GetEvaluationResult(c *gin.Context) {
 		"data":    result,
 	})
 }
+
+// ListEvaluations godoc
+// @Summary      获取评估任务列表
+// @Description  按开始时间倒序列出当前租户的评估任务
+// @Tags         评估
+// @Produce      json
+// @Param        limit  query     int  false  "返回数量上限，默认 50"
+// @Success      200    {object}  map[string]interface{}  "评估任务列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/tasks [get]
+func (e *EvaluationHandler) ListEvaluations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := defaultEvaluationListLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.Error(errors.NewBadRequestError("limit must be a positive integer"))
+			return
+		}
+		limit = n
+	}
+
+	tasks, err := e.evaluationService.ListEvaluations(ctx, limit)
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tasks,
+	})
+}
+
+// ListQuestionResults godoc
+// @Summary      获取评估任务的逐题结果
+// @Description  返回评估任务每个问题的检索结果、生成答案与单题指标
+// @Tags         评估
+// @Produce      json
+// @Param        task_id  path      string  true  "评估任务ID"
+// @Success      200      {object}  map[string]interface{}  "逐题结果"
+// @Failure      404      {object}  errors.AppError         "任务不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/tasks/{task_id}/results [get]
+func (e *EvaluationHandler) ListQuestionResults(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	results, err := e.evaluationService.ListQuestionResults(ctx, secutils.SanitizeForLog(c.Param("task_id")))
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// UploadDataset godoc
+// @Summary      上传评估数据集
+// @Description  上传 JSONL（file 字段）或 parquet（queries/corpus/qrels/answers/qas 字段）格式的问答数据集
+// @Tags         评估
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name         formData  string  true   "数据集名称"
+// @Param        description  formData  string  false  "数据集描述"
+// @Param        format       formData  string  false  "jsonl 或 parquet，默认按上传字段推断"
+// @Param        file         formData  file    false  "JSONL 数据集"
+// @Success      200          {object}  map[string]interface{}  "数据集"
+// @Failure      400          {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/datasets [post]
+func (e *EvaluationHandler) UploadDataset(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.Error(errors.NewBadRequestError("name is required"))
+		return
+	}
+	upload := &types.EvaluationDatasetUpload{
+		Name:        name,
+		Description: strings.TrimSpace(c.PostForm("description")),
+		Format:      strings.ToLower(strings.TrimSpace(c.PostForm("format"))),
+		Parquet:     make(map[string][]byte),
+	}
+
+	jsonl, err := readEvaluationUploadFile(c, "file")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	upload.JSONL = jsonl
+	for _, part := range service.DatasetParquetFiles {
+		data, err := readEvaluationUploadFile(c, part)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if data != nil {
+			upload.Parquet[part] = data
+		}
+	}
+	if upload.Format == "" {
+		if upload.JSONL != nil {
+			upload.Format = types.EvaluationDatasetFormatJSONL
+		} else {
+			upload.Format = types.EvaluationDatasetFormatParquet
+		}
+	}
+
+	logger.Infof(ctx, "Uploading evaluation dataset %s, format: %s",
+		secutils.SanitizeForLog(name), secutils.SanitizeForLog(upload.Format))
+	dataset, err := e.datasetService.UploadDataset(ctx, upload)
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dataset,
+	})
+}
+
+// readEvaluationUploadFile reads one optional multipart file, enforcing the
+// global upload size limit. A missing field yields nil without error.
+func readEvaluationUploadFile(c *gin.Context, field string) ([]byte, error) {
+	file, header, err := c.Request.FormFile(field)
+	if err != nil {
+		return nil, nil
+	}
+	defer file.Close()
+
+	maxFileBytes := secutils.GetMaxFileSize()
+	maxFileSizeMB := secutils.GetMaxFileSizeMB()
+	if header.Size > maxFileBytes {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("%s cannot exceed %d MB", field, maxFileSizeMB))
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxFileBytes+1))
+	if err != nil {
+		return nil, errors.NewBadRequestError("failed to read uploaded file")
+	}
+	if int64(len(data)) > maxFileBytes {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("%s cannot exceed %d MB", field, maxFileSizeMB))
+	}
+	return data, nil
+}
+
+// ListDatasets godoc
+// @Summary      获取评估数据集列表
+// @Description  列出当前租户上传的评估数据集（不含内置 default 数据集）
+// @Tags         评估
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "数据集列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/datasets [get]
+func (e *EvaluationHandler) ListDatasets(c *gin.Context) {
+	datasets, err := e.datasetService.ListDatasets(c.Request.Context())
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    datasets,
+	})
+}
+
+// DeleteDataset godoc
+// @Summary      删除评估数据集
+// @Description  删除当前租户上传的评估数据集
+// @Tags         评估
+// @Produce      json
+// @Param        id   path      string  true  "数据集ID"
+// @Success      200  {object}  map[string]interface{}  "删除成功"
+// @Failure      404  {object}  errors.AppError         "数据集不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/datasets/{id} [delete]
+func (e *EvaluationHandler) DeleteDataset(c *gin.Context) {
+	if err := e.datasetService.DeleteDataset(c.Request.Context(), secutils.SanitizeForLog(c.Param("id"))); err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// CompareEvaluations godoc
+// @Summary      对比两次评估
+// @Description  按问题与整体对比目标评估和基线评估的指标；指定 metrics 时执行回归门禁，任一指标下降超过 max_drop 则 passed 为 false
+// @Tags         评估
+// @Produce      json
+// @Param        base_task_id    query     string  true   "基线评估任务ID"
+// @Param        target_task_id  query     string  true   "目标评估任务ID"
+// @Param        metrics         query     string  false  "门禁指标，逗号分隔，如 mrr,ndcg10"
+// @Param        max_drop        query     number  false  "允许的最大绝对下降值，默认 0"
+// @Success      200             {object}  map[string]interface{}  "对比结果"
+// @Failure      400             {object}  errors.AppError         "请求参数错误"
+// @Failure      404             {object}  errors.AppError         "任务不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/compare [get]
+func (e *EvaluationHandler) CompareEvaluations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	baseTaskID := secutils.SanitizeForLog(c.Query("base_task_id"))
+	targetTaskID := secutils.SanitizeForLog(c.Query("target_task_id"))
+	if baseTaskID == "" || targetTaskID == "" {
+		c.Error(errors.NewBadRequestError("base_task_id and target_task_id are required"))
+		return
+	}
+
+	var gate *types.EvaluationGate
+	if raw := c.Query("metrics"); raw != "" {
+		gate = &types.EvaluationGate{}
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				gate.Metrics = append(gate.Metrics, name)
+			}
+		}
+		if raw := c.Query("max_drop"); raw != "" {
+			maxDrop, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				c.Error(errors.NewBadRequestError("max_drop must be a number"))
+				return
+			}
+			gate.MaxDrop = maxDrop
+		}
+	}
+
+	comparison, err := e.evaluationService.CompareEvaluations(ctx, baseTaskID, targetTaskID, gate)
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    comparison,
+	})
+}
+
+// TuneFusionWeights godoc
+// @Summary      从评估结果学习融合权重
+// @Description  在同一数据集、使用不同分数融合配置（weighted / dbsf）的已完成评估中选出指标最高的一次，将其权重作为 learned 融合写入知识库
+// @Tags         评估
+// @Accept       json
+// @Produce      json
+// @Param        request  body      types.FusionTuneRequest  true  "调优请求"
+// @Success      200      {object}  map[string]interface{}   "知识库新的融合配置"
+// @Failure      400      {object}  errors.AppError          "请求参数错误"
+// @Failure      404      {object}  errors.AppError          "任务或知识库不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /evaluation/fusion/tune [post]
+func (e *EvaluationHandler) TuneFusionWeights(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request types.FusionTuneRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(errors.NewBadRequestError("Invalid request parameters").WithDetails(err.Error()))
+		return
+	}
+	request.KnowledgeBaseID = secutils.SanitizeForLog(request.KnowledgeBaseID)
+	for i, taskID := range request.TaskIDs {
+		request.TaskIDs[i] = secutils.SanitizeForLog(taskID)
+	}
+	request.Metric = strings.ToLower(strings.TrimSpace(request.Metric))
+
+	fusion, err := e.evaluationService.TuneFusionWeights(ctx, &request)
+	if err != nil {
+		respondEvaluationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fusion,
+	})
+}
@@ -184,7 +184,7 @@ func (h *FAQHandler) CreateEntry(c *gin.Context) {
 	entry, err := h.knowledgeService.CreateFAQEntry(ctx, kbID, &req)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 
@@ -228,7 +228,7 @@ func (h *FAQHandler) UpdateEntry(c *gin.Context) {
 	entry, err := h.knowledgeService.UpdateFAQEntry(ctx, kbID, entrySeqID, &req)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 
@@ -263,7 +263,7 @@ func (h *FAQHandler) UpdateEntryTagBatch(c *gin.Context) {
 	}
 	if err := h.knowledgeService.UpdateFAQEntryTagBatch(ctx, kbID, req.Updates); err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -296,7 +296,7 @@ func (h *FAQHandler) UpdateEntryFieldsBatch(c *gin.Context) {
 	}
 	if err := h.knowledgeService.UpdateFAQEntryFieldsBatch(ctx, kbID, &req); err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -330,7 +330,7 @@ func (h *FAQHandler) DeleteEntries(c *gin.Context) {
 
 	if err := h.knowledgeService.DeleteFAQEntries(ctx, kbID, req.IDs); err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 
@@ -570,7 +570,7 @@ func (h *FAQHandler) AddSimilarQuestions(c *gin.Context) {
 	entry, err := h.knowledgeService.AddSimilarQuestions(ctx, kbID, entrySeqID, req.SimilarQuestions)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		c.Error(err)
+		c.Error(asVectorStoreMigrationConflict(err))
 		return
 	}
 
@@ -38,6 +38,7 @@ type KnowledgeHandler struct {
 	agentShareService interfaces.AgentShareService
 	asynqClient       interfaces.TaskEnqueuer
 	spanRepo          repository.KnowledgeSpanRepository
+	knowledgeACL      interfaces.KnowledgeACLService
 }
 
 // NewKnowledgeHandler creates a new knowledge handler instance
@@ -49,6 +50,7 @@ func NewKnowledgeHandler(
 	agentShareService interfaces.AgentShareService,
 	asynqClient interfaces.TaskEnqueuer,
 	spanRepo repository.KnowledgeSpanRepository,
+	knowledgeACL interfaces.KnowledgeACLService,
 ) *KnowledgeHandler {
 	return &KnowledgeHandler{
 		cfg:               cfg,
@@ -58,6 +60,7 @@ func NewKnowledgeHandler(
 		agentShareService: agentShareService,
 		asynqClient:       asynqClient,
 		spanRepo:          spanRepo,
+		knowledgeACL:      knowledgeACL,
 	}
 }
 
@@ -170,6 +173,9 @@ func (h *KnowledgeHandler) resolveKnowledgeAndValidateKBAccess(c *gin.Context, k
 	if err := requireTenantAPIKeyKnowledgeBase(ctx, knowledge.KnowledgeBaseID); err != nil {
 		return nil, ctx, err
 	}
+	if err := h.requireKnowledgeReadable(ctx, knowledge); err != nil {
+		return nil, ctx, err
+	}
 
 	// Owner: knowledge belongs to caller's tenant
 	if knowledge.TenantID == tenantID {
@@ -226,6 +232,84 @@ func (h *KnowledgeHandler) resolveKnowledgeAndValidateKBAccess(c *gin.Context, k
 	return nil, ctx, errors.NewForbiddenError("Permission denied to access this knowledge")
 }
 
+// requireKnowledgeReadable hides a document that its access list does not
+// grant to the caller. It reports not found rather than forbidden so the
+// existence of restricted documents is not revealed.
+func (h *KnowledgeHandler) requireKnowledgeReadable(ctx context.Context, knowledge *types.Knowledge) error {
+	return requireKnowledgeReadable(ctx, h.knowledgeACL, knowledge, "Knowledge not found")
+}
+
+// requireKnowledgeReadable is shared by the handlers that serve a document's
+// content; notFound is the message of the error that hides it.
+func requireKnowledgeReadable(
+	ctx context.Context, acl interfaces.KnowledgeACLService, knowledge *types.Knowledge, notFound string,
+) error {
+	if acl == nil {
+		return nil
+	}
+	readable, err := acl.CanReadKnowledge(ctx, knowledge)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		return errors.NewInternalServerError("Failed to verify document access")
+	}
+	if !readable {
+		return errors.NewNotFoundError(notFound)
+	}
+	return nil
+}
+
+// deniedKnowledgeIDs returns the documents of a knowledge base that access
+// lists hide from the caller. ctx must still carry the caller's own tenant.
+func (h *KnowledgeHandler) deniedKnowledgeIDs(ctx context.Context, kbID string) ([]string, error) {
+	if h.knowledgeACL == nil {
+		return nil, nil
+	}
+	denied, err := h.knowledgeACL.DeniedKnowledgeIDs(ctx, []string{kbID})
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		return nil, errors.NewInternalServerError("Failed to verify document access")
+	}
+	return denied[kbID], nil
+}
+
+// filterReadableKnowledges drops the documents access lists hide from the caller
+func (h *KnowledgeHandler) filterReadableKnowledges(
+	ctx context.Context, knowledges []*types.Knowledge,
+) ([]*types.Knowledge, error) {
+	if h.knowledgeACL == nil || len(knowledges) == 0 {
+		return knowledges, nil
+	}
+	var kbIDs []string
+	seen := make(map[string]bool)
+	for _, k := range knowledges {
+		if !seen[k.KnowledgeBaseID] {
+			seen[k.KnowledgeBaseID] = true
+			kbIDs = append(kbIDs, k.KnowledgeBaseID)
+		}
+	}
+	denied, err := h.knowledgeACL.DeniedKnowledgeIDs(ctx, kbIDs)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		return nil, errors.NewInternalServerError("Failed to verify document access")
+	}
+	hidden := make(map[string]bool)
+	for _, ids := range denied {
+		for _, id := range ids {
+			hidden[id] = true
+		}
+	}
+	if len(hidden) == 0 {
+		return knowledges, nil
+	}
+	readable := make([]*types.Knowledge, 0, len(knowledges))
+	for _, k := range knowledges {
+		if !hidden[k.ID] {
+			readable = append(readable, k)
+		}
+	}
+	return readable, nil
+}
+
 // handleDuplicateKnowledgeError handles cases where duplicate knowledge is detected
 // Returns true if the error was a duplicate error and was handled, false otherwise
 func (h *KnowledgeHandler) handleDuplicateKnowledgeError(c *gin.Context,
@@ -943,6 +1027,11 @@ func (h *KnowledgeHandler) ListKnowledge(c *gin.Context) {
 		c.Error(err)
 		return
 	}
+	denied, err := h.deniedKnowledgeIDs(ctx, kbID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
 	// Update context with effective tenant ID for shared KB access
 	ctx = context.WithValue(ctx, types.TenantIDContextKey, effectiveTenantID)
@@ -956,11 +1045,12 @@ func (h *KnowledgeHandler) ListKnowledge(c *gin.Context) {
 	}
 
 	filter := types.KnowledgeListFilter{
-		TagIDs:      parseCommaSeparatedTagIDs(c.Query("tag_ids")),
-		Keyword:     c.Query("keyword"),
-		FileType:    c.Query("file_type"),
-		ParseStatus: c.Query("parse_status"),
-		Source:      c.Query("source"),
+		TagIDs:              parseCommaSeparatedTagIDs(c.Query("tag_ids")),
+		Keyword:             c.Query("keyword"),
+		FileType:            c.Query("file_type"),
+		ParseStatus:         c.Query("parse_status"),
+		Source:              c.Query("source"),
+		ExcludeKnowledgeIDs: denied,
 	}
 	if raw := c.Query("start_time"); raw != "" {
 		t, err := parseFilterTime(raw)
@@ -1768,6 +1858,10 @@ func (h *KnowledgeHandler) GetKnowledgeBatch(c *gin.Context) {
 		c.Error(errors.NewInternalServerError("Failed to retrieve knowledge list").WithDetails(err.Error()))
 		return
 	}
+	if knowledges, err = h.filterReadableKnowledges(c.Request.Context(), knowledges); err != nil {
+		c.Error(err)
+		return
+	}
 
 	logger.Infof(ctx, "Batch knowledge retrieval successful, requested count: %d, returned count: %d",
 		len(req.IDs), len(knowledges))
@@ -1994,6 +2088,10 @@ func (h *KnowledgeHandler) ReparseKnowledge(c *gin.Context) {
 			c.Error(appErr)
 			return
 		}
+		if goerrors.Is(err, service.ErrVectorStoreMigrating) {
+			c.Error(asVectorStoreMigrationConflict(err))
+			return
+		}
 		logger.ErrorWithFields(ctx, err, map[string]interface{}{
 			"knowledge_id": id,
 		})
@@ -2342,6 +2440,10 @@ func (h *KnowledgeHandler) SearchKnowledge(c *gin.Context) {
 			c.Error(errors.NewInternalServerError("Failed to search knowledge").WithDetails(err.Error()))
 			return
 		}
+		if knowledges, err = h.filterReadableKnowledges(c.Request.Context(), knowledges); err != nil {
+			c.Error(err)
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{
 			"success":  true,
 			"data":     knowledges,
@@ -2367,6 +2469,10 @@ func (h *KnowledgeHandler) SearchKnowledge(c *gin.Context) {
 			c.Error(errors.NewInternalServerError("Failed to search knowledge").WithDetails(err.Error()))
 			return
 		}
+		if knowledges, err = h.filterReadableKnowledges(c.Request.Context(), knowledges); err != nil {
+			c.Error(err)
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{
 			"success":  true,
 			"data":     knowledges,
@@ -2383,6 +2489,10 @@ func (h *KnowledgeHandler) SearchKnowledge(c *gin.Context) {
 		c.Error(errors.NewInternalServerError("Failed to search knowledge").WithDetails(err.Error()))
 		return
 	}
+	if knowledges, err = h.filterReadableKnowledges(c.Request.Context(), knowledges); err != nil {
+		c.Error(err)
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// KnowledgeACLHandler manages the access lists of documents and folders
+type KnowledgeACLHandler struct {
+	service interfaces.KnowledgeACLService
+}
+
+// NewKnowledgeACLHandler creates a new KnowledgeACLHandler instance
+func NewKnowledgeACLHandler(service interfaces.KnowledgeACLService) *KnowledgeACLHandler {
+	return &KnowledgeACLHandler{service: service}
+}
+
+// knowledgeACLRequest is the body of the access list updates. An empty list
+// removes the access list of the target.
+type knowledgeACLRequest struct {
+	Principals []types.KnowledgeACLPrincipal `json:"principals"`
+}
+
+// respondACLError passes user-facing AppErrors through and wraps the rest
+func respondACLError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// GetKnowledgeACL godoc
+// @Summary      获取文档访问控制列表
+// @Description  返回文档自身的访问控制列表，以及它从最近的受限文件夹继承的列表
+// @Tags         访问控制
+// @Produce      json
+// @Param        id   path      string  true  "知识ID"
+// @Success      200  {object}  map[string]interface{}  "访问控制列表"
+// @Failure      404  {object}  errors.AppError         "知识不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/acl [get]
+func (h *KnowledgeACLHandler) GetKnowledgeACL(c *gin.Context) {
+	acl, err := h.service.GetKnowledgeACL(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    acl,
+	})
+}
+
+// SetKnowledgeACL godoc
+// @Summary      设置文档访问控制列表
+// @Description  整体替换文档的访问控制列表，主体为用户或用户组；传空列表后文档重新继承所在文件夹的列表
+// @Tags         访问控制
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "知识ID"
+// @Param        request  body      map[string]interface{}  true  "{principals: [{type, id}]}"
+// @Success      200      {object}  map[string]interface{}  "更新后的访问控制列表"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/acl [put]
+func (h *KnowledgeACLHandler) SetKnowledgeACL(c *gin.Context) {
+	var req knowledgeACLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	acl, err := h.service.SetKnowledgeACL(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")), req.Principals)
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    acl,
+	})
+}
+
+// ListKnowledgeBaseACLs godoc
+// @Summary      获取知识库内的访问控制列表
+// @Description  列出知识库内设置过访问控制列表的全部文件夹与文档
+// @Tags         访问控制
+// @Produce      json
+// @Param        id   path      string  true  "知识库ID"
+// @Success      200  {object}  map[string]interface{}  "访问控制列表"
+// @Failure      404  {object}  errors.AppError         "知识库不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/acl [get]
+func (h *KnowledgeACLHandler) ListKnowledgeBaseACLs(c *gin.Context) {
+	acls, err := h.service.ListKnowledgeBaseACLs(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    acls,
+	})
+}
+
+// GetFolderACL godoc
+// @Summary      获取文件夹访问控制列表
+// @Description  返回文件夹自身的访问控制列表，以及它从上级文件夹继承的列表
+// @Tags         访问控制
+// @Produce      json
+// @Param        id           path      string  true  "知识库ID"
+// @Param        folder_path  query     string  true  "文件夹路径"
+// @Success      200          {object}  map[string]interface{}  "访问控制列表"
+// @Failure      400          {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/acl/folder [get]
+func (h *KnowledgeACLHandler) GetFolderACL(c *gin.Context) {
+	acl, err := h.service.GetFolderACL(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), c.Query("folder_path"))
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    acl,
+	})
+}
+
+// SetFolderACL godoc
+// @Summary      设置文件夹访问控制列表
+// @Description  整体替换文件夹的访问控制列表，作用于文件夹及其子文件夹中没有自身列表的文档；根目录不能设置
+// @Tags         访问控制
+// @Accept       json
+// @Produce      json
+// @Param        id           path      string                  true  "知识库ID"
+// @Param        folder_path  query     string                  true  "文件夹路径"
+// @Param        request      body      map[string]interface{}  true  "{principals: [{type, id}]}"
+// @Success      200          {object}  map[string]interface{}  "更新后的访问控制列表"
+// @Failure      400          {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/acl/folder [put]
+func (h *KnowledgeACLHandler) SetFolderACL(c *gin.Context) {
+	var req knowledgeACLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	acl, err := h.service.SetFolderACL(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), c.Query("folder_path"), req.Principals)
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    acl,
+	})
+}
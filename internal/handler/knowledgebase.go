@@ -1062,11 +1062,12 @@ func (h *KnowledgeBaseHandler) CopyKnowledgeBase(c *gin.Context) {
 		}
 		// Pre-flight defense 2: vector store binding must match.
 		// Cross-store cloning would require copying physical vector data
-		// between stores, which is not yet supported.
+		// between stores; that is what a vector store migration does, so
+		// the caller is pointed there instead.
 		if !sourceKB.SharesStoreWith(targetKB) {
 			c.Error(apperrors.NewBadRequestError(
 				"source and target knowledge bases are bound to different vector stores; " +
-					"cross-store cloning is not yet supported"))
+					"migrate one of them to the other's vector store before cloning"))
 			return
 		}
 		// Pre-flight defense 3: compare concrete instance IDs, not just the
@@ -1404,3 +1405,176 @@ func (h *KnowledgeBaseHandler) ListMoveTargets(c *gin.Context) {
 		"data":    targets,
 	})
 }
+
+// SetEmbeddingModelRequest defines the request body for switching the
+// embedding model of a knowledge base.
+type SetEmbeddingModelRequest struct {
+	ModelID string `json:"model_id" binding:"required"`
+}
+
+// SetEmbeddingModel godoc
+// @Summary      切换知识库Embedding模型
+// @Description  切换知识库的Embedding模型。已有文档时在后台用新模型重建影子索引，完成前检索仍使用旧模型与旧向量，完成后原子切换并删除旧向量
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "知识库ID"
+// @Param        request  body      SetEmbeddingModelRequest  true  "目标模型"
+// @Success      200      {object}  map[string]interface{}    "已切换（无需迁移）"
+// @Success      202      {object}  map[string]interface{}    "迁移任务已提交"
+// @Failure      400      {object}  errors.AppError           "请求参数错误"
+// @Failure      409      {object}  errors.AppError           "已有迁移进行中"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/embedding-model [put]
+func (h *KnowledgeBaseHandler) SetEmbeddingModel(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if id == "" {
+		c.Error(apperrors.NewBadRequestError("knowledge base ID is required"))
+		return
+	}
+	var req SetEmbeddingModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	migration, err := h.service.SetEmbeddingModel(ctx, id, req.ModelID)
+	if err != nil {
+		if appErr, ok := apperrors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		if stderrors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+			c.Error(apperrors.NewNotFoundError("knowledge base not found"))
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(apperrors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	if migration == nil {
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": nil})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": migration})
+}
+
+// GetEmbeddingMigration godoc
+// @Summary      获取Embedding模型迁移进度
+// @Description  获取知识库最近一次Embedding模型迁移的状态与进度，没有迁移时 data 为 null
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string                  true  "知识库ID"
+// @Success      200  {object}  map[string]interface{}  "迁移状态"
+// @Failure      404  {object}  errors.AppError         "知识库不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/embedding-migration [get]
+func (h *KnowledgeBaseHandler) GetEmbeddingMigration(c *gin.Context) {
+	ctx := c.Request.Context()
+	kb, err := h.service.GetKnowledgeBaseByID(ctx, c.Param("id"))
+	if err != nil {
+		if stderrors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+			c.Error(apperrors.NewNotFoundError("knowledge base not found"))
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(apperrors.NewInternalServerError(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": kb.EmbeddingMigration})
+}
+
+// MigrateVectorStoreRequest defines the request body for moving a knowledge
+// base to another vector store.
+type MigrateVectorStoreRequest struct {
+	VectorStoreID string `json:"vector_store_id" binding:"required"`
+}
+
+// MigrateVectorStore godoc
+// @Summary      迁移知识库向量存储
+// @Description  将知识库的索引条目连同已存储的向量迁移到另一个向量存储，不重新调用Embedding模型。后台分页复制并保存断点，校验条目数一致后切换绑定并删除原存储中的条目，完成前检索仍使用原存储
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "知识库ID"
+// @Param        request  body      MigrateVectorStoreRequest  true  "目标向量存储"
+// @Success      202      {object}  map[string]interface{}     "迁移任务已提交"
+// @Failure      400      {object}  errors.AppError            "请求参数错误或存储不支持迁移"
+// @Failure      409      {object}  errors.AppError            "已有迁移进行中"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/vector-store [put]
+func (h *KnowledgeBaseHandler) MigrateVectorStore(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if id == "" {
+		c.Error(apperrors.NewBadRequestError("knowledge base ID is required"))
+		return
+	}
+	var req MigrateVectorStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	migration, err := h.service.MigrateVectorStore(ctx, id, req.VectorStoreID)
+	if err != nil {
+		if appErr, ok := apperrors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		if stderrors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+			c.Error(apperrors.NewNotFoundError("knowledge base not found"))
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(apperrors.NewInternalServerError(err.Error()))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": migration})
+}
+
+// asVectorStoreMigrationConflict reports an edit refused by a knowledge base
+// that is moving to another vector store or embedding model as 409, so
+// clients retry after the migration. Other errors are returned unchanged.
+func asVectorStoreMigrationConflict(err error) error {
+	if stderrors.Is(err, service.ErrEmbeddingModelMigrating) {
+		return apperrors.NewConflictError("Knowledge base is switching to another embedding model; retry when it finishes")
+	}
+	if stderrors.Is(err, service.ErrVectorStoreMigrating) {
+		return apperrors.NewConflictError("Knowledge base is migrating to another vector store; retry when it finishes")
+	}
+	return err
+}
+
+// GetVectorStoreMigration godoc
+// @Summary      获取向量存储迁移进度
+// @Description  获取知识库最近一次向量存储迁移的状态、进度与校验条目数，没有迁移时 data 为 null
+// @Tags         知识库
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string                  true  "知识库ID"
+// @Success      200  {object}  map[string]interface{}  "迁移状态"
+// @Failure      404  {object}  errors.AppError         "知识库不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/vector-store-migration [get]
+func (h *KnowledgeBaseHandler) GetVectorStoreMigration(c *gin.Context) {
+	ctx := c.Request.Context()
+	kb, err := h.service.GetKnowledgeBaseByID(ctx, c.Param("id"))
+	if err != nil {
+		if stderrors.Is(err, repository.ErrKnowledgeBaseNotFound) {
+			c.Error(apperrors.NewNotFoundError("knowledge base not found"))
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(apperrors.NewInternalServerError(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": kb.VectorStoreMigration})
+}
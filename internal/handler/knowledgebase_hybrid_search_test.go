@@ -132,3 +132,21 @@ func performHybridSearchRequest(svc interfaces.KnowledgeBaseService, body string
 	newHybridSearchTestRouter(svc).ServeHTTP(response, request)
 	return response
 }
+
+func TestHybridSearchBindsMetadataFilter(t *testing.T) {
+	svc := &hybridSearchTestService{}
+	response := performHybridSearchRequest(svc, `{"query_text":"policy","metadata_filter":`+
+		`{"op":"and","filters":[{"op":"eq","key":"region","value":"EU"},`+
+		`{"op":"gt","key":"effective_date","value":"2025-01-01"}]}}`)
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", response.Code, response.Body.String())
+	}
+	filter := svc.searchParams.MetadataFilter
+	if filter == nil || filter.Op != types.MetadataFilterAnd || len(filter.Filters) != 2 {
+		t.Fatalf("metadata filter = %+v, want an and of two conditions", filter)
+	}
+	if filter.Filters[0].Key != "region" || filter.Filters[0].Value != "EU" {
+		t.Fatalf("first condition = %+v, want region = EU", filter.Filters[0])
+	}
+}
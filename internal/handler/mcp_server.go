@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/Tencent/WeKnora/internal/mcpserver"
+	"github.com/gin-gonic/gin"
+)
+
+// MCPServerHandler serves the workspace as a remote MCP server over the
+// streamable HTTP transport
+type MCPServerHandler struct {
+	server *mcpserver.Server
+}
+
+// NewMCPServerHandler creates a new MCPServerHandler instance
+func NewMCPServerHandler(server *mcpserver.Server) *MCPServerHandler {
+	return &MCPServerHandler{server: server}
+}
+
+// Serve godoc
+// @Summary      MCP 服务端（Streamable HTTP）
+// @Description  以 MCP Streamable HTTP 协议（无状态）提供知识库检索、文档与分块读取、Wiki 页面读取和智能体问答工具，并把知识库与 Wiki 页面作为资源列出。API Key 可通过 X-API-Key 或 Authorization: Bearer 传入，工具按 Key 的能力与知识库范围过滤。请求体为 JSON-RPC 消息，GET 返回 405
+// @Tags         MCP服务端
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "JSON-RPC 响应"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /mcp [post]
+func (h *MCPServerHandler) Serve(c *gin.Context) {
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
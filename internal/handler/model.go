@@ -92,6 +92,10 @@ func (h *ModelHandler) CreateModel(c *gin.Context) {
 			return
 		}
 	}
+	if err := req.Parameters.RerankParameters.Validate(); err != nil {
+		c.Error(errors.NewBadRequestError("Invalid rerank_parameters: " + err.Error()))
+		return
+	}
 
 	model := &types.Model{
 		TenantID:    tenantID,
@@ -623,6 +627,15 @@ func (h *ModelHandler) UpdateModel(c *gin.Context) {
 	if newParams.ExtraConfig == nil {
 		newParams.ExtraConfig = model.Parameters.ExtraConfig
 	}
+	if newParams.RerankParameters == nil {
+		newParams.RerankParameters = model.Parameters.RerankParameters
+	}
+	// Validate what will be saved, so a stored calibration that predates
+	// validation cannot survive an edit that leaves it untouched.
+	if err := newParams.RerankParameters.Validate(); err != nil {
+		c.Error(errors.NewBadRequestError("Invalid rerank_parameters: " + err.Error()))
+		return
+	}
 	model.Parameters = newParams
 
 	model.Source = req.Source
@@ -232,10 +232,6 @@ func (p *AttachmentProcessor) processWithDocumentReader(
 	attachment *types.MessageAttachment,
 	tenantID uint64,
 ) error {
-	if p.documentReader == nil {
-		return fmt.Errorf("DocumentReader not configured")
-	}
-
 	normalizedType := strings.TrimPrefix(fileType, ".")
 
 	parserEngine := ""
@@ -246,9 +242,10 @@ func (p *AttachmentProcessor) processWithDocumentReader(
 	}
 	overrides := getParserEngineOverridesFromContext(ctx)
 
-	// Engines that parse in this process (anydoc, MinerU, ...) are resolved
-	// through the registry so a chat attachment honours the same engine rules
-	// as an ingested document. Anything the registry cannot build here — a
+	// Engines that parse in this process (simple, anydoc, MinerU, ...) are
+	// resolved through the registry so a chat attachment honours the same
+	// engine rules as an ingested document; without a docreader, office
+	// formats are still converted in Go. Anything the registry cannot build here — a
 	// cloud engine whose credentials this path cannot resolve — falls back to
 	// the docreader, which is where every engine name went before.
 	reader, err := docparser.NewReader(ctx, parserEngine, normalizedType, false, docparser.ReaderDeps{
@@ -256,6 +253,9 @@ func (p *AttachmentProcessor) processWithDocumentReader(
 		Remote:    p.documentReader,
 	})
 	if err != nil {
+		if p.documentReader == nil {
+			return fmt.Errorf("DocumentReader not configured")
+		}
 		logger.Warnf(ctx, "parser engine %q unusable for this attachment, using docreader: %v", parserEngine, err)
 		reader = p.documentReader
 	}
@@ -0,0 +1,540 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAI-compatible model IDs name the WeKnora resource that answers:
+// "agent:<custom agent ID>" runs the agent, "kb:<knowledge base ID>" runs
+// knowledge QA over a single knowledge base.
+const (
+	openAIModelAgentPrefix = "agent:"
+	openAIModelKBPrefix    = "kb:"
+
+	// openAISessionTitleRunes caps the title of the session backing a completion
+	openAISessionTitleRunes = 50
+)
+
+// OpenAIChatMessage is one message of an OpenAI chat completion request.
+// Content is either a string or an array of content parts; only text parts
+// are used.
+type OpenAIChatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// OpenAIChatCompletionRequest is the subset of the OpenAI chat completion
+// request the facade understands. Sampling parameters are ignored: the agent
+// or knowledge base configuration owns them.
+type OpenAIChatCompletionRequest struct {
+	Model    string              `json:"model"    binding:"required"`
+	Messages []OpenAIChatMessage `json:"messages" binding:"required"`
+	Stream   bool                `json:"stream"`
+}
+
+// OpenAIChatCompletionMessage is the assistant message of a completion
+type OpenAIChatCompletionMessage struct {
+	Role             string `json:"role,omitempty"`
+	Content          string `json:"content,omitempty"`
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+}
+
+// OpenAIChatCompletionChoice is one choice of a completion or stream chunk
+type OpenAIChatCompletionChoice struct {
+	Index        int                          `json:"index"`
+	Message      *OpenAIChatCompletionMessage `json:"message,omitempty"`
+	Delta        *OpenAIChatCompletionMessage `json:"delta,omitempty"`
+	FinishReason *string                      `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionResponse is a completion ("chat.completion") or a
+// stream chunk ("chat.completion.chunk"). References is a WeKnora extension
+// carrying the retrieved chunks the answer is grounded on.
+type OpenAIChatCompletionResponse struct {
+	ID         string                       `json:"id"`
+	Object     string                       `json:"object"`
+	Created    int64                        `json:"created"`
+	Model      string                       `json:"model"`
+	Choices    []OpenAIChatCompletionChoice `json:"choices"`
+	References types.References             `json:"references,omitempty"`
+}
+
+// OpenAIModel is one entry of the OpenAI model list
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+	Name    string `json:"name"` // WeKnora extension: display name of the agent or knowledge base
+}
+
+// openAIErrorBody is the OpenAI error envelope
+type openAIErrorBody struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+}
+
+// respondOpenAIError writes an error in the shape OpenAI clients parse
+func respondOpenAIError(c *gin.Context, status int, errType, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": openAIErrorBody{Message: message, Type: errType, Code: code}})
+}
+
+// ListOpenAIModels godoc
+// @Summary      OpenAI 兼容模型列表
+// @Description  以 OpenAI /v1/models 格式列出可对话的智能体（agent:<id>）和知识库（kb:<id>）
+// @Tags         OpenAI兼容
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "模型列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /openai/models [get]
+func (h *Handler) ListOpenAIModels(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	agents, err := h.customAgentService.ListAgents(ctx)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", "failed to list agents")
+		return
+	}
+	kbs, err := h.knowledgebaseService.ListKnowledgeBases(ctx)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", "failed to list knowledge bases")
+		return
+	}
+
+	models := make([]OpenAIModel, 0, len(agents)+len(kbs))
+	for _, agent := range agents {
+		if agent == nil {
+			continue
+		}
+		models = append(models, OpenAIModel{
+			ID:      openAIModelAgentPrefix + agent.ID,
+			Object:  "model",
+			Created: agent.CreatedAt.Unix(),
+			OwnedBy: "weknora",
+			Name:    agent.Name,
+		})
+	}
+	for _, kb := range kbs {
+		// A key restricted to some knowledge bases only sees those
+		if kb == nil || types.AuthorizeTenantAPIKeyKnowledgeBases(ctx, kb.ID) != nil {
+			continue
+		}
+		models = append(models, OpenAIModel{
+			ID:      openAIModelKBPrefix + kb.ID,
+			Object:  "model",
+			Created: kb.CreatedAt.Unix(),
+			OwnedBy: "weknora",
+			Name:    kb.Name,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": models})
+}
+
+// ChatCompletions godoc
+// @Summary      OpenAI 兼容对话补全
+// @Description  以 OpenAI /v1/chat/completions 格式对智能体或知识库提问，支持流式（SSE）和非流式；引用在扩展字段 references 中返回
+// @Tags         OpenAI兼容
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Param        request  body      OpenAIChatCompletionRequest  true  "对话补全请求"
+// @Success      200      {object}  OpenAIChatCompletionResponse  "对话补全结果"
+// @Failure      400      {object}  map[string]interface{}        "请求参数错误"
+// @Failure      404      {object}  map[string]interface{}        "模型不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /openai/chat/completions [post]
+func (h *Handler) ChatCompletions(c *gin.Context) {
+	receivedAt := time.Now()
+	ctx := logger.CloneContext(c.Request.Context())
+	requestID := secutils.SanitizeForLog(c.GetString(types.RequestIDContextKey.String()))
+
+	var request OpenAIChatCompletionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondOpenAIError(c, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+	query, history, err := splitOpenAIMessages(request.Messages)
+	if err != nil {
+		respondOpenAIError(c, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+
+	reqCtx := &qaRequestContext{
+		ctx:        ctx,
+		c:          c,
+		requestID:  requestID,
+		receivedAt: receivedAt,
+		query:      query,
+		channel:    types.ChannelAPI,
+	}
+	mode := qaModeNormal
+	model := request.Model
+	switch {
+	case strings.HasPrefix(model, openAIModelAgentPrefix):
+		agentID := strings.TrimPrefix(model, openAIModelAgentPrefix)
+		customAgent, effectiveTenantID, sharedAgentReadOnly := h.resolveAgent(ctx, c, agentID, 0)
+		if customAgent == nil {
+			respondOpenAIError(c, http.StatusNotFound, "invalid_request_error", "model_not_found",
+				fmt.Sprintf("model %s does not exist", model))
+			return
+		}
+		reqCtx.customAgent = customAgent
+		reqCtx.effectiveTenantID = effectiveTenantID
+		reqCtx.sharedAgentReadOnly = sharedAgentReadOnly
+		reqCtx.reqAgentID = agentID
+		if customAgent.IsAgentMode() {
+			mode = qaModeAgent
+			reqCtx.reqAgentEnabled = true
+		}
+	case strings.HasPrefix(model, openAIModelKBPrefix):
+		kbID := strings.TrimPrefix(model, openAIModelKBPrefix)
+		kb, err := h.knowledgebaseService.GetKnowledgeBaseByID(ctx, kbID)
+		if err != nil || kb == nil || kb.TenantID != c.GetUint64(types.TenantIDContextKey.String()) ||
+			types.AuthorizeTenantAPIKeyKnowledgeBases(ctx, kbID) != nil {
+			respondOpenAIError(c, http.StatusNotFound, "invalid_request_error", "model_not_found",
+				fmt.Sprintf("model %s does not exist", model))
+			return
+		}
+		reqCtx.knowledgeBaseIDs = []string{kbID}
+	default:
+		respondOpenAIError(c, http.StatusNotFound, "invalid_request_error", "model_not_found",
+			fmt.Sprintf("model %s does not exist; use agent:<id> or kb:<id>", model))
+		return
+	}
+
+	// Every completion runs in a fresh session seeded with the prior turns,
+	// since OpenAI clients resend the whole conversation on each request.
+	session, err := h.createOpenAISession(ctx, c, query, requestID, history)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", "failed to create session")
+		return
+	}
+	reqCtx.session = session
+	reqCtx.sessionID = session.ID
+
+	executionContext, agentID, agentTenantID, modelID := buildMessageExecutionContext(
+		ctx, reqCtx.customAgent, reqCtx.effectiveTenantID, "",
+		reqCtx.knowledgeBaseIDs, nil, nil, nil, nil, nil, false,
+	)
+	reqCtx.assistantMessage = &types.Message{
+		SessionID:        session.ID,
+		Role:             "assistant",
+		RequestID:        requestID,
+		IsCompleted:      false,
+		Channel:          types.ChannelAPI,
+		AgentID:          agentID,
+		AgentTenantID:    agentTenantID,
+		ModelID:          modelID,
+		ExecutionContext: executionContext,
+	}
+	logger.Infof(ctx, "[ChatCompletions] model=%s session=%s stream=%v history_turns=%d",
+		secutils.SanitizeForLog(model), session.ID, request.Stream, len(history))
+
+	streamCtx := h.startQA(reqCtx, mode, false)
+	if streamCtx == nil {
+		if len(c.Errors) > 0 {
+			respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", c.Errors.Last().Error())
+		} else {
+			respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", "failed to start completion")
+		}
+		return
+	}
+
+	completion := newOpenAICompletion("chatcmpl-"+reqCtx.assistantMessage.ID, request.Model)
+	if request.Stream {
+		h.streamOpenAICompletion(ctx, c, reqCtx, completion)
+		return
+	}
+	h.collectOpenAICompletion(ctx, c, reqCtx, completion)
+}
+
+// openAIHistoryTurn is a completed user/assistant exchange sent by the client
+type openAIHistoryTurn struct {
+	query  string
+	answer string
+}
+
+// splitOpenAIMessages returns the final user message as the query and the
+// earlier user/assistant pairs as history. System messages are dropped: the
+// agent or knowledge base configuration owns the system prompt.
+func splitOpenAIMessages(messages []OpenAIChatMessage) (string, []openAIHistoryTurn, error) {
+	var pending *string
+	var history []openAIHistoryTurn
+	query := ""
+	for i, msg := range messages {
+		text, err := openAIMessageText(msg.Content)
+		if err != nil {
+			return "", nil, fmt.Errorf("messages[%d].content: %w", i, err)
+		}
+		switch msg.Role {
+		case "user":
+			if i == len(messages)-1 {
+				query = text
+				continue
+			}
+			pending = &text
+		case "assistant":
+			if pending != nil {
+				history = append(history, openAIHistoryTurn{query: *pending, answer: text})
+				pending = nil
+			}
+		case "system", "developer", "tool":
+		default:
+			return "", nil, fmt.Errorf("messages[%d].role %q is not supported", i, msg.Role)
+		}
+	}
+	if strings.TrimSpace(query) == "" {
+		return "", nil, fmt.Errorf("the last message must be a non-empty user message")
+	}
+	return query, history, nil
+}
+
+// openAIMessageText flattens string or content-part message content to text
+func openAIMessageText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", fmt.Errorf("must be a string or an array of content parts")
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// createOpenAISession creates the session backing one completion and stores
+// the client-supplied history as completed turns, so the QA services load it
+// the same way they load a native conversation.
+func (h *Handler) createOpenAISession(
+	ctx context.Context, c *gin.Context, query, requestID string, history []openAIHistoryTurn,
+) (*types.Session, error) {
+	title := []rune(query)
+	if len(title) > openAISessionTitleRunes {
+		title = title[:openAISessionTitleRunes]
+	}
+	session := &types.Session{
+		TenantID: c.GetUint64(types.TenantIDContextKey.String()),
+		Title:    string(title),
+	}
+	if ownerID := types.SessionOwnerIDFromContext(ctx); ownerID != "" {
+		session.UserID = ownerID
+	}
+	session, err := h.sessionService.CreateSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// History is paired by request ID and ordered by creation time
+	start := time.Now().Add(-time.Duration(len(history)+1) * time.Second)
+	for i, turn := range history {
+		turnRequestID := fmt.Sprintf("%s-history-%d", requestID, i)
+		createdAt := start.Add(time.Duration(i) * time.Second)
+		for _, msg := range []*types.Message{
+			{Role: "user", Content: turn.query, CreatedAt: createdAt},
+			{Role: "assistant", Content: turn.answer, CreatedAt: createdAt.Add(time.Millisecond)},
+		} {
+			msg.SessionID = session.ID
+			msg.RequestID = turnRequestID
+			msg.IsCompleted = true
+			msg.Channel = types.ChannelAPI
+			if _, err := h.messageService.CreateMessage(ctx, msg); err != nil {
+				return nil, fmt.Errorf("store history turn %d: %w", i, err)
+			}
+		}
+	}
+	return session, nil
+}
+
+// openAICompletion folds the WeKnora stream events of one turn into an
+// OpenAI completion
+type openAICompletion struct {
+	id      string
+	model   string
+	created int64
+
+	content    strings.Builder
+	reasoning  strings.Builder
+	references types.References
+
+	done   bool
+	errMsg string
+}
+
+func newOpenAICompletion(id, model string) *openAICompletion {
+	return &openAICompletion{id: id, model: model, created: time.Now().Unix()}
+}
+
+// apply folds one stream event into the completion and returns the chunk to
+// stream for it, or nil when the event has no OpenAI counterpart
+func (o *openAICompletion) apply(evt interfaces.StreamEvent) *OpenAIChatCompletionResponse {
+	switch evt.Type {
+	case types.ResponseTypeAnswer:
+		if evt.Content == "" {
+			return nil
+		}
+		o.content.WriteString(evt.Content)
+		return o.chunk(&OpenAIChatCompletionMessage{Content: evt.Content}, nil, nil)
+	case types.ResponseTypeThinking:
+		if evt.Content == "" {
+			return nil
+		}
+		o.reasoning.WriteString(evt.Content)
+		return o.chunk(&OpenAIChatCompletionMessage{ReasoningContent: evt.Content}, nil, nil)
+	case types.ResponseTypeToolCall:
+		// Text streamed before a tool call was a preamble of a non-final
+		// agent round; the persisted answer drops it and so does the
+		// non-streaming completion.
+		o.content.Reset()
+		return nil
+	case types.ResponseTypeReferences:
+		o.references = buildStreamResponse(evt, "").KnowledgeReferences
+		return o.chunk(&OpenAIChatCompletionMessage{}, nil, o.references)
+	case types.ResponseTypeError:
+		o.done = true
+		o.errMsg = evt.Content
+		return nil
+	case types.ResponseTypeComplete, types.ResponseType(event.EventStop):
+		o.done = true
+		stop := "stop"
+		return o.chunk(&OpenAIChatCompletionMessage{}, &stop, nil)
+	}
+	return nil
+}
+
+func (o *openAICompletion) chunk(
+	delta *OpenAIChatCompletionMessage, finishReason *string, refs types.References,
+) *OpenAIChatCompletionResponse {
+	return &OpenAIChatCompletionResponse{
+		ID:         o.id,
+		Object:     "chat.completion.chunk",
+		Created:    o.created,
+		Model:      o.model,
+		Choices:    []OpenAIChatCompletionChoice{{Delta: delta, FinishReason: finishReason}},
+		References: refs,
+	}
+}
+
+// response builds the non-streaming completion
+func (o *openAICompletion) response() *OpenAIChatCompletionResponse {
+	stop := "stop"
+	return &OpenAIChatCompletionResponse{
+		ID:      o.id,
+		Object:  "chat.completion",
+		Created: o.created,
+		Model:   o.model,
+		Choices: []OpenAIChatCompletionChoice{{
+			Message: &OpenAIChatCompletionMessage{
+				Role:             "assistant",
+				Content:          o.content.String(),
+				ReasoningContent: o.reasoning.String(),
+			},
+			FinishReason: &stop,
+		}},
+		References: o.references,
+	}
+}
+
+// pollOpenAIEvents feeds the turn's stream events to onEvent until the
+// completion is done or the client goes away; returns false in the latter case
+func (h *Handler) pollOpenAIEvents(
+	ctx context.Context, c *gin.Context, reqCtx *qaRequestContext, completion *openAICompletion,
+	onEvent func(*OpenAIChatCompletionResponse),
+) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	offset := 0
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			logger.Infof(ctx, "Client disconnected from completion, session=%s", reqCtx.sessionID)
+			return false
+		case <-ticker.C:
+			events, next, err := h.streamManager.GetEvents(ctx, reqCtx.sessionID, reqCtx.assistantMessage.ID, offset)
+			if err != nil {
+				logger.Warnf(ctx, "Failed to get events from stream: %v", err)
+				continue
+			}
+			offset = next
+			for _, evt := range events {
+				if chunk := completion.apply(evt); chunk != nil {
+					onEvent(chunk)
+				}
+				if completion.done {
+					return true
+				}
+			}
+		}
+	}
+}
+
+// collectOpenAICompletion waits for the whole answer and returns one completion
+func (h *Handler) collectOpenAICompletion(
+	ctx context.Context, c *gin.Context, reqCtx *qaRequestContext, completion *openAICompletion,
+) {
+	if !h.pollOpenAIEvents(ctx, c, reqCtx, completion, func(*OpenAIChatCompletionResponse) {}) {
+		return
+	}
+	// startQA set SSE headers for the stream path; this reply is plain JSON
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	if completion.errMsg != "" {
+		respondOpenAIError(c, http.StatusInternalServerError, "server_error", "", completion.errMsg)
+		return
+	}
+	c.JSON(http.StatusOK, completion.response())
+}
+
+// streamOpenAICompletion relays the answer as OpenAI stream chunks
+func (h *Handler) streamOpenAICompletion(
+	ctx context.Context, c *gin.Context, reqCtx *qaRequestContext, completion *openAICompletion,
+) {
+	c.Status(http.StatusOK)
+	writeData := func(v interface{}) {
+		payload, err := json.Marshal(v)
+		if err != nil {
+			logger.Errorf(ctx, "Failed to encode completion chunk: %v", err)
+			return
+		}
+		fmt.Fprintf(c.Writer, "data: %s\n\n", payload)
+		c.Writer.Flush()
+	}
+
+	writeData(completion.chunk(&OpenAIChatCompletionMessage{Role: "assistant"}, nil, nil))
+	if !h.pollOpenAIEvents(ctx, c, reqCtx, completion, func(chunk *OpenAIChatCompletionResponse) { writeData(chunk) }) {
+		return
+	}
+	if completion.errMsg != "" {
+		writeData(gin.H{"error": openAIErrorBody{Message: completion.errMsg, Type: "server_error"}})
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
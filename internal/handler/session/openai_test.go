@@ -0,0 +1,108 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+func openAIMessages(t *testing.T, raw string) []OpenAIChatMessage {
+	t.Helper()
+	var messages []OpenAIChatMessage
+	if err := json.Unmarshal([]byte(raw), &messages); err != nil {
+		t.Fatalf("unmarshal messages: %v", err)
+	}
+	return messages
+}
+
+func TestSplitOpenAIMessages(t *testing.T) {
+	messages := openAIMessages(t, `[
+		{"role":"system","content":"be nice"},
+		{"role":"user","content":"first"},
+		{"role":"assistant","content":"first answer"},
+		{"role":"user","content":[{"type":"text","text":"second"},{"type":"image_url","image_url":{"url":"x"}},{"type":"text","text":"part"}]}
+	]`)
+	query, history, err := splitOpenAIMessages(messages)
+	if err != nil {
+		t.Fatalf("splitOpenAIMessages: %v", err)
+	}
+	if query != "second\npart" {
+		t.Errorf("query = %q", query)
+	}
+	if len(history) != 1 || history[0].query != "first" || history[0].answer != "first answer" {
+		t.Errorf("history = %+v", history)
+	}
+}
+
+func TestSplitOpenAIMessagesRejectsInvalidConversations(t *testing.T) {
+	for name, raw := range map[string]string{
+		"last not user":  `[{"role":"user","content":"q"},{"role":"assistant","content":"a"}]`,
+		"empty query":    `[{"role":"user","content":"  "}]`,
+		"unknown role":   `[{"role":"robot","content":"x"},{"role":"user","content":"q"}]`,
+		"bad content":    `[{"role":"user","content":42}]`,
+		"empty messages": `[]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := splitOpenAIMessages(openAIMessages(t, raw)); err == nil {
+				t.Fatal("splitOpenAIMessages() error = nil")
+			}
+		})
+	}
+}
+
+func TestOpenAICompletionFoldsStreamEvents(t *testing.T) {
+	completion := newOpenAICompletion("chatcmpl-1", "agent:a1")
+	refs := types.References{{ID: "chunk-1", Content: "ctx"}}
+	events := []interfaces.StreamEvent{
+		{Type: types.ResponseTypeThinking, Content: "hmm"},
+		{Type: types.ResponseTypeAnswer, Content: "let me search"},
+		{Type: types.ResponseTypeToolCall},
+		{Type: types.ResponseTypeReferences, Data: map[string]interface{}{"references": refs}},
+		{Type: types.ResponseTypeAnswer, Content: "Hello"},
+		{Type: types.ResponseTypeAnswer, Content: " world"},
+		{Type: types.ResponseTypeComplete, Done: true},
+	}
+	var chunks []*OpenAIChatCompletionResponse
+	for _, evt := range events {
+		if chunk := completion.apply(evt); chunk != nil {
+			chunks = append(chunks, chunk)
+		}
+	}
+	if !completion.done {
+		t.Fatal("completion not done after complete event")
+	}
+	// thinking, preamble answer, references, two answers, finish
+	if len(chunks) != 6 {
+		t.Fatalf("got %d chunks, want 6", len(chunks))
+	}
+	if chunks[0].Choices[0].Delta.ReasoningContent != "hmm" {
+		t.Errorf("first chunk = %+v", chunks[0].Choices[0].Delta)
+	}
+	if len(chunks[2].References) != 1 || chunks[2].References[0].ID != "chunk-1" {
+		t.Errorf("references chunk = %+v", chunks[2])
+	}
+	last := chunks[len(chunks)-1]
+	if last.Choices[0].FinishReason == nil || *last.Choices[0].FinishReason != "stop" {
+		t.Errorf("last chunk finish_reason = %v", last.Choices[0].FinishReason)
+	}
+
+	resp := completion.response()
+	if resp.Object != "chat.completion" || resp.Choices[0].Message.Content != "Hello world" {
+		t.Errorf("response = %+v", resp.Choices[0].Message)
+	}
+	if resp.Choices[0].Message.ReasoningContent != "hmm" || len(resp.References) != 1 {
+		t.Errorf("response reasoning/references = %+v / %+v", resp.Choices[0].Message, resp.References)
+	}
+}
+
+func TestOpenAICompletionStopsOnError(t *testing.T) {
+	completion := newOpenAICompletion("chatcmpl-1", "kb:k1")
+	if chunk := completion.apply(interfaces.StreamEvent{Type: types.ResponseTypeError, Content: "boom", Done: true}); chunk != nil {
+		t.Errorf("error event produced chunk %+v", chunk)
+	}
+	if !completion.done || completion.errMsg != "boom" {
+		t.Errorf("done=%v errMsg=%q", completion.done, completion.errMsg)
+	}
+}
@@ -48,6 +48,8 @@ type qaRequestContext struct {
 	skillNames            []string
 	summaryModelID        string
 	webSearchEnabled      bool
+	retrievalMode         string
+	metadataFilter        *types.MetadataFilter
 	mentionedItems        types.MentionedItems
 	effectiveTenantID     uint64                   // when using shared agent, tenant ID for model/KB/MCP resolution; 0 = use context tenant
 	sharedAgentReadOnly   bool                     // access was granted by a read-only agent share
@@ -89,6 +91,8 @@ func (rc *qaRequestContext) buildQARequest() *types.QARequest {
 		ImageDescription:    imageDescription,
 		UserMessageID:       rc.userMessageID,
 		WebSearchEnabled:    rc.webSearchEnabled,
+		RetrievalMode:       rc.retrievalMode,
+		MetadataFilter:      rc.metadataFilter,
 		Attachments:         rc.attachments,
 	}
 }
@@ -169,6 +173,10 @@ func (h *Handler) parseQARequest(c *gin.Context, logPrefix string) (*qaRequestCo
 	if err := types.AuthorizeTenantAPIKeyKnowledgeTargets(ctx, kbIDs, knowledgeIDs); err != nil {
 		return nil, nil, err
 	}
+	metadataFilter, err := request.MetadataFilter.Normalize()
+	if err != nil {
+		return nil, nil, errors.NewBadRequestError(err.Error())
+	}
 
 	// The built-in wiki fixer is invoked from a KB page, not from a tenant's
 	// regular agent picker. When the KB is shared, run it in the source tenant
@@ -375,6 +383,8 @@ func (h *Handler) parseQARequest(c *gin.Context, logPrefix string) (*qaRequestCo
 		skillNames:            secutils.SanitizeForLogArray(skillNames),
 		summaryModelID:        secutils.SanitizeForLog(request.SummaryModelID),
 		webSearchEnabled:      request.WebSearchEnabled,
+		retrievalMode:         types.NormalizeRetrievalMode(request.RetrievalMode),
+		metadataFilter:        metadataFilter,
 		mentionedItems:        convertMentionedItems(request.MentionedItems),
 		effectiveTenantID:     effectiveTenantID,
 		sharedAgentReadOnly:   sharedAgentReadOnly,
@@ -881,6 +891,22 @@ const (
 // executeQA is the unified execution flow for both KnowledgeQA and AgentQA modes.
 // It handles message creation, SSE setup, VLM analysis, service invocation, and error handling.
 func (h *Handler) executeQA(reqCtx *qaRequestContext, mode qaMode, generateTitle bool) {
+	streamCtx := h.startQA(reqCtx, mode, generateTitle)
+	if streamCtx == nil {
+		return
+	}
+
+	// Handle SSE events (blocking)
+	shouldWaitForTitle := generateTitle && reqCtx.session.Title == ""
+	h.handleAgentEventsForSSE(reqCtx.ctx, reqCtx.c, reqCtx.sessionID, reqCtx.assistantMessage.ID,
+		reqCtx.requestID, streamCtx.eventBus, shouldWaitForTitle, reqCtx.resourceRewriter)
+}
+
+// startQA creates the turn's messages and starts the QA service in the
+// background. Its events land in the stream manager under the assistant
+// message, ready for the caller to relay. Returns nil when the turn could not
+// be started; the error has then already been attached to the gin context.
+func (h *Handler) startQA(reqCtx *qaRequestContext, mode qaMode, generateTitle bool) *sseStreamContext {
 	ctx := reqCtx.ctx
 	sessionID := reqCtx.sessionID
 
@@ -904,7 +930,7 @@ func (h *Handler) executeQA(reqCtx *qaRequestContext, mode qaMode, generateTitle
 			},
 		}); err != nil {
 			logger.Errorf(ctx, "Failed to emit agent query event: %v", err)
-			return
+			return nil
 		}
 	}
 
@@ -917,7 +943,7 @@ func (h *Handler) executeQA(reqCtx *qaRequestContext, mode qaMode, generateTitle
 	userMsg, err := h.createUserMessage(ctx, sessionID, reqCtx.query, reqCtx.requestID, reqCtx.mentionedItems, convertImageAttachments(reqCtx.images), userMessageAttachments, reqCtx.channel, reqCtx.suggestionAttribution)
 	if err != nil {
 		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+		return nil
 	}
 	reqCtx.userMessageID = userMsg.ID
 
@@ -925,7 +951,7 @@ func (h *Handler) executeQA(reqCtx *qaRequestContext, mode qaMode, generateTitle
 	assistantMessagePtr, err := h.createAssistantMessage(ctx, reqCtx.assistantMessage)
 	if err != nil {
 		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+		return nil
 	}
 	reqCtx.assistantMessage = assistantMessagePtr
 
@@ -1061,10 +1087,7 @@ func (h *Handler) executeQA(reqCtx *qaRequestContext, mode qaMode, generateTitle
 		}
 	}()
 
-	// Handle SSE events (blocking)
-	shouldWaitForTitle := generateTitle && reqCtx.session.Title == ""
-	h.handleAgentEventsForSSE(ctx, reqCtx.c, sessionID, reqCtx.assistantMessage.ID,
-		reqCtx.requestID, streamCtx.eventBus, shouldWaitForTitle, reqCtx.resourceRewriter)
+	return streamCtx
 }
 
 // runVLMAnalysisIfNeeded runs VLM image analysis within the async goroutine,
@@ -325,7 +325,7 @@ func (h *SystemHandler) GetSystemInfo(c *gin.Context) {
 	// Get vector store engine from config or RETRIEVE_DRIVER
 	vectorStoreEngine := h.getVectorStoreEngine()
 
-	// Get graph database engine from NEO4J_ENABLE
+	// Get graph database engine from GRAPH_DRIVER / NEO4J_ENABLE
 	graphDatabaseEngine := h.getGraphDatabaseEngine()
 
 	// Get MinIO enabled status
@@ -609,10 +609,18 @@ func (h *SystemHandler) getVectorStoreEngine() string {
 
 // getGraphDatabaseEngine returns the graph database engine name
 func (h *SystemHandler) getGraphDatabaseEngine() string {
-	if h.neo4jDriver == nil {
-		return "Not Enabled"
+	switch types.GraphDriver() {
+	case types.GraphDriverNeo4j:
+		if h.neo4jDriver != nil {
+			return "Neo4j"
+		}
+	case types.GraphDriverDatabase:
+		if os.Getenv("DB_DRIVER") == "sqlite" {
+			return "SQLite"
+		}
+		return "PostgreSQL"
 	}
-	return "Neo4j"
+	return "Not Enabled"
 }
 
 // supportsRetrieverType checks if a driver supports a specific retriever type
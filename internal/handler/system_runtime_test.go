@@ -237,9 +237,9 @@ func TestGetRuntimeQueuesReportsIsolatedPoolCapacity(t *testing.T) {
 	}{
 		types.WorkerPoolCore:        {8, 2},
 		types.WorkerPoolPostProcess: {2, 1},
-		types.WorkerPoolEnrichment:  {12, 5},
-		types.WorkerPoolMaintenance: {4, 2},
-		types.WorkerPoolShared:      {6, 7},
+		types.WorkerPoolEnrichment:  {12, 6},
+		types.WorkerPoolMaintenance: {4, 3},
+		types.WorkerPoolShared:      {6, 8},
 		types.WorkerPoolWiki:        {8, 1},
 	}
 	if len(response.Pools) != len(want) {
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// UserGroupHandler manages the user groups of a tenant that document access
+// lists can grant
+type UserGroupHandler struct {
+	service interfaces.UserGroupService
+}
+
+// NewUserGroupHandler creates a new UserGroupHandler instance
+func NewUserGroupHandler(service interfaces.UserGroupService) *UserGroupHandler {
+	return &UserGroupHandler{service: service}
+}
+
+type userGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type userGroupMembersRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// ListGroups godoc
+// @Summary      获取用户组列表
+// @Description  列出当前租户的用户组及成员数
+// @Tags         访问控制
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "用户组列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups [get]
+func (h *UserGroupHandler) ListGroups(c *gin.Context) {
+	groups, err := h.service.ListGroups(c.Request.Context())
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    groups,
+	})
+}
+
+// CreateGroup godoc
+// @Summary      创建用户组
+// @Description  在当前租户下创建用户组
+// @Tags         访问控制
+// @Accept       json
+// @Produce      json
+// @Param        request  body      map[string]interface{}  true  "{name, description}"
+// @Success      201      {object}  map[string]interface{}  "创建的用户组"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups [post]
+func (h *UserGroupHandler) CreateGroup(c *gin.Context) {
+	var req userGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	group, err := h.service.CreateGroup(c.Request.Context(), &types.UserGroup{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    group,
+	})
+}
+
+// GetGroup godoc
+// @Summary      获取用户组详情
+// @Description  返回用户组及其成员
+// @Tags         访问控制
+// @Produce      json
+// @Param        id   path      string  true  "用户组ID"
+// @Success      200  {object}  map[string]interface{}  "用户组"
+// @Failure      404  {object}  errors.AppError         "用户组不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups/{id} [get]
+func (h *UserGroupHandler) GetGroup(c *gin.Context) {
+	group, err := h.service.GetGroup(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    group,
+	})
+}
+
+// UpdateGroup godoc
+// @Summary      更新用户组
+// @Description  修改用户组的名称与描述
+// @Tags         访问控制
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "用户组ID"
+// @Param        request  body      map[string]interface{}  true  "{name, description}"
+// @Success      200      {object}  map[string]interface{}  "更新后的用户组"
+// @Failure      404      {object}  errors.AppError         "用户组不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups/{id} [put]
+func (h *UserGroupHandler) UpdateGroup(c *gin.Context) {
+	var req userGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	group, err := h.service.UpdateGroup(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), req.Name, req.Description)
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    group,
+	})
+}
+
+// DeleteGroup godoc
+// @Summary      删除用户组
+// @Description  删除用户组及其成员关系；仍被访问控制列表引用的用户组不能删除
+// @Tags         访问控制
+// @Produce      json
+// @Param        id   path      string  true  "用户组ID"
+// @Success      200  {object}  map[string]interface{}  "删除成功"
+// @Failure      409  {object}  errors.AppError         "用户组仍被引用"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups/{id} [delete]
+func (h *UserGroupHandler) DeleteGroup(c *gin.Context) {
+	if err := h.service.DeleteGroup(c.Request.Context(), secutils.SanitizeForLog(c.Param("id"))); err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// SetGroupMembers godoc
+// @Summary      设置用户组成员
+// @Description  整体替换用户组成员，成员必须是当前租户的有效成员
+// @Tags         访问控制
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "用户组ID"
+// @Param        request  body      map[string]interface{}  true  "{user_ids: []}"
+// @Success      200      {object}  map[string]interface{}  "更新后的用户组"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /user-groups/{id}/members [put]
+func (h *UserGroupHandler) SetGroupMembers(c *gin.Context) {
+	var req userGroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	group, err := h.service.SetGroupMembers(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")), req.UserIDs)
+	if err != nil {
+		respondACLError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    group,
+	})
+}
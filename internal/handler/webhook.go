@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages the outbound webhook subscriptions of a tenant and
+// their delivery log
+type WebhookHandler struct {
+	service interfaces.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(service interfaces.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// webhookSubscriptionRequest is the body of create and update. Enabled
+// defaults to true; a nil secret keeps the stored secret on update and an
+// empty one clears it.
+type webhookSubscriptionRequest struct {
+	Name             string   `json:"name"`
+	URL              string   `json:"url"`
+	Secret           *string  `json:"secret"`
+	EventTypes       []string `json:"event_types"`
+	KnowledgeBaseIDs []string `json:"knowledge_base_ids"`
+	Enabled          *bool    `json:"enabled"`
+}
+
+func (r *webhookSubscriptionRequest) toSubscription() *types.WebhookSubscription {
+	sub := &types.WebhookSubscription{
+		Name:             r.Name,
+		URL:              r.URL,
+		EventTypes:       types.StringArray(r.EventTypes),
+		KnowledgeBaseIDs: types.StringArray(r.KnowledgeBaseIDs),
+		Enabled:          r.Enabled == nil || *r.Enabled,
+	}
+	if r.Secret != nil {
+		sub.Secret = *r.Secret
+	}
+	return sub
+}
+
+// respondWebhookError passes user-facing AppErrors through and wraps the rest
+func respondWebhookError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// ListSubscriptions godoc
+// @Summary      获取 Webhook 订阅列表
+// @Description  列出当前租户的 Webhook 订阅，签名密钥只以 has_secret 标记返回
+// @Tags         Webhook
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "订阅列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subs, err := h.service.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    subs,
+	})
+}
+
+// CreateSubscription godoc
+// @Summary      创建 Webhook 订阅
+// @Description  订阅知识创建/解析完成/解析失败/删除、知识库变更、数据源同步结束、Wiki 页面修订与评估完成事件；event_types 与 knowledge_base_ids 为空时不过滤。设置 secret 后请求体以 HMAC-SHA256 签名
+// @Tags         Webhook
+// @Accept       json
+// @Produce      json
+// @Param        request  body      map[string]interface{}  true  "订阅配置"
+// @Success      201      {object}  map[string]interface{}  "创建的订阅"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req webhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	sub, err := h.service.CreateSubscription(c.Request.Context(), req.toSubscription())
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    sub,
+	})
+}
+
+// GetSubscription godoc
+// @Summary      获取 Webhook 订阅详情
+// @Description  获取当前租户的一个 Webhook 订阅
+// @Tags         Webhook
+// @Produce      json
+// @Param        id   path      string  true  "订阅ID"
+// @Success      200  {object}  map[string]interface{}  "订阅"
+// @Failure      404  {object}  errors.AppError         "订阅不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.service.GetSubscription(c.Request.Context(), secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sub,
+	})
+}
+
+// UpdateSubscription godoc
+// @Summary      更新 Webhook 订阅
+// @Description  整体替换订阅配置；不传 secret 时保留原密钥，传空字符串时清除
+// @Tags         Webhook
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "订阅ID"
+// @Param        request  body      map[string]interface{}  true  "订阅配置"
+// @Success      200      {object}  map[string]interface{}  "更新后的订阅"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Failure      404      {object}  errors.AppError         "订阅不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	var req webhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	sub, err := h.service.UpdateSubscription(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), req.toSubscription(), req.Secret)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sub,
+	})
+}
+
+// DeleteSubscription godoc
+// @Summary      删除 Webhook 订阅
+// @Description  删除订阅，尚未完成的投递不再发送，投递记录保留
+// @Tags         Webhook
+// @Produce      json
+// @Param        id   path      string  true  "订阅ID"
+// @Success      200  {object}  map[string]interface{}  "删除成功"
+// @Failure      404  {object}  errors.AppError         "订阅不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c.Request.Context(), secutils.SanitizeForLog(c.Param("id"))); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// ListDeliveries godoc
+// @Summary      获取 Webhook 投递记录
+// @Description  分页列出订阅的投递记录，按创建时间倒序，包含请求体、尝试次数与最近一次响应
+// @Tags         Webhook
+// @Produce      json
+// @Param        id         path      string  true   "订阅ID"
+// @Param        status     query     string  false  "投递状态：pending、retrying、success、failed"
+// @Param        page       query     int     false  "页码"
+// @Param        page_size  query     int     false  "每页数量"
+// @Success      200        {object}  map[string]interface{}  "投递记录"
+// @Failure      404        {object}  errors.AppError         "订阅不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var page types.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+	result, err := h.service.ListDeliveries(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), c.Query("status"), &page)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ReplayDelivery godoc
+// @Summary      重放 Webhook 投递
+// @Description  以原请求体（事件ID不变）重新投递一次，生成新的投递记录并返回
+// @Tags         Webhook
+// @Produce      json
+// @Param        id           path      string  true  "订阅ID"
+// @Param        delivery_id  path      string  true  "投递ID"
+// @Success      202          {object}  map[string]interface{}  "新的投递记录"
+// @Failure      404          {object}  errors.AppError         "订阅或投递不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	delivery, err := h.service.ReplayDelivery(c.Request.Context(),
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("delivery_id")))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
@@ -612,6 +612,8 @@ func (s *Service) prepareIMAttachments(ctx context.Context, msg *IncomingMessage
 		result, err = (&docparser.SimpleFormatReader{}).Read(attachmentCtx, request)
 	} else if result == nil && !isImage && s.documentReader != nil {
 		result, err = s.documentReader.Read(attachmentCtx, request)
+	} else if result == nil && docparser.IsOfficeFormat(attachment.FileType) {
+		result, err = (&docparser.SimpleFormatReader{}).Read(attachmentCtx, request)
 	}
 	if err != nil {
 		logger.Warnf(ctx, "[IM] attachment parsing failed, continuing with attachment metadata: %v", err)
@@ -2739,6 +2741,38 @@ func (s *Service) fallbackNonStream(ctx context.Context, msg *IncomingMessage, s
 	return adapter.SendReply(ctx, msg, &ReplyMessage{Content: formatIMOutboundAnswer(ctx, answer, tenant, s.defaultFileSvc, s.storageResolver), IsFinal: true})
 }
 
+// SendChannelMessage pushes a message that does not answer an incoming one,
+// e.g. the output of a scheduled agent run, to a chat of a channel. chatID
+// addresses a group chat; userID a direct chat when chatID is empty.
+// Platforms that can only reply inside a callback (DingTalk session
+// webhooks) return the adapter's error.
+func (s *Service) SendChannelMessage(ctx context.Context, tenantID uint64, channelID, chatID, userID, content string) error {
+	if _, err := s.GetChannelByIDAndTenant(channelID, tenantID); err != nil {
+		return fmt.Errorf("load channel: %w", err)
+	}
+	adapter, channel, err := s.EnsureChannelAdapter(channelID)
+	if err != nil {
+		return err
+	}
+	tenant, err := s.tenantService.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("load tenant: %w", err)
+	}
+	msg := &IncomingMessage{
+		Platform: Platform(channel.Platform),
+		UserID:   userID,
+		ChatID:   chatID,
+		ChatType: ChatTypeDirect,
+	}
+	if chatID != "" {
+		msg.ChatType = ChatTypeGroup
+	}
+	return adapter.SendReply(ctx, msg, &ReplyMessage{
+		Content: formatIMOutboundAnswer(ctx, content, tenant, s.defaultFileSvc, s.storageResolver),
+		IsFinal: true,
+	})
+}
+
 // runQA executes the WeKnora QA pipeline and returns the full answer text.
 func (s *Service) runQA(ctx context.Context, session *types.Session, query string, customAgent *types.CustomAgent, kbIDs []string, attachments types.MessageAttachments, imageURLs []string, userKey string, quote *QuotedMessage) (string, error) {
 	// Cancellable context (no hard deadline): each agent round has its own
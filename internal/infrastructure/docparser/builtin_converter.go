@@ -42,8 +42,9 @@ func IsSimpleFormat(fileType string) bool {
 	return simpleFormats[strings.ToLower(strings.TrimPrefix(fileType, "."))]
 }
 
-// SimpleFormatReader handles simple file formats and images directly in Go,
-// bypassing the Python docreader service.
+// SimpleFormatReader handles simple file formats, images, and the common
+// office formats (docx, xlsx, pptx, html) directly in Go, bypassing the
+// Python docreader service.
 type SimpleFormatReader struct{}
 
 // Read reads simple format files and returns markdown.
@@ -70,6 +71,30 @@ func (b *SimpleFormatReader) Read(_ context.Context, req *types.ReadRequest) (*t
 			return nil, fmt.Errorf("json conversion failed: %w", err)
 		}
 		return &types.ReadResult{MarkdownContent: md}, nil
+	case ft == "docx":
+		md, images, err := docxToMarkdown(req.FileContent)
+		if err != nil {
+			return nil, fmt.Errorf("docx conversion failed: %w", err)
+		}
+		return officeResult(ft, md, images), nil
+	case ft == "xlsx":
+		md, err := xlsxToMarkdown(req.FileContent)
+		if err != nil {
+			return nil, fmt.Errorf("xlsx conversion failed: %w", err)
+		}
+		return officeResult(ft, md, nil), nil
+	case ft == "pptx":
+		md, images, err := pptxToMarkdown(req.FileContent)
+		if err != nil {
+			return nil, fmt.Errorf("pptx conversion failed: %w", err)
+		}
+		return officeResult(ft, md, images), nil
+	case ft == "html" || ft == "htm":
+		md, err := htmlPageToMarkdown(req.FileContent)
+		if err != nil {
+			return nil, fmt.Errorf("html conversion failed: %w", err)
+		}
+		return officeResult("html", md, nil), nil
 	case imageFormats[ft]:
 		return imageToResult(req.FileName, req.FileContent), nil
 	case audioFormats[ft]:
@@ -79,6 +104,18 @@ func (b *SimpleFormatReader) Read(_ context.Context, req *types.ReadRequest) (*t
 	}
 }
 
+// officeResult wraps the markdown of a document converted in Go.
+func officeResult(format, md string, images []types.ImageRef) *types.ReadResult {
+	return &types.ReadResult{
+		MarkdownContent: md,
+		ImageRefs:       images,
+		Metadata: map[string]string{
+			"parser":        SimpleEngineName,
+			"source_format": format,
+		},
+	}
+}
+
 // imageToResult wraps a standalone image as a markdown image reference with
 // the raw bytes in ImageRefs, matching Python ImageParser behaviour.
 func imageToResult(fileName string, data []byte) *types.ReadResult {
@@ -0,0 +1,395 @@
+package docparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// docxConverter renders word/document.xml as markdown: headings from
+// paragraph styles and outline levels, lists from numbering, bold/italic
+// runs, hyperlinks, tables, and embedded images placed where they appear.
+type docxConverter struct {
+	pkg    *ooxmlPackage
+	rels   map[string]ooxmlRelationship
+	images *officeImages
+	// headingLevels maps paragraph style IDs to heading levels (1-6).
+	headingLevels map[string]int
+	// orderedLists maps numId/ilvl pairs to whether the level is numbered
+	// (as opposed to bulleted).
+	orderedLists map[string]bool
+}
+
+// docxToMarkdown converts a .docx file to markdown plus its embedded images.
+func docxToMarkdown(data []byte) (string, []types.ImageRef, error) {
+	pkg, err := openOOXMLPackage(data)
+	if err != nil {
+		return "", nil, err
+	}
+	mainPart := "word/document.xml"
+	if rels, err := pkg.relationships(""); err == nil {
+		for _, rel := range rels {
+			if strings.HasSuffix(rel.Type, "/officeDocument") && !rel.External {
+				mainPart = rel.Target
+			}
+		}
+	}
+	doc, err := pkg.xmlPart(mainPart)
+	if err != nil {
+		return "", nil, err
+	}
+	if doc == nil {
+		return "", nil, fmt.Errorf("not a word document: %s not found", mainPart)
+	}
+	rels, err := pkg.relationships(mainPart)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &docxConverter{
+		pkg:           pkg,
+		rels:          rels,
+		images:        newOfficeImages(pkg),
+		headingLevels: map[string]int{},
+		orderedLists:  map[string]bool{},
+	}
+	for _, rel := range rels {
+		switch {
+		case strings.HasSuffix(rel.Type, "/styles"):
+			c.loadStyles(rel.Target)
+		case strings.HasSuffix(rel.Type, "/numbering"):
+			c.loadNumbering(rel.Target)
+		}
+	}
+
+	var blocks []string
+	c.convertBlocks(doc.child("body"), &blocks)
+	return strings.Join(blocks, "\n\n"), c.images.refs, nil
+}
+
+// loadStyles records the heading level of every paragraph style. A style is a
+// heading when its name is "heading N" or "Title", or when it sets an
+// outline level. Unreadable styles only cost the headings.
+func (c *docxConverter) loadStyles(part string) {
+	styles, err := c.pkg.xmlPart(part)
+	if err != nil || styles == nil {
+		return
+	}
+	for _, style := range styles.childrenNamed("style") {
+		if style.attr("type") != "paragraph" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(style.child("name").attr("val")))
+		level := 0
+		switch {
+		case name == "title":
+			level = 1
+		case strings.HasPrefix(name, "heading "):
+			level, _ = strconv.Atoi(strings.TrimPrefix(name, "heading "))
+		default:
+			level = outlineHeadingLevel(style.path("pPr", "outlineLvl"))
+		}
+		if level > 0 {
+			c.headingLevels[style.attr("styleId")] = min(level, 6)
+		}
+	}
+}
+
+// loadNumbering records which list levels are numbered.
+func (c *docxConverter) loadNumbering(part string) {
+	numbering, err := c.pkg.xmlPart(part)
+	if err != nil || numbering == nil {
+		return
+	}
+	abstract := map[string]map[string]bool{}
+	for _, def := range numbering.childrenNamed("abstractNum") {
+		levels := map[string]bool{}
+		for _, lvl := range def.childrenNamed("lvl") {
+			format := lvl.child("numFmt").attr("val")
+			levels[lvl.attr("ilvl")] = format != "" && format != "bullet" && format != "none"
+		}
+		abstract[def.attr("abstractNumId")] = levels
+	}
+	for _, num := range numbering.childrenNamed("num") {
+		for ilvl, ordered := range abstract[num.child("abstractNumId").attr("val")] {
+			c.orderedLists[num.attr("numId")+"/"+ilvl] = ordered
+		}
+	}
+}
+
+// outlineHeadingLevel maps a w:outlineLvl (0-based, 9 meaning body text) to
+// a heading level, 0 when there is none.
+func outlineHeadingLevel(outline *xmlNode) int {
+	if outline == nil {
+		return 0
+	}
+	lvl, err := strconv.Atoi(outline.attr("val"))
+	if err != nil || lvl < 0 || lvl > 8 {
+		return 0
+	}
+	return min(lvl+1, 6)
+}
+
+// convertBlocks appends the markdown of the paragraphs and tables in a block
+// container (body, structured document tag content, ...).
+func (c *docxConverter) convertBlocks(container *xmlNode, blocks *[]string) {
+	if container == nil {
+		return
+	}
+	for _, n := range container.Children {
+		switch n.Name {
+		case "p":
+			if md := c.paragraph(n); md != "" {
+				*blocks = append(*blocks, md)
+			}
+		case "tbl":
+			if md := c.table(n); md != "" {
+				*blocks = append(*blocks, md)
+			}
+		case "sdt":
+			c.convertBlocks(n.child("sdtContent"), blocks)
+		case "customXml", "ins", "smartTag":
+			c.convertBlocks(n, blocks)
+		}
+	}
+}
+
+func (c *docxConverter) paragraph(p *xmlNode) string {
+	pPr := p.child("pPr")
+	level := c.headingLevels[pPr.child("pStyle").attr("val")]
+	if lvl := outlineHeadingLevel(pPr.child("outlineLvl")); lvl > 0 {
+		level = lvl
+	}
+
+	if level > 0 {
+		text := strings.TrimSpace(c.inline(p, false))
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "\n", " ")
+	}
+
+	text := strings.TrimSpace(c.inline(p, true))
+	if text == "" {
+		return ""
+	}
+	if numPr := pPr.child("numPr"); numPr != nil {
+		numID := numPr.child("numId").attr("val")
+		if numID != "" && numID != "0" {
+			ilvl := numPr.child("ilvl").attr("val")
+			if ilvl == "" {
+				ilvl = "0"
+			}
+			depth, _ := strconv.Atoi(ilvl)
+			marker := "- "
+			if c.orderedLists[numID+"/"+ilvl] {
+				marker = "1. "
+			}
+			indent := strings.Repeat("    ", max(depth, 0))
+			return indent + marker + strings.ReplaceAll(text, "\n", "\n"+indent+"  ")
+		}
+	}
+	return text
+}
+
+// docxSpan is a run of text sharing the same formatting.
+type docxSpan struct {
+	text         string
+	bold, italic bool
+	link         string
+	// raw spans (images) are written as is, never wrapped in emphasis.
+	raw bool
+}
+
+// inline renders the runs of a paragraph. Formatting is dropped when
+// emphasis is false, as for headings.
+func (c *docxConverter) inline(p *xmlNode, emphasis bool) string {
+	var spans []docxSpan
+	c.collectSpans(p, "", &spans)
+
+	// Merge neighbours with identical formatting so that a word split over
+	// several runs does not come out as **a****b**.
+	var merged []docxSpan
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.raw && !merged[n-1].raw &&
+			merged[n-1].bold == s.bold && merged[n-1].italic == s.italic && merged[n-1].link == s.link {
+			merged[n-1].text += s.text
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	var sb strings.Builder
+	for _, s := range merged {
+		if s.raw {
+			sb.WriteString(s.text)
+			continue
+		}
+		text := s.text
+		if emphasis {
+			text = emphasize(text, s.bold, s.italic)
+		}
+		if s.link != "" && strings.TrimSpace(text) != "" {
+			text = "[" + text + "](" + s.link + ")"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// emphasize wraps the non-blank part of text in bold/italic markers, keeping
+// surrounding spaces outside so the markers stay valid markdown.
+func emphasize(text string, bold, italic bool) string {
+	if !bold && !italic {
+		return text
+	}
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	marker := "*"
+	switch {
+	case bold && italic:
+		marker = "***"
+	case bold:
+		marker = "**"
+	}
+	start := strings.Index(text, core)
+	return text[:start] + marker + core + marker + text[start+len(core):]
+}
+
+func (c *docxConverter) collectSpans(n *xmlNode, link string, spans *[]docxSpan) {
+	if n == nil {
+		return
+	}
+	for _, child := range n.Children {
+		switch child.Name {
+		case "r":
+			c.runSpans(child, link, spans)
+		case "hyperlink":
+			target := link
+			if rel, ok := c.rels[child.relID()]; ok && rel.External {
+				target = rel.Target
+			}
+			c.collectSpans(child, target, spans)
+		case "ins", "smartTag", "fldSimple", "customXml":
+			c.collectSpans(child, link, spans)
+		case "sdt":
+			c.collectSpans(child.child("sdtContent"), link, spans)
+		}
+	}
+}
+
+func (c *docxConverter) runSpans(r *xmlNode, link string, spans *[]docxSpan) {
+	rPr := r.child("rPr")
+	span := docxSpan{bold: toggleOn(rPr.child("b")), italic: toggleOn(rPr.child("i")), link: link}
+	flush := func() {
+		if span.text != "" {
+			*spans = append(*spans, span)
+			span.text = ""
+		}
+	}
+	for _, child := range r.Children {
+		switch child.Name {
+		case "t":
+			span.text += child.Text
+		case "tab", "ptab":
+			span.text += " "
+		case "br", "cr":
+			if child.attr("type") != "page" {
+				span.text += "\n"
+			}
+		case "noBreakHyphen":
+			span.text += "-"
+		case "drawing", "pict", "object":
+			flush()
+			for _, img := range c.runImages(child) {
+				*spans = append(*spans, docxSpan{text: img, raw: true})
+			}
+		}
+	}
+	flush()
+}
+
+// runImages returns the markdown of the images in a drawing: DrawingML
+// pictures (a:blip r:embed) and legacy VML images (v:imagedata r:id).
+func (c *docxConverter) runImages(drawing *xmlNode) []string {
+	var out []string
+	for _, blip := range drawing.descendants("blip") {
+		if md := c.images.markdown(c.rels, blip.attr("embed")); md != "" {
+			out = append(out, md)
+		}
+	}
+	for _, img := range drawing.descendants("imagedata") {
+		if md := c.images.markdown(c.rels, img.relID()); md != "" {
+			out = append(out, md)
+		}
+	}
+	return out
+}
+
+// toggleOn reports whether a boolean run property (w:b, w:i) is set: present
+// without a value, or with a value other than false/0/off.
+func toggleOn(prop *xmlNode) bool {
+	if prop == nil {
+		return false
+	}
+	switch strings.ToLower(prop.attr("val")) {
+	case "false", "0", "off", "none":
+		return false
+	}
+	return true
+}
+
+// table renders a w:tbl as a GFM table. Horizontally merged cells are padded
+// so columns stay aligned; nested tables are flattened into their cell.
+func (c *docxConverter) table(tbl *xmlNode) string {
+	var rows [][]string
+	for _, tr := range tbl.childrenNamed("tr") {
+		var row []string
+		for _, tc := range tr.childrenNamed("tc") {
+			row = append(row, c.cellText(tc))
+			span, _ := strconv.Atoi(tc.path("tcPr", "gridSpan").attr("val"))
+			for i := 1; i < span; i++ {
+				row = append(row, "")
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	writeMarkdownTable(&sb, rows)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (c *docxConverter) cellText(tc *xmlNode) string {
+	if tc == nil {
+		return ""
+	}
+	var parts []string
+	for _, n := range tc.Children {
+		switch n.Name {
+		case "p":
+			if text := strings.TrimSpace(c.inline(n, true)); text != "" {
+				parts = append(parts, text)
+			}
+		case "tbl":
+			for _, tr := range n.childrenNamed("tr") {
+				for _, nested := range tr.childrenNamed("tc") {
+					if text := c.cellText(nested); text != "" {
+						parts = append(parts, text)
+					}
+				}
+			}
+		case "sdt":
+			if text := c.cellText(n.child("sdtContent")); text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
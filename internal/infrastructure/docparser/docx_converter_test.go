@@ -0,0 +1,91 @@
+package docparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const docxNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" ` +
+	`xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" ` +
+	`xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" ` +
+	`xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"`
+
+func testDocx(t *testing.T) []byte {
+	t.Helper()
+	return buildOOXML(t, map[string]string{
+		"_rels/.rels": `<?xml version="1.0"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+		</Relationships>`,
+		"word/_rels/document.xml.rels": `<?xml version="1.0"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+			<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>
+			<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>
+			<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>
+		</Relationships>`,
+		// Localized Word templates use numeric style IDs; the name decides.
+		"word/styles.xml": `<w:styles ` + docxNS + `>
+			<w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/></w:style>
+			<w:style w:type="paragraph" w:styleId="Custom2"><w:name w:val="My Section"/><w:pPr><w:outlineLvl w:val="1"/></w:pPr></w:style>
+		</w:styles>`,
+		"word/numbering.xml": `<w:numbering ` + docxNS + `>
+			<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/></w:lvl><w:lvl w:ilvl="1"><w:numFmt w:val="bullet"/></w:lvl></w:abstractNum>
+			<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>
+			<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+			<w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num>
+		</w:numbering>`,
+		"word/media/image1.png": string(pngBytes),
+		"word/document.xml": `<w:document ` + docxNS + `><w:body>
+			<w:p><w:pPr><w:pStyle w:val="1"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>Quarterly Report</w:t></w:r></w:p>
+			<w:p><w:r><w:t xml:space="preserve">Revenue </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>grew</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> fast</w:t></w:r><w:r><w:t xml:space="preserve">, see </w:t></w:r><w:hyperlink r:id="rId4"><w:r><w:t>the site</w:t></w:r></w:hyperlink><w:r><w:rPr><w:i w:val="0"/></w:rPr><w:t>.</w:t></w:r></w:p>
+			<w:p><w:pPr><w:pStyle w:val="Custom2"/></w:pPr><w:r><w:t>Details</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>First point</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Sub point</w:t></w:r></w:p>
+			<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t>Step one</w:t></w:r></w:p>
+			<w:tbl>
+				<w:tr><w:tc><w:p><w:r><w:t>Region</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Sales</w:t></w:r></w:p></w:tc></w:tr>
+				<w:tr><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>Total | all</w:t></w:r></w:p></w:tc></w:tr>
+			</w:tbl>
+			<w:p><w:r><w:drawing><wp:inline><a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="rId3"/></pic:blipFill></pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>
+			<w:sdt><w:sdtContent><w:p><w:r><w:t>Inside a content control</w:t></w:r></w:p></w:sdtContent></w:sdt>
+			<w:p><w:r><w:delText>deleted</w:delText></w:r></w:p>
+		</w:body></w:document>`,
+	})
+}
+
+func TestDocxToMarkdown(t *testing.T) {
+	result, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+		FileContent: testDocx(t), FileName: "report.docx", FileType: "docx",
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"# Quarterly Report",
+		"Revenue **grew fast**, see [the site](https://example.com).",
+		"## Details",
+		"- First point",
+		"    - Sub point",
+		"1. Step one",
+		"| Region | Sales |\n| --- | --- |\n| Total \\| all |  |",
+		"![image1.png](images/image1.png)",
+		"Inside a content control",
+	}, "\n\n")
+	if result.MarkdownContent != want {
+		t.Fatalf("markdown =\n%s\n\nwant\n%s", result.MarkdownContent, want)
+	}
+	if len(result.ImageRefs) != 1 {
+		t.Fatalf("image refs = %+v, want one", result.ImageRefs)
+	}
+	img := result.ImageRefs[0]
+	if img.OriginalRef != "images/image1.png" || img.MimeType != "image/png" || len(img.ImageData) != len(pngBytes) {
+		t.Errorf("image ref = %+v", img)
+	}
+	if result.Metadata["parser"] != SimpleEngineName || result.Metadata["source_format"] != "docx" {
+		t.Errorf("metadata = %v", result.Metadata)
+	}
+}
@@ -63,21 +63,36 @@ func lookupEngine(name string) (EngineRegistration, bool) {
 // NewReader builds the reader for an engine.
 //
 // An empty engine name means "no explicit choice": simple formats are handled
-// in Go and everything else goes to the docreader service. An unknown name is
-// routed to the docreader too, so engines that only exist in the Python
-// service keep working without a Go-side registration.
+// in Go and everything else goes to the docreader service. Office formats the
+// Go reader can convert also stay in Go when the docreader is not connected,
+// as in Lite and desktop deployments. An unknown name is routed to the
+// docreader too, so engines that only exist in the Python service keep
+// working without a Go-side registration.
 func NewReader(
 	ctx context.Context, engine, fileType string, isURL bool, deps ReaderDeps,
 ) (interfaces.DocReader, error) {
 	if registration, ok := lookupEngine(engine); ok {
 		return registration.NewReader(ctx, deps)
 	}
-	if engine == "" && !isURL && IsSimpleFormat(fileType) {
-		return &SimpleFormatReader{}, nil
+	if engine == "" && !isURL {
+		if IsSimpleFormat(fileType) || (IsOfficeFormat(fileType) && !remoteConnected(deps)) {
+			return &SimpleFormatReader{}, nil
+		}
 	}
 	return remoteReader(deps)
 }
 
+// remoteConnected reports whether the docreader can take a request.
+func remoteConnected(deps ReaderDeps) bool {
+	if deps.Remote == nil {
+		return false
+	}
+	if connected, ok := deps.Remote.(interface{ IsConnected() bool }); ok {
+		return connected.IsConnected()
+	}
+	return true
+}
+
 // remoteReader returns the docreader client, or an error when the service is
 // not connected — a nil interface value here would panic at the call site.
 func remoteReader(deps ReaderDeps) (interfaces.DocReader, error) {
@@ -53,6 +53,30 @@ func TestNewReaderRoutesByEngine(t *testing.T) {
 	}
 }
 
+// disconnectedRemote is a docreader client whose connection is down.
+type disconnectedRemote struct{ stubRemote }
+
+func (disconnectedRemote) IsConnected() bool { return false }
+
+// Without a docreader, as in Lite and desktop deployments, office formats are
+// converted in Go instead of failing; legacy formats still need the service.
+func TestNewReaderConvertsOfficeFormatsInGoWithoutDocReader(t *testing.T) {
+	for _, deps := range []ReaderDeps{{}, {Remote: &disconnectedRemote{}}} {
+		for _, fileType := range []string{"docx", "xlsx", "pptx", "html"} {
+			reader, err := NewReader(context.Background(), "", fileType, false, deps)
+			if err != nil {
+				t.Fatalf("NewReader(%s): %v", fileType, err)
+			}
+			if _, ok := reader.(*SimpleFormatReader); !ok {
+				t.Fatalf("NewReader(%s) = %T, want *SimpleFormatReader", fileType, reader)
+			}
+		}
+	}
+	if reader, err := NewReader(context.Background(), "", "doc", false, ReaderDeps{}); err == nil {
+		t.Fatalf("NewReader(doc) = %T, want an error without a docreader", reader)
+	}
+}
+
 // A disconnected docreader has to surface as an error, not as a nil reader the
 // caller would dereference.
 func TestNewReaderReportsDisconnectedDocReader(t *testing.T) {
@@ -12,7 +12,8 @@ import (
 const (
 	// BuiltinEngineName is the DocReader (Python) parser suite.
 	BuiltinEngineName = "builtin"
-	// SimpleEngineName is Go-native handling of text formats and images.
+	// SimpleEngineName is Go-native handling of text formats, images and
+	// common office formats.
 	SimpleEngineName = "simple"
 	// AnydocEngineName is the in-process anydoc office-document converter.
 	AnydocEngineName = "anydoc"
@@ -72,9 +73,9 @@ func (e *builtinEngine) NewReader(_ context.Context, deps ReaderDeps) (interface
 }
 
 // ---------------------------------------------------------------------------
-// simple — Go handles md/txt/csv/json natively, no external service needed.
-// Distinct from docreader's "builtin", which uses Python libraries for complex
-// formats (docx, pdf, ...).
+// simple — Go handles md/txt/csv/json and docx/xlsx/pptx/html natively, no
+// external service needed. Distinct from docreader's "builtin", which uses
+// Python libraries and also covers pdf, legacy office formats and OCR.
 // ---------------------------------------------------------------------------
 
 type simpleEngine struct{}
@@ -82,12 +83,13 @@ type simpleEngine struct{}
 func (e *simpleEngine) Name() string { return SimpleEngineName }
 
 func (e *simpleEngine) Description() string {
-	return "Simple format & image parsing (no external service required)"
+	return "Simple format, office document & image parsing (no external service required)"
 }
 
 func (e *simpleEngine) FileTypes(_ bool) []string {
 	return []string{
 		"md", "markdown", "txt", "csv", "json",
+		"docx", "xlsx", "pptx", "html", "htm",
 		"jpg", "jpeg", "png", "gif", "bmp", "tiff", "webp",
 		"mp3", "wav", "m4a", "flac", "ogg",
 	}
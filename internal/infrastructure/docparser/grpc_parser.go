@@ -12,6 +12,7 @@ import (
 	docclient "github.com/Tencent/WeKnora/docreader/client"
 	"github.com/Tencent/WeKnora/docreader/proto"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
 	"github.com/Tencent/WeKnora/internal/types"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -52,6 +53,7 @@ func (p *GRPCDocumentReader) connect(addr string) error {
 	if err != nil {
 		return fmt.Errorf("failed to build docreader dial options: %w", err)
 	}
+	opts = append(opts, langfuse.GRPCDialOptions()...)
 	if authConfig.TLSEnabled {
 		logger.Infof(context.Background(), "TLS enabled for docreader gRPC client")
 	}
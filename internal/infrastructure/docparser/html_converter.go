@@ -0,0 +1,77 @@
+package docparser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	readability "codeberg.org/readeck/go-readability/v2"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// minMainContentRatio is the share of the page's text the readability
+// extraction must keep to be used. Below it the page is more likely a
+// document readability misjudged (a report of many sibling sections, a
+// table-only export) than an article wrapped in navigation, and the whole
+// body is converted instead.
+const minMainContentRatio = 0.3
+
+// htmlPageToMarkdown converts an HTML page to markdown. Like a reader view,
+// it keeps the page's main content and drops navigation, sidebars, and
+// footers; the page title becomes the top heading. Inline data: images are
+// kept as markdown images for the image resolver to store.
+func htmlPageToMarkdown(data []byte) (string, error) {
+	utf8Reader, err := charset.NewReader(bytes.NewReader(data), "text/html")
+	if err != nil {
+		return "", fmt.Errorf("detect html charset: %w", err)
+	}
+	root, err := html.Parse(utf8Reader)
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+	page := goquery.NewDocumentFromNode(root)
+	page.Find("script, style, noscript, template").Remove()
+	pageText := utf8.RuneCountInString(strings.Join(strings.Fields(page.Find("body").Text()), " "))
+
+	title := strings.TrimSpace(page.Find("title").First().Text())
+	content := root
+	if article, err := readability.FromDocument(root, nil); err == nil && article.Node != nil {
+		var text strings.Builder
+		if article.RenderText(&text) == nil {
+			mainText := utf8.RuneCountInString(strings.Join(strings.Fields(text.String()), " "))
+			if mainText > 0 && float64(mainText) >= minMainContentRatio*float64(pageText) {
+				content = article.Node
+			}
+		}
+		if t := strings.TrimSpace(article.Title()); t != "" {
+			title = t
+		}
+	}
+
+	conv := converter.NewConverter(
+		converter.WithPlugins(
+			base.NewBasePlugin(),
+			commonmark.NewCommonmarkPlugin(),
+			table.NewTablePlugin(),
+		),
+	)
+	md, err := conv.ConvertNode(content)
+	if err != nil {
+		return "", fmt.Errorf("convert html to markdown: %w", err)
+	}
+	markdown := strings.TrimSpace(string(md))
+	if title != "" && !strings.HasPrefix(markdown, "# "+title) {
+		// Drop the <title> from the body when the page repeats it as its
+		// first heading at another level.
+		markdown = strings.TrimSpace(strings.TrimPrefix(markdown, "## "+title))
+		markdown = "# " + title + "\n\n" + markdown
+	}
+	return strings.TrimSpace(markdown), nil
+}
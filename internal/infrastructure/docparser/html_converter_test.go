@@ -0,0 +1,59 @@
+package docparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestHTMLPageToMarkdownKeepsMainContent(t *testing.T) {
+	paragraph := "WeKnora converts uploaded pages to markdown before chunking them for retrieval. "
+	page := `<!DOCTYPE html><html><head><title>Release Notes</title><script>var tracking = 1;</script></head><body>
+		<nav><a href="/">Home</a> | <a href="/docs">Docs</a> | <a href="/blog">Blog</a></nav>
+		<article>
+			<h1>Release Notes</h1>
+			<p>` + strings.Repeat(paragraph, 6) + `</p>
+			<h2>Changes</h2>
+			<p>` + strings.Repeat(paragraph, 4) + `</p>
+			<table><tr><th>Version</th><th>Date</th></tr><tr><td>1.2</td><td>2026-10-01</td></tr></table>
+		</article>
+		<footer>Copyright footer text</footer>
+	</body></html>`
+
+	result, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+		FileContent: []byte(page), FileName: "notes.html",
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	md := result.MarkdownContent
+	if !strings.HasPrefix(md, "# Release Notes\n\n") || strings.Count(md, "Release Notes") != 1 {
+		t.Errorf("markdown should start with the title once:\n%s", md)
+	}
+	for _, want := range []string{"## Changes", "| Version |", "| 1.2     | 2026-10-01 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown misses %q:\n%s", want, md)
+		}
+	}
+	for _, unwanted := range []string{"Home", "Copyright", "tracking"} {
+		if strings.Contains(md, unwanted) {
+			t.Errorf("markdown keeps %q from outside the main content:\n%s", unwanted, md)
+		}
+	}
+}
+
+func TestHTMLPageToMarkdownFallsBackToWholeBody(t *testing.T) {
+	// A short page readability cannot score: everything is kept.
+	page := `<html><head><meta charset="gbk"></head><body><div><b>Owner:</b> ops</div><ul><li>alpha</li><li>beta</li></ul></body></html>`
+	md, err := htmlPageToMarkdown([]byte(page))
+	if err != nil {
+		t.Fatalf("htmlPageToMarkdown: %v", err)
+	}
+	for _, want := range []string{"**Owner:** ops", "- alpha", "- beta"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown misses %q:\n%s", want, md)
+		}
+	}
+}
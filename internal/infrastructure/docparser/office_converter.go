@@ -0,0 +1,356 @@
+package docparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// office_converter.go holds what the Go-native docx and pptx converters share:
+// reading parts out of the OOXML zip package, resolving relationships, and
+// collecting embedded images as ImageRefs.
+
+const (
+	// maxOfficePartSize caps the uncompressed size of a single package part,
+	// so a zip bomb cannot exhaust memory.
+	maxOfficePartSize = 64 << 20
+	// maxOfficeTotalSize caps the uncompressed size of everything read from
+	// one package, images included.
+	maxOfficeTotalSize = 512 << 20
+)
+
+// maxXMLDepth caps element nesting in parsed office parts.
+// Real documents stay far below it; the converters walk element trees
+// recursively, and a crafted, highly compressible part nesting millions of
+// elements would otherwise overflow the goroutine stack, which Go cannot
+// recover from.
+const maxXMLDepth = 1000
+
+// relationshipsNS is the namespace of r:id and r:embed attributes.
+const relationshipsNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+// officeFormats lists the office formats the SimpleFormatReader converts in Go.
+// Unlike simpleFormats they are not routed to Go by default: DocReader
+// still parses them when it is connected (see NewReader).
+var officeFormats = map[string]bool{
+	"docx": true, "xlsx": true, "pptx": true,
+	"html": true, "htm": true,
+}
+
+// IsOfficeFormat returns true if the file type is an office document the Go
+// SimpleFormatReader can convert.
+func IsOfficeFormat(fileType string) bool {
+	return officeFormats[strings.ToLower(strings.TrimPrefix(fileType, "."))]
+}
+
+// ooxmlPackage is an opened docx/xlsx/pptx zip package.
+type ooxmlPackage struct {
+	files map[string]*zip.File
+	read  int64
+}
+
+func openOOXMLPackage(data []byte) (*ooxmlPackage, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid office document: %w", err)
+	}
+	pkg := &ooxmlPackage{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		pkg.files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return pkg, nil
+}
+
+func (p *ooxmlPackage) has(name string) bool {
+	_, ok := p.files[name]
+	return ok
+}
+
+// part returns the uncompressed content of a package part.
+func (p *ooxmlPackage) part(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, fmt.Errorf("part %s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open part %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxOfficePartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read part %s: %w", name, err)
+	}
+	if len(data) > maxOfficePartSize {
+		return nil, fmt.Errorf("part %s exceeds %d bytes", name, maxOfficePartSize)
+	}
+	p.read += int64(len(data))
+	if p.read > maxOfficeTotalSize {
+		return nil, fmt.Errorf("document exceeds %d uncompressed bytes", maxOfficeTotalSize)
+	}
+	return data, nil
+}
+
+// xmlPart parses a package part into an element tree; a missing part yields
+// a nil tree and no error.
+func (p *ooxmlPackage) xmlPart(name string) (*xmlNode, error) {
+	if !p.has(name) {
+		return nil, nil
+	}
+	data, err := p.part(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseXMLTree(data)
+}
+
+// ooxmlRelationship is one entry of a part's .rels file, with Target resolved
+// to a package path for internal targets.
+type ooxmlRelationship struct {
+	Type     string
+	Target   string
+	External bool
+}
+
+// relationships returns the relationships of a part keyed by ID.
+func (p *ooxmlPackage) relationships(partName string) (map[string]ooxmlRelationship, error) {
+	dir, file := path.Split(partName)
+	tree, err := p.xmlPart(dir + "_rels/" + file + ".rels")
+	if err != nil || tree == nil {
+		return map[string]ooxmlRelationship{}, err
+	}
+	rels := map[string]ooxmlRelationship{}
+	for _, rel := range tree.childrenNamed("Relationship") {
+		target := rel.attr("Target")
+		external := strings.EqualFold(rel.attr("TargetMode"), "External")
+		if !external {
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join(dir, target)
+			}
+		}
+		rels[rel.attr("Id")] = ooxmlRelationship{Type: rel.attr("Type"), Target: target, External: external}
+	}
+	return rels, nil
+}
+
+// officeImages collects the images a document embeds, once per media part.
+type officeImages struct {
+	pkg   *ooxmlPackage
+	refs  []types.ImageRef
+	byKey map[string]string
+	names map[string]bool
+}
+
+func newOfficeImages(pkg *ooxmlPackage) *officeImages {
+	return &officeImages{pkg: pkg, byKey: map[string]string{}, names: map[string]bool{}}
+}
+
+// markdown returns the markdown image for the media part a relationship
+// points to, or "" when it is external, missing or unreadable.
+func (c *officeImages) markdown(rels map[string]ooxmlRelationship, relID string) string {
+	rel, ok := rels[relID]
+	if !ok || rel.External || !c.pkg.has(rel.Target) {
+		return ""
+	}
+	ref, seen := c.byKey[rel.Target]
+	if !seen {
+		data, err := c.pkg.part(rel.Target)
+		if err != nil || len(data) == 0 {
+			return ""
+		}
+		name := path.Base(rel.Target)
+		for i := 2; c.names[name]; i++ {
+			ext := path.Ext(rel.Target)
+			name = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(path.Base(rel.Target), ext), i, ext)
+		}
+		c.names[name] = true
+		ref = "images/" + strings.ReplaceAll(name, " ", "%20")
+		mimeType := mime.TypeByExtension(path.Ext(name))
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
+		}
+		c.byKey[rel.Target] = ref
+		c.refs = append(c.refs, types.ImageRef{
+			Filename:    name,
+			OriginalRef: ref,
+			MimeType:    mimeType,
+			ImageData:   data,
+		})
+	}
+	return fmt.Sprintf("![%s](%s)", path.Base(ref), ref)
+}
+
+// xmlNode is a minimal element tree. Names are local names: the converters
+// match elements regardless of their namespace prefix. parseXMLTree rejects
+// trees deeper than maxXMLDepth, which bounds every recursive walk.
+type xmlNode struct {
+	Name     string
+	Attrs    []xml.Attr
+	Children []*xmlNode
+	Text     string
+}
+
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xml: %w", err)
+		}
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) > maxXMLDepth {
+				return nil, fmt.Errorf("parse xml: elements nested deeper than %d levels", maxXMLDepth)
+			}
+			n := &xmlNode{Name: t.Name.Local, Attrs: t.Attr}
+			top.Children = append(top.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			top.Text += string(t)
+		}
+	}
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("parse xml: no root element")
+	}
+	return root.Children[0], nil
+}
+
+// attr returns the value of the attribute with this local name.
+func (n *xmlNode) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relID returns the r:id attribute, which elements such as p:sldId carry
+// alongside an unrelated plain id attribute.
+func (n *xmlNode) relID() string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == "id" && a.Name.Space == relationshipsNS {
+			return a.Value
+		}
+	}
+	return n.attr("id")
+}
+
+// child returns the first direct child with this name.
+func (n *xmlNode) child(name string) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// childrenNamed returns the direct children with this name.
+func (n *xmlNode) childrenNamed(name string) []*xmlNode {
+	if n == nil {
+		return nil
+	}
+	var out []*xmlNode
+	for _, c := range n.Children {
+		if c.Name == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// path follows a chain of first children, e.g. n.path("pPr", "pStyle").
+func (n *xmlNode) path(names ...string) *xmlNode {
+	for _, name := range names {
+		n = n.child(name)
+	}
+	return n
+}
+
+// descendants returns every element below n with this name, in document order.
+func (n *xmlNode) descendants(name string) []*xmlNode {
+	if n == nil {
+		return nil
+	}
+	var out []*xmlNode
+	for _, c := range n.Children {
+		if c.Name == name {
+			out = append(out, c)
+		}
+		out = append(out, c.descendants(name)...)
+	}
+	return out
+}
+
+// writeMarkdownTable renders rows as a GFM table whose first row is the
+// header. Rows are padded to the widest row; pipes and line breaks inside
+// cells are escaped so each row stays on one line.
+func writeMarkdownTable(sb *strings.Builder, rows [][]string) {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return
+	}
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < width; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = markdownTableCell(row[i])
+			}
+			sb.WriteString(" ")
+			sb.WriteString(cell)
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|")
+	for i := 0; i < width; i++ {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
+
+func markdownTableCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
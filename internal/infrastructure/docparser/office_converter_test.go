@@ -0,0 +1,86 @@
+package docparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// buildOOXML zips the given parts into an in-memory office package.
+func buildOOXML(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// pngBytes is a 1x1 PNG.
+var pngBytes = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89" +
+	"\x00\x00\x00\rIDATx\x9cc\xf8\x0f\x00\x00\x01\x01\x00\x05\x18\xd8N\x00\x00\x00\x00IEND\xaeB`\x82")
+
+func TestWriteMarkdownTableEscapesCellsAndPadsRows(t *testing.T) {
+	var sb strings.Builder
+	writeMarkdownTable(&sb, [][]string{{"a", "b|c"}, {"line1\nline2"}})
+	want := "| a | b\\|c |\n| --- | --- |\n| line1<br>line2 |  |\n"
+	if sb.String() != want {
+		t.Fatalf("table =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestSimpleFormatReaderRejectsCorruptOfficeFiles(t *testing.T) {
+	for _, ft := range []string{"docx", "xlsx", "pptx"} {
+		_, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+			FileContent: []byte("not a zip"), FileName: "broken." + ft, FileType: ft,
+		})
+		if err == nil {
+			t.Errorf("%s: want an error for a corrupt file", ft)
+		}
+	}
+}
+
+func TestSimpleFormatReaderRejectsDeeplyNestedMarkup(t *testing.T) {
+	const depth = 200000
+	nested := func(open, body, close string) string {
+		return strings.Repeat(open, depth) + body + strings.Repeat(close, depth)
+	}
+	files := map[string][]byte{
+		"docx": buildOOXML(t, map[string]string{
+			"word/document.xml": `<w:document ` + docxNS + `><w:body><w:p>` +
+				nested("<w:ins>", "<w:r><w:t>x</w:t></w:r>", "</w:ins>") + `</w:p></w:body></w:document>`,
+		}),
+		"pptx": buildOOXML(t, map[string]string{
+			"ppt/presentation.xml": `<p:presentation ` + pptxNS + `><p:sldIdLst><p:sldId id="256" r:id="rId1"/></p:sldIdLst></p:presentation>`,
+			"ppt/_rels/presentation.xml.rels": `<Relationships ` + pptxRelsNS + `>
+				<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/>
+			</Relationships>`,
+			"ppt/slides/slide1.xml": `<p:sld ` + pptxNS + `><p:cSld><p:spTree>` +
+				nested("<p:grpSp>", "", "</p:grpSp>") + `</p:spTree></p:cSld></p:sld>`,
+		}),
+		"html": []byte("<html><body>" + nested("<div>", "x", "</div>") + "</body></html>"),
+	}
+	for ft, content := range files {
+		_, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+			FileContent: content, FileName: "nested." + ft, FileType: ft,
+		})
+		// HTML hits golang.org/x/net/html's own open-element limit.
+		if err == nil {
+			t.Errorf("%s: want an error for markup nested %d levels deep", ft, depth)
+		}
+	}
+}
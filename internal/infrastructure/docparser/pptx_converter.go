@@ -0,0 +1,207 @@
+package docparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// pptxConverter renders a presentation as one "## Slide N: <title>" section
+// per slide, in presentation order: text boxes as paragraphs (placeholder
+// bodies as bullet lists), tables, pictures, and the speaker notes.
+type pptxConverter struct {
+	pkg    *ooxmlPackage
+	images *officeImages
+}
+
+// pptxToMarkdown converts a .pptx file to markdown plus its embedded images.
+func pptxToMarkdown(data []byte) (string, []types.ImageRef, error) {
+	pkg, err := openOOXMLPackage(data)
+	if err != nil {
+		return "", nil, err
+	}
+	const presentationPart = "ppt/presentation.xml"
+	presentation, err := pkg.xmlPart(presentationPart)
+	if err != nil {
+		return "", nil, err
+	}
+	if presentation == nil {
+		return "", nil, fmt.Errorf("not a presentation: %s not found", presentationPart)
+	}
+	rels, err := pkg.relationships(presentationPart)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &pptxConverter{pkg: pkg, images: newOfficeImages(pkg)}
+	var sections []string
+	for i, slideID := range presentation.path("sldIdLst").childrenNamed("sldId") {
+		rel, ok := rels[slideID.relID()]
+		if !ok || rel.External {
+			continue
+		}
+		md, err := c.slide(i+1, rel.Target)
+		if err != nil {
+			return "", nil, err
+		}
+		sections = append(sections, md)
+	}
+	return strings.Join(sections, "\n\n"), c.images.refs, nil
+}
+
+func (c *pptxConverter) slide(number int, part string) (string, error) {
+	slide, err := c.pkg.xmlPart(part)
+	if err != nil {
+		return "", err
+	}
+	rels, err := c.pkg.relationships(part)
+	if err != nil {
+		return "", err
+	}
+
+	var title string
+	var blocks []string
+	c.shapes(slide.path("cSld", "spTree"), rels, &title, &blocks)
+
+	heading := "## Slide " + strconv.Itoa(number)
+	if title != "" {
+		heading += ": " + title
+	}
+	out := []string{heading}
+	out = append(out, blocks...)
+
+	for _, rel := range rels {
+		if rel.External || !strings.HasSuffix(rel.Type, "/notesSlide") {
+			continue
+		}
+		if notes := c.notes(rel.Target); notes != "" {
+			out = append(out, "### Notes", notes)
+		}
+	}
+	return strings.Join(out, "\n\n"), nil
+}
+
+// shapes walks a shape tree in z-order, which is also the reading order
+// PowerPoint uses for accessibility. The first title placeholder becomes the
+// slide title instead of a block.
+func (c *pptxConverter) shapes(tree *xmlNode, rels map[string]ooxmlRelationship, title *string, blocks *[]string) {
+	if tree == nil {
+		return
+	}
+	for _, shape := range tree.Children {
+		switch shape.Name {
+		case "sp":
+			placeholder := shape.path("nvSpPr", "nvPr", "ph")
+			phType := placeholder.attr("type")
+			switch phType {
+			case "sldNum", "dt", "ftr", "hdr":
+				continue
+			case "title", "ctrTitle":
+				if *title == "" {
+					*title = strings.Join(pptxParagraphs(shape.child("txBody")), " ")
+					continue
+				}
+			}
+			// Content placeholders are bullet lists unless a paragraph turns
+			// bullets off; free text boxes are plain paragraphs.
+			bullets := placeholder != nil && (phType == "" || phType == "body" || phType == "obj")
+			if md := pptxText(shape.child("txBody"), bullets); md != "" {
+				*blocks = append(*blocks, md)
+			}
+		case "graphicFrame":
+			for _, tbl := range shape.descendants("tbl") {
+				if md := pptxTable(tbl); md != "" {
+					*blocks = append(*blocks, md)
+				}
+			}
+		case "pic":
+			if md := c.images.markdown(rels, shape.path("blipFill", "blip").attr("embed")); md != "" {
+				*blocks = append(*blocks, md)
+			}
+		case "grpSp":
+			c.shapes(shape, rels, title, blocks)
+		}
+	}
+}
+
+// notes returns the text of a notes slide's body placeholder.
+func (c *pptxConverter) notes(part string) string {
+	notes, err := c.pkg.xmlPart(part)
+	if err != nil || notes == nil {
+		return ""
+	}
+	for _, shape := range notes.path("cSld", "spTree").childrenNamed("sp") {
+		if shape.path("nvSpPr", "nvPr", "ph").attr("type") == "body" {
+			return strings.Join(pptxParagraphs(shape.child("txBody")), "\n\n")
+		}
+	}
+	return ""
+}
+
+// pptxText renders a text body, as a bullet list (indented by paragraph
+// level) when bullets is set.
+func pptxText(body *xmlNode, bullets bool) string {
+	var lines []string
+	for _, p := range body.childrenNamed("p") {
+		text := pptxParagraphText(p)
+		if text == "" {
+			continue
+		}
+		pPr := p.child("pPr")
+		if !bullets || pPr.child("buNone") != nil {
+			lines = append(lines, text)
+			continue
+		}
+		level, _ := strconv.Atoi(pPr.attr("lvl"))
+		lines = append(lines, strings.Repeat("    ", max(level, 0))+"- "+text)
+	}
+	if bullets {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// pptxParagraphs returns the non-empty paragraphs of a text body.
+func pptxParagraphs(body *xmlNode) []string {
+	var out []string
+	for _, p := range body.childrenNamed("p") {
+		if text := pptxParagraphText(p); text != "" {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+func pptxParagraphText(p *xmlNode) string {
+	var sb strings.Builder
+	for _, n := range p.Children {
+		switch n.Name {
+		case "r", "fld":
+			if t := n.child("t"); t != nil {
+				sb.WriteString(t.Text)
+			}
+		case "br":
+			sb.WriteString(" ")
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func pptxTable(tbl *xmlNode) string {
+	var rows [][]string
+	for _, tr := range tbl.childrenNamed("tr") {
+		var row []string
+		for _, tc := range tr.childrenNamed("tc") {
+			row = append(row, strings.Join(pptxParagraphs(tc.child("txBody")), "\n"))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	writeMarkdownTable(&sb, rows)
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,92 @@
+package docparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const pptxNS = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" ` +
+	`xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+const pptxRelsNS = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+
+func pptxShape(phType, body string) string {
+	ph := ""
+	if phType != "-" {
+		ph = `<p:ph type="` + phType + `"/>`
+		if phType == "" {
+			ph = `<p:ph idx="1"/>`
+		}
+	}
+	return `<p:sp><p:nvSpPr><p:cNvPr id="1" name="s"/><p:cNvSpPr/><p:nvPr>` + ph + `</p:nvPr></p:nvSpPr>` +
+		`<p:txBody>` + body + `</p:txBody></p:sp>`
+}
+
+func pptxPara(text string, attrs string) string {
+	return `<a:p><a:pPr` + attrs + `/><a:r><a:t>` + text + `</a:t></a:r></a:p>`
+}
+
+func testPptx(t *testing.T) []byte {
+	t.Helper()
+	return buildOOXML(t, map[string]string{
+		"ppt/presentation.xml": `<p:presentation ` + pptxNS + `><p:sldIdLst>
+			<p:sldId id="257" r:id="rId3"/><p:sldId id="256" r:id="rId2"/>
+		</p:sldIdLst></p:presentation>`,
+		// Presentation order follows sldIdLst, not the slide file names.
+		"ppt/_rels/presentation.xml.rels": `<Relationships ` + pptxRelsNS + `>
+			<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide2.xml"/>
+			<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/>
+		</Relationships>`,
+		"ppt/slides/slide1.xml": `<p:sld ` + pptxNS + `><p:cSld><p:spTree>` +
+			pptxShape("ctrTitle", pptxPara("Roadmap 2026", "")) +
+			pptxShape("", pptxPara("Ship the Lite edition", "")+pptxPara("Desktop first", ` lvl="1"`)) +
+			pptxShape("sldNum", pptxPara("1", "")) +
+			`<p:pic><p:blipFill><a:blip r:embed="rId2"/></p:blipFill></p:pic>` +
+			`</p:spTree></p:cSld></p:sld>`,
+		"ppt/slides/_rels/slide1.xml.rels": `<Relationships ` + pptxRelsNS + `>
+			<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide" Target="../notesSlides/notesSlide1.xml"/>
+			<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="../media/image1.png"/>
+		</Relationships>`,
+		"ppt/notesSlides/notesSlide1.xml": `<p:notes ` + pptxNS + `><p:cSld><p:spTree>` +
+			pptxShape("sldImg", "") +
+			pptxShape("body", pptxPara("Mention the beta date.", "")) +
+			`</p:spTree></p:cSld></p:notes>`,
+		"ppt/media/image1.png": string(pngBytes),
+		"ppt/slides/slide2.xml": `<p:sld ` + pptxNS + `><p:cSld><p:spTree>` +
+			pptxShape("-", pptxPara("Free text box", "")) +
+			`<p:grpSp><p:graphicFrame><a:graphic><a:graphicData><a:tbl>` +
+			`<a:tr><a:tc><a:txBody>` + pptxPara("Q1", "") + `</a:txBody></a:tc><a:tc><a:txBody>` + pptxPara("Q2", "") + `</a:txBody></a:tc></a:tr>` +
+			`<a:tr><a:tc><a:txBody>` + pptxPara("10", "") + `</a:txBody></a:tc><a:tc><a:txBody>` + pptxPara("12", "") + `</a:txBody></a:tc></a:tr>` +
+			`</a:tbl></a:graphicData></a:graphic></p:graphicFrame></p:grpSp>` +
+			`</p:spTree></p:cSld></p:sld>`,
+	})
+}
+
+func TestPptxToMarkdown(t *testing.T) {
+	result, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+		FileContent: testPptx(t), FileName: "roadmap.pptx", FileType: ".pptx",
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := strings.Join([]string{
+		"## Slide 1: Roadmap 2026",
+		"- Ship the Lite edition\n    - Desktop first",
+		"![image1.png](images/image1.png)",
+		"### Notes",
+		"Mention the beta date.",
+		"## Slide 2",
+		"Free text box",
+		"| Q1 | Q2 |\n| --- | --- |\n| 10 | 12 |",
+	}, "\n\n")
+	if result.MarkdownContent != want {
+		t.Fatalf("markdown =\n%s\n\nwant\n%s", result.MarkdownContent, want)
+	}
+	if len(result.ImageRefs) != 1 || result.ImageRefs[0].OriginalRef != "images/image1.png" {
+		t.Fatalf("image refs = %+v", result.ImageRefs)
+	}
+}
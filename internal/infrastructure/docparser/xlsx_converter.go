@@ -0,0 +1,134 @@
+package docparser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// xlsxMaxSheetRows and xlsxMaxSheetColumns bound the table taken from one
+	// sheet. A few megabytes of sheet XML can declare millions of cells, and
+	// every kept cell ends up in chunks and embeddings.
+	xlsxMaxSheetRows    = 10000
+	xlsxMaxSheetColumns = 256
+)
+
+// xlsxToMarkdown converts a workbook to one "## <sheet>" section per visible
+// sheet, each holding the sheet's used range as a table whose first non-empty
+// row is the header. Cells are read as displayed (formatted values). Sheets
+// beyond xlsxMaxSheetRows non-empty rows or xlsxMaxSheetColumns columns are
+// cut off with a note.
+func xlsxToMarkdown(data []byte) (string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data), excelize.Options{UnzipSizeLimit: maxOfficeTotalSize})
+	if err != nil {
+		return "", fmt.Errorf("not a valid xlsx workbook: %w", err)
+	}
+	defer f.Close()
+
+	var sections []string
+	for _, sheet := range f.GetSheetList() {
+		if visible, err := f.GetSheetVisible(sheet); err == nil && !visible {
+			continue
+		}
+		rows, truncated, err := readSheetRows(f, sheet)
+		if err != nil {
+			return "", fmt.Errorf("read sheet %q: %w", sheet, err)
+		}
+		rows = trimSheetRows(rows)
+		if len(rows) == 0 {
+			continue
+		}
+		var sb strings.Builder
+		sb.WriteString("## ")
+		sb.WriteString(sheet)
+		sb.WriteString("\n\n")
+		writeMarkdownTable(&sb, rows)
+		if truncated {
+			fmt.Fprintf(&sb, "\n\n_Sheet truncated to the first %d rows and %d columns._",
+				xlsxMaxSheetRows, xlsxMaxSheetColumns)
+		}
+		sections = append(sections, strings.TrimRight(sb.String(), "\n"))
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// readSheetRows streams the rows of a sheet, keeping at most
+// xlsxMaxSheetRows non-blank rows of at most xlsxMaxSheetColumns cells, and
+// reports whether anything was cut off.
+func readSheetRows(f *excelize.File, sheet string) ([][]string, bool, error) {
+	it, err := f.Rows(sheet)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+
+	var rows [][]string
+	truncated := false
+	for it.Next() {
+		row, err := it.Columns()
+		if err != nil {
+			return nil, false, err
+		}
+		if len(row) > xlsxMaxSheetColumns {
+			if !blankCells(row[xlsxMaxSheetColumns:]) {
+				truncated = true
+			}
+			row = row[:xlsxMaxSheetColumns]
+		}
+		if blankCells(row) {
+			continue
+		}
+		if len(rows) == xlsxMaxSheetRows {
+			truncated = true
+			break
+		}
+		rows = append(rows, row)
+	}
+	if err := it.Error(); err != nil {
+		return nil, false, err
+	}
+	return rows, truncated, nil
+}
+
+func blankCells(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// trimSheetRows drops blank rows and the empty columns before the first and
+// after the last used column, so a table starting at C5 has no empty columns.
+func trimSheetRows(rows [][]string) [][]string {
+	first, last := -1, -1
+	var kept [][]string
+	for _, row := range rows {
+		blank := true
+		for i, cell := range row {
+			if strings.TrimSpace(cell) == "" {
+				continue
+			}
+			blank = false
+			if first < 0 || i < first {
+				first = i
+			}
+			last = max(last, i)
+		}
+		if !blank {
+			kept = append(kept, row)
+		}
+	}
+	for i, row := range kept {
+		trimmed := make([]string, last-first+1)
+		if first < len(row) {
+			copy(trimmed, row[first:min(len(row), last+1)])
+		}
+		kept[i] = trimmed
+	}
+	return kept
+}
@@ -0,0 +1,108 @@
+package docparser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestXlsxToMarkdownWritesOneSectionPerVisibleSheet(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	// Sales: a table starting at B2 with a blank row in the middle.
+	for cell, value := range map[string]interface{}{
+		"B2": "Region", "C2": "Amount",
+		"B3": "North", "C3": 1200,
+		"B5": "South|East", "C5": 800.5,
+	} {
+		if err := f.SetCellValue("Sheet1", cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.SetSheetName("Sheet1", "Sales"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.NewSheet("Empty"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.NewSheet("Hidden"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellValue("Hidden", "A1", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetVisible("Hidden", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.NewSheet("Notes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellValue("Notes", "A1", "Reviewed"); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := (&SimpleFormatReader{}).Read(context.Background(), &types.ReadRequest{
+		FileContent: buf.Bytes(), FileName: "sales.xlsx",
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := "## Sales\n\n" +
+		"| Region | Amount |\n| --- | --- |\n| North | 1200 |\n| South\\|East | 800.5 |\n\n" +
+		"## Notes\n\n" +
+		"| Reviewed |\n| --- |"
+	if result.MarkdownContent != want {
+		t.Fatalf("markdown =\n%s\n\nwant\n%s", result.MarkdownContent, want)
+	}
+}
+
+func TestXlsxToMarkdownCapsSheetSize(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := 1; r <= xlsxMaxSheetRows+5; r++ {
+		cell, _ := excelize.CoordinatesToCellName(1, r)
+		row := []interface{}{r}
+		if r == 1 {
+			// A header cell past the column cap is dropped.
+			row = make([]interface{}, xlsxMaxSheetColumns+1)
+			row[0], row[xlsxMaxSheetColumns] = "id", "overflow"
+		}
+		if err := sw.SetRow(cell, row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := xlsxToMarkdown(buf.Bytes())
+	if err != nil {
+		t.Fatalf("xlsxToMarkdown: %v", err)
+	}
+	if strings.Contains(md, "overflow") {
+		t.Error("cell beyond the column cap was kept")
+	}
+	if !strings.Contains(md, fmt.Sprintf("| %d |", xlsxMaxSheetRows)) ||
+		strings.Contains(md, fmt.Sprintf("| %d |", xlsxMaxSheetRows+1)) {
+		t.Errorf("rows were not capped at %d", xlsxMaxSheetRows)
+	}
+	if !strings.HasSuffix(md, "_Sheet truncated to the first 10000 rows and 256 columns._") {
+		t.Errorf("missing truncation note, got tail %q", md[max(0, len(md)-80):])
+	}
+}
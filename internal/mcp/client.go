@@ -41,6 +41,16 @@ type MCPClient interface {
 	// ReadResource reads a resource from the MCP service
 	ReadResource(ctx context.Context, uri string) (*ReadResourceResult, error)
 
+	// ListPrompts retrieves the list of available prompts from the MCP service
+	ListPrompts(ctx context.Context) ([]*types.MCPPrompt, error)
+
+	// GetPrompt renders a prompt of the MCP service with the given arguments
+	GetPrompt(ctx context.Context, name string, args map[string]string) (*GetPromptResult, error)
+
+	// ServerCapabilities returns the capabilities the service advertised
+	// during initialization
+	ServerCapabilities() ServerCapabilities
+
 	// IsConnected returns true if the client is connected
 	IsConnected() bool
 
@@ -402,6 +412,7 @@ func (c *mcpGoClient) Initialize(ctx context.Context) (*InitializeResult, error)
 
 	return &InitializeResult{
 		ProtocolVersion: result.ProtocolVersion,
+		Capabilities:    convertServerCapabilities(result.Capabilities),
 		ServerInfo: ServerInfo{
 			Name:        result.ServerInfo.Name,
 			Version:     result.ServerInfo.Version,
@@ -556,6 +567,108 @@ func (c *mcpGoClient) ReadResource(ctx context.Context, uri string) (*ReadResour
 	}, nil
 }
 
+// ListPrompts retrieves the list of available prompts
+func (c *mcpGoClient) ListPrompts(ctx context.Context) ([]*types.MCPPrompt, error) {
+	if !c.initialized {
+		return nil, ErrNotConnected
+	}
+
+	req := mcp.ListPromptsRequest{}
+	result, err := oauthCall(ctx, c, func() (*mcp.ListPromptsResult, error) {
+		return c.client.ListPrompts(ctx, req)
+	})
+	if err != nil {
+		c.checkErrorAndDisconnectIfNeeded(err)
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	// Convert to our types
+	prompts := make([]*types.MCPPrompt, len(result.Prompts))
+	for i, prompt := range result.Prompts {
+		args := make([]types.MCPPromptArgument, len(prompt.Arguments))
+		for j, arg := range prompt.Arguments {
+			args[j] = types.MCPPromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			}
+		}
+		prompts[i] = &types.MCPPrompt{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Arguments:   args,
+		}
+	}
+
+	return prompts, nil
+}
+
+// GetPrompt renders a prompt with the given arguments
+func (c *mcpGoClient) GetPrompt(ctx context.Context, name string, args map[string]string) (*GetPromptResult, error) {
+	if !c.initialized {
+		return nil, ErrNotConnected
+	}
+
+	req := mcp.GetPromptRequest{
+		Params: mcp.GetPromptParams{
+			Name:      name,
+			Arguments: args,
+		},
+	}
+
+	result, err := oauthCall(ctx, c, func() (*mcp.GetPromptResult, error) {
+		return c.client.GetPrompt(ctx, req)
+	})
+	if err != nil {
+		c.checkErrorAndDisconnectIfNeeded(err)
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	// Convert to our types. Only text is kept: a prompt is used as
+	// instructions, so images and audio have nowhere to go.
+	messages := make([]PromptMessage, 0, len(result.Messages))
+	for _, message := range result.Messages {
+		if textContent, ok := mcp.AsTextContent(message.Content); ok {
+			messages = append(messages, PromptMessage{Role: string(message.Role), Text: textContent.Text})
+		} else if resource, ok := mcp.AsEmbeddedResource(message.Content); ok {
+			if textResource, ok := mcp.AsTextResourceContents(resource.Resource); ok {
+				messages = append(messages, PromptMessage{Role: string(message.Role), Text: textResource.Text})
+			}
+		}
+	}
+
+	return &GetPromptResult{
+		Description: result.Description,
+		Messages:    messages,
+	}, nil
+}
+
+// ServerCapabilities returns the capabilities advertised by the service
+func (c *mcpGoClient) ServerCapabilities() ServerCapabilities {
+	if !c.initialized {
+		return ServerCapabilities{}
+	}
+	return convertServerCapabilities(c.client.GetServerCapabilities())
+}
+
+// convertServerCapabilities keeps the capabilities WeKnora acts on
+func convertServerCapabilities(caps mcp.ServerCapabilities) ServerCapabilities {
+	var out ServerCapabilities
+	if caps.Tools != nil {
+		out.Tools = &ToolsCapability{ListChanged: caps.Tools.ListChanged}
+	}
+	if caps.Resources != nil {
+		out.Resources = &ResourcesCapability{
+			Subscribe:   caps.Resources.Subscribe,
+			ListChanged: caps.Resources.ListChanged,
+		}
+	}
+	if caps.Prompts != nil {
+		out.Prompts = &PromptsCapability{ListChanged: caps.Prompts.ListChanged}
+	}
+	return out
+}
+
 // IsConnected returns true if the client is connected
 func (c *mcpGoClient) IsConnected() bool {
 	return c.connected
@@ -121,3 +121,15 @@ func TestApplyAuthHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPromptResultText(t *testing.T) {
+	result := &GetPromptResult{Messages: []PromptMessage{
+		{Role: "user", Text: "  You are the on-call assistant for payments.  "},
+		{Role: "assistant", Text: ""},
+		{Role: "user", Text: "Escalate incidents above P2."},
+	}}
+	want := "You are the on-call assistant for payments.\n\nEscalate incidents above P2."
+	if got := result.Text(); got != want {
+		t.Fatalf("Text() = %q, want %q", got, want)
+	}
+}
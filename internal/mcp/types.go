@@ -1,5 +1,7 @@
 package mcp
 
+import "strings"
+
 // InitializeResult represents the result of initialize request
 type InitializeResult struct {
 	ProtocolVersion string             `json:"protocolVersion"`
@@ -66,3 +68,26 @@ type ResourceContent struct {
 	Text     string `json:"text,omitempty"`
 	Blob     string `json:"blob,omitempty"` // Base64 encoded
 }
+
+// GetPromptResult represents the result of prompts/get request
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage represents one text message of a rendered prompt
+type PromptMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// Text joins the messages of a rendered prompt into a single block
+func (r *GetPromptResult) Text() string {
+	parts := make([]string, 0, len(r.Messages))
+	for _, message := range r.Messages {
+		if text := strings.TrimSpace(message.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,152 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	knowledgeBaseURIPrefix = "weknora://knowledge-bases/"
+	wikiURISegment         = "/wiki/"
+	// listedWikiPagesPerKB bounds the wiki pages resources/list returns for
+	// one knowledge base, most recently updated first. Older pages are still
+	// readable through the template and discoverable with wiki_list.
+	listedWikiPagesPerKB = 50
+)
+
+func knowledgeBaseURI(kbID string) string {
+	return knowledgeBaseURIPrefix + kbID
+}
+
+func wikiPageURI(kbID, slug string) string {
+	return knowledgeBaseURIPrefix + kbID + wikiURISegment + slug
+}
+
+// parseResourceURI splits a resource URI into its knowledge base ID and, for
+// wiki pages, the page slug. Slugs may contain slashes.
+func parseResourceURI(uri string) (kbID, slug string, ok bool) {
+	rest, found := strings.CutPrefix(uri, knowledgeBaseURIPrefix)
+	if !found || rest == "" {
+		return "", "", false
+	}
+	kbID, slug, isWiki := strings.Cut(rest, wikiURISegment)
+	if isWiki && slug == "" {
+		return "", "", false
+	}
+	if kbID == "" || strings.Contains(kbID, "/") {
+		return "", "", false
+	}
+	return kbID, slug, true
+}
+
+// registerResources adds the knowledge base and wiki page templates. The
+// concrete resources depend on the caller and are listed by
+// appendWorkspaceResources.
+func (s *Server) registerResources() {
+	s.mcp.AddResourceTemplate(mcp.NewResourceTemplate(knowledgeBaseURIPrefix+"{kb_id}", "knowledge-base",
+		mcp.WithTemplateDescription("A knowledge base: name, description, type and whether it has a wiki"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.readKnowledgeBase)
+	s.mcp.AddResourceTemplate(mcp.NewResourceTemplate(knowledgeBaseURIPrefix+"{kb_id}/wiki/{+slug}", "wiki-page",
+		mcp.WithTemplateDescription("A wiki page of a knowledge base as markdown"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	), s.readWikiPage)
+}
+
+func (s *Server) readKnowledgeBase(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	if !callerHasCapability(ctx, types.APIKeyCapabilityRetrieve) {
+		return nil, errors.NewForbiddenError("API key scope does not allow reading knowledge bases")
+	}
+	kbID, slug, ok := parseResourceURI(req.Params.URI)
+	if !ok || slug != "" {
+		return nil, errors.NewNotFoundError("resource not found")
+	}
+	kb, err := s.knowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(newKnowledgeBaseItem(kb))
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: "application/json",
+		Text:     string(body),
+	}}, nil
+}
+
+func (s *Server) readWikiPage(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	if !callerHasCapability(ctx, types.APIKeyCapabilityRetrieve) {
+		return nil, errors.NewForbiddenError("API key scope does not allow reading wiki pages")
+	}
+	kbID, slug, ok := parseResourceURI(req.Params.URI)
+	if !ok || slug == "" {
+		return nil, errors.NewNotFoundError("resource not found")
+	}
+	page, err := s.wikiPage(ctx, kbID, slug)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: "text/markdown",
+		Text:     page.Content,
+	}}, nil
+}
+
+// appendWorkspaceResources lists the caller's knowledge bases and the most
+// recent pages of their wikis. Resources are per caller, so they cannot be
+// registered on the shared server and are added to each list result.
+func (s *Server) appendWorkspaceResources(
+	ctx context.Context, _ any, req *mcp.ListResourcesRequest, result *mcp.ListResourcesResult,
+) {
+	if req.Params.Cursor != "" || !callerHasCapability(ctx, types.APIKeyCapabilityRetrieve) {
+		return
+	}
+	kbs, err := s.knowledgeBases(ctx)
+	if err != nil {
+		logger.Warnf(ctx, "[MCPServer] failed to list knowledge base resources: %v", err)
+		return
+	}
+	for _, kb := range kbs {
+		result.Resources = append(result.Resources, mcp.NewResource(knowledgeBaseURI(kb.ID), kb.Name,
+			mcp.WithResourceDescription(kb.Description),
+			mcp.WithMIMEType("application/json"),
+		))
+		if !kb.IsWikiEnabled() {
+			continue
+		}
+		pages, err := s.wikiService.ListPages(ctx, &types.WikiPageListRequest{
+			KnowledgeBaseID: kb.ID,
+			Page:            1,
+			PageSize:        listedWikiPagesPerKB,
+			SortBy:          "updated_at",
+			SortOrder:       "desc",
+		})
+		if err != nil {
+			logger.Warnf(ctx, "[MCPServer] failed to list wiki pages of knowledge base %s: %v", kb.ID, err)
+			continue
+		}
+		hidden, err := s.hiddenKnowledge(ctx, kb.ID)
+		if err != nil {
+			logger.Warnf(ctx, "[MCPServer] failed to resolve hidden documents of knowledge base %s: %v", kb.ID, err)
+			continue
+		}
+		for _, page := range pages.Pages {
+			if page.BuiltFrom(hidden) {
+				continue
+			}
+			result.Resources = append(result.Resources, mcp.NewResource(wikiPageURI(kb.ID, page.Slug), page.Title,
+				mcp.WithResourceDescription(page.Summary),
+				mcp.WithMIMEType("text/markdown"),
+			))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package mcpserver exposes a workspace to remote MCP hosts over the
+// streamable HTTP transport: knowledge base search, document, chunk and wiki
+// reads and agent asks as tools, knowledge bases and wiki pages as resources.
+//
+// It is the server-side counterpart of `weknora mcp serve` in the CLI, which
+// only speaks stdio. Requests are authenticated by the regular /api/v1 auth
+// middleware, so every handler runs as the caller and honours the tenant API
+// key's capabilities and knowledge base allow-list.
+package mcpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	serverName    = "WeKnora"
+	serverVersion = "1.0.0"
+)
+
+// Server is the MCP endpoint of the backend. It is stateless, so any replica
+// can serve any request of a client.
+type Server struct {
+	kbService        interfaces.KnowledgeBaseService
+	knowledgeService interfaces.KnowledgeService
+	chunkService     interfaces.ChunkService
+	sessionService   interfaces.SessionService
+	messageService   interfaces.MessageService
+	agentService     interfaces.CustomAgentService
+	wikiService      interfaces.WikiPageService
+	knowledgeACL     interfaces.KnowledgeACLService
+
+	mcp  *server.MCPServer
+	http *server.StreamableHTTPServer
+}
+
+// New creates the MCP server with all tools and resources registered
+func New(
+	kbService interfaces.KnowledgeBaseService,
+	knowledgeService interfaces.KnowledgeService,
+	chunkService interfaces.ChunkService,
+	sessionService interfaces.SessionService,
+	messageService interfaces.MessageService,
+	agentService interfaces.CustomAgentService,
+	wikiService interfaces.WikiPageService,
+	knowledgeACL interfaces.KnowledgeACLService,
+) *Server {
+	s := &Server{
+		kbService:        kbService,
+		knowledgeService: knowledgeService,
+		chunkService:     chunkService,
+		sessionService:   sessionService,
+		messageService:   messageService,
+		agentService:     agentService,
+		wikiService:      wikiService,
+		knowledgeACL:     knowledgeACL,
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddAfterListResources(s.appendWorkspaceResources)
+	s.mcp = server.NewMCPServer(serverName, serverVersion,
+		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithToolFilter(filterTools),
+		server.WithToolHandlerMiddleware(authorizeTool),
+		server.WithHooks(hooks),
+		server.WithRecovery(),
+		server.WithResourceRecovery(),
+		server.WithInstructions("Search and read the knowledge bases, documents and wiki pages of a "+
+			"WeKnora workspace, and ask its agents. Use kb_list first to find knowledge base IDs."),
+	)
+	s.registerTools()
+	s.registerResources()
+	// Stateless mode never pushes server-initiated messages, so the GET
+	// stream is refused with 405 as the transport allows.
+	s.http = server.NewStreamableHTTPServer(s.mcp,
+		server.WithStateLess(true),
+		server.WithDisableStreaming(true),
+	)
+	return s
+}
+
+// ServeHTTP handles one streamable HTTP request. The request context must
+// carry the authenticated tenant and caller.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.http.ServeHTTP(w, r)
+}
+
+// toolCapabilities lists the API key capabilities that admit each tool; a
+// key needs any one of them. Full-access keys and logged-in users may call
+// every tool.
+var toolCapabilities = map[string][]types.APIKeyCapability{
+	"kb_list":       {types.APIKeyCapabilityRetrieve},
+	"search_chunks": {types.APIKeyCapabilityRetrieve},
+	"doc_view":      {types.APIKeyCapabilityRetrieve},
+	"chunk_list":    {types.APIKeyCapabilityRetrieve},
+	"wiki_list":     {types.APIKeyCapabilityRetrieve},
+	"wiki_view":     {types.APIKeyCapabilityRetrieve},
+	"agent_list":    {types.APIKeyCapabilityChat, types.APIKeyCapabilityReadAgents},
+	"agent_ask":     {types.APIKeyCapabilityChat},
+}
+
+// callerHasCapability reports whether the caller may use a feature that
+// needs any of the given capabilities
+func callerHasCapability(ctx context.Context, capabilities ...types.APIKeyCapability) bool {
+	scope, ok := types.TenantAPIKeyScopeFromContext(ctx)
+	if !ok || scope.FullAccess {
+		return true
+	}
+	for _, capability := range capabilities {
+		if scope.HasCapability(capability) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTools hides the tools the caller's API key may not call
+func filterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if callerHasCapability(ctx, toolCapabilities[tool.Name]...) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// authorizeTool rejects calls of hidden tools, which a client may still
+// name directly
+func authorizeTool(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !callerHasCapability(ctx, toolCapabilities[req.Params.Name]...) {
+			return mcp.NewToolResultError("API key scope does not allow tool " + req.Params.Name), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+// knowledgeBase loads a knowledge base of the caller's workspace that the
+// caller's API key may read. Knowledge bases shared from other workspaces
+// are not reachable through MCP.
+func (s *Server) knowledgeBase(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil || kb == nil || kb.TenantID != types.MustTenantIDFromContext(ctx) {
+		return nil, errors.NewNotFoundError("knowledge base not found")
+	}
+	if err := types.AuthorizeTenantAPIKeyKnowledgeBases(ctx, kb.ID); err != nil {
+		return nil, err
+	}
+	return kb, nil
+}
+
+// knowledgeBases lists the knowledge bases of the caller's workspace that
+// the caller's API key may read
+func (s *Server) knowledgeBases(ctx context.Context) ([]*types.KnowledgeBase, error) {
+	kbs, err := s.kbService.ListKnowledgeBases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]*types.KnowledgeBase, 0, len(kbs))
+	for _, kb := range kbs {
+		if kb != nil && types.AuthorizeTenantAPIKeyKnowledgeBases(ctx, kb.ID) == nil {
+			visible = append(visible, kb)
+		}
+	}
+	return visible, nil
+}
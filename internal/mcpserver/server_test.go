@@ -0,0 +1,228 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+type stubKBService struct {
+	interfaces.KnowledgeBaseService
+	kbs []*types.KnowledgeBase
+}
+
+func (s *stubKBService) ListKnowledgeBases(ctx context.Context) ([]*types.KnowledgeBase, error) {
+	tenantID := types.MustTenantIDFromContext(ctx)
+	var out []*types.KnowledgeBase
+	for _, kb := range s.kbs {
+		if kb.TenantID == tenantID {
+			out = append(out, kb)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubKBService) GetKnowledgeBaseByID(_ context.Context, id string) (*types.KnowledgeBase, error) {
+	for _, kb := range s.kbs {
+		if kb.ID == id {
+			return kb, nil
+		}
+	}
+	return nil, fmt.Errorf("knowledge base %s not found", id)
+}
+
+type stubSessionService struct {
+	interfaces.SessionService
+	searchedKBIDs []string
+}
+
+func (s *stubSessionService) SearchKnowledge(
+	_ context.Context, kbIDs []string, _ []string, _ []types.TagScope, _ string,
+) ([]*types.SearchResult, error) {
+	s.searchedKBIDs = kbIDs
+	return []*types.SearchResult{{ID: "c-1", KnowledgeBaseID: kbIDs[0], Content: "hit"}}, nil
+}
+
+type stubWikiService struct {
+	interfaces.WikiPageService
+	pages []*types.WikiPage
+}
+
+func (s *stubWikiService) GetPageBySlug(_ context.Context, kbID, slug string) (*types.WikiPage, error) {
+	for _, p := range s.pages {
+		if p.KnowledgeBaseID == kbID && p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("page %s not found", slug)
+}
+
+func (s *stubWikiService) ListPages(_ context.Context, req *types.WikiPageListRequest) (*types.WikiPageListResponse, error) {
+	resp := &types.WikiPageListResponse{Page: req.Page, PageSize: req.PageSize}
+	for _, p := range s.pages {
+		if p.KnowledgeBaseID == req.KnowledgeBaseID {
+			resp.Pages = append(resp.Pages, p)
+		}
+	}
+	resp.Total = int64(len(resp.Pages))
+	return resp, nil
+}
+
+func newServerForTest() (*Server, *stubSessionService) {
+	kbs := &stubKBService{kbs: []*types.KnowledgeBase{
+		{ID: "kb-1", TenantID: 1, Name: "Handbook", IndexingStrategy: types.IndexingStrategy{WikiEnabled: true}},
+		{ID: "kb-2", TenantID: 1, Name: "Finance"},
+		{ID: "kb-other", TenantID: 2, Name: "Other workspace"},
+	}}
+	sessions := &stubSessionService{}
+	wikiPages := &stubWikiService{pages: []*types.WikiPage{
+		{KnowledgeBaseID: "kb-1", Slug: "entity/acme-corp", Title: "Acme Corp", Content: "# Acme"},
+	}}
+	return New(kbs, nil, nil, sessions, nil, nil, wikiPages, nil), sessions
+}
+
+// scopedContext authenticates as a key of tenant 1 with the given
+// capabilities, restricted to kb-1
+func scopedContext(capabilities ...string) context.Context {
+	ctx := context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1))
+	return types.WithTenantAPIKeyScope(ctx, types.TenantAPIKeyScope{
+		KeyID:            7,
+		KnowledgeBaseIDs: types.StringArray{"kb-1"},
+		Capabilities:     types.StringArray(capabilities),
+	})
+}
+
+// call sends one JSON-RPC request through the streamable HTTP transport
+func call(t *testing.T, s *Server, ctx context.Context, method string, params any) json.RawMessage {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/mcp", strings.NewReader(string(body))).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	require.Nil(t, resp.Error, rec.Body.String())
+	return resp.Result
+}
+
+func callTool(t *testing.T, s *Server, ctx context.Context, name string, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+	var result mcp.CallToolResult
+	raw := call(t, s, ctx, "tools/call", map[string]any{"name": name, "arguments": args})
+	require.NoError(t, json.Unmarshal(raw, &result))
+	return result
+}
+
+func TestToolsFollowAPIKeyCapabilities(t *testing.T) {
+	s, _ := newServerForTest()
+
+	var list mcp.ListToolsResult
+	require.NoError(t, json.Unmarshal(call(t, s, scopedContext("retrieve"), "tools/list", map[string]any{}), &list))
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	require.Contains(t, names, "search_chunks")
+	require.NotContains(t, names, "agent_ask")
+	require.NotContains(t, names, "agent_list")
+
+	result := callTool(t, s, scopedContext("retrieve"), "agent_ask", map[string]any{"agent_id": "a", "question": "q"})
+	require.True(t, result.IsError, "a hidden tool must not be callable by name")
+
+	require.NoError(t, json.Unmarshal(call(t, s, scopedContext("read_agents"), "tools/list", map[string]any{}), &list))
+	require.Len(t, list.Tools, 1)
+	require.Equal(t, "agent_list", list.Tools[0].Name)
+}
+
+func TestToolsStayWithinKnowledgeBaseAllowList(t *testing.T) {
+	s, sessions := newServerForTest()
+	ctx := scopedContext("retrieve")
+
+	result := callTool(t, s, ctx, "kb_list", nil)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.Contains(t, text, "kb-1")
+	require.NotContains(t, text, "kb-2")
+	require.NotContains(t, text, "kb-other")
+
+	result = callTool(t, s, ctx, "search_chunks", map[string]any{"query": "pricing"})
+	require.False(t, result.IsError)
+	require.Equal(t, []string{"kb-1"}, sessions.searchedKBIDs)
+
+	result = callTool(t, s, ctx, "search_chunks", map[string]any{"query": "pricing", "knowledge_base_ids": []string{"kb-2"}})
+	require.True(t, result.IsError)
+
+	// A key without a KB allow-list still cannot reach another workspace
+	unrestricted := types.WithTenantAPIKeyScope(
+		context.WithValue(context.Background(), types.TenantIDContextKey, uint64(1)),
+		types.TenantAPIKeyScope{KeyID: 8, FullAccess: true},
+	)
+	result = callTool(t, s, unrestricted, "wiki_list", map[string]any{"knowledge_base_id": "kb-other"})
+	require.True(t, result.IsError)
+}
+
+func TestWikiPagesAreListedAndReadableAsResources(t *testing.T) {
+	s, _ := newServerForTest()
+	ctx := scopedContext("retrieve")
+
+	var list mcp.ListResourcesResult
+	require.NoError(t, json.Unmarshal(call(t, s, ctx, "resources/list", map[string]any{}), &list))
+	var uris []string
+	for _, r := range list.Resources {
+		uris = append(uris, r.URI)
+	}
+	require.ElementsMatch(t, []string{
+		"weknora://knowledge-bases/kb-1",
+		"weknora://knowledge-bases/kb-1/wiki/entity/acme-corp",
+	}, uris)
+
+	var read struct {
+		Contents []mcp.TextResourceContents `json:"contents"`
+	}
+	raw := call(t, s, ctx, "resources/read", map[string]any{"uri": "weknora://knowledge-bases/kb-1/wiki/entity/acme-corp"})
+	require.NoError(t, json.Unmarshal(raw, &read))
+	require.Len(t, read.Contents, 1)
+	require.Equal(t, "# Acme", read.Contents[0].Text)
+
+	raw = call(t, s, ctx, "resources/read", map[string]any{"uri": "weknora://knowledge-bases/kb-1"})
+	require.NoError(t, json.Unmarshal(raw, &read))
+	require.Contains(t, read.Contents[0].Text, `"wiki_enabled":true`)
+}
+
+func TestParseResourceURI(t *testing.T) {
+	cases := []struct {
+		uri, kbID, slug string
+		ok              bool
+	}{
+		{"weknora://knowledge-bases/kb-1", "kb-1", "", true},
+		{"weknora://knowledge-bases/kb-1/wiki/entity/acme", "kb-1", "entity/acme", true},
+		{"weknora://knowledge-bases/kb-1/wiki/", "", "", false},
+		{"weknora://knowledge-bases/kb-1/docs", "", "", false},
+		{"weknora://agents/a-1", "", "", false},
+	}
+	for _, tc := range cases {
+		kbID, slug, ok := parseResourceURI(tc.uri)
+		require.Equal(t, tc.ok, ok, tc.uri)
+		require.Equal(t, tc.kbID, kbID, tc.uri)
+		require.Equal(t, tc.slug, slug, tc.uri)
+	}
+}
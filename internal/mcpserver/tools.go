@@ -0,0 +1,469 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/service"
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	defaultPageSize    = 20
+	maxPageSize        = 100
+	// sessionTitleRunes bounds the title of the session agent_ask creates
+	sessionTitleRunes = 60
+)
+
+// registerTools adds the tool set. Names and arguments follow the CLI's
+// stdio MCP server so prompts written for one work with the other.
+func (s *Server) registerTools() {
+	s.mcp.AddTool(mcp.NewTool("kb_list",
+		mcp.WithDescription("List the knowledge bases of the workspace that this key may read. "+
+			"Returns items[] with id, name, description, type and wiki_enabled."),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.kbList)
+
+	s.mcp.AddTool(mcp.NewTool("search_chunks",
+		mcp.WithDescription("Hybrid (vector + keyword) search over knowledge bases without LLM "+
+			"summarisation. Returns the best matching chunks with their document and score."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("search query")),
+		mcp.WithArray("knowledge_base_ids", mcp.WithStringItems(),
+			mcp.Description("knowledge bases to search; all readable knowledge bases when omitted")),
+		mcp.WithInteger("limit", mcp.Min(1), mcp.Max(maxSearchLimit),
+			mcp.Description(fmt.Sprintf("maximum number of chunks, default %d", defaultSearchLimit))),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.searchChunks)
+
+	s.mcp.AddTool(mcp.NewTool("doc_view",
+		mcp.WithDescription("Fetch a document (knowledge) by ID: title, file, parse status, "+
+			"description and metadata. Use chunk_list to read its content."),
+		mcp.WithString("knowledge_id", mcp.Required(), mcp.Description("document ID")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.docView)
+
+	s.mcp.AddTool(mcp.NewTool("chunk_list",
+		mcp.WithDescription("Read the text chunks of a document in order, one page at a time."),
+		mcp.WithString("knowledge_id", mcp.Required(), mcp.Description("document ID")),
+		mcp.WithInteger("page", mcp.Min(1), mcp.Description("page number, default 1")),
+		mcp.WithInteger("page_size", mcp.Min(1), mcp.Max(maxPageSize),
+			mcp.Description(fmt.Sprintf("chunks per page, default %d", defaultPageSize))),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.chunkList)
+
+	s.mcp.AddTool(mcp.NewTool("wiki_list",
+		mcp.WithDescription("List the wiki pages of a knowledge base with wiki enabled. "+
+			"Returns slug, title, page_type and summary; read a page with wiki_view."),
+		mcp.WithString("knowledge_base_id", mcp.Required(), mcp.Description("knowledge base ID")),
+		mcp.WithString("query", mcp.Description("optional full-text filter")),
+		mcp.WithString("page_type", mcp.Description("optional page type filter, e.g. entity, concept, summary")),
+		mcp.WithInteger("page", mcp.Min(1), mcp.Description("page number, default 1")),
+		mcp.WithInteger("page_size", mcp.Min(1), mcp.Max(maxPageSize),
+			mcp.Description(fmt.Sprintf("pages per page, default %d", defaultPageSize))),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.wikiList)
+
+	s.mcp.AddTool(mcp.NewTool("wiki_view",
+		mcp.WithDescription("Read a wiki page by slug, including its markdown content."),
+		mcp.WithString("knowledge_base_id", mcp.Required(), mcp.Description("knowledge base ID")),
+		mcp.WithString("slug", mcp.Required(), mcp.Description("page slug, e.g. entity/acme-corp")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.wikiView)
+
+	s.mcp.AddTool(mcp.NewTool("agent_list",
+		mcp.WithDescription("List the agents of the workspace. Returns items[] with id, name, "+
+			"description and whether the agent runs in agent (tool-using) mode."),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.agentList)
+
+	s.mcp.AddTool(mcp.NewTool("agent_ask",
+		mcp.WithDescription("Ask an agent a question and wait for its final answer. Each call "+
+			"starts a new conversation; the session_id is returned for reference."),
+		mcp.WithString("agent_id", mcp.Required(), mcp.Description("agent ID from agent_list")),
+		mcp.WithString("question", mcp.Required(), mcp.Description("question for the agent")),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+	), s.agentAsk)
+}
+
+// toolError turns an error into a tool result the model can read. Internal
+// errors are logged and reported without detail.
+func toolError(ctx context.Context, err error) *mcp.CallToolResult {
+	if appErr, ok := errors.IsAppError(err); ok {
+		return mcp.NewToolResultError(appErr.Message)
+	}
+	logger.ErrorWithFields(ctx, err, nil)
+	return mcp.NewToolResultError("internal error")
+}
+
+// toolJSON returns data as structured content with a JSON text fallback
+func toolJSON(ctx context.Context, data any) (*mcp.CallToolResult, error) {
+	result, err := mcp.NewToolResultJSON(data)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	return result, nil
+}
+
+// pageArgs reads page and page_size, clamping the size
+func pageArgs(req mcp.CallToolRequest) (int, int) {
+	page := req.GetInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	size := req.GetInt("page_size", defaultPageSize)
+	if size < 1 || size > maxPageSize {
+		size = defaultPageSize
+	}
+	return page, size
+}
+
+type knowledgeBaseItem struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	WikiEnabled bool      `json:"wiki_enabled"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newKnowledgeBaseItem(kb *types.KnowledgeBase) knowledgeBaseItem {
+	return knowledgeBaseItem{
+		ID:          kb.ID,
+		Name:        kb.Name,
+		Description: kb.Description,
+		Type:        kb.Type,
+		WikiEnabled: kb.IsWikiEnabled(),
+		UpdatedAt:   kb.UpdatedAt,
+	}
+}
+
+func (s *Server) kbList(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kbs, err := s.knowledgeBases(ctx)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	items := make([]knowledgeBaseItem, 0, len(kbs))
+	for _, kb := range kbs {
+		items = append(items, newKnowledgeBaseItem(kb))
+	}
+	return toolJSON(ctx, map[string]any{"items": items})
+}
+
+type searchChunkItem struct {
+	ChunkID         string  `json:"chunk_id"`
+	KnowledgeID     string  `json:"knowledge_id"`
+	KnowledgeTitle  string  `json:"knowledge_title"`
+	KnowledgeBaseID string  `json:"knowledge_base_id"`
+	ChunkIndex      int     `json:"chunk_index"`
+	Score           float64 `json:"score"`
+	Content         string  `json:"content"`
+}
+
+func (s *Server) searchChunks(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := req.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+	limit := req.GetInt("limit", defaultSearchLimit)
+	if limit < 1 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+
+	kbIDs := req.GetStringSlice("knowledge_base_ids", nil)
+	if len(kbIDs) == 0 {
+		kbs, err := s.knowledgeBases(ctx)
+		if err != nil {
+			return toolError(ctx, err), nil
+		}
+		for _, kb := range kbs {
+			kbIDs = append(kbIDs, kb.ID)
+		}
+		if len(kbIDs) == 0 {
+			return toolJSON(ctx, map[string]any{"items": []searchChunkItem{}})
+		}
+	} else {
+		for _, kbID := range kbIDs {
+			if _, err := s.knowledgeBase(ctx, kbID); err != nil {
+				return toolError(ctx, err), nil
+			}
+		}
+	}
+
+	results, err := s.sessionService.SearchKnowledge(ctx, kbIDs, nil, nil, query)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	items := make([]searchChunkItem, 0, len(results))
+	for _, r := range results {
+		items = append(items, searchChunkItem{
+			ChunkID:         r.ID,
+			KnowledgeID:     r.KnowledgeID,
+			KnowledgeTitle:  r.KnowledgeTitle,
+			KnowledgeBaseID: r.KnowledgeBaseID,
+			ChunkIndex:      r.ChunkIndex,
+			Score:           r.Score,
+			Content:         r.Content,
+		})
+	}
+	return toolJSON(ctx, map[string]any{"items": items})
+}
+
+// knowledge loads a document of a knowledge base the caller may read
+func (s *Server) knowledge(ctx context.Context, knowledgeID string) (*types.Knowledge, error) {
+	knowledge, err := s.knowledgeService.GetKnowledgeByID(ctx, knowledgeID)
+	if err != nil || knowledge == nil {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	if _, err := s.knowledgeBase(ctx, knowledge.KnowledgeBaseID); err != nil {
+		return nil, err
+	}
+	if s.knowledgeACL != nil {
+		readable, err := s.knowledgeACL.CanReadKnowledge(ctx, knowledge)
+		if err != nil {
+			return nil, err
+		}
+		if !readable {
+			return nil, errors.NewNotFoundError("document not found")
+		}
+	}
+	return knowledge, nil
+}
+
+// hiddenKnowledge returns the documents of a knowledge base that access
+// lists hide from the caller. Wiki pages built from any of them are treated
+// as absent.
+func (s *Server) hiddenKnowledge(ctx context.Context, kbID string) (map[string]struct{}, error) {
+	if s.knowledgeACL == nil {
+		return nil, nil
+	}
+	denied, err := s.knowledgeACL.DeniedKnowledgeIDs(ctx, []string{kbID})
+	if err != nil {
+		return nil, err
+	}
+	hidden := make(map[string]struct{}, len(denied[kbID]))
+	for _, id := range denied[kbID] {
+		hidden[id] = struct{}{}
+	}
+	return hidden, nil
+}
+
+func (s *Server) docView(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	knowledgeID, err := req.RequireString("knowledge_id")
+	if err != nil {
+		return mcp.NewToolResultError("knowledge_id is required"), nil
+	}
+	knowledge, err := s.knowledge(ctx, knowledgeID)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	return toolJSON(ctx, knowledge)
+}
+
+type chunkItem struct {
+	ID         string `json:"id"`
+	ChunkIndex int    `json:"chunk_index"`
+	Content    string `json:"content"`
+}
+
+func (s *Server) chunkList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	knowledgeID, err := req.RequireString("knowledge_id")
+	if err != nil {
+		return mcp.NewToolResultError("knowledge_id is required"), nil
+	}
+	if _, err := s.knowledge(ctx, knowledgeID); err != nil {
+		return toolError(ctx, err), nil
+	}
+	page, size := pageArgs(req)
+	result, err := s.chunkService.ListPagedChunksByKnowledgeID(ctx, knowledgeID,
+		&types.Pagination{Page: page, PageSize: size}, []types.ChunkType{types.ChunkTypeText})
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	chunks, _ := result.Data.([]*types.Chunk)
+	items := make([]chunkItem, 0, len(chunks))
+	for _, chunk := range chunks {
+		items = append(items, chunkItem{ID: chunk.ID, ChunkIndex: chunk.ChunkIndex, Content: chunk.Content})
+	}
+	return toolJSON(ctx, map[string]any{
+		"items":     items,
+		"total":     result.Total,
+		"page":      result.Page,
+		"page_size": result.PageSize,
+	})
+}
+
+// wikiKnowledgeBase loads a readable knowledge base and checks its wiki
+func (s *Server) wikiKnowledgeBase(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.knowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if !kb.IsWikiEnabled() {
+		return nil, errors.NewBadRequestError("wiki is not enabled for this knowledge base")
+	}
+	return kb, nil
+}
+
+type wikiPageItem struct {
+	Slug      string    `json:"slug"`
+	Title     string    `json:"title"`
+	PageType  string    `json:"page_type"`
+	Summary   string    `json:"summary"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *Server) wikiList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kbID, err := req.RequireString("knowledge_base_id")
+	if err != nil {
+		return mcp.NewToolResultError("knowledge_base_id is required"), nil
+	}
+	if _, err := s.wikiKnowledgeBase(ctx, kbID); err != nil {
+		return toolError(ctx, err), nil
+	}
+	hidden, err := s.hiddenKnowledge(ctx, kbID)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	page, size := pageArgs(req)
+	resp, err := s.wikiService.ListPages(ctx, &types.WikiPageListRequest{
+		KnowledgeBaseID: kbID,
+		PageType:        req.GetString("page_type", ""),
+		Query:           req.GetString("query", ""),
+		Page:            page,
+		PageSize:        size,
+	})
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	items := make([]wikiPageItem, 0, len(resp.Pages))
+	for _, p := range resp.Pages {
+		if p.BuiltFrom(hidden) {
+			continue
+		}
+		items = append(items, wikiPageItem{
+			Slug: p.Slug, Title: p.Title, PageType: p.PageType, Summary: p.Summary, UpdatedAt: p.UpdatedAt,
+		})
+	}
+	return toolJSON(ctx, map[string]any{
+		"items":     items,
+		"total":     resp.Total,
+		"page":      resp.Page,
+		"page_size": resp.PageSize,
+	})
+}
+
+// wikiPage loads a page of a readable, wiki-enabled knowledge base
+func (s *Server) wikiPage(ctx context.Context, kbID, slug string) (*types.WikiPage, error) {
+	if _, err := s.wikiKnowledgeBase(ctx, kbID); err != nil {
+		return nil, err
+	}
+	page, err := s.wikiService.GetPageBySlug(ctx, kbID, slug)
+	if err != nil || page == nil {
+		return nil, errors.NewNotFoundError("wiki page not found")
+	}
+	hidden, err := s.hiddenKnowledge(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if page.BuiltFrom(hidden) {
+		return nil, errors.NewNotFoundError("wiki page not found")
+	}
+	return page, nil
+}
+
+func (s *Server) wikiView(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kbID, err := req.RequireString("knowledge_base_id")
+	if err != nil {
+		return mcp.NewToolResultError("knowledge_base_id is required"), nil
+	}
+	slug, err := req.RequireString("slug")
+	if err != nil {
+		return mcp.NewToolResultError("slug is required"), nil
+	}
+	page, err := s.wikiPage(ctx, kbID, slug)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	return toolJSON(ctx, page)
+}
+
+type agentItem struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsBuiltin   bool   `json:"is_builtin"`
+	AgentMode   bool   `json:"agent_mode"`
+}
+
+func (s *Server) agentList(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	agents, err := s.agentService.ListAgents(ctx)
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	items := make([]agentItem, 0, len(agents))
+	for _, agent := range agents {
+		if agent == nil {
+			continue
+		}
+		items = append(items, agentItem{
+			ID:          agent.ID,
+			Name:        agent.Name,
+			Description: agent.Description,
+			IsBuiltin:   agent.IsBuiltin,
+			AgentMode:   agent.IsAgentMode(),
+		})
+	}
+	return toolJSON(ctx, map[string]any{"items": items})
+}
+
+// agentAsk runs the agent in a new session owned by the caller. Nobody can
+// answer an MCP OAuth prompt mid-call, so MCP authorization of the agent's
+// own tools is non-interactive.
+func (s *Server) agentAsk(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	agentID, err := req.RequireString("agent_id")
+	if err != nil {
+		return mcp.NewToolResultError("agent_id is required"), nil
+	}
+	question, err := req.RequireString("question")
+	if err != nil || strings.TrimSpace(question) == "" {
+		return mcp.NewToolResultError("question is required"), nil
+	}
+	agent, err := s.agentService.GetAgentByID(ctx, agentID)
+	if err != nil || agent == nil {
+		return mcp.NewToolResultError("agent not found"), nil
+	}
+
+	ctx = types.WithMCPOAuthNonInteractive(ctx)
+	title := []rune(question)
+	if len(title) > sessionTitleRunes {
+		title = title[:sessionTitleRunes]
+	}
+	session, err := s.sessionService.CreateSession(ctx, &types.Session{
+		TenantID: types.MustTenantIDFromContext(ctx),
+		UserID:   types.SessionOwnerIDFromContext(ctx),
+		Title:    string(title),
+	})
+	if err != nil {
+		return toolError(ctx, err), nil
+	}
+	answer, err := service.AskAgent(ctx, s.sessionService, s.messageService, session, agent, question, types.ChannelMCP)
+	if err != nil {
+		logger.Warnf(ctx, "[MCPServer] agent %s failed to answer in session %s: %v", agent.ID, session.ID, err)
+		return mcp.NewToolResultError(fmt.Sprintf("agent failed to answer (session %s): %v", session.ID, err)), nil
+	}
+	return toolJSON(ctx, map[string]any{
+		"session_id": session.ID,
+		"answer":     answer,
+	})
+}
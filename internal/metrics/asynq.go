@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queueTasksDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "task_queue", "tasks"),
+		"Tasks currently in an asynq queue, by queue and task state.",
+		[]string{"queue", "state"}, nil,
+	)
+	queueLatencyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "task_queue", "latency_seconds"),
+		"Age of the oldest pending task in an asynq queue.",
+		[]string{"queue"}, nil,
+	)
+	queueFailedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "task_queue", "failed_total"),
+		"Task failures recorded by asynq for a queue across all workers.",
+		[]string{"queue"}, nil,
+	)
+	queuePausedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "task_queue", "paused"),
+		"Whether an asynq queue is paused (1) or not (0).",
+		[]string{"queue"}, nil,
+	)
+)
+
+// queueCollector reads queue state from Redis at scrape time, so every
+// instance reports the same cluster-wide view.
+type queueCollector struct {
+	inspector *asynq.Inspector
+}
+
+// RegisterTaskQueues exposes the depth of every asynq queue. Queue state is
+// shared through Redis, so only one scrape target needs it, but reporting it
+// from every instance is harmless.
+func RegisterTaskQueues(inspector *asynq.Inspector) {
+	if inspector == nil {
+		return
+	}
+	if err := Registry.Register(&queueCollector{inspector: inspector}); err != nil {
+		logger.Warnf(context.Background(), "[Metrics] failed to register task queue collector: %v", err)
+	}
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueTasksDesc
+	ch <- queueLatencyDesc
+	ch <- queueFailedDesc
+	ch <- queuePausedDesc
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := q.inspector.Queues()
+	if err != nil {
+		logger.Warnf(context.Background(), "[Metrics] failed to list task queues: %v", err)
+		return
+	}
+	for _, queue := range queues {
+		info, err := q.inspector.GetQueueInfo(queue)
+		if err != nil {
+			logger.Warnf(context.Background(), "[Metrics] failed to read task queue %s: %v", queue, err)
+			continue
+		}
+		for state, count := range map[string]int{
+			"pending":   info.Pending,
+			"active":    info.Active,
+			"scheduled": info.Scheduled,
+			"retry":     info.Retry,
+			"archived":  info.Archived,
+		} {
+			ch <- prometheus.MustNewConstMetric(queueTasksDesc, prometheus.GaugeValue, float64(count), queue, state)
+		}
+		ch <- prometheus.MustNewConstMetric(queueLatencyDesc, prometheus.GaugeValue, info.Latency.Seconds(), queue)
+		ch <- prometheus.MustNewConstMetric(queueFailedDesc, prometheus.CounterValue, float64(info.FailedTotal), queue)
+		paused := 0.0
+		if info.Paused {
+			paused = 1
+		}
+		ch <- prometheus.MustNewConstMetric(queuePausedDesc, prometheus.GaugeValue, paused, queue)
+	}
+}
+
+// AsynqMiddleware records the outcome and duration of every task this
+// instance processes
+func AsynqMiddleware() asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			start := time.Now()
+			err := next.ProcessTask(ctx, t)
+			ObserveTask(t.Type(), err, time.Since(start))
+			return err
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package metrics exposes WeKnora's operational Prometheus metrics: chat
+// pipeline stage latency, model calls, the embedding cache, asynq task
+// queues, retriever engines and data source syncs. Everything registers on a private registry served by
+// Handler, so the endpoint only carries what this package defines plus the
+// standard Go runtime and process collectors.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "weknora"
+
+// Status label values shared by every outcome-bearing metric
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Registry holds every WeKnora collector
+var Registry = prometheus.NewRegistry()
+
+var (
+	pipelineStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "pipeline_stage_duration_seconds",
+		Help:      "Latency of chat pipeline stages, by stage and outcome.",
+		Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"stage", "status"})
+
+	modelRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "model_request_duration_seconds",
+		Help:      "Latency of model provider calls, by model type, provider, operation and outcome. Streaming calls are measured to the end of the stream.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"type", "provider", "operation", "status"})
+
+	modelTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "model_tokens_total",
+		Help:      "Tokens consumed by model calls, by model type, provider and direction (prompt or completion). Embedding tokens are estimated from input length.",
+	}, []string{"type", "provider", "direction"})
+
+	taskProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "task_processed_total",
+		Help:      "Asynchronous tasks processed by this instance, by task type and outcome.",
+	}, []string{"task_type", "status"})
+
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "task_duration_seconds",
+		Help:      "Processing time of asynchronous tasks, by task type.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+	}, []string{"task_type"})
+
+	retrieverDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "retriever_duration_seconds",
+		Help:      "Latency of retrieval calls, by retriever engine, retriever type and outcome.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"engine", "retriever", "status"})
+
+	dataSourceSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "datasource_sync_total",
+		Help:      "Finished data source syncs, by connector type and final sync log status.",
+	}, []string{"connector", "status"})
+
+	embeddingCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "embedding_cache_lookups_total",
+		Help:      "Texts looked up in the embedding cache, by cache backend and result (hit or miss).",
+	}, []string{"backend", "result"})
+
+	answerCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "answer_cache_lookups_total",
+		Help:      "Knowledge QA questions looked up in the semantic answer cache, by result (hit or miss).",
+	}, []string{"result"})
+
+	dataSourceSyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "datasource_sync_duration_seconds",
+		Help:      "Wall-clock duration of finished data source syncs, by connector type.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 300, 600, 1800, 3600, 7200},
+	}, []string{"connector"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		pipelineStageDuration,
+		modelRequestDuration,
+		modelTokens,
+		taskProcessed,
+		taskDuration,
+		retrieverDuration,
+		dataSourceSyncs,
+		dataSourceSyncDuration,
+		embeddingCacheLookups,
+		answerCacheLookups,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Status maps an error to the status label value
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusOK
+}
+
+// ObservePipelineStage records one chat pipeline stage run
+func ObservePipelineStage(stage string, failed bool, elapsed time.Duration) {
+	status := StatusOK
+	if failed {
+		status = StatusError
+	}
+	pipelineStageDuration.WithLabelValues(stage, status).Observe(elapsed.Seconds())
+}
+
+// ObserveModelRequest records one model provider call. modelType is "chat",
+// "embedding", etc.; operation distinguishes e.g. "chat" from "chat_stream".
+func ObserveModelRequest(modelType, provider, operation string, err error, elapsed time.Duration) {
+	modelRequestDuration.WithLabelValues(modelType, provider, operation, Status(err)).Observe(elapsed.Seconds())
+}
+
+// AddModelTokens records tokens consumed by a model call
+func AddModelTokens(modelType, provider string, promptTokens, completionTokens int) {
+	if promptTokens > 0 {
+		modelTokens.WithLabelValues(modelType, provider, "prompt").Add(float64(promptTokens))
+	}
+	if completionTokens > 0 {
+		modelTokens.WithLabelValues(modelType, provider, "completion").Add(float64(completionTokens))
+	}
+}
+
+// ObserveTask records one processed asynchronous task
+func ObserveTask(taskType string, err error, elapsed time.Duration) {
+	taskProcessed.WithLabelValues(taskType, Status(err)).Inc()
+	taskDuration.WithLabelValues(taskType).Observe(elapsed.Seconds())
+}
+
+// ObserveRetrieve records one retrieval call against a single engine
+func ObserveRetrieve(engine, retriever string, err error, elapsed time.Duration) {
+	retrieverDuration.WithLabelValues(engine, retriever, Status(err)).Observe(elapsed.Seconds())
+}
+
+// AddEmbeddingCacheLookups records the outcome of one embedding cache lookup
+func AddEmbeddingCacheLookups(backend string, hits, misses int) {
+	if hits > 0 {
+		embeddingCacheLookups.WithLabelValues(backend, "hit").Add(float64(hits))
+	}
+	if misses > 0 {
+		embeddingCacheLookups.WithLabelValues(backend, "miss").Add(float64(misses))
+	}
+}
+
+// IncAnswerCacheLookup records the outcome of one answer cache lookup
+func IncAnswerCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	answerCacheLookups.WithLabelValues(result).Inc()
+}
+
+// ObserveDataSourceSync records a finished data source sync. A non-positive
+// elapsed time (unknown start) only counts the outcome.
+func ObserveDataSourceSync(connector, status string, elapsed time.Duration) {
+	dataSourceSyncs.WithLabelValues(connector, status).Inc()
+	if elapsed > 0 {
+		dataSourceSyncDuration.WithLabelValues(connector).Observe(elapsed.Seconds())
+	}
+}
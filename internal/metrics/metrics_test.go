@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func findMetric(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue next
+				}
+			}
+			return m
+		}
+	}
+	t.Fatalf("metric %s%v not found", name, labels)
+	return nil
+}
+
+func TestObserveModelRequestAndTokens(t *testing.T) {
+	ObserveModelRequest("chat", "test-provider", "chat", errors.New("boom"), 2*time.Second)
+	AddModelTokens("chat", "test-provider", 10, 0)
+
+	m := findMetric(t, "weknora_model_request_duration_seconds",
+		map[string]string{"provider": "test-provider", "status": StatusError})
+	if m.GetHistogram().GetSampleCount() != 1 {
+		t.Errorf("sample count = %d, want 1", m.GetHistogram().GetSampleCount())
+	}
+	tokens := findMetric(t, "weknora_model_tokens_total",
+		map[string]string{"provider": "test-provider", "direction": "prompt"})
+	if tokens.GetCounter().GetValue() != 10 {
+		t.Errorf("prompt tokens = %v, want 10", tokens.GetCounter().GetValue())
+	}
+}
+
+func TestAsynqMiddlewareCountsOutcomes(t *testing.T) {
+	handler := AsynqMiddleware()(asynq.HandlerFunc(func(context.Context, *asynq.Task) error {
+		return errors.New("failed")
+	}))
+	if err := handler.ProcessTask(context.Background(), asynq.NewTask("test:task", nil)); err == nil {
+		t.Fatal("middleware swallowed the handler error")
+	}
+	m := findMetric(t, "weknora_task_processed_total",
+		map[string]string{"task_type": "test:task", "status": StatusError})
+	if m.GetCounter().GetValue() != 1 {
+		t.Errorf("failed tasks = %v, want 1", m.GetCounter().GetValue())
+	}
+}
+
+func TestObserveDataSourceSyncSkipsUnknownDuration(t *testing.T) {
+	ObserveDataSourceSync("test-connector", "failed", 0)
+	m := findMetric(t, "weknora_datasource_sync_total",
+		map[string]string{"connector": "test-connector", "status": "failed"})
+	if m.GetCounter().GetValue() != 1 {
+		t.Errorf("syncs = %v, want 1", m.GetCounter().GetValue())
+	}
+	families, _ := Registry.Gather()
+	for _, family := range families {
+		if family.GetName() == "weknora_datasource_sync_duration_seconds" {
+			t.Error("duration observed for a sync with unknown start")
+		}
+	}
+}
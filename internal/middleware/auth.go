@@ -118,7 +118,8 @@ func attachTenantlessUserContext(c *gin.Context, user *types.User) {
 //  2. Bearer JWT —— 成功则走 authenticateJWTUser 完成空间/角色解析；
 //     校验失败不立即拒绝，继续尝试 X-API-Key（保持既有兼容行为：
 //     携带过期 JWT 但同时带有效 API key 的客户端仍可通过）；
-//  3. X-API-Key —— authenticateAPIKeyRequest。
+//  3. X-API-Key —— authenticateAPIKeyRequest。OpenAI 兼容接口
+//     （/api/v1/openai/）上，未通过 JWT 校验的 Bearer token 也按 API key 处理。
 //
 // 三条通道都未命中时返回 401；若调用方提交过 Bearer token，错误消息
 // 明确指出 token 无效而不是笼统的 "missing authentication"，方便客户端
@@ -144,9 +145,8 @@ func Auth(
 		}
 
 		// 尝试JWT Token认证
-		bearerPresented := false
-		if token, ok := bearerToken(c); ok {
-			bearerPresented = true
+		token, bearerPresented := bearerToken(c)
+		if bearerPresented {
 			user, jwtTenantID, err := userService.ValidateToken(c.Request.Context(), token)
 			if err == nil && user != nil {
 				if authenticateJWTUser(c, tenantService, memberService, cfg, user, jwtTenantID) {
@@ -158,7 +158,7 @@ func Auth(
 		}
 
 		// 尝试X-API-Key认证（兼容模式）
-		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		if apiKey := requestAPIKey(c, token); apiKey != "" {
 			if apiKeyService == nil {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: API key service is not configured"})
 				c.Abort()
@@ -189,6 +189,31 @@ func bearerToken(c *gin.Context) (string, bool) {
 	return strings.TrimPrefix(authHeader, "Bearer "), true
 }
 
+// openAICompatibleAPIPrefix is served to stock OpenAI SDKs, which can only
+// send their key as "Authorization: Bearer <key>".
+const openAICompatibleAPIPrefix = "/api/v1/openai/"
+
+// mcpServerAPIPath is the streamable-HTTP MCP endpoint. MCP hosts configure
+// a remote server with a bearer token, so it accepts API keys there too.
+const mcpServerAPIPath = "/api/v1/mcp"
+
+// requestAPIKey returns the API key the request presents: the X-API-Key
+// header, or on OpenAI-compatible and MCP routes a bearer token that failed
+// JWT validation.
+func requestAPIKey(c *gin.Context, bearer string) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+	if bearer == "" {
+		return ""
+	}
+	path := c.Request.URL.Path
+	if strings.HasPrefix(path, openAICompatibleAPIPrefix) || path == mcpServerAPIPath {
+		return bearer
+	}
+	return ""
+}
+
 // authenticateJWTUser finishes authentication for a validated JWT user:
 // it resolves the target tenant (X-Tenant-ID switch / JWT claim / first
 // active membership), resolves the caller's role inside that tenant, and
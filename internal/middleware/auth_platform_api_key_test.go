@@ -93,3 +93,30 @@ func TestAttachTargetedPlatformAPIKeyKeepsPlatformPrincipal(t *testing.T) {
 		t.Fatalf("user = %#v, ok=%v", user, ok)
 	}
 }
+
+func TestRequestAPIKeyAcceptsBearerOnOpenAIAndMCPRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		path   string
+		header string
+		bearer string
+		want   string
+	}{
+		{"/api/v1/openai/chat/completions", "", "sk-key", "sk-key"},
+		{"/api/v1/openai/models", "sk-header", "sk-key", "sk-header"},
+		{"/api/v1/mcp", "", "sk-key", "sk-key"},
+		{"/api/v1/mcp-services", "", "sk-key", ""},
+		{"/api/v1/knowledge-bases", "", "sk-key", ""},
+		{"/api/v1/knowledge-bases", "sk-header", "", "sk-header"},
+	}
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.header != "" {
+			c.Request.Header.Set("X-API-Key", tc.header)
+		}
+		if got := requestAPIKey(c, tc.bearer); got != tc.want {
+			t.Fatalf("requestAPIKey(%s) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
@@ -152,6 +152,7 @@ func NewChat(config *ChatConfig, ollamaService *ollama.OllamaService) (Chat, err
 	}
 	c, err = wrapChatDebug(c, err)
 	c, err = wrapChatLangfuse(c, err)
+	c, err = wrapChatMetrics(c, provider.MetricsLabel(config.Source, config.Provider, config.BaseURL), err)
 	// Outermost: hold the per-model concurrency slot only around the real
 	// provider round-trip, so the wait is excluded from debug/langfuse timing.
 	return wrapChatConcurrency(c, config.MaxConcurrency, err)
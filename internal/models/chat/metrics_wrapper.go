@@ -0,0 +1,67 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/metrics"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// metricsChat records latency, token usage and errors of every call in the
+// Prometheus model metrics, labelled by provider.
+type metricsChat struct {
+	inner    Chat
+	provider string
+}
+
+func wrapChatMetrics(c Chat, provider string, err error) (Chat, error) {
+	if err != nil || c == nil {
+		return c, err
+	}
+	return &metricsChat{inner: c, provider: provider}, nil
+}
+
+func (m *metricsChat) GetModelName() string { return m.inner.GetModelName() }
+func (m *metricsChat) GetModelID() string   { return m.inner.GetModelID() }
+
+func (m *metricsChat) Chat(ctx context.Context, messages []Message, opts *ChatOptions) (*types.ChatResponse, error) {
+	start := time.Now()
+	resp, err := m.inner.Chat(ctx, messages, opts)
+	metrics.ObserveModelRequest("chat", m.provider, "chat", err, time.Since(start))
+	if resp != nil {
+		metrics.AddModelTokens("chat", m.provider, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	return resp, err
+}
+
+func (m *metricsChat) ChatStream(ctx context.Context, messages []Message, opts *ChatOptions) (<-chan types.StreamResponse, error) {
+	start := time.Now()
+	ch, err := m.inner.ChatStream(ctx, messages, opts)
+	if err != nil || ch == nil {
+		metrics.ObserveModelRequest("chat", m.provider, "chat_stream", err, time.Since(start))
+		return ch, err
+	}
+
+	wrapped := make(chan types.StreamResponse)
+	go func() {
+		defer close(wrapped)
+		var usage *types.TokenUsage
+		var streamErr error
+		for resp := range ch {
+			if resp.Usage != nil {
+				usage = resp.Usage
+			}
+			if resp.ResponseType == types.ResponseTypeError {
+				streamErr = errors.New(resp.Content)
+			}
+			wrapped <- resp
+		}
+		metrics.ObserveModelRequest("chat", m.provider, "chat_stream", streamErr, time.Since(start))
+		if usage != nil {
+			metrics.AddModelTokens("chat", m.provider, usage.PromptTokens, usage.CompletionTokens)
+		}
+	}()
+	return wrapped, nil
+}
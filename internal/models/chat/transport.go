@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
 	secutils "github.com/Tencent/WeKnora/internal/utils"
 )
 
@@ -56,7 +57,9 @@ var rawHTTPTransport = &http.Transport{
 	MaxIdleConnsPerHost: 5,
 }
 
+// The transport is wrapped so every LLM call carries the W3C traceparent of
+// its request context, letting an OTel-instrumented gateway join the trace.
 var rawHTTPClient = secutils.NewSSRFSafeHTTPClientWithTransport(
 	secutils.SSRFSafeHTTPClientConfig{Timeout: 0, MaxRedirects: 10},
-	rawHTTPTransport,
+	langfuse.HTTPTransport(rawHTTPTransport),
 )
@@ -0,0 +1,192 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/metrics"
+)
+
+// Cache stores embedding vectors by key. Keys already identify both the model
+// and the content (see cacheKeyPrefix), so a backend is a plain key-value
+// store. Backends fail soft: a lookup error is treated as a miss and a write
+// error is logged, so the cache can never fail an embedding call.
+type Cache interface {
+	// GetMany returns one entry per key, nil for misses
+	GetMany(ctx context.Context, keys []string) ([][]float32, error)
+	// SetMany stores the given vectors
+	SetMany(ctx context.Context, entries map[string][]float32) error
+	// Name labels the backend in metrics and logs
+	Name() string
+}
+
+// The embedding cache is process-wide, like the concurrency governor: every
+// embedder built by NewEmbedder consults it. Wired once at startup (see
+// container.registerEmbeddingCache) via SetCache.
+var (
+	cacheMu     sync.RWMutex
+	globalCache Cache
+)
+
+// SetCache installs the process-wide embedding cache. Passing nil disables
+// caching for embedders created afterwards.
+func SetCache(c Cache) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	globalCache = c
+}
+
+func currentCache() Cache {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	return globalCache
+}
+
+// cachingEmbedder serves vectors of previously embedded texts from the cache
+// and only sends the misses to the provider. It sits outermost, above the
+// tracing decorators, so cache hits cost neither a provider call nor a
+// tracing observation.
+type cachingEmbedder struct {
+	inner  Embedder
+	cache  Cache
+	prefix string
+}
+
+// wrapEmbeddingCache installs the process-wide cache around an embedder.
+// Embedders without a model ID (connection tests of unsaved models) are never
+// cached: they must reach the provider to prove it works.
+func wrapEmbeddingCache(e Embedder, config Config) Embedder {
+	c := currentCache()
+	if e == nil || c == nil || config.ModelID == "" {
+		return e
+	}
+	return &cachingEmbedder{inner: e, cache: c, prefix: cacheKeyPrefix(config)}
+}
+
+// cacheKeyPrefix identifies the vector space of a model configuration. Any
+// change to the model name, endpoint or requested dimensions yields new keys,
+// so a reconfigured model never serves vectors of its previous setup.
+func cacheKeyPrefix(config Config) string {
+	sum := sha256.Sum256([]byte(config.ModelID + "\x00" + config.ModelName + "\x00" +
+		config.BaseURL + "\x00" + strconv.Itoa(config.Dimensions)))
+	return "weknora:embedding:" + hex.EncodeToString(sum[:8]) + ":"
+}
+
+func (c *cachingEmbedder) key(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return c.prefix + hex.EncodeToString(sum[:])
+}
+
+func (c *cachingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := c.key(text)
+	if cached := c.lookup(ctx, []string{key}); cached[0] != nil {
+		return cached[0], nil
+	}
+	result, err := c.inner.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, map[string][]float32{key: result})
+	return result, nil
+}
+
+func (c *cachingEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	return c.batch(ctx, texts, c.inner.BatchEmbed)
+}
+
+// BatchEmbedWithPool only pools the misses, and threads the inner embedder
+// down as the model so the per-sub-batch callbacks do not consult the cache
+// a second time.
+func (c *cachingEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder, texts []string) ([][]float32, error) {
+	return c.batch(ctx, texts, func(ctx context.Context, misses []string) ([][]float32, error) {
+		return c.inner.BatchEmbedWithPool(ctx, c.inner, misses)
+	})
+}
+
+func (c *cachingEmbedder) GetModelName() string { return c.inner.GetModelName() }
+func (c *cachingEmbedder) GetDimensions() int   { return c.inner.GetDimensions() }
+func (c *cachingEmbedder) GetModelID() string   { return c.inner.GetModelID() }
+
+// batch embeds texts, sending each distinct uncached text to embed once.
+func (c *cachingEmbedder) batch(ctx context.Context, texts []string,
+	embed func(context.Context, []string) ([][]float32, error),
+) ([][]float32, error) {
+	if len(texts) == 0 {
+		return embed(ctx, texts)
+	}
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = c.key(text)
+	}
+	results := c.lookup(ctx, keys)
+
+	var misses []string
+	missIndex := make(map[string]int)
+	for i, result := range results {
+		if result != nil {
+			continue
+		}
+		if _, seen := missIndex[keys[i]]; !seen {
+			missIndex[keys[i]] = len(misses)
+			misses = append(misses, texts[i])
+		}
+	}
+	if len(misses) == 0 {
+		return results, nil
+	}
+
+	embedded, err := embed(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(misses) {
+		return nil, fmt.Errorf("embedding model returned %d embeddings for %d inputs", len(embedded), len(misses))
+	}
+	entries := make(map[string][]float32, len(misses))
+	for i, result := range results {
+		if result == nil {
+			results[i] = embedded[missIndex[keys[i]]]
+			entries[keys[i]] = results[i]
+		}
+	}
+	c.store(ctx, entries)
+	return results, nil
+}
+
+// lookup returns one entry per key, nil for misses, and records the outcome.
+func (c *cachingEmbedder) lookup(ctx context.Context, keys []string) [][]float32 {
+	results, err := c.cache.GetMany(ctx, keys)
+	if err != nil || len(results) != len(keys) {
+		if err != nil {
+			logger.Warnf(ctx, "[EmbeddingCache] %s lookup failed, embedding without cache: %v", c.cache.Name(), err)
+		}
+		results = make([][]float32, len(keys))
+	}
+	hits := 0
+	for _, result := range results {
+		if result != nil {
+			hits++
+		}
+	}
+	metrics.AddEmbeddingCacheLookups(c.cache.Name(), hits, len(keys)-hits)
+	return results
+}
+
+func (c *cachingEmbedder) store(ctx context.Context, entries map[string][]float32) {
+	for key, vector := range entries {
+		if len(vector) == 0 {
+			delete(entries, key)
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if err := c.cache.SetMany(ctx, entries); err != nil {
+		logger.Warnf(ctx, "[EmbeddingCache] %s write failed: %v", c.cache.Name(), err)
+	}
+}
@@ -0,0 +1,70 @@
+package embedding
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+)
+
+// memoryCache is an in-process LRU cache for Lite mode, where there is no
+// Redis to share vectors across replicas.
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front = most recently used
+	entries    map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key    string
+	vector []float32
+}
+
+// NewMemoryCache builds an in-process cache holding at most maxEntries
+// vectors; a non-positive maxEntries yields nil, no cache.
+func NewMemoryCache(maxEntries int) Cache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &memoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (m *memoryCache) Name() string { return "memory" }
+
+// GetMany returns copies, so callers may modify the vectors they get.
+func (m *memoryCache) GetMany(_ context.Context, keys []string) ([][]float32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := make([][]float32, len(keys))
+	for i, key := range keys {
+		if element, ok := m.entries[key]; ok {
+			m.order.MoveToFront(element)
+			results[i] = slices.Clone(element.Value.(*memoryCacheEntry).vector)
+		}
+	}
+	return results, nil
+}
+
+func (m *memoryCache) SetMany(_ context.Context, entries map[string][]float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, vector := range entries {
+		if element, ok := m.entries[key]; ok {
+			element.Value.(*memoryCacheEntry).vector = slices.Clone(vector)
+			m.order.MoveToFront(element)
+			continue
+		}
+		m.entries[key] = m.order.PushFront(&memoryCacheEntry{key: key, vector: slices.Clone(vector)})
+		for m.order.Len() > m.maxEntries {
+			oldest := m.order.Back()
+			m.order.Remove(oldest)
+			delete(m.entries, oldest.Value.(*memoryCacheEntry).key)
+		}
+	}
+	return nil
+}
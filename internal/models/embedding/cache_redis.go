@@ -0,0 +1,51 @@
+package embedding
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCache shares cached vectors across replicas. Entries expire after ttl
+// so vectors of retired models and deleted content do not accumulate.
+type redisCache struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+// NewRedisCache builds a cache backed by rdb. A non-positive ttl keeps
+// entries until Redis evicts them.
+func NewRedisCache(rdb *redis.Client, ttl time.Duration) Cache {
+	return &redisCache{rdb: rdb, ttl: ttl}
+}
+
+func (r *redisCache) Name() string { return "redis" }
+
+func (r *redisCache) GetMany(ctx context.Context, keys []string) ([][]float32, error) {
+	values, err := r.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]float32, len(keys))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			results[i] = utils.DecodeVector([]byte(s))
+		}
+	}
+	return results, nil
+}
+
+func (r *redisCache) SetMany(ctx context.Context, entries map[string][]float32) error {
+	ttl := r.ttl
+	if ttl < 0 {
+		ttl = 0
+	}
+	pipe := r.rdb.Pipeline()
+	for key, vector := range entries {
+		pipe.Set(ctx, key, utils.EncodeVector(vector), ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
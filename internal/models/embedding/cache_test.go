@@ -0,0 +1,115 @@
+package embedding
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// countingEmbedder embeds a text as {len(text)} and records every text that
+// reached the provider.
+type countingEmbedder struct {
+	calls []string
+}
+
+func (c *countingEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	c.calls = append(c.calls, text)
+	return []float32{float32(len(text))}, nil
+}
+
+func (c *countingEmbedder) BatchEmbed(_ context.Context, texts []string) ([][]float32, error) {
+	c.calls = append(c.calls, texts...)
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = []float32{float32(len(text))}
+	}
+	return out, nil
+}
+
+func (c *countingEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder, texts []string) ([][]float32, error) {
+	return model.BatchEmbed(ctx, texts)
+}
+
+func (c *countingEmbedder) GetModelName() string { return "counting" }
+func (c *countingEmbedder) GetDimensions() int   { return 1 }
+func (c *countingEmbedder) GetModelID() string   { return "model-1" }
+
+func newCachedEmbedder(t *testing.T, config Config, cache Cache) (Embedder, *countingEmbedder) {
+	t.Helper()
+	t.Cleanup(func() { SetCache(nil) })
+	SetCache(cache)
+	inner := &countingEmbedder{}
+	return wrapEmbeddingCache(inner, config), inner
+}
+
+func TestCachingEmbedderOnlyEmbedsMisses(t *testing.T) {
+	config := Config{ModelID: "model-1", ModelName: "counting", BaseURL: "http://provider"}
+	e, inner := newCachedEmbedder(t, config, NewMemoryCache(100))
+
+	if _, err := e.Embed(context.Background(), "query"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Embed(context.Background(), "query"); err != nil {
+		t.Fatal(err)
+	}
+	results, err := e.BatchEmbedWithPool(context.Background(), e, []string{"query", "chunk", "chunk", "longer chunk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(inner.calls, ","); got != "query,chunk,longer chunk" {
+		t.Fatalf("provider calls = %s, want each distinct text once", got)
+	}
+	want := []float32{5, 5, 5, 12}
+	for i, result := range results {
+		if len(result) != 1 || result[0] != want[i] {
+			t.Fatalf("result %d = %v, want [%v]", i, result, want[i])
+		}
+	}
+}
+
+func TestCachingEmbedderKeysOnModelConfiguration(t *testing.T) {
+	cache := NewMemoryCache(100)
+	first, _ := newCachedEmbedder(t, Config{ModelID: "model-1", Dimensions: 512}, cache)
+	if _, err := first.Embed(context.Background(), "text"); err != nil {
+		t.Fatal(err)
+	}
+
+	second, inner := newCachedEmbedder(t, Config{ModelID: "model-1", Dimensions: 1024}, cache)
+	if _, err := second.Embed(context.Background(), "text"); err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.calls) != 1 {
+		t.Fatalf("a model with other dimensions reused cached vectors")
+	}
+}
+
+func TestCachingEmbedderSkipsUnsavedModels(t *testing.T) {
+	inner := &countingEmbedder{}
+	t.Cleanup(func() { SetCache(nil) })
+	SetCache(NewMemoryCache(100))
+	if e := wrapEmbeddingCache(inner, Config{ModelName: "test-connection"}); e != Embedder(inner) {
+		t.Fatalf("embedder without model ID was wrapped in the cache")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	ctx := context.Background()
+	_ = cache.SetMany(ctx, map[string][]float32{"a": {1}})
+	_ = cache.SetMany(ctx, map[string][]float32{"b": {2}})
+	if _, err := cache.GetMany(ctx, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = cache.SetMany(ctx, map[string][]float32{"c": {3}})
+
+	results, _ := cache.GetMany(ctx, []string{"a", "b", "c"})
+	if results[0] == nil || results[1] != nil || results[2] == nil {
+		t.Fatalf("cache kept %v, want a and c", results)
+	}
+	results[0][0] = 42
+	again, _ := cache.GetMany(ctx, []string{"a"})
+	if again[0][0] != 1 {
+		t.Fatalf("modifying a returned vector changed the cached entry")
+	}
+}
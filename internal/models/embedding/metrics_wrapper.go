@@ -0,0 +1,50 @@
+package embedding
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/metrics"
+)
+
+// metricsEmbedder records latency, estimated tokens and errors of every
+// provider round-trip in the Prometheus model metrics. It sits directly on
+// the raw embedder, below the concurrency gate, so gate wait time is not
+// counted as provider latency.
+type metricsEmbedder struct {
+	inner    Embedder
+	provider string
+}
+
+func (m *metricsEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	start := time.Now()
+	result, err := m.inner.Embed(ctx, text)
+	m.observe("embed", []string{text}, err, time.Since(start))
+	return result, err
+}
+
+func (m *metricsEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	start := time.Now()
+	result, err := m.inner.BatchEmbed(ctx, texts)
+	m.observe("batch_embed", texts, err, time.Since(start))
+	return result, err
+}
+
+// BatchEmbedWithPool keeps the caller's model: the pooler must call back into
+// the concurrency wrapper above, whose BatchEmbed lands here per sub-batch.
+func (m *metricsEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder, texts []string) ([][]float32, error) {
+	return m.inner.BatchEmbedWithPool(ctx, model, texts)
+}
+
+func (m *metricsEmbedder) GetModelName() string { return m.inner.GetModelName() }
+func (m *metricsEmbedder) GetDimensions() int   { return m.inner.GetDimensions() }
+func (m *metricsEmbedder) GetModelID() string   { return m.inner.GetModelID() }
+
+func (m *metricsEmbedder) observe(operation string, texts []string, err error, elapsed time.Duration) {
+	metrics.ObserveModelRequest("embedding", m.provider, operation, err, elapsed)
+	if err == nil {
+		if usage := approxEmbeddingUsage(texts); usage != nil {
+			metrics.AddModelTokens("embedding", m.provider, usage.Input, 0)
+		}
+	}
+}
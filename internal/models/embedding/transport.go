@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
 	secutils "github.com/Tencent/WeKnora/internal/utils"
 )
 
@@ -16,6 +17,10 @@ var sharedEmbeddingHTTPTransport = secutils.NewSSRFSafeTransport(
 	secutils.DefaultSSRFSafeHTTPClientConfig(),
 )
 
+// sharedEmbeddingRoundTripper propagates the W3C traceparent of each request
+// context on top of the shared pool, so embedding calls join the caller's trace.
+var sharedEmbeddingRoundTripper = langfuse.HTTPTransport(sharedEmbeddingHTTPTransport)
+
 // validateEmbeddingBaseURL checks that a resolved embedding API base URL is safe
 // for outbound requests. Empty URLs are allowed (callers apply provider defaults).
 func validateEmbeddingBaseURL(baseURL string) error {
@@ -30,10 +35,10 @@ func validateEmbeddingBaseURL(baseURL string) error {
 
 // newEmbeddingHTTPClient returns an HTTP client with connection-level SSRF
 // protection and redirect validation, aligned with internal/models/chat/transport.go.
-// All clients share sharedEmbeddingHTTPTransport so keep-alive connections are
+// All clients share sharedEmbeddingRoundTripper so keep-alive connections are
 // pooled globally, while each keeps its own timeout.
 func newEmbeddingHTTPClient(timeout time.Duration) *http.Client {
 	cfg := secutils.DefaultSSRFSafeHTTPClientConfig()
 	cfg.Timeout = timeout
-	return secutils.NewSSRFSafeHTTPClientWithTransport(cfg, sharedEmbeddingHTTPTransport)
+	return secutils.NewSSRFSafeHTTPClientWithTransport(cfg, sharedEmbeddingRoundTripper)
 }
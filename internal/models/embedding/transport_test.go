@@ -1,7 +1,6 @@
 package embedding
 
 import (
-	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -29,7 +28,7 @@ func TestNewEmbeddingHTTPClient_ReusesTransport(t *testing.T) {
 	if firstGuard.Base != secondGuard.Base {
 		t.Fatal("expected embedding HTTP clients to share a base transport")
 	}
-	if firstGuard.Base != http.RoundTripper(sharedEmbeddingHTTPTransport) {
+	if firstGuard.Base != sharedEmbeddingRoundTripper {
 		t.Fatal("expected embedding HTTP client to use the shared transport")
 	}
 	if first.Timeout != firstTimeout {
@@ -63,6 +63,8 @@ const (
 	ProviderNovita ProviderName = "novita"
 	// Azure OpenAI
 	ProviderAzureOpenAI ProviderName = "azure_openai"
+	// Self-hosted Text Embeddings Inference / Infinity
+	ProviderTEI ProviderName = "tei"
 )
 
 // AllProviders 返回所有注册的提供者名称
@@ -94,6 +96,7 @@ func AllProviders() []ProviderName {
 		ProviderNvidia,
 		ProviderNovita,
 		ProviderAzureOpenAI,
+		ProviderTEI,
 	}
 }
 
@@ -276,6 +279,19 @@ func DetectProvider(baseURL string) ProviderName {
 	}
 }
 
+// MetricsLabel names the provider serving a model in operational metrics:
+// local models report "ollama", remote ones their configured provider or the
+// one detected from the base URL.
+func MetricsLabel(source types.ModelSource, providerName, baseURL string) string {
+	if source == types.ModelSourceLocal {
+		return "ollama"
+	}
+	if providerName != "" {
+		return strings.ToLower(providerName)
+	}
+	return string(DetectProvider(baseURL))
+}
+
 func containsAny(s string, substrs ...string) bool {
 	for _, sub := range substrs {
 		if strings.Contains(s, sub) {
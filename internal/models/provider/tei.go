@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const (
+	// TEIBaseURL 自部署 TEI / Infinity 服务地址示例，需在 SSRF 白名单中放行内网地址
+	TEIBaseURL = "http://your_tei_server_url:8080"
+)
+
+// TEIProvider 实现自部署 Text Embeddings Inference / Infinity 的 Provider 接口
+type TEIProvider struct{}
+
+func init() {
+	Register(&TEIProvider{})
+}
+
+// Info 返回 TEI provider 的元数据
+func (p *TEIProvider) Info() ProviderInfo {
+	return ProviderInfo{
+		Name:        ProviderTEI,
+		DisplayName: "TEI / Infinity",
+		Description: "Self-hosted cross-encoders such as bge-reranker-v2-m3 served by TEI or Infinity",
+		DefaultURLs: map[types.ModelType]string{
+			types.ModelTypeRerank: TEIBaseURL,
+		},
+		ModelTypes: []types.ModelType{
+			types.ModelTypeRerank,
+		},
+		RequiresAuth: false, // 仅在服务端以 --api-key 启动时需要
+		ExtraFields: []ExtraFieldConfig{
+			{
+				Key:      "api_format",
+				Label:    "API Format",
+				Type:     "select",
+				Required: false,
+				Default:  "tei",
+				Options: []struct {
+					Label string `json:"label"`
+					Value string `json:"value"`
+				}{
+					{Label: "TEI (texts)", Value: "tei"},
+					{Label: "Infinity (documents)", Value: "infinity"},
+				},
+			},
+			{
+				Key:         "batch_size",
+				Label:       "Batch Size",
+				Type:        "number",
+				Required:    false,
+				Default:     "32",
+				Placeholder: "TEI --max-client-batch-size, default 32",
+			},
+		},
+	}
+}
+
+// ValidateConfig 验证 TEI provider 配置
+func (p *TEIProvider) ValidateConfig(config *Config) error {
+	if config.BaseURL == "" {
+		return fmt.Errorf("base URL is required for TEI provider")
+	}
+	return nil
+}
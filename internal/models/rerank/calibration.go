@@ -0,0 +1,59 @@
+package rerank
+
+import (
+	"context"
+	"math"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// calibratedReranker maps the relevance scores of the inner reranker onto
+// [0, 1], so RerankThreshold and the composite score see the same scale no
+// matter which provider ranked the documents. Every calibration is monotonic,
+// so the order the provider returned is kept.
+type calibratedReranker struct {
+	inner  Reranker
+	params types.RerankParameters
+}
+
+// wrapRerankerCalibration applies params to r. Rerankers without a
+// calibration (or with "none") are returned unchanged.
+func wrapRerankerCalibration(r Reranker, params *types.RerankParameters) Reranker {
+	if params == nil || params.ScoreCalibration == "" || params.ScoreCalibration == types.RerankScoreCalibrationNone {
+		return r
+	}
+	return &calibratedReranker{inner: r, params: *params}
+}
+
+func (c *calibratedReranker) GetModelName() string { return c.inner.GetModelName() }
+func (c *calibratedReranker) GetModelID() string   { return c.inner.GetModelID() }
+
+func (c *calibratedReranker) Rerank(ctx context.Context, query string, documents []string) ([]RankResult, error) {
+	results, err := c.inner.Rerank(ctx, query, documents)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].RelevanceScore = calibrateScore(c.params, results[i].RelevanceScore)
+	}
+	return results, nil
+}
+
+// calibrateScore maps one raw score onto [0, 1] according to params.
+func calibrateScore(params types.RerankParameters, score float64) float64 {
+	switch params.ScoreCalibration {
+	case types.RerankScoreCalibrationSigmoid:
+		scale := params.ScoreScale
+		if scale == 0 {
+			scale = 1
+		}
+		return 1 / (1 + math.Exp(-(scale*score + params.ScoreOffset)))
+	case types.RerankScoreCalibrationLinear:
+		if params.ScoreMax <= params.ScoreMin {
+			return score
+		}
+		return math.Min(1, math.Max(0, (score-params.ScoreMin)/(params.ScoreMax-params.ScoreMin)))
+	default:
+		return score
+	}
+}
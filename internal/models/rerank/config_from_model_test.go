@@ -17,6 +17,9 @@ func TestConfigFromModel(t *testing.T) {
 			Provider:      "siliconflow",
 			ExtraConfig:   map[string]string{"flag": "on"},
 			CustomHeaders: map[string]string{"X-Gateway": "g"},
+			RerankParameters: &types.RerankParameters{
+				ScoreCalibration: types.RerankScoreCalibrationSigmoid,
+			},
 		},
 	}
 	cfg := ConfigFromModel(m, "app", "secret")
@@ -29,4 +32,7 @@ func TestConfigFromModel(t *testing.T) {
 	if cfg.AppID != "app" || cfg.AppSecret != "secret" {
 		t.Errorf("cloud creds mismatch: %+v", cfg)
 	}
+	if cfg.Calibration == nil || cfg.Calibration.ScoreCalibration != types.RerankScoreCalibrationSigmoid {
+		t.Errorf("score calibration not propagated: %+v", cfg.Calibration)
+	}
 }
@@ -90,6 +90,8 @@ type RerankerConfig struct {
 	CustomHeaders map[string]string
 	AppID         string
 	AppSecret     string // 加密值，工厂函数调用方传入，使用前已解密
+	// Calibration maps the provider's relevance scores onto [0, 1]; nil keeps them as returned
+	Calibration *types.RerankParameters
 }
 
 // ConfigFromModel 根据 types.Model 构造 RerankerConfig。
@@ -110,15 +112,20 @@ func ConfigFromModel(m *types.Model, appID, appSecret string) *RerankerConfig {
 		CustomHeaders: m.Parameters.CustomHeaders,
 		AppID:         appID,
 		AppSecret:     appSecret,
+		Calibration:   m.Parameters.RerankParameters,
 	}
 }
 
 // NewReranker creates a reranker based on the configuration
 func NewReranker(config *RerankerConfig) (Reranker, error) {
+	if err := config.Calibration.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rerank score calibration: %w", err)
+	}
 	r, err := newReranker(config)
 	if err != nil {
 		return r, err
 	}
+	r = wrapRerankerCalibration(r, config.Calibration)
 	if logger.LLMDebugEnabled() {
 		r = &debugReranker{inner: r}
 	}
@@ -156,6 +163,8 @@ func newReranker(config *RerankerConfig) (Reranker, error) {
 		reranker, err = NewLKEAPReranker(config)
 	case provider.ProviderVolcengine:
 		reranker, err = NewVolcengineReranker(config)
+	case provider.ProviderTEI:
+		reranker, err = NewTEIReranker(config)
 	default:
 		reranker, err = NewOpenAIReranker(config)
 	}
@@ -0,0 +1,214 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+const (
+	// defaultTEIBatchSize matches TEI's default --max-client-batch-size;
+	// larger requests are rejected with 413.
+	defaultTEIBatchSize = 32
+	// teiRerankConcurrency bounds how many batches of one call are in flight
+	teiRerankConcurrency = 4
+
+	teiAPIFormatTEI      = "tei"
+	teiAPIFormatInfinity = "infinity"
+)
+
+// TEIReranker calls a self-hosted cross-encoder served by Hugging Face Text
+// Embeddings Inference or Infinity. Both cap the number of documents per
+// request, so documents are split into batches that are scored concurrently
+// and merged back into one ranking.
+type TEIReranker struct {
+	modelName     string       // Name of the model used for reranking
+	modelID       string       // Unique identifier of the model
+	apiKey        string       // Optional API key (TEI/Infinity started with --api-key)
+	baseURL       string       // Base URL of the server, without /rerank
+	apiFormat     string       // Request shape: "tei" (texts) or "infinity" (documents)
+	batchSize     int          // Maximum documents per request
+	client        *http.Client // HTTP client for making API requests
+	customHeaders map[string]string
+}
+
+// SetCustomHeaders 设置用户自定义 HTTP 请求头（类似 OpenAI Python SDK 的 extra_headers）。
+func (r *TEIReranker) SetCustomHeaders(headers map[string]string) {
+	r.customHeaders = headers
+}
+
+// TEIRerankRequest is the body of TEI's POST /rerank
+type TEIRerankRequest struct {
+	Query      string   `json:"query"`
+	Texts      []string `json:"texts"`
+	Truncate   bool     `json:"truncate"`    // Truncate inputs longer than the model's max length instead of failing
+	RawScores  bool     `json:"raw_scores"`  // false returns sigmoid-normalized scores
+	ReturnText bool     `json:"return_text"` // Documents are not echoed back
+}
+
+// InfinityRerankRequest is the body of Infinity's POST /rerank
+type InfinityRerankRequest struct {
+	Model           string   `json:"model,omitempty"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+// NewTEIReranker creates a reranker for a TEI or Infinity server. The server
+// usually runs on a private network, so its host must be on the SSRF whitelist.
+func NewTEIReranker(config *RerankerConfig) (*TEIReranker, error) {
+	baseURL := strings.TrimSuffix(strings.TrimSpace(config.BaseURL), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("base URL is required for TEI reranker")
+	}
+	if err := validateRerankBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+
+	apiFormat := teiAPIFormatTEI
+	batchSize := defaultTEIBatchSize
+	if config.ExtraConfig != nil {
+		switch raw := strings.TrimSpace(config.ExtraConfig["api_format"]); raw {
+		case "", teiAPIFormatTEI:
+		case teiAPIFormatInfinity:
+			apiFormat = teiAPIFormatInfinity
+		default:
+			return nil, fmt.Errorf("invalid api_format in extra_config: %q", raw)
+		}
+		if raw := strings.TrimSpace(config.ExtraConfig["batch_size"]); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid batch_size in extra_config: %q", raw)
+			}
+			batchSize = n
+		}
+	}
+
+	return &TEIReranker{
+		modelName: config.ModelName,
+		modelID:   config.ModelID,
+		apiKey:    config.APIKey,
+		baseURL:   baseURL,
+		apiFormat: apiFormat,
+		batchSize: batchSize,
+		client:    newRerankHTTPClient(0),
+	}, nil
+}
+
+// Rerank scores documents in batches of batchSize and returns every result
+// sorted by relevance score descending, with Index pointing into documents.
+func (r *TEIReranker) Rerank(ctx context.Context, query string, documents []string) ([]RankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	logger.Debugf(ctx, "%s", buildRerankRequestDebug(r.modelName, r.baseURL+"/rerank", query, documents))
+
+	batches := make([][]RankResult, (len(documents)+r.batchSize-1)/r.batchSize)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(teiRerankConcurrency)
+	for i := range batches {
+		start := i * r.batchSize
+		end := min(start+r.batchSize, len(documents))
+		g.Go(func() error {
+			results, err := r.rerankBatch(gctx, query, documents[start:end])
+			if err != nil {
+				return err
+			}
+			for j := range results {
+				if results[j].Index < 0 || results[j].Index >= end-start {
+					return fmt.Errorf("rerank result index %d out of range for batch of %d", results[j].Index, end-start)
+				}
+				results[j].Index += start
+				results[j].Document = DocumentInfo{Text: documents[results[j].Index]}
+			}
+			batches[i] = results
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := make([]RankResult, 0, len(documents))
+	for _, results := range batches {
+		merged = append(merged, results...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].RelevanceScore > merged[j].RelevanceScore
+	})
+	return merged, nil
+}
+
+func (r *TEIReranker) rerankBatch(ctx context.Context, query string, documents []string) ([]RankResult, error) {
+	var requestBody any
+	if r.apiFormat == teiAPIFormatInfinity {
+		requestBody = &InfinityRerankRequest{Model: r.modelName, Query: query, Documents: documents}
+	} else {
+		requestBody = &TEIRerankRequest{Query: query, Texts: documents, Truncate: true}
+	}
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.baseURL+"/rerank", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.apiKey))
+	}
+	secutils.ApplyCustomHeaders(req, r.customHeaders)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.GetLogger(ctx).Errorf("TEIReranker API error: Http Status: %s, Body: %s", resp.Status, string(body))
+		return nil, fmt.Errorf("Rerank API error: Http Status: %s", resp.Status)
+	}
+
+	// TEI answers with a bare array of {index, score}; Infinity wraps
+	// {index, relevance_score} in an OpenAI-style results object.
+	var results []RankResult
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &results)
+	} else {
+		var response RerankResponse
+		err = json.Unmarshal(body, &response)
+		results = response.Results
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return results, nil
+}
+
+// GetModelName returns the name of the reranking model
+func (r *TEIReranker) GetModelName() string {
+	return r.modelName
+}
+
+// GetModelID returns the unique identifier of the reranking model
+func (r *TEIReranker) GetModelID() string {
+	return r.modelID
+}
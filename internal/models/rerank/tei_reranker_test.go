@@ -0,0 +1,138 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// newTEITestServer emulates TEI's /rerank: it rejects batches above maxBatch
+// with 413 and scores each text by its trailing number, returning raw logits
+// when raw_scores is set the way a bge cross-encoder would.
+func newTEITestServer(t *testing.T, maxBatch int, batchSizes *[]int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rerank" {
+			http.NotFound(w, r)
+			return
+		}
+		var req TEIRerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		*batchSizes = append(*batchSizes, len(req.Texts))
+		mu.Unlock()
+		if len(req.Texts) > maxBatch {
+			http.Error(w, "batch size exceeds limit", http.StatusRequestEntityTooLarge)
+			return
+		}
+		results := make([]string, 0, len(req.Texts))
+		for i, text := range req.Texts {
+			var n int
+			_, _ = fmt.Sscanf(text[strings.LastIndex(text, " ")+1:], "%d", &n)
+			// Logits around zero: doc-10 scores 0, higher numbers score higher.
+			results = append(results, fmt.Sprintf(`{"index": %d, "score": %g}`, i, float64(n-10)))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(results, ",") + "]"))
+	}))
+}
+
+func TestTEIRerankerBatchesAndMergesResults(t *testing.T) {
+	withRerankSSRFWhitelist(t, "127.0.0.1")
+
+	var batchSizes []int
+	server := newTEITestServer(t, 4, &batchSizes)
+	defer server.Close()
+
+	reranker, err := NewReranker(&RerankerConfig{
+		BaseURL:     server.URL,
+		ModelName:   "bge-reranker-v2-m3",
+		Provider:    "tei",
+		ExtraConfig: map[string]string{"batch_size": "4"},
+		Calibration: &types.RerankParameters{ScoreCalibration: types.RerankScoreCalibrationSigmoid},
+	})
+	if err != nil {
+		t.Fatalf("NewReranker: %v", err)
+	}
+
+	documents := make([]string, 10)
+	for i := range documents {
+		documents[i] = fmt.Sprintf("doc %d", i+5)
+	}
+	results, err := reranker.Rerank(context.Background(), "query", documents)
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+
+	if len(batchSizes) != 3 {
+		t.Fatalf("sent %d batches (%v), want 3 of at most 4 documents", len(batchSizes), batchSizes)
+	}
+	if len(results) != len(documents) {
+		t.Fatalf("got %d results, want %d", len(results), len(documents))
+	}
+	for i, result := range results {
+		if want := len(documents) - 1 - i; result.Index != want {
+			t.Errorf("results[%d].Index = %d, want %d", i, result.Index, want)
+		}
+		if result.Document.Text != documents[result.Index] {
+			t.Errorf("results[%d] document %q does not match index %d", i, result.Document.Text, result.Index)
+		}
+		if result.RelevanceScore < 0 || result.RelevanceScore > 1 {
+			t.Errorf("results[%d] score %f not calibrated onto [0, 1]", i, result.RelevanceScore)
+		}
+	}
+	// doc 10 has logit 0, which the sigmoid maps to 0.5.
+	if got := results[len(documents)-1-5].RelevanceScore; got != 0.5 {
+		t.Errorf("calibrated score of a zero logit = %f, want 0.5", got)
+	}
+}
+
+func TestCalibrateScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		params types.RerankParameters
+		raw    float64
+		want   float64
+	}{
+		{"none", types.RerankParameters{}, 3.5, 3.5},
+		{"sigmoid default scale", types.RerankParameters{ScoreCalibration: types.RerankScoreCalibrationSigmoid}, 0, 0.5},
+		{"sigmoid with offset", types.RerankParameters{
+			ScoreCalibration: types.RerankScoreCalibrationSigmoid, ScoreScale: 2, ScoreOffset: -4,
+		}, 2, 0.5},
+		{"linear", types.RerankParameters{
+			ScoreCalibration: types.RerankScoreCalibrationLinear, ScoreMin: 0, ScoreMax: 10,
+		}, 7.5, 0.75},
+		{"linear clamps", types.RerankParameters{
+			ScoreCalibration: types.RerankScoreCalibrationLinear, ScoreMin: -5, ScoreMax: 5,
+		}, 12, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calibrateScore(tt.params, tt.raw); got != tt.want {
+				t.Errorf("calibrateScore(%v) = %f, want %f", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRerankerRejectsInvalidCalibration(t *testing.T) {
+	_, err := NewReranker(&RerankerConfig{
+		BaseURL:     "https://api.example.com/v1",
+		ModelName:   "rerank",
+		Calibration: &types.RerankParameters{ScoreCalibration: types.RerankScoreCalibrationLinear, ScoreMin: 1, ScoreMax: 1},
+	})
+	if err == nil {
+		t.Fatal("NewReranker accepted a linear calibration with an empty range")
+	}
+}
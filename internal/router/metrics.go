@@ -0,0 +1,38 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMetricsRoute exposes Prometheus metrics at /metrics. The path sits
+// outside /api, so the bundled nginx does not proxy it: scrape the backend
+// port directly. WEKNORA_METRICS_ENABLED=false removes the endpoint, and
+// WEKNORA_METRICS_TOKEN, when set, requires "Authorization: Bearer <token>".
+func RegisterMetricsRoute(r *gin.Engine) {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("WEKNORA_METRICS_ENABLED")), "false") {
+		return
+	}
+	r.GET("/metrics", metricsAuth(os.Getenv("WEKNORA_METRICS_TOKEN")), gin.WrapH(metrics.Handler()))
+}
+
+// metricsAuth checks the scrape bearer token; an empty token allows all scrapes
+func metricsAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsRouteRequiresConfiguredToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("WEKNORA_METRICS_TOKEN", "scrape-secret")
+	r := gin.New()
+	RegisterMetricsRoute(r)
+
+	cases := []struct {
+		auth string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer scrape-secret", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("Authorization %q: status = %d, want %d", tc.auth, w.Code, tc.want)
+		}
+		if tc.want == http.StatusOK && !strings.Contains(w.Body.String(), "go_goroutines") {
+			t.Fatalf("metrics body missing runtime metrics:\n%.200s", w.Body.String())
+		}
+	}
+}
+
+func TestMetricsRouteCanBeDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("WEKNORA_METRICS_ENABLED", "false")
+	r := gin.New()
+	RegisterMetricsRoute(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+}
@@ -73,7 +73,14 @@ type RouterParams struct {
 	ResourceCatalog              interfaces.ResourceCatalog
 	FAQHandler                   *handler.FAQHandler
 	TagHandler                   *handler.TagHandler
+	GraphCommunityHandler        *handler.GraphCommunityHandler
 	CustomAgentHandler           *handler.CustomAgentHandler
+	AnswerCacheHandler           *handler.AnswerCacheHandler
+	AgentTriggerHandler          *handler.AgentTriggerHandler
+	WebhookHandler               *handler.WebhookHandler
+	KnowledgeACLHandler          *handler.KnowledgeACLHandler
+	UserGroupHandler             *handler.UserGroupHandler
+	MCPServerHandler             *handler.MCPServerHandler
 	UserFavoriteHandler          *handler.UserResourceFavoriteHandler
 	SkillHandler                 *handler.SkillHandler
 	OrganizationHandler          *handler.OrganizationHandler
@@ -130,6 +137,9 @@ func NewRouter(params RouterParams) *gin.Engine {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Prometheus 指标（不需要认证，可选 Bearer token）
+	RegisterMetricsRoute(r)
+
 	// Swagger API 文档（仅在非生产环境下启用）
 	// 通过 GIN_MODE 环境变量判断：release 模式下禁用 Swagger
 	if gin.Mode() != gin.ReleaseMode {
@@ -257,6 +267,7 @@ func NewRouter(params RouterParams) *gin.Engine {
 			params.ResourceCatalog,
 		)
 		RegisterKnowledgeTagRoutes(v1, params.TagHandler, rbacGuards)
+		RegisterGraphCommunityRoutes(v1, params.GraphCommunityHandler, rbacGuards)
 		RegisterKnowledgeRoutes(v1, params.KnowledgeHandler, rbacGuards)
 		RegisterFAQRoutes(v1, params.FAQHandler, rbacGuards)
 		RegisterChunkRoutes(v1, params.ChunkHandler, rbacGuards)
@@ -276,6 +287,12 @@ func NewRouter(params RouterParams) *gin.Engine {
 		RegisterVectorStoreRoutes(v1, params.VectorStoreHandler, rbacGuards)
 		RegisterStorageBackendRoutes(v1, params.StorageBackendHandler, rbacGuards)
 		RegisterCustomAgentRoutes(v1, params.CustomAgentHandler, rbacGuards)
+		RegisterAnswerCacheRoutes(v1, params.AnswerCacheHandler, rbacGuards)
+		RegisterAgentTriggerRoutes(v1, params.AgentTriggerHandler, rbacGuards)
+		RegisterWebhookRoutes(v1, params.WebhookHandler, rbacGuards)
+		RegisterKnowledgeACLRoutes(v1, params.KnowledgeACLHandler, rbacGuards)
+		RegisterUserGroupRoutes(v1, params.UserGroupHandler, rbacGuards)
+		RegisterMCPServerRoutes(v1, params.MCPServerHandler, rbacGuards)
 		RegisterUserFavoriteRoutes(v1, params.UserFavoriteHandler, rbacGuards)
 		RegisterSkillRoutes(v1, params.SkillHandler, rbacGuards)
 		RegisterOrganizationRoutes(v1, params.OrganizationHandler, rbacGuards)
@@ -30,6 +30,8 @@ func TestConversationRoutesDeclareChatCapability(t *testing.T) {
 		{http.MethodPost, "/api/v1/sessions/:session_id/suggestion-events"},
 		{http.MethodPost, "/api/v1/knowledge-chat/:session_id"},
 		{http.MethodPost, "/api/v1/agent-chat/:session_id"},
+		{http.MethodGet, "/api/v1/openai/models"},
+		{http.MethodPost, "/api/v1/openai/chat/completions"},
 		{http.MethodGet, "/api/v1/messages/:session_id/load"},
 		{http.MethodDelete, "/api/v1/messages/:session_id/:id"},
 	}
@@ -47,6 +49,27 @@ func TestConversationRoutesDeclareChatCapability(t *testing.T) {
 	}
 }
 
+func TestMCPServerRouteAdmitsRetrieveChatAndReadAgentKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := &rbacGuards{}
+	v1 := gin.New().Group("/api/v1")
+	RegisterMCPServerRoutes(v1, &handler.MCPServerHandler{}, g)
+
+	for _, method := range []string{http.MethodPost, http.MethodGet, http.MethodDelete} {
+		policy := mustLookupAPIKeyPolicy(t, g, method, "/api/v1/mcp")
+		if !policy.RequireFullAccess {
+			t.Fatalf("%s /api/v1/mcp should require full access without a matching capability", method)
+		}
+		for _, capability := range []types.APIKeyCapability{
+			types.APIKeyCapabilityRetrieve, types.APIKeyCapabilityChat, types.APIKeyCapabilityReadAgents,
+		} {
+			if !policyHasCapability(policy, capability) {
+				t.Fatalf("%s /api/v1/mcp capabilities = %#v, want %s", method, policy.Capabilities, capability)
+			}
+		}
+	}
+}
+
 func TestPlatformControlPlaneRoutesDeclarePlatformCapabilities(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	g := &rbacGuards{}
@@ -188,6 +211,8 @@ func TestAgentWriteRoutesRequireManageAgentsCapability(t *testing.T) {
 	v1 := gin.New().Group("/api/v1")
 
 	RegisterCustomAgentRoutes(v1, &handler.CustomAgentHandler{}, g)
+	RegisterAnswerCacheRoutes(v1, &handler.AnswerCacheHandler{}, g)
+	RegisterAgentTriggerRoutes(v1, &handler.AgentTriggerHandler{}, g)
 
 	cases := []struct {
 		method string
@@ -197,6 +222,16 @@ func TestAgentWriteRoutesRequireManageAgentsCapability(t *testing.T) {
 		{http.MethodPut, "/api/v1/agents/:id"},
 		{http.MethodDelete, "/api/v1/agents/:id"},
 		{http.MethodPost, "/api/v1/agents/:id/copy"},
+		{http.MethodGet, "/api/v1/agents/:id/answer-cache"},
+		{http.MethodDelete, "/api/v1/agents/:id/answer-cache"},
+		{http.MethodDelete, "/api/v1/agents/:id/answer-cache/:entry_id"},
+		{http.MethodGet, "/api/v1/agents/:id/triggers"},
+		{http.MethodPost, "/api/v1/agents/:id/triggers"},
+		{http.MethodGet, "/api/v1/agents/:id/triggers/:trigger_id"},
+		{http.MethodPut, "/api/v1/agents/:id/triggers/:trigger_id"},
+		{http.MethodDelete, "/api/v1/agents/:id/triggers/:trigger_id"},
+		{http.MethodPost, "/api/v1/agents/:id/triggers/:trigger_id/run"},
+		{http.MethodGet, "/api/v1/agents/:id/triggers/:trigger_id/runs"},
 	}
 
 	for _, tc := range cases {
@@ -268,6 +303,8 @@ func TestKnowledgeBaseLifecycleRoutesDeclareManageCapability(t *testing.T) {
 		{http.MethodPost, "/api/v1/knowledge-bases"},
 		{http.MethodPost, "/api/v1/knowledge-bases/copy"},
 		{http.MethodPost, "/api/v1/knowledge-bases/:id/duplicate"},
+		{http.MethodPut, "/api/v1/knowledge-bases/:id/embedding-model"},
+		{http.MethodPut, "/api/v1/knowledge-bases/:id/vector-store"},
 	}
 
 	for _, tc := range cases {
@@ -295,6 +332,7 @@ func TestKnowledgeReadRoutesDeclareRetrieveCapability(t *testing.T) {
 	RegisterKnowledgeRoutes(v1, &handler.KnowledgeHandler{}, g)
 	RegisterFAQRoutes(v1, &handler.FAQHandler{}, g)
 	RegisterKnowledgeTagRoutes(v1, &handler.TagHandler{}, g)
+	RegisterGraphCommunityRoutes(v1, &handler.GraphCommunityHandler{}, g)
 	RegisterChatRoutes(v1, &sessionhandler.Handler{}, g)
 	RegisterInitializationRoutes(v1, &handler.InitializationHandler{}, g)
 	RegisterWikiPageRoutes(v1, &handler.WikiPageHandler{}, g)
@@ -306,11 +344,14 @@ func TestKnowledgeReadRoutesDeclareRetrieveCapability(t *testing.T) {
 		{http.MethodGet, "/api/v1/knowledge-bases"},
 		{http.MethodGet, "/api/v1/knowledge-bases/:id"},
 		{http.MethodPost, "/api/v1/knowledge-bases/:id/hybrid-search"},
+		{http.MethodGet, "/api/v1/knowledge-bases/:id/embedding-migration"},
+		{http.MethodGet, "/api/v1/knowledge-bases/:id/vector-store-migration"},
 		{http.MethodGet, "/api/v1/knowledge-bases/:id/knowledge"},
 		{http.MethodGet, "/api/v1/knowledge/:id"},
 		{http.MethodGet, "/api/v1/knowledge/:id/download"},
 		{http.MethodPost, "/api/v1/knowledge-bases/:id/faq/search"},
 		{http.MethodGet, "/api/v1/knowledge-bases/:id/tags"},
+		{http.MethodGet, "/api/v1/knowledge-bases/:id/graph/communities"},
 		{http.MethodPost, "/api/v1/knowledge-search"},
 		{http.MethodGet, "/api/v1/initialization/config/:kbId"},
 		{http.MethodGet, "/api/v1/knowledgebase/:kb_id/wiki/pages"},
@@ -345,6 +386,9 @@ func TestTenantInfrastructureRoutesDeclareSpecificCapabilities(t *testing.T) {
 	RegisterSandboxConfigRoutes(v1, &handler.SandboxConfigHandler{}, g)
 	RegisterEmbedChannelRoutes(v1, &handler.EmbedChannelHandler{}, g)
 	RegisterIMChannelRoutes(v1, &handler.IMHandler{}, g)
+	RegisterWebhookRoutes(v1, &handler.WebhookHandler{}, g)
+	RegisterKnowledgeACLRoutes(v1, &handler.KnowledgeACLHandler{}, g)
+	RegisterUserGroupRoutes(v1, &handler.UserGroupHandler{}, g)
 	RegisterDataSourceRoutes(v1, &handler.DataSourceHandler{}, &handler.DataSourceCredentialsHandler{}, g)
 	RegisterWeKnoraCloudRoutes(v1, &handler.WeKnoraCloudHandler{}, g)
 
@@ -361,6 +405,7 @@ func TestTenantInfrastructureRoutesDeclareSpecificCapabilities(t *testing.T) {
 		{http.MethodGet, "/api/v1/tenants", types.APIKeyCapabilityManageTenantSettings},
 		{http.MethodGet, "/api/v1/models", types.APIKeyCapabilityManageModels},
 		{http.MethodPost, "/api/v1/evaluation", types.APIKeyCapabilityRunEvaluations},
+		{http.MethodPost, "/api/v1/evaluation/datasets", types.APIKeyCapabilityRunEvaluations},
 		{http.MethodGet, "/api/v1/system/info", types.APIKeyCapabilityManageVectorStores},
 		{http.MethodGet, "/api/v1/mcp-services", types.APIKeyCapabilityManageMCPServices},
 		{http.MethodGet, "/api/v1/web-search-providers", types.APIKeyCapabilityManageWebSearch},
@@ -368,6 +413,13 @@ func TestTenantInfrastructureRoutesDeclareSpecificCapabilities(t *testing.T) {
 		{http.MethodGet, "/api/v1/storage-backends", types.APIKeyCapabilityManageStorageBackends},
 		{http.MethodGet, "/api/v1/embed-channels", types.APIKeyCapabilityManageChannels},
 		{http.MethodGet, "/api/v1/im-channels", types.APIKeyCapabilityManageChannels},
+		{http.MethodGet, "/api/v1/webhooks", types.APIKeyCapabilityManageChannels},
+		{http.MethodPost, "/api/v1/webhooks/:id/deliveries/:delivery_id/replay", types.APIKeyCapabilityManageChannels},
+		{http.MethodGet, "/api/v1/knowledge-bases/:id/acl", types.APIKeyCapabilityManageKnowledgeBases},
+		{http.MethodPut, "/api/v1/knowledge-bases/:id/acl/folder", types.APIKeyCapabilityManageKnowledgeBases},
+		{http.MethodPut, "/api/v1/knowledge/:id/acl", types.APIKeyCapabilityManageKnowledgeBases},
+		{http.MethodGet, "/api/v1/user-groups", types.APIKeyCapabilityManageMembers},
+		{http.MethodPut, "/api/v1/user-groups/:id/members", types.APIKeyCapabilityManageMembers},
 		{http.MethodGet, "/api/v1/datasource", types.APIKeyCapabilityManageDataSources},
 		{http.MethodGet, "/api/v1/models/weknoracloud/status", types.APIKeyCapabilityManageModels},
 	}
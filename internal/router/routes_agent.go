@@ -50,6 +50,35 @@ func RegisterCustomAgentRoutes(r *gin.RouterGroup, agentHandler *handler.CustomA
 		apiKeyReadAgents(apiKeyManageAgents(apiKeyChat(apiKeyFullAccess()))), g.Viewer(), agentHandler.GetSuggestedQuestions)
 }
 
+// RegisterAnswerCacheRoutes registers the admin view of an agent's semantic
+// answer cache. Cached answers were generated for any user of the agent, so
+// reading them is limited like editing the agent: creator OR Admin+.
+func RegisterAnswerCacheRoutes(r *gin.RouterGroup, h *handler.AnswerCacheHandler, g *rbacGuards) {
+	answerCache := g.apiKeyGroup(r.Group("/agents/:id/answer-cache"), apiKeyManageAgents(apiKeyFullAccess()))
+	{
+		answerCache.GET("", g.OwnedAgentOrAdmin(), h.ListEntries)
+		answerCache.DELETE("", g.OwnedAgentOrAdmin(), h.ClearEntries)
+		answerCache.DELETE("/:entry_id", g.OwnedAgentOrAdmin(), h.DeleteEntry)
+	}
+}
+
+// RegisterAgentTriggerRoutes registers the scheduled and event-driven
+// triggers of an agent. A trigger runs the agent unattended and delivers its
+// answer, so managing triggers is limited like editing the agent: creator OR
+// Admin+.
+func RegisterAgentTriggerRoutes(r *gin.RouterGroup, h *handler.AgentTriggerHandler, g *rbacGuards) {
+	triggers := g.apiKeyGroup(r.Group("/agents/:id/triggers"), apiKeyManageAgents(apiKeyFullAccess()))
+	{
+		triggers.GET("", g.OwnedAgentOrAdmin(), h.ListTriggers)
+		triggers.POST("", g.OwnedAgentOrAdmin(), h.CreateTrigger)
+		triggers.GET("/:trigger_id", g.OwnedAgentOrAdmin(), h.GetTrigger)
+		triggers.PUT("/:trigger_id", g.OwnedAgentOrAdmin(), h.UpdateTrigger)
+		triggers.DELETE("/:trigger_id", g.OwnedAgentOrAdmin(), h.DeleteTrigger)
+		triggers.POST("/:trigger_id/run", g.OwnedAgentOrAdmin(), h.RunTrigger)
+		triggers.GET("/:trigger_id/runs", g.OwnedAgentOrAdmin(), h.ListRuns)
+	}
+}
+
 // RegisterUserFavoriteRoutes wires the per-user starred-resource endpoints.
 //
 // Authorization: the handler always derives (user_id, tenant_id) from the
@@ -317,3 +317,18 @@ func RegisterSystemAdminRoutes(
 		}
 	}
 }
+
+// RegisterUserGroupRoutes registers the user groups of the current tenant.
+// Anyone who can manage an access list may list groups to pick from; changing
+// them is for Admins.
+func RegisterUserGroupRoutes(r *gin.RouterGroup, h *handler.UserGroupHandler, g *rbacGuards) {
+	groups := g.apiKeyGroup(r.Group("/user-groups"), apiKeyManageMembers(apiKeyFullAccess()))
+	{
+		groups.GET("", g.Viewer(), h.ListGroups)
+		groups.POST("", g.Admin(), h.CreateGroup)
+		groups.GET("/:id", g.Viewer(), h.GetGroup)
+		groups.PUT("/:id", g.Admin(), h.UpdateGroup)
+		groups.DELETE("/:id", g.Admin(), h.DeleteGroup)
+		groups.PUT("/:id/members", g.Admin(), h.SetGroupMembers)
+	}
+}
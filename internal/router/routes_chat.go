@@ -119,4 +119,25 @@ func RegisterChatRoutes(r *gin.RouterGroup, handler *session.Handler, g *rbacGua
 	{
 		knowledgeSearch.POST("", handler.SearchKnowledge)
 	}
+
+	// OpenAI-compatible facade: clients point their base URL at /api/v1/openai
+	// and pick an agent or knowledge base through the "model" field.
+	openAI := g.apiKeyGroup(r.Group("/openai", g.Viewer()), apiKeyChat(apiKeyFullAccess()))
+	{
+		openAI.GET("/models", handler.ListOpenAIModels)
+		openAI.POST("/chat/completions", handler.ChatCompletions)
+	}
+}
+
+// RegisterMCPServerRoutes registers the streamable-HTTP MCP endpoint. Every
+// tool needs one of the retrieve, chat or read_agents capabilities; the
+// server narrows the tool list to what the calling key may use.
+func RegisterMCPServerRoutes(r *gin.RouterGroup, h *handler.MCPServerHandler, g *rbacGuards) {
+	mcpServer := g.apiKeyGroup(r.Group("/mcp", g.Viewer()),
+		apiKeyRetrieve(apiKeyChat(apiKeyReadAgents(apiKeyFullAccess()))))
+	{
+		mcpServer.POST("", h.Serve)
+		mcpServer.GET("", h.Serve)
+		mcpServer.DELETE("", h.Serve)
+	}
 }
@@ -70,6 +70,14 @@ func RegisterEvaluationRoutes(r *gin.RouterGroup, handler *handler.EvaluationHan
 	{
 		evaluationRoutes.POST("", g.Admin(), handler.Evaluation)
 		evaluationRoutes.GET("", g.Viewer(), handler.GetEvaluationResult)
+		evaluationRoutes.GET("/tasks", g.Viewer(), handler.ListEvaluations)
+		evaluationRoutes.GET("/tasks/:task_id/results", g.Viewer(), handler.ListQuestionResults)
+		evaluationRoutes.GET("/compare", g.Viewer(), handler.CompareEvaluations)
+		evaluationRoutes.POST("/fusion/tune", g.Admin(), handler.TuneFusionWeights)
+		// 数据集上传/删除会改动空间内的评估数据 — Admin+
+		evaluationRoutes.POST("/datasets", g.Admin(), handler.UploadDataset)
+		evaluationRoutes.GET("/datasets", g.Viewer(), handler.ListDatasets)
+		evaluationRoutes.DELETE("/datasets/:id", g.Admin(), handler.DeleteDataset)
 	}
 }
 
@@ -147,6 +155,8 @@ func RegisterMCPServiceRoutes(
 		mcpServices.GET("/:id/tools", g.Viewer(), handler.GetMCPServiceTools)
 		// Get MCP service resources — Viewer+
 		mcpServices.GET("/:id/resources", g.Viewer(), handler.GetMCPServiceResources)
+		// Get MCP service prompt templates — Viewer+
+		mcpServices.GET("/:id/prompts", g.Viewer(), handler.GetMCPServicePrompts)
 		// Per-field credential subresource: secrets never travel via the main
 		// PUT body. See internal/handler/mcp_credentials.go for the contract. — Admin+
 		mcpServices.PUT("/:id/credentials", g.Admin(), credHandler.Put)
@@ -318,3 +328,19 @@ func RegisterWeKnoraCloudRoutes(r *gin.RouterGroup, handler *handler.WeKnoraClou
 	g.apiKeyRoute(r, http.MethodPost, "/weknoracloud/credentials", apiKeyManageModels(apiKeyFullAccess()), g.Admin(), handler.SaveCredentials)
 	g.apiKeyRoute(r, http.MethodGet, "/models/weknoracloud/status", apiKeyManageModels(apiKeyFullAccess()), g.Viewer(), handler.Status)
 }
+
+// RegisterWebhookRoutes registers tenant webhook subscriptions and their
+// delivery log. Subscriptions POST workspace content to external URLs and
+// carry signing secrets, so every route is Admin+, reads included.
+func RegisterWebhookRoutes(r *gin.RouterGroup, h *handler.WebhookHandler, g *rbacGuards) {
+	webhooks := g.apiKeyGroup(r.Group("/webhooks"), apiKeyManageChannels(apiKeyFullAccess()))
+	{
+		webhooks.GET("", g.Admin(), h.ListSubscriptions)
+		webhooks.POST("", g.Admin(), h.CreateSubscription)
+		webhooks.GET("/:id", g.Admin(), h.GetSubscription)
+		webhooks.PUT("/:id", g.Admin(), h.UpdateSubscription)
+		webhooks.DELETE("/:id", g.Admin(), h.DeleteSubscription)
+		webhooks.GET("/:id/deliveries", g.Admin(), h.ListDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/replay", g.Admin(), h.ReplayDelivery)
+	}
+}
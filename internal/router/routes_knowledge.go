@@ -214,6 +214,11 @@ func RegisterKnowledgeBaseRoutes(r *gin.RouterGroup, handler *handler.KnowledgeB
 		// 把删除锁死为「所有者租户 + Admin」，共享 editor 无法删除源 KB。
 		kbManagement.PUT("/:id", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), handler.UpdateKnowledgeBase)
 		kbManagement.DELETE("/:id", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), handler.DeleteKnowledgeBase)
+		// 切换 Embedding 模型 — 与 update 同档；已有文档时后台重建索引，进度只读可查。
+		kbManagement.PUT("/:id/embedding-model", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), handler.SetEmbeddingModel)
+		kb.GET("/:id/embedding-migration", g.Viewer(), g.KBAccessRead("id"), handler.GetEmbeddingMigration)
+		kbManagement.PUT("/:id/vector-store", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), handler.MigrateVectorStore)
+		kb.GET("/:id/vector-store-migration", g.Viewer(), g.KBAccessRead("id"), handler.GetVectorStoreMigration)
 		// 置顶/取消置顶知识库 — 创建者本人 OR Admin+ 且对 KB 有 write 权限
 		// Pin state is now per-(user, kb) (migration 000050). Anyone with
 		// at least Viewer-level read access to the KB — including users
@@ -282,6 +287,23 @@ func RegisterKnowledgeTagRoutes(r *gin.RouterGroup, tagHandler *handler.TagHandl
 	}
 }
 
+// RegisterGraphCommunityRoutes registers the knowledge graph community routes.
+//
+// Listing communities is a KB read (Viewer+, KBAccessRead). A rebuild spends
+// one LLM call per community, so it follows the tag write matrix: the KB
+// creator or an Admin+.
+func RegisterGraphCommunityRoutes(r *gin.RouterGroup, communityHandler *handler.GraphCommunityHandler, g *rbacGuards) {
+	if communityHandler == nil {
+		return
+	}
+	communities := g.apiKeyGroup(r.Group("/knowledge-bases/:id/graph/communities"), apiKeyIngest(apiKeyFullAccess()))
+	communitiesRead := communities.With(apiKeyRetrieve(apiKeyFullAccess()))
+	{
+		communitiesRead.GET("", g.Viewer(), g.KBAccessRead("id"), communityHandler.ListCommunities)
+		communities.POST("/rebuild", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), communityHandler.RebuildCommunities)
+	}
+}
+
 // RegisterWikiPageRoutes registers wiki page related routes.
 //
 // Wiki pages are KB content (wiki mode): reads are Viewer+ and gated by
@@ -332,3 +354,20 @@ func RegisterWikiPageRoutes(r *gin.RouterGroup, wikiHandler *handler.WikiPageHan
 		wiki.PUT("/issues/:issue_id/status", g.OwnedWikiKBOrAdmin(), g.KBAccessWrite("kb_id"), wikiHandler.UpdateIssueStatus)
 	}
 }
+
+// RegisterKnowledgeACLRoutes registers the access lists of documents and
+// folders. Managing them is a change to the knowledge base, so it follows the
+// "KB creator OR Admin+" rule and needs a knowledge-base-management API key.
+func RegisterKnowledgeACLRoutes(r *gin.RouterGroup, h *handler.KnowledgeACLHandler, g *rbacGuards) {
+	kb := g.apiKeyGroup(r.Group("/knowledge-bases/:id/acl"), apiKeyManageKnowledgeBases(apiKeyFullAccess()))
+	{
+		kb.GET("", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), h.ListKnowledgeBaseACLs)
+		kb.GET("/folder", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), h.GetFolderACL)
+		kb.PUT("/folder", g.OwnedKBOrAdmin(), g.KBAccessWrite("id"), h.SetFolderACL)
+	}
+	k := g.apiKeyGroup(r.Group("/knowledge/:id/acl"), apiKeyManageKnowledgeBases(apiKeyFullAccess()))
+	{
+		k.GET("", g.OwnedKnowledgeKBOrAdmin(), g.KBAccessWriteFromKnowledgeIDParam("id"), h.GetKnowledgeACL)
+		k.PUT("", g.OwnedKnowledgeKBOrAdmin(), g.KBAccessWriteFromKnowledgeIDParam("id"), h.SetKnowledgeACL)
+	}
+}
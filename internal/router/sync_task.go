@@ -106,6 +106,13 @@ func (e *SyncTaskExecutor) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asy
 					task.Type(), taskID, time.Since(start))
 				return
 			}
+			if !asynqIsFailure(lastErr) {
+				// Held by a vector store migration: wait it out without
+				// spending an attempt, as the asynq server does.
+				logger.Infof(ctx, "[SyncTask] Holding task type=%s id=%s: %v", task.Type(), taskID, lastErr)
+				time.Sleep(vectorStoreMigrationRetryDelay)
+				attempt--
+			}
 		}
 
 		logger.Errorf(ctx, "[SyncTask] Task failed (exhausted retries) type=%s id=%s elapsed=%v err=%v",
@@ -123,6 +130,8 @@ type SyncTaskParams struct {
 	KnowledgeBaseService interfaces.KnowledgeBaseService
 	TagService           interfaces.KnowledgeTagService
 	DataSourceService    interfaces.DataSourceService
+	AgentTriggerService  interfaces.AgentTriggerService
+	WebhookService       interfaces.WebhookService
 	ChunkExtractor       interfaces.TaskHandler `name:"chunkExtractor"`
 	DataTableSummary     interfaces.TaskHandler `name:"dataTableSummary"`
 	ImageMultimodal      interfaces.TaskHandler `name:"imageMultimodal"`
@@ -131,6 +140,7 @@ type SyncTaskParams struct {
 	WikiIngest           interfaces.TaskHandler `name:"wikiIngest"`
 	TemporaryDocument    interfaces.TemporaryDocumentService
 	MemoryService        interfaces.MemoryService
+	CommunityService     interfaces.GraphCommunityService
 }
 
 // RegisterSyncHandlers registers all task handlers on the SyncTaskExecutor.
@@ -150,12 +160,17 @@ func RegisterSyncHandlers(params SyncTaskParams) {
 	params.Executor.RegisterHandler(types.TypeKnowledgeListReparse, params.KnowledgeService.ProcessKnowledgeListReparse)
 	params.Executor.RegisterHandler(types.TypeIndexDelete, params.TagService.ProcessIndexDelete)
 	params.Executor.RegisterHandler(types.TypeKBDelete, params.KnowledgeBaseService.ProcessKBDelete)
+	params.Executor.RegisterHandler(types.TypeKBReembed, params.KnowledgeBaseService.ProcessEmbeddingMigration)
+	params.Executor.RegisterHandler(types.TypeKBVectorStoreMigrate, params.KnowledgeBaseService.ProcessVectorStoreMigration)
 	params.Executor.RegisterHandler(types.TypeImageMultimodal, params.ImageMultimodal.Handle)
 	params.Executor.RegisterHandler(types.TypeKnowledgePostProcess, params.KnowledgePostProcess.Handle)
 	params.Executor.RegisterHandler(types.TypeKnowledgeAutoTag, params.KnowledgeAutoTag.Handle)
 	params.Executor.RegisterHandler(types.TypeDataSourceSync, params.DataSourceService.ProcessSync)
+	params.Executor.RegisterHandler(types.TypeAgentTriggerRun, params.AgentTriggerService.ProcessRun)
+	params.Executor.RegisterHandler(types.TypeWebhookDeliver, params.WebhookService.ProcessDelivery)
 	params.Executor.RegisterHandler(types.TypeWikiIngest, params.WikiIngest.Handle)
 	params.Executor.RegisterHandler(types.TypeWikiFinalize, params.WikiIngest.Handle)
 	params.Executor.RegisterHandler(types.TypeMemoryExtract, params.MemoryService.Handle)
+	params.Executor.RegisterHandler(types.TypeGraphCommunityBuild, params.CommunityService.ProcessCommunityBuild)
 	logger.Infof(context.Background(), "[SyncTask] All task handlers registered (Lite mode, no Redis)")
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/application/service"
 	"github.com/Tencent/WeKnora/internal/common"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/metrics"
 	"github.com/Tencent/WeKnora/internal/middleware/asynqdl"
 	"github.com/Tencent/WeKnora/internal/tracing/langfuse"
 	"github.com/Tencent/WeKnora/internal/types"
@@ -38,6 +39,8 @@ type AsynqTaskParams struct {
 	KnowledgeBaseService interfaces.KnowledgeBaseService
 	TagService           interfaces.KnowledgeTagService
 	DataSourceService    interfaces.DataSourceService
+	AgentTriggerService  interfaces.AgentTriggerService
+	WebhookService       interfaces.WebhookService
 	ChunkExtractor       interfaces.TaskHandler `name:"chunkExtractor"`
 	DataTableSummary     interfaces.TaskHandler `name:"dataTableSummary"`
 	ImageMultimodal      interfaces.TaskHandler `name:"imageMultimodal"`
@@ -46,6 +49,7 @@ type AsynqTaskParams struct {
 	WikiIngest           interfaces.TaskHandler `name:"wikiIngest"`
 	TemporaryDocument    interfaces.TemporaryDocumentService
 	MemoryService        interfaces.MemoryService
+	CommunityService     interfaces.GraphCommunityService
 	DeadLetterRepo       interfaces.TaskDeadLetterRepository
 	SpanTracker          service.SpanTracker
 }
@@ -107,6 +111,11 @@ func NewAsyncqClient() (*asynq.Client, error) {
 // burning through retries; but short enough that users don't feel the stall.
 const wikiIngestRetryDelay = 15 * time.Second
 
+// vectorStoreMigrationRetryDelay spaces out the retries of tasks held while
+// their knowledge base moves to another vector store. Those retries are not
+// counted (see asynqIsFailure), so a long migration cannot exhaust them.
+const vectorStoreMigrationRetryDelay = time.Minute
+
 // asynqRetryDelayFunc customizes per-task retry backoff.
 //
 // Default asynq backoff is exponential (≈10s, 40s, 90s, 2.5m, ...), which
@@ -121,9 +130,20 @@ func asynqRetryDelayFunc(n int, e error, t *asynq.Task) time.Duration {
 	if errors.Is(e, service.ErrWikiIngestConcurrent) {
 		return wikiIngestRetryDelay
 	}
+	if errors.Is(e, service.ErrVectorStoreMigrating) {
+		return vectorStoreMigrationRetryDelay
+	}
 	return asynq.DefaultRetryDelayFunc(n, e, t)
 }
 
+// asynqIsFailure reports whether a task error uses up one of the task's
+// retries. A task held by a vector store or embedding model migration is
+// requeued instead, so it runs once the migration finishes however long that
+// takes.
+func asynqIsFailure(err error) bool {
+	return !errors.Is(err, service.ErrVectorStoreMigrating)
+}
+
 // Worker defaults live in types so server construction and runtime reporting
 // cannot drift. The upstream budget is divided without increasing historical
 // total capacity; Wiki remains separate because its model-heavy workload has a
@@ -140,6 +160,7 @@ func newAsynqServer(concurrency int, queues map[string]int) *asynq.Server {
 			Concurrency:    concurrency,
 			Queues:         queues,
 			RetryDelayFunc: asynqRetryDelayFunc,
+			IsFailure:      asynqIsFailure,
 		},
 	)
 }
@@ -259,6 +280,10 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// chat / rerank / ASR) nest correctly in the Langfuse UI.
 	mux.Use(langfuse.AsynqMiddleware())
 
+	// Count every processed task by type and outcome for /metrics. Sits
+	// inside the dead-letter middleware so it sees the handler's own error.
+	mux.Use(metrics.AsynqMiddleware())
+
 	// Register extract handlers - router will dispatch to appropriate handler
 	mux.HandleFunc(types.TypeChunkExtract, params.ChunkExtractor.Handle)
 	mux.HandleFunc(types.TypeDataTableSummary, params.DataTableSummary.Handle)
@@ -297,6 +322,10 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// Register KB delete handler
 	mux.HandleFunc(types.TypeKBDelete, params.KnowledgeBaseService.ProcessKBDelete)
 
+	// Register KB re-embed handler
+	mux.HandleFunc(types.TypeKBReembed, params.KnowledgeBaseService.ProcessEmbeddingMigration)
+	mux.HandleFunc(types.TypeKBVectorStoreMigrate, params.KnowledgeBaseService.ProcessVectorStoreMigration)
+
 	// Register image multimodal handler
 	mux.HandleFunc(types.TypeImageMultimodal, params.ImageMultimodal.Handle)
 
@@ -307,6 +336,12 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// Register data source sync handler
 	mux.HandleFunc(types.TypeDataSourceSync, params.DataSourceService.ProcessSync)
 
+	// Register scheduled / event-driven agent run handler
+	mux.HandleFunc(types.TypeAgentTriggerRun, params.AgentTriggerService.ProcessRun)
+
+	// Register tenant webhook delivery handler
+	mux.HandleFunc(types.TypeWebhookDeliver, params.WebhookService.ProcessDelivery)
+
 	// Register wiki ingest handler + the debounced KB-global finalize handler.
 	// Both route to the same dispatch (WikiIngest.Handle switches on task type)
 	// and both land on QueueWiki, so the dedicated wiki pool serves them.
@@ -316,6 +351,9 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// Register long-term memory distillation handler
 	mux.HandleFunc(types.TypeMemoryExtract, params.MemoryService.Handle)
 
+	// Register the debounced graph community rebuild handler
+	mux.HandleFunc(types.TypeGraphCommunityBuild, params.CommunityService.ProcessCommunityBuild)
+
 	// Run the same mux on every pool. Shared and dedicated servers intentionally
 	// overlap, but Redis dequeue is atomic, so each task still executes once.
 	runPool := func(name string, srv *asynq.Server) {
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/application/service"
+	"github.com/hibiken/asynq"
+)
+
+func TestVectorStoreMigrationHoldIsNotCountedAsFailure(t *testing.T) {
+	held := fmt.Errorf("process document: %w", service.ErrVectorStoreMigrating)
+	if asynqIsFailure(held) {
+		t.Error("a task held by a vector store migration must not use up a retry")
+	}
+	if !asynqIsFailure(errors.New("embedding timeout")) {
+		t.Error("ordinary task errors must still count as failures")
+	}
+	task := asynq.NewTask("document:process", nil)
+	if got := asynqRetryDelayFunc(20, held, task); got != vectorStoreMigrationRetryDelay {
+		t.Errorf("retry delay = %s, want %s", got, vectorStoreMigrationRetryDelay)
+	}
+}
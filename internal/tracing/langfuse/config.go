@@ -3,6 +3,10 @@
 // generations plus the surrounding agent/HTTP/asynq spans as OTLP/HTTP spans
 // to a Langfuse v3+ or LiteFuse backend (POST /api/public/otel/v1/traces).
 //
+// The same spans can additionally be exported to any OTLP backend (Jaeger,
+// Tempo, an OTel Collector) configured through the standard OTEL_EXPORTER_OTLP_*
+// variables; see OTLPConfig.
+//
 // The integration is fully opt-in: when disabled (the default), every public
 // entry point is a cheap no-op, so callers can wire them unconditionally. A
 // W3C traceparent propagated from upstream callers (e.g. sop3) is inherited so
@@ -52,6 +56,12 @@ type Config struct {
 	// Debug enables verbose logging of batch send errors.
 	Debug bool
 
+	// OTLP configures an additional generic OTLP trace exporter (Jaeger,
+	// Tempo, an OpenTelemetry Collector, …). It is independent of Enabled:
+	// either backend alone is enough to start recording spans, and when both
+	// are on every span is exported to both.
+	OTLP OTLPConfig
+
 	// testExporter, when non-nil, replaces the real OTLP exporter. Tests use
 	// it to inject an in-memory span exporter (tracetest.InMemoryExporter) so
 	// they can assert on exported spans deterministically without an HTTP
@@ -118,11 +128,17 @@ func LoadConfigFromEnv() Config {
 		cfg.SampleRate = 1.0
 	}
 
+	cfg.OTLP = loadOTLPConfigFromEnv()
+
 	return cfg
 }
 
-// Validate verifies required fields are present when Langfuse is enabled.
+// Validate verifies required fields are present when Langfuse is enabled and
+// that the OTLP exporter settings are usable.
 func (c Config) Validate() error {
+	if err := c.OTLP.Validate(); err != nil {
+		return err
+	}
 	if !c.Enabled {
 		return nil
 	}
@@ -25,7 +25,8 @@ var propagator = propagation.TraceContext{}
 // and still invoke methods — every public method tolerates a nil receiver.
 //
 // Internally the manager owns an OpenTelemetry TracerProvider backed by an
-// OTLP/HTTP exporter pointing at the Langfuse v3+ / LiteFuse OTel endpoint.
+// OTLP/HTTP exporter pointing at the Langfuse v3+ / LiteFuse OTel endpoint
+// and/or a generic OTLP exporter (see OTLPConfig).
 // The handles (*Trace / *Span / *Generation) wrap OTel spans; spans are
 // buffered by the BatchSpanProcessor and exported complete on End, so there
 // is no per-flush-batch duplication of root spans (the bug the legacy
@@ -45,52 +46,37 @@ var (
 )
 
 // Init builds a Manager from cfg and installs it as the package-wide
-// singleton. When cfg.Enabled is false this returns a disabled manager that
+// singleton. When neither Langfuse (cfg.Enabled) nor the generic OTLP
+// exporter (cfg.OTLP.Enabled) is on, this returns a disabled manager that
 // behaves as a no-op for every public method.
 func Init(cfg Config) (*Manager, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 	m := &Manager{cfg: cfg}
-	if cfg.Enabled {
-		resAttrs := []attribute.KeyValue{
-			attribute.String("service.name", "weknora"),
-			attribute.String(attrLangfusePubKey, cfg.PublicKey),
-		}
-		if cfg.Environment != "" {
-			resAttrs = append(resAttrs, attribute.String(attrEnvironment, cfg.Environment))
-		}
-		if cfg.Release != "" {
-			resAttrs = append(resAttrs, attribute.String(attrRelease, cfg.Release))
-		}
-		res, err := resource.New(context.Background(), resource.WithAttributes(resAttrs...))
+	if cfg.exporting() {
+		res, err := newResource(cfg)
 		if err != nil {
 			return nil, err
 		}
-		var sp sdktrace.SpanProcessor
-		if cfg.testExporter != nil {
-			// Test mode: synchronous export on span End (deterministic).
-			sp = sdktrace.NewSimpleSpanProcessor(cfg.testExporter)
-		} else {
-			exp, err := newExporter(context.Background(), cfg)
-			if err != nil {
-				return nil, err
-			}
-			sp = sdktrace.NewBatchSpanProcessor(exp,
-				sdktrace.WithBatchTimeout(cfg.FlushInterval),
-				sdktrace.WithMaxExportBatchSize(cfg.FlushAt),
-				sdktrace.WithMaxQueueSize(cfg.QueueSize),
-			)
+		processors, err := newSpanProcessors(cfg)
+		if err != nil {
+			return nil, err
 		}
-		m.tp = sdktrace.NewTracerProvider(
+		opts := []sdktrace.TracerProviderOption{
 			sdktrace.WithResource(res),
-			sdktrace.WithSpanProcessor(sp),
 			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
-		)
-		m.tracer = m.tp.Tracer(langfuseScopeName,
-			trace.WithInstrumentationVersion(langfuseScopeVersion),
-			trace.WithInstrumentationAttributes(attribute.String("public_key", cfg.PublicKey)),
-		)
+		}
+		for _, sp := range processors {
+			opts = append(opts, sdktrace.WithSpanProcessor(sp))
+		}
+		m.tp = sdktrace.NewTracerProvider(opts...)
+		tracerOpts := []trace.TracerOption{trace.WithInstrumentationVersion(langfuseScopeVersion)}
+		if cfg.Enabled {
+			tracerOpts = append(tracerOpts,
+				trace.WithInstrumentationAttributes(attribute.String("public_key", cfg.PublicKey)))
+		}
+		m.tracer = m.tp.Tracer(langfuseScopeName, tracerOpts...)
 		// Extraction/injection in this package use the package-level
 		// `propagator` directly, so we deliberately do NOT call
 		// otel.SetTextMapPropagator here — mutating global OTel state could
@@ -107,9 +93,72 @@ func Init(cfg Config) (*Manager, error) {
 			cfg.Host, cfg.FlushAt, cfg.FlushInterval, cfg.SampleRate,
 		)
 	}
+	if cfg.OTLP.Enabled {
+		logger.Infof(context.Background(),
+			"[Tracing] OTLP exporter enabled endpoint=%s protocol=%s sample_rate=%.2f",
+			cfg.OTLP.Endpoint, cfg.OTLP.Protocol, cfg.SampleRate,
+		)
+	}
 	return m, nil
 }
 
+// exporting reports whether at least one span backend is configured.
+func (c Config) exporting() bool {
+	return c.Enabled || c.OTLP.Enabled
+}
+
+// newResource describes this process. The Langfuse public key is only
+// attached when Langfuse is on; with the generic OTLP exporter the
+// OTEL_SERVICE_NAME / OTEL_RESOURCE_ATTRIBUTES variables override the
+// defaults so spans land under the expected service in Jaeger / Tempo.
+func newResource(cfg Config) (*resource.Resource, error) {
+	resAttrs := []attribute.KeyValue{attribute.String("service.name", "weknora")}
+	if cfg.Enabled {
+		resAttrs = append(resAttrs, attribute.String(attrLangfusePubKey, cfg.PublicKey))
+	}
+	if cfg.Environment != "" {
+		resAttrs = append(resAttrs, attribute.String(attrEnvironment, cfg.Environment))
+	}
+	if cfg.Release != "" {
+		resAttrs = append(resAttrs, attribute.String(attrRelease, cfg.Release))
+	}
+	opts := []resource.Option{resource.WithAttributes(resAttrs...)}
+	if cfg.OTLP.Enabled {
+		opts = append(opts, resource.WithFromEnv())
+	}
+	return resource.New(context.Background(), opts...)
+}
+
+// newSpanProcessors returns one batch processor per enabled backend so a
+// slow or unreachable backend never delays export to the other.
+func newSpanProcessors(cfg Config) ([]sdktrace.SpanProcessor, error) {
+	if cfg.testExporter != nil {
+		// Test mode: synchronous export on span End (deterministic).
+		return []sdktrace.SpanProcessor{sdktrace.NewSimpleSpanProcessor(cfg.testExporter)}, nil
+	}
+	batchOpts := []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(cfg.FlushInterval),
+		sdktrace.WithMaxExportBatchSize(cfg.FlushAt),
+		sdktrace.WithMaxQueueSize(cfg.QueueSize),
+	}
+	var processors []sdktrace.SpanProcessor
+	if cfg.Enabled {
+		exp, err := newExporter(context.Background(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		processors = append(processors, sdktrace.NewBatchSpanProcessor(exp, batchOpts...))
+	}
+	if cfg.OTLP.Enabled {
+		exp, err := newOTLPExporter(context.Background(), cfg.OTLP)
+		if err != nil {
+			return nil, err
+		}
+		processors = append(processors, sdktrace.NewBatchSpanProcessor(exp, batchOpts...))
+	}
+	return processors, nil
+}
+
 // GetManager returns the installed singleton, or nil if Init has not been
 // called. Callers must tolerate a nil return.
 func GetManager() *Manager {
@@ -120,7 +169,7 @@ func GetManager() *Manager {
 
 // Enabled reports whether the manager would actually emit spans.
 func (m *Manager) Enabled() bool {
-	return m != nil && m.cfg.Enabled && !m.closed.Load() && m.tp != nil
+	return m != nil && m.cfg.exporting() && !m.closed.Load() && m.tp != nil
 }
 
 // Tracer exposes the OTel tracer so middleware can create spans directly
@@ -136,7 +185,7 @@ func (m *Manager) Tracer() trace.Tracer {
 // Shutdown flushes pending spans and releases the exporter. Safe to call
 // multiple times.
 func (m *Manager) Shutdown(ctx context.Context) error {
-	if m == nil || !m.cfg.Enabled || m.tp == nil {
+	if m == nil || !m.cfg.exporting() || m.tp == nil {
 		return nil
 	}
 	if !m.closed.CompareAndSwap(false, true) {
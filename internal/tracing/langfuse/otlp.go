@@ -0,0 +1,85 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// OTLP protocols accepted in OTEL_EXPORTER_OTLP_(TRACES_)PROTOCOL.
+const (
+	OTLPProtocolGRPC         = "grpc"
+	OTLPProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// OTLPConfig configures the generic OTLP trace exporter. It is driven by the
+// standard OTEL_* environment variables so WeKnora drops into an existing
+// Jaeger / Tempo / Collector setup without bespoke settings.
+type OTLPConfig struct {
+	// Enabled is true when an OTLP endpoint is configured and the SDK is not
+	// disabled via OTEL_SDK_DISABLED.
+	Enabled bool
+	// Endpoint is the configured endpoint, kept for logging only. The
+	// exporter itself re-reads the OTEL_EXPORTER_OTLP_* variables so that
+	// headers, TLS, timeout and compression settings apply unchanged.
+	Endpoint string
+	// Protocol is either OTLPProtocolGRPC or OTLPProtocolHTTPProtobuf.
+	Protocol string
+}
+
+// loadOTLPConfigFromEnv reads the OTLP exporter settings. The signal-specific
+// OTEL_EXPORTER_OTLP_TRACES_* variables take precedence over the generic
+// OTEL_EXPORTER_OTLP_* ones, as in every other OTel SDK.
+func loadOTLPConfigFromEnv() OTLPConfig {
+	cfg := OTLPConfig{
+		Endpoint: firstNonEmpty(
+			os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+			os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		),
+		Protocol: strings.ToLower(firstNonEmpty(
+			os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"),
+			os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"),
+			OTLPProtocolHTTPProtobuf,
+		)),
+	}
+	cfg.Enabled = cfg.Endpoint != "" && !parseBool(os.Getenv("OTEL_SDK_DISABLED"))
+	return cfg
+}
+
+// Validate rejects protocols the exporter cannot speak. "http/json" is part
+// of the spec but not implemented by the Go SDK.
+func (c OTLPConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Protocol {
+	case OTLPProtocolGRPC, OTLPProtocolHTTPProtobuf:
+		return nil
+	}
+	return fmt.Errorf("langfuse: unsupported OTLP protocol %q (want %s or %s)",
+		c.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTPProtobuf)
+}
+
+// newOTLPExporter builds the generic OTLP trace exporter. No options are
+// passed on purpose: both exporters resolve endpoint, headers, TLS, timeout
+// and compression from the OTEL_EXPORTER_OTLP_* environment themselves.
+func newOTLPExporter(ctx context.Context, cfg OTLPConfig) (sdktrace.SpanExporter, error) {
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	if cfg.Protocol == OTLPProtocolGRPC {
+		exp, err = otlptracegrpc.New(ctx)
+	} else {
+		exp, err = otlptracehttp.New(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("langfuse: build generic otlp exporter: %w", err)
+	}
+	return exp, nil
+}
@@ -0,0 +1,134 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLoadOTLPConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	if cfg := loadOTLPConfigFromEnv(); cfg.Enabled {
+		t.Fatalf("expected OTLP disabled without an endpoint, got %+v", cfg)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	cfg := loadOTLPConfigFromEnv()
+	if !cfg.Enabled || cfg.Protocol != OTLPProtocolHTTPProtobuf {
+		t.Fatalf("expected enabled http/protobuf exporter, got %+v", cfg)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "GRPC")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://tempo:4317")
+	cfg = loadOTLPConfigFromEnv()
+	if cfg.Protocol != OTLPProtocolGRPC || cfg.Endpoint != "http://tempo:4317" {
+		t.Fatalf("expected traces-specific settings to win, got %+v", cfg)
+	}
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if cfg := loadOTLPConfigFromEnv(); cfg.Enabled {
+		t.Fatal("expected OTEL_SDK_DISABLED to switch the exporter off")
+	}
+}
+
+func TestOTLPConfig_ValidateRejectsHTTPJSON(t *testing.T) {
+	cfg := Config{OTLP: OTLPConfig{Enabled: true, Endpoint: "http://x", Protocol: "http/json"}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected http/json to be rejected")
+	}
+}
+
+// TestManager_OTLPOnlyRecordsSpans verifies spans are recorded when only the
+// generic OTLP exporter is configured and Langfuse credentials are absent.
+func TestManager_OTLPOnlyRecordsSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	m, err := Init(Config{
+		OTLP:          OTLPConfig{Enabled: true, Endpoint: "http://collector:4318", Protocol: OTLPProtocolHTTPProtobuf},
+		FlushAt:       1,
+		FlushInterval: time.Second,
+		QueueSize:     32,
+		SampleRate:    1.0,
+		testExporter:  exp,
+	})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+	if !m.Enabled() {
+		t.Fatal("expected manager enabled with OTLP only")
+	}
+
+	_, span := m.StartSpan(context.Background(), SpanOptions{Name: "docreader.read"})
+	span.Finish(nil, nil, nil)
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected span plus auto-created root, got %d", len(spans))
+	}
+	for _, kv := range spans[0].Resource.Attributes() {
+		if string(kv.Key) == attrLangfusePubKey {
+			t.Fatal("langfuse public key must not be attached when Langfuse is off")
+		}
+	}
+}
+
+func TestHTTPTransport_InjectsTraceparent(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: HTTPTransport(nil)}
+	ctx, span := m.StartSpan(context.Background(), SpanOptions{Name: "llm"})
+	defer span.Finish(nil, nil, nil)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	if got == "" {
+		t.Fatal("expected traceparent header on outbound request")
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Fatal("transport must not mutate the caller's request")
+	}
+}
+
+func TestGRPCUnaryInterceptor_InjectsTraceparent(t *testing.T) {
+	m, _ := newTestManager(t)
+	ctx, span := m.StartSpan(context.Background(), SpanOptions{Name: "docreader.read"})
+	defer span.Finish(nil, nil, nil)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer t")
+
+	var md metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := unaryClientInterceptor(ctx, "/docreader.DocReader/Read", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if len(md.Get("traceparent")) != 1 {
+		t.Fatalf("expected traceparent metadata, got %v", md)
+	}
+	if len(md.Get("authorization")) != 1 {
+		t.Fatalf("existing metadata must be preserved, got %v", md)
+	}
+}
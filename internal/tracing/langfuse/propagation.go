@@ -0,0 +1,83 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// InjectHTTPHeaders writes the W3C traceparent of the span carried by ctx into
+// h, so the downstream service (docreader, a model provider behind an OTel
+// gateway, …) can continue the same trace. It is a no-op when ctx carries no
+// valid span context.
+func InjectHTTPHeaders(ctx context.Context, h http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// HTTPTransport wraps base so every outbound request carries the traceparent
+// of its request context. A nil base means http.DefaultTransport.
+func HTTPTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &propagatingTransport{base: base}
+}
+
+type propagatingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *propagatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	carrier := propagation.HeaderCarrier(http.Header{})
+	propagator.Inject(req.Context(), carrier)
+	if len(carrier) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTrippers must not mutate the caller's request.
+	req = req.Clone(req.Context())
+	for k, v := range carrier {
+		req.Header[k] = v
+	}
+	return t.base.RoundTrip(req)
+}
+
+// GRPCDialOptions returns client interceptors that propagate the W3C trace
+// context of the call's ctx as gRPC metadata (lower-cased traceparent /
+// tracestate keys, as expected by OTel gRPC server instrumentation).
+func GRPCDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(streamClientInterceptor),
+	}
+}
+
+func unaryClientInterceptor(
+	ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	return invoker(injectGRPCMetadata(ctx), method, req, reply, cc, opts...)
+}
+
+func streamClientInterceptor(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return streamer(injectGRPCMetadata(ctx), desc, cc, method, opts...)
+}
+
+func injectGRPCMetadata(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ctx
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for k, v := range carrier {
+		md.Set(k, v)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
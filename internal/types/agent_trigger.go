@@ -0,0 +1,241 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AgentTriggerType names what starts an agent trigger run
+type AgentTriggerType string
+
+const (
+	// AgentTriggerTypeSchedule runs the agent on a cron schedule
+	AgentTriggerTypeSchedule AgentTriggerType = "schedule"
+	// AgentTriggerTypeEvent runs the agent when a matching event happens
+	AgentTriggerTypeEvent AgentTriggerType = "event"
+)
+
+// AgentTriggerEventType names an event an agent trigger can listen to
+type AgentTriggerEventType string
+
+const (
+	// AgentTriggerEventKnowledgeIngested fires when a knowledge item of a
+	// knowledge base finishes processing
+	AgentTriggerEventKnowledgeIngested AgentTriggerEventType = "knowledge_ingested"
+	// AgentTriggerEventDataSourceSynced fires when a datasource sync finishes,
+	// whatever its outcome
+	AgentTriggerEventDataSourceSynced AgentTriggerEventType = "datasource_sync_completed"
+	// AgentTriggerEventWikiIssueFlagged fires when an issue is flagged on a
+	// wiki page
+	AgentTriggerEventWikiIssueFlagged AgentTriggerEventType = "wiki_issue_flagged"
+)
+
+// AgentTriggerDeliveryType names where the output of a run goes
+type AgentTriggerDeliveryType string
+
+const (
+	// AgentTriggerDeliveryNone only records the answer on the run
+	AgentTriggerDeliveryNone AgentTriggerDeliveryType = "none"
+	// AgentTriggerDeliveryIM sends the answer to a chat of an IM channel
+	AgentTriggerDeliveryIM AgentTriggerDeliveryType = "im"
+	// AgentTriggerDeliveryWebhook POSTs the answer to a URL, signed with
+	// HMAC-SHA256 when a secret is set
+	AgentTriggerDeliveryWebhook AgentTriggerDeliveryType = "webhook"
+	// AgentTriggerDeliveryKnowledge saves the answer as a manual knowledge
+	// document
+	AgentTriggerDeliveryKnowledge AgentTriggerDeliveryType = "knowledge"
+)
+
+// Agent trigger run statuses
+const (
+	AgentTriggerRunPending = "pending"
+	AgentTriggerRunRunning = "running"
+	AgentTriggerRunSuccess = "success"
+	AgentTriggerRunFailed  = "failed"
+)
+
+// Agent trigger run sources
+const (
+	AgentTriggerSourceSchedule = "schedule"
+	AgentTriggerSourceEvent    = "event"
+	AgentTriggerSourceManual   = "manual"
+)
+
+// AgentTriggerEventPlaceholder in a trigger prompt is replaced with the
+// event that started the run, rendered as JSON marked as untrusted data.
+// Without it the event is appended to the prompt.
+const AgentTriggerEventPlaceholder = "{{event}}"
+
+// AgentTrigger runs a custom agent without a user message: on a cron
+// schedule or when an event happens in the workspace. The prompt is sent to
+// the agent as the user message of a fresh session and the answer is
+// delivered as configured.
+type AgentTrigger struct {
+	ID       string `json:"id"        gorm:"type:varchar(36);primaryKey"`
+	TenantID uint64 `json:"tenant_id" gorm:"index"`
+	AgentID  string `json:"agent_id"  gorm:"type:varchar(36);index"`
+	Name     string `json:"name"      gorm:"type:varchar(255)"`
+	Enabled  bool   `json:"enabled"`
+
+	TriggerType AgentTriggerType `json:"trigger_type" gorm:"type:varchar(20)"`
+	// Schedule is a cron expression with seconds, e.g. "0 0 9 * * 1-5" for
+	// 09:00 on weekdays. Used by schedule triggers.
+	Schedule string `json:"schedule,omitempty" gorm:"type:varchar(100)"`
+	// EventType is the event an event trigger listens to
+	EventType AgentTriggerEventType `json:"event_type,omitempty" gorm:"type:varchar(50);index"`
+	// KnowledgeBaseIDs restricts an event trigger to events of these
+	// knowledge bases; empty means any knowledge base of the workspace
+	KnowledgeBaseIDs StringArray `json:"knowledge_base_ids" gorm:"type:json"`
+	// CooldownSeconds is the minimum time between two event runs. Events in
+	// between are dropped, so a bulk import starts one run, not hundreds.
+	CooldownSeconds int `json:"cooldown_seconds"`
+
+	// Prompt is the message sent to the agent, see AgentTriggerEventPlaceholder
+	Prompt   string               `json:"prompt" gorm:"type:text"`
+	Delivery AgentTriggerDelivery `json:"delivery" gorm:"embedded;embeddedPrefix:delivery_"`
+
+	CreatedBy     string     `json:"created_by" gorm:"type:varchar(36)"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastRunStatus string     `json:"last_run_status,omitempty" gorm:"type:varchar(20)"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for AgentTrigger
+func (AgentTrigger) TableName() string { return "agent_triggers" }
+
+// AgentTriggerDelivery says where the answer of a run goes. Only the fields
+// of the selected type are used.
+type AgentTriggerDelivery struct {
+	Type AgentTriggerDeliveryType `json:"type" gorm:"type:varchar(20)"`
+
+	// IMChannelID is the IM channel that sends the answer. ChatID addresses
+	// a group chat; UserID a direct chat when ChatID is empty.
+	IMChannelID string `json:"im_channel_id,omitempty" gorm:"type:varchar(36)"`
+	IMChatID    string `json:"im_chat_id,omitempty"    gorm:"type:varchar(255)"`
+	IMUserID    string `json:"im_user_id,omitempty"    gorm:"type:varchar(255)"`
+
+	WebhookURL string `json:"webhook_url,omitempty" gorm:"type:varchar(1024)"`
+	// WebhookSecret signs the webhook body; never returned by the API
+	WebhookSecret    string `json:"-"                  gorm:"type:varchar(128)"`
+	HasWebhookSecret bool   `json:"has_webhook_secret" gorm:"-"`
+
+	// KnowledgeBaseID receives the answer as a published manual knowledge
+	// document titled after the trigger and the run time
+	KnowledgeBaseID string `json:"knowledge_base_id,omitempty" gorm:"type:varchar(36)"`
+}
+
+// Validate checks the trigger type, schedule or event, and delivery fields.
+// The cron expression itself is checked by the scheduler.
+func (t *AgentTrigger) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(t.Prompt) == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	if t.CooldownSeconds < 0 {
+		return fmt.Errorf("cooldown_seconds must not be negative")
+	}
+	switch t.TriggerType {
+	case AgentTriggerTypeSchedule:
+		if strings.TrimSpace(t.Schedule) == "" {
+			return fmt.Errorf("schedule is required for schedule triggers")
+		}
+	case AgentTriggerTypeEvent:
+		switch t.EventType {
+		case AgentTriggerEventKnowledgeIngested, AgentTriggerEventDataSourceSynced, AgentTriggerEventWikiIssueFlagged:
+		default:
+			return fmt.Errorf("unknown event_type %q", t.EventType)
+		}
+	default:
+		return fmt.Errorf("unknown trigger_type %q", t.TriggerType)
+	}
+	d := t.Delivery
+	switch d.Type {
+	case "", AgentTriggerDeliveryNone:
+	case AgentTriggerDeliveryIM:
+		if d.IMChannelID == "" || (d.IMChatID == "" && d.IMUserID == "") {
+			return fmt.Errorf("im delivery needs im_channel_id and im_chat_id or im_user_id")
+		}
+	case AgentTriggerDeliveryWebhook:
+		if strings.TrimSpace(d.WebhookURL) == "" {
+			return fmt.Errorf("webhook delivery needs webhook_url")
+		}
+	case AgentTriggerDeliveryKnowledge:
+		if d.KnowledgeBaseID == "" {
+			return fmt.Errorf("knowledge delivery needs knowledge_base_id")
+		}
+	default:
+		return fmt.Errorf("unknown delivery type %q", d.Type)
+	}
+	return nil
+}
+
+// MatchesKnowledgeBase reports whether an event of kbID passes the
+// trigger's knowledge base filter
+func (t *AgentTrigger) MatchesKnowledgeBase(kbID string) bool {
+	if len(t.KnowledgeBaseIDs) == 0 {
+		return true
+	}
+	for _, id := range t.KnowledgeBaseIDs {
+		if id == kbID {
+			return true
+		}
+	}
+	return false
+}
+
+// AgentTriggerEvent is the workspace event that started an event run
+type AgentTriggerEvent struct {
+	Type            AgentTriggerEventType `json:"type"`
+	TenantID        uint64                `json:"tenant_id"`
+	KnowledgeBaseID string                `json:"knowledge_base_id,omitempty"`
+	// SubjectID identifies what the event is about (knowledge, sync log or
+	// wiki issue ID) and deduplicates repeated deliveries of one event
+	SubjectID string `json:"subject_id"`
+	// Data holds the event's details under stable snake_case keys. Values
+	// such as titles and error messages come from workspace content.
+	Data JSONMap `json:"data,omitempty"`
+}
+
+// AgentTriggerRun records one execution of a trigger
+type AgentTriggerRun struct {
+	ID        string `json:"id"         gorm:"type:varchar(36);primaryKey"`
+	TenantID  uint64 `json:"tenant_id"  gorm:"index"`
+	TriggerID string `json:"trigger_id" gorm:"type:varchar(36);index"`
+	AgentID   string `json:"agent_id"   gorm:"type:varchar(36)"`
+	Source    string `json:"source"     gorm:"type:varchar(20)"`
+
+	Event     *AgentTriggerEvent `json:"event,omitempty" gorm:"type:json;serializer:json"`
+	Query     string             `json:"query" gorm:"type:text"`
+	SessionID string             `json:"session_id,omitempty" gorm:"type:varchar(36)"`
+	Answer    string             `json:"answer" gorm:"type:text"`
+
+	Status        string `json:"status" gorm:"type:varchar(20);index"`
+	ErrorMessage  string `json:"error_message,omitempty" gorm:"type:text"`
+	DeliveryType  string `json:"delivery_type" gorm:"type:varchar(20)"`
+	DeliveryError string `json:"delivery_error,omitempty" gorm:"type:text"`
+	// KnowledgeID is the document the answer was saved as, for knowledge delivery
+	KnowledgeID string `json:"knowledge_id,omitempty" gorm:"type:varchar(36)"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for AgentTriggerRun
+func (AgentTriggerRun) TableName() string { return "agent_trigger_runs" }
+
+// AgentTriggerRunPayload is the asynq payload of one trigger run
+type AgentTriggerRunPayload struct {
+	TracingContext
+	TenantID  uint64 `json:"tenant_id"`
+	TriggerID string `json:"trigger_id"`
+	RunID     string `json:"run_id"`
+}
@@ -0,0 +1,73 @@
+package types
+
+import "time"
+
+const (
+	// DefaultAnswerCacheThreshold is the cosine similarity a question needs to
+	// a cached one before the cached answer is replayed
+	DefaultAnswerCacheThreshold = 0.95
+	// DefaultAnswerCacheTTLHours is how long a cached answer stays valid while
+	// its knowledge bases do not change
+	DefaultAnswerCacheTTLHours = 24
+)
+
+// AnswerCacheEntry is a knowledge QA answer kept for replay to later questions
+// that are close in embedding space. Entries belong to one agent and one
+// retrieval scope (ScopeHash); an entry older than the last change to its
+// knowledge bases is stale and never served.
+type AnswerCacheEntry struct {
+	ID       string `json:"id"        gorm:"type:varchar(36);primaryKey"`
+	TenantID uint64 `json:"tenant_id" gorm:"not null"`
+	AgentID  string `json:"agent_id"  gorm:"type:varchar(36);not null"`
+	// ScopeHash identifies everything besides the question that shaped the
+	// answer: search targets, filters, retrieval mode, models, language and
+	// the agent configuration revision
+	ScopeHash        string      `json:"scope_hash"         gorm:"type:varchar(64);not null"`
+	KnowledgeBaseIDs StringArray `json:"knowledge_base_ids" gorm:"type:json"`
+
+	Query          string     `json:"query"      gorm:"type:text;not null"`
+	QueryEmbedding []byte     `json:"-"` // Little-endian float32 vector of Query
+	Answer         string     `json:"answer"     gorm:"type:text;not null"`
+	References     References `json:"references" gorm:"type:json;column:knowledge_references"` // Replayed with the answer
+
+	HitCount  int64      `json:"hit_count"   gorm:"not null;default:0"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// TableName returns the table name for AnswerCacheEntry
+func (AnswerCacheEntry) TableName() string { return "answer_cache_entries" }
+
+// AnswerCacheScope is the part of a knowledge QA request the answer cache
+// matches on, computed once per request and shared by lookup and store.
+type AnswerCacheScope struct {
+	TenantID         uint64
+	AgentID          string
+	ScopeHash        string
+	KnowledgeBaseIDs []string
+	Query            string
+	QueryEmbedding   []float32
+	Threshold        float64
+	TTL              time.Duration
+	// StartedAt is when the request began, before retrieval ran
+	StartedAt time.Time
+}
+
+// AnswerCacheThresholdOrDefault returns the configured similarity threshold,
+// falling back to DefaultAnswerCacheThreshold when unset or out of range.
+func (c *CustomAgentConfig) AnswerCacheThresholdOrDefault() float64 {
+	if c.AnswerCacheThreshold <= 0 || c.AnswerCacheThreshold > 1 {
+		return DefaultAnswerCacheThreshold
+	}
+	return c.AnswerCacheThreshold
+}
+
+// AnswerCacheTTL returns how long a cached answer of this agent stays valid.
+func (c *CustomAgentConfig) AnswerCacheTTL() time.Duration {
+	hours := c.AnswerCacheTTLHours
+	if hours <= 0 {
+		hours = DefaultAnswerCacheTTLHours
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -250,6 +250,7 @@ var validBuiltinModelStatuses = map[ModelStatus]struct{}{
 //     see ModelIDMaxLen) which would fail at INSERT time
 //   - empty or misspelled type (provider factories match exact strings)
 //   - explicit non-empty status outside the known set
+//   - rerank_parameters whose calibration cannot map scores onto [0, 1]
 //
 // Source is intentionally NOT validated against a fixed list because the
 // provider matrix in internal/models/* keeps growing and a too-strict
@@ -277,6 +278,9 @@ func validateBuiltinModelEntry(e *BuiltinModelEntry, index int) error {
 				index, e.ID, e.Status)
 		}
 	}
+	if err := e.Parameters.RerankParameters.Validate(); err != nil {
+		return errBuiltinModel("entry %d (%s) has invalid rerank_parameters: %v", index, e.ID, err)
+	}
 	return nil
 }
 
@@ -332,6 +332,11 @@ func TestLoadBuiltinModelsConfig_RejectsInvalidEntries(t *testing.T) {
 			name: "unknown status",
 			yaml: "builtin_models:\n  - id: builtin-x\n    type: KnowledgeQA\n    status: enabled\n",
 		},
+		{
+			name: "empty linear calibration range",
+			yaml: "builtin_models:\n  - id: builtin-x\n    type: Rerank\n    parameters:\n" +
+				"      rerank_parameters:\n        score_calibration: linear\n        score_min: 5\n        score_max: 5\n",
+		},
 	}
 
 	for _, c := range cases {
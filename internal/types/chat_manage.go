@@ -13,10 +13,13 @@ type PipelineRequest struct {
 	KnowledgeBaseIDs []string      `json:"knowledge_base_ids"`
 	KnowledgeIDs     []string      `json:"knowledge_ids,omitempty"`
 	SearchTargets    SearchTargets `json:"-"`
-	VectorThreshold  float64       `json:"vector_threshold"`
-	KeywordThreshold float64       `json:"keyword_threshold"`
-	EmbeddingTopK    int           `json:"embedding_top_k"`
-	VectorDatabase   string        `json:"vector_database"`
+	// MetadataFilter restricts retrieval to documents whose custom metadata
+	// matches. It is normalised at the request entry point.
+	MetadataFilter   *MetadataFilter `json:"metadata_filter,omitempty"`
+	VectorThreshold  float64         `json:"vector_threshold"`
+	KeywordThreshold float64         `json:"keyword_threshold"`
+	EmbeddingTopK    int             `json:"embedding_top_k"`
+	VectorDatabase   string          `json:"vector_database"`
 
 	// Rerank parameters
 	RerankModelID   string  `json:"rerank_model_id"`
@@ -42,6 +45,12 @@ type PipelineRequest struct {
 	// the query-understanding (rewrite + intent classification) stage only.
 	// Empty means fall back to ChatModelID.
 	QueryUnderstandModelID string `json:"query_understand_model_id,omitempty"`
+	// EnableHyDE generates a hypothetical answer passage before retrieval and
+	// searches the vector index with its embedding (QUERY_HYDE stage).
+	EnableHyDE bool `json:"enable_hyde"`
+	// EnableQueryDecomposition splits compound questions into sub-queries that
+	// are searched alongside the main query (QUERY_DECOMPOSE stage).
+	EnableQueryDecomposition bool `json:"enable_query_decomposition"`
 
 	// FAQ strategy
 	FAQPriorityEnabled       bool    `json:"-"`
@@ -53,6 +62,10 @@ type PipelineRequest struct {
 	// every RAG request that happens to retrieve CSV/Excel chunks.
 	DataAnalysisEnabled bool `json:"-"`
 
+	// RetrievalMode selects chunk retrieval (RetrievalModeLocal) or graph
+	// community summaries (RetrievalModeGlobal) as the answer context.
+	RetrievalMode string `json:"-"`
+
 	// Image / multimodal support
 	Images                  []string `json:"-"`
 	VLMModelID              string   `json:"-"`
@@ -114,6 +127,12 @@ type PipelineState struct {
 	RewriteQuery string      `json:"rewrite_query,omitempty"`
 	Intent       QueryIntent `json:"intent,omitempty"`
 	History      []*History  `json:"history,omitempty"`
+	// HypotheticalDocument is the drafted answer whose embedding replaces the
+	// query embedding in vector search, empty when HyDE is off or failed.
+	HypotheticalDocument string `json:"hypothetical_document,omitempty"`
+	// SubQueries are the parts of a compound question, each searched in
+	// parallel with RewriteQuery. Empty when the question was not decomposed.
+	SubQueries []string `json:"sub_queries,omitempty"`
 
 	SearchResult         []*SearchResult   `json:"-"`
 	RerankResult         []*SearchResult   `json:"-"`
@@ -213,6 +232,7 @@ func (c *ChatManage) Clone() *ChatManage {
 			KnowledgeBaseIDs:         knowledgeBaseIDs,
 			KnowledgeIDs:             knowledgeIDs,
 			SearchTargets:            searchTargets,
+			MetadataFilter:           c.MetadataFilter,
 			VectorThreshold:          c.VectorThreshold,
 			KeywordThreshold:         c.KeywordThreshold,
 			EmbeddingTopK:            c.EmbeddingTopK,
@@ -231,10 +251,13 @@ func (c *ChatManage) Clone() *ChatManage {
 			RewritePromptSystem:      c.RewritePromptSystem,
 			RewritePromptUser:        c.RewritePromptUser,
 			QueryUnderstandModelID:   c.QueryUnderstandModelID,
+			EnableHyDE:               c.EnableHyDE,
+			EnableQueryDecomposition: c.EnableQueryDecomposition,
 			FAQPriorityEnabled:       c.FAQPriorityEnabled,
 			FAQDirectAnswerThreshold: c.FAQDirectAnswerThreshold,
 			FAQScoreBoost:            c.FAQScoreBoost,
 			DataAnalysisEnabled:      c.DataAnalysisEnabled,
+			RetrievalMode:            c.RetrievalMode,
 			Images:                   append([]string(nil), c.Images...),
 			VLMModelID:               c.VLMModelID,
 			ChatModelSupportsVision:  c.ChatModelSupportsVision,
@@ -251,6 +274,8 @@ func (c *ChatManage) Clone() *ChatManage {
 		PipelineState: PipelineState{
 			RewriteQuery:         c.RewriteQuery,
 			Intent:               c.Intent,
+			HypotheticalDocument: c.HypotheticalDocument,
+			SubQueries:           append([]string(nil), c.SubQueries...),
 			ImageDescription:     c.ImageDescription,
 			QuotedContext:        c.QuotedContext,
 			SystemPromptOverride: c.SystemPromptOverride,
@@ -271,6 +296,8 @@ const (
 	LOAD_HISTORY           EventType = "load_history"
 	MEMORY_RECALL          EventType = "memory_recall"
 	QUERY_UNDERSTAND       EventType = "query_understand"
+	QUERY_HYDE             EventType = "query_hyde"
+	QUERY_DECOMPOSE        EventType = "query_decompose"
 	CHUNK_SEARCH           EventType = "chunk_search"
 	CHUNK_SEARCH_PARALLEL  EventType = "chunk_search_parallel"
 	ENTITY_SEARCH          EventType = "entity_search"
@@ -278,6 +305,7 @@ const (
 	WEB_FETCH              EventType = "web_fetch"
 	CHUNK_MERGE            EventType = "chunk_merge"
 	DATA_ANALYSIS          EventType = "data_analysis"
+	COMMUNITY_SEARCH       EventType = "community_search"
 	INTO_CHAT_MESSAGE      EventType = "into_chat_message"
 	CHAT_COMPLETION        EventType = "chat_completion"
 	CHAT_COMPLETION_STREAM EventType = "chat_completion_stream"
@@ -88,6 +88,10 @@ const (
 	// ChatParserEngineContextKey carries the resolved parser engine
 	// from the agent's ChatParserEngineRules for chat attachment processing.
 	ChatParserEngineContextKey ContextKey = "ChatParserEngine"
+	// KeepKnowledgeACLContextKey marks a document deletion whose access list
+	// moves to the document replacing it, as a data source sync does when a
+	// source item changes. See WithKnowledgeACLKept.
+	KeepKnowledgeACLContextKey ContextKey = "KeepKnowledgeACL"
 )
 
 // String returns the string representation of the context key
@@ -111,6 +111,10 @@ var contextCloneAcrossDetach = map[ContextKey]bool{
 	// request context inside the embed handler that authenticated it; nothing
 	// downstream of a detach reads it.
 	EmbedChannelContextKey: false,
+	// Keeps the access list of the one document a data source sync is about
+	// to replace. Carried further it would leave the access lists of
+	// unrelated deleted documents behind.
+	KeepKnowledgeACLContextKey: false,
 }
 
 // ContextKeysClonedAcrossDetach returns the keys logger.CloneContext carries
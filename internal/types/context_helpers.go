@@ -242,6 +242,22 @@ func IsBackgroundTask(ctx context.Context) bool {
 	return v
 }
 
+// WithKnowledgeACLKept marks ctx so DeleteKnowledge leaves the access list of
+// the deleted document in place for the document that replaces it. See
+// KeepKnowledgeACLContextKey.
+func WithKnowledgeACLKept(ctx context.Context) context.Context {
+	return context.WithValue(ctx, KeepKnowledgeACLContextKey, true)
+}
+
+// IsKnowledgeACLKept reports whether ctx was marked by WithKnowledgeACLKept.
+func IsKnowledgeACLKept(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(KeepKnowledgeACLContextKey).(bool)
+	return v
+}
+
 type taskRetryMetadata struct {
 	retried  int
 	maxRetry int
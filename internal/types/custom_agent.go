@@ -143,6 +143,11 @@ type CustomAgentConfig struct {
 	// MCPAuthWaitTimeout is how many seconds to wait for in-conversation OAuth
 	// authorization before skipping. <=0 uses the gate's configured timeout.
 	MCPAuthWaitTimeout int `yaml:"mcp_auth_wait_timeout,omitempty" json:"mcp_auth_wait_timeout,omitempty"`
+	// MCPPrompt selects a prompt template of an MCP service as the system
+	// prompt (only for agent type). It is rendered at the start of every turn
+	// and takes precedence over SystemPrompt, which remains the fallback when
+	// the service cannot be reached.
+	MCPPrompt *MCPPromptRef `yaml:"mcp_prompt,omitempty" json:"mcp_prompt,omitempty"`
 
 	// ===== Skills Settings (only for smart-reasoning mode) =====
 	// Skills selection mode: "all" = all preloaded skills, "selected" = specific skills, "none" = no skills
@@ -228,6 +233,15 @@ type CustomAgentConfig struct {
 	// FAQ score boost multiplier - FAQ results score multiplied by this factor
 	FAQScoreBoost float64 `yaml:"faq_score_boost" json:"faq_score_boost"`
 
+	// ===== Answer Cache Settings (only for quick-answer mode) =====
+	// Whether repeated knowledge base questions are answered from the semantic
+	// answer cache. Cached answers are shared by all users of the agent.
+	AnswerCacheEnabled bool `yaml:"answer_cache_enabled" json:"answer_cache_enabled"`
+	// Minimum cosine similarity to a cached question for a hit (default 0.95)
+	AnswerCacheThreshold float64 `yaml:"answer_cache_threshold" json:"answer_cache_threshold,omitempty"`
+	// Hours a cached answer stays valid while the knowledge bases are unchanged (default 24)
+	AnswerCacheTTLHours int `yaml:"answer_cache_ttl_hours" json:"answer_cache_ttl_hours,omitempty"`
+
 	// ===== Web Search Settings =====
 	// Whether web search is enabled
 	WebSearchEnabled bool `yaml:"web_search_enabled" json:"web_search_enabled"`
@@ -268,6 +282,11 @@ type CustomAgentConfig struct {
 	EnableQueryExpansion bool `yaml:"enable_query_expansion" json:"enable_query_expansion"`
 	// Whether to enable query rewrite for multi-turn conversations
 	EnableRewrite bool `yaml:"enable_rewrite" json:"enable_rewrite"`
+	// Whether to search with the embedding of a model-drafted hypothetical
+	// answer (HyDE) instead of the query embedding
+	EnableHyDE bool `yaml:"enable_hyde" json:"enable_hyde"`
+	// Whether to split compound questions into sub-queries searched in parallel
+	EnableQueryDecomposition bool `yaml:"enable_query_decomposition" json:"enable_query_decomposition"`
 	// Rewrite prompt system message
 	RewritePromptSystem string `yaml:"rewrite_prompt_system" json:"rewrite_prompt_system"`
 	// Rewrite prompt user message template
@@ -1,5 +1,25 @@
 package types
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultEvaluationDatasetID identifies the bundled parquet sample dataset.
+// It is served from disk and is visible to every tenant.
+const DefaultEvaluationDatasetID = "default"
+
+// Supported formats for uploaded evaluation datasets
+const (
+	EvaluationDatasetFormatJSONL   = "jsonl"
+	EvaluationDatasetFormatParquet = "parquet"
+)
+
 // QAPair represents a complete QA example with question, related passages and answer
 type QAPair struct {
 	QID      int      // Question ID
@@ -9,3 +29,96 @@ type QAPair struct {
 	AID      int      // Answer ID
 	Answer   string   // Answer text
 }
+
+// EvaluationDataset is a tenant-owned QA/qrels dataset uploaded for
+// evaluation. The QA pairs themselves live in evaluation_dataset_items.
+type EvaluationDataset struct {
+	ID           string    `json:"id"            gorm:"primaryKey;type:varchar(36)"`
+	TenantID     uint64    `json:"tenant_id"     gorm:"not null;index"`
+	Name         string    `json:"name"          gorm:"type:varchar(255);not null"`
+	Description  string    `json:"description"   gorm:"type:text"`
+	Format       string    `json:"format"        gorm:"type:varchar(16);not null"`
+	QACount      int       `json:"qa_count"      gorm:"column:qa_count;not null;default:0"`
+	PassageCount int       `json:"passage_count" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for EvaluationDataset
+func (EvaluationDataset) TableName() string { return "evaluation_datasets" }
+
+// BeforeCreate assigns a UUID when the caller did not provide one
+func (d *EvaluationDataset) BeforeCreate(_ *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// EvaluationDatasetItem is one QA pair of an uploaded dataset together with
+// the passages judged relevant to it.
+type EvaluationDatasetItem struct {
+	ID        uint64      `json:"id"         gorm:"primaryKey;autoIncrement"`
+	DatasetID string      `json:"dataset_id" gorm:"type:varchar(36);not null;index"`
+	TenantID  uint64      `json:"tenant_id"  gorm:"not null"`
+	QID       int         `json:"qid"        gorm:"column:qid;not null"`
+	Question  string      `json:"question"   gorm:"type:text;not null"`
+	AID       int         `json:"aid"        gorm:"column:aid;not null;default:0"`
+	Answer    string      `json:"answer"     gorm:"type:text"`
+	PIDs      IntList     `json:"pids"       gorm:"column:pids;type:json"`
+	Passages  StringArray `json:"passages"   gorm:"type:json"`
+}
+
+// TableName returns the table name for EvaluationDatasetItem
+func (EvaluationDatasetItem) TableName() string { return "evaluation_dataset_items" }
+
+// QAPair converts the stored item back into the in-memory evaluation shape
+func (i *EvaluationDatasetItem) QAPair() *QAPair {
+	return &QAPair{
+		QID:      i.QID,
+		Question: i.Question,
+		PIDs:     []int(i.PIDs),
+		Passages: []string(i.Passages),
+		AID:      i.AID,
+		Answer:   i.Answer,
+	}
+}
+
+// EvaluationDatasetUpload carries the raw files of a dataset upload.
+// JSONL uploads use JSONL; parquet uploads use the same file layout as the
+// bundled sample (queries, corpus, answers, qrels, qas).
+type EvaluationDatasetUpload struct {
+	Name        string
+	Description string
+	Format      string
+	JSONL       []byte
+	Parquet     map[string][]byte
+}
+
+// IntList represents a list of integers stored as JSON
+type IntList []int
+
+// Value implements the driver.Valuer interface
+func (l IntList) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface
+func (l *IntList) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("type assertion to []byte or string failed")
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, l)
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -97,6 +100,11 @@ type DataSource struct {
 	// Whether to sync deletions from source
 	SyncDeletions bool `json:"sync_deletions" gorm:"default:true"`
 
+	// Whether to mirror the source's read permissions onto synced documents
+	// as access lists. A pointer so an update that omits it leaves it as is;
+	// nil reads as false.
+	SyncPermissions *bool `json:"sync_permissions,omitempty" gorm:"default:false"`
+
 	// Last successful sync timestamp
 	LastSyncAt *time.Time `json:"last_sync_at"`
 
@@ -133,6 +141,11 @@ func (d *DataSource) TableName() string {
 	return "data_sources"
 }
 
+// PermissionSyncEnabled reports whether source permissions are synced
+func (d *DataSource) PermissionSyncEnabled() bool {
+	return d.SyncPermissions != nil && *d.SyncPermissions
+}
+
 // BeforeCreate hook to generate UUID
 func (d *DataSource) BeforeCreate(tx *gorm.DB) error {
 	if d.ID == "" {
@@ -235,6 +248,11 @@ type DataSourceConfig struct {
 	// ingesting an image into a KB without VLM is rejected, so image extraction is
 	// skipped when this is false.
 	MultimodalEnabled bool `json:"-"`
+
+	// SyncPermissions mirrors DataSource.SyncPermissions for the current sync
+	// run and is never persisted. Connectors that support permission sync
+	// only query the source's sharing settings when it is true.
+	SyncPermissions bool `json:"-"`
 }
 
 // HasCredentials reports whether the credentials map carries any value at
@@ -376,6 +394,73 @@ type FetchedItem struct {
 	// nil and empty are equivalent here; the omitempty tag is for API/debug
 	// exposure only and must not be relied on to distinguish "unset" from "empty".
 	SubtreeKeep []string `json:"subtree_keep,omitempty"`
+
+	// Permissions lists who may read the item in the source system. nil
+	// means the connector does not report permissions for it, which leaves
+	// any access list synced earlier untouched.
+	Permissions *ItemPermissions `json:"permissions,omitempty"`
+
+	// PermissionsOnly marks an item whose content is unchanged since the last
+	// sync but whose Permissions changed. It carries no content; ingestion
+	// only updates the access list of the already synced document.
+	PermissionsOnly bool `json:"permissions_only,omitempty"`
+}
+
+// Source principal types reported by connectors
+const (
+	SourcePrincipalUser  = "user"
+	SourcePrincipalGroup = "group"
+)
+
+// PermissionFingerprintsCursorKey is the ConnectorCursor key under which
+// connectors keep the permission fingerprint of every synced item, so an
+// incremental sync can tell that the sharing of an unchanged document moved.
+const PermissionFingerprintsCursorKey = "permission_fingerprints"
+
+// ItemPermissions describes who may read an item in its source system
+type ItemPermissions struct {
+	// Public is true when every member of the source organization can read
+	// the item (a public repository, a document shared tenant-wide). Such
+	// items are readable by everyone with access to the knowledge base.
+	Public bool `json:"public"`
+	// Principals are the users and groups granted read access
+	Principals []SourcePrincipal `json:"principals,omitempty"`
+}
+
+// SourcePrincipal is a user or group of the source system. Users are mapped
+// to WeKnora accounts by email, the key OIDC sign-in matches accounts on;
+// principals without a matching account still restrict the document, so it
+// never becomes readable by everyone just because its readers are unknown.
+type SourcePrincipal struct {
+	// Type is SourcePrincipalUser or SourcePrincipalGroup
+	Type string `json:"type"`
+	// ID is the source system's identifier of the principal
+	ID string `json:"id"`
+	// Email of a user, empty when the source does not expose it
+	Email string `json:"email,omitempty"`
+	// Name is the display name in the source system
+	Name string `json:"name,omitempty"`
+}
+
+// Fingerprint returns a stable digest of the permissions, independent of the
+// order principals were listed in
+func (p *ItemPermissions) Fingerprint() string {
+	if p == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(p.Principals))
+	for _, principal := range p.Principals {
+		keys = append(keys, principal.Type+"\x00"+principal.ID+"\x00"+strings.ToLower(principal.Email))
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	if p.Public {
+		h.Write([]byte("public\n"))
+	}
+	for _, key := range keys {
+		h.Write([]byte(key + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
 }
 
 // SubtreeChildID builds the external_id of a sub-item fanned out from a parent
@@ -411,6 +496,45 @@ type SyncCursor struct {
 	LastSchemaHash string `json:"last_schema_hash"`
 }
 
+// PermissionFingerprints returns the per-item permission fingerprints stored
+// under PermissionFingerprintsCursorKey, keyed by external ID. It never
+// returns nil.
+func (c *SyncCursor) PermissionFingerprints() map[string]string {
+	out := map[string]string{}
+	if c == nil {
+		return out
+	}
+	switch raw := c.ConnectorCursor[PermissionFingerprintsCursorKey].(type) {
+	case map[string]string:
+		for k, v := range raw {
+			out[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				out[k] = s
+			}
+		}
+	}
+	return out
+}
+
+// SetPermissionFingerprints stores the per-item permission fingerprints; an
+// empty map removes the key
+func (c *SyncCursor) SetPermissionFingerprints(fingerprints map[string]string) {
+	if c == nil {
+		return
+	}
+	if len(fingerprints) == 0 {
+		delete(c.ConnectorCursor, PermissionFingerprintsCursorKey)
+		return
+	}
+	if c.ConnectorCursor == nil {
+		c.ConnectorCursor = map[string]interface{}{}
+	}
+	c.ConnectorCursor[PermissionFingerprintsCursorKey] = fingerprints
+}
+
 // SyncResult summarizes the outcome of a sync operation
 type SyncResult struct {
 	// Total items processed
@@ -23,6 +23,7 @@ const (
 	MatchTypeWebSearch    // 网络搜索匹配类型
 	MatchTypeDirectLoad   // Deprecated: reserved to preserve serialized enum values
 	MatchTypeDataAnalysis // 数据分析匹配类型
+	MatchTypeCommunity    // 图谱社区摘要匹配类型
 )
 
 // IndexInfo contains information about indexed content
@@ -38,4 +39,22 @@ type IndexInfo struct {
 	TagID           string     // Tag ID for categorization (used for FAQ priority filtering)
 	IsEnabled       bool       // Whether the chunk is enabled for retrieval
 	IsRecommended   bool       // Whether the chunk is recommended
+	// Metadata holds the document's custom metadata as returned by
+	// IndexMetadata, for metadata filters. Nil when the document has none.
+	Metadata map[string]any
+}
+
+// IndexExportPage is one page of index entries read back from a vector store,
+// together with their stored embeddings, so they can be written into another
+// store without calling the embedding model again.
+type IndexExportPage struct {
+	// Entries are the exported index entries. ID holds the entry's identifier
+	// in the source store.
+	Entries []*IndexInfo
+	// Embeddings maps SourceID to the stored vector, in the shape BatchSave
+	// expects under its "embedding" parameter. Empty for keyword-only entries.
+	Embeddings map[string][]float32
+	// NextCursor resumes the export after this page; empty when there are no
+	// more entries.
+	NextCursor string
 }
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// EmbeddingMigrationStatus is the lifecycle state of a re-embedding job.
+type EmbeddingMigrationStatus string
+
+const (
+	// EmbeddingMigrationPending: the job is queued; queries use the old model.
+	EmbeddingMigrationPending EmbeddingMigrationStatus = "pending"
+	// EmbeddingMigrationRunning: the shadow index is being built; queries
+	// still use the old model and its vectors.
+	EmbeddingMigrationRunning EmbeddingMigrationStatus = "running"
+	// EmbeddingMigrationCompleted: the knowledge base has been switched to the
+	// new model and the old vectors have been dropped.
+	EmbeddingMigrationCompleted EmbeddingMigrationStatus = "completed"
+	// EmbeddingMigrationFailed: the job gave up; the knowledge base still uses
+	// the old model and the partial shadow index has been dropped.
+	EmbeddingMigrationFailed EmbeddingMigrationStatus = "failed"
+)
+
+// EmbeddingMigration records a background re-embedding of a knowledge base
+// onto a new embedding model. It is stored on the knowledge base row so the
+// state survives restarts and is returned with the knowledge base.
+type EmbeddingMigration struct {
+	// TaskID identifies the asynq task that runs this migration.
+	TaskID string `json:"task_id"`
+	// SourceModelID is the embedding model the knowledge base used when the
+	// migration started.
+	SourceModelID string `json:"source_model_id"`
+	// TargetModelID is the embedding model being migrated to.
+	TargetModelID string `json:"target_model_id"`
+	// IndexID is the knowledge_base_id label the shadow index is written
+	// under; it becomes KnowledgeBase.EmbeddingIndexID at cutover.
+	IndexID string `json:"-"`
+	// Status is the current lifecycle state.
+	Status EmbeddingMigrationStatus `json:"status"`
+	// Total is the number of documents to re-embed.
+	Total int `json:"total"`
+	// Processed is the number of documents re-embedded so far.
+	Processed int `json:"processed"`
+	// Error holds the last failure message, if any.
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the worker began building the shadow index.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// FinishedAt is when the migration completed or failed.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// UpdatedAt is the time of the last progress update.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Active reports whether the migration is still queued or running.
+func (m *EmbeddingMigration) Active() bool {
+	return m != nil && (m.Status == EmbeddingMigrationPending || m.Status == EmbeddingMigrationRunning)
+}
+
+// Progress returns the completion percentage (0-100).
+func (m *EmbeddingMigration) Progress() int {
+	if m == nil {
+		return 0
+	}
+	if m.Status == EmbeddingMigrationCompleted {
+		return 100
+	}
+	if m.Total <= 0 {
+		return 0
+	}
+	return min(m.Processed*100/m.Total, 99)
+}
+
+// MarshalJSON adds the computed progress percentage.
+func (m EmbeddingMigration) MarshalJSON() ([]byte, error) {
+	type alias EmbeddingMigration
+	return json.Marshal(struct {
+		alias
+		Progress int `json:"progress"`
+	}{alias: alias(m), Progress: m.Progress()})
+}
+
+// Value implements driver.Valuer. Unlike the API encoding, the column keeps
+// IndexID, which the worker needs to find the shadow index again.
+func (m EmbeddingMigration) Value() (driver.Value, error) {
+	type alias EmbeddingMigration
+	return json.Marshal(struct {
+		alias
+		IndexID string `json:"index_id"`
+	}{alias: alias(m), IndexID: m.IndexID})
+}
+
+// Scan implements sql.Scanner.
+func (m *EmbeddingMigration) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return nil
+	}
+	type alias EmbeddingMigration
+	var row struct {
+		alias
+		IndexID string `json:"index_id"`
+	}
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	*m = EmbeddingMigration(row.alias)
+	m.IndexID = row.IndexID
+	return nil
+}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"time"
@@ -37,20 +39,41 @@ const (
 	EvaluationStatueFailed                          // Task failed
 )
 
-// EvaluationTask contains information about an evaluation task
+// EvaluationTask contains information about an evaluation task.
+// Tasks are persisted in evaluation_tasks so runs survive restarts and can be
+// read from any replica; the aggregated metric is stored alongside the row.
 type EvaluationTask struct {
-	ID        string `json:"id"`         // Unique task ID
-	TenantID  uint64 `json:"tenant_id"`  // Tenant/Organization ID
-	DatasetID string `json:"dataset_id"` // Dataset ID for evaluation
+	ID        string `json:"id"         gorm:"primaryKey;type:varchar(128)"` // Unique task ID
+	TenantID  uint64 `json:"tenant_id"  gorm:"not null;index"`               // Tenant/Organization ID
+	DatasetID string `json:"dataset_id" gorm:"type:varchar(64);not null"`    // Dataset ID for evaluation
 
-	StartTime time.Time        `json:"start_time"`        // Task start time
-	Status    EvaluationStatue `json:"status"`            // Current task status
-	ErrMsg    string           `json:"err_msg,omitempty"` // Error message if failed
+	KnowledgeBaseID string `json:"knowledge_base_id,omitempty" gorm:"type:varchar(36)"` // Source knowledge base, empty for defaults
+	ChatModelID     string `json:"chat_model_id,omitempty"     gorm:"type:varchar(64)"` // Chat model under evaluation
+	RerankModelID   string `json:"rerank_model_id,omitempty"   gorm:"type:varchar(64)"` // Rerank model under evaluation
+	JudgeModelID    string `json:"judge_model_id,omitempty"    gorm:"type:varchar(64)"` // Chat model scoring judge metrics, empty to skip them
 
-	Total    int `json:"total,omitempty"`    // Total items to evaluate
-	Finished int `json:"finished,omitempty"` // Completed items count
+	// FusionConfig is the hybrid fusion the run's knowledge base searched
+	// with; nil means the tenant default. Runs that differ only here are the
+	// input of fusion weight tuning.
+	FusionConfig *FusionConfig `json:"fusion_config,omitempty" gorm:"column:fusion_config;type:json"`
+
+	StartTime time.Time        `json:"start_time"`                                  // Task start time
+	EndTime   *time.Time       `json:"end_time,omitempty"`                          // Task end time, nil while running
+	Status    EvaluationStatue `json:"status"            gorm:"not null;default:0"` // Current task status
+	ErrMsg    string           `json:"err_msg,omitempty" gorm:"type:text"`          // Error message if failed
+
+	Total    int `json:"total,omitempty"    gorm:"not null;default:0"` // Total items to evaluate
+	Finished int `json:"finished,omitempty" gorm:"not null;default:0"` // Completed items count
+
+	Metric *MetricResult `json:"-" gorm:"type:json"` // Aggregated metric, surfaced via EvaluationDetail
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// TableName returns the table name for EvaluationTask
+func (EvaluationTask) TableName() string { return "evaluation_tasks" }
+
 // EvaluationDetail contains detailed evaluation information
 type EvaluationDetail struct {
 	Task   *EvaluationTask `json:"task"`             // Evaluation task info
@@ -58,6 +81,30 @@ type EvaluationDetail struct {
 	Metric *MetricResult   `json:"metric,omitempty"` // Evaluation metrics
 }
 
+// EvaluationQuestionResult stores the outcome of one question of an
+// evaluation run: what was retrieved, what was generated and the metrics
+// computed for that single question.
+type EvaluationQuestionResult struct {
+	ID       uint64 `json:"id"        gorm:"primaryKey;autoIncrement"`
+	TaskID   string `json:"task_id"   gorm:"type:varchar(128);not null;index"`
+	TenantID uint64 `json:"tenant_id" gorm:"not null"`
+
+	QuestionIndex int     `json:"question_index" gorm:"not null"`                      // Position in the dataset iteration
+	QID           int     `json:"qid"            gorm:"column:qid;not null"`           // Question ID from the dataset
+	Question      string  `json:"question"       gorm:"type:text;not null"`            // Question text
+	Expected      string  `json:"expected"       gorm:"type:text"`                     // Ground-truth answer
+	Generated     string  `json:"generated"      gorm:"type:text"`                     // Answer produced by the pipeline
+	RetrievalGT   IntList `json:"retrieval_gt"  gorm:"column:retrieval_gt;type:json"`  // Relevant passage IDs
+	RetrievalIDs  IntList `json:"retrieval_ids" gorm:"column:retrieval_ids;type:json"` // Retrieved passage IDs, ranked
+
+	Metric *MetricResult `json:"metric" gorm:"type:json"` // Metrics for this question only
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for EvaluationQuestionResult
+func (EvaluationQuestionResult) TableName() string { return "evaluation_results" }
+
 // String returns JSON representation of EvaluationTask
 func (e *EvaluationTask) String() string {
 	b, _ := json.Marshal(e)
@@ -77,6 +124,35 @@ type MetricInput struct {
 type MetricResult struct {
 	RetrievalMetrics  RetrievalMetrics  `json:"retrieval_metrics"`  // Retrieval performance metrics
 	GenerationMetrics GenerationMetrics `json:"generation_metrics"` // Text generation quality metrics
+
+	// JudgeMetrics holds LLM-as-judge scores; nil when the run had no judge
+	// model or the judge failed for every question.
+	JudgeMetrics *JudgeMetrics `json:"judge_metrics,omitempty"`
+}
+
+// Value implements the driver.Valuer interface
+func (m MetricResult) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface
+func (m *MetricResult) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("type assertion to []byte or string failed")
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, m)
 }
 
 // RetrievalMetrics contains metrics for retrieval evaluation
@@ -101,6 +177,23 @@ type GenerationMetrics struct {
 	ROUGEL float64 `json:"rougel"` // ROUGE-L score
 }
 
+// JudgeMetrics contains RAG answer quality scores assigned by a judge model.
+// Every score lies in [0, 1].
+type JudgeMetrics struct {
+	Faithfulness     float64 `json:"faithfulness"`      // Share of answer claims supported by the retrieved context
+	AnswerRelevance  float64 `json:"answer_relevance"`  // How directly the answer addresses the question
+	ContextPrecision float64 `json:"context_precision"` // Rank-weighted share of retrieved chunks relevant to the question
+	ContextRecall    float64 `json:"context_recall"`    // Share of ground-truth statements attributable to the retrieved context
+}
+
+// JudgeInput is everything a judge sees for one evaluated question
+type JudgeInput struct {
+	Question    string   // Question text
+	Answer      string   // Generated answer
+	GroundTruth string   // Reference answer, may be empty
+	Contexts    []string // Retrieved chunk contents in rank order
+}
+
 // EvalState represents different stages of evaluation process
 type EvalState int
 
@@ -114,3 +207,130 @@ const (
 	StateAfterComplete                      // After completion
 	StateEnd                                // Evaluation ended
 )
+
+// Metric names accepted by evaluation comparisons and regression gates.
+// They match the JSON keys of RetrievalMetrics and GenerationMetrics.
+const (
+	MetricPrecision = "precision"
+	MetricRecall    = "recall"
+	MetricNDCG3     = "ndcg3"
+	MetricNDCG10    = "ndcg10"
+	MetricMRR       = "mrr"
+	MetricMAP       = "map"
+	MetricBLEU1     = "bleu1"
+	MetricBLEU2     = "bleu2"
+	MetricBLEU4     = "bleu4"
+	MetricROUGE1    = "rouge1"
+	MetricROUGE2    = "rouge2"
+	MetricROUGEL    = "rougel"
+
+	MetricFaithfulness     = "faithfulness"
+	MetricAnswerRelevance  = "answer_relevance"
+	MetricContextPrecision = "context_precision"
+	MetricContextRecall    = "context_recall"
+)
+
+// EvaluationMetricNames lists every metric name in display order
+var EvaluationMetricNames = []string{
+	MetricPrecision, MetricRecall, MetricNDCG3, MetricNDCG10, MetricMRR, MetricMAP,
+	MetricBLEU1, MetricBLEU2, MetricBLEU4, MetricROUGE1, MetricROUGE2, MetricROUGEL,
+	MetricFaithfulness, MetricAnswerRelevance, MetricContextPrecision, MetricContextRecall,
+}
+
+// MetricValue returns the score of the named metric. Judge metrics report
+// false when the result carries no judge scores.
+func (m *MetricResult) MetricValue(name string) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	r, g := m.RetrievalMetrics, m.GenerationMetrics
+	if j := m.JudgeMetrics; j != nil {
+		switch name {
+		case MetricFaithfulness:
+			return j.Faithfulness, true
+		case MetricAnswerRelevance:
+			return j.AnswerRelevance, true
+		case MetricContextPrecision:
+			return j.ContextPrecision, true
+		case MetricContextRecall:
+			return j.ContextRecall, true
+		}
+	}
+	switch name {
+	case MetricPrecision:
+		return r.Precision, true
+	case MetricRecall:
+		return r.Recall, true
+	case MetricNDCG3:
+		return r.NDCG3, true
+	case MetricNDCG10:
+		return r.NDCG10, true
+	case MetricMRR:
+		return r.MRR, true
+	case MetricMAP:
+		return r.MAP, true
+	case MetricBLEU1:
+		return g.BLEU1, true
+	case MetricBLEU2:
+		return g.BLEU2, true
+	case MetricBLEU4:
+		return g.BLEU4, true
+	case MetricROUGE1:
+		return g.ROUGE1, true
+	case MetricROUGE2:
+		return g.ROUGE2, true
+	case MetricROUGEL:
+		return g.ROUGEL, true
+	}
+	return 0, false
+}
+
+// FusionTuneRequest asks to pick the best score fusion weights among
+// finished evaluation runs and store them on a knowledge base
+type FusionTuneRequest struct {
+	KnowledgeBaseID string   `json:"knowledge_base_id"` // Knowledge base receiving the learned weights
+	TaskIDs         []string `json:"task_ids"`          // Finished runs over one dataset, each with a score-based fusion_config
+	Metric          string   `json:"metric"`            // Metric the runs are ranked by, default ndcg10
+}
+
+// EvaluationGate is a regression gate: the target run fails the gate when any
+// of Metrics drops by more than MaxDrop (absolute) versus the baseline.
+type EvaluationGate struct {
+	Metrics []string `json:"metrics"`  // Metrics the gate checks; empty disables the gate
+	MaxDrop float64  `json:"max_drop"` // Largest tolerated drop, e.g. 0.02
+}
+
+// MetricDelta compares one metric between a baseline and a target run
+type MetricDelta struct {
+	Metric    string  `json:"metric"`
+	Base      float64 `json:"base"`
+	Target    float64 `json:"target"`
+	Delta     float64 `json:"delta"`     // Target - Base
+	Regressed bool    `json:"regressed"` // Gated metric dropped by more than MaxDrop
+}
+
+// QuestionComparison compares one question answered by both runs
+type QuestionComparison struct {
+	QID             int           `json:"qid"`
+	Question        string        `json:"question"`
+	BaseGenerated   string        `json:"base_generated"`
+	TargetGenerated string        `json:"target_generated"`
+	Deltas          []MetricDelta `json:"deltas"`
+	Regressed       bool          `json:"regressed"` // Any gated metric regressed on this question
+}
+
+// EvaluationComparison is the diff of two evaluation runs over the same dataset
+type EvaluationComparison struct {
+	BaseTaskID   string               `json:"base_task_id"`
+	TargetTaskID string               `json:"target_task_id"`
+	DatasetID    string               `json:"dataset_id"`
+	Gate         *EvaluationGate      `json:"gate,omitempty"`
+	Aggregate    []MetricDelta        `json:"aggregate"`
+	Questions    []QuestionComparison `json:"questions"`
+	// Questions present in only one of the runs, e.g. when a run failed midway
+	UnmatchedQuestions int `json:"unmatched_questions"`
+	// RegressedQuestions counts questions where a gated metric regressed
+	RegressedQuestions int `json:"regressed_questions"`
+	// Passed is false when a gated aggregate metric regressed
+	Passed bool `json:"passed"`
+}
@@ -26,6 +26,10 @@ type GraphRelation struct {
 	Node1 string `json:"node1,omitempty"`
 	Node2 string `json:"node2,omitempty"`
 	Type  string `json:"type,omitempty"`
+	// Chunks are the IDs of the chunks the relation was extracted from, i.e.
+	// the evidence backing this edge. Empty for relations used as prompt
+	// examples or extraction config.
+	Chunks []string `json:"chunks,omitempty"`
 }
 
 type GraphData struct {
@@ -51,3 +55,52 @@ func (n NameSpace) Labels() []string {
 	}
 	return res
 }
+
+// Graph traversal limits. MaxHops is capped so an agent cannot ask for an
+// unbounded expansion of a densely connected graph.
+const (
+	DefaultGraphTraversalHops  = 2
+	MaxGraphTraversalHops      = 4
+	DefaultGraphTraversalNodes = 200
+)
+
+// GraphTraversalOptions controls multi-hop graph queries.
+type GraphTraversalOptions struct {
+	// MaxHops is the maximum number of edges walked from a seed entity.
+	MaxHops int `json:"max_hops,omitempty"`
+	// RelationTypes restricts traversal to these relation types; empty means
+	// every type.
+	RelationTypes []string `json:"relation_types,omitempty"`
+	// MaxNodes bounds the number of distinct entities visited.
+	MaxNodes int `json:"max_nodes,omitempty"`
+}
+
+// Normalize fills defaults and clamps MaxHops into [1, MaxGraphTraversalHops].
+func (o GraphTraversalOptions) Normalize() GraphTraversalOptions {
+	if o.MaxHops <= 0 {
+		o.MaxHops = DefaultGraphTraversalHops
+	}
+	if o.MaxHops > MaxGraphTraversalHops {
+		o.MaxHops = MaxGraphTraversalHops
+	}
+	if o.MaxNodes <= 0 {
+		o.MaxNodes = DefaultGraphTraversalNodes
+	}
+	return o
+}
+
+// GraphPath is a chain of entities connecting a source to a target. Nodes[i]
+// and Nodes[i+1] are joined by Relations[i]; a relation keeps its stored
+// direction, so Node1/Node2 may be reversed relative to the walk.
+type GraphPath struct {
+	Nodes     []*GraphNode     `json:"nodes"`
+	Relations []*GraphRelation `json:"relations"`
+}
+
+// Hops returns the number of edges on the path.
+func (p *GraphPath) Hops() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.Relations)
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FusionStrategy names how HybridSearch merges vector and keyword results
+type FusionStrategy string
+
+const (
+	// FusionStrategyRRF is Reciprocal Rank Fusion: only the rank of a chunk in
+	// each list counts, scores are ignored. The default.
+	FusionStrategyRRF FusionStrategy = "rrf"
+	// FusionStrategyWeighted min-max normalizes each engine's scores to [0, 1]
+	// and sums them with the vector / keyword weights.
+	FusionStrategyWeighted FusionStrategy = "weighted"
+	// FusionStrategyDBSF is distribution-based score fusion: each engine's
+	// scores are normalized over mean ± 3 standard deviations before the
+	// weighted sum, so a single outlier does not flatten the rest of the list.
+	FusionStrategyDBSF FusionStrategy = "dbsf"
+	// FusionStrategyLearned is weighted score fusion with weights tuned from
+	// evaluation runs (see LearnedFusionWeights).
+	FusionStrategyLearned FusionStrategy = "learned"
+)
+
+// IsScoreBased reports whether the strategy fuses normalized scores rather
+// than ranks
+func (s FusionStrategy) IsScoreBased() bool {
+	return s == FusionStrategyWeighted || s == FusionStrategyDBSF || s == FusionStrategyLearned
+}
+
+// FusionConfig controls how vector and keyword results of a hybrid search are
+// merged into one ranking. The tenant default lives inline in RetrievalConfig;
+// a knowledge base can carry its own copy that replaces it as a whole.
+type FusionConfig struct {
+	// FusionStrategy selects the fusion mode; empty means rrf.
+	FusionStrategy FusionStrategy `json:"fusion_strategy,omitempty"`
+
+	// RRFK is the smoothing constant of Reciprocal Rank Fusion. Larger values
+	// flatten the curve, reducing the bias towards top-1 results.
+	// Default: 60. Sensible range: 30..100 depending on corpus size.
+	RRFK int `json:"rrf_k,omitempty"`
+	// RRFVectorWeight is the weight applied to the vector retriever inside RRF.
+	// RRFVectorWeight + RRFKeywordWeight should usually sum to 1.0 but the math
+	// works for any positive weights. Default: 0.7.
+	RRFVectorWeight float64 `json:"rrf_vector_weight,omitempty"`
+	// RRFKeywordWeight is the keyword counterpart. Default: 0.3.
+	RRFKeywordWeight float64 `json:"rrf_keyword_weight,omitempty"`
+
+	// ScoreVectorWeight and ScoreKeywordWeight weight the normalized scores of
+	// the weighted and dbsf strategies. Default: 0.5 / 0.5.
+	ScoreVectorWeight  float64 `json:"score_vector_weight,omitempty"`
+	ScoreKeywordWeight float64 `json:"score_keyword_weight,omitempty"`
+
+	// LearnedWeights holds the weights the learned strategy uses. It is
+	// written by the evaluation fusion tuner, not edited by hand.
+	LearnedWeights *LearnedFusionWeights `json:"learned_weights,omitempty"`
+}
+
+// LearnedFusionWeights records the weights picked from a set of evaluation
+// runs and where they came from
+type LearnedFusionWeights struct {
+	VectorWeight  float64 `json:"vector_weight"`
+	KeywordWeight float64 `json:"keyword_weight"`
+	// Normalization is the per-engine normalization the winning run used:
+	// weighted (min-max) or dbsf.
+	Normalization FusionStrategy `json:"normalization"`
+
+	SourceTaskID string    `json:"source_task_id"` // Evaluation run the weights came from
+	Metric       string    `json:"metric"`         // Metric the runs were ranked by
+	MetricValue  float64   `json:"metric_value"`   // Value of Metric on SourceTaskID
+	RunCount     int       `json:"run_count"`      // Number of runs compared
+	TunedAt      time.Time `json:"tuned_at"`
+}
+
+// GetEffectiveFusionStrategy returns FusionStrategy with rrf as fallback.
+// A learned strategy without learned weights degrades to weighted.
+func (c *FusionConfig) GetEffectiveFusionStrategy() FusionStrategy {
+	if c == nil || c.FusionStrategy == "" {
+		return FusionStrategyRRF
+	}
+	if c.FusionStrategy == FusionStrategyLearned && c.LearnedWeights == nil {
+		return FusionStrategyWeighted
+	}
+	return c.FusionStrategy
+}
+
+// GetEffectiveRRFK returns the RRF smoothing constant with a fallback default.
+func (c *FusionConfig) GetEffectiveRRFK() int {
+	if c == nil || c.RRFK <= 0 {
+		return 60
+	}
+	return c.RRFK
+}
+
+// GetEffectiveRRFWeights returns vector / keyword weights with sensible defaults.
+// When neither weight is set explicitly, returns 0.7 / 0.3.
+func (c *FusionConfig) GetEffectiveRRFWeights() (vector, keyword float64) {
+	if c == nil || (c.RRFVectorWeight == 0 && c.RRFKeywordWeight == 0) {
+		return 0.7, 0.3
+	}
+	v := c.RRFVectorWeight
+	k := c.RRFKeywordWeight
+	if v <= 0 {
+		v = 0.7
+	}
+	if k <= 0 {
+		k = 0.3
+	}
+	return v, k
+}
+
+// GetEffectiveScoreWeights returns the weights of the score-based strategies.
+// The learned strategy uses its learned weights; the others fall back to
+// 0.5 / 0.5 when neither weight is set. A single zero weight is honoured, so
+// a list can be switched off entirely.
+func (c *FusionConfig) GetEffectiveScoreWeights() (vector, keyword float64) {
+	if c.GetEffectiveFusionStrategy() == FusionStrategyLearned {
+		return c.LearnedWeights.VectorWeight, c.LearnedWeights.KeywordWeight
+	}
+	if c == nil || (c.ScoreVectorWeight == 0 && c.ScoreKeywordWeight == 0) {
+		return 0.5, 0.5
+	}
+	return c.ScoreVectorWeight, c.ScoreKeywordWeight
+}
+
+// GetEffectiveNormalization returns the per-engine normalization of the
+// score-based strategies: weighted (min-max) or dbsf.
+func (c *FusionConfig) GetEffectiveNormalization() FusionStrategy {
+	switch c.GetEffectiveFusionStrategy() {
+	case FusionStrategyDBSF:
+		return FusionStrategyDBSF
+	case FusionStrategyLearned:
+		if c.LearnedWeights.Normalization == FusionStrategyDBSF {
+			return FusionStrategyDBSF
+		}
+	}
+	return FusionStrategyWeighted
+}
+
+// Validate checks the strategy name and that no weight is negative
+func (c *FusionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.FusionStrategy {
+	case "", FusionStrategyRRF, FusionStrategyWeighted, FusionStrategyDBSF, FusionStrategyLearned:
+	default:
+		return fmt.Errorf("unknown fusion_strategy %q", c.FusionStrategy)
+	}
+	if c.RRFK < 0 {
+		return fmt.Errorf("rrf_k must not be negative")
+	}
+	if c.RRFVectorWeight < 0 || c.RRFKeywordWeight < 0 ||
+		c.ScoreVectorWeight < 0 || c.ScoreKeywordWeight < 0 {
+		return fmt.Errorf("fusion weights must not be negative")
+	}
+	if lw := c.LearnedWeights; lw != nil {
+		if lw.VectorWeight < 0 || lw.KeywordWeight < 0 || lw.VectorWeight+lw.KeywordWeight == 0 {
+			return fmt.Errorf("learned fusion weights must be non-negative and not both zero")
+		}
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization
+func (c FusionConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization
+func (c *FusionConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, c)
+}
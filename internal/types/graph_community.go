@@ -0,0 +1,59 @@
+package types
+
+import "time"
+
+// Retrieval modes accepted by knowledge QA requests.
+const (
+	// RetrievalModeLocal answers from the chunks (and graph neighbours) that
+	// match the query. It is the default.
+	RetrievalModeLocal = "local"
+	// RetrievalModeGlobal answers from the knowledge base's graph community
+	// summaries, for corpus-wide questions such as "what are the main
+	// themes across these documents" that no single chunk can answer.
+	RetrievalModeGlobal = "global"
+)
+
+// NormalizeRetrievalMode maps a request value onto a known retrieval mode,
+// falling back to RetrievalModeLocal for empty or unknown values.
+func NormalizeRetrievalMode(mode string) string {
+	if mode == RetrievalModeGlobal {
+		return RetrievalModeGlobal
+	}
+	return RetrievalModeLocal
+}
+
+// GraphCommunity is a cluster of densely connected entities in a knowledge
+// base's graph together with an LLM-written summary of what the cluster is
+// about. Communities are rebuilt wholesale per knowledge base whenever its
+// graph changes, so rows are never edited in place.
+type GraphCommunity struct {
+	// Unique identifier (UUID)
+	ID string `json:"id" gorm:"type:varchar(36);primaryKey"`
+	// Workspace ID for multi-workspace isolation
+	TenantID uint64 `json:"tenant_id" gorm:"index"`
+	// Knowledge base whose graph the community was detected in
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36);index"`
+	// Short human-readable title generated with the summary
+	Title string `json:"title" gorm:"type:varchar(512)"`
+	// Summary of the entities and relations in the community
+	Summary string `json:"summary" gorm:"type:text"`
+	// Member entity names, most connected first
+	Entities StringArray `json:"entities" gorm:"type:json"`
+	// Number of distinct relations between members
+	RelationCount int `json:"relation_count"`
+	// Chunks the member relations were extracted from
+	ChunkIDs StringArray `json:"chunk_ids" gorm:"column:chunk_ids;type:json"`
+	// Every document the member relations were extracted from; unlike
+	// ChunkIDs it is never truncated, so access checks can rely on it
+	KnowledgeIDs StringArray `json:"knowledge_ids" gorm:"column:knowledge_ids;type:json"`
+	// Rank orders communities by importance: the share of the graph's
+	// relation weight that falls inside this community
+	Rank float64 `json:"rank"`
+	// Time the community was built
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for GraphCommunity
+func (GraphCommunity) TableName() string {
+	return "graph_communities"
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"os"
+	"strings"
+)
+
+// Graph store backends selectable through GRAPH_DRIVER.
+const (
+	// GraphDriverNeo4j stores the knowledge graph in Neo4j (requires APOC).
+	GraphDriverNeo4j = "neo4j"
+	// GraphDriverDatabase stores the knowledge graph in the application's
+	// own relational database (Postgres or SQLite), so no extra service is
+	// needed.
+	GraphDriverDatabase = "database"
+)
+
+// GraphDriver returns the configured graph store backend, or "" when the
+// knowledge graph is disabled.
+//
+// GRAPH_DRIVER takes precedence ("postgres" and "sqlite" are accepted as
+// aliases of "database"); without it, the legacy NEO4J_ENABLE=true switch
+// still selects Neo4j so existing deployments keep working unchanged.
+func GraphDriver() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("GRAPH_DRIVER"))) {
+	case GraphDriverNeo4j:
+		return GraphDriverNeo4j
+	case GraphDriverDatabase, "postgres", "sqlite", "db":
+		return GraphDriverDatabase
+	case "":
+		if strings.ToLower(os.Getenv("NEO4J_ENABLE")) == "true" {
+			return GraphDriverNeo4j
+		}
+	}
+	return ""
+}
+
+// GraphStoreEnabled reports whether any graph store backend is configured.
+func GraphStoreEnabled() bool {
+	return GraphDriver() != ""
+}
+
+// GraphMigrateFromNeo4j reports whether the Neo4j graph should be copied
+// into the relational store on startup (GRAPH_MIGRATE_FROM_NEO4J=true). It
+// only applies with GRAPH_DRIVER=database; NEO4J_URI and credentials must
+// still point at the old instance.
+func GraphMigrateFromNeo4j() bool {
+	return GraphDriver() == GraphDriverDatabase &&
+		strings.ToLower(os.Getenv("GRAPH_MIGRATE_FROM_NEO4J")) == "true"
+}
@@ -0,0 +1,69 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// AgentTriggerService manages scheduled and event-driven runs of custom agents
+type AgentTriggerService interface {
+	// CreateTrigger adds a trigger to an agent of the current tenant
+	CreateTrigger(ctx context.Context, agentID string, trigger *types.AgentTrigger) (*types.AgentTrigger, error)
+	// ListTriggers returns the triggers of an agent
+	ListTriggers(ctx context.Context, agentID string) ([]*types.AgentTrigger, error)
+	// GetTrigger returns one trigger of an agent
+	GetTrigger(ctx context.Context, agentID string, id string) (*types.AgentTrigger, error)
+	// UpdateTrigger replaces the editable fields of a trigger. A nil
+	// webhookSecret keeps the stored secret.
+	UpdateTrigger(ctx context.Context, agentID string, id string,
+		trigger *types.AgentTrigger, webhookSecret *string) (*types.AgentTrigger, error)
+	// DeleteTrigger removes a trigger and its schedule
+	DeleteTrigger(ctx context.Context, agentID string, id string) error
+	// RunTrigger enqueues a run of a trigger now, whatever its type
+	RunTrigger(ctx context.Context, agentID string, id string) (*types.AgentTriggerRun, error)
+	// ListRuns returns a page of a trigger's runs, newest first
+	ListRuns(ctx context.Context, agentID string, id string, page *types.Pagination) (*types.PageResult, error)
+	// ProcessRun is the asynq handler that executes a run
+	ProcessRun(ctx context.Context, task *asynq.Task) error
+}
+
+// AgentTriggerRepository persists agent triggers and their runs
+type AgentTriggerRepository interface {
+	// Create inserts a trigger
+	Create(ctx context.Context, trigger *types.AgentTrigger) error
+	// Update saves every column of a trigger
+	Update(ctx context.Context, trigger *types.AgentTrigger) error
+	// Delete soft-deletes a trigger of an agent
+	Delete(ctx context.Context, tenantID uint64, agentID string, id string) error
+	// Get returns a trigger of an agent
+	Get(ctx context.Context, tenantID uint64, agentID string, id string) (*types.AgentTrigger, error)
+	// GetByID returns a trigger of a tenant regardless of its agent
+	GetByID(ctx context.Context, tenantID uint64, id string) (*types.AgentTrigger, error)
+	// ListByAgent returns the triggers of an agent, oldest first
+	ListByAgent(ctx context.Context, tenantID uint64, agentID string) ([]*types.AgentTrigger, error)
+	// ListEnabledSchedules returns every enabled schedule trigger of all tenants
+	ListEnabledSchedules(ctx context.Context) ([]*types.AgentTrigger, error)
+	// ListEnabledByEvent returns a tenant's enabled triggers listening to an event
+	ListEnabledByEvent(ctx context.Context, tenantID uint64,
+		eventType types.AgentTriggerEventType) ([]*types.AgentTrigger, error)
+	// RecordRun stores the time and status of a trigger's latest run
+	RecordRun(ctx context.Context, id string, at time.Time, status string) error
+
+	// CreateRun inserts a run
+	CreateRun(ctx context.Context, run *types.AgentTriggerRun) error
+	// UpdateRun saves every column of a run
+	UpdateRun(ctx context.Context, run *types.AgentTriggerRun) error
+	// DeleteRun removes a run that was never enqueued
+	DeleteRun(ctx context.Context, id string) error
+	// GetRun returns a run of a tenant
+	GetRun(ctx context.Context, tenantID uint64, id string) (*types.AgentTriggerRun, error)
+	// ListRuns returns a page of a trigger's runs, newest first, and the total count
+	ListRuns(ctx context.Context, tenantID uint64, triggerID string,
+		page *types.Pagination) ([]*types.AgentTriggerRun, int64, error)
+	// HasActiveRun reports whether a run of the trigger created after since is
+	// still pending or running
+	HasActiveRun(ctx context.Context, triggerID string, since time.Time) (bool, error)
+}
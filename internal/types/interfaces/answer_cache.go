@@ -0,0 +1,49 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// AnswerCacheService serves and records semantically cached knowledge QA answers
+type AnswerCacheService interface {
+	// Lookup returns the freshest cached answer whose question is within the
+	// scope's similarity threshold, or nil on a miss
+	Lookup(ctx context.Context, scope *types.AnswerCacheScope) (*types.AnswerCacheEntry, error)
+	// Store records a generated answer for the scope
+	Store(ctx context.Context, scope *types.AnswerCacheScope, answer string, references types.References) error
+	// ListEntries lists the cached answers of an agent of the current tenant, newest first
+	ListEntries(ctx context.Context, agentID string, page *types.Pagination) (*types.PageResult, error)
+	// DeleteEntry removes one cached answer of an agent
+	DeleteEntry(ctx context.Context, agentID string, entryID string) error
+	// ClearAgent removes every cached answer of an agent and returns how many were removed
+	ClearAgent(ctx context.Context, agentID string) (int64, error)
+}
+
+// AnswerCacheRepository persists answer cache entries
+type AnswerCacheRepository interface {
+	// Create inserts an entry
+	Create(ctx context.Context, entry *types.AnswerCacheEntry) error
+	// ListCandidates returns the unexpired entries of a scope created after
+	// since, newest first, at most limit
+	ListCandidates(ctx context.Context, tenantID uint64, agentID string, scopeHash string,
+		since time.Time, limit int) ([]*types.AnswerCacheEntry, error)
+	// RecordHit increments the hit counter of an entry
+	RecordHit(ctx context.Context, id string) error
+	// List returns a page of an agent's entries, newest first, and the total count
+	List(ctx context.Context, tenantID uint64, agentID string,
+		page *types.Pagination) ([]*types.AnswerCacheEntry, int64, error)
+	// Delete removes one entry of an agent
+	Delete(ctx context.Context, tenantID uint64, agentID string, id string) error
+	// DeleteByAgent removes every entry of an agent
+	DeleteByAgent(ctx context.Context, tenantID uint64, agentID string) (int64, error)
+	// DeleteStale removes the entries of a scope that expired or were created
+	// at or before changedAt
+	DeleteStale(ctx context.Context, tenantID uint64, agentID string, scopeHash string,
+		changedAt time.Time) (int64, error)
+	// LastKnowledgeChange returns the latest time any of the knowledge bases,
+	// their knowledge or their chunks was updated or deleted
+	LastKnowledgeChange(ctx context.Context, knowledgeBaseIDs []string) (time.Time, error)
+}
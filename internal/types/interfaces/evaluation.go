@@ -2,18 +2,62 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/Tencent/WeKnora/internal/types"
 )
 
 // EvaluationService defines operations for evaluation tasks
 type EvaluationService interface {
-	// Evaluation starts a new evaluation task
+	// Evaluation starts a new evaluation task; fusionConfig, when set, is the
+	// hybrid fusion the run's knowledge base searches with
 	Evaluation(ctx context.Context, datasetID string, knowledgeBaseID string,
-		chatModelID string, rerankModelID string,
+		chatModelID string, rerankModelID string, judgeModelID string, fusionConfig *types.FusionConfig,
 	) (*types.EvaluationDetail, error)
 	// EvaluationResult retrieves evaluation result by task ID
 	EvaluationResult(ctx context.Context, taskID string) (*types.EvaluationDetail, error)
+	// ListEvaluations lists the evaluation tasks of the current tenant, newest first
+	ListEvaluations(ctx context.Context, limit int) ([]*types.EvaluationTask, error)
+	// ListQuestionResults returns the per-question results of an evaluation task
+	ListQuestionResults(ctx context.Context, taskID string) ([]*types.EvaluationQuestionResult, error)
+	// CompareEvaluations diffs a target run against a baseline run and applies
+	// the optional regression gate
+	CompareEvaluations(ctx context.Context, baseTaskID string, targetTaskID string,
+		gate *types.EvaluationGate) (*types.EvaluationComparison, error)
+	// TuneFusionWeights picks the best score fusion weights among finished
+	// runs and stores them on a knowledge base as its learned fusion
+	TuneFusionWeights(ctx context.Context, req *types.FusionTuneRequest) (*types.FusionConfig, error)
+}
+
+// EvaluationRepository persists evaluation tasks and their per-question results
+type EvaluationRepository interface {
+	// CreateTask inserts a new evaluation task
+	CreateTask(ctx context.Context, task *types.EvaluationTask) error
+	// GetTask returns a task of the given tenant
+	GetTask(ctx context.Context, tenantID uint64, taskID string) (*types.EvaluationTask, error)
+	// ListTasks returns the tasks of a tenant ordered by start time, newest first
+	ListTasks(ctx context.Context, tenantID uint64, limit int) ([]*types.EvaluationTask, error)
+	// UpdateTaskProgress updates the mutable progress fields of a task
+	UpdateTaskProgress(ctx context.Context, task *types.EvaluationTask) error
+	// FailStaleTasks marks pending or running tasks without progress since
+	// updatedBefore as failed; a task whose worker died with its process would
+	// otherwise stay running forever
+	FailStaleTasks(ctx context.Context, updatedBefore time.Time, errMsg string) (int64, error)
+	// CreateQuestionResult inserts the result of one evaluated question
+	CreateQuestionResult(ctx context.Context, result *types.EvaluationQuestionResult) error
+	// ListQuestionResults returns the results of a task ordered by question index
+	ListQuestionResults(ctx context.Context, tenantID uint64, taskID string) ([]*types.EvaluationQuestionResult, error)
+
+	// CreateDataset inserts a dataset together with its QA items
+	CreateDataset(ctx context.Context, dataset *types.EvaluationDataset, items []*types.EvaluationDatasetItem) error
+	// GetDataset returns dataset metadata of the given tenant
+	GetDataset(ctx context.Context, tenantID uint64, datasetID string) (*types.EvaluationDataset, error)
+	// ListDatasets returns the datasets of a tenant, newest first
+	ListDatasets(ctx context.Context, tenantID uint64) ([]*types.EvaluationDataset, error)
+	// ListDatasetItems returns the QA items of a dataset ordered by QID
+	ListDatasetItems(ctx context.Context, tenantID uint64, datasetID string) ([]*types.EvaluationDatasetItem, error)
+	// DeleteDataset removes a dataset and its items
+	DeleteDataset(ctx context.Context, tenantID uint64, datasetID string) error
 }
 
 // Metrics defines interface for computing evaluation metrics
@@ -22,6 +66,12 @@ type Metrics interface {
 	Compute(metricInput *types.MetricInput) float64
 }
 
+// EvaluationJudge scores a generated answer with an LLM acting as judge
+type EvaluationJudge interface {
+	// Judge returns the judge metrics of one evaluated question
+	Judge(ctx context.Context, input *types.JudgeInput) (*types.JudgeMetrics, error)
+}
+
 // EvalHook defines interface for evaluation process hooks
 type EvalHook interface {
 	// Handle processes evaluation state change
@@ -32,4 +82,10 @@ type EvalHook interface {
 type DatasetService interface {
 	// GetDatasetByID retrieves QA pairs from dataset by ID
 	GetDatasetByID(ctx context.Context, datasetID string) ([]*types.QAPair, error)
+	// UploadDataset parses an uploaded JSONL or parquet dataset and stores it for the current tenant
+	UploadDataset(ctx context.Context, upload *types.EvaluationDatasetUpload) (*types.EvaluationDataset, error)
+	// ListDatasets lists the uploaded datasets of the current tenant
+	ListDatasets(ctx context.Context) ([]*types.EvaluationDataset, error)
+	// DeleteDataset deletes an uploaded dataset of the current tenant
+	DeleteDataset(ctx context.Context, datasetID string) error
 }
@@ -0,0 +1,43 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// GraphCommunityRepository stores the community summaries of knowledge base
+// graphs. Communities are only ever replaced as a whole set per knowledge
+// base, so there is no per-row update.
+type GraphCommunityRepository interface {
+	// ReplaceByKnowledgeBase atomically swaps the communities of a knowledge
+	// base for a freshly built set (which may be empty).
+	ReplaceByKnowledgeBase(ctx context.Context, kbID string, communities []*types.GraphCommunity) error
+	// ListByKnowledgeBases returns the communities of the given knowledge
+	// bases, highest rank first.
+	ListByKnowledgeBases(ctx context.Context, kbIDs []string) ([]*types.GraphCommunity, error)
+	// DeleteByKnowledgeBase removes every community of a knowledge base.
+	DeleteByKnowledgeBase(ctx context.Context, kbID string) error
+}
+
+// GraphCommunityService detects communities in knowledge base graphs,
+// summarizes them and serves the summaries to global retrieval.
+type GraphCommunityService interface {
+	// ListCommunities returns the stored communities of a knowledge base,
+	// leaving out those built from chunks of excludeKnowledgeIDs.
+	ListCommunities(ctx context.Context, kbID string, excludeKnowledgeIDs []string) ([]*types.GraphCommunity, error)
+	// ScheduleRebuild enqueues a debounced community rebuild for a
+	// knowledge base. Bursts of calls for the same knowledge base (one per
+	// extracted chunk) coalesce into a single rebuild.
+	ScheduleRebuild(ctx context.Context, tenantID uint64, kbID string) error
+	// SearchCommunities ranks the communities of the given knowledge bases
+	// against a query and returns at most limit of them. Communities built
+	// from chunks of excludeKnowledgeIDs are dropped, so documents hidden by
+	// access lists do not reach the answer through their summaries.
+	SearchCommunities(
+		ctx context.Context, kbIDs []string, query string, limit int, excludeKnowledgeIDs []string,
+	) ([]*types.GraphCommunity, error)
+	// ProcessCommunityBuild is the asynq handler for TypeGraphCommunityBuild.
+	ProcessCommunityBuild(ctx context.Context, t *asynq.Task) error
+}
@@ -0,0 +1,133 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// KnowledgeACLService manages document and folder access lists and resolves
+// which documents the caller of a request may read
+type KnowledgeACLService interface {
+	// GetKnowledgeACL returns the access list of a document, including what
+	// it inherits from its folders
+	GetKnowledgeACL(ctx context.Context, knowledgeID string) (*types.KnowledgeACL, error)
+	// SetKnowledgeACL replaces the access list of a document; an empty list
+	// makes it inherit from its folders again
+	SetKnowledgeACL(ctx context.Context, knowledgeID string,
+		principals []types.KnowledgeACLPrincipal) (*types.KnowledgeACL, error)
+	// GetFolderACL returns the access list of a folder of a knowledge base
+	GetFolderACL(ctx context.Context, kbID string, folderPath string) (*types.KnowledgeACL, error)
+	// SetFolderACL replaces the access list of a folder of a knowledge base
+	SetFolderACL(ctx context.Context, kbID string, folderPath string,
+		principals []types.KnowledgeACLPrincipal) (*types.KnowledgeACL, error)
+	// ListKnowledgeBaseACLs returns every access list set in a knowledge base
+	ListKnowledgeBaseACLs(ctx context.Context, kbID string) ([]*types.KnowledgeACL, error)
+
+	// ResolveReader returns who the request in ctx reads as, or nil when
+	// access lists do not apply to it (background tasks and internal calls)
+	ResolveReader(ctx context.Context) (*types.KnowledgeACLReader, error)
+	// DeniedKnowledgeIDs returns, per knowledge base, the documents the
+	// caller in ctx may not read. Knowledge bases without restrictions for
+	// the caller are absent from the result.
+	DeniedKnowledgeIDs(ctx context.Context, kbIDs []string) (map[string][]string, error)
+	// CanReadKnowledge reports whether the caller in ctx may read a document
+	CanReadKnowledge(ctx context.Context, knowledge *types.Knowledge) (bool, error)
+
+	// ApplySourcePermissions replaces the access list a data source synced
+	// onto documents with the permissions reported by its connector. Source
+	// users are mapped to members of the data source's tenant by email;
+	// readers without an account keep the documents restricted. Public
+	// permissions remove the synced list.
+	ApplySourcePermissions(ctx context.Context, ds *types.DataSource, knowledgeIDs []string,
+		permissions *types.ItemPermissions) error
+	// MoveKnowledgeACL moves the access list of a document to the document
+	// that replaced it, as a data source sync does when a source item changes
+	MoveKnowledgeACL(ctx context.Context, kbID string, fromKnowledgeID string, toKnowledgeID string) error
+	// ClearSourcePermissions removes every access list entry a data source synced
+	ClearSourcePermissions(ctx context.Context, dataSourceID string) error
+	// CopyKnowledgeACL gives dst, a copy of src or src itself after a move to
+	// another knowledge base, the access list that decides access to src,
+	// including one inherited from its folders, as its own list
+	CopyKnowledgeACL(ctx context.Context, src, dst *types.Knowledge) error
+	// CopyFolderACLs copies the folder access lists of a knowledge base onto
+	// the same folders of another one, leaving folders that already have one
+	CopyFolderACLs(ctx context.Context, srcKBID string, dstKB *types.KnowledgeBase) error
+	// DeleteKnowledgeACLs removes the access lists of documents of a
+	// knowledge base
+	DeleteKnowledgeACLs(ctx context.Context, kbID string, knowledgeIDs []string) error
+	// DeleteKnowledgeBaseACLs removes every access list of a knowledge base
+	DeleteKnowledgeBaseACLs(ctx context.Context, kbID string) error
+}
+
+// KnowledgeACLRepository persists document and folder access lists
+type KnowledgeACLRepository interface {
+	// ListByKnowledgeBases returns the entries of the given knowledge bases
+	ListByKnowledgeBases(ctx context.Context, kbIDs []string) ([]*types.KnowledgeACLEntry, error)
+	// ListByTarget returns the entries of one document or folder
+	ListByTarget(ctx context.Context, kbID string, targetType types.KnowledgeACLTargetType,
+		targetID string) ([]*types.KnowledgeACLEntry, error)
+	// ReplaceTarget replaces the entries of one document or folder that were
+	// set by hand; entries synced from a data source are kept
+	ReplaceTarget(ctx context.Context, kbID string, targetType types.KnowledgeACLTargetType,
+		targetID string, entries []*types.KnowledgeACLEntry) error
+	// ReplaceSynced replaces the entries a data source synced onto the given
+	// documents
+	ReplaceSynced(ctx context.Context, kbID string, dataSourceID string, knowledgeIDs []string,
+		entries []*types.KnowledgeACLEntry) error
+	// MoveKnowledgeTarget moves every entry of a document to another one
+	MoveKnowledgeTarget(ctx context.Context, kbID string, fromKnowledgeID string, toKnowledgeID string) error
+	// DeleteByDataSource removes every entry a data source synced
+	DeleteByDataSource(ctx context.Context, dataSourceID string) error
+	// DeleteByKnowledge removes the entries of documents of a knowledge base
+	DeleteByKnowledge(ctx context.Context, kbID string, knowledgeIDs []string) error
+	// DeleteByKnowledgeBase removes every entry of a knowledge base
+	DeleteByKnowledgeBase(ctx context.Context, kbID string) error
+	// ListKnowledgeInFolders returns the documents stored in any of the
+	// folders, or below them, as ID and folder path only
+	ListKnowledgeInFolders(ctx context.Context, kbID string, folderPaths []string) ([]*types.Knowledge, error)
+	// CountByPrincipal counts the entries granting a principal
+	CountByPrincipal(ctx context.Context, tenantID uint64,
+		principalType types.KnowledgeACLPrincipalType, principalID string) (int64, error)
+}
+
+// UserGroupService manages the user groups of a tenant
+type UserGroupService interface {
+	// ListGroups returns the groups of the current tenant with member counts
+	ListGroups(ctx context.Context) ([]*types.UserGroup, error)
+	// GetGroup returns a group with its members
+	GetGroup(ctx context.Context, id string) (*types.UserGroup, error)
+	// CreateGroup adds a group to the current tenant
+	CreateGroup(ctx context.Context, group *types.UserGroup) (*types.UserGroup, error)
+	// UpdateGroup changes the name and description of a group
+	UpdateGroup(ctx context.Context, id string, name string, description string) (*types.UserGroup, error)
+	// DeleteGroup removes a group and its memberships. A group still granted
+	// by an access list cannot be deleted, since dropping it could make the
+	// documents it guards readable by everyone.
+	DeleteGroup(ctx context.Context, id string) error
+	// SetGroupMembers replaces the members of a group; every user must be a
+	// member of the current tenant
+	SetGroupMembers(ctx context.Context, id string, userIDs []string) (*types.UserGroup, error)
+}
+
+// UserGroupRepository persists user groups and their memberships
+type UserGroupRepository interface {
+	// Create inserts a group
+	Create(ctx context.Context, group *types.UserGroup) error
+	// Update saves every column of a group
+	Update(ctx context.Context, group *types.UserGroup) error
+	// Delete soft-deletes a group of a tenant and removes its memberships
+	Delete(ctx context.Context, tenantID uint64, id string) error
+	// Get returns a group of a tenant
+	Get(ctx context.Context, tenantID uint64, id string) (*types.UserGroup, error)
+	// List returns the groups of a tenant, oldest first, with member counts
+	List(ctx context.Context, tenantID uint64) ([]*types.UserGroup, error)
+	// ListByIDs returns the groups of a tenant among ids
+	ListByIDs(ctx context.Context, tenantID uint64, ids []string) ([]*types.UserGroup, error)
+	// ListMemberIDs returns the user IDs of a group's members
+	ListMemberIDs(ctx context.Context, groupID string) ([]string, error)
+	// ReplaceMembers replaces the members of a group
+	ReplaceMembers(ctx context.Context, tenantID uint64, groupID string, userIDs []string) error
+	// ListGroupIDsByUser returns the groups a user belongs to, in any tenant
+	ListGroupIDsByUser(ctx context.Context, userID string) ([]string, error)
+}
@@ -136,6 +136,50 @@ type KnowledgeBaseService interface {
 	// Returns:
 	//   - Possible errors during deletion
 	ProcessKBDelete(ctx context.Context, t *asynq.Task) error
+
+	// SetEmbeddingModel switches the knowledge base to another embedding model.
+	// A knowledge base with nothing indexed yet is switched in place and nil
+	// is returned; otherwise a background re-embedding job is queued and its
+	// migration record is returned. Queries keep using the old model until
+	// the job cuts over.
+	// Parameters:
+	//   - ctx: Context information
+	//   - id: Knowledge base ID
+	//   - modelID: Target embedding model ID
+	// Returns:
+	//   - The queued migration, or nil when switched in place
+	//   - Possible errors such as a migration already running
+	SetEmbeddingModel(ctx context.Context, id string, modelID string) (*types.EmbeddingMigration, error)
+
+	// ProcessEmbeddingMigration handles the async re-embedding task
+	// Parameters:
+	//   - ctx: Context information
+	//   - t: Asynq task containing KBReembedPayload
+	// Returns:
+	//   - Possible errors; the task is retried until its last attempt
+	ProcessEmbeddingMigration(ctx context.Context, t *asynq.Task) error
+
+	// MigrateVectorStore moves the knowledge base's index entries, with their
+	// stored embeddings, to another vector store. A background job copies and
+	// verifies the entries; queries keep using the current store until the
+	// job rebinds the knowledge base.
+	// Parameters:
+	//   - ctx: Context information
+	//   - id: Knowledge base ID
+	//   - targetStoreID: Target vector store ID
+	// Returns:
+	//   - The queued migration
+	//   - Possible errors such as a migration already running or an engine
+	//     that cannot export its entries
+	MigrateVectorStore(ctx context.Context, id string, targetStoreID string) (*types.VectorStoreMigration, error)
+
+	// ProcessVectorStoreMigration handles the async vector store migration task
+	// Parameters:
+	//   - ctx: Context information
+	//   - t: Asynq task containing KBVectorStoreMigratePayload
+	// Returns:
+	//   - Possible errors; the task resumes from its last checkpoint on retry
+	ProcessVectorStoreMigration(ctx context.Context, t *asynq.Task) error
 }
 
 // KnowledgeBaseRepository defines the knowledge base repository interface
@@ -225,6 +269,28 @@ type KnowledgeBaseRepository interface {
 	// CountByModelID counts active KBs in the tenant that reference the given
 	// model ID in any model-binding field (embedding, summary, VLM, ASR, etc.).
 	CountByModelID(ctx context.Context, tenantID uint64, modelID string) (int64, error)
+
+	// UpdateEmbeddingMigration overwrites only the embedding_migration column,
+	// so progress updates never race with a full-row settings save.
+	UpdateEmbeddingMigration(ctx context.Context, id string, migration *types.EmbeddingMigration) error
+
+	// SwitchEmbeddingIndex atomically points the knowledge base at a new
+	// embedding model and index label, records the finished migration, and
+	// re-stamps the embedding model of every document in the knowledge base.
+	// Returns ErrKnowledgeBaseNotFound when the knowledge base is gone.
+	SwitchEmbeddingIndex(
+		ctx context.Context, id string, modelID string, indexID string, migration *types.EmbeddingMigration,
+	) error
+
+	// UpdateVectorStoreMigration overwrites only the vector_store_migration
+	// column, so progress checkpoints never race with a full-row settings save.
+	UpdateVectorStoreMigration(ctx context.Context, id string, migration *types.VectorStoreMigration) error
+
+	// SwitchVectorStore rebinds the knowledge base to another vector store and
+	// records the finished migration. It is the only writer of
+	// vector_store_id after creation. Returns ErrKnowledgeBaseNotFound when
+	// the knowledge base is gone.
+	SwitchVectorStore(ctx context.Context, id string, storeID string, migration *types.VectorStoreMigration) error
 	// SetUserKBPin inserts or removes a row in user_kb_pins for the given
 	// (tenant, user, kb) triple. Returns the resulting pinned_at (nil when
 	// pinned=false) and an error. The tenant_id is captured to support
@@ -66,6 +66,13 @@ type MCPServiceService interface {
 	// GetMCPServiceResources retrieves the list of resources from an MCP service
 	GetMCPServiceResources(ctx context.Context, tenantID uint64, id string) ([]*types.MCPResource, error)
 
+	// GetMCPServicePrompts retrieves the list of prompt templates from an MCP service
+	GetMCPServicePrompts(ctx context.Context, tenantID uint64, id string) ([]*types.MCPPrompt, error)
+
+	// RenderMCPServicePrompt renders a prompt template of an enabled MCP
+	// service and returns the text of its messages
+	RenderMCPServicePrompt(ctx context.Context, tenantID uint64, ref *types.MCPPromptRef) (string, error)
+
 	// UpdateMCPCredentials writes one or more credential fields on the auth
 	// config. Nil pointer means "do not touch this field". Returns the updated
 	// service (with current AuthConfig) so the handler can derive the
@@ -52,6 +52,11 @@ type RetrieveEngineRepository interface {
 	// DeleteByKnowledgeIDList deletes the index info by knowledge id list
 	DeleteByKnowledgeIDList(ctx context.Context, knowledgeIDList []string, dimension int, knowledgeType string) error
 
+	// DeleteByKnowledgeBaseIDList deletes the index info by knowledge base id list.
+	// The IDs are index labels (KnowledgeBase.IndexKnowledgeBaseID); this is how
+	// the previous index of a re-embedded knowledge base is dropped.
+	DeleteByKnowledgeBaseIDList(ctx context.Context, knowledgeBaseIDList []string, dimension int, knowledgeType string) error
+
 	// BatchUpdateChunkEnabledStatus updates the enabled status of chunks in batch
 	// chunkStatusMap: map of chunk ID to enabled status (true = enabled, false = disabled)
 	BatchUpdateChunkEnabledStatus(ctx context.Context, chunkStatusMap map[string]bool) error
@@ -64,6 +69,34 @@ type RetrieveEngineRepository interface {
 	RetrieveEngine
 }
 
+// RetrieveEngineIndexExporter is implemented by retrieve engine repositories
+// (and services) that can read their index entries back together with the
+// stored embeddings. It is what lets a knowledge base move to another vector
+// store without calling the embedding model again.
+type RetrieveEngineIndexExporter interface {
+	// ExportIndices returns up to limit entries stored under the knowledge
+	// base label, starting after cursor ("" for the first page). The order is
+	// stable, so a page can be re-read from the cursor of the previous one.
+	ExportIndices(
+		ctx context.Context, knowledgeBaseID string, dimension int, knowledgeType string, cursor string, limit int,
+	) (*types.IndexExportPage, error)
+
+	// CountIndices returns the number of entries stored under the knowledge
+	// base label.
+	CountIndices(ctx context.Context, knowledgeBaseID string, dimension int, knowledgeType string) (int64, error)
+}
+
+// RetrieveEngineMetadataUpdater is implemented by retrieve engine repositories
+// (and services) that store the custom metadata of documents on their index
+// entries (IndexInfo.Metadata). It lets a metadata edit reach metadata
+// filters without re-indexing the document.
+type RetrieveEngineMetadataUpdater interface {
+	// BatchUpdateKnowledgeMetadata replaces the metadata of every entry of
+	// each knowledge ID with the given fields, as returned by
+	// types.IndexMetadata. A nil map clears it.
+	BatchUpdateKnowledgeMetadata(ctx context.Context, knowledgeMetadata map[string]map[string]any) error
+}
+
 // RetrieveEngineRegistry defines the retrieve engine registry interface
 type RetrieveEngineRegistry interface {
 	// Register registers the retrieve engine service
@@ -143,6 +176,11 @@ type RetrieveEngineService interface {
 	// DeleteByKnowledgeIDList deletes the index info by knowledge id list
 	DeleteByKnowledgeIDList(ctx context.Context, knowledgeIDList []string, dimension int, knowledgeType string) error
 
+	// DeleteByKnowledgeBaseIDList deletes the index info by knowledge base id list.
+	// The IDs are index labels (KnowledgeBase.IndexKnowledgeBaseID); this is how
+	// the previous index of a re-embedded knowledge base is dropped.
+	DeleteByKnowledgeBaseIDList(ctx context.Context, knowledgeBaseIDList []string, dimension int, knowledgeType string) error
+
 	// BatchUpdateChunkEnabledStatus updates the enabled status of chunks in batch
 	// chunkStatusMap: map of chunk ID to enabled status (true = enabled, false = disabled)
 	BatchUpdateChunkEnabledStatus(ctx context.Context, chunkStatusMap map[string]bool) error
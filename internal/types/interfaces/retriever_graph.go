@@ -14,4 +14,16 @@ type RetrieveGraphRepository interface {
 	DelGraph(ctx context.Context, namespace []types.NameSpace) error
 	// SearchNode searches for nodes in the repository
 	SearchNode(ctx context.Context, namespace types.NameSpace, nodes []string) (*types.GraphData, error)
+	// ExpandNode returns the k-hop neighbourhood of the named nodes (exact
+	// name match), walking only the relation types in opts when given
+	ExpandNode(
+		ctx context.Context, namespace types.NameSpace, nodes []string, opts types.GraphTraversalOptions,
+	) (*types.GraphData, error)
+	// ShortestPath returns a shortest path between two named nodes within
+	// opts.MaxHops, or nil when they are not connected
+	ShortestPath(
+		ctx context.Context, namespace types.NameSpace, source, target string, opts types.GraphTraversalOptions,
+	) (*types.GraphPath, error)
+	// GetGraph returns the whole graph of a namespace, nodes merged by name
+	GetGraph(ctx context.Context, namespace types.NameSpace) (*types.GraphData, error)
 }
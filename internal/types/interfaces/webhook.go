@@ -0,0 +1,61 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// WebhookService manages the outbound webhook subscriptions of a tenant and
+// delivers workspace events to them
+type WebhookService interface {
+	// CreateSubscription adds a webhook subscription to the current tenant
+	CreateSubscription(ctx context.Context, sub *types.WebhookSubscription) (*types.WebhookSubscription, error)
+	// ListSubscriptions returns the webhook subscriptions of the current tenant
+	ListSubscriptions(ctx context.Context) ([]*types.WebhookSubscription, error)
+	// GetSubscription returns one webhook subscription
+	GetSubscription(ctx context.Context, id string) (*types.WebhookSubscription, error)
+	// UpdateSubscription replaces the editable fields of a subscription. A
+	// nil secret keeps the stored secret.
+	UpdateSubscription(ctx context.Context, id string,
+		sub *types.WebhookSubscription, secret *string) (*types.WebhookSubscription, error)
+	// DeleteSubscription removes a subscription; its delivery log is kept
+	DeleteSubscription(ctx context.Context, id string) error
+	// ListDeliveries returns a page of a subscription's deliveries, newest
+	// first, optionally only those with status
+	ListDeliveries(ctx context.Context, id string, status string, page *types.Pagination) (*types.PageResult, error)
+	// ReplayDelivery sends the payload of a past delivery again as a new delivery
+	ReplayDelivery(ctx context.Context, id string, deliveryID string) (*types.WebhookDelivery, error)
+	// ProcessDelivery is the asynq handler that POSTs one delivery
+	ProcessDelivery(ctx context.Context, task *asynq.Task) error
+}
+
+// WebhookRepository persists webhook subscriptions and their deliveries
+type WebhookRepository interface {
+	// CreateSubscription inserts a subscription
+	CreateSubscription(ctx context.Context, sub *types.WebhookSubscription) error
+	// UpdateSubscription saves every column of a subscription
+	UpdateSubscription(ctx context.Context, sub *types.WebhookSubscription) error
+	// DeleteSubscription soft-deletes a subscription of a tenant
+	DeleteSubscription(ctx context.Context, tenantID uint64, id string) error
+	// GetSubscription returns a subscription of a tenant
+	GetSubscription(ctx context.Context, tenantID uint64, id string) (*types.WebhookSubscription, error)
+	// ListSubscriptions returns the subscriptions of a tenant, oldest first
+	ListSubscriptions(ctx context.Context, tenantID uint64) ([]*types.WebhookSubscription, error)
+	// ListEnabledSubscriptions returns the enabled subscriptions of a tenant
+	ListEnabledSubscriptions(ctx context.Context, tenantID uint64) ([]*types.WebhookSubscription, error)
+
+	// CreateDelivery inserts a delivery
+	CreateDelivery(ctx context.Context, delivery *types.WebhookDelivery) error
+	// UpdateDelivery saves every column of a delivery
+	UpdateDelivery(ctx context.Context, delivery *types.WebhookDelivery) error
+	// DeleteDelivery removes a delivery that was never enqueued
+	DeleteDelivery(ctx context.Context, id string) error
+	// GetDelivery returns a delivery of a tenant
+	GetDelivery(ctx context.Context, tenantID uint64, id string) (*types.WebhookDelivery, error)
+	// ListDeliveries returns a page of a subscription's deliveries, newest
+	// first, and the total count. An empty status lists every delivery.
+	ListDeliveries(ctx context.Context, tenantID uint64, subscriptionID string, status string,
+		page *types.Pagination) ([]*types.WebhookDelivery, int64, error)
+}
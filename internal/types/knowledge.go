@@ -36,6 +36,9 @@ const (
 	ChannelYuque            = "yuque"             // Yuque (语雀)
 	ChannelRSS              = "rss"               // RSS / Atom feed
 	ChannelIMA              = "ima"               // Tencent IMA (ima.qq.com)
+	ChannelConfluence       = "confluence"        // Atlassian Confluence
+	ChannelAgentTrigger     = "agent_trigger"     // Scheduled or event-driven agent run
+	ChannelMCP              = "mcp"               // Server-hosted MCP endpoint
 )
 
 // Knowledge parse status constants
@@ -116,6 +119,9 @@ type KnowledgeListFilter struct {
 	// FolderScope selects whether FolderPath matches exactly or includes
 	// descendant folders. FolderScopeAny (the default) ignores folders.
 	FolderScope KnowledgeFolderScope
+	// ExcludeKnowledgeIDs hides documents the caller may not read under
+	// document access lists.
+	ExcludeKnowledgeIDs []string
 }
 
 // Knowledge represents a knowledge entity in the system.
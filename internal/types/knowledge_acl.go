@@ -0,0 +1,266 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KnowledgeACLTargetType names what a knowledge ACL entry is attached to
+type KnowledgeACLTargetType string
+
+const (
+	// KnowledgeACLTargetKnowledge attaches an entry to a single document;
+	// TargetID is the knowledge ID
+	KnowledgeACLTargetKnowledge KnowledgeACLTargetType = "knowledge"
+	// KnowledgeACLTargetFolder attaches an entry to a folder and everything
+	// below it; TargetID is the normalized folder path
+	KnowledgeACLTargetFolder KnowledgeACLTargetType = "folder"
+)
+
+// KnowledgeACLPrincipalType names who a knowledge ACL entry grants read access to
+type KnowledgeACLPrincipalType string
+
+const (
+	// KnowledgeACLPrincipalUser grants a WeKnora user, by user ID
+	KnowledgeACLPrincipalUser KnowledgeACLPrincipalType = "user"
+	// KnowledgeACLPrincipalGroup grants every member of a user group, by group ID
+	KnowledgeACLPrincipalGroup KnowledgeACLPrincipalType = "group"
+	// KnowledgeACLPrincipalSource records a reader of the source system a
+	// data source synced the document from who has no WeKnora account. It
+	// grants nobody, but keeps the document restricted; PrincipalID
+	// identifies the source user or group.
+	KnowledgeACLPrincipalSource KnowledgeACLPrincipalType = "source"
+)
+
+// MaxKnowledgeACLPrincipals caps how many principals one document or folder
+// can list; larger audiences belong in a user group.
+const MaxKnowledgeACLPrincipals = 200
+
+// KnowledgeACLEntry grants one principal read access to a document or a
+// folder of a knowledge base. A document or folder without entries inherits
+// from its closest ancestor folder that has some; when none has, the
+// document is readable by everyone with access to the knowledge base.
+//
+// Entries with a DataSourceID were copied from the source system's sharing
+// settings by that data source and are rewritten by each of its syncs;
+// entries set through the API have none and are never touched by a sync.
+type KnowledgeACLEntry struct {
+	ID              string                    `json:"id"                gorm:"type:varchar(36);primaryKey"`
+	TenantID        uint64                    `json:"tenant_id"         gorm:"index"`
+	KnowledgeBaseID string                    `json:"knowledge_base_id" gorm:"type:varchar(36);index"`
+	TargetType      KnowledgeACLTargetType    `json:"target_type"       gorm:"type:varchar(16)"`
+	TargetID        string                    `json:"target_id"         gorm:"type:varchar(1024)"`
+	PrincipalType   KnowledgeACLPrincipalType `json:"principal_type"    gorm:"type:varchar(16)"`
+	PrincipalID     string                    `json:"principal_id"      gorm:"type:varchar(64)"`
+	DataSourceID    string                    `json:"data_source_id"    gorm:"type:varchar(36);index"`
+	CreatedBy       string                    `json:"created_by"        gorm:"type:varchar(36)"`
+	CreatedAt       time.Time                 `json:"created_at"`
+}
+
+// TableName returns the table name for KnowledgeACLEntry
+func (KnowledgeACLEntry) TableName() string {
+	return "knowledge_acl_entries"
+}
+
+// KnowledgeACLPrincipal is one user or group of an access list
+type KnowledgeACLPrincipal struct {
+	Type KnowledgeACLPrincipalType `json:"type"`
+	ID   string                    `json:"id"`
+	// Name is the display name of the user or group, filled on read
+	Name string `json:"name,omitempty"`
+	// DataSourceID is set on principals synced from a data source; they are
+	// read-only and ignored when an access list is replaced
+	DataSourceID string `json:"data_source_id,omitempty"`
+}
+
+// Validate checks the principal type and ID
+func (p KnowledgeACLPrincipal) Validate() error {
+	if p.Type != KnowledgeACLPrincipalUser && p.Type != KnowledgeACLPrincipalGroup {
+		return fmt.Errorf("invalid principal type %q", p.Type)
+	}
+	if strings.TrimSpace(p.ID) == "" {
+		return fmt.Errorf("principal id is required")
+	}
+	return nil
+}
+
+// KnowledgeACL is the access list of one document or folder
+type KnowledgeACL struct {
+	KnowledgeBaseID string                 `json:"knowledge_base_id"`
+	TargetType      KnowledgeACLTargetType `json:"target_type"`
+	TargetID        string                 `json:"target_id"`
+	// Principals are the entries set on the target itself
+	Principals []KnowledgeACLPrincipal `json:"principals"`
+	// InheritedFrom is the folder whose entries apply when the target has
+	// none of its own, or null when nothing is inherited
+	InheritedFrom *string `json:"inherited_from"`
+	// InheritedPrincipals are the entries of InheritedFrom
+	InheritedPrincipals []KnowledgeACLPrincipal `json:"inherited_principals,omitempty"`
+}
+
+// Restricted reports whether the access list limits who can read the target
+func (a *KnowledgeACL) Restricted() bool {
+	return len(a.Principals) > 0 || a.InheritedFrom != nil
+}
+
+// KnowledgeACLReader identifies who retrieval runs for. A nil reader stands
+// for a system caller that is not subject to document access lists.
+type KnowledgeACLReader struct {
+	// UserID is empty for callers without a WeKnora account (IM users, embed
+	// visitors, API keys), which can only read unrestricted documents
+	UserID string
+	// GroupIDs are the user groups UserID belongs to
+	GroupIDs map[string]bool
+	// BypassTenantIDs are the tenants whose knowledge bases the reader
+	// administers; access lists never apply to them there
+	BypassTenantIDs map[uint64]bool
+}
+
+// Matches reports whether an entry grants the reader access
+func (r *KnowledgeACLReader) Matches(entry *KnowledgeACLEntry) bool {
+	if r == nil || entry == nil {
+		return false
+	}
+	switch entry.PrincipalType {
+	case KnowledgeACLPrincipalUser:
+		return r.UserID != "" && entry.PrincipalID == r.UserID
+	case KnowledgeACLPrincipalGroup:
+		return r.GroupIDs[entry.PrincipalID]
+	}
+	return false
+}
+
+// KnowledgeACLIndex resolves the effective access list of the documents of
+// one knowledge base from its entries
+type KnowledgeACLIndex struct {
+	byKnowledge map[string][]*KnowledgeACLEntry
+	byFolder    map[string][]*KnowledgeACLEntry
+}
+
+// NewKnowledgeACLIndex indexes the entries of one knowledge base
+func NewKnowledgeACLIndex(entries []*KnowledgeACLEntry) *KnowledgeACLIndex {
+	index := &KnowledgeACLIndex{
+		byKnowledge: make(map[string][]*KnowledgeACLEntry),
+		byFolder:    make(map[string][]*KnowledgeACLEntry),
+	}
+	for _, entry := range entries {
+		switch entry.TargetType {
+		case KnowledgeACLTargetKnowledge:
+			index.byKnowledge[entry.TargetID] = append(index.byKnowledge[entry.TargetID], entry)
+		case KnowledgeACLTargetFolder:
+			index.byFolder[entry.TargetID] = append(index.byFolder[entry.TargetID], entry)
+		}
+	}
+	return index
+}
+
+// Empty reports whether no document of the knowledge base is restricted
+func (x *KnowledgeACLIndex) Empty() bool {
+	return len(x.byKnowledge) == 0 && len(x.byFolder) == 0
+}
+
+// KnowledgeIDs returns the documents that carry entries of their own
+func (x *KnowledgeACLIndex) KnowledgeIDs() []string {
+	ids := make([]string, 0, len(x.byKnowledge))
+	for id := range x.byKnowledge {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// FolderPaths returns the folders that carry entries
+func (x *KnowledgeACLIndex) FolderPaths() []string {
+	paths := make([]string, 0, len(x.byFolder))
+	for path := range x.byFolder {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// KnowledgeEntries returns the entries set on a document itself
+func (x *KnowledgeACLIndex) KnowledgeEntries(knowledgeID string) []*KnowledgeACLEntry {
+	return x.byKnowledge[knowledgeID]
+}
+
+// FolderEntries returns the entries set on folderPath itself
+func (x *KnowledgeACLIndex) FolderEntries(folderPath string) []*KnowledgeACLEntry {
+	return x.byFolder[folderPath]
+}
+
+// Effective returns the entries that decide access to a document: its own,
+// else those of its closest ancestor folder that has some. from is the
+// folder the entries come from, empty for the document's own entries; ok is
+// false when the document is unrestricted.
+func (x *KnowledgeACLIndex) Effective(knowledgeID, folderPath string) (entries []*KnowledgeACLEntry, from string, ok bool) {
+	if own := x.byKnowledge[knowledgeID]; len(own) > 0 {
+		return own, "", true
+	}
+	return x.InheritedFolder(folderPath)
+}
+
+// InheritedFolder returns the entries of folderPath or of its closest
+// ancestor folder that has some
+func (x *KnowledgeACLIndex) InheritedFolder(folderPath string) (entries []*KnowledgeACLEntry, from string, ok bool) {
+	for path := folderPath; path != ""; path = parentKnowledgeFolder(path) {
+		if folder := x.byFolder[path]; len(folder) > 0 {
+			return folder, path, true
+		}
+	}
+	return nil, "", false
+}
+
+// Allows reports whether the reader may read a document
+func (x *KnowledgeACLIndex) Allows(reader *KnowledgeACLReader, knowledgeID, folderPath string) bool {
+	entries, _, restricted := x.Effective(knowledgeID, folderPath)
+	if !restricted {
+		return true
+	}
+	for _, entry := range entries {
+		if reader.Matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func parentKnowledgeFolder(path string) string {
+	if idx := strings.LastIndex(path, "/"); idx >= 0 {
+		return path[:idx]
+	}
+	return ""
+}
+
+// UserGroup is a named set of users of a tenant that document access lists
+// can grant as a whole
+type UserGroup struct {
+	ID          string         `json:"id"           gorm:"type:varchar(36);primaryKey"`
+	TenantID    uint64         `json:"tenant_id"    gorm:"index"`
+	Name        string         `json:"name"         gorm:"type:varchar(255)"`
+	Description string         `json:"description"  gorm:"type:text"`
+	CreatedBy   string         `json:"created_by"   gorm:"type:varchar(36)"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-"            gorm:"index"`
+	// MemberCount is filled on list
+	MemberCount int `json:"member_count" gorm:"-"`
+	// Members is filled when a single group is read
+	Members []*UserGroupMemberInfo `json:"members,omitempty" gorm:"-"`
+}
+
+// UserGroupMember puts a user in a user group
+type UserGroupMember struct {
+	GroupID   string    `json:"group_id"   gorm:"type:varchar(36);primaryKey"`
+	UserID    string    `json:"user_id"    gorm:"type:varchar(36);primaryKey"`
+	TenantID  uint64    `json:"tenant_id"  gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserGroupMemberInfo describes a member of a user group
+type UserGroupMemberInfo struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
@@ -81,6 +81,18 @@ type KnowledgeBase struct {
 	ImageProcessingConfig ImageProcessingConfig `yaml:"image_processing_config" json:"image_processing_config" gorm:"type:json"`
 	// ID of the embedding model
 	EmbeddingModelID string `yaml:"embedding_model_id"      json:"embedding_model_id"`
+	// EmbeddingIndexID is the knowledge_base_id label this knowledge base's
+	// chunks are stored under in the retrieval index; empty means the
+	// knowledge base's own ID. A re-embedding job writes its shadow index
+	// under a fresh label and switches this field together with
+	// EmbeddingModelID, so queries move to the new vectors in one row update.
+	// Use IndexKnowledgeBaseID() rather than reading it directly. Only
+	// KnowledgeBaseRepository.SwitchEmbeddingIndex writes it (`<-:create`).
+	EmbeddingIndexID string `yaml:"-"                       json:"-"                       gorm:"column:embedding_index_id;type:varchar(36);<-:create"`
+	// EmbeddingMigration reports the latest background re-embedding job, if
+	// any. Written through KnowledgeBaseRepository.UpdateEmbeddingMigration so
+	// a concurrent settings save cannot roll the progress back.
+	EmbeddingMigration *EmbeddingMigration `yaml:"embedding_migration" json:"embedding_migration,omitempty" gorm:"column:embedding_migration;type:json;<-:create"`
 	// Summary model ID
 	SummaryModelID string `yaml:"summary_model_id"        json:"summary_model_id"`
 	// VLM config
@@ -99,8 +111,14 @@ type KnowledgeBase struct {
 	// the RETRIEVE_DRIVER environment variable (env store flow).
 	// This field is set once at creation time and must not be modified afterwards;
 	// enforcement lives at the GORM layer (`<-:create`) plus the service-layer
-	// KB update path, which omits this field from its update DTO.
+	// KB update path, which omits this field from its update DTO. The only
+	// exception is the cutover of a vector store migration, which goes through
+	// KnowledgeBaseRepository.SwitchVectorStore.
 	VectorStoreID *string `yaml:"vector_store_id"         json:"vector_store_id,omitempty" gorm:"column:vector_store_id;type:varchar(36);<-:create"`
+	// VectorStoreMigration reports the latest background copy of this
+	// knowledge base's index into another vector store, if any. Written
+	// through KnowledgeBaseRepository.UpdateVectorStoreMigration.
+	VectorStoreMigration *VectorStoreMigration `yaml:"vector_store_migration" json:"vector_store_migration,omitempty" gorm:"column:vector_store_migration;type:json;<-:create"`
 	// Extract config
 	ExtractConfig *ExtractConfig `yaml:"extract_config"          json:"extract_config"          gorm:"column:extract_config;type:json"`
 	// FAQConfig stores FAQ specific configuration such as indexing strategy
@@ -114,6 +132,9 @@ type KnowledgeBase struct {
 	// IndexingStrategy controls which indexing pipelines are active for this knowledge base.
 	// Pipelines: vector search, keyword search, wiki generation, knowledge graph extraction.
 	IndexingStrategy IndexingStrategy `yaml:"indexing_strategy"       json:"indexing_strategy"       gorm:"column:indexing_strategy;type:json"`
+	// FusionConfig overrides the tenant's RetrievalConfig fusion settings for
+	// searches whose primary knowledge base is this one; nil inherits them.
+	FusionConfig *FusionConfig `yaml:"fusion_config"           json:"fusion_config,omitempty" gorm:"column:fusion_config;type:json"`
 	// IsPinned and PinnedAt are computed per-caller from user_kb_pins
 	// (see migration 000050). They used to be stored on the row itself,
 	// which made pinning a workspace-wide ordering decision gated behind
@@ -163,6 +184,9 @@ type KnowledgeBaseConfig struct {
 	// IndexingStrategy controls which indexing pipelines are active.
 	// nil means "no change" when updating (preserves existing strategy).
 	IndexingStrategy *IndexingStrategy `yaml:"indexing_strategy"       json:"indexing_strategy"`
+	// FusionConfig overrides the tenant fusion settings for this knowledge base.
+	// nil means "no change" when updating; an empty object clears the override.
+	FusionConfig *FusionConfig `yaml:"fusion_config"           json:"fusion_config"`
 }
 
 const (
@@ -805,6 +829,18 @@ func (kb *KnowledgeBase) IsGraphEnabled() bool {
 		kb.ExtractConfig != nil && kb.ExtractConfig.Enabled
 }
 
+// IndexKnowledgeBaseID returns the knowledge_base_id that this knowledge
+// base's chunks are written under and searched by in the retrieval index.
+// Every IndexInfo and RetrieveParams built for the knowledge base must use it
+// instead of kb.ID, otherwise writes land in (or reads hit) the index of a
+// previous embedding model.
+func (kb *KnowledgeBase) IndexKnowledgeBaseID() string {
+	if kb.EmbeddingIndexID != "" {
+		return kb.EmbeddingIndexID
+	}
+	return kb.ID
+}
+
 // NeedsEmbeddingModel returns true if any enabled pipeline requires an embedding model.
 // Currently only vector and keyword search need embeddings.
 func (kb *KnowledgeBase) NeedsEmbeddingModel() bool {
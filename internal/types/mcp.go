@@ -151,6 +151,28 @@ type MCPResource struct {
 	MimeType    string `json:"mimeType,omitempty"`
 }
 
+// MCPPrompt represents a prompt template exposed by an MCP service
+type MCPPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument represents an argument of an MCP prompt template
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPPromptRef selects a prompt of an MCP service, with the arguments it is
+// rendered with
+type MCPPromptRef struct {
+	ServiceID string            `yaml:"service_id" json:"service_id"`
+	Name      string            `yaml:"name" json:"name"`
+	Arguments map[string]string `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+}
+
 // MCPTestResult represents the result of testing an MCP service connection
 type MCPTestResult struct {
 	Success     bool   `json:"success"`
@@ -163,6 +185,7 @@ type MCPTestResult struct {
 	OAuthRequired bool           `json:"oauth_required,omitempty"`
 	Tools         []*MCPTool     `json:"tools,omitempty"`
 	Resources     []*MCPResource `json:"resources,omitempty"`
+	Prompts       []*MCPPrompt   `json:"prompts,omitempty"`
 }
 
 // BeforeCreate is a GORM hook that runs before creating a new MCP service
@@ -0,0 +1,361 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// MetadataFilterOp is the operator of a MetadataFilter node.
+type MetadataFilterOp string
+
+const (
+	// MetadataFilterEq matches documents whose field equals Value.
+	MetadataFilterEq MetadataFilterOp = "eq"
+	// MetadataFilterIn matches documents whose field equals any of Values.
+	MetadataFilterIn MetadataFilterOp = "in"
+	// MetadataFilterGt, MetadataFilterGte, MetadataFilterLt and
+	// MetadataFilterLte compare a number or a date.
+	MetadataFilterGt  MetadataFilterOp = "gt"
+	MetadataFilterGte MetadataFilterOp = "gte"
+	MetadataFilterLt  MetadataFilterOp = "lt"
+	MetadataFilterLte MetadataFilterOp = "lte"
+	// MetadataFilterAnd and MetadataFilterOr combine Filters.
+	MetadataFilterAnd MetadataFilterOp = "and"
+	MetadataFilterOr  MetadataFilterOp = "or"
+)
+
+const (
+	// maxMetadataFilterDepth bounds the nesting of and/or nodes.
+	maxMetadataFilterDepth = 4
+	// maxMetadataFilterConditions bounds the number of comparisons, so a
+	// filter always translates into a reasonably sized engine query.
+	maxMetadataFilterConditions = 32
+	// maxMetadataFilterValues bounds the value list of an "in" comparison.
+	maxMetadataFilterValues = 100
+	// maxMetadataKeyLength matches the key limit of Knowledge.CustomMetadata.
+	maxMetadataKeyLength = 64
+)
+
+// MetadataValueKind tells how a custom metadata value is stored in index
+// entries. Each kind lives under its own field, so a key holding a string in
+// one document and a number in another never conflicts in engines with typed
+// schemas.
+type MetadataValueKind string
+
+const (
+	MetadataKindString MetadataValueKind = "s"
+	MetadataKindNumber MetadataValueKind = "n"
+	MetadataKindBool   MetadataValueKind = "b"
+	// MetadataKindDate holds string values that parse as a date, as Unix
+	// seconds, next to their string field.
+	MetadataKindDate MetadataValueKind = "d"
+)
+
+// metadataDateLayouts are the date formats recognised in metadata values and
+// in range filters. Values without a zone are taken as UTC.
+var metadataDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// MetadataField returns the flat field name under which index entries store
+// values of the given kind for a custom metadata key. The key is hex-encoded,
+// so the name is a valid field, column or property name in every engine.
+func MetadataField(kind MetadataValueKind, key string) string {
+	return string(kind) + "_" + hex.EncodeToString([]byte(key))
+}
+
+// ParseMetadataDate parses a date in one of the supported layouts.
+func ParseMetadataDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range metadataDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// IndexMetadata converts Knowledge.CustomMetadata into the flat, typed fields
+// stored on every index entry of the document. It returns nil when there is
+// nothing to store.
+func IndexMetadata(custom JSON) map[string]any {
+	if len(custom) == 0 {
+		return nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal(custom, &values); err != nil || len(values) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			fields[MetadataField(MetadataKindString, key)] = v
+			if t, ok := ParseMetadataDate(v); ok {
+				fields[MetadataField(MetadataKindDate, key)] = float64(t.Unix())
+			}
+		case float64:
+			fields[MetadataField(MetadataKindNumber, key)] = v
+		case bool:
+			fields[MetadataField(MetadataKindBool, key)] = v
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// IndexMetadata returns the custom metadata fields stored on the index
+// entries of this knowledge.
+func (k *Knowledge) IndexMetadata() map[string]any {
+	if k == nil {
+		return nil
+	}
+	return IndexMetadata(k.CustomMetadata)
+}
+
+// MetadataFilter is a filter expression over Knowledge.CustomMetadata.
+//
+// A leaf compares one metadata key: "eq" and "in" take strings, numbers or
+// booleans; "gt", "gte", "lt" and "lte" take a number or a date
+// ("2025-01-01", RFC 3339). "and" and "or" combine Filters. For example
+// region = EU and effective_date > 2025-01-01:
+//
+//	{"op": "and", "filters": [
+//	  {"op": "eq", "key": "region", "value": "EU"},
+//	  {"op": "gt", "key": "effective_date", "value": "2025-01-01"}]}
+//
+// Engines translate the normalised form returned by Normalize.
+type MetadataFilter struct {
+	Op      MetadataFilterOp  `json:"op"`
+	Key     string            `json:"key,omitempty"`
+	Value   any               `json:"value,omitempty"`
+	Values  []any             `json:"values,omitempty"`
+	Filters []*MetadataFilter `json:"filters,omitempty"`
+
+	// Field is the stored field a normalised leaf compares; see MetadataField.
+	Field string `json:"-"`
+}
+
+// IsRange reports whether op is a range comparison.
+func (op MetadataFilterOp) IsRange() bool {
+	switch op {
+	case MetadataFilterGt, MetadataFilterGte, MetadataFilterLt, MetadataFilterLte:
+		return true
+	}
+	return false
+}
+
+// Normalize validates the filter and returns the form engines translate:
+// every leaf has its stored Field set and its values converted to string,
+// float64 or bool (dates become Unix seconds on the date field). An "in"
+// mixing value kinds becomes an "or" of one "in" per kind. A nil filter
+// normalises to nil.
+func (f *MetadataFilter) Normalize() (*MetadataFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+	conditions := 0
+	return f.normalize(0, &conditions)
+}
+
+func (f *MetadataFilter) normalize(depth int, conditions *int) (*MetadataFilter, error) {
+	if f == nil {
+		return nil, errors.New("metadata filter: empty node")
+	}
+	switch f.Op {
+	case MetadataFilterAnd, MetadataFilterOr:
+		if depth >= maxMetadataFilterDepth {
+			return nil, fmt.Errorf("metadata filter: nested deeper than %d levels", maxMetadataFilterDepth)
+		}
+		if len(f.Filters) == 0 {
+			return nil, fmt.Errorf("metadata filter: %q needs at least one filter", f.Op)
+		}
+		out := &MetadataFilter{Op: f.Op, Filters: make([]*MetadataFilter, 0, len(f.Filters))}
+		for _, child := range f.Filters {
+			normalized, err := child.normalize(depth+1, conditions)
+			if err != nil {
+				return nil, err
+			}
+			out.Filters = append(out.Filters, normalized)
+		}
+		return out, nil
+	}
+
+	*conditions++
+	if *conditions > maxMetadataFilterConditions {
+		return nil, fmt.Errorf("metadata filter: more than %d conditions", maxMetadataFilterConditions)
+	}
+	if f.Field != "" {
+		// Already normalised: converting again would move a date comparison
+		// onto the number field.
+		return f, nil
+	}
+	key := strings.TrimSpace(f.Key)
+	if key == "" || len(key) > maxMetadataKeyLength {
+		return nil, fmt.Errorf("metadata filter: %q needs a key of 1 to %d characters", f.Op, maxMetadataKeyLength)
+	}
+
+	switch {
+	case f.Op == MetadataFilterEq:
+		kind, value, err := metadataEqualityValue(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("metadata filter on %q: %w", key, err)
+		}
+		return &MetadataFilter{Op: f.Op, Key: key, Field: MetadataField(kind, key), Value: value}, nil
+
+	case f.Op == MetadataFilterIn:
+		if len(f.Values) == 0 || len(f.Values) > maxMetadataFilterValues {
+			return nil, fmt.Errorf("metadata filter on %q: \"in\" takes 1 to %d values", key, maxMetadataFilterValues)
+		}
+		var order []MetadataValueKind
+		byKind := make(map[MetadataValueKind][]any)
+		for _, raw := range f.Values {
+			kind, value, err := metadataEqualityValue(raw)
+			if err != nil {
+				return nil, fmt.Errorf("metadata filter on %q: %w", key, err)
+			}
+			if _, seen := byKind[kind]; !seen {
+				order = append(order, kind)
+			}
+			byKind[kind] = append(byKind[kind], value)
+		}
+		leaves := make([]*MetadataFilter, 0, len(order))
+		for _, kind := range order {
+			leaves = append(leaves, &MetadataFilter{
+				Op: MetadataFilterIn, Key: key, Field: MetadataField(kind, key), Values: byKind[kind],
+			})
+		}
+		if len(leaves) == 1 {
+			return leaves[0], nil
+		}
+		return &MetadataFilter{Op: MetadataFilterOr, Filters: leaves}, nil
+
+	case f.Op.IsRange():
+		switch v := f.Value.(type) {
+		case string:
+			t, ok := ParseMetadataDate(v)
+			if !ok {
+				return nil, fmt.Errorf("metadata filter on %q: %q is neither a number nor a date", key, v)
+			}
+			return &MetadataFilter{
+				Op: f.Op, Key: key, Field: MetadataField(MetadataKindDate, key), Value: float64(t.Unix()),
+			}, nil
+		default:
+			n, ok := metadataNumber(v)
+			if !ok {
+				return nil, fmt.Errorf("metadata filter on %q: %q compares a number or a date", key, f.Op)
+			}
+			return &MetadataFilter{Op: f.Op, Key: key, Field: MetadataField(MetadataKindNumber, key), Value: n}, nil
+		}
+	}
+	return nil, fmt.Errorf("metadata filter: unsupported operator %q", f.Op)
+}
+
+// metadataEqualityValue returns the stored kind and value an equality
+// comparison matches.
+func metadataEqualityValue(value any) (MetadataValueKind, any, error) {
+	switch v := value.(type) {
+	case string:
+		return MetadataKindString, v, nil
+	case bool:
+		return MetadataKindBool, v, nil
+	case nil:
+		return "", nil, errors.New("value is required")
+	}
+	if n, ok := metadataNumber(value); ok {
+		return MetadataKindNumber, n, nil
+	}
+	return "", nil, fmt.Errorf("unsupported value %v", value)
+}
+
+func metadataNumber(value any) (float64, bool) {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case float32:
+		n = float64(v)
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	default:
+		return 0, false
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// Matches evaluates a normalised filter against the fields returned by
+// IndexMetadata. Engines do the filtering natively; this is for callers
+// that check documents already loaded from the database.
+func (f *MetadataFilter) Matches(fields map[string]any) bool {
+	if f == nil {
+		return true
+	}
+	switch f.Op {
+	case MetadataFilterAnd:
+		for _, child := range f.Filters {
+			if !child.Matches(fields) {
+				return false
+			}
+		}
+		return true
+	case MetadataFilterOr:
+		for _, child := range f.Filters {
+			if child.Matches(fields) {
+				return true
+			}
+		}
+		return false
+	}
+	stored, ok := fields[f.Field]
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case MetadataFilterEq:
+		return stored == f.Value
+	case MetadataFilterIn:
+		for _, value := range f.Values {
+			if stored == value {
+				return true
+			}
+		}
+		return false
+	}
+	a, aok := stored.(float64)
+	b, bok := f.Value.(float64)
+	if !aok || !bok {
+		return false
+	}
+	switch f.Op {
+	case MetadataFilterGt:
+		return a > b
+	case MetadataFilterGte:
+		return a >= b
+	case MetadataFilterLt:
+		return a < b
+	case MetadataFilterLte:
+		return a <= b
+	}
+	return false
+}
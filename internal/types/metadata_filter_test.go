@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseMetadataFilter(t *testing.T, raw string) *MetadataFilter {
+	t.Helper()
+	var filter MetadataFilter
+	require.NoError(t, json.Unmarshal([]byte(raw), &filter))
+	return &filter
+}
+
+func TestMetadataFilterNormalizeResolvesFieldsAndValues(t *testing.T) {
+	filter := parseMetadataFilter(t, `{"op":"and","filters":[
+		{"op":"eq","key":"region","value":"EU"},
+		{"op":"gt","key":"effective_date","value":"2025-01-01"},
+		{"op":"lte","key":"version","value":3}]}`)
+
+	normalized, err := filter.Normalize()
+	require.NoError(t, err)
+	require.Len(t, normalized.Filters, 3)
+
+	eq := normalized.Filters[0]
+	require.Equal(t, MetadataField(MetadataKindString, "region"), eq.Field)
+	require.Equal(t, "EU", eq.Value)
+
+	date := normalized.Filters[1]
+	require.Equal(t, MetadataField(MetadataKindDate, "effective_date"), date.Field)
+	require.Equal(t, float64(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix()), date.Value)
+
+	number := normalized.Filters[2]
+	require.Equal(t, MetadataField(MetadataKindNumber, "version"), number.Field)
+	require.Equal(t, float64(3), number.Value)
+
+	again, err := normalized.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, normalized, again)
+}
+
+func TestMetadataFilterNormalizeSplitsMixedIn(t *testing.T) {
+	filter := parseMetadataFilter(t, `{"op":"in","key":"level","values":["high",2,"low"]}`)
+
+	normalized, err := filter.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, MetadataFilterOr, normalized.Op)
+	require.Len(t, normalized.Filters, 2)
+	require.Equal(t, []any{"high", "low"}, normalized.Filters[0].Values)
+	require.Equal(t, MetadataField(MetadataKindNumber, "level"), normalized.Filters[1].Field)
+	require.Equal(t, []any{float64(2)}, normalized.Filters[1].Values)
+}
+
+func TestMetadataFilterNormalizeRejectsInvalidFilters(t *testing.T) {
+	for name, raw := range map[string]string{
+		"missing key":     `{"op":"eq","value":"EU"}`,
+		"missing value":   `{"op":"eq","key":"region"}`,
+		"unknown op":      `{"op":"like","key":"region","value":"EU"}`,
+		"empty group":     `{"op":"and","filters":[]}`,
+		"empty in":        `{"op":"in","key":"region","values":[]}`,
+		"range on text":   `{"op":"gt","key":"region","value":"EU"}`,
+		"range on bool":   `{"op":"lt","key":"approved","value":true}`,
+		"object value":    `{"op":"eq","key":"region","value":{"a":1}}`,
+		"nested too deep": `{"op":"and","filters":[{"op":"and","filters":[{"op":"and","filters":[{"op":"and","filters":[{"op":"and","filters":[{"op":"eq","key":"a","value":1}]}]}]}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseMetadataFilter(t, raw).Normalize()
+			require.Error(t, err)
+		})
+	}
+
+	var nilFilter *MetadataFilter
+	normalized, err := nilFilter.Normalize()
+	require.NoError(t, err)
+	require.Nil(t, normalized)
+}
+
+func TestMetadataFilterMatchesIndexMetadata(t *testing.T) {
+	fields := IndexMetadata(JSON(`{"region":"EU","effective_date":"2025-03-01","version":4,"approved":true}`))
+	require.Equal(t, "EU", fields[MetadataField(MetadataKindString, "region")])
+	require.Equal(t, "2025-03-01", fields[MetadataField(MetadataKindString, "effective_date")])
+	require.Contains(t, fields, MetadataField(MetadataKindDate, "effective_date"))
+	require.Equal(t, float64(4), fields[MetadataField(MetadataKindNumber, "version")])
+	require.Equal(t, true, fields[MetadataField(MetadataKindBool, "approved")])
+
+	for raw, want := range map[string]bool{
+		`{"op":"eq","key":"region","value":"EU"}`:                           true,
+		`{"op":"eq","key":"region","value":"US"}`:                           false,
+		`{"op":"in","key":"region","values":["US","EU"]}`:                   true,
+		`{"op":"gt","key":"effective_date","value":"2025-01-01"}`:           true,
+		`{"op":"lt","key":"effective_date","value":"2025-01-01T00:00:00Z"}`: false,
+		`{"op":"gte","key":"version","value":4}`:                            true,
+		`{"op":"eq","key":"approved","value":true}`:                         true,
+		`{"op":"eq","key":"missing","value":"x"}`:                           false,
+		`{"op":"or","filters":[{"op":"eq","key":"region","value":"US"},` +
+			`{"op":"eq","key":"version","value":4}]}`: true,
+	} {
+		normalized, err := parseMetadataFilter(t, raw).Normalize()
+		require.NoError(t, err, raw)
+		require.Equal(t, want, normalized.Matches(fields), raw)
+	}
+}
+
+func TestIndexMetadataIgnoresEmptyOrInvalidMetadata(t *testing.T) {
+	require.Nil(t, IndexMetadata(nil))
+	require.Nil(t, IndexMetadata(JSON(`{}`)))
+	require.Nil(t, IndexMetadata(JSON(`not json`)))
+	require.Nil(t, IndexMetadata(JSON(`{"tags":["a","b"]}`)))
+	require.Nil(t, (*Knowledge)(nil).IndexMetadata())
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
@@ -62,11 +63,61 @@ type EmbeddingParameters struct {
 	SupportsDimensionOverride bool `yaml:"supports_dimension_override" json:"supports_dimension_override"`
 }
 
+// RerankScoreCalibration names how a rerank model's raw relevance scores are
+// mapped onto [0, 1]
+type RerankScoreCalibration string
+
+const (
+	// RerankScoreCalibrationNone keeps scores as the provider returns them
+	RerankScoreCalibrationNone RerankScoreCalibration = "none"
+	// RerankScoreCalibrationSigmoid maps logits through 1 / (1 + e^-(scale*x + offset))
+	RerankScoreCalibrationSigmoid RerankScoreCalibration = "sigmoid"
+	// RerankScoreCalibrationLinear maps [min, max] linearly onto [0, 1] and clamps
+	RerankScoreCalibrationLinear RerankScoreCalibration = "linear"
+)
+
+// RerankParameters represents the rerank parameters for a model. Providers
+// return scores on very different ranges (probabilities, raw logits, 0-10);
+// calibrating them onto [0, 1] keeps RerankThreshold portable across models.
+type RerankParameters struct {
+	ScoreCalibration RerankScoreCalibration `yaml:"score_calibration" json:"score_calibration,omitempty"`
+	// ScoreScale and ScoreOffset parameterize the sigmoid; a zero scale means 1
+	ScoreScale  float64 `yaml:"score_scale"  json:"score_scale,omitempty"`
+	ScoreOffset float64 `yaml:"score_offset" json:"score_offset,omitempty"`
+	// ScoreMin and ScoreMax bound the raw range of the linear calibration
+	ScoreMin float64 `yaml:"score_min" json:"score_min,omitempty"`
+	ScoreMax float64 `yaml:"score_max" json:"score_max,omitempty"`
+}
+
+// Validate checks that the calibration is known and its parameters usable
+func (p *RerankParameters) Validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.ScoreCalibration {
+	case "", RerankScoreCalibrationNone:
+		return nil
+	case RerankScoreCalibrationSigmoid:
+		if p.ScoreScale < 0 {
+			return fmt.Errorf("score_scale must not be negative")
+		}
+		return nil
+	case RerankScoreCalibrationLinear:
+		if p.ScoreMax <= p.ScoreMin {
+			return fmt.Errorf("score_max must be greater than score_min")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown score_calibration %q", p.ScoreCalibration)
+	}
+}
+
 type ModelParameters struct {
 	BaseURL             string              `yaml:"base_url"             json:"base_url"`
 	APIKey              string              `yaml:"api_key"              json:"api_key"`
 	InterfaceType       string              `yaml:"interface_type"       json:"interface_type"`
 	EmbeddingParameters EmbeddingParameters `yaml:"embedding_parameters" json:"embedding_parameters"`
+	RerankParameters    *RerankParameters   `yaml:"rerank_parameters"    json:"rerank_parameters,omitempty"`
 	ParameterSize       string              `yaml:"parameter_size"       json:"parameter_size"` // Ollama model parameter size (e.g., "7B", "13B", "70B")
 	Provider            string              `yaml:"provider"             json:"provider"`       // Provider identifier: openai, aliyun, zhipu, generic
 	ExtraConfig         map[string]string   `yaml:"extra_config"         json:"extra_config"`   // Provider-specific configuration
@@ -13,12 +13,14 @@ type QARequest struct {
 	KnowledgeBaseIDs    []string           // Knowledge base IDs to search (from request + @mentions)
 	KnowledgeIDs        []string           // Specific knowledge (file) IDs to search
 	TagScopes           []TagScope         // Tag-constrained KB scopes from @mentions
+	MetadataFilter      *MetadataFilter    // Normalised custom metadata filter applied to retrieval
 	MCPServiceIDs       []string           // Per-request MCP service IDs from @mentions
 	SkillNames          []string           // Per-request preloaded skill names from @mentions
 	ImageURLs           []string           // Image URLs for multimodal input
 	ImageDescription    string             // VLM-generated image description (fallback for non-vision models)
 	UserMessageID       string             // Created user message ID
 	WebSearchEnabled    bool               // Whether web search is enabled for this request
+	RetrievalMode       string             // RetrievalModeLocal (chunks) or RetrievalModeGlobal (graph community summaries)
 	QuotedContext       string             // Quoted message content from IM quote-reply (appended at LLM prompt stage, not used for retrieval)
 	Attachments         MessageAttachments // File attachments (processed and ready for prompt injection)
 }
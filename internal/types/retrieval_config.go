@@ -25,16 +25,10 @@ type RetrievalConfig struct {
 	// RerankModelID is the ID of the rerank model to use (required for search)
 	RerankModelID string `json:"rerank_model_id"`
 
-	// RRFK is the smoothing constant of Reciprocal Rank Fusion. Larger values
-	// flatten the curve, reducing the bias towards top-1 results.
-	// Default: 60. Sensible range: 30..100 depending on corpus size.
-	RRFK int `json:"rrf_k,omitempty"`
-	// RRFVectorWeight is the weight applied to the vector retriever inside RRF.
-	// RRFVectorWeight + RRFKeywordWeight should usually sum to 1.0 but the math
-	// works for any positive weights. Default: 0.7.
-	RRFVectorWeight float64 `json:"rrf_vector_weight,omitempty"`
-	// RRFKeywordWeight is the keyword counterpart. Default: 0.3.
-	RRFKeywordWeight float64 `json:"rrf_keyword_weight,omitempty"`
+	// FusionConfig selects how vector and keyword results are merged. Its
+	// fields are inlined into the JSON document; a knowledge base can
+	// override them with KnowledgeBase.FusionConfig.
+	FusionConfig
 }
 
 // DefaultRetrievalTopK is the retrieval depth used when a caller supplies no
@@ -83,29 +77,12 @@ func (c *RetrievalConfig) GetEffectiveRerankThreshold() float64 {
 	return c.RerankThreshold
 }
 
-// GetEffectiveRRFK returns the RRF smoothing constant with a fallback default.
-func (c *RetrievalConfig) GetEffectiveRRFK() int {
-	if c == nil || c.RRFK <= 0 {
-		return 60
-	}
-	return c.RRFK
-}
-
-// GetEffectiveRRFWeights returns vector / keyword weights with sensible defaults.
-// When neither weight is set explicitly, returns 0.7 / 0.3.
-func (c *RetrievalConfig) GetEffectiveRRFWeights() (vector, keyword float64) {
-	if c == nil || (c.RRFVectorWeight == 0 && c.RRFKeywordWeight == 0) {
-		return 0.7, 0.3
-	}
-	v := c.RRFVectorWeight
-	k := c.RRFKeywordWeight
-	if v <= 0 {
-		v = 0.7
-	}
-	if k <= 0 {
-		k = 0.3
+// GetFusionConfig returns the tenant-wide fusion settings; nil-safe.
+func (c *RetrievalConfig) GetFusionConfig() *FusionConfig {
+	if c == nil {
+		return nil
 	}
-	return v, k
+	return &c.FusionConfig
 }
 
 // Value implements the driver.Valuer interface for database serialization
@@ -48,6 +48,10 @@ type RetrieveParams struct {
 	KnowledgeIDs []string
 	// Tag IDs for filtering (used for FAQ priority filtering)
 	TagIDs []string
+	// MetadataFilter restricts results to documents whose custom metadata
+	// matches. It is normalised (see MetadataFilter.Normalize); engines that
+	// cannot translate it must fail rather than ignore it.
+	MetadataFilter *MetadataFilter
 	// Excluded knowledge IDs
 	ExcludeKnowledgeIDs []string
 	// Excluded chunk IDs
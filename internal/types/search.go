@@ -44,6 +44,10 @@ type SearchTarget struct {
 	// user-selected scope. The reranker still orders candidates, but vector and
 	// keyword thresholds cannot erase the whole explicit scope before reranking.
 	DisableRecallThresholds bool `json:"disable_recall_thresholds,omitempty"`
+	// ExcludeKnowledgeIDs lists the documents of the knowledge base the caller
+	// may not read under document access lists. It applies to every other
+	// constraint of the target.
+	ExcludeKnowledgeIDs []string `json:"exclude_knowledge_ids,omitempty"`
 }
 
 // SearchTargets is a list of search targets, pre-computed at request entry point
@@ -249,6 +253,12 @@ type SearchParams struct {
 	// in processSearchResults. Used by the chat pipeline where context assembly
 	// is handled separately in the merge stage.
 	SkipContextEnrichment bool `json:"skip_context_enrichment,omitempty"`
+	// MetadataFilter restricts results to documents whose custom metadata
+	// matches the expression. It is pushed down to every retriever engine.
+	MetadataFilter *MetadataFilter `json:"metadata_filter,omitempty"`
+	// ExcludeKnowledgeIDs drops documents from retrieval. HybridSearch adds
+	// the documents the caller may not read under document access lists.
+	ExcludeKnowledgeIDs []string `json:"-"`
 }
 
 // Value implements the driver.Valuer interface, used to convert SearchResult to database value
@@ -46,6 +46,12 @@ const (
 	// the enrichment pool because it is a background LLM call whose latency
 	// nobody is waiting on.
 	QueueMemory = "memory"
+	// QueueAgentTrigger carries scheduled and event-driven agent runs. Like
+	// memory distillation nobody waits on them interactively.
+	QueueAgentTrigger = "agent_trigger"
+	// QueueWebhook carries outbound webhook deliveries. They are short HTTP
+	// calls, so the maintenance pool weights them above bulk maintenance.
+	QueueWebhook = "webhook"
 )
 
 // QueueDefinition is the single source of truth for queue topology. Worker
@@ -76,13 +82,16 @@ var queueDefinitions = []QueueDefinition{
 		TypeSummaryGeneration, TypeDataTableSummary, TypeKnowledgeAutoTag,
 	}},
 	{Name: QueueMultimodal, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeImageMultimodal}},
-	{Name: QueueGraph, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeChunkExtract}},
+	{Name: QueueGraph, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeChunkExtract, TypeGraphCommunityBuild}},
 	{Name: QueueQuestion, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeQuestionGeneration}},
 	{Name: QueueMemory, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeMemoryExtract}},
+	{Name: QueueAgentTrigger, Pool: WorkerPoolEnrichment, Weight: 1, SharedWeight: 1, TaskTypes: []string{TypeAgentTriggerRun}},
 	{Name: QueueSync, Pool: WorkerPoolMaintenance, Weight: 2, TaskTypes: []string{TypeDataSourceSync}},
+	{Name: QueueWebhook, Pool: WorkerPoolMaintenance, Weight: 2, TaskTypes: []string{TypeWebhookDeliver}},
 	{Name: QueueMaintenance, Pool: WorkerPoolMaintenance, Weight: 1, TaskTypes: []string{
 		TypeFAQImport, TypeKBClone, TypeIndexDelete, TypeKBDelete,
-		TypeKnowledgeListDelete, TypeKnowledgeListReparse, TypeKnowledgeMove,
+		TypeKnowledgeListDelete, TypeKnowledgeListReparse, TypeKnowledgeMove, TypeKBReembed,
+		TypeKBVectorStoreMigrate,
 	}},
 	{Name: QueueWiki, Pool: WorkerPoolWiki, Weight: 1, TaskTypes: []string{TypeWikiIngest, TypeWikiFinalize}},
 }
@@ -254,6 +263,16 @@ const (
 	TypeTemporaryDocumentProcess = "temporary_document:process" // 会话临时文档解析任务
 	// TypeMemoryExtract 长期记忆抽取任务（会话轮次防抖后异步执行）
 	TypeMemoryExtract = "memory:extract"
+	// TypeGraphCommunityBuild 知识图谱社区检测与社区摘要任务（按知识库防抖）
+	TypeGraphCommunityBuild = "graph:community_build"
+	// TypeKBReembed 更换 Embedding 模型后的知识库重新向量化任务（影子索引 + 原子切换）
+	TypeKBReembed = "kb:reembed"
+	// TypeKBVectorStoreMigrate 知识库向量数据跨向量存储迁移任务（复用已存向量，可断点续传）
+	TypeKBVectorStoreMigrate = "kb:vector_store_migrate"
+	// TypeAgentTriggerRun 定时或事件触发的智能体运行任务
+	TypeAgentTriggerRun = "agent_trigger:run"
+	// TypeWebhookDeliver 租户 webhook 事件投递任务（失败按 asynq 重试）
+	TypeWebhookDeliver = "webhook:deliver"
 )
 
 // MemoryExtractPayload carries everything the background distillation task
@@ -277,6 +296,14 @@ type MemoryExtractPayload struct {
 	Language    string `json:"language,omitempty"`
 }
 
+// GraphCommunityBuildPayload asks the worker to re-cluster a knowledge
+// base's graph and regenerate its community summaries.
+type GraphCommunityBuildPayload struct {
+	TracingContext
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+}
+
 // ExtractChunkPayload represents the extract chunk task payload
 type ExtractChunkPayload struct {
 	TracingContext
@@ -409,6 +436,28 @@ type KBClonePayload struct {
 	Initiator TaskInitiator `json:"initiator,omitempty"`
 }
 
+// KBReembedPayload asks the worker to run the embedding migration recorded
+// on the knowledge base. The target model and shadow index label live on the
+// knowledge base row, so TaskID only identifies which migration the task was
+// enqueued for; a task for a superseded migration exits without work.
+type KBReembedPayload struct {
+	TracingContext
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	TaskID          string `json:"task_id"`
+}
+
+// KBVectorStoreMigratePayload asks the worker to run the vector store
+// migration recorded on the knowledge base. The target store and the resume
+// cursor live on the knowledge base row; TaskID only identifies which
+// migration the task was enqueued for.
+type KBVectorStoreMigratePayload struct {
+	TracingContext
+	TenantID        uint64 `json:"tenant_id"`
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	TaskID          string `json:"task_id"`
+}
+
 // IndexDeletePayload represents the index delete task payload
 type IndexDeletePayload struct {
 	TracingContext
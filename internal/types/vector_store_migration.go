@@ -0,0 +1,125 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// VectorStoreMigrationStatus is the lifecycle state of a vector store migration.
+type VectorStoreMigrationStatus string
+
+const (
+	// VectorStoreMigrationPending: the job is queued; queries use the source store.
+	VectorStoreMigrationPending VectorStoreMigrationStatus = "pending"
+	// VectorStoreMigrationRunning: entries are being copied into the target
+	// store; queries still use the source store.
+	VectorStoreMigrationRunning VectorStoreMigrationStatus = "running"
+	// VectorStoreMigrationCompleted: the knowledge base has been bound to the
+	// target store and its entries in the source store have been dropped.
+	VectorStoreMigrationCompleted VectorStoreMigrationStatus = "completed"
+	// VectorStoreMigrationFailed: the job gave up; the knowledge base is still
+	// bound to the source store and the partial copy has been dropped.
+	VectorStoreMigrationFailed VectorStoreMigrationStatus = "failed"
+)
+
+// VectorStoreMigration records a background copy of a knowledge base's index
+// entries, with their stored embeddings, from one vector store into another.
+// It is stored on the knowledge base row so the state and the resume cursor
+// survive restarts.
+type VectorStoreMigration struct {
+	// TaskID identifies the asynq task that runs this migration.
+	TaskID string `json:"task_id"`
+	// SourceStoreID is the vector store the knowledge base was bound to when
+	// the migration started; empty means the environment default store.
+	SourceStoreID string `json:"source_vector_store_id"`
+	// TargetStoreID is the vector store being migrated to.
+	TargetStoreID string `json:"target_vector_store_id"`
+	// Status is the current lifecycle state.
+	Status VectorStoreMigrationStatus `json:"status"`
+	// Total is the number of index entries in the source store when the copy
+	// started.
+	Total int64 `json:"total"`
+	// Copied is the number of index entries copied so far.
+	Copied int64 `json:"copied"`
+	// SourceCount and TargetCount are the entry counts compared by the final
+	// verification.
+	SourceCount int64 `json:"source_count,omitempty"`
+	TargetCount int64 `json:"target_count,omitempty"`
+	// Cursor is the export position of the last copied page; a retried task
+	// resumes from it.
+	Cursor string `json:"-"`
+	// Error holds the last failure message, if any.
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the worker began copying.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// FinishedAt is when the migration completed or failed.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// UpdatedAt is the time of the last progress update.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Active reports whether the migration is still queued or running.
+func (m *VectorStoreMigration) Active() bool {
+	return m != nil && (m.Status == VectorStoreMigrationPending || m.Status == VectorStoreMigrationRunning)
+}
+
+// Progress returns the completion percentage (0-100).
+func (m *VectorStoreMigration) Progress() int {
+	if m == nil {
+		return 0
+	}
+	if m.Status == VectorStoreMigrationCompleted {
+		return 100
+	}
+	if m.Total <= 0 {
+		return 0
+	}
+	return int(min(m.Copied*100/m.Total, 99))
+}
+
+// MarshalJSON adds the computed progress percentage.
+func (m VectorStoreMigration) MarshalJSON() ([]byte, error) {
+	type alias VectorStoreMigration
+	return json.Marshal(struct {
+		alias
+		Progress int `json:"progress"`
+	}{alias: alias(m), Progress: m.Progress()})
+}
+
+// Value implements driver.Valuer. Unlike the API encoding, the column keeps
+// Cursor so a retried task can resume the copy.
+func (m VectorStoreMigration) Value() (driver.Value, error) {
+	type alias VectorStoreMigration
+	return json.Marshal(struct {
+		alias
+		Cursor string `json:"cursor"`
+	}{alias: alias(m), Cursor: m.Cursor})
+}
+
+// Scan implements sql.Scanner.
+func (m *VectorStoreMigration) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return nil
+	}
+	type alias VectorStoreMigration
+	var row struct {
+		alias
+		Cursor string `json:"cursor"`
+	}
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	*m = VectorStoreMigration(row.alias)
+	m.Cursor = row.Cursor
+	return nil
+}
@@ -0,0 +1,185 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEventType names a workspace event a webhook subscription can receive
+type WebhookEventType string
+
+const (
+	// WebhookEventKnowledgeCreated fires when a knowledge item is added and
+	// queued for parsing (or saved, for manual knowledge)
+	WebhookEventKnowledgeCreated WebhookEventType = "knowledge.created"
+	// WebhookEventKnowledgeParsed fires when a knowledge item finishes
+	// processing, including its enrichment subtasks
+	WebhookEventKnowledgeParsed WebhookEventType = "knowledge.parsed"
+	// WebhookEventKnowledgeFailed fires when parsing a knowledge item fails
+	// for good, after its retries
+	WebhookEventKnowledgeFailed WebhookEventType = "knowledge.failed"
+	// WebhookEventKnowledgeDeleted fires when a knowledge item is deleted
+	WebhookEventKnowledgeDeleted WebhookEventType = "knowledge.deleted"
+	// WebhookEventKnowledgeBaseChanged fires when a knowledge base is
+	// created, updated or deleted; data.action tells which
+	WebhookEventKnowledgeBaseChanged WebhookEventType = "knowledge_base.changed"
+	// WebhookEventDataSourceSyncFinished fires when a datasource sync ends,
+	// whatever its outcome
+	WebhookEventDataSourceSyncFinished WebhookEventType = "datasource.sync_finished"
+	// WebhookEventWikiPageRevised fires when a wiki page is created or its
+	// content changes, i.e. when it gets a new version
+	WebhookEventWikiPageRevised WebhookEventType = "wiki.page_revised"
+	// WebhookEventEvaluationCompleted fires when an evaluation run ends,
+	// successfully or not
+	WebhookEventEvaluationCompleted WebhookEventType = "evaluation.completed"
+)
+
+// WebhookEventTypes lists every event type a subscription can select
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventKnowledgeCreated,
+	WebhookEventKnowledgeParsed,
+	WebhookEventKnowledgeFailed,
+	WebhookEventKnowledgeDeleted,
+	WebhookEventKnowledgeBaseChanged,
+	WebhookEventDataSourceSyncFinished,
+	WebhookEventWikiPageRevised,
+	WebhookEventEvaluationCompleted,
+}
+
+// IsValid reports whether t is a known webhook event type
+func (t WebhookEventType) IsValid() bool {
+	for _, known := range WebhookEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook delivery statuses. A delivery is retrying between a failed attempt
+// and the next one, and failed once its retries are used up.
+const (
+	WebhookDeliveryPending  = "pending"
+	WebhookDeliveryRetrying = "retrying"
+	WebhookDeliverySuccess  = "success"
+	WebhookDeliveryFailed   = "failed"
+)
+
+// WebhookSubscription POSTs workspace events of a tenant to a URL. Bodies are
+// signed with HMAC-SHA256 in the X-WeKnora-Signature header when a secret is
+// set, like embed channel webhooks.
+type WebhookSubscription struct {
+	ID       string `json:"id"        gorm:"type:varchar(36);primaryKey"`
+	TenantID uint64 `json:"tenant_id" gorm:"index"`
+	Name     string `json:"name"      gorm:"type:varchar(255)"`
+	URL      string `json:"url"       gorm:"type:varchar(1024)"`
+	// Secret signs the body; never returned by the API
+	Secret    string `json:"-"          gorm:"type:varchar(128)"`
+	HasSecret bool   `json:"has_secret" gorm:"-"`
+	// EventTypes selects the events to receive; empty means every event
+	EventTypes StringArray `json:"event_types" gorm:"type:json"`
+	// KnowledgeBaseIDs restricts the subscription to events of these
+	// knowledge bases; empty means every event of the tenant
+	KnowledgeBaseIDs StringArray `json:"knowledge_base_ids" gorm:"type:json"`
+	Enabled          bool        `json:"enabled"`
+
+	CreatedBy string         `json:"created_by" gorm:"type:varchar(36)"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string { return "webhook_subscriptions" }
+
+// Validate checks the name, URL presence and event types. The URL itself is
+// checked against SSRF rules by the service.
+func (s *WebhookSubscription) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(s.URL) == "" {
+		return fmt.Errorf("url is required")
+	}
+	for _, eventType := range s.EventTypes {
+		if !WebhookEventType(eventType).IsValid() {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an event of eventType about kbID goes to the
+// subscription. Events without a knowledge base only pass an empty
+// knowledge base filter.
+func (s *WebhookSubscription) Matches(eventType WebhookEventType, kbID string) bool {
+	if len(s.EventTypes) > 0 {
+		selected := false
+		for _, t := range s.EventTypes {
+			if WebhookEventType(t) == eventType {
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			return false
+		}
+	}
+	if len(s.KnowledgeBaseIDs) == 0 {
+		return true
+	}
+	for _, id := range s.KnowledgeBaseIDs {
+		if id == kbID {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the JSON body POSTed to a subscription. ID identifies the
+// event, so receivers can drop the duplicates that retries and replays send.
+type WebhookEvent struct {
+	ID              string           `json:"id"`
+	Type            WebhookEventType `json:"type"`
+	TenantID        uint64           `json:"tenant_id"`
+	KnowledgeBaseID string           `json:"knowledge_base_id,omitempty"`
+	OccurredAt      time.Time        `json:"occurred_at"`
+	Data            any              `json:"data"`
+}
+
+// WebhookDelivery records the delivery of one event to one subscription.
+// Payload is the exact body sent, so a replay sends the same bytes.
+type WebhookDelivery struct {
+	ID             string           `json:"id"              gorm:"type:varchar(36);primaryKey"`
+	TenantID       uint64           `json:"tenant_id"       gorm:"index"`
+	SubscriptionID string           `json:"subscription_id" gorm:"type:varchar(36);index"`
+	EventID        string           `json:"event_id"        gorm:"type:varchar(36)"`
+	EventType      WebhookEventType `json:"event_type"      gorm:"type:varchar(50)"`
+	Payload        JSON             `json:"payload"         gorm:"type:json"`
+
+	Status   string `json:"status"   gorm:"type:varchar(20)"`
+	Attempts int    `json:"attempts"`
+	// ResponseStatus and ResponseBody are from the latest attempt; the body
+	// is truncated
+	ResponseStatus int    `json:"response_status,omitempty"`
+	ResponseBody   string `json:"response_body,omitempty" gorm:"type:text"`
+	Error          string `json:"error,omitempty"         gorm:"type:text"`
+	// ReplayOf is the delivery this one replays
+	ReplayOf string `json:"replay_of,omitempty" gorm:"type:varchar(36)"`
+
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string { return "webhook_deliveries" }
+
+// WebhookDeliveryPayload is the asynq payload of one delivery
+type WebhookDeliveryPayload struct {
+	TenantID   uint64 `json:"tenant_id"`
+	DeliveryID string `json:"delivery_id"`
+}
@@ -156,6 +156,8 @@ type SearchScope struct {
 	KnowledgeBaseID string
 	KnowledgeIDs    []string
 	TagIDs          []string
+	// ExcludeKnowledgeIDs are documents of the KB the caller may not read
+	ExcludeKnowledgeIDs []string
 }
 
 // ParseSQL parses a SQL statement using pg_query_go and extracts table names, select fields, and where fields
@@ -1136,9 +1138,10 @@ func buildKnowledgeBaseScopeCondition(alias string, scopes []SearchScope) string
 	return fmt.Sprintf("%s.id IN (%s)", alias, strings.Join(quoteStringSlice(kbIDs), ", "))
 }
 
-// buildScopeClause renders one scope. A scope's document whitelist and tag
-// filter are ANDed: each is an independent narrowing of the same KB, so
-// applying only one of them would admit rows the other excludes. Scopes are
+// buildScopeClause renders one scope. A scope's document whitelist, tag
+// filter and excluded documents are ANDed: each is an independent narrowing
+// of the same KB, so applying only some of them would admit rows the others
+// exclude. Scopes are
 // ORed against each other by joinOrClauses because they are alternatives.
 func buildScopeClause(alias, knowledgeIDColumn string, scope SearchScope) string {
 	if scope.KnowledgeBaseID == "" {
@@ -1159,6 +1162,12 @@ func buildScopeClause(alias, knowledgeIDColumn string, scope SearchScope) string
 			alias, knowledgeIDColumn, strings.Join(quoteStringSlice(scope.TagIDs), ", "),
 		))
 	}
+	if len(scope.ExcludeKnowledgeIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"%s.%s NOT IN (%s)",
+			alias, knowledgeIDColumn, strings.Join(quoteStringSlice(scope.ExcludeKnowledgeIDs), ", "),
+		))
+	}
 	if len(conditions) == 1 {
 		return conditions[0]
 	}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// EncodeVector packs an embedding as little-endian float32s, the format the
+// embedding cache and the answer cache store vectors in.
+func EncodeVector(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	return data
+}
+
+// DecodeVector unpacks a vector written by EncodeVector. Empty or malformed
+// data decodes to nil, which callers treat as a miss.
+func DecodeVector(data []byte) []float32 {
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "testing"
+
+func TestVectorEncodingRoundTrips(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3e-8}
+	decoded := DecodeVector(EncodeVector(vector))
+	if len(decoded) != len(vector) {
+		t.Fatalf("decoded %v, want %v", decoded, vector)
+	}
+	for i := range vector {
+		if decoded[i] != vector[i] {
+			t.Fatalf("decoded %v, want %v", decoded, vector)
+		}
+	}
+	if DecodeVector([]byte{1, 2, 3}) != nil {
+		t.Fatal("malformed data decoded to a vector")
+	}
+	if DecodeVector(nil) != nil {
+		t.Fatal("empty data decoded to a vector")
+	}
+}
@@ -0,0 +1,71 @@
+-- Persisted evaluation runs and uploaded datasets (Lite).
+-- Mirrors migrations/versioned/000085. Row ids are generated in Go.
+
+CREATE TABLE IF NOT EXISTS evaluation_tasks (
+    id VARCHAR(128) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    dataset_id VARCHAR(64) NOT NULL,
+    knowledge_base_id VARCHAR(36),
+    chat_model_id VARCHAR(64),
+    rerank_model_id VARCHAR(64),
+    start_time DATETIME NOT NULL,
+    end_time DATETIME,
+    status INTEGER NOT NULL DEFAULT 0,
+    err_msg TEXT,
+    total INTEGER NOT NULL DEFAULT 0,
+    finished INTEGER NOT NULL DEFAULT 0,
+    metric TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_tasks_tenant
+    ON evaluation_tasks (tenant_id, start_time DESC);
+
+CREATE TABLE IF NOT EXISTS evaluation_results (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id VARCHAR(128) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    question_index INTEGER NOT NULL,
+    qid INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    expected TEXT,
+    generated TEXT,
+    retrieval_gt TEXT,
+    retrieval_ids TEXT,
+    metric TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_results_task
+    ON evaluation_results (task_id, question_index);
+
+CREATE TABLE IF NOT EXISTS evaluation_datasets (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    format VARCHAR(16) NOT NULL,
+    qa_count INTEGER NOT NULL DEFAULT 0,
+    passage_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_datasets_tenant
+    ON evaluation_datasets (tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS evaluation_dataset_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dataset_id VARCHAR(36) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    qid INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    aid INTEGER NOT NULL DEFAULT 0,
+    answer TEXT,
+    pids TEXT,
+    passages TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_dataset_items_dataset
+    ON evaluation_dataset_items (dataset_id, qid);
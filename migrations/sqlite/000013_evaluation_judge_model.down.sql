@@ -0,0 +1 @@
+ALTER TABLE evaluation_tasks DROP COLUMN judge_model_id;
@@ -0,0 +1,4 @@
+-- Mirrors versioned migration 000086_evaluation_judge_model:
+-- judge model for LLM-as-judge evaluation metrics.
+
+ALTER TABLE evaluation_tasks ADD COLUMN judge_model_id VARCHAR(64);
@@ -0,0 +1,35 @@
+-- Mirrors versioned migration 000087_relational_graph_store:
+-- relational knowledge graph store (GRAPH_DRIVER=database).
+
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    name TEXT NOT NULL,
+    chunk_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_graph_nodes_observation
+    ON graph_nodes (knowledge_id, name, chunk_id);
+CREATE INDEX IF NOT EXISTS idx_graph_nodes_kb_name
+    ON graph_nodes (knowledge_base_id, name);
+
+CREATE TABLE IF NOT EXISTS graph_relations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    type TEXT NOT NULL,
+    chunk_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_graph_relations_observation
+    ON graph_relations (knowledge_id, source, type, target, chunk_id);
+CREATE INDEX IF NOT EXISTS idx_graph_relations_kb_source
+    ON graph_relations (knowledge_base_id, source);
+CREATE INDEX IF NOT EXISTS idx_graph_relations_kb_target
+    ON graph_relations (knowledge_base_id, target);
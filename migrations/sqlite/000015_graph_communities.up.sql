@@ -0,0 +1,20 @@
+-- Mirrors versioned migration 000088_graph_communities:
+-- knowledge graph community summaries.
+
+CREATE TABLE IF NOT EXISTS graph_communities (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    title VARCHAR(512) NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    entities TEXT DEFAULT '[]',
+    relation_count INTEGER NOT NULL DEFAULT 0,
+    chunk_ids TEXT DEFAULT '[]',
+    rank REAL NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_graph_communities_kb
+    ON graph_communities (knowledge_base_id);
+CREATE INDEX IF NOT EXISTS idx_graph_communities_tenant_id
+    ON graph_communities (tenant_id);
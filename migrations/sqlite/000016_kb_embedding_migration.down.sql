@@ -0,0 +1,2 @@
+ALTER TABLE knowledge_bases DROP COLUMN embedding_migration;
+ALTER TABLE knowledge_bases DROP COLUMN embedding_index_id;
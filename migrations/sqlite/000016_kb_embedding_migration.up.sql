@@ -0,0 +1,7 @@
+-- Mirrors versioned migration 000089_kb_embedding_migration:
+-- shadow index label and progress of a background re-embedding job.
+-- lite_embeddings is created by the SQLite retriever itself, which scopes its
+-- source uniqueness by knowledge_base_id on startup.
+
+ALTER TABLE knowledge_bases ADD COLUMN embedding_index_id VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_bases ADD COLUMN embedding_migration TEXT;
@@ -0,0 +1 @@
+ALTER TABLE knowledge_bases DROP COLUMN vector_store_migration;
@@ -0,0 +1,4 @@
+-- Mirrors versioned migration 000090_kb_vector_store_migration:
+-- progress and resume cursor of a background vector store migration.
+
+ALTER TABLE knowledge_bases ADD COLUMN vector_store_migration TEXT;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_chunks_kb_deleted;
+DROP INDEX IF EXISTS idx_chunks_kb_updated;
+DROP TABLE IF EXISTS answer_cache_entries;
@@ -0,0 +1,28 @@
+-- Mirrors versioned migration 000092_answer_cache:
+-- semantic answer cache for knowledge QA.
+
+CREATE TABLE IF NOT EXISTS answer_cache_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    scope_hash VARCHAR(64) NOT NULL,
+    knowledge_base_ids TEXT DEFAULT '[]',
+    query TEXT NOT NULL,
+    query_embedding BLOB,
+    answer TEXT NOT NULL,
+    knowledge_references TEXT,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    last_hit_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_answer_cache_entries_scope
+    ON answer_cache_entries (tenant_id, agent_id, scope_hash, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_answer_cache_entries_expires
+    ON answer_cache_entries (expires_at);
+
+CREATE INDEX IF NOT EXISTS idx_chunks_kb_updated
+    ON chunks (knowledge_base_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_chunks_kb_deleted
+    ON chunks (knowledge_base_id, deleted_at) WHERE deleted_at IS NOT NULL;
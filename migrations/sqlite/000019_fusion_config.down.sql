@@ -0,0 +1,2 @@
+ALTER TABLE evaluation_tasks DROP COLUMN fusion_config;
+ALTER TABLE knowledge_bases DROP COLUMN fusion_config;
@@ -0,0 +1,5 @@
+-- Mirrors versioned migration 000093_fusion_config:
+-- per knowledge base fusion override and the fusion an evaluation run used.
+
+ALTER TABLE knowledge_bases ADD COLUMN fusion_config TEXT;
+ALTER TABLE evaluation_tasks ADD COLUMN fusion_config TEXT;
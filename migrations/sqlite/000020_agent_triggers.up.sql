@@ -0,0 +1,61 @@
+-- Mirrors versioned migration 000094_agent_triggers:
+-- scheduled and event-driven agent triggers and their runs.
+
+CREATE TABLE IF NOT EXISTS agent_triggers (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    trigger_type VARCHAR(20) NOT NULL,
+    schedule VARCHAR(100) NOT NULL DEFAULT '',
+    event_type VARCHAR(50) NOT NULL DEFAULT '',
+    knowledge_base_ids TEXT DEFAULT '[]',
+    cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+    prompt TEXT NOT NULL,
+    delivery_type VARCHAR(20) NOT NULL DEFAULT 'none',
+    delivery_im_channel_id VARCHAR(36) NOT NULL DEFAULT '',
+    delivery_im_chat_id VARCHAR(255) NOT NULL DEFAULT '',
+    delivery_im_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    delivery_webhook_url VARCHAR(1024) NOT NULL DEFAULT '',
+    delivery_webhook_secret VARCHAR(128) NOT NULL DEFAULT '',
+    delivery_knowledge_base_id VARCHAR(36) NOT NULL DEFAULT '',
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    last_run_at DATETIME,
+    last_run_status VARCHAR(20) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_agent
+    ON agent_triggers (tenant_id, agent_id);
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_event
+    ON agent_triggers (tenant_id, event_type) WHERE deleted_at IS NULL AND enabled;
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_deleted_at
+    ON agent_triggers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS agent_trigger_runs (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    trigger_id VARCHAR(36) NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    event TEXT,
+    query TEXT NOT NULL DEFAULT '',
+    session_id VARCHAR(36) NOT NULL DEFAULT '',
+    answer TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    error_message TEXT NOT NULL DEFAULT '',
+    delivery_type VARCHAR(20) NOT NULL DEFAULT 'none',
+    delivery_error TEXT NOT NULL DEFAULT '',
+    knowledge_id VARCHAR(36) NOT NULL DEFAULT '',
+    started_at DATETIME,
+    finished_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_trigger_runs_trigger
+    ON agent_trigger_runs (tenant_id, trigger_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_trigger_runs_active
+    ON agent_trigger_runs (trigger_id, status, created_at);
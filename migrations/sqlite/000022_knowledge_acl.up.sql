@@ -0,0 +1,52 @@
+-- Mirrors versioned migration 000096_knowledge_acl:
+-- document and folder access lists, and user groups.
+
+CREATE TABLE IF NOT EXISTS knowledge_acl_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id VARCHAR(1024) NOT NULL,
+    principal_type VARCHAR(16) NOT NULL,
+    principal_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_tenant_id
+    ON knowledge_acl_entries (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_knowledge_base_id
+    ON knowledge_acl_entries (knowledge_base_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_target
+    ON knowledge_acl_entries (knowledge_base_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_principal
+    ON knowledge_acl_entries (tenant_id, principal_type, principal_id);
+
+CREATE TABLE IF NOT EXISTS user_groups (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_groups_tenant_id
+    ON user_groups (tenant_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_groups_deleted_at
+    ON user_groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_group_members (
+    group_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_group_members_user_id
+    ON user_group_members (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_group_members_tenant_id
+    ON user_group_members (tenant_id);
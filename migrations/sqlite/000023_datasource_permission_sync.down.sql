@@ -0,0 +1,4 @@
+DELETE FROM knowledge_acl_entries WHERE data_source_id <> '';
+DROP INDEX IF EXISTS idx_knowledge_acl_entries_data_source_id;
+ALTER TABLE knowledge_acl_entries DROP COLUMN data_source_id;
+ALTER TABLE data_sources DROP COLUMN sync_permissions;
@@ -0,0 +1,8 @@
+-- Mirrors versioned migration 000097_datasource_permission_sync:
+-- source permission sync flag and the data source of synced access list entries.
+
+ALTER TABLE data_sources ADD COLUMN sync_permissions INTEGER DEFAULT 0;
+ALTER TABLE knowledge_acl_entries ADD COLUMN data_source_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_data_source_id
+    ON knowledge_acl_entries (data_source_id);
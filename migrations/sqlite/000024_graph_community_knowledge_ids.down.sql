@@ -0,0 +1 @@
+ALTER TABLE graph_communities DROP COLUMN knowledge_ids;
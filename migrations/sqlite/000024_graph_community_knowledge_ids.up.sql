@@ -0,0 +1,4 @@
+-- Mirrors versioned migration 000098_graph_community_knowledge_ids:
+-- every source document of a graph community.
+
+ALTER TABLE graph_communities ADD COLUMN knowledge_ids TEXT;
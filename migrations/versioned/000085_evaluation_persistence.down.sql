@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_evaluation_dataset_items_dataset;
+DROP TABLE IF EXISTS evaluation_dataset_items;
+DROP INDEX IF EXISTS idx_evaluation_datasets_tenant;
+DROP TABLE IF EXISTS evaluation_datasets;
+DROP INDEX IF EXISTS idx_evaluation_results_task;
+DROP TABLE IF EXISTS evaluation_results;
+DROP INDEX IF EXISTS idx_evaluation_tasks_tenant;
+DROP TABLE IF EXISTS evaluation_tasks;
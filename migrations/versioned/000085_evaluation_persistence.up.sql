@@ -0,0 +1,79 @@
+-- Migration 000085: persist evaluation runs and uploaded evaluation datasets.
+--
+-- Evaluation tasks used to live in a process-local map, so a restart or a
+-- request routed to another replica lost the run. Tasks, their per-question
+-- results and tenant-uploaded QA/qrels datasets now live in the database.
+
+CREATE TABLE IF NOT EXISTS evaluation_tasks (
+    id VARCHAR(128) PRIMARY KEY,
+    tenant_id INTEGER NOT NULL,
+    -- "default" for the bundled sample dataset, otherwise evaluation_datasets.id
+    dataset_id VARCHAR(64) NOT NULL,
+    knowledge_base_id VARCHAR(36),
+    chat_model_id VARCHAR(64),
+    rerank_model_id VARCHAR(64),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    -- 0 pending | 1 running | 2 success | 3 failed
+    status SMALLINT NOT NULL DEFAULT 0,
+    err_msg TEXT,
+    total INTEGER NOT NULL DEFAULT 0,
+    finished INTEGER NOT NULL DEFAULT 0,
+    -- Aggregated MetricResult, refreshed as questions complete.
+    metric JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_tasks_tenant
+    ON evaluation_tasks (tenant_id, start_time DESC);
+
+CREATE TABLE IF NOT EXISTS evaluation_results (
+    id BIGSERIAL PRIMARY KEY,
+    task_id VARCHAR(128) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    question_index INTEGER NOT NULL,
+    qid INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    expected TEXT,
+    generated TEXT,
+    retrieval_gt JSONB,
+    retrieval_ids JSONB,
+    metric JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_results_task
+    ON evaluation_results (task_id, question_index);
+
+CREATE TABLE IF NOT EXISTS evaluation_datasets (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    -- jsonl | parquet
+    format VARCHAR(16) NOT NULL,
+    qa_count INTEGER NOT NULL DEFAULT 0,
+    passage_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_datasets_tenant
+    ON evaluation_datasets (tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS evaluation_dataset_items (
+    id BIGSERIAL PRIMARY KEY,
+    dataset_id VARCHAR(36) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    qid INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    aid INTEGER NOT NULL DEFAULT 0,
+    answer TEXT,
+    -- Relevant passage ids and texts, index-aligned.
+    pids JSONB,
+    passages JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_evaluation_dataset_items_dataset
+    ON evaluation_dataset_items (dataset_id, qid);
@@ -0,0 +1 @@
+ALTER TABLE evaluation_tasks DROP COLUMN IF EXISTS judge_model_id;
@@ -0,0 +1,7 @@
+-- Migration 000086: judge model for LLM-as-judge evaluation metrics.
+--
+-- Runs started with a judge model score faithfulness, answer relevance and
+-- context precision/recall in addition to the lexical metrics.
+ALTER TABLE evaluation_tasks ADD COLUMN IF NOT EXISTS judge_model_id VARCHAR(64);
+
+COMMENT ON COLUMN evaluation_tasks.judge_model_id IS 'Chat model scoring judge metrics; empty when the run computed lexical metrics only';
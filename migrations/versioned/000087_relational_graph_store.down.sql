@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS graph_relations;
+DROP TABLE IF EXISTS graph_nodes;
@@ -0,0 +1,39 @@
+-- Migration 000087: relational knowledge graph store.
+--
+-- GRAPH_DRIVER=database keeps the entity graph in these tables instead of
+-- Neo4j. Each row is one (entity or edge, chunk) observation; readers fold
+-- rows with the same name back together, so writes stay insert-only.
+
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    id BIGSERIAL PRIMARY KEY,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    name TEXT NOT NULL,
+    -- Chunk the entity was extracted from; '' when unknown.
+    chunk_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_graph_nodes_observation
+    ON graph_nodes (knowledge_id, name, chunk_id);
+CREATE INDEX IF NOT EXISTS idx_graph_nodes_kb_name
+    ON graph_nodes (knowledge_base_id, name);
+
+CREATE TABLE IF NOT EXISTS graph_relations (
+    id BIGSERIAL PRIMARY KEY,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    type TEXT NOT NULL,
+    chunk_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_graph_relations_observation
+    ON graph_relations (knowledge_id, source, type, target, chunk_id);
+CREATE INDEX IF NOT EXISTS idx_graph_relations_kb_source
+    ON graph_relations (knowledge_base_id, source);
+CREATE INDEX IF NOT EXISTS idx_graph_relations_kb_target
+    ON graph_relations (knowledge_base_id, target);
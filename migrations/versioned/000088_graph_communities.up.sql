@@ -0,0 +1,24 @@
+-- Migration 000088: knowledge graph communities.
+--
+-- Community detection clusters a knowledge base's entity graph and stores one
+-- LLM-written summary per cluster. The "global" retrieval mode answers
+-- corpus-wide questions from these summaries. Rows are replaced wholesale per
+-- knowledge base on every rebuild.
+
+CREATE TABLE IF NOT EXISTS graph_communities (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    title VARCHAR(512) NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    entities JSONB DEFAULT '[]'::JSONB,
+    relation_count INTEGER NOT NULL DEFAULT 0,
+    chunk_ids JSONB DEFAULT '[]'::JSONB,
+    rank DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_graph_communities_kb
+    ON graph_communities (knowledge_base_id);
+CREATE INDEX IF NOT EXISTS idx_graph_communities_tenant_id
+    ON graph_communities (tenant_id);
@@ -0,0 +1,10 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embeddings') THEN
+        DROP INDEX IF EXISTS embeddings_unique_kb_source;
+        CREATE UNIQUE INDEX IF NOT EXISTS embeddings_unique_source ON embeddings(source_id, source_type);
+    END IF;
+END $$;
+
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS embedding_migration;
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS embedding_index_id;
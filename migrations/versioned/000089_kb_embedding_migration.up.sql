@@ -0,0 +1,23 @@
+-- Migration 000089: zero-downtime re-embedding.
+--
+-- Changing a knowledge base's embedding model builds a shadow index with the
+-- new model under a separate knowledge_base_id label and switches to it once
+-- complete. embedding_index_id is that label (empty = the knowledge base ID);
+-- embedding_migration holds the progress of the running job.
+
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS embedding_index_id VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS embedding_migration JSONB;
+
+-- The shadow index stores the same source IDs as the live one, so source
+-- uniqueness has to be scoped by knowledge_base_id.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embeddings') THEN
+        DROP INDEX IF EXISTS embeddings_unique_source;
+        CREATE UNIQUE INDEX IF NOT EXISTS embeddings_unique_kb_source
+            ON embeddings(knowledge_base_id, source_id, source_type);
+        RAISE NOTICE '[Migration 000089] Scoped embeddings source uniqueness by knowledge_base_id';
+    ELSE
+        RAISE NOTICE '[Migration 000089] embeddings table does not exist, skipping';
+    END IF;
+END $$;
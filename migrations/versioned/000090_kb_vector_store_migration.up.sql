@@ -0,0 +1,8 @@
+-- Migration 000090: move a knowledge base between vector stores.
+--
+-- A background task copies a knowledge base's index entries, with their
+-- stored embeddings, into another vector store and rebinds vector_store_id
+-- once the copy is verified. vector_store_migration holds the progress and
+-- the resume cursor of that task.
+
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS vector_store_migration JSONB;
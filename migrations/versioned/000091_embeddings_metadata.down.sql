@@ -0,0 +1,12 @@
+-- Remove metadata column from embeddings table
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'embeddings' AND column_name = 'metadata'
+    ) THEN
+        DROP INDEX IF EXISTS idx_embeddings_metadata;
+        ALTER TABLE embeddings DROP COLUMN metadata;
+        RAISE NOTICE '[Migration 000091 Rollback] Removed metadata column from embeddings table';
+    END IF;
+END $$;
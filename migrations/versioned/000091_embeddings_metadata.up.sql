@@ -0,0 +1,21 @@
+-- Migration 000091: custom metadata on embeddings.
+--
+-- Each index entry carries the custom metadata of its document as flat,
+-- typed fields (see types.IndexMetadata) so retrieval can filter on it.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embeddings') THEN
+        IF NOT EXISTS (
+            SELECT 1 FROM information_schema.columns
+            WHERE table_name = 'embeddings' AND column_name = 'metadata'
+        ) THEN
+            ALTER TABLE embeddings ADD COLUMN metadata JSONB;
+            CREATE INDEX IF NOT EXISTS idx_embeddings_metadata ON embeddings USING gin (metadata jsonb_path_ops);
+            RAISE NOTICE '[Migration 000091] Added metadata column and index to embeddings table';
+        ELSE
+            RAISE NOTICE '[Migration 000091] metadata column already exists in embeddings table, skipping';
+        END IF;
+    ELSE
+        RAISE NOTICE '[Migration 000091] embeddings table does not exist, skipping';
+    END IF;
+END $$;
@@ -0,0 +1,35 @@
+-- Migration 000092: semantic answer cache for knowledge QA.
+--
+-- Agents that opt in keep recent answers together with the embedding of the
+-- question that produced them. A later question close enough in embedding
+-- space, against the same retrieval scope, replays the stored answer and
+-- references instead of running the pipeline. An entry created before the
+-- last change to its knowledge bases is stale; the chunk indexes below keep
+-- that "last change" lookup an index probe per knowledge base.
+
+CREATE TABLE IF NOT EXISTS answer_cache_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    scope_hash VARCHAR(64) NOT NULL,
+    knowledge_base_ids JSONB DEFAULT '[]'::JSONB,
+    query TEXT NOT NULL,
+    -- little-endian float32 vector of query
+    query_embedding BYTEA,
+    answer TEXT NOT NULL,
+    knowledge_references JSONB,
+    hit_count BIGINT NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_answer_cache_entries_scope
+    ON answer_cache_entries (tenant_id, agent_id, scope_hash, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_answer_cache_entries_expires
+    ON answer_cache_entries (expires_at);
+
+CREATE INDEX IF NOT EXISTS idx_chunks_kb_updated
+    ON chunks (knowledge_base_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_chunks_kb_deleted
+    ON chunks (knowledge_base_id, deleted_at) WHERE deleted_at IS NOT NULL;
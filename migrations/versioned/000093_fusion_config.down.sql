@@ -0,0 +1,2 @@
+ALTER TABLE evaluation_tasks DROP COLUMN IF EXISTS fusion_config;
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS fusion_config;
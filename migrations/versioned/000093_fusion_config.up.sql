@@ -0,0 +1,12 @@
+-- Migration 000093: configurable hybrid fusion strategies.
+--
+-- Hybrid search merged vector and keyword results with RRF only. A knowledge
+-- base can now carry its own fusion settings (strategy, weights, learned
+-- weights) that replace the tenant's retrieval_config defaults, and an
+-- evaluation run records the fusion it searched with so runs can be compared
+-- to tune the learned weights.
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS fusion_config JSONB;
+ALTER TABLE evaluation_tasks ADD COLUMN IF NOT EXISTS fusion_config JSONB;
+
+COMMENT ON COLUMN knowledge_bases.fusion_config IS 'Hybrid fusion override; NULL inherits the tenant retrieval_config';
+COMMENT ON COLUMN evaluation_tasks.fusion_config IS 'Hybrid fusion the run searched with; NULL means the tenant default';
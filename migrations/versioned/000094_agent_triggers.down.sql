@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS agent_trigger_runs;
+DROP TABLE IF EXISTS agent_triggers;
@@ -0,0 +1,67 @@
+-- Migration 000094: scheduled and event-driven agent triggers.
+--
+-- A trigger runs a custom agent without a user message: on a cron schedule
+-- (with seconds) or when a workspace event happens (knowledge ingested,
+-- datasource sync finished, wiki issue flagged). The answer is delivered to
+-- an IM chat, a signed webhook or a knowledge base. Every execution is kept
+-- as a run for auditing.
+
+CREATE TABLE IF NOT EXISTS agent_triggers (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    trigger_type VARCHAR(20) NOT NULL,
+    schedule VARCHAR(100) NOT NULL DEFAULT '',
+    event_type VARCHAR(50) NOT NULL DEFAULT '',
+    knowledge_base_ids JSONB DEFAULT '[]'::JSONB,
+    cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+    prompt TEXT NOT NULL,
+    delivery_type VARCHAR(20) NOT NULL DEFAULT 'none',
+    delivery_im_channel_id VARCHAR(36) NOT NULL DEFAULT '',
+    delivery_im_chat_id VARCHAR(255) NOT NULL DEFAULT '',
+    delivery_im_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    delivery_webhook_url VARCHAR(1024) NOT NULL DEFAULT '',
+    delivery_webhook_secret VARCHAR(128) NOT NULL DEFAULT '',
+    delivery_knowledge_base_id VARCHAR(36) NOT NULL DEFAULT '',
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_status VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_agent
+    ON agent_triggers (tenant_id, agent_id);
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_event
+    ON agent_triggers (tenant_id, event_type) WHERE deleted_at IS NULL AND enabled;
+CREATE INDEX IF NOT EXISTS idx_agent_triggers_deleted_at
+    ON agent_triggers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS agent_trigger_runs (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    trigger_id VARCHAR(36) NOT NULL,
+    agent_id VARCHAR(36) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    -- workspace event that started an event run
+    event JSONB,
+    query TEXT NOT NULL DEFAULT '',
+    session_id VARCHAR(36) NOT NULL DEFAULT '',
+    answer TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    error_message TEXT NOT NULL DEFAULT '',
+    delivery_type VARCHAR(20) NOT NULL DEFAULT 'none',
+    delivery_error TEXT NOT NULL DEFAULT '',
+    knowledge_id VARCHAR(36) NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_trigger_runs_trigger
+    ON agent_trigger_runs (tenant_id, trigger_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_trigger_runs_active
+    ON agent_trigger_runs (trigger_id, status, created_at);
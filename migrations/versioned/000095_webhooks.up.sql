@@ -0,0 +1,50 @@
+-- Migration 000095: tenant webhook subscriptions and their delivery log.
+--
+-- A subscription POSTs workspace events of a tenant (knowledge lifecycle,
+-- knowledge base changes, datasource syncs, wiki revisions, evaluations) to
+-- a URL, HMAC-signed when a secret is set. Every event sent to a
+-- subscription is a delivery that keeps the exact body, so failed
+-- deliveries can be inspected and replayed.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    secret VARCHAR(128) NOT NULL DEFAULT '',
+    -- empty selects every event type
+    event_types JSONB DEFAULT '[]'::JSONB,
+    -- empty selects events of every knowledge base
+    knowledge_base_ids JSONB DEFAULT '[]'::JSONB,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_tenant_id
+    ON webhook_subscriptions (tenant_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at
+    ON webhook_subscriptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    subscription_id VARCHAR(36) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    replay_of VARCHAR(36) NOT NULL DEFAULT '',
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON webhook_deliveries (tenant_id, subscription_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_group_members;
+DROP TABLE IF EXISTS user_groups;
+DROP TABLE IF EXISTS knowledge_acl_entries;
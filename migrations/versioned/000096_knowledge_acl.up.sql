@@ -0,0 +1,59 @@
+-- Migration 000096: document and folder access lists, and user groups.
+--
+-- An access list restricts who may read a document, or every document of a
+-- folder and its subfolders that has no list of its own. Entries name a user
+-- or a user group of the tenant owning the knowledge base; a target without
+-- entries is readable by everyone who can read the knowledge base.
+
+CREATE TABLE IF NOT EXISTS knowledge_acl_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    -- knowledge or folder
+    target_type VARCHAR(16) NOT NULL,
+    -- knowledge ID, or the normalized folder path
+    target_id VARCHAR(1024) NOT NULL,
+    -- user or group
+    principal_type VARCHAR(16) NOT NULL,
+    principal_id VARCHAR(64) NOT NULL,
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_tenant_id
+    ON knowledge_acl_entries (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_knowledge_base_id
+    ON knowledge_acl_entries (knowledge_base_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_target
+    ON knowledge_acl_entries (knowledge_base_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_principal
+    ON knowledge_acl_entries (tenant_id, principal_type, principal_id);
+
+CREATE TABLE IF NOT EXISTS user_groups (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_groups_tenant_id
+    ON user_groups (tenant_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_groups_deleted_at
+    ON user_groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_group_members (
+    group_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_group_members_user_id
+    ON user_group_members (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_group_members_tenant_id
+    ON user_group_members (tenant_id);
@@ -0,0 +1,14 @@
+-- Migration 000097: sync source-system permissions from data sources.
+--
+-- A data source can copy the read permissions of the documents it syncs
+-- (Feishu sharing, GitLab project membership, Yuque book visibility) into
+-- document access lists. Entries it writes carry its ID so each sync can
+-- replace them without touching access lists set by hand.
+ALTER TABLE data_sources ADD COLUMN IF NOT EXISTS sync_permissions BOOLEAN DEFAULT false;
+ALTER TABLE knowledge_acl_entries ADD COLUMN IF NOT EXISTS data_source_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_acl_entries_data_source_id
+    ON knowledge_acl_entries (data_source_id);
+
+COMMENT ON COLUMN data_sources.sync_permissions IS 'Mirror source read permissions onto synced documents';
+COMMENT ON COLUMN knowledge_acl_entries.data_source_id IS 'Data source that synced the entry; empty for entries set by hand';
@@ -0,0 +1 @@
+ALTER TABLE graph_communities DROP COLUMN IF EXISTS knowledge_ids;
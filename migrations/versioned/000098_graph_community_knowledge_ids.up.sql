@@ -0,0 +1,8 @@
+-- Migration 000098: record every source document of a graph community.
+--
+-- chunk_ids keeps only the first 100 evidence chunks, which is not enough to
+-- tell whether a community draws on a document the reader may not see.
+-- Rows built before this migration keep a NULL list until the next rebuild.
+ALTER TABLE graph_communities ADD COLUMN IF NOT EXISTS knowledge_ids JSONB;
+
+COMMENT ON COLUMN graph_communities.knowledge_ids IS 'Documents the member relations were extracted from';